@@ -0,0 +1,103 @@
+package activity
+
+import "sync"
+
+// Hub 把账户有新分录的信号分发给订阅了这个账户的连接
+// 信号不带内容，订阅方收到后按自己的游标去数据库查，所以信号可以合并也可以多发
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[*Subscription]struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[int64]map[*Subscription]struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Subscription 一个连接对一个账户的订阅，用完必须 Close
+type Subscription struct {
+	hub       *Hub
+	accountID int64
+	c         chan struct{}
+}
+
+func (hub *Hub) Subscribe(accountID int64) *Subscription {
+	sub := &Subscription{
+		hub:       hub,
+		accountID: accountID,
+		// 缓冲一个信号，处理慢的订阅方不会阻塞 Publish，积压的信号合并成一个
+		c: make(chan struct{}, 1),
+	}
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	subs, ok := hub.subscribers[accountID]
+	if !ok {
+		subs = make(map[*Subscription]struct{})
+		hub.subscribers[accountID] = subs
+	}
+	subs[sub] = struct{}{}
+	return sub
+}
+
+// C 有新分录时可读
+func (sub *Subscription) C() <-chan struct{} {
+	return sub.c
+}
+
+func (sub *Subscription) Close() {
+	hub := sub.hub
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	subs := hub.subscribers[sub.accountID]
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(hub.subscribers, sub.accountID)
+	}
+}
+
+// Publish 通知账户的所有订阅方
+func (hub *Hub) Publish(accountID int64) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	for sub := range hub.subscribers[accountID] {
+		sub.notify()
+	}
+}
+
+// PublishAll 通知所有订阅方，和数据库的连接断过之后用，期间的通知可能丢了
+func (hub *Hub) PublishAll() {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	for _, subs := range hub.subscribers {
+		for sub := range subs {
+			sub.notify()
+		}
+	}
+}
+
+func (sub *Subscription) notify() {
+	select {
+	case sub.c <- struct{}{}:
+	default:
+	}
+}
+
+// Close 服务关闭时调用，订阅方收到 Done 后结束连接，否则优雅关闭会一直等长连接
+func (hub *Hub) Close() {
+	hub.closeOnce.Do(func() {
+		close(hub.done)
+	})
+}
+
+func (hub *Hub) Done() <-chan struct{} {
+	return hub.done
+}
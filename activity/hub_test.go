@@ -0,0 +1,39 @@
+package activity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub(t *testing.T) {
+	hub := NewHub()
+	sub1 := hub.Subscribe(1)
+	sub2 := hub.Subscribe(2)
+
+	// 没有读的信号合并成一个，Publish 不阻塞
+	hub.Publish(1)
+	hub.Publish(1)
+	require.Len(t, sub1.C(), 1)
+	require.Empty(t, sub2.C())
+	<-sub1.C()
+
+	hub.PublishAll()
+	require.Len(t, sub1.C(), 1)
+	require.Len(t, sub2.C(), 1)
+	<-sub1.C()
+	<-sub2.C()
+
+	// 关闭后不再收到信号
+	sub1.Close()
+	hub.Publish(1)
+	require.Empty(t, sub1.C())
+	require.Empty(t, hub.subscribers[1])
+
+	sub2.Close()
+	require.Empty(t, hub.subscribers)
+
+	hub.Close()
+	hub.Close()
+	<-hub.Done()
+}
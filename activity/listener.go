@@ -0,0 +1,61 @@
+package activity
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Channel 和迁移里 notify_account_activity 触发器用的频道一致
+const Channel = "account_activity"
+
+const (
+	minReconnectInterval = time.Second
+	maxReconnectInterval = time.Minute
+	// 长时间没有通知时 ping 一下，连接断了能尽快发现
+	pingInterval = 90 * time.Second
+)
+
+// Listen 用 LISTEN 接收分录提交的通知并转给 hub，阻塞到 ctx 结束
+// 连接断开后 pq.Listener 自动重连，重连后通知所有订阅方补查
+func Listen(ctx context.Context, dbSource string, hub *Hub) error {
+	listener := pq.NewListener(dbSource, minReconnectInterval, maxReconnectInterval, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Error("account activity listener error", slog.String("error", err.Error()))
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(Channel); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-listener.Notify:
+			// 重连后会收到 nil
+			if notification == nil {
+				hub.PublishAll()
+				continue
+			}
+			accountID, err := strconv.ParseInt(notification.Extra, 10, 64)
+			if err != nil {
+				slog.Error("invalid account activity notification", slog.String("payload", notification.Extra))
+				continue
+			}
+			hub.Publish(accountID)
+		case <-ticker.C:
+			if err := listener.Ping(); err != nil {
+				slog.Error("failed to ping account activity listener", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
@@ -3,17 +3,21 @@ package api
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 	db "simplebank/db/sqlc"
+	"strconv"
 
 	"simplebank/token"
+	"simplebank/util"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
 )
 
 type createAccountRequest struct {
-	Currency string `json:"currency" binding:"required,currency"`
+	Currency    string `json:"currency" binding:"required,currency"`
+	ProductType string `json:"product_type" binding:"omitempty,product_type"`
 }
 
 func (server *Server) createAccount(ctx *gin.Context) {
@@ -23,14 +27,28 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		return
 	}
 
+	// 不传产品类型时默认开活期账户
+	productType := req.ProductType
+	if productType == "" {
+		productType = util.CheckingProduct
+	}
+
+	accountNumber, err := util.NewAccountNumber(server.config.AccountNumberBankCode)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	arg := db.CreateAccountParams{
-		Owner:    authPayload.Username,
-		Balance:  0,
-		Currency: req.Currency,
+		Owner:         authPayload.Username,
+		Balance:       0,
+		Currency:      req.Currency,
+		ProductType:   productType,
+		AccountNumber: accountNumber,
 	}
 
-	accounts, err := server.store.CreateAccount(ctx, arg)
+	result, err := server.store.CreateAccountTx(ctx, arg)
 
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
@@ -45,11 +63,12 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, accounts)
+	ctx.JSON(http.StatusOK, result.Account)
 }
 
+// ID 可以是数字账户 ID，也可以是对外账号
 type getAccountRequest struct {
-	ID int64 `uri:"id" binding:"required,min=1"`
+	ID string `uri:"id" binding:"required"`
 }
 
 func (server *Server) getAccount(ctx *gin.Context) {
@@ -60,7 +79,18 @@ func (server *Server) getAccount(ctx *gin.Context) {
 		return
 	}
 
-	account, err := server.store.GetAccount(ctx, req.ID)
+	var account db.Account
+	accountID, err := strconv.ParseInt(req.ID, 10, 64)
+	switch {
+	case err == nil && accountID >= 1:
+		account, err = server.store.GetAccount(ctx, accountID)
+	case util.ValidAccountNumber(req.ID):
+		// 账号校验码不对的在这里就拦下，不查库
+		account, err = server.store.GetAccountByNumber(ctx, req.ID)
+	default:
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid account id or number %q", req.ID)))
+		return
+	}
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
@@ -71,9 +101,7 @@ func (server *Server) getAccount(ctx *gin.Context) {
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if account.Owner != authPayload.Username {
-		err := errors.New("account doesn't belong to the authorization user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	if !server.authorizedMember(ctx, account.ID, authPayload.Username, false) {
 		return
 	}
 
@@ -96,9 +124,9 @@ func (server *Server) listAccount(ctx *gin.Context) {
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
 	arg := db.ListAccountsParams{
-		Owner:  authPayload.Username,
-		Limit:  req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+		Username: authPayload.Username,
+		Limit:    req.PageSize,
+		Offset:   (req.PageID - 1) * req.PageSize,
 	}
 
 	accounts, err := server.store.ListAccounts(ctx, arg)
@@ -109,3 +137,28 @@ func (server *Server) listAccount(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, accounts)
 }
+
+// authorizedMember 通过成员表校验当前用户对账户的权限，不通过时已经写好了响应
+func (server *Server) authorizedMember(ctx *gin.Context, accountID int64, username string, needTransfer bool) bool {
+	member, err := server.store.GetAccountMember(ctx, db.GetAccountMemberParams{
+		AccountID: accountID,
+		Username:  username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err := errors.New("account doesn't belong to the authorization user")
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	if needTransfer && !util.CanTransfer(member.Role) {
+		err := fmt.Errorf("account member with role %s cannot transfer", member.Role)
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return false
+	}
+
+	return true
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	mockdb "simplebank/db/mock"
 	db "simplebank/db/sqlc"
 	"simplebank/token"
@@ -22,11 +23,22 @@ import (
 
 func TestGetAccountAPI(t *testing.T) {
 	user, _ := randomUser(t)
+	viewer, _ := randomUser(t)
 	account := randomAccount(user.Username)
 
+	// 把账号的两位数字互换，校验码应该能发现
+	transposed := []byte(account.AccountNumber)
+	for i := 8; i < len(transposed)-1; i++ {
+		if transposed[i] != transposed[i+1] {
+			transposed[i], transposed[i+1] = transposed[i+1], transposed[i]
+			break
+		}
+	}
+
 	testCases := []struct {
 		name          string
 		accountID     int64
+		accountNumber string
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
@@ -35,19 +47,99 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "OK",
 			accountID: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
 					Times(1).
 					Return(account, nil)
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user.Username})).
+					Times(1).
+					Return(db.AccountMember{AccountID: account.ID, Username: user.Username, Role: util.MemberRoleOwner}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 				requireBodyMatchAccount(t, recorder.Body, account)
 			},
 		},
+		{
+			name:          "ByAccountNumber",
+			accountNumber: account.AccountNumber,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountByNumber(gomock.Any(), gomock.Eq(account.AccountNumber)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user.Username})).
+					Times(1).
+					Return(db.AccountMember{AccountID: account.ID, Username: user.Username, Role: util.MemberRoleOwner}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, account)
+			},
+		},
+		{
+			name:          "TransposedAccountNumber",
+			accountNumber: string(transposed),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "ViewerCanRead",
+			accountID: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, viewer.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: viewer.Username})).
+					Times(1).
+					Return(db.AccountMember{AccountID: account.ID, Username: viewer.Username, Role: util.MemberRoleViewer}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, account)
+			},
+		},
+		{
+			name:      "NotMember",
+			accountID: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, viewer.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountMember{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 		{
 			name:      "NoAuthorization",
 			accountID: account.ID,
@@ -65,7 +157,7 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "ExpiredToken",
 			accountID: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, -time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, -time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
@@ -78,7 +170,7 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "NotFound",
 			accountID: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
@@ -94,7 +186,7 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "InternalError",
 			accountID: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
@@ -123,6 +215,9 @@ func TestGetAccountAPI(t *testing.T) {
 			recorder := httptest.NewRecorder()
 
 			url := fmt.Sprintf("/accounts/%d", tc.accountID)
+			if tc.accountNumber != "" {
+				url = fmt.Sprintf("/accounts/%s", tc.accountNumber)
+			}
 			request, err := http.NewRequest(http.MethodGet, url, nil)
 			require.NoError(t, err)
 
@@ -134,6 +229,34 @@ func TestGetAccountAPI(t *testing.T) {
 	}
 }
 
+// 账号是随机生成的，只校验格式和银行代码
+type eqCreateAccountParamsMatcher struct {
+	arg      db.CreateAccountParams
+	bankCode string
+}
+
+func (e eqCreateAccountParamsMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(db.CreateAccountParams)
+	if !ok {
+		return false
+	}
+
+	if !util.ValidAccountNumber(arg.AccountNumber) || arg.AccountNumber[4:8] != e.bankCode {
+		return false
+	}
+
+	e.arg.AccountNumber = arg.AccountNumber
+	return reflect.DeepEqual(e.arg, arg)
+}
+
+func (e eqCreateAccountParamsMatcher) String() string {
+	return fmt.Sprintf("matches arg %v and bank code %v", e.arg, e.bankCode)
+}
+
+func EqCreateAccountParams(arg db.CreateAccountParams, bankCode string) gomock.Matcher {
+	return eqCreateAccountParamsMatcher{arg: arg, bankCode: bankCode}
+}
+
 func TestCreateAccountAPI(t *testing.T) {
 	user, _ := randomUser(t)
 	account := randomAccount(user.Username)
@@ -153,19 +276,20 @@ func TestCreateAccountAPI(t *testing.T) {
 				"currency": account.Currency,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				arg := db.CreateAccountParams{
-					Owner:    account.Owner,
-					Currency: account.Currency,
-					Balance:  0,
+					Owner:       account.Owner,
+					Currency:    account.Currency,
+					Balance:     0,
+					ProductType: util.CheckingProduct,
 				}
 
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Eq(arg)).
+					CreateAccountTx(gomock.Any(), EqCreateAccountParams(arg, util.DefaultBankCode)).
 					Times(1).
-					Return(account, nil)
+					Return(db.CreateAccountTxResult{Account: account}, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -181,7 +305,7 @@ func TestCreateAccountAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Any()).
+					CreateAccountTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
@@ -195,24 +319,69 @@ func TestCreateAccountAPI(t *testing.T) {
 				"currency": account.Currency,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				arg := db.CreateAccountParams{
-					Owner:    account.Owner,
-					Currency: account.Currency,
-					Balance:  0,
+					Owner:       account.Owner,
+					Currency:    account.Currency,
+					Balance:     0,
+					ProductType: util.CheckingProduct,
 				}
 
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Eq(arg)).
+					CreateAccountTx(gomock.Any(), EqCreateAccountParams(arg, util.DefaultBankCode)).
 					Times(1).
-					Return(db.Account{}, sql.ErrConnDone)
+					Return(db.CreateAccountTxResult{}, sql.ErrConnDone)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name: "SavingsProduct",
+			body: gin.H{
+				"currency":     account.Currency,
+				"product_type": util.SavingsProduct,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateAccountParams{
+					Owner:       account.Owner,
+					Currency:    account.Currency,
+					Balance:     0,
+					ProductType: util.SavingsProduct,
+				}
+
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), EqCreateAccountParams(arg, util.DefaultBankCode)).
+					Times(1).
+					Return(db.CreateAccountTxResult{Account: account}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidProductType",
+			body: gin.H{
+				"currency":     account.Currency,
+				"product_type": "xyz",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "InvalidCurrency", // 参数校验失败
 			body: gin.H{
@@ -220,13 +389,13 @@ func TestCreateAccountAPI(t *testing.T) {
 				"currency": "XYZ", // 无效货币
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				// 因为 Gin 的 binding 校验会在调用 Store 之前就报错
 				// 所以 Store 根本不会被调用！这里必须写 Times(0) 或者干脆不写
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Any()).
+					CreateAccountTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
@@ -295,13 +464,13 @@ func TestListAccountsAPI(t *testing.T) {
 				pageSize: n,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				arg := db.ListAccountsParams{
-					Owner:  user.Username,
-					Limit:  int32(n),
-					Offset: 0,
+					Username: user.Username,
+					Limit:    int32(n),
+					Offset:   0,
 				}
 
 				store.EXPECT().
@@ -338,7 +507,7 @@ func TestListAccountsAPI(t *testing.T) {
 				pageSize: n,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
@@ -357,7 +526,7 @@ func TestListAccountsAPI(t *testing.T) {
 				pageSize: n,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
@@ -375,7 +544,7 @@ func TestListAccountsAPI(t *testing.T) {
 				pageSize: 100000,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
@@ -420,10 +589,13 @@ func TestListAccountsAPI(t *testing.T) {
 
 func randomAccount(owner string) db.Account {
 	return db.Account{
-		ID:       util.RandomInt(1, 1000),
-		Owner:    owner,
-		Balance:  util.RandomMoney(),
-		Currency: util.RandomCurrency(),
+		ID:            util.RandomInt(1, 1000),
+		Owner:         owner,
+		Balance:       util.RandomMoney(),
+		Currency:      util.RandomCurrency(),
+		FreezeStatus:  util.FreezeStatusActive,
+		ProductType:   util.CheckingProduct,
+		AccountNumber: util.RandomAccountNumber(),
 	}
 }
 
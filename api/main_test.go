@@ -14,8 +14,12 @@ import (
 
 func NewTestServer(t *testing.T, store db.Store) *Server {
 	config := util.Config{
-		TokenSymmetricKey:   util.RandomString(32),
-		AccessTokenDuration: time.Minute,
+		TokenSymmetricKey:     util.RandomString(32),
+		AccessTokenDuration:   time.Minute,
+		AccountNumberBankCode: util.DefaultBankCode,
+		// 随机余额不超过 6000，普通用例不会触发审批
+		TransferApprovalThreshold: 100_000,
+		TransferApprovalTimeout:   time.Hour,
 	}
 
 	server, err := NewServer(config, store)
@@ -30,9 +34,10 @@ func setupAuth(
 	tokenMaker token.Maker,
 	authorizationType string,
 	username string,
+	role string,
 	duration time.Duration,
 ) {
-	token, payload, err := tokenMaker.CreateToken(username, duration)
+	token, payload, err := tokenMaker.CreateToken(username, role, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
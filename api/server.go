@@ -33,6 +33,9 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 	//注册验证器：看到 binding:"currency" 这个标签，就用validCurrency函数去检查
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("currency", validCurrency)
+		v.RegisterValidation("product_type", validProductType)
+		v.RegisterValidation("account_number", validAccountNumber)
+		v.RegisterValidation("transfer_metadata", validTransferMetadata)
 	}
 
 	server.setupRouter()
@@ -2,21 +2,47 @@ package api
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	db "simplebank/db/sqlc"
 	"simplebank/token"
+	"simplebank/util"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
 )
 
+// 转出账户用 ID 或对外账号二选一；收款方用 ID、对外账号、收款人三选一
 type createTransferRequest struct {
-	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
-	Amount        int64  `json:"amount" binding:"required,gt=0"`
-	Currency      string `json:"currency" binding:"required,currency"`
+	FromAccountID     int64  `json:"from_account_id" binding:"required_without=FromAccountNumber,omitempty,min=1"`
+	FromAccountNumber string `json:"from_account_number" binding:"excluded_with=FromAccountID,omitempty,account_number"`
+	ToAccountID       int64  `json:"to_account_id" binding:"required_without_all=ToAccountNumber Recipient,omitempty,min=1"`
+	ToAccountNumber   string `json:"to_account_number" binding:"excluded_with=ToAccountID Recipient,omitempty,account_number"`
+	// 收款人用户名或已验证邮箱
+	Recipient string `json:"recipient" binding:"excluded_with=ToAccountID ToAccountNumber,omitempty,min=3,max=200"`
+	Amount    int64  `json:"amount" binding:"required,gt=0"`
+	Currency  string `json:"currency" binding:"required,currency"`
+	// 备注双方可见，私人备注只有转出方能看到
+	Memo              string            `json:"memo" binding:"max=140"`
+	PrivateNote       string            `json:"private_note" binding:"max=500"`
+	ExternalReference string            `json:"external_reference" binding:"max=64"`
+	Metadata          map[string]string `json:"metadata" binding:"omitempty,transfer_metadata"`
+}
+
+// 按收款人转账时不返回对方的账户和分录，避免泄露账户 ID 和余额
+type recipientTransferResponse struct {
+	TransferID  int64      `json:"transfer_id"`
+	Status      string     `json:"status"`
+	Amount      int64      `json:"amount"`
+	Fee         int64      `json:"fee"`
+	Memo        string     `json:"memo"`
+	CreatedAt   time.Time  `json:"created_at"`
+	FromAccount db.Account `json:"from_account"`
+	FromEntry   db.Entry   `json:"from_entry"`
+	FeeEntry    db.Entry   `json:"fee_entry"`
 }
 
 func (server *Server) createTransfer(ctx *gin.Context) {
@@ -26,69 +52,213 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
-	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
-
+	var fromAccount db.Account
+	var valid bool
+	if req.FromAccountNumber != "" {
+		fromAccount, valid = server.validAccountByNumber(ctx, req.FromAccountNumber, req.Currency)
+	} else {
+		fromAccount, valid = server.validAccount(ctx, req.FromAccountID, req.Currency)
+	}
 	if !valid {
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if fromAccount.Owner != authPayload.Username {
-		err := errors.New("from account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	if !server.authorizedMember(ctx, fromAccount.ID, authPayload.Username, true) {
 		return
 	}
 
-	_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
+	var toAccount db.Account
+	switch {
+	case req.Recipient != "":
+		toAccount, valid = server.recipientAccount(ctx, req.Recipient, req.Currency)
+	case req.ToAccountNumber != "":
+		toAccount, valid = server.validAccountByNumber(ctx, req.ToAccountNumber, req.Currency)
+	default:
+		toAccount, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
+	}
 	if !valid {
 		return
 	}
 
-	if fromAccount.Balance < req.Amount {
+	// 待审批转账占用的金额不能再用
+	if fromAccount.Balance+fromAccount.OverdraftLimit-fromAccount.HeldAmount < req.Amount {
 		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("insufficient balance")))
 		return
 	}
 
 	arg := db.TransferTxParams{
-		FromAccountID: req.FromAccountID,
-		ToAccountID:   req.ToAccountID,
-		Amount:        req.Amount,
+		FromAccountID:     fromAccount.ID,
+		ToAccountID:       toAccount.ID,
+		Amount:            req.Amount,
+		Memo:              req.Memo,
+		PrivateNote:       req.PrivateNote,
+		ExternalReference: req.ExternalReference,
+		// 按收款人转账时转出方以后查询也看不到收款账户
+		HideToAccount: req.Recipient != "",
+	}
+	if len(req.Metadata) > 0 {
+		metadata, err := json.Marshal(req.Metadata)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		arg.Metadata = metadata
+	}
+
+	if server.requiresApproval(req.Amount) {
+		server.createPendingTransfer(ctx, arg, authPayload.Username)
+		return
 	}
 
 	result, err := server.store.TransferTX(ctx, arg)
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code.Name() {
-			case "check_violation":
-				ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("insufficient balance")))
-				return
-			}
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		transferErrorResponse(ctx, err)
+		return
+	}
+
+	if arg.HideToAccount {
+		ctx.JSON(http.StatusOK, recipientTransferResponse{
+			TransferID:  result.Transfer.ID,
+			Status:      result.Transfer.Status,
+			Amount:      result.Transfer.Amount,
+			Fee:         result.Transfer.Fee,
+			Memo:        result.Transfer.Memo,
+			CreatedAt:   result.Transfer.CreatedAt,
+			FromAccount: result.FromAccount,
+			FromEntry:   result.FromEntry,
+			FeeEntry:    result.FeeEntry,
+		})
 		return
 	}
 
 	ctx.JSON(http.StatusOK, result)
 }
 
+// requiresApproval 超过阈值的转账需要另一个人在 gRPC 接口上审批，阈值为 0 时关闭
+func (server *Server) requiresApproval(amount int64) bool {
+	return server.config.TransferApprovalThreshold > 0 && amount > server.config.TransferApprovalThreshold
+}
+
+// createPendingTransfer 建待审批的转账并占用资金，返回 202
+func (server *Server) createPendingTransfer(ctx *gin.Context, arg db.TransferTxParams, username string) {
+	result, err := server.store.CreatePendingTransferTx(ctx, db.CreatePendingTransferTxParams{
+		TransferTxParams: arg,
+		RequestedBy:      username,
+		ExpiresAt:        time.Now().Add(server.config.TransferApprovalTimeout),
+	})
+	if err != nil {
+		transferErrorResponse(ctx, err)
+		return
+	}
+
+	if arg.HideToAccount {
+		ctx.JSON(http.StatusAccepted, recipientTransferResponse{
+			TransferID:  result.Transfer.ID,
+			Status:      result.Transfer.Status,
+			Amount:      result.Transfer.Amount,
+			Fee:         result.Transfer.Fee,
+			Memo:        result.Transfer.Memo,
+			CreatedAt:   result.Transfer.CreatedAt,
+			FromAccount: result.FromAccount,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, result)
+}
+
+func transferErrorResponse(ctx *gin.Context, err error) {
+	if isTransferRejected(err) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Code.Name() {
+		case "check_violation":
+			ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("insufficient balance")))
+			return
+		}
+	}
+	ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+}
+
+// recipientAccount 找收款人在该币种下的活期账户
+// 用户不存在、邮箱未验证、没有该币种账户都返回同样的 404，不泄露用户是否存在
+func (server *Server) recipientAccount(ctx *gin.Context, recipient string, currency string) (db.Account, bool) {
+	account, err := server.store.GetRecipientAccount(ctx, db.GetRecipientAccountParams{
+		Recipient:   recipient,
+		Currency:    currency,
+		ProductType: util.CheckingProduct,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err := fmt.Errorf("recipient cannot receive transfers in %s", currency)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return account, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return account, false
+	}
+
+	return account, true
+}
+
 func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
 	account, err := server.store.GetAccount(ctx, accountID)
+	// 银行内部账户对外当作不存在，返回和查不到一样的 404
+	if err == nil && account.Owner == util.BankUsername {
+		err = sql.ErrNoRows
+	}
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return account, false
+			return db.Account{}, false
 		}
 
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return account, false
 	}
 
+	return account, server.checkAccountCurrency(ctx, account, currency)
+}
+
+func (server *Server) validAccountByNumber(ctx *gin.Context, accountNumber string, currency string) (db.Account, bool) {
+	account, err := server.store.GetAccountByNumber(ctx, accountNumber)
+	// 银行内部账户对外当作不存在，返回和查不到一样的 404
+	if err == nil && account.Owner == util.BankUsername {
+		err = sql.ErrNoRows
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return db.Account{}, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return account, false
+	}
+
+	return account, server.checkAccountCurrency(ctx, account, currency)
+}
+
+func (server *Server) checkAccountCurrency(ctx *gin.Context, account db.Account, currency string) bool {
 	if account.Currency != currency {
-		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", accountID, account.Currency, currency)
+		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, currency)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return account, false
+		return false
 	}
+	return true
+}
 
-	return account, true
+// 冻结、产品规则等导致的拒绝，属于客户端可以理解的业务错误
+func isTransferRejected(err error) bool {
+	return errors.Is(err, db.ErrAccountFrozen) ||
+		errors.Is(err, db.ErrTransferLimitExceeded) ||
+		errors.Is(err, db.ErrMinimumBalance) ||
+		errors.Is(err, db.ErrInsufficientFunds) ||
+		errors.Is(err, db.ErrExternalTransferNotAllowed)
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	mockdb "simplebank/db/mock"
@@ -33,6 +34,13 @@ func TestTransferAPI(t *testing.T) {
 	account2.Currency = util.USD
 	account3.Currency = util.EUR
 
+	owner1 := db.AccountMember{AccountID: account1.ID, Username: user1.Username, Role: util.MemberRoleOwner}
+
+	tooManyMetadataKeys := gin.H{}
+	for i := 0; i < 21; i++ {
+		tooManyMetadataKeys[fmt.Sprintf("key%d", i)] = "value"
+	}
+
 	testCases := []struct {
 		name          string
 		body          gin.H
@@ -49,10 +57,11 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(owner1, nil)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
 
 				arg := db.TransferTxParams{
@@ -75,10 +84,11 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user2.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user2.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user2.Username})).Times(1).Return(db.AccountMember{}, sql.ErrNoRows)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0) // 不会调用toAccount的GetAccount
 				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
 			},
@@ -86,6 +96,273 @@ func TestTransferAPI(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name: "ViewerCannotTransfer",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user2.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				viewer := db.AccountMember{AccountID: account1.ID, Username: user2.Username, Role: util.MemberRoleViewer}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(viewer, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "CoOwnerCanTransfer",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user2.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				coOwner := db.AccountMember{AccountID: account1.ID, Username: user2.Username, Role: util.MemberRoleCoOwner}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(coOwner, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "RecipientOK",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"recipient":       user2.Email,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(owner1, nil)
+				store.EXPECT().GetRecipientAccount(gomock.Any(), gomock.Eq(db.GetRecipientAccountParams{
+					Recipient:   user2.Email,
+					Currency:    util.USD,
+					ProductType: util.CheckingProduct,
+				})).Times(1).Return(account2, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+					Amount:        amount,
+					HideToAccount: true,
+				}
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{
+					Transfer:  db.Transfer{ID: 1, FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: amount, Fee: 25},
+					ToAccount: account2,
+				}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp map[string]any
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, float64(25), rsp["fee"])
+				require.NotContains(t, rsp, "to_account")
+				require.NotContains(t, rsp, "to_entry")
+				require.NotContains(t, rsp, "transfer")
+			},
+		},
+		{
+			name: "RecipientNotFound",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"recipient":       user2.Username,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(owner1, nil)
+				store.EXPECT().GetRecipientAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "RecipientWithAccountID",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"recipient":       user2.Username,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingRecipient",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ToAccountNumberOK",
+			body: gin.H{
+				"from_account_id":   account1.ID,
+				"to_account_number": account2.AccountNumber,
+				"amount":            amount,
+				"currency":          util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(owner1, nil)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account2.AccountNumber)).Times(1).Return(account2, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+					Amount:        amount,
+				}
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidToAccountNumber",
+			body: gin.H{
+				"from_account_id":   account1.ID,
+				"to_account_number": "SB00SMPL0123456789",
+				"amount":            amount,
+				"currency":          util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "WithMemoAndMetadata",
+			body: gin.H{
+				"from_account_id":    account1.ID,
+				"to_account_id":      account2.ID,
+				"amount":             amount,
+				"currency":           util.USD,
+				"memo":               "rent for may",
+				"private_note":       "split with roommate",
+				"external_reference": "INV-42",
+				"metadata":           gin.H{"invoice": "42", "category": "housing"},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountID:     account1.ID,
+					ToAccountID:       account2.ID,
+					Amount:            amount,
+					Memo:              "rent for may",
+					PrivateNote:       "split with roommate",
+					ExternalReference: "INV-42",
+					Metadata:          json.RawMessage(`{"category":"housing","invoice":"42"}`),
+				}
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "MemoTooLong",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+				"memo":            util.RandomString(141),
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MetadataTooLarge",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+				"metadata":        tooManyMetadataKeys,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "NoAuthorization",
 			body: gin.H{
@@ -113,7 +390,7 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
@@ -133,10 +410,11 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(owner1, nil)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
 				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
 			},
@@ -144,6 +422,29 @@ func TestTransferAPI(t *testing.T) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 		},
+		{
+			name: "ToAccountOwnedByBank",
+			body: gin.H{
+				"from_account_id":   account1.ID,
+				"to_account_number": account2.AccountNumber,
+				"amount":            amount,
+				"currency":          util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				system := account2
+				system.Owner = util.BankUsername
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(owner1, nil)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account2.AccountNumber)).Times(1).Return(system, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
 		{
 			name: "FromAccountCurrencyMismatch",
 			body: gin.H{
@@ -153,7 +454,7 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account3.ID)).Times(1).Return(account3, nil)
@@ -173,10 +474,11 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(owner1, nil)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account3.ID)).Times(1).Return(account3, nil)
 				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
 			},
@@ -193,7 +495,7 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        "XYZ",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
@@ -212,7 +514,7 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
@@ -231,7 +533,7 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(db.Account{}, sql.ErrConnDone)
@@ -250,10 +552,11 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        util.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(owner1, nil)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
 				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, sql.ErrTxDone)
 			},
@@ -261,6 +564,167 @@ func TestTransferAPI(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name: "OverdraftCoversAmount",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          account1.Balance + amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				overdrawn := account1
+				overdrawn.OverdraftLimit = amount
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(overdrawn, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(owner1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "PendingApproval",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          200_000,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				rich := account1
+				rich.Balance = 1_000_000
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(rich, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(owner1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreatePendingTransferTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.CreatePendingTransferTxParams) (db.CreatePendingTransferTxResult, error) {
+						require.Equal(t, user1.Username, arg.RequestedBy)
+						require.Equal(t, int64(200_000), arg.Amount)
+						require.WithinDuration(t, time.Now().Add(time.Hour), arg.ExpiresAt, time.Second)
+						return db.CreatePendingTransferTxResult{
+							Transfer: db.Transfer{ID: 1, Amount: arg.Amount, Status: util.TransferStatusPendingApproval},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var rsp db.CreatePendingTransferTxResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, util.TransferStatusPendingApproval, rsp.Transfer.Status)
+			},
+		},
+		{
+			name: "RecipientPendingApproval",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"recipient":       user2.Email,
+				"amount":          200_000,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				rich := account1
+				rich.Balance = 1_000_000
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(rich, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(owner1, nil)
+				store.EXPECT().GetRecipientAccount(gomock.Any(), gomock.Any()).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreatePendingTransferTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.CreatePendingTransferTxParams) (db.CreatePendingTransferTxResult, error) {
+						require.True(t, arg.HideToAccount)
+						return db.CreatePendingTransferTxResult{
+							Transfer: db.Transfer{ID: 1, Amount: arg.Amount, Status: util.TransferStatusPendingApproval, HideToAccount: true},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var rsp map[string]any
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.NotContains(t, rsp, "to_account")
+				require.NotContains(t, rsp, "transfer")
+			},
+		},
+		{
+			name: "HeldFundsNotAvailable",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				held := account1
+				held.HeldAmount = account1.Balance
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(held, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(owner1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          account1.Balance + amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(owner1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AccountFrozen",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				setupAuth(t, request, tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(owner1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTX(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrAccountFrozen)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 	}
 
 	for i := range testCases {
@@ -2,6 +2,7 @@ package api
 
 import (
 	"simplebank/util"
+	"simplebank/val"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -13,3 +14,24 @@ var validCurrency validator.Func = func(fl validator.FieldLevel) bool {
 	}
 	return false
 }
+
+var validProductType validator.Func = func(fl validator.FieldLevel) bool {
+	if productType, ok := fl.Field().Interface().(string); ok {
+		return util.IsSupportedProductType(productType)
+	}
+	return false
+}
+
+var validAccountNumber validator.Func = func(fl validator.FieldLevel) bool {
+	if accountNumber, ok := fl.Field().Interface().(string); ok {
+		return util.ValidAccountNumber(accountNumber)
+	}
+	return false
+}
+
+var validTransferMetadata validator.Func = func(fl validator.FieldLevel) bool {
+	if metadata, ok := fl.Field().Interface().(map[string]string); ok {
+		return val.ValidateMetadata(metadata) == nil
+	}
+	return false
+}
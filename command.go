@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	db "simplebank/db/sqlc"
+	"simplebank/nacha"
+	"simplebank/util"
+	"text/tabwriter"
+	"time"
+)
+
+// runCommand 运维命令：
+//
+//	simplebank ach export [-o file]  导出待提交的他行付款
+//	simplebank ach returns <file>    处理收款行的退回文件
+//	simplebank tasks list [flags]    列出等待重试或已归档的任务
+//	simplebank tasks show <id>       查看任务的 payload 和失败记录
+//	simplebank tasks run <id>        马上再处理一次失败的任务
+//	simplebank tasks delete <id>     删掉失败的任务
+func runCommand(ctx context.Context, config util.Config, store db.Store, args []string) error {
+	if len(args) >= 2 {
+		switch args[0] {
+		case "ach":
+			return runACHCommand(ctx, config, store, args[1:])
+		case "tasks":
+			return runTaskCommand(ctx, config, store, args[1:])
+		}
+	}
+	return errors.New("usage: simplebank ach export|returns ... | simplebank tasks list|show|run|delete ...")
+}
+
+func runACHCommand(ctx context.Context, config util.Config, store db.Store, args []string) error {
+	service := nacha.NewService(store, nacha.OriginFromConfig(config))
+	switch args[0] {
+	case "export":
+		return runACHExport(ctx, service, args[1:])
+	case "returns":
+		return runACHReturns(ctx, service, args[1:])
+	}
+	return fmt.Errorf("unknown ach command %q", args[0])
+}
+
+func runACHExport(ctx context.Context, service *nacha.Service, args []string) error {
+	flags := flag.NewFlagSet("ach export", flag.ContinueOnError)
+	output := flags.String("o", "", "output file, default simplebank_<date>_<modifier>.ach")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	result, err := service.Export(ctx, now)
+	if err != nil {
+		if errors.Is(err, nacha.ErrNoPayments) {
+			fmt.Println(err)
+			return nil
+		}
+		return err
+	}
+
+	// 文件已经入库，写盘失败时可以从 ach_files 表里取回
+	name := *output
+	if name == "" {
+		name = fmt.Sprintf("simplebank_%s_%s.ach", now.Format("20060102"), result.ACHFile.FileIDModifier)
+	}
+	err = os.WriteFile(name, []byte(result.ACHFile.Content), 0o600)
+	if err != nil {
+		return fmt.Errorf("file %d saved but cannot be written to %s: %w", result.ACHFile.ID, name, err)
+	}
+
+	fmt.Printf("exported file %d to %s: %d entries, total credit %d\n",
+		result.ACHFile.ID, name, result.ACHFile.EntryCount, result.ACHFile.TotalCredit)
+	return nil
+}
+
+func runACHReturns(ctx context.Context, service *nacha.Service, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: simplebank ach returns <file>")
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	results, err := service.ProcessReturns(ctx, file)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TRACE\tCODE\tTRANSFER\tSTATUS\tERROR")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n",
+			result.OriginalTraceNumber, result.ReturnCode, result.TransferID, result.Status, result.Error)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	db "simplebank/db/sqlc"
+	"simplebank/util"
+	"simplebank/worker"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// runTaskCommand 失败任务管理，和 gapi 的 ListFailedTasks 等接口一样直接操作配置的任务队列
+func runTaskCommand(ctx context.Context, config util.Config, store db.Store, args []string) error {
+	redisOpt := asynq.RedisClientOpt{
+		Addr: config.RedisAddress,
+	}
+	backend, err := worker.NewTaskBackend(config.TaskQueueBackend, redisOpt, store)
+	if err != nil {
+		return err
+	}
+	inspector := backend.Inspector()
+	defer inspector.Close()
+
+	switch args[0] {
+	case "list":
+		return runTaskList(ctx, inspector, args[1:])
+	case "show":
+		return runTaskShow(ctx, inspector, store, args[1:])
+	case "run":
+		return runTaskAction(ctx, args[1:], "run", inspector.RunTask)
+	case "delete":
+		return runTaskAction(ctx, args[1:], "delete", inspector.DeleteTask)
+	}
+	return fmt.Errorf("unknown tasks command %q", args[0])
+}
+
+func runTaskList(ctx context.Context, inspector worker.TaskInspector, args []string) error {
+	flags := flag.NewFlagSet("tasks list", flag.ContinueOnError)
+	state := flags.String("state", "archived", "retry or archived")
+	queue := flags.String("queue", "", "only list tasks in this queue")
+	taskType := flags.String("type", "", "only list tasks of this type, e.g. task:send_verify_email")
+	page := flags.Int("page", 1, "page id")
+	size := flags.Int("size", 20, "page size")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	filter := worker.FailedTaskFilter{
+		Queue:    *queue,
+		Type:     *taskType,
+		PageID:   *page,
+		PageSize: *size,
+	}
+	switch *state {
+	case "retry":
+		filter.State = asynq.TaskStateRetry
+	case "archived":
+		filter.State = asynq.TaskStateArchived
+	default:
+		return fmt.Errorf("unknown task state %q", *state)
+	}
+
+	tasks, err := inspector.ListFailedTasks(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tQUEUE\tTYPE\tRETRIED\tLAST FAILED\tLAST ERROR")
+	for _, task := range tasks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\t%s\t%s\n",
+			task.ID, task.Queue, task.Type, task.Retried, task.MaxRetry, formatTaskTime(task.LastFailedAt), task.LastErr)
+	}
+	return w.Flush()
+}
+
+func runTaskShow(ctx context.Context, inspector worker.TaskInspector, store db.Store, args []string) error {
+	flags := flag.NewFlagSet("tasks show", flag.ContinueOnError)
+	queue := flags.String("queue", "", "queue of the task, default search all queues")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: simplebank tasks show [-queue name] <id>")
+	}
+
+	task, err := inspector.GetTaskInfo(ctx, *queue, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("id:          %s\n", task.ID)
+	fmt.Printf("queue:       %s\n", task.Queue)
+	fmt.Printf("type:        %s\n", task.Type)
+	fmt.Printf("state:       %s\n", task.State)
+	fmt.Printf("retried:     %d/%d\n", task.Retried, task.MaxRetry)
+	fmt.Printf("next run:    %s\n", formatTaskTime(task.NextProcessAt))
+	fmt.Printf("last failed: %s\n", formatTaskTime(task.LastFailedAt))
+	fmt.Printf("last error:  %s\n", task.LastErr)
+	fmt.Printf("payload:     %s\n", task.Payload)
+
+	failures, err := store.ListTaskFailures(ctx, db.ListTaskFailuresParams{
+		TaskID: task.ID,
+		Limit:  20,
+	})
+	if err != nil {
+		return err
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FAILED AT\tRETRIED\tARCHIVED\tERROR")
+	for _, failure := range failures {
+		fmt.Fprintf(w, "%s\t%d\t%t\t%s\n",
+			formatTaskTime(failure.CreatedAt), failure.Retried, failure.Archived, failure.Error)
+	}
+	return w.Flush()
+}
+
+func runTaskAction(ctx context.Context, args []string, name string, action func(ctx context.Context, queue string, id string) error) error {
+	flags := flag.NewFlagSet("tasks "+name, flag.ContinueOnError)
+	queue := flags.String("queue", "", "queue of the task, default search all queues")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: simplebank tasks %s [-queue name] <id>", name)
+	}
+
+	if err := action(ctx, *queue, flags.Arg(0)); err != nil {
+		return err
+	}
+	fmt.Printf("%s task %s: ok\n", name, flags.Arg(0))
+	return nil
+}
+
+func formatTaskTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "account_freezes";
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "valid_freeze_status";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "freeze_status";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "role";
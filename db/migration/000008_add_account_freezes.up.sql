@@ -0,0 +1,22 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'depositor';
+
+ALTER TABLE "accounts" ADD COLUMN "freeze_status" varchar NOT NULL DEFAULT 'active';
+
+ALTER TABLE "accounts" ADD CONSTRAINT "valid_freeze_status" CHECK ("freeze_status" IN ('active', 'debit_frozen', 'frozen'));
+
+CREATE TABLE "account_freezes" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "freeze_status" varchar NOT NULL,
+  "reason" varchar NOT NULL,
+  "actor" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "account_freezes" ("account_id");
+
+COMMENT ON COLUMN "account_freezes"."freeze_status" IS 'status after the change, active means unfrozen';
+
+ALTER TABLE "account_freezes" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_freezes" ADD FOREIGN KEY ("actor") REFERENCES "users" ("username");
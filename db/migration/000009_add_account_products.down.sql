@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS "accounts_owner_currency_product_type_idx";
+
+CREATE UNIQUE INDEX ON "accounts" ("owner", "currency");
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "accounts_product_type_fkey";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "product_type";
+
+DROP TABLE IF EXISTS "account_products";
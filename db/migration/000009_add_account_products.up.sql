@@ -0,0 +1,23 @@
+CREATE TABLE "account_products" (
+  "code" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "max_monthly_outgoing_transfers" int,
+  "min_balance" bigint NOT NULL DEFAULT 0,
+  "allow_external_transfers" bool NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "account_products"."max_monthly_outgoing_transfers" IS 'null means unlimited';
+
+INSERT INTO "account_products" ("code", "name", "max_monthly_outgoing_transfers", "min_balance", "allow_external_transfers")
+VALUES
+  ('checking', 'Checking', NULL, 0, true),
+  ('savings', 'Savings', 6, 0, false);
+
+ALTER TABLE "accounts" ADD COLUMN "product_type" varchar NOT NULL DEFAULT 'checking';
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("product_type") REFERENCES "account_products" ("code");
+
+DROP INDEX IF EXISTS "accounts_owner_currency_idx";
+
+CREATE UNIQUE INDEX ON "accounts" ("owner", "currency", "product_type");
@@ -0,0 +1,19 @@
+DROP TABLE IF EXISTS "interest_accruals";
+
+DROP TABLE IF EXISTS "interest_postings";
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "positive_balance";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "positive_balance" CHECK ("balance" >= 0);
+
+DELETE FROM "accounts" WHERE "owner" = 'bank';
+
+DELETE FROM "users" WHERE "username" = 'bank';
+
+DELETE FROM "account_products" WHERE "code" = 'interest_expense';
+
+ALTER TABLE "account_products" DROP CONSTRAINT IF EXISTS "valid_day_count_convention";
+
+ALTER TABLE "account_products" DROP COLUMN IF EXISTS "day_count_convention";
+
+ALTER TABLE "account_products" DROP COLUMN IF EXISTS "annual_interest_rate_bps";
@@ -0,0 +1,58 @@
+ALTER TABLE "account_products" ADD COLUMN "annual_interest_rate_bps" int NOT NULL DEFAULT 0;
+
+ALTER TABLE "account_products" ADD COLUMN "day_count_convention" varchar NOT NULL DEFAULT 'ACT/365';
+
+ALTER TABLE "account_products" ADD CONSTRAINT "valid_day_count_convention" CHECK ("day_count_convention" IN ('ACT/365', 'ACT/360', 'ACT/ACT'));
+
+UPDATE "account_products" SET "annual_interest_rate_bps" = 200 WHERE "code" = 'savings';
+
+INSERT INTO "account_products" ("code", "name", "min_balance", "allow_external_transfers")
+VALUES ('interest_expense', 'Interest Expense', 0, false);
+
+-- 银行自己的系统账户都挂在 bank 用户下，允许出现负余额
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email", "is_email_verified", "role")
+VALUES ('bank', '', 'Simple Bank', 'system@simplebank.internal', true, 'system');
+
+ALTER TABLE "accounts" DROP CONSTRAINT "positive_balance";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "positive_balance" CHECK ("balance" >= 0 OR "owner" = 'bank');
+
+CREATE TABLE "interest_postings" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "period" date NOT NULL,
+  "accrued_micros" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "interest_postings" ("account_id", "period");
+
+COMMENT ON COLUMN "interest_postings"."period" IS 'first day of the posted month';
+
+COMMENT ON COLUMN "interest_postings"."amount" IS 'whole minor units, rounded down from accrued_micros';
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE TABLE "interest_accruals" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "annual_rate_bps" int NOT NULL,
+  "day_count_convention" varchar NOT NULL,
+  "amount_micros" bigint NOT NULL,
+  "posting_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "interest_accruals" ("account_id", "accrual_date");
+
+COMMENT ON COLUMN "interest_accruals"."amount_micros" IS 'millionths of a minor unit';
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("posting_id") REFERENCES "interest_postings" ("id");
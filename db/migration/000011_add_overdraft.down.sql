@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS "overdraft_charges";
+
+DELETE FROM "account_products" WHERE "code" = 'overdraft_interest_income';
+
+ALTER TABLE "account_products" DROP COLUMN IF EXISTS "overdraft_interest_rate_bps";
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "positive_balance";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "positive_balance" CHECK ("balance" >= 0 OR "owner" = 'bank');
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "valid_overdraft_limit";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "overdraft_limit";
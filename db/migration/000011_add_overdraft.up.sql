@@ -0,0 +1,37 @@
+ALTER TABLE "accounts" ADD COLUMN "overdraft_limit" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "accounts" ADD CONSTRAINT "valid_overdraft_limit" CHECK ("overdraft_limit" >= 0);
+
+ALTER TABLE "accounts" DROP CONSTRAINT "positive_balance";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "positive_balance" CHECK ("balance" >= -"overdraft_limit" OR "owner" = 'bank');
+
+ALTER TABLE "account_products" ADD COLUMN "overdraft_interest_rate_bps" int NOT NULL DEFAULT 0;
+
+UPDATE "account_products" SET "overdraft_interest_rate_bps" = 1800 WHERE "code" = 'checking';
+
+INSERT INTO "account_products" ("code", "name", "min_balance", "allow_external_transfers")
+VALUES ('overdraft_interest_income', 'Overdraft Interest Income', 0, false);
+
+CREATE TABLE "overdraft_charges" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "charge_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "annual_rate_bps" int NOT NULL,
+  "amount_micros" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "residual_micros" bigint NOT NULL,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "overdraft_charges" ("account_id", "charge_date");
+
+COMMENT ON COLUMN "overdraft_charges"."amount_micros" IS 'interest of the day plus residual carried from the previous charge';
+
+COMMENT ON COLUMN "overdraft_charges"."residual_micros" IS 'sub minor unit remainder carried to the next charge';
+
+ALTER TABLE "overdraft_charges" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "overdraft_charges" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,19 @@
+CREATE TABLE "account_members" (
+  "account_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "username")
+);
+
+CREATE INDEX ON "account_members" ("username");
+
+ALTER TABLE "account_members" ADD CONSTRAINT "valid_member_role" CHECK ("role" IN ('owner', 'co_owner', 'viewer'));
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+-- 已有账户的 owner 全部迁移成成员
+INSERT INTO "account_members" ("account_id", "username", "role")
+SELECT "id", "owner", 'owner' FROM "accounts" WHERE "owner" <> 'bank';
@@ -0,0 +1,16 @@
+CREATE TABLE "payees" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "nickname" varchar NOT NULL,
+  "holder_name" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "payees" ("owner", "account_id");
+
+COMMENT ON COLUMN "payees"."holder_name" IS 'full name of the account owner when the payee was verified';
+
+ALTER TABLE "payees" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "payees" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
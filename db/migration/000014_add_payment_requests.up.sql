@@ -0,0 +1,36 @@
+CREATE TABLE "payment_requests" (
+  "id" bigserial PRIMARY KEY,
+  "requester" varchar NOT NULL,
+  "payer" varchar NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "memo" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "payment_requests" ("payer", "status");
+
+CREATE INDEX ON "payment_requests" ("requester");
+
+CREATE INDEX ON "payment_requests" ("status", "expires_at");
+
+COMMENT ON COLUMN "payment_requests"."to_account_id" IS 'requester account that receives the money';
+
+COMMENT ON COLUMN "payment_requests"."transfer_id" IS 'set when the payer accepts the request';
+
+ALTER TABLE "payment_requests" ADD CONSTRAINT "positive_request_amount" CHECK ("amount" > 0);
+
+ALTER TABLE "payment_requests" ADD CONSTRAINT "valid_request_status" CHECK ("status" IN ('pending', 'paid', 'declined', 'expired'));
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("requester") REFERENCES "users" ("username");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("payer") REFERENCES "users" ("username");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
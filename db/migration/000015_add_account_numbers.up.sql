@@ -0,0 +1,26 @@
+ALTER TABLE "accounts" ADD COLUMN "account_number" varchar;
+
+-- 已有账户按默认银行代码 SMPL 补号，系统账户用 BANK
+-- 校验码按 IBAN 规则: 98 - (BBAN + 'SB00' 转成数字后 mod 97)
+-- SMPL = 28 22 25 21, BANK = 11 10 23 20, SB00 = 28 11 00
+WITH "numbered" AS (
+  SELECT
+    "id",
+    CASE WHEN "owner" = 'bank' THEN 'BANK' ELSE 'SMPL' END AS "bank_code",
+    CASE WHEN "owner" = 'bank' THEN '11102320' ELSE '28222521' END AS "bank_code_digits",
+    lpad((floor(random() * 10000000000))::bigint::text, 10, '0') AS "digits"
+  FROM "accounts"
+)
+UPDATE "accounts"
+SET "account_number" = 'SB'
+  || lpad((98 - mod(("numbered"."bank_code_digits" || "numbered"."digits" || '281100')::numeric, 97))::text, 2, '0')
+  || "numbered"."bank_code"
+  || "numbered"."digits"
+FROM "numbered"
+WHERE "accounts"."id" = "numbered"."id";
+
+ALTER TABLE "accounts" ALTER COLUMN "account_number" SET NOT NULL;
+
+CREATE UNIQUE INDEX ON "accounts" ("account_number");
+
+COMMENT ON COLUMN "accounts"."account_number" IS 'external IBAN-style account number with mod-97 check digits';
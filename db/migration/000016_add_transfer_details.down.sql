@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS "transfers_to_tsvector_idx";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "metadata";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "external_reference";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "private_note";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "memo";
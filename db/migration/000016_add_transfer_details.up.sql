@@ -0,0 +1,23 @@
+ALTER TABLE "transfers" ADD COLUMN "memo" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfers" ADD COLUMN "private_note" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfers" ADD COLUMN "external_reference" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfers" ADD COLUMN "metadata" jsonb NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN "transfers"."memo" IS 'visible to both parties';
+
+COMMENT ON COLUMN "transfers"."private_note" IS 'visible to the sender only';
+
+ALTER TABLE "transfers" ADD CONSTRAINT "memo_length" CHECK (char_length("memo") <= 140);
+
+ALTER TABLE "transfers" ADD CONSTRAINT "private_note_length" CHECK (char_length("private_note") <= 500);
+
+ALTER TABLE "transfers" ADD CONSTRAINT "external_reference_length" CHECK (char_length("external_reference") <= 64);
+
+-- API 限制 4KB，这里按 jsonb 文本长度留余量兜底
+ALTER TABLE "transfers" ADD CONSTRAINT "metadata_object" CHECK (jsonb_typeof("metadata") = 'object' AND octet_length("metadata"::text) <= 8192);
+
+-- 流水里按备注全文搜索
+CREATE INDEX ON "transfers" USING GIN (to_tsvector('simple', "memo"));
@@ -0,0 +1,5 @@
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "fee";
+
+DROP TABLE IF EXISTS "fee_schedules";
+
+DELETE FROM "account_products" WHERE "code" = 'fee_income';
@@ -0,0 +1,34 @@
+INSERT INTO "account_products" ("code", "name", "min_balance", "allow_external_transfers")
+VALUES ('fee_income', 'Fee Income', 0, false);
+
+CREATE TABLE "fee_schedules" (
+  "id" bigserial PRIMARY KEY,
+  "transfer_type" varchar NOT NULL,
+  "product_type" varchar,
+  "flat_fee" bigint NOT NULL DEFAULT 0,
+  "percentage_bps" int NOT NULL DEFAULT 0,
+  "percentage_threshold" bigint NOT NULL DEFAULT 0,
+  "max_fee" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "fee_schedules"."product_type" IS 'product of the sending account, NULL applies to every product';
+
+COMMENT ON COLUMN "fee_schedules"."percentage_threshold" IS 'percentage is charged on the part of the amount above this';
+
+COMMENT ON COLUMN "fee_schedules"."max_fee" IS '0 means no cap';
+
+ALTER TABLE "fee_schedules" ADD CONSTRAINT "valid_fee" CHECK (
+  "flat_fee" >= 0 AND "percentage_bps" >= 0 AND "percentage_threshold" >= 0 AND "max_fee" >= 0
+);
+
+-- 同一转账类型下每个产品只能有一条，NULL 的通用配置也只能有一条
+CREATE UNIQUE INDEX ON "fee_schedules" ("transfer_type", COALESCE("product_type", ''));
+
+ALTER TABLE "fee_schedules" ADD FOREIGN KEY ("product_type") REFERENCES "account_products" ("code");
+
+ALTER TABLE "transfers" ADD COLUMN "fee" bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "transfers"."fee" IS 'charged to the sender on top of amount';
+
+ALTER TABLE "transfers" ADD CONSTRAINT "non_negative_fee" CHECK ("fee" >= 0);
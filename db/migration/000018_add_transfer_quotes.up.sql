@@ -0,0 +1,5 @@
+ALTER TABLE "transfers" ADD COLUMN "quote_id" uuid;
+
+COMMENT ON COLUMN "transfers"."quote_id" IS 'quote whose fee was honoured, each quote can be used once';
+
+CREATE UNIQUE INDEX ON "transfers" ("quote_id");
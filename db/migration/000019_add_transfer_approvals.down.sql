@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "transfer_approvals";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "held_amount";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "status";
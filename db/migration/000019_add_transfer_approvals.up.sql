@@ -0,0 +1,28 @@
+ALTER TABLE "transfers" ADD COLUMN "status" varchar NOT NULL DEFAULT 'completed';
+
+ALTER TABLE "transfers" ADD CONSTRAINT "valid_transfer_status" CHECK ("status" IN ('pending_approval', 'completed', 'rejected', 'expired'));
+
+ALTER TABLE "accounts" ADD COLUMN "held_amount" bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "accounts"."held_amount" IS 'reserved by transfers waiting for approval, not available for spending';
+
+ALTER TABLE "accounts" ADD CONSTRAINT "non_negative_held_amount" CHECK ("held_amount" >= 0);
+
+CREATE TABLE "transfer_approvals" (
+  "transfer_id" bigint PRIMARY KEY,
+  "requested_by" varchar NOT NULL,
+  "decided_by" varchar,
+  "expires_at" timestamptz NOT NULL,
+  "decided_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "transfer_approvals" ("expires_at");
+
+ALTER TABLE "transfer_approvals" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfer_approvals" ADD FOREIGN KEY ("requested_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_approvals" ADD FOREIGN KEY ("decided_by") REFERENCES "users" ("username");
+
+CREATE INDEX ON "transfers" ("status") WHERE "status" = 'pending_approval';
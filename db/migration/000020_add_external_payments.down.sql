@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "external_payments";
+
+ALTER TABLE "transfers" DROP CONSTRAINT IF EXISTS "valid_transfer_status";
+
+ALTER TABLE "transfers" ADD CONSTRAINT "valid_transfer_status" CHECK ("status" IN ('pending_approval', 'completed', 'rejected', 'expired'));
+
+DELETE FROM "account_products" WHERE "code" IN ('external_clearing', 'external_settlement');
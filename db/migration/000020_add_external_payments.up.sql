@@ -0,0 +1,40 @@
+INSERT INTO "account_products" ("code", "name", "min_balance", "allow_external_transfers")
+VALUES
+  ('external_clearing', 'External Clearing', 0, false),
+  ('external_settlement', 'External Settlement', 0, false);
+
+ALTER TABLE "transfers" DROP CONSTRAINT "valid_transfer_status";
+
+ALTER TABLE "transfers" ADD CONSTRAINT "valid_transfer_status" CHECK ("status" IN (
+  'pending_approval', 'completed', 'rejected', 'expired',
+  'initiated', 'pending', 'settled', 'failed', 'returned'
+));
+
+CREATE TABLE "external_payments" (
+  "transfer_id" bigint PRIMARY KEY,
+  "network" varchar NOT NULL,
+  "creditor_account_number" varchar NOT NULL,
+  "creditor_name" varchar NOT NULL,
+  "network_reference" varchar,
+  "failure_reason" varchar NOT NULL DEFAULT '',
+  "settlement_transfer_id" bigint,
+  "reversal_transfer_id" bigint,
+  "submitted_at" timestamptz,
+  "settled_at" timestamptz,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "external_payments"."transfer_id" IS 'customer to clearing account transfer made at initiation';
+
+COMMENT ON COLUMN "external_payments"."settlement_transfer_id" IS 'clearing to settlement account transfer made when the network settles';
+
+COMMENT ON COLUMN "external_payments"."reversal_transfer_id" IS 'refund to the customer when the payment fails or is returned';
+
+CREATE UNIQUE INDEX ON "external_payments" ("network", "network_reference");
+
+ALTER TABLE "external_payments" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "external_payments" ADD FOREIGN KEY ("settlement_transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "external_payments" ADD FOREIGN KEY ("reversal_transfer_id") REFERENCES "transfers" ("id");
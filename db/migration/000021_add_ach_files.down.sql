@@ -0,0 +1,7 @@
+ALTER TABLE "external_payments" DROP COLUMN IF EXISTS "ach_file_id";
+
+ALTER TABLE "external_payments" DROP COLUMN IF EXISTS "creditor_routing_number";
+
+DROP SEQUENCE IF EXISTS "ach_trace_numbers";
+
+DROP TABLE IF EXISTS "ach_files";
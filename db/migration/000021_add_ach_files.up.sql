@@ -0,0 +1,22 @@
+CREATE TABLE "ach_files" (
+  "id" bigserial PRIMARY KEY,
+  "file_id_modifier" varchar NOT NULL,
+  "entry_count" int NOT NULL,
+  "total_credit" bigint NOT NULL,
+  "content" text NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "ach_files" ("created_at");
+
+CREATE SEQUENCE "ach_trace_numbers" MAXVALUE 9999999 CYCLE;
+
+COMMENT ON SEQUENCE "ach_trace_numbers" IS 'last 7 digits of ACH trace numbers';
+
+ALTER TABLE "external_payments" ADD COLUMN "creditor_routing_number" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "external_payments" ADD COLUMN "ach_file_id" bigint;
+
+COMMENT ON COLUMN "external_payments"."ach_file_id" IS 'batch file the payment was exported in, for batch networks only';
+
+ALTER TABLE "external_payments" ADD FOREIGN KEY ("ach_file_id") REFERENCES "ach_files" ("id");
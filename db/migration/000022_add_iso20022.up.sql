@@ -0,0 +1,23 @@
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'null for postings that are not part of a transfer, such as interest';
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "entries" ("account_id", "created_at");
+
+CREATE TABLE "payment_imports" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "message_id" varchar NOT NULL,
+  "instruction_count" int NOT NULL,
+  "accepted_count" int NOT NULL,
+  "status_report" text NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "payment_imports"."message_id" IS 'GrpHdr/MsgId of the pain.001 file, unique per user';
+
+CREATE UNIQUE INDEX ON "payment_imports" ("username", "message_id");
+
+ALTER TABLE "payment_imports" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
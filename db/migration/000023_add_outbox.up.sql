@@ -0,0 +1,20 @@
+CREATE TABLE "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "task_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "queue" varchar NOT NULL,
+  "max_retry" int NOT NULL,
+  "process_at" timestamptz,
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "published_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON TABLE "outbox" IS 'tasks written in the same transaction as the business change, published to the task queue by the relay';
+
+COMMENT ON COLUMN "outbox"."process_at" IS 'null to process as soon as published';
+
+COMMENT ON COLUMN "outbox"."attempts" IS 'failed publish attempts';
+
+CREATE INDEX ON "outbox" ("id") WHERE "published_at" IS NULL;
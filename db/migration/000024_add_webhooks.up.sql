@@ -0,0 +1,36 @@
+CREATE TABLE "webhook_subscriptions" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "url" varchar NOT NULL,
+  "event_types" varchar[] NOT NULL,
+  "secret" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "webhook_subscriptions"."secret" IS 'HMAC-SHA256 signing key, only shown to the owner when the subscription is created';
+
+CREATE INDEX ON "webhook_subscriptions" ("owner");
+
+ALTER TABLE "webhook_subscriptions" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+CREATE TABLE "webhook_deliveries" (
+  "id" bigserial PRIMARY KEY,
+  "subscription_id" bigint NOT NULL,
+  "event_id" uuid NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "response_status" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "delivered_at" timestamptz
+);
+
+COMMENT ON COLUMN "webhook_deliveries"."status" IS 'pending, succeeded or failed after the last retry';
+
+COMMENT ON COLUMN "webhook_deliveries"."response_status" IS 'HTTP status of the last attempt, 0 if the receiver was unreachable';
+
+CREATE UNIQUE INDEX ON "webhook_deliveries" ("subscription_id", "event_id");
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("subscription_id") REFERENCES "webhook_subscriptions" ("id") ON DELETE CASCADE;
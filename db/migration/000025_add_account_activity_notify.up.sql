@@ -0,0 +1,15 @@
+-- 分录提交后通知监听的服务，payload 只带账户 ID，新分录由服务按游标自己查
+-- NOTIFY 在事务提交时才发出，回滚的事务不会通知；同一个事务里重复的通知会合并
+CREATE FUNCTION notify_account_activity() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('account_activity', NEW.account_id::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER entries_notify_account_activity
+AFTER INSERT ON "entries"
+FOR EACH ROW EXECUTE FUNCTION notify_account_activity();
+
+-- 按账户和分录 ID 续传
+CREATE INDEX "entries_account_id_id_idx" ON "entries" ("account_id", "id");
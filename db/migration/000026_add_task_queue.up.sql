@@ -0,0 +1,26 @@
+CREATE TABLE "task_queue" (
+  "id" bigserial PRIMARY KEY,
+  "task_id" varchar UNIQUE NOT NULL,
+  "task_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "queue" varchar NOT NULL,
+  "state" varchar NOT NULL DEFAULT 'pending',
+  "retried" int NOT NULL DEFAULT 0,
+  "max_retry" int NOT NULL,
+  "process_at" timestamptz NOT NULL DEFAULT (now()),
+  "lease_expires_at" timestamptz,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "last_failed_at" timestamptz,
+  "completed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON TABLE "task_queue" IS 'task queue backend for deployments without redis, workers claim tasks with SKIP LOCKED';
+
+COMMENT ON COLUMN "task_queue"."task_id" IS 'duplicate task ids are rejected while the row exists';
+
+COMMENT ON COLUMN "task_queue"."state" IS 'pending, active, completed or archived';
+
+COMMENT ON COLUMN "task_queue"."lease_expires_at" IS 'active tasks whose lease expired are claimed again, the worker probably crashed';
+
+CREATE INDEX ON "task_queue" ("queue", "process_at") WHERE "state" IN ('pending', 'active');
@@ -0,0 +1,19 @@
+CREATE TABLE "task_failures" (
+  "id" bigserial PRIMARY KEY,
+  "task_id" varchar NOT NULL,
+  "task_type" varchar NOT NULL,
+  "queue" varchar NOT NULL,
+  "error" varchar NOT NULL,
+  "retried" int NOT NULL,
+  "max_retry" int NOT NULL,
+  "archived" boolean NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON TABLE "task_failures" IS 'one row per failed attempt of a worker task, on every task queue backend';
+
+COMMENT ON COLUMN "task_failures"."archived" IS 'the task will not be retried, it needs an operator to run or delete it';
+
+CREATE INDEX ON "task_failures" ("task_id");
+
+CREATE INDEX ON "task_failures" ("task_type", "created_at");
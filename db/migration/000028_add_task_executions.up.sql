@@ -0,0 +1,13 @@
+CREATE TABLE "task_executions" (
+  "task_id" varchar NOT NULL,
+  "step" varchar NOT NULL,
+  "result" jsonb NOT NULL DEFAULT 'null',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("task_id", "step")
+);
+
+COMMENT ON TABLE "task_executions" IS 'ledger of worker task steps that already succeeded, retries of the same task skip them';
+
+COMMENT ON COLUMN "task_executions"."step" IS 'completed for the whole task, otherwise a step inside the handler';
+
+CREATE INDEX ON "task_executions" ("created_at");
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS "outbox_published_at_idx";
+
+DROP INDEX IF EXISTS "verify_emails_expired_at_idx";
+
+DROP INDEX IF EXISTS "sessions_expires_at_idx";
+
+DROP TABLE IF EXISTS "periodic_job_runs";
+
+DROP TABLE IF EXISTS "scheduler_leases";
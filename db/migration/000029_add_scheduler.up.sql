@@ -0,0 +1,23 @@
+CREATE TABLE "scheduler_leases" (
+  "name" varchar PRIMARY KEY,
+  "holder" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "acquired_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON TABLE "scheduler_leases" IS 'leader election for the periodic scheduler, only the holder of an unexpired lease enqueues jobs';
+
+CREATE TABLE "periodic_job_runs" (
+  "job" varchar NOT NULL,
+  "scheduled_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("job", "scheduled_at")
+);
+
+COMMENT ON TABLE "periodic_job_runs" IS 'each cron slot of a job is enqueued once, even when the leader changes';
+
+CREATE INDEX ON "sessions" ("expires_at");
+
+CREATE INDEX ON "verify_emails" ("expired_at");
+
+CREATE INDEX ON "outbox" ("published_at");
@@ -0,0 +1,5 @@
+ALTER TABLE "interest_postings" ADD COLUMN "residual_micros" bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "interest_postings"."accrued_micros" IS 'interest accrued in the period plus residual carried from the previous posting';
+
+COMMENT ON COLUMN "interest_postings"."residual_micros" IS 'sub minor unit remainder carried to the next posting';
@@ -0,0 +1 @@
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "hide_to_account";
@@ -0,0 +1,3 @@
+ALTER TABLE "transfers" ADD COLUMN "hide_to_account" boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN "transfers"."hide_to_account" IS 'addressed by recipient username or email, to_account_id is not shown to the sender';
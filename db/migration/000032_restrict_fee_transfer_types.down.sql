@@ -0,0 +1 @@
+ALTER TABLE "fee_schedules" DROP CONSTRAINT IF EXISTS "valid_transfer_type";
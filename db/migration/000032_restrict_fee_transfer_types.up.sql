@@ -0,0 +1,4 @@
+-- 没有换汇，跨币种的费率永远用不到
+DELETE FROM "fee_schedules" WHERE "transfer_type" NOT IN ('internal', 'external');
+
+ALTER TABLE "fee_schedules" ADD CONSTRAINT "valid_transfer_type" CHECK ("transfer_type" IN ('internal', 'external'));
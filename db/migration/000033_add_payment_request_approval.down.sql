@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS "payment_requests_transfer_id_idx";
+
+UPDATE "payment_requests" SET "status" = 'pending', "transfer_id" = NULL WHERE "status" = 'pending_approval';
+
+ALTER TABLE "payment_requests" DROP CONSTRAINT IF EXISTS "valid_request_status";
+
+ALTER TABLE "payment_requests" ADD CONSTRAINT "valid_request_status" CHECK ("status" IN ('pending', 'paid', 'declined', 'expired'));
@@ -0,0 +1,7 @@
+ALTER TABLE "payment_requests" DROP CONSTRAINT "valid_request_status";
+
+ALTER TABLE "payment_requests" ADD CONSTRAINT "valid_request_status" CHECK ("status" IN ('pending', 'pending_approval', 'paid', 'declined', 'expired'));
+
+COMMENT ON COLUMN "payment_requests"."transfer_id" IS 'set when the payer accepts the request, points to a pending transfer while it awaits approval';
+
+CREATE INDEX ON "payment_requests" ("transfer_id");
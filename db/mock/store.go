@@ -11,8 +11,10 @@ package mockdb
 
 import (
 	context "context"
+	sql "database/sql"
 	reflect "reflect"
 	db "simplebank/db/sqlc"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -42,317 +44,2256 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AcquireSchedulerLease mocks base method.
+func (m *MockStore) AcquireSchedulerLease(ctx context.Context, arg db.AcquireSchedulerLeaseParams) (db.SchedulerLease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireSchedulerLease", ctx, arg)
+	ret0, _ := ret[0].(db.SchedulerLease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireSchedulerLease indicates an expected call of AcquireSchedulerLease.
+func (mr *MockStoreMockRecorder) AcquireSchedulerLease(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireSchedulerLease", reflect.TypeOf((*MockStore)(nil).AcquireSchedulerLease), ctx, arg)
+}
+
 // AddAccountBalance mocks base method.
 func (m *MockStore) AddAccountBalance(ctx context.Context, arg db.AddAccountBalanceParams) (db.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddAccountBalance", ctx, arg)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "AddAccountBalance", ctx, arg)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAccountBalance indicates an expected call of AddAccountBalance.
+func (mr *MockStoreMockRecorder) AddAccountBalance(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalance", reflect.TypeOf((*MockStore)(nil).AddAccountBalance), ctx, arg)
+}
+
+// AddAccountHeldAmount mocks base method.
+func (m *MockStore) AddAccountHeldAmount(ctx context.Context, arg db.AddAccountHeldAmountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccountHeldAmount", ctx, arg)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAccountHeldAmount indicates an expected call of AddAccountHeldAmount.
+func (mr *MockStoreMockRecorder) AddAccountHeldAmount(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountHeldAmount", reflect.TypeOf((*MockStore)(nil).AddAccountHeldAmount), ctx, arg)
+}
+
+// ApproveTransferTx mocks base method.
+func (m *MockStore) ApproveTransferTx(ctx context.Context, arg db.ApproveTransferTxParams) (db.ApproveTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveTransferTx", ctx, arg)
+	ret0, _ := ret[0].(db.ApproveTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveTransferTx indicates an expected call of ApproveTransferTx.
+func (mr *MockStoreMockRecorder) ApproveTransferTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveTransferTx", reflect.TypeOf((*MockStore)(nil).ApproveTransferTx), ctx, arg)
+}
+
+// ArchiveTask mocks base method.
+func (m *MockStore) ArchiveTask(ctx context.Context, arg db.ArchiveTaskParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveTask", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ArchiveTask indicates an expected call of ArchiveTask.
+func (mr *MockStoreMockRecorder) ArchiveTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveTask", reflect.TypeOf((*MockStore)(nil).ArchiveTask), ctx, arg)
+}
+
+// ChargeOverdraftInterestTx mocks base method.
+func (m *MockStore) ChargeOverdraftInterestTx(ctx context.Context, arg db.ChargeOverdraftInterestTxParams) (db.ChargeOverdraftInterestTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChargeOverdraftInterestTx", ctx, arg)
+	ret0, _ := ret[0].(db.ChargeOverdraftInterestTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChargeOverdraftInterestTx indicates an expected call of ChargeOverdraftInterestTx.
+func (mr *MockStoreMockRecorder) ChargeOverdraftInterestTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChargeOverdraftInterestTx", reflect.TypeOf((*MockStore)(nil).ChargeOverdraftInterestTx), ctx, arg)
+}
+
+// CheckTransfer mocks base method.
+func (m *MockStore) CheckTransfer(ctx context.Context, arg db.TransferTxParams) (db.CheckTransferResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckTransfer", ctx, arg)
+	ret0, _ := ret[0].(db.CheckTransferResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckTransfer indicates an expected call of CheckTransfer.
+func (mr *MockStoreMockRecorder) CheckTransfer(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTransfer", reflect.TypeOf((*MockStore)(nil).CheckTransfer), ctx, arg)
+}
+
+// ClaimTask mocks base method.
+func (m *MockStore) ClaimTask(ctx context.Context, arg db.ClaimTaskParams) (db.TaskQueue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimTask", ctx, arg)
+	ret0, _ := ret[0].(db.TaskQueue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimTask indicates an expected call of ClaimTask.
+func (mr *MockStoreMockRecorder) ClaimTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimTask", reflect.TypeOf((*MockStore)(nil).ClaimTask), ctx, arg)
+}
+
+// CompletePaymentRequestTransfer mocks base method.
+func (m *MockStore) CompletePaymentRequestTransfer(ctx context.Context, transferID sql.NullInt64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompletePaymentRequestTransfer", ctx, transferID)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompletePaymentRequestTransfer indicates an expected call of CompletePaymentRequestTransfer.
+func (mr *MockStoreMockRecorder) CompletePaymentRequestTransfer(ctx, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompletePaymentRequestTransfer", reflect.TypeOf((*MockStore)(nil).CompletePaymentRequestTransfer), ctx, transferID)
+}
+
+// CompleteTask mocks base method.
+func (m *MockStore) CompleteTask(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteTask", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteTask indicates an expected call of CompleteTask.
+func (mr *MockStoreMockRecorder) CompleteTask(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTask", reflect.TypeOf((*MockStore)(nil).CompleteTask), ctx, id)
+}
+
+// CountACHFilesSince mocks base method.
+func (m *MockStore) CountACHFilesSince(ctx context.Context, createdAt time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountACHFilesSince", ctx, createdAt)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountACHFilesSince indicates an expected call of CountACHFilesSince.
+func (mr *MockStoreMockRecorder) CountACHFilesSince(ctx, createdAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountACHFilesSince", reflect.TypeOf((*MockStore)(nil).CountACHFilesSince), ctx, createdAt)
+}
+
+// CountMonthlyTransfersFromAccount mocks base method.
+func (m *MockStore) CountMonthlyTransfersFromAccount(ctx context.Context, fromAccountID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountMonthlyTransfersFromAccount", ctx, fromAccountID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountMonthlyTransfersFromAccount indicates an expected call of CountMonthlyTransfersFromAccount.
+func (mr *MockStoreMockRecorder) CountMonthlyTransfersFromAccount(ctx, fromAccountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountMonthlyTransfersFromAccount", reflect.TypeOf((*MockStore)(nil).CountMonthlyTransfersFromAccount), ctx, fromAccountID)
+}
+
+// CreateACHFile mocks base method.
+func (m *MockStore) CreateACHFile(ctx context.Context, arg db.CreateACHFileParams) (db.AchFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateACHFile", ctx, arg)
+	ret0, _ := ret[0].(db.AchFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateACHFile indicates an expected call of CreateACHFile.
+func (mr *MockStoreMockRecorder) CreateACHFile(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateACHFile", reflect.TypeOf((*MockStore)(nil).CreateACHFile), ctx, arg)
+}
+
+// CreateACHFileTx mocks base method.
+func (m *MockStore) CreateACHFileTx(ctx context.Context, arg db.CreateACHFileTxParams) (db.CreateACHFileTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateACHFileTx", ctx, arg)
+	ret0, _ := ret[0].(db.CreateACHFileTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateACHFileTx indicates an expected call of CreateACHFileTx.
+func (mr *MockStoreMockRecorder) CreateACHFileTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateACHFileTx", reflect.TypeOf((*MockStore)(nil).CreateACHFileTx), ctx, arg)
+}
+
+// CreateAccount mocks base method.
+func (m *MockStore) CreateAccount(ctx context.Context, arg db.CreateAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccount", ctx, arg)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockStoreMockRecorder) CreateAccount(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), ctx, arg)
+}
+
+// CreateAccountFreeze mocks base method.
+func (m *MockStore) CreateAccountFreeze(ctx context.Context, arg db.CreateAccountFreezeParams) (db.AccountFreeze, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountFreeze", ctx, arg)
+	ret0, _ := ret[0].(db.AccountFreeze)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountFreeze indicates an expected call of CreateAccountFreeze.
+func (mr *MockStoreMockRecorder) CreateAccountFreeze(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountFreeze", reflect.TypeOf((*MockStore)(nil).CreateAccountFreeze), ctx, arg)
+}
+
+// CreateAccountMember mocks base method.
+func (m *MockStore) CreateAccountMember(ctx context.Context, arg db.CreateAccountMemberParams) (db.AccountMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountMember", ctx, arg)
+	ret0, _ := ret[0].(db.AccountMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountMember indicates an expected call of CreateAccountMember.
+func (mr *MockStoreMockRecorder) CreateAccountMember(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountMember", reflect.TypeOf((*MockStore)(nil).CreateAccountMember), ctx, arg)
+}
+
+// CreateAccountTx mocks base method.
+func (m *MockStore) CreateAccountTx(ctx context.Context, arg db.CreateAccountParams) (db.CreateAccountTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountTx", ctx, arg)
+	ret0, _ := ret[0].(db.CreateAccountTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountTx indicates an expected call of CreateAccountTx.
+func (mr *MockStoreMockRecorder) CreateAccountTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountTx", reflect.TypeOf((*MockStore)(nil).CreateAccountTx), ctx, arg)
+}
+
+// CreateEntry mocks base method.
+func (m *MockStore) CreateEntry(ctx context.Context, arg db.CreateEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntry", ctx, arg)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntry indicates an expected call of CreateEntry.
+func (mr *MockStoreMockRecorder) CreateEntry(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), ctx, arg)
+}
+
+// CreateExternalPayment mocks base method.
+func (m *MockStore) CreateExternalPayment(ctx context.Context, arg db.CreateExternalPaymentParams) (db.ExternalPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExternalPayment", ctx, arg)
+	ret0, _ := ret[0].(db.ExternalPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExternalPayment indicates an expected call of CreateExternalPayment.
+func (mr *MockStoreMockRecorder) CreateExternalPayment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExternalPayment", reflect.TypeOf((*MockStore)(nil).CreateExternalPayment), ctx, arg)
+}
+
+// CreateFeeSchedule mocks base method.
+func (m *MockStore) CreateFeeSchedule(ctx context.Context, arg db.CreateFeeScheduleParams) (db.FeeSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFeeSchedule", ctx, arg)
+	ret0, _ := ret[0].(db.FeeSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFeeSchedule indicates an expected call of CreateFeeSchedule.
+func (mr *MockStoreMockRecorder) CreateFeeSchedule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeeSchedule", reflect.TypeOf((*MockStore)(nil).CreateFeeSchedule), ctx, arg)
+}
+
+// CreateInterestAccrual mocks base method.
+func (m *MockStore) CreateInterestAccrual(ctx context.Context, arg db.CreateInterestAccrualParams) (db.InterestAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInterestAccrual", ctx, arg)
+	ret0, _ := ret[0].(db.InterestAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInterestAccrual indicates an expected call of CreateInterestAccrual.
+func (mr *MockStoreMockRecorder) CreateInterestAccrual(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInterestAccrual", reflect.TypeOf((*MockStore)(nil).CreateInterestAccrual), ctx, arg)
+}
+
+// CreateInterestPosting mocks base method.
+func (m *MockStore) CreateInterestPosting(ctx context.Context, arg db.CreateInterestPostingParams) (db.InterestPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInterestPosting", ctx, arg)
+	ret0, _ := ret[0].(db.InterestPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInterestPosting indicates an expected call of CreateInterestPosting.
+func (mr *MockStoreMockRecorder) CreateInterestPosting(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInterestPosting", reflect.TypeOf((*MockStore)(nil).CreateInterestPosting), ctx, arg)
+}
+
+// CreateOutboxMessage mocks base method.
+func (m *MockStore) CreateOutboxMessage(ctx context.Context, arg db.CreateOutboxMessageParams) (db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxMessage", ctx, arg)
+	ret0, _ := ret[0].(db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutboxMessage indicates an expected call of CreateOutboxMessage.
+func (mr *MockStoreMockRecorder) CreateOutboxMessage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxMessage", reflect.TypeOf((*MockStore)(nil).CreateOutboxMessage), ctx, arg)
+}
+
+// CreateOverdraftCharge mocks base method.
+func (m *MockStore) CreateOverdraftCharge(ctx context.Context, arg db.CreateOverdraftChargeParams) (db.OverdraftCharge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOverdraftCharge", ctx, arg)
+	ret0, _ := ret[0].(db.OverdraftCharge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOverdraftCharge indicates an expected call of CreateOverdraftCharge.
+func (mr *MockStoreMockRecorder) CreateOverdraftCharge(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOverdraftCharge", reflect.TypeOf((*MockStore)(nil).CreateOverdraftCharge), ctx, arg)
+}
+
+// CreatePayee mocks base method.
+func (m *MockStore) CreatePayee(ctx context.Context, arg db.CreatePayeeParams) (db.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayee", ctx, arg)
+	ret0, _ := ret[0].(db.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePayee indicates an expected call of CreatePayee.
+func (mr *MockStoreMockRecorder) CreatePayee(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayee", reflect.TypeOf((*MockStore)(nil).CreatePayee), ctx, arg)
+}
+
+// CreatePaymentImport mocks base method.
+func (m *MockStore) CreatePaymentImport(ctx context.Context, arg db.CreatePaymentImportParams) (db.PaymentImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentImport", ctx, arg)
+	ret0, _ := ret[0].(db.PaymentImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentImport indicates an expected call of CreatePaymentImport.
+func (mr *MockStoreMockRecorder) CreatePaymentImport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentImport", reflect.TypeOf((*MockStore)(nil).CreatePaymentImport), ctx, arg)
+}
+
+// CreatePaymentRequest mocks base method.
+func (m *MockStore) CreatePaymentRequest(ctx context.Context, arg db.CreatePaymentRequestParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentRequest", ctx, arg)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentRequest indicates an expected call of CreatePaymentRequest.
+func (mr *MockStoreMockRecorder) CreatePaymentRequest(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentRequest", reflect.TypeOf((*MockStore)(nil).CreatePaymentRequest), ctx, arg)
+}
+
+// CreatePaymentRequestTx mocks base method.
+func (m *MockStore) CreatePaymentRequestTx(ctx context.Context, arg db.CreatePaymentRequestTxParams) (db.CreatePaymentRequestTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentRequestTx", ctx, arg)
+	ret0, _ := ret[0].(db.CreatePaymentRequestTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentRequestTx indicates an expected call of CreatePaymentRequestTx.
+func (mr *MockStoreMockRecorder) CreatePaymentRequestTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentRequestTx", reflect.TypeOf((*MockStore)(nil).CreatePaymentRequestTx), ctx, arg)
+}
+
+// CreatePendingTransferTx mocks base method.
+func (m *MockStore) CreatePendingTransferTx(ctx context.Context, arg db.CreatePendingTransferTxParams) (db.CreatePendingTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePendingTransferTx", ctx, arg)
+	ret0, _ := ret[0].(db.CreatePendingTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePendingTransferTx indicates an expected call of CreatePendingTransferTx.
+func (mr *MockStoreMockRecorder) CreatePendingTransferTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePendingTransferTx", reflect.TypeOf((*MockStore)(nil).CreatePendingTransferTx), ctx, arg)
+}
+
+// CreatePeriodicJobRun mocks base method.
+func (m *MockStore) CreatePeriodicJobRun(ctx context.Context, arg db.CreatePeriodicJobRunParams) (db.PeriodicJobRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePeriodicJobRun", ctx, arg)
+	ret0, _ := ret[0].(db.PeriodicJobRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePeriodicJobRun indicates an expected call of CreatePeriodicJobRun.
+func (mr *MockStoreMockRecorder) CreatePeriodicJobRun(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePeriodicJobRun", reflect.TypeOf((*MockStore)(nil).CreatePeriodicJobRun), ctx, arg)
+}
+
+// CreateSession mocks base method.
+func (m *MockStore) CreateSession(ctx context.Context, arg db.CreateSessionParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, arg)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockStoreMockRecorder) CreateSession(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), ctx, arg)
+}
+
+// CreateTaskExecution mocks base method.
+func (m *MockStore) CreateTaskExecution(ctx context.Context, arg db.CreateTaskExecutionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskExecution", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTaskExecution indicates an expected call of CreateTaskExecution.
+func (mr *MockStoreMockRecorder) CreateTaskExecution(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskExecution", reflect.TypeOf((*MockStore)(nil).CreateTaskExecution), ctx, arg)
+}
+
+// CreateTaskFailure mocks base method.
+func (m *MockStore) CreateTaskFailure(ctx context.Context, arg db.CreateTaskFailureParams) (db.TaskFailure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskFailure", ctx, arg)
+	ret0, _ := ret[0].(db.TaskFailure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaskFailure indicates an expected call of CreateTaskFailure.
+func (mr *MockStoreMockRecorder) CreateTaskFailure(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskFailure", reflect.TypeOf((*MockStore)(nil).CreateTaskFailure), ctx, arg)
+}
+
+// CreateTransfer mocks base method.
+func (m *MockStore) CreateTransfer(ctx context.Context, arg db.CreateTransferParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", ctx, arg)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockStoreMockRecorder) CreateTransfer(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), ctx, arg)
+}
+
+// CreateTransferApproval mocks base method.
+func (m *MockStore) CreateTransferApproval(ctx context.Context, arg db.CreateTransferApprovalParams) (db.TransferApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferApproval", ctx, arg)
+	ret0, _ := ret[0].(db.TransferApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferApproval indicates an expected call of CreateTransferApproval.
+func (mr *MockStoreMockRecorder) CreateTransferApproval(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferApproval", reflect.TypeOf((*MockStore)(nil).CreateTransferApproval), ctx, arg)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, arg)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), ctx, arg)
+}
+
+// CreateUserTx mocks base method.
+func (m *MockStore) CreateUserTx(ctx context.Context, arg db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserTx", ctx, arg)
+	ret0, _ := ret[0].(db.CreateUserTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserTx indicates an expected call of CreateUserTx.
+func (mr *MockStoreMockRecorder) CreateUserTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserTx", reflect.TypeOf((*MockStore)(nil).CreateUserTx), ctx, arg)
+}
+
+// CreateVerifyEmail mocks base method.
+func (m *MockStore) CreateVerifyEmail(ctx context.Context, arg db.CreateVerifyEmailParams) (db.VerifyEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVerifyEmail", ctx, arg)
+	ret0, _ := ret[0].(db.VerifyEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVerifyEmail indicates an expected call of CreateVerifyEmail.
+func (mr *MockStoreMockRecorder) CreateVerifyEmail(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVerifyEmail", reflect.TypeOf((*MockStore)(nil).CreateVerifyEmail), ctx, arg)
+}
+
+// CreateWebhookDelivery mocks base method.
+func (m *MockStore) CreateWebhookDelivery(ctx context.Context, arg db.CreateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", ctx, arg)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery.
+func (mr *MockStoreMockRecorder) CreateWebhookDelivery(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CreateWebhookDelivery), ctx, arg)
+}
+
+// CreateWebhookSubscription mocks base method.
+func (m *MockStore) CreateWebhookSubscription(ctx context.Context, arg db.CreateWebhookSubscriptionParams) (db.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookSubscription", ctx, arg)
+	ret0, _ := ret[0].(db.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookSubscription indicates an expected call of CreateWebhookSubscription.
+func (mr *MockStoreMockRecorder) CreateWebhookSubscription(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookSubscription", reflect.TypeOf((*MockStore)(nil).CreateWebhookSubscription), ctx, arg)
+}
+
+// DecideTransferApproval mocks base method.
+func (m *MockStore) DecideTransferApproval(ctx context.Context, arg db.DecideTransferApprovalParams) (db.TransferApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideTransferApproval", ctx, arg)
+	ret0, _ := ret[0].(db.TransferApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideTransferApproval indicates an expected call of DecideTransferApproval.
+func (mr *MockStoreMockRecorder) DecideTransferApproval(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideTransferApproval", reflect.TypeOf((*MockStore)(nil).DecideTransferApproval), ctx, arg)
+}
+
+// DeclinePaymentRequest mocks base method.
+func (m *MockStore) DeclinePaymentRequest(ctx context.Context, id int64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclinePaymentRequest", ctx, id)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeclinePaymentRequest indicates an expected call of DeclinePaymentRequest.
+func (mr *MockStoreMockRecorder) DeclinePaymentRequest(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclinePaymentRequest", reflect.TypeOf((*MockStore)(nil).DeclinePaymentRequest), ctx, id)
+}
+
+// DeclinePaymentRequestTx mocks base method.
+func (m *MockStore) DeclinePaymentRequestTx(ctx context.Context, arg db.DeclinePaymentRequestTxParams) (db.DeclinePaymentRequestTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclinePaymentRequestTx", ctx, arg)
+	ret0, _ := ret[0].(db.DeclinePaymentRequestTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeclinePaymentRequestTx indicates an expected call of DeclinePaymentRequestTx.
+func (mr *MockStoreMockRecorder) DeclinePaymentRequestTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclinePaymentRequestTx", reflect.TypeOf((*MockStore)(nil).DeclinePaymentRequestTx), ctx, arg)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockStore) DeleteAccount(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockStoreMockRecorder) DeleteAccount(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), ctx, id)
+}
+
+// DeleteAccountMember mocks base method.
+func (m *MockStore) DeleteAccountMember(ctx context.Context, arg db.DeleteAccountMemberParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccountMember", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccountMember indicates an expected call of DeleteAccountMember.
+func (mr *MockStoreMockRecorder) DeleteAccountMember(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountMember", reflect.TypeOf((*MockStore)(nil).DeleteAccountMember), ctx, arg)
+}
+
+// DeleteCompletedTasks mocks base method.
+func (m *MockStore) DeleteCompletedTasks(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCompletedTasks", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCompletedTasks indicates an expected call of DeleteCompletedTasks.
+func (mr *MockStoreMockRecorder) DeleteCompletedTasks(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCompletedTasks", reflect.TypeOf((*MockStore)(nil).DeleteCompletedTasks), ctx, before)
+}
+
+// DeleteExpiredSessions mocks base method.
+func (m *MockStore) DeleteExpiredSessions(ctx context.Context, expiresAt time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredSessions", ctx, expiresAt)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredSessions indicates an expected call of DeleteExpiredSessions.
+func (mr *MockStoreMockRecorder) DeleteExpiredSessions(ctx, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredSessions", reflect.TypeOf((*MockStore)(nil).DeleteExpiredSessions), ctx, expiresAt)
+}
+
+// DeleteExpiredVerifyEmails mocks base method.
+func (m *MockStore) DeleteExpiredVerifyEmails(ctx context.Context, expiredAt time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredVerifyEmails", ctx, expiredAt)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredVerifyEmails indicates an expected call of DeleteExpiredVerifyEmails.
+func (mr *MockStoreMockRecorder) DeleteExpiredVerifyEmails(ctx, expiredAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredVerifyEmails", reflect.TypeOf((*MockStore)(nil).DeleteExpiredVerifyEmails), ctx, expiredAt)
+}
+
+// DeleteFailedTask mocks base method.
+func (m *MockStore) DeleteFailedTask(ctx context.Context, taskID string) (db.TaskQueue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFailedTask", ctx, taskID)
+	ret0, _ := ret[0].(db.TaskQueue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFailedTask indicates an expected call of DeleteFailedTask.
+func (mr *MockStoreMockRecorder) DeleteFailedTask(ctx, taskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFailedTask", reflect.TypeOf((*MockStore)(nil).DeleteFailedTask), ctx, taskID)
+}
+
+// DeleteFeeSchedule mocks base method.
+func (m *MockStore) DeleteFeeSchedule(ctx context.Context, id int64) (db.FeeSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFeeSchedule", ctx, id)
+	ret0, _ := ret[0].(db.FeeSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFeeSchedule indicates an expected call of DeleteFeeSchedule.
+func (mr *MockStoreMockRecorder) DeleteFeeSchedule(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFeeSchedule", reflect.TypeOf((*MockStore)(nil).DeleteFeeSchedule), ctx, id)
+}
+
+// DeletePayee mocks base method.
+func (m *MockStore) DeletePayee(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePayee", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePayee indicates an expected call of DeletePayee.
+func (mr *MockStoreMockRecorder) DeletePayee(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePayee", reflect.TypeOf((*MockStore)(nil).DeletePayee), ctx, id)
+}
+
+// DeletePeriodicJobRuns mocks base method.
+func (m *MockStore) DeletePeriodicJobRuns(ctx context.Context, scheduledAt time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePeriodicJobRuns", ctx, scheduledAt)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePeriodicJobRuns indicates an expected call of DeletePeriodicJobRuns.
+func (mr *MockStoreMockRecorder) DeletePeriodicJobRuns(ctx, scheduledAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePeriodicJobRuns", reflect.TypeOf((*MockStore)(nil).DeletePeriodicJobRuns), ctx, scheduledAt)
+}
+
+// DeletePublishedOutboxMessages mocks base method.
+func (m *MockStore) DeletePublishedOutboxMessages(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePublishedOutboxMessages", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePublishedOutboxMessages indicates an expected call of DeletePublishedOutboxMessages.
+func (mr *MockStoreMockRecorder) DeletePublishedOutboxMessages(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePublishedOutboxMessages", reflect.TypeOf((*MockStore)(nil).DeletePublishedOutboxMessages), ctx, before)
+}
+
+// DeleteTaskExecutions mocks base method.
+func (m *MockStore) DeleteTaskExecutions(ctx context.Context, createdAt time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaskExecutions", ctx, createdAt)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTaskExecutions indicates an expected call of DeleteTaskExecutions.
+func (mr *MockStoreMockRecorder) DeleteTaskExecutions(ctx, createdAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaskExecutions", reflect.TypeOf((*MockStore)(nil).DeleteTaskExecutions), ctx, createdAt)
+}
+
+// DeleteTaskFailures mocks base method.
+func (m *MockStore) DeleteTaskFailures(ctx context.Context, createdAt time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaskFailures", ctx, createdAt)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTaskFailures indicates an expected call of DeleteTaskFailures.
+func (mr *MockStoreMockRecorder) DeleteTaskFailures(ctx, createdAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaskFailures", reflect.TypeOf((*MockStore)(nil).DeleteTaskFailures), ctx, createdAt)
+}
+
+// DeleteTransfer mocks base method.
+func (m *MockStore) DeleteTransfer(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTransfer", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTransfer indicates an expected call of DeleteTransfer.
+func (mr *MockStoreMockRecorder) DeleteTransfer(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransfer", reflect.TypeOf((*MockStore)(nil).DeleteTransfer), ctx, id)
+}
+
+// DeleteWebhookSubscription mocks base method.
+func (m *MockStore) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookSubscription", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookSubscription indicates an expected call of DeleteWebhookSubscription.
+func (mr *MockStoreMockRecorder) DeleteWebhookSubscription(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookSubscription", reflect.TypeOf((*MockStore)(nil).DeleteWebhookSubscription), ctx, id)
+}
+
+// EnqueueTask mocks base method.
+func (m *MockStore) EnqueueTask(ctx context.Context, arg db.EnqueueTaskParams) (db.TaskQueue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueTask", ctx, arg)
+	ret0, _ := ret[0].(db.TaskQueue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueTask indicates an expected call of EnqueueTask.
+func (mr *MockStoreMockRecorder) EnqueueTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueTask", reflect.TypeOf((*MockStore)(nil).EnqueueTask), ctx, arg)
+}
+
+// EnsureSystemAccount mocks base method.
+func (m *MockStore) EnsureSystemAccount(ctx context.Context, arg db.EnsureSystemAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureSystemAccount", ctx, arg)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureSystemAccount indicates an expected call of EnsureSystemAccount.
+func (mr *MockStoreMockRecorder) EnsureSystemAccount(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureSystemAccount", reflect.TypeOf((*MockStore)(nil).EnsureSystemAccount), ctx, arg)
+}
+
+// ExpirePaymentRequests mocks base method.
+func (m *MockStore) ExpirePaymentRequests(ctx context.Context) ([]db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePaymentRequests", ctx)
+	ret0, _ := ret[0].([]db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePaymentRequests indicates an expected call of ExpirePaymentRequests.
+func (mr *MockStoreMockRecorder) ExpirePaymentRequests(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePaymentRequests", reflect.TypeOf((*MockStore)(nil).ExpirePaymentRequests), ctx)
+}
+
+// ExpireTransferTx mocks base method.
+func (m *MockStore) ExpireTransferTx(ctx context.Context, transferID int64) (db.CloseTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireTransferTx", ctx, transferID)
+	ret0, _ := ret[0].(db.CloseTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireTransferTx indicates an expected call of ExpireTransferTx.
+func (mr *MockStoreMockRecorder) ExpireTransferTx(ctx, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireTransferTx", reflect.TypeOf((*MockStore)(nil).ExpireTransferTx), ctx, transferID)
+}
+
+// FreezeAccountTx mocks base method.
+func (m *MockStore) FreezeAccountTx(ctx context.Context, arg db.FreezeAccountTxParams) (db.FreezeAccountTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreezeAccountTx", ctx, arg)
+	ret0, _ := ret[0].(db.FreezeAccountTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreezeAccountTx indicates an expected call of FreezeAccountTx.
+func (mr *MockStoreMockRecorder) FreezeAccountTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeAccountTx", reflect.TypeOf((*MockStore)(nil).FreezeAccountTx), ctx, arg)
+}
+
+// GetACHFile mocks base method.
+func (m *MockStore) GetACHFile(ctx context.Context, id int64) (db.AchFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetACHFile", ctx, id)
+	ret0, _ := ret[0].(db.AchFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetACHFile indicates an expected call of GetACHFile.
+func (mr *MockStoreMockRecorder) GetACHFile(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetACHFile", reflect.TypeOf((*MockStore)(nil).GetACHFile), ctx, id)
+}
+
+// GetAccount mocks base method.
+func (m *MockStore) GetAccount(ctx context.Context, id int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", ctx, id)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockStoreMockRecorder) GetAccount(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), ctx, id)
+}
+
+// GetAccountActivityCursor mocks base method.
+func (m *MockStore) GetAccountActivityCursor(ctx context.Context, id int64) (db.GetAccountActivityCursorRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountActivityCursor", ctx, id)
+	ret0, _ := ret[0].(db.GetAccountActivityCursorRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountActivityCursor indicates an expected call of GetAccountActivityCursor.
+func (mr *MockStoreMockRecorder) GetAccountActivityCursor(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountActivityCursor", reflect.TypeOf((*MockStore)(nil).GetAccountActivityCursor), ctx, id)
+}
+
+// GetAccountByNumber mocks base method.
+func (m *MockStore) GetAccountByNumber(ctx context.Context, accountNumber string) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByNumber", ctx, accountNumber)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByNumber indicates an expected call of GetAccountByNumber.
+func (mr *MockStoreMockRecorder) GetAccountByNumber(ctx, accountNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByNumber", reflect.TypeOf((*MockStore)(nil).GetAccountByNumber), ctx, accountNumber)
+}
+
+// GetAccountForUpdate mocks base method.
+func (m *MockStore) GetAccountForUpdate(ctx context.Context, id int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountForUpdate", ctx, id)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountForUpdate indicates an expected call of GetAccountForUpdate.
+func (mr *MockStoreMockRecorder) GetAccountForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), ctx, id)
+}
+
+// GetAccountMember mocks base method.
+func (m *MockStore) GetAccountMember(ctx context.Context, arg db.GetAccountMemberParams) (db.AccountMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountMember", ctx, arg)
+	ret0, _ := ret[0].(db.AccountMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountMember indicates an expected call of GetAccountMember.
+func (mr *MockStoreMockRecorder) GetAccountMember(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountMember", reflect.TypeOf((*MockStore)(nil).GetAccountMember), ctx, arg)
+}
+
+// GetAccountProduct mocks base method.
+func (m *MockStore) GetAccountProduct(ctx context.Context, code string) (db.AccountProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountProduct", ctx, code)
+	ret0, _ := ret[0].(db.AccountProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountProduct indicates an expected call of GetAccountProduct.
+func (mr *MockStoreMockRecorder) GetAccountProduct(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountProduct", reflect.TypeOf((*MockStore)(nil).GetAccountProduct), ctx, code)
+}
+
+// GetActiveVerifyEmail mocks base method.
+func (m *MockStore) GetActiveVerifyEmail(ctx context.Context, arg db.GetActiveVerifyEmailParams) (db.VerifyEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveVerifyEmail", ctx, arg)
+	ret0, _ := ret[0].(db.VerifyEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveVerifyEmail indicates an expected call of GetActiveVerifyEmail.
+func (mr *MockStoreMockRecorder) GetActiveVerifyEmail(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveVerifyEmail", reflect.TypeOf((*MockStore)(nil).GetActiveVerifyEmail), ctx, arg)
+}
+
+// GetEntry mocks base method.
+func (m *MockStore) GetEntry(ctx context.Context, id int64) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntry", ctx, id)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockStoreMockRecorder) GetEntry(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), ctx, id)
+}
+
+// GetExternalPayment mocks base method.
+func (m *MockStore) GetExternalPayment(ctx context.Context, transferID int64) (db.ExternalPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalPayment", ctx, transferID)
+	ret0, _ := ret[0].(db.ExternalPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExternalPayment indicates an expected call of GetExternalPayment.
+func (mr *MockStoreMockRecorder) GetExternalPayment(ctx, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalPayment", reflect.TypeOf((*MockStore)(nil).GetExternalPayment), ctx, transferID)
+}
+
+// GetExternalPaymentByReference mocks base method.
+func (m *MockStore) GetExternalPaymentByReference(ctx context.Context, arg db.GetExternalPaymentByReferenceParams) (db.ExternalPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalPaymentByReference", ctx, arg)
+	ret0, _ := ret[0].(db.ExternalPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExternalPaymentByReference indicates an expected call of GetExternalPaymentByReference.
+func (mr *MockStoreMockRecorder) GetExternalPaymentByReference(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalPaymentByReference", reflect.TypeOf((*MockStore)(nil).GetExternalPaymentByReference), ctx, arg)
+}
+
+// GetLatestInterestPosting mocks base method.
+func (m *MockStore) GetLatestInterestPosting(ctx context.Context, arg db.GetLatestInterestPostingParams) (db.InterestPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestInterestPosting", ctx, arg)
+	ret0, _ := ret[0].(db.InterestPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestInterestPosting indicates an expected call of GetLatestInterestPosting.
+func (mr *MockStoreMockRecorder) GetLatestInterestPosting(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestInterestPosting", reflect.TypeOf((*MockStore)(nil).GetLatestInterestPosting), ctx, arg)
+}
+
+// GetLatestOverdraftCharge mocks base method.
+func (m *MockStore) GetLatestOverdraftCharge(ctx context.Context, accountID int64) (db.OverdraftCharge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestOverdraftCharge", ctx, accountID)
+	ret0, _ := ret[0].(db.OverdraftCharge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestOverdraftCharge indicates an expected call of GetLatestOverdraftCharge.
+func (mr *MockStoreMockRecorder) GetLatestOverdraftCharge(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestOverdraftCharge", reflect.TypeOf((*MockStore)(nil).GetLatestOverdraftCharge), ctx, accountID)
+}
+
+// GetOutboxMessage mocks base method.
+func (m *MockStore) GetOutboxMessage(ctx context.Context, id int64) (db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboxMessage", ctx, id)
+	ret0, _ := ret[0].(db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutboxMessage indicates an expected call of GetOutboxMessage.
+func (mr *MockStoreMockRecorder) GetOutboxMessage(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboxMessage", reflect.TypeOf((*MockStore)(nil).GetOutboxMessage), ctx, id)
+}
+
+// GetPayee mocks base method.
+func (m *MockStore) GetPayee(ctx context.Context, id int64) (db.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayee", ctx, id)
+	ret0, _ := ret[0].(db.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayee indicates an expected call of GetPayee.
+func (mr *MockStoreMockRecorder) GetPayee(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayee", reflect.TypeOf((*MockStore)(nil).GetPayee), ctx, id)
+}
+
+// GetPaymentImport mocks base method.
+func (m *MockStore) GetPaymentImport(ctx context.Context, arg db.GetPaymentImportParams) (db.PaymentImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentImport", ctx, arg)
+	ret0, _ := ret[0].(db.PaymentImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentImport indicates an expected call of GetPaymentImport.
+func (mr *MockStoreMockRecorder) GetPaymentImport(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentImport", reflect.TypeOf((*MockStore)(nil).GetPaymentImport), ctx, arg)
+}
+
+// GetPaymentRequest mocks base method.
+func (m *MockStore) GetPaymentRequest(ctx context.Context, id int64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequest", ctx, id)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequest indicates an expected call of GetPaymentRequest.
+func (mr *MockStoreMockRecorder) GetPaymentRequest(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequest", reflect.TypeOf((*MockStore)(nil).GetPaymentRequest), ctx, id)
+}
+
+// GetPaymentRequestForUpdate mocks base method.
+func (m *MockStore) GetPaymentRequestForUpdate(ctx context.Context, id int64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequestForUpdate", ctx, id)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequestForUpdate indicates an expected call of GetPaymentRequestForUpdate.
+func (mr *MockStoreMockRecorder) GetPaymentRequestForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequestForUpdate", reflect.TypeOf((*MockStore)(nil).GetPaymentRequestForUpdate), ctx, id)
+}
+
+// GetQueuedTask mocks base method.
+func (m *MockStore) GetQueuedTask(ctx context.Context, taskID string) (db.TaskQueue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQueuedTask", ctx, taskID)
+	ret0, _ := ret[0].(db.TaskQueue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueuedTask indicates an expected call of GetQueuedTask.
+func (mr *MockStoreMockRecorder) GetQueuedTask(ctx, taskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueuedTask", reflect.TypeOf((*MockStore)(nil).GetQueuedTask), ctx, taskID)
+}
+
+// GetRecipientAccount mocks base method.
+func (m *MockStore) GetRecipientAccount(ctx context.Context, arg db.GetRecipientAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipientAccount", ctx, arg)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipientAccount indicates an expected call of GetRecipientAccount.
+func (mr *MockStoreMockRecorder) GetRecipientAccount(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipientAccount", reflect.TypeOf((*MockStore)(nil).GetRecipientAccount), ctx, arg)
+}
+
+// GetSession mocks base method.
+func (m *MockStore) GetSession(ctx context.Context, id uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", ctx, id)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStoreMockRecorder) GetSession(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), ctx, id)
+}
+
+// GetTaskExecution mocks base method.
+func (m *MockStore) GetTaskExecution(ctx context.Context, arg db.GetTaskExecutionParams) (db.TaskExecution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskExecution", ctx, arg)
+	ret0, _ := ret[0].(db.TaskExecution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskExecution indicates an expected call of GetTaskExecution.
+func (mr *MockStoreMockRecorder) GetTaskExecution(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskExecution", reflect.TypeOf((*MockStore)(nil).GetTaskExecution), ctx, arg)
+}
+
+// GetTransfer mocks base method.
+func (m *MockStore) GetTransfer(ctx context.Context, id int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", ctx, id)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockStoreMockRecorder) GetTransfer(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), ctx, id)
+}
+
+// GetTransferApproval mocks base method.
+func (m *MockStore) GetTransferApproval(ctx context.Context, transferID int64) (db.TransferApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferApproval", ctx, transferID)
+	ret0, _ := ret[0].(db.TransferApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferApproval indicates an expected call of GetTransferApproval.
+func (mr *MockStoreMockRecorder) GetTransferApproval(ctx, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferApproval", reflect.TypeOf((*MockStore)(nil).GetTransferApproval), ctx, transferID)
+}
+
+// GetTransferFeeSchedule mocks base method.
+func (m *MockStore) GetTransferFeeSchedule(ctx context.Context, arg db.GetTransferFeeScheduleParams) (db.FeeSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferFeeSchedule", ctx, arg)
+	ret0, _ := ret[0].(db.FeeSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferFeeSchedule indicates an expected call of GetTransferFeeSchedule.
+func (mr *MockStoreMockRecorder) GetTransferFeeSchedule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferFeeSchedule", reflect.TypeOf((*MockStore)(nil).GetTransferFeeSchedule), ctx, arg)
+}
+
+// GetTransferForUpdate mocks base method.
+func (m *MockStore) GetTransferForUpdate(ctx context.Context, id int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferForUpdate", ctx, id)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferForUpdate indicates an expected call of GetTransferForUpdate.
+func (mr *MockStoreMockRecorder) GetTransferForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferForUpdate", reflect.TypeOf((*MockStore)(nil).GetTransferForUpdate), ctx, id)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(ctx context.Context, username string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, username)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), ctx, username)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockStore) GetWebhookDelivery(ctx context.Context, id int64) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", ctx, id)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockStoreMockRecorder) GetWebhookDelivery(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockStore)(nil).GetWebhookDelivery), ctx, id)
+}
+
+// GetWebhookSubscription mocks base method.
+func (m *MockStore) GetWebhookSubscription(ctx context.Context, id int64) (db.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookSubscription", ctx, id)
+	ret0, _ := ret[0].(db.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookSubscription indicates an expected call of GetWebhookSubscription.
+func (mr *MockStoreMockRecorder) GetWebhookSubscription(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookSubscription", reflect.TypeOf((*MockStore)(nil).GetWebhookSubscription), ctx, id)
+}
+
+// InitiateExternalTransferTx mocks base method.
+func (m *MockStore) InitiateExternalTransferTx(ctx context.Context, arg db.InitiateExternalTransferTxParams) (db.InitiateExternalTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InitiateExternalTransferTx", ctx, arg)
+	ret0, _ := ret[0].(db.InitiateExternalTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InitiateExternalTransferTx indicates an expected call of InitiateExternalTransferTx.
+func (mr *MockStoreMockRecorder) InitiateExternalTransferTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitiateExternalTransferTx", reflect.TypeOf((*MockStore)(nil).InitiateExternalTransferTx), ctx, arg)
+}
+
+// InitiatePendingExternalTransferTx mocks base method.
+func (m *MockStore) InitiatePendingExternalTransferTx(ctx context.Context, arg db.InitiatePendingExternalTransferTxParams) (db.InitiatePendingExternalTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InitiatePendingExternalTransferTx", ctx, arg)
+	ret0, _ := ret[0].(db.InitiatePendingExternalTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InitiatePendingExternalTransferTx indicates an expected call of InitiatePendingExternalTransferTx.
+func (mr *MockStoreMockRecorder) InitiatePendingExternalTransferTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitiatePendingExternalTransferTx", reflect.TypeOf((*MockStore)(nil).InitiatePendingExternalTransferTx), ctx, arg)
+}
+
+// ListAccountActivity mocks base method.
+func (m *MockStore) ListAccountActivity(ctx context.Context, arg db.ListAccountActivityParams) ([]db.ListAccountActivityRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountActivity", ctx, arg)
+	ret0, _ := ret[0].([]db.ListAccountActivityRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountActivity indicates an expected call of ListAccountActivity.
+func (mr *MockStoreMockRecorder) ListAccountActivity(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountActivity", reflect.TypeOf((*MockStore)(nil).ListAccountActivity), ctx, arg)
+}
+
+// ListAccountFreezes mocks base method.
+func (m *MockStore) ListAccountFreezes(ctx context.Context, arg db.ListAccountFreezesParams) ([]db.AccountFreeze, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountFreezes", ctx, arg)
+	ret0, _ := ret[0].([]db.AccountFreeze)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountFreezes indicates an expected call of ListAccountFreezes.
+func (mr *MockStoreMockRecorder) ListAccountFreezes(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountFreezes", reflect.TypeOf((*MockStore)(nil).ListAccountFreezes), ctx, arg)
+}
+
+// ListAccountMembers mocks base method.
+func (m *MockStore) ListAccountMembers(ctx context.Context, accountID int64) ([]db.AccountMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountMembers", ctx, accountID)
+	ret0, _ := ret[0].([]db.AccountMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountMembers indicates an expected call of ListAccountMembers.
+func (mr *MockStoreMockRecorder) ListAccountMembers(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountMembers", reflect.TypeOf((*MockStore)(nil).ListAccountMembers), ctx, accountID)
+}
+
+// ListAccountProducts mocks base method.
+func (m *MockStore) ListAccountProducts(ctx context.Context) ([]db.AccountProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountProducts", ctx)
+	ret0, _ := ret[0].([]db.AccountProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountProducts indicates an expected call of ListAccountProducts.
+func (mr *MockStoreMockRecorder) ListAccountProducts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountProducts", reflect.TypeOf((*MockStore)(nil).ListAccountProducts), ctx)
+}
+
+// ListAccountTransfers mocks base method.
+func (m *MockStore) ListAccountTransfers(ctx context.Context, arg db.ListAccountTransfersParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountTransfers", ctx, arg)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountTransfers indicates an expected call of ListAccountTransfers.
+func (mr *MockStoreMockRecorder) ListAccountTransfers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountTransfers", reflect.TypeOf((*MockStore)(nil).ListAccountTransfers), ctx, arg)
+}
+
+// ListAccounts mocks base method.
+func (m *MockStore) ListAccounts(ctx context.Context, arg db.ListAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", ctx, arg)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockStoreMockRecorder) ListAccounts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), ctx, arg)
+}
+
+// ListAccountsWithUnpostedInterest mocks base method.
+func (m *MockStore) ListAccountsWithUnpostedInterest(ctx context.Context, periodEnd time.Time) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountsWithUnpostedInterest", ctx, periodEnd)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountsWithUnpostedInterest indicates an expected call of ListAccountsWithUnpostedInterest.
+func (mr *MockStoreMockRecorder) ListAccountsWithUnpostedInterest(ctx, periodEnd any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountsWithUnpostedInterest", reflect.TypeOf((*MockStore)(nil).ListAccountsWithUnpostedInterest), ctx, periodEnd)
+}
+
+// ListEntries mocks base method.
+func (m *MockStore) ListEntries(ctx context.Context, arg db.ListEntriesParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", ctx, arg)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockStoreMockRecorder) ListEntries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), ctx, arg)
+}
+
+// ListEntriesByAccountID mocks base method.
+func (m *MockStore) ListEntriesByAccountID(ctx context.Context, arg db.ListEntriesByAccountIDParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntriesByAccountID", ctx, arg)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntriesByAccountID indicates an expected call of ListEntriesByAccountID.
+func (mr *MockStoreMockRecorder) ListEntriesByAccountID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntriesByAccountID", reflect.TypeOf((*MockStore)(nil).ListEntriesByAccountID), ctx, arg)
+}
+
+// ListExpiredTransferApprovals mocks base method.
+func (m *MockStore) ListExpiredTransferApprovals(ctx context.Context) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredTransferApprovals", ctx)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredTransferApprovals indicates an expected call of ListExpiredTransferApprovals.
+func (mr *MockStoreMockRecorder) ListExpiredTransferApprovals(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredTransferApprovals", reflect.TypeOf((*MockStore)(nil).ListExpiredTransferApprovals), ctx)
+}
+
+// ListFailedTasks mocks base method.
+func (m *MockStore) ListFailedTasks(ctx context.Context, arg db.ListFailedTasksParams) ([]db.TaskQueue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFailedTasks", ctx, arg)
+	ret0, _ := ret[0].([]db.TaskQueue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFailedTasks indicates an expected call of ListFailedTasks.
+func (mr *MockStoreMockRecorder) ListFailedTasks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFailedTasks", reflect.TypeOf((*MockStore)(nil).ListFailedTasks), ctx, arg)
+}
+
+// ListFeeSchedules mocks base method.
+func (m *MockStore) ListFeeSchedules(ctx context.Context) ([]db.FeeSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFeeSchedules", ctx)
+	ret0, _ := ret[0].([]db.FeeSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFeeSchedules indicates an expected call of ListFeeSchedules.
+func (mr *MockStoreMockRecorder) ListFeeSchedules(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeeSchedules", reflect.TypeOf((*MockStore)(nil).ListFeeSchedules), ctx)
+}
+
+// ListIncomingPaymentRequests mocks base method.
+func (m *MockStore) ListIncomingPaymentRequests(ctx context.Context, arg db.ListIncomingPaymentRequestsParams) ([]db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncomingPaymentRequests", ctx, arg)
+	ret0, _ := ret[0].([]db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncomingPaymentRequests indicates an expected call of ListIncomingPaymentRequests.
+func (mr *MockStoreMockRecorder) ListIncomingPaymentRequests(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncomingPaymentRequests", reflect.TypeOf((*MockStore)(nil).ListIncomingPaymentRequests), ctx, arg)
+}
+
+// ListInitiatedExternalPayments mocks base method.
+func (m *MockStore) ListInitiatedExternalPayments(ctx context.Context, network string) ([]db.ListInitiatedExternalPaymentsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInitiatedExternalPayments", ctx, network)
+	ret0, _ := ret[0].([]db.ListInitiatedExternalPaymentsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInitiatedExternalPayments indicates an expected call of ListInitiatedExternalPayments.
+func (mr *MockStoreMockRecorder) ListInitiatedExternalPayments(ctx, network any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInitiatedExternalPayments", reflect.TypeOf((*MockStore)(nil).ListInitiatedExternalPayments), ctx, network)
+}
+
+// ListInterestBearingAccounts mocks base method.
+func (m *MockStore) ListInterestBearingAccounts(ctx context.Context, endOfDay time.Time) ([]db.ListInterestBearingAccountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInterestBearingAccounts", ctx, endOfDay)
+	ret0, _ := ret[0].([]db.ListInterestBearingAccountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInterestBearingAccounts indicates an expected call of ListInterestBearingAccounts.
+func (mr *MockStoreMockRecorder) ListInterestBearingAccounts(ctx, endOfDay any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInterestBearingAccounts", reflect.TypeOf((*MockStore)(nil).ListInterestBearingAccounts), ctx, endOfDay)
+}
+
+// ListOutgoingPaymentRequests mocks base method.
+func (m *MockStore) ListOutgoingPaymentRequests(ctx context.Context, arg db.ListOutgoingPaymentRequestsParams) ([]db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutgoingPaymentRequests", ctx, arg)
+	ret0, _ := ret[0].([]db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutgoingPaymentRequests indicates an expected call of ListOutgoingPaymentRequests.
+func (mr *MockStoreMockRecorder) ListOutgoingPaymentRequests(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutgoingPaymentRequests", reflect.TypeOf((*MockStore)(nil).ListOutgoingPaymentRequests), ctx, arg)
+}
+
+// ListOverdrawnAccounts mocks base method.
+func (m *MockStore) ListOverdrawnAccounts(ctx context.Context, endOfDay time.Time) ([]db.ListOverdrawnAccountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverdrawnAccounts", ctx, endOfDay)
+	ret0, _ := ret[0].([]db.ListOverdrawnAccountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverdrawnAccounts indicates an expected call of ListOverdrawnAccounts.
+func (mr *MockStoreMockRecorder) ListOverdrawnAccounts(ctx, endOfDay any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverdrawnAccounts", reflect.TypeOf((*MockStore)(nil).ListOverdrawnAccounts), ctx, endOfDay)
+}
+
+// ListPayees mocks base method.
+func (m *MockStore) ListPayees(ctx context.Context, arg db.ListPayeesParams) ([]db.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPayees", ctx, arg)
+	ret0, _ := ret[0].([]db.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPayees indicates an expected call of ListPayees.
+func (mr *MockStoreMockRecorder) ListPayees(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayees", reflect.TypeOf((*MockStore)(nil).ListPayees), ctx, arg)
+}
+
+// ListPendingTransferApprovals mocks base method.
+func (m *MockStore) ListPendingTransferApprovals(ctx context.Context, arg db.ListPendingTransferApprovalsParams) ([]db.ListPendingTransferApprovalsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingTransferApprovals", ctx, arg)
+	ret0, _ := ret[0].([]db.ListPendingTransferApprovalsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingTransferApprovals indicates an expected call of ListPendingTransferApprovals.
+func (mr *MockStoreMockRecorder) ListPendingTransferApprovals(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingTransferApprovals", reflect.TypeOf((*MockStore)(nil).ListPendingTransferApprovals), ctx, arg)
+}
+
+// ListSettleableExternalPayments mocks base method.
+func (m *MockStore) ListSettleableExternalPayments(ctx context.Context, arg db.ListSettleableExternalPaymentsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSettleableExternalPayments", ctx, arg)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSettleableExternalPayments indicates an expected call of ListSettleableExternalPayments.
+func (mr *MockStoreMockRecorder) ListSettleableExternalPayments(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSettleableExternalPayments", reflect.TypeOf((*MockStore)(nil).ListSettleableExternalPayments), ctx, arg)
+}
+
+// ListStatementEntries mocks base method.
+func (m *MockStore) ListStatementEntries(ctx context.Context, arg db.ListStatementEntriesParams) ([]db.ListStatementEntriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStatementEntries", ctx, arg)
+	ret0, _ := ret[0].([]db.ListStatementEntriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatementEntries indicates an expected call of ListStatementEntries.
+func (mr *MockStoreMockRecorder) ListStatementEntries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatementEntries", reflect.TypeOf((*MockStore)(nil).ListStatementEntries), ctx, arg)
+}
+
+// ListTaskFailures mocks base method.
+func (m *MockStore) ListTaskFailures(ctx context.Context, arg db.ListTaskFailuresParams) ([]db.TaskFailure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskFailures", ctx, arg)
+	ret0, _ := ret[0].([]db.TaskFailure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskFailures indicates an expected call of ListTaskFailures.
+func (mr *MockStoreMockRecorder) ListTaskFailures(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskFailures", reflect.TypeOf((*MockStore)(nil).ListTaskFailures), ctx, arg)
+}
+
+// ListTransfers mocks base method.
+func (m *MockStore) ListTransfers(ctx context.Context, arg db.ListTransfersParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfers", ctx, arg)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfers indicates an expected call of ListTransfers.
+func (mr *MockStoreMockRecorder) ListTransfers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), ctx, arg)
+}
+
+// ListTransfersByFromAccount mocks base method.
+func (m *MockStore) ListTransfersByFromAccount(ctx context.Context, arg db.ListTransfersByFromAccountParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfersByFromAccount", ctx, arg)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfersByFromAccount indicates an expected call of ListTransfersByFromAccount.
+func (mr *MockStoreMockRecorder) ListTransfersByFromAccount(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfersByFromAccount", reflect.TypeOf((*MockStore)(nil).ListTransfersByFromAccount), ctx, arg)
+}
+
+// ListTransfersByToAccount mocks base method.
+func (m *MockStore) ListTransfersByToAccount(ctx context.Context, arg db.ListTransfersByToAccountParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfersByToAccount", ctx, arg)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfersByToAccount indicates an expected call of ListTransfersByToAccount.
+func (mr *MockStoreMockRecorder) ListTransfersByToAccount(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfersByToAccount", reflect.TypeOf((*MockStore)(nil).ListTransfersByToAccount), ctx, arg)
+}
+
+// ListUnbalancedAccounts mocks base method.
+func (m *MockStore) ListUnbalancedAccounts(ctx context.Context) ([]db.ListUnbalancedAccountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnbalancedAccounts", ctx)
+	ret0, _ := ret[0].([]db.ListUnbalancedAccountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnbalancedAccounts indicates an expected call of ListUnbalancedAccounts.
+func (mr *MockStoreMockRecorder) ListUnbalancedAccounts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnbalancedAccounts", reflect.TypeOf((*MockStore)(nil).ListUnbalancedAccounts), ctx)
+}
+
+// ListUnpublishedOutboxMessages mocks base method.
+func (m *MockStore) ListUnpublishedOutboxMessages(ctx context.Context, limit int32) ([]db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnpublishedOutboxMessages", ctx, limit)
+	ret0, _ := ret[0].([]db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnpublishedOutboxMessages indicates an expected call of ListUnpublishedOutboxMessages.
+func (mr *MockStoreMockRecorder) ListUnpublishedOutboxMessages(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnpublishedOutboxMessages", reflect.TypeOf((*MockStore)(nil).ListUnpublishedOutboxMessages), ctx, limit)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockStore) ListWebhookDeliveries(ctx context.Context, arg db.ListWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ListWebhookDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ListWebhookDeliveries), ctx, arg)
+}
+
+// ListWebhookSubscriptions mocks base method.
+func (m *MockStore) ListWebhookSubscriptions(ctx context.Context, owner string) ([]db.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookSubscriptions", ctx, owner)
+	ret0, _ := ret[0].([]db.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookSubscriptions indicates an expected call of ListWebhookSubscriptions.
+func (mr *MockStoreMockRecorder) ListWebhookSubscriptions(ctx, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookSubscriptions", reflect.TypeOf((*MockStore)(nil).ListWebhookSubscriptions), ctx, owner)
+}
+
+// ListWebhookSubscriptionsForEvent mocks base method.
+func (m *MockStore) ListWebhookSubscriptionsForEvent(ctx context.Context, arg db.ListWebhookSubscriptionsForEventParams) ([]db.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookSubscriptionsForEvent", ctx, arg)
+	ret0, _ := ret[0].([]db.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookSubscriptionsForEvent indicates an expected call of ListWebhookSubscriptionsForEvent.
+func (mr *MockStoreMockRecorder) ListWebhookSubscriptionsForEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookSubscriptionsForEvent", reflect.TypeOf((*MockStore)(nil).ListWebhookSubscriptionsForEvent), ctx, arg)
+}
+
+// MarkExternalPaymentReversed mocks base method.
+func (m *MockStore) MarkExternalPaymentReversed(ctx context.Context, arg db.MarkExternalPaymentReversedParams) (db.ExternalPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkExternalPaymentReversed", ctx, arg)
+	ret0, _ := ret[0].(db.ExternalPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkExternalPaymentReversed indicates an expected call of MarkExternalPaymentReversed.
+func (mr *MockStoreMockRecorder) MarkExternalPaymentReversed(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkExternalPaymentReversed", reflect.TypeOf((*MockStore)(nil).MarkExternalPaymentReversed), ctx, arg)
+}
+
+// MarkExternalPaymentSettled mocks base method.
+func (m *MockStore) MarkExternalPaymentSettled(ctx context.Context, arg db.MarkExternalPaymentSettledParams) (db.ExternalPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkExternalPaymentSettled", ctx, arg)
+	ret0, _ := ret[0].(db.ExternalPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkExternalPaymentSettled indicates an expected call of MarkExternalPaymentSettled.
+func (mr *MockStoreMockRecorder) MarkExternalPaymentSettled(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkExternalPaymentSettled", reflect.TypeOf((*MockStore)(nil).MarkExternalPaymentSettled), ctx, arg)
+}
+
+// MarkExternalPaymentSubmitted mocks base method.
+func (m *MockStore) MarkExternalPaymentSubmitted(ctx context.Context, arg db.MarkExternalPaymentSubmittedParams) (db.ExternalPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkExternalPaymentSubmitted", ctx, arg)
+	ret0, _ := ret[0].(db.ExternalPayment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// AddAccountBalance indicates an expected call of AddAccountBalance.
-func (mr *MockStoreMockRecorder) AddAccountBalance(ctx, arg any) *gomock.Call {
+// MarkExternalPaymentSubmitted indicates an expected call of MarkExternalPaymentSubmitted.
+func (mr *MockStoreMockRecorder) MarkExternalPaymentSubmitted(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalance", reflect.TypeOf((*MockStore)(nil).AddAccountBalance), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkExternalPaymentSubmitted", reflect.TypeOf((*MockStore)(nil).MarkExternalPaymentSubmitted), ctx, arg)
 }
 
-// CreateAccount mocks base method.
-func (m *MockStore) CreateAccount(ctx context.Context, arg db.CreateAccountParams) (db.Account, error) {
+// MarkInterestAccrualsPosted mocks base method.
+func (m *MockStore) MarkInterestAccrualsPosted(ctx context.Context, arg db.MarkInterestAccrualsPostedParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAccount", ctx, arg)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "MarkInterestAccrualsPosted", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkInterestAccrualsPosted indicates an expected call of MarkInterestAccrualsPosted.
+func (mr *MockStoreMockRecorder) MarkInterestAccrualsPosted(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkInterestAccrualsPosted", reflect.TypeOf((*MockStore)(nil).MarkInterestAccrualsPosted), ctx, arg)
+}
+
+// MarkOutboxMessagePublished mocks base method.
+func (m *MockStore) MarkOutboxMessagePublished(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxMessagePublished", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxMessagePublished indicates an expected call of MarkOutboxMessagePublished.
+func (mr *MockStoreMockRecorder) MarkOutboxMessagePublished(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxMessagePublished", reflect.TypeOf((*MockStore)(nil).MarkOutboxMessagePublished), ctx, id)
+}
+
+// MarkPaymentRequestPaid mocks base method.
+func (m *MockStore) MarkPaymentRequestPaid(ctx context.Context, arg db.MarkPaymentRequestPaidParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPaymentRequestPaid", ctx, arg)
+	ret0, _ := ret[0].(db.PaymentRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateAccount indicates an expected call of CreateAccount.
-func (mr *MockStoreMockRecorder) CreateAccount(ctx, arg any) *gomock.Call {
+// MarkPaymentRequestPaid indicates an expected call of MarkPaymentRequestPaid.
+func (mr *MockStoreMockRecorder) MarkPaymentRequestPaid(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPaymentRequestPaid", reflect.TypeOf((*MockStore)(nil).MarkPaymentRequestPaid), ctx, arg)
 }
 
-// CreateEntry mocks base method.
-func (m *MockStore) CreateEntry(ctx context.Context, arg db.CreateEntryParams) (db.Entry, error) {
+// MarkPaymentRequestPendingApproval mocks base method.
+func (m *MockStore) MarkPaymentRequestPendingApproval(ctx context.Context, arg db.MarkPaymentRequestPendingApprovalParams) (db.PaymentRequest, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEntry", ctx, arg)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "MarkPaymentRequestPendingApproval", ctx, arg)
+	ret0, _ := ret[0].(db.PaymentRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateEntry indicates an expected call of CreateEntry.
-func (mr *MockStoreMockRecorder) CreateEntry(ctx, arg any) *gomock.Call {
+// MarkPaymentRequestPendingApproval indicates an expected call of MarkPaymentRequestPendingApproval.
+func (mr *MockStoreMockRecorder) MarkPaymentRequestPendingApproval(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPaymentRequestPendingApproval", reflect.TypeOf((*MockStore)(nil).MarkPaymentRequestPendingApproval), ctx, arg)
 }
 
-// CreateSession mocks base method.
-func (m *MockStore) CreateSession(ctx context.Context, arg db.CreateSessionParams) (db.Session, error) {
+// NextACHTraceSequence mocks base method.
+func (m *MockStore) NextACHTraceSequence(ctx context.Context) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", ctx, arg)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "NextACHTraceSequence", ctx)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateSession indicates an expected call of CreateSession.
-func (mr *MockStoreMockRecorder) CreateSession(ctx, arg any) *gomock.Call {
+// NextACHTraceSequence indicates an expected call of NextACHTraceSequence.
+func (mr *MockStoreMockRecorder) NextACHTraceSequence(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextACHTraceSequence", reflect.TypeOf((*MockStore)(nil).NextACHTraceSequence), ctx)
 }
 
-// CreateTransfer mocks base method.
-func (m *MockStore) CreateTransfer(ctx context.Context, arg db.CreateTransferParams) (db.Transfer, error) {
+// PayPaymentRequestPendingTx mocks base method.
+func (m *MockStore) PayPaymentRequestPendingTx(ctx context.Context, arg db.PayPaymentRequestPendingTxParams) (db.PayPaymentRequestPendingTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransfer", ctx, arg)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "PayPaymentRequestPendingTx", ctx, arg)
+	ret0, _ := ret[0].(db.PayPaymentRequestPendingTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTransfer indicates an expected call of CreateTransfer.
-func (mr *MockStoreMockRecorder) CreateTransfer(ctx, arg any) *gomock.Call {
+// PayPaymentRequestPendingTx indicates an expected call of PayPaymentRequestPendingTx.
+func (mr *MockStoreMockRecorder) PayPaymentRequestPendingTx(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayPaymentRequestPendingTx", reflect.TypeOf((*MockStore)(nil).PayPaymentRequestPendingTx), ctx, arg)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
+// PayPaymentRequestTx mocks base method.
+func (m *MockStore) PayPaymentRequestTx(ctx context.Context, arg db.PayPaymentRequestTxParams) (db.PayPaymentRequestTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", ctx, arg)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "PayPaymentRequestTx", ctx, arg)
+	ret0, _ := ret[0].(db.PayPaymentRequestTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(ctx, arg any) *gomock.Call {
+// PayPaymentRequestTx indicates an expected call of PayPaymentRequestTx.
+func (mr *MockStoreMockRecorder) PayPaymentRequestTx(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayPaymentRequestTx", reflect.TypeOf((*MockStore)(nil).PayPaymentRequestTx), ctx, arg)
 }
 
-// CreateUserTx mocks base method.
-func (m *MockStore) CreateUserTx(ctx context.Context, arg db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+// PostInterestTx mocks base method.
+func (m *MockStore) PostInterestTx(ctx context.Context, arg db.PostInterestTxParams) (db.PostInterestTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUserTx", ctx, arg)
-	ret0, _ := ret[0].(db.CreateUserTxResult)
+	ret := m.ctrl.Call(m, "PostInterestTx", ctx, arg)
+	ret0, _ := ret[0].(db.PostInterestTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUserTx indicates an expected call of CreateUserTx.
-func (mr *MockStoreMockRecorder) CreateUserTx(ctx, arg any) *gomock.Call {
+// PostInterestTx indicates an expected call of PostInterestTx.
+func (mr *MockStoreMockRecorder) PostInterestTx(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserTx", reflect.TypeOf((*MockStore)(nil).CreateUserTx), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostInterestTx", reflect.TypeOf((*MockStore)(nil).PostInterestTx), ctx, arg)
 }
 
-// CreateVerifyEmail mocks base method.
-func (m *MockStore) CreateVerifyEmail(ctx context.Context, arg db.CreateVerifyEmailParams) (db.VerifyEmail, error) {
+// PublishOutboxTx mocks base method.
+func (m *MockStore) PublishOutboxTx(ctx context.Context, arg db.PublishOutboxTxParams) (db.PublishOutboxTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateVerifyEmail", ctx, arg)
-	ret0, _ := ret[0].(db.VerifyEmail)
+	ret := m.ctrl.Call(m, "PublishOutboxTx", ctx, arg)
+	ret0, _ := ret[0].(db.PublishOutboxTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateVerifyEmail indicates an expected call of CreateVerifyEmail.
-func (mr *MockStoreMockRecorder) CreateVerifyEmail(ctx, arg any) *gomock.Call {
+// PublishOutboxTx indicates an expected call of PublishOutboxTx.
+func (mr *MockStoreMockRecorder) PublishOutboxTx(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVerifyEmail", reflect.TypeOf((*MockStore)(nil).CreateVerifyEmail), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishOutboxTx", reflect.TypeOf((*MockStore)(nil).PublishOutboxTx), ctx, arg)
 }
 
-// DeleteAccount mocks base method.
-func (m *MockStore) DeleteAccount(ctx context.Context, id int64) error {
+// RecordOutboxMessageFailure mocks base method.
+func (m *MockStore) RecordOutboxMessageFailure(ctx context.Context, arg db.RecordOutboxMessageFailureParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAccount", ctx, id)
+	ret := m.ctrl.Call(m, "RecordOutboxMessageFailure", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteAccount indicates an expected call of DeleteAccount.
-func (mr *MockStoreMockRecorder) DeleteAccount(ctx, id any) *gomock.Call {
+// RecordOutboxMessageFailure indicates an expected call of RecordOutboxMessageFailure.
+func (mr *MockStoreMockRecorder) RecordOutboxMessageFailure(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordOutboxMessageFailure", reflect.TypeOf((*MockStore)(nil).RecordOutboxMessageFailure), ctx, arg)
 }
 
-// DeleteTransfer mocks base method.
-func (m *MockStore) DeleteTransfer(ctx context.Context, id int64) error {
+// RecordWebhookDeliveryAttempt mocks base method.
+func (m *MockStore) RecordWebhookDeliveryAttempt(ctx context.Context, arg db.RecordWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTransfer", ctx, id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "RecordWebhookDeliveryAttempt", ctx, arg)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteTransfer indicates an expected call of DeleteTransfer.
-func (mr *MockStoreMockRecorder) DeleteTransfer(ctx, id any) *gomock.Call {
+// RecordWebhookDeliveryAttempt indicates an expected call of RecordWebhookDeliveryAttempt.
+func (mr *MockStoreMockRecorder) RecordWebhookDeliveryAttempt(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransfer", reflect.TypeOf((*MockStore)(nil).DeleteTransfer), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookDeliveryAttempt", reflect.TypeOf((*MockStore)(nil).RecordWebhookDeliveryAttempt), ctx, arg)
 }
 
-// GetAccount mocks base method.
-func (m *MockStore) GetAccount(ctx context.Context, id int64) (db.Account, error) {
+// RedeliverWebhookTx mocks base method.
+func (m *MockStore) RedeliverWebhookTx(ctx context.Context, arg db.RedeliverWebhookTxParams) (db.RedeliverWebhookTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccount", ctx, id)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "RedeliverWebhookTx", ctx, arg)
+	ret0, _ := ret[0].(db.RedeliverWebhookTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccount indicates an expected call of GetAccount.
-func (mr *MockStoreMockRecorder) GetAccount(ctx, id any) *gomock.Call {
+// RedeliverWebhookTx indicates an expected call of RedeliverWebhookTx.
+func (mr *MockStoreMockRecorder) RedeliverWebhookTx(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverWebhookTx", reflect.TypeOf((*MockStore)(nil).RedeliverWebhookTx), ctx, arg)
 }
 
-// GetEntry mocks base method.
-func (m *MockStore) GetEntry(ctx context.Context, id int64) (db.Entry, error) {
+// RejectTransferTx mocks base method.
+func (m *MockStore) RejectTransferTx(ctx context.Context, arg db.RejectTransferTxParams) (db.CloseTransferTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntry", ctx, id)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "RejectTransferTx", ctx, arg)
+	ret0, _ := ret[0].(db.CloseTransferTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntry indicates an expected call of GetEntry.
-func (mr *MockStoreMockRecorder) GetEntry(ctx, id any) *gomock.Call {
+// RejectTransferTx indicates an expected call of RejectTransferTx.
+func (mr *MockStoreMockRecorder) RejectTransferTx(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectTransferTx", reflect.TypeOf((*MockStore)(nil).RejectTransferTx), ctx, arg)
 }
 
-// GetSession mocks base method.
-func (m *MockStore) GetSession(ctx context.Context, id uuid.UUID) (db.Session, error) {
+// ReleaseSchedulerLease mocks base method.
+func (m *MockStore) ReleaseSchedulerLease(ctx context.Context, arg db.ReleaseSchedulerLeaseParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSession", ctx, id)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "ReleaseSchedulerLease", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseSchedulerLease indicates an expected call of ReleaseSchedulerLease.
+func (mr *MockStoreMockRecorder) ReleaseSchedulerLease(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseSchedulerLease", reflect.TypeOf((*MockStore)(nil).ReleaseSchedulerLease), ctx, arg)
+}
+
+// ReopenPaymentRequest mocks base method.
+func (m *MockStore) ReopenPaymentRequest(ctx context.Context, transferID sql.NullInt64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReopenPaymentRequest", ctx, transferID)
+	ret0, _ := ret[0].(db.PaymentRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSession indicates an expected call of GetSession.
-func (mr *MockStoreMockRecorder) GetSession(ctx, id any) *gomock.Call {
+// ReopenPaymentRequest indicates an expected call of ReopenPaymentRequest.
+func (mr *MockStoreMockRecorder) ReopenPaymentRequest(ctx, transferID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReopenPaymentRequest", reflect.TypeOf((*MockStore)(nil).ReopenPaymentRequest), ctx, transferID)
 }
 
-// GetTransfer mocks base method.
-func (m *MockStore) GetTransfer(ctx context.Context, id int64) (db.Transfer, error) {
+// ResetWebhookDelivery mocks base method.
+func (m *MockStore) ResetWebhookDelivery(ctx context.Context, id int64) (db.WebhookDelivery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransfer", ctx, id)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "ResetWebhookDelivery", ctx, id)
+	ret0, _ := ret[0].(db.WebhookDelivery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransfer indicates an expected call of GetTransfer.
-func (mr *MockStoreMockRecorder) GetTransfer(ctx, id any) *gomock.Call {
+// ResetWebhookDelivery indicates an expected call of ResetWebhookDelivery.
+func (mr *MockStoreMockRecorder) ResetWebhookDelivery(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetWebhookDelivery", reflect.TypeOf((*MockStore)(nil).ResetWebhookDelivery), ctx, id)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(ctx context.Context, username string) (db.User, error) {
+// RetryTask mocks base method.
+func (m *MockStore) RetryTask(ctx context.Context, arg db.RetryTaskParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", ctx, username)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "RetryTask", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryTask indicates an expected call of RetryTask.
+func (mr *MockStoreMockRecorder) RetryTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryTask", reflect.TypeOf((*MockStore)(nil).RetryTask), ctx, arg)
+}
+
+// ReverseExternalTransferTx mocks base method.
+func (m *MockStore) ReverseExternalTransferTx(ctx context.Context, arg db.ReverseExternalTransferTxParams) (db.ExternalTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseExternalTransferTx", ctx, arg)
+	ret0, _ := ret[0].(db.ExternalTransferTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(ctx, username any) *gomock.Call {
+// ReverseExternalTransferTx indicates an expected call of ReverseExternalTransferTx.
+func (mr *MockStoreMockRecorder) ReverseExternalTransferTx(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), ctx, username)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseExternalTransferTx", reflect.TypeOf((*MockStore)(nil).ReverseExternalTransferTx), ctx, arg)
 }
 
-// ListAccounts mocks base method.
-func (m *MockStore) ListAccounts(ctx context.Context, arg db.ListAccountsParams) ([]db.Account, error) {
+// RunFailedTask mocks base method.
+func (m *MockStore) RunFailedTask(ctx context.Context, taskID string) (db.TaskQueue, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAccounts", ctx, arg)
-	ret0, _ := ret[0].([]db.Account)
+	ret := m.ctrl.Call(m, "RunFailedTask", ctx, taskID)
+	ret0, _ := ret[0].(db.TaskQueue)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListAccounts indicates an expected call of ListAccounts.
-func (mr *MockStoreMockRecorder) ListAccounts(ctx, arg any) *gomock.Call {
+// RunFailedTask indicates an expected call of RunFailedTask.
+func (mr *MockStoreMockRecorder) RunFailedTask(ctx, taskID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunFailedTask", reflect.TypeOf((*MockStore)(nil).RunFailedTask), ctx, taskID)
 }
 
-// ListEntries mocks base method.
-func (m *MockStore) ListEntries(ctx context.Context, arg db.ListEntriesParams) ([]db.Entry, error) {
+// SchedulePeriodicJobTx mocks base method.
+func (m *MockStore) SchedulePeriodicJobTx(ctx context.Context, arg db.SchedulePeriodicJobTxParams) (db.SchedulePeriodicJobTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListEntries", ctx, arg)
-	ret0, _ := ret[0].([]db.Entry)
+	ret := m.ctrl.Call(m, "SchedulePeriodicJobTx", ctx, arg)
+	ret0, _ := ret[0].(db.SchedulePeriodicJobTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListEntries indicates an expected call of ListEntries.
-func (mr *MockStoreMockRecorder) ListEntries(ctx, arg any) *gomock.Call {
+// SchedulePeriodicJobTx indicates an expected call of SchedulePeriodicJobTx.
+func (mr *MockStoreMockRecorder) SchedulePeriodicJobTx(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchedulePeriodicJobTx", reflect.TypeOf((*MockStore)(nil).SchedulePeriodicJobTx), ctx, arg)
 }
 
-// ListEntriesByAccountID mocks base method.
-func (m *MockStore) ListEntriesByAccountID(ctx context.Context, arg db.ListEntriesByAccountIDParams) ([]db.Entry, error) {
+// SettleExternalTransferTx mocks base method.
+func (m *MockStore) SettleExternalTransferTx(ctx context.Context, transferID int64) (db.ExternalTransferTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListEntriesByAccountID", ctx, arg)
-	ret0, _ := ret[0].([]db.Entry)
+	ret := m.ctrl.Call(m, "SettleExternalTransferTx", ctx, transferID)
+	ret0, _ := ret[0].(db.ExternalTransferTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListEntriesByAccountID indicates an expected call of ListEntriesByAccountID.
-func (mr *MockStoreMockRecorder) ListEntriesByAccountID(ctx, arg any) *gomock.Call {
+// SettleExternalTransferTx indicates an expected call of SettleExternalTransferTx.
+func (mr *MockStoreMockRecorder) SettleExternalTransferTx(ctx, transferID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntriesByAccountID", reflect.TypeOf((*MockStore)(nil).ListEntriesByAccountID), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleExternalTransferTx", reflect.TypeOf((*MockStore)(nil).SettleExternalTransferTx), ctx, transferID)
 }
 
-// ListTransfers mocks base method.
-func (m *MockStore) ListTransfers(ctx context.Context, arg db.ListTransfersParams) ([]db.Transfer, error) {
+// SubmitExternalTransferTx mocks base method.
+func (m *MockStore) SubmitExternalTransferTx(ctx context.Context, arg db.SubmitExternalTransferTxParams) (db.ExternalTransferTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTransfers", ctx, arg)
-	ret0, _ := ret[0].([]db.Transfer)
+	ret := m.ctrl.Call(m, "SubmitExternalTransferTx", ctx, arg)
+	ret0, _ := ret[0].(db.ExternalTransferTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListTransfers indicates an expected call of ListTransfers.
-func (mr *MockStoreMockRecorder) ListTransfers(ctx, arg any) *gomock.Call {
+// SubmitExternalTransferTx indicates an expected call of SubmitExternalTransferTx.
+func (mr *MockStoreMockRecorder) SubmitExternalTransferTx(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitExternalTransferTx", reflect.TypeOf((*MockStore)(nil).SubmitExternalTransferTx), ctx, arg)
 }
 
-// ListTransfersByFromAccount mocks base method.
-func (m *MockStore) ListTransfersByFromAccount(ctx context.Context, arg db.ListTransfersByFromAccountParams) ([]db.Transfer, error) {
+// SumAccountEntriesSince mocks base method.
+func (m *MockStore) SumAccountEntriesSince(ctx context.Context, arg db.SumAccountEntriesSinceParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTransfersByFromAccount", ctx, arg)
-	ret0, _ := ret[0].([]db.Transfer)
+	ret := m.ctrl.Call(m, "SumAccountEntriesSince", ctx, arg)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListTransfersByFromAccount indicates an expected call of ListTransfersByFromAccount.
-func (mr *MockStoreMockRecorder) ListTransfersByFromAccount(ctx, arg any) *gomock.Call {
+// SumAccountEntriesSince indicates an expected call of SumAccountEntriesSince.
+func (mr *MockStoreMockRecorder) SumAccountEntriesSince(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfersByFromAccount", reflect.TypeOf((*MockStore)(nil).ListTransfersByFromAccount), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumAccountEntriesSince", reflect.TypeOf((*MockStore)(nil).SumAccountEntriesSince), ctx, arg)
 }
 
-// ListTransfersByToAccount mocks base method.
-func (m *MockStore) ListTransfersByToAccount(ctx context.Context, arg db.ListTransfersByToAccountParams) ([]db.Transfer, error) {
+// SumUnpostedInterest mocks base method.
+func (m *MockStore) SumUnpostedInterest(ctx context.Context, arg db.SumUnpostedInterestParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTransfersByToAccount", ctx, arg)
-	ret0, _ := ret[0].([]db.Transfer)
+	ret := m.ctrl.Call(m, "SumUnpostedInterest", ctx, arg)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListTransfersByToAccount indicates an expected call of ListTransfersByToAccount.
-func (mr *MockStoreMockRecorder) ListTransfersByToAccount(ctx, arg any) *gomock.Call {
+// SumUnpostedInterest indicates an expected call of SumUnpostedInterest.
+func (mr *MockStoreMockRecorder) SumUnpostedInterest(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfersByToAccount", reflect.TypeOf((*MockStore)(nil).ListTransfersByToAccount), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumUnpostedInterest", reflect.TypeOf((*MockStore)(nil).SumUnpostedInterest), ctx, arg)
 }
 
 // TransferTX mocks base method.
@@ -385,6 +2326,111 @@ func (mr *MockStoreMockRecorder) UpdateAccount(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), ctx, arg)
 }
 
+// UpdateAccountFreezeStatus mocks base method.
+func (m *MockStore) UpdateAccountFreezeStatus(ctx context.Context, arg db.UpdateAccountFreezeStatusParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountFreezeStatus", ctx, arg)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountFreezeStatus indicates an expected call of UpdateAccountFreezeStatus.
+func (mr *MockStoreMockRecorder) UpdateAccountFreezeStatus(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountFreezeStatus", reflect.TypeOf((*MockStore)(nil).UpdateAccountFreezeStatus), ctx, arg)
+}
+
+// UpdateAccountOverdraftLimit mocks base method.
+func (m *MockStore) UpdateAccountOverdraftLimit(ctx context.Context, arg db.UpdateAccountOverdraftLimitParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountOverdraftLimit", ctx, arg)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountOverdraftLimit indicates an expected call of UpdateAccountOverdraftLimit.
+func (mr *MockStoreMockRecorder) UpdateAccountOverdraftLimit(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountOverdraftLimit", reflect.TypeOf((*MockStore)(nil).UpdateAccountOverdraftLimit), ctx, arg)
+}
+
+// UpdateInterestPostingTransfer mocks base method.
+func (m *MockStore) UpdateInterestPostingTransfer(ctx context.Context, arg db.UpdateInterestPostingTransferParams) (db.InterestPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInterestPostingTransfer", ctx, arg)
+	ret0, _ := ret[0].(db.InterestPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInterestPostingTransfer indicates an expected call of UpdateInterestPostingTransfer.
+func (mr *MockStoreMockRecorder) UpdateInterestPostingTransfer(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInterestPostingTransfer", reflect.TypeOf((*MockStore)(nil).UpdateInterestPostingTransfer), ctx, arg)
+}
+
+// UpdateOverdraftChargeTransfer mocks base method.
+func (m *MockStore) UpdateOverdraftChargeTransfer(ctx context.Context, arg db.UpdateOverdraftChargeTransferParams) (db.OverdraftCharge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOverdraftChargeTransfer", ctx, arg)
+	ret0, _ := ret[0].(db.OverdraftCharge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOverdraftChargeTransfer indicates an expected call of UpdateOverdraftChargeTransfer.
+func (mr *MockStoreMockRecorder) UpdateOverdraftChargeTransfer(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOverdraftChargeTransfer", reflect.TypeOf((*MockStore)(nil).UpdateOverdraftChargeTransfer), ctx, arg)
+}
+
+// UpdatePayeeNickname mocks base method.
+func (m *MockStore) UpdatePayeeNickname(ctx context.Context, arg db.UpdatePayeeNicknameParams) (db.Payee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePayeeNickname", ctx, arg)
+	ret0, _ := ret[0].(db.Payee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePayeeNickname indicates an expected call of UpdatePayeeNickname.
+func (mr *MockStoreMockRecorder) UpdatePayeeNickname(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePayeeNickname", reflect.TypeOf((*MockStore)(nil).UpdatePayeeNickname), ctx, arg)
+}
+
+// UpdatePaymentImportResult mocks base method.
+func (m *MockStore) UpdatePaymentImportResult(ctx context.Context, arg db.UpdatePaymentImportResultParams) (db.PaymentImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentImportResult", ctx, arg)
+	ret0, _ := ret[0].(db.PaymentImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePaymentImportResult indicates an expected call of UpdatePaymentImportResult.
+func (mr *MockStoreMockRecorder) UpdatePaymentImportResult(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentImportResult", reflect.TypeOf((*MockStore)(nil).UpdatePaymentImportResult), ctx, arg)
+}
+
+// UpdatePendingTransferStatus mocks base method.
+func (m *MockStore) UpdatePendingTransferStatus(ctx context.Context, arg db.UpdatePendingTransferStatusParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePendingTransferStatus", ctx, arg)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePendingTransferStatus indicates an expected call of UpdatePendingTransferStatus.
+func (mr *MockStoreMockRecorder) UpdatePendingTransferStatus(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePendingTransferStatus", reflect.TypeOf((*MockStore)(nil).UpdatePendingTransferStatus), ctx, arg)
+}
+
 // UpdateTransfer mocks base method.
 func (m *MockStore) UpdateTransfer(ctx context.Context, arg db.UpdateTransferParams) (db.Transfer, error) {
 	m.ctrl.T.Helper()
@@ -400,6 +2446,21 @@ func (mr *MockStoreMockRecorder) UpdateTransfer(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransfer", reflect.TypeOf((*MockStore)(nil).UpdateTransfer), ctx, arg)
 }
 
+// UpdateTransferStatus mocks base method.
+func (m *MockStore) UpdateTransferStatus(ctx context.Context, arg db.UpdateTransferStatusParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransferStatus", ctx, arg)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTransferStatus indicates an expected call of UpdateTransferStatus.
+func (mr *MockStoreMockRecorder) UpdateTransferStatus(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransferStatus", reflect.TypeOf((*MockStore)(nil).UpdateTransferStatus), ctx, arg)
+}
+
 // UpdateUser mocks base method.
 func (m *MockStore) UpdateUser(ctx context.Context, arg db.UpdateUserParams) (db.User, error) {
 	m.ctrl.T.Helper()
@@ -415,6 +2476,21 @@ func (mr *MockStoreMockRecorder) UpdateUser(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStore)(nil).UpdateUser), ctx, arg)
 }
 
+// UpdateUserTx mocks base method.
+func (m *MockStore) UpdateUserTx(ctx context.Context, arg db.UpdateUserTxParams) (db.UpdateUserTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserTx", ctx, arg)
+	ret0, _ := ret[0].(db.UpdateUserTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserTx indicates an expected call of UpdateUserTx.
+func (mr *MockStoreMockRecorder) UpdateUserTx(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserTx", reflect.TypeOf((*MockStore)(nil).UpdateUserTx), ctx, arg)
+}
+
 // UpdateVerifyEmail mocks base method.
 func (m *MockStore) UpdateVerifyEmail(ctx context.Context, arg db.UpdateVerifyEmailParams) (db.VerifyEmail, error) {
 	m.ctrl.T.Helper()
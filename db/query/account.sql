@@ -2,9 +2,11 @@
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  product_type,
+  account_number
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 )
 RETURNING *;
 
@@ -12,10 +14,15 @@ RETURNING *;
 SELECT * FROM accounts
 WHERE id = $1 LIMIT 1;
 
--- name: ListAccounts :many
+-- name: GetAccountByNumber :one
 SELECT * FROM accounts
-WHERE owner = $1
-ORDER BY id
+WHERE account_number = $1 LIMIT 1;
+
+-- name: ListAccounts :many
+SELECT accounts.* FROM accounts
+JOIN account_members ON account_members.account_id = accounts.id
+WHERE account_members.username = $1
+ORDER BY accounts.id
 LIMIT $2
 OFFSET $3;
 
@@ -31,6 +38,49 @@ SET balance = balance + sqlc.arg(amount)
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
+-- name: AddAccountHeldAmount :one
+UPDATE accounts
+SET held_amount = held_amount + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
 -- name: DeleteAccount :exec
 DELETE FROM accounts
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: GetAccountForUpdate :one
+SELECT * FROM accounts
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: UpdateAccountFreezeStatus :one
+UPDATE accounts
+SET freeze_status = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: EnsureSystemAccount :one
+-- 系统账户按 (币种, 用途) 懒创建，冲突时返回已存在的那一行
+INSERT INTO accounts (
+  owner,
+  balance,
+  currency,
+  product_type,
+  account_number
+) VALUES (
+  'bank', 0, sqlc.arg(currency), sqlc.arg(product_type), sqlc.arg(account_number)
+)
+ON CONFLICT (owner, currency, product_type) DO UPDATE SET owner = EXCLUDED.owner
+RETURNING *;
+
+-- name: GetRecipientAccount :one
+-- 按用户名或已验证邮箱找收款账户，查不到的原因一律不区分
+SELECT accounts.* FROM accounts
+JOIN users ON users.username = accounts.owner
+WHERE (users.username = sqlc.arg(recipient) OR users.email = sqlc.arg(recipient))
+  AND users.is_email_verified = true
+  AND accounts.owner <> 'bank'
+  AND accounts.currency = sqlc.arg(currency)
+  AND accounts.product_type = sqlc.arg(product_type)
+  AND accounts.freeze_status <> 'frozen'
+LIMIT 1;
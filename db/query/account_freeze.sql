@@ -0,0 +1,17 @@
+-- name: CreateAccountFreeze :one
+INSERT INTO account_freezes (
+  account_id,
+  freeze_status,
+  reason,
+  actor
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListAccountFreezes :many
+SELECT * FROM account_freezes
+WHERE account_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
@@ -0,0 +1,7 @@
+-- name: GetAccountProduct :one
+SELECT * FROM account_products
+WHERE code = $1 LIMIT 1;
+
+-- name: ListAccountProducts :many
+SELECT * FROM account_products
+ORDER BY code;
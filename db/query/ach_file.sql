@@ -0,0 +1,21 @@
+-- name: CreateACHFile :one
+INSERT INTO ach_files (
+  file_id_modifier,
+  entry_count,
+  total_credit,
+  content
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetACHFile :one
+SELECT * FROM ach_files
+WHERE id = $1 LIMIT 1;
+
+-- name: CountACHFilesSince :one
+SELECT count(*) FROM ach_files
+WHERE created_at >= $1;
+
+-- name: NextACHTraceSequence :one
+SELECT nextval('ach_trace_numbers')::bigint;
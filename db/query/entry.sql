@@ -1,9 +1,10 @@
 -- name: CreateEntry :one
 INSERT INTO entries (
   account_id,
-  amount
+  amount,
+  transfer_id
 ) VALUES (
-  $1, $2
+  $1, $2, sqlc.narg(transfer_id)
 )
 RETURNING *;
 
@@ -20,4 +21,75 @@ LIMIT $2 OFFSET $3;
 -- name: ListEntries :many
 SELECT * FROM entries
 ORDER BY created_at DESC
-LIMIT $1 OFFSET $2;
\ No newline at end of file
+LIMIT $1 OFFSET $2;
+
+-- name: ListStatementEntries :many
+-- 对账单的分录，转账带出备注、参考号和对方账户，[from_time, to_time) 左闭右开
+-- 按用户名转账时收款账户对转出方隐藏，转出方这边不带对方账户
+SELECT
+  entries.id,
+  entries.amount,
+  entries.created_at,
+  entries.transfer_id,
+  COALESCE(transfers.memo, '')::text AS memo,
+  COALESCE(transfers.external_reference, '')::text AS external_reference,
+  COALESCE(transfers.fee, 0)::bigint AS fee,
+  COALESCE(counterparty.account_number, '')::text AS counterparty_account_number,
+  COALESCE(counterparty_owner.full_name, '')::text AS counterparty_name
+FROM entries
+LEFT JOIN transfers ON transfers.id = entries.transfer_id
+LEFT JOIN accounts AS counterparty ON counterparty.id = CASE
+  WHEN transfers.from_account_id = entries.account_id AND transfers.hide_to_account THEN NULL
+  WHEN transfers.from_account_id = entries.account_id THEN transfers.to_account_id
+  ELSE transfers.from_account_id
+END
+LEFT JOIN users AS counterparty_owner ON counterparty_owner.username = counterparty.owner
+WHERE entries.account_id = sqlc.arg(account_id)
+  AND entries.created_at >= sqlc.arg(from_time)
+  AND entries.created_at < sqlc.arg(to_time)
+ORDER BY entries.id;
+
+-- name: SumAccountEntriesSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint FROM entries
+WHERE account_id = $1 AND created_at >= $2;
+
+-- name: ListAccountActivity :many
+-- 游标之后的分录，balance 是这条分录入账后的余额，由当前余额减去之后的分录倒推
+-- 同一条语句里读余额和分录，看到的是同一个快照
+WITH later AS (
+  SELECT * FROM entries
+  WHERE entries.account_id = sqlc.arg(account_id) AND entries.id > sqlc.arg(after_entry_id)
+)
+SELECT
+  later.id,
+  later.account_id,
+  later.amount,
+  later.created_at,
+  later.transfer_id,
+  (accounts.balance - COALESCE(SUM(later.amount) OVER (
+    ORDER BY later.id DESC ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+  ), 0))::bigint AS balance
+FROM later
+JOIN accounts ON accounts.id = later.account_id
+ORDER BY later.id
+LIMIT sqlc.arg(limit_count);
+
+-- name: GetAccountActivityCursor :one
+-- 账户当前的余额和最后一条分录的 ID，开始订阅时作为起点
+SELECT
+  accounts.balance,
+  COALESCE((SELECT MAX(entries.id) FROM entries WHERE entries.account_id = accounts.id), 0)::bigint AS last_entry_id
+FROM accounts
+WHERE accounts.id = $1;
+
+-- name: ListUnbalancedAccounts :many
+-- 余额应该等于全部分录之和，对不上的账户说明有绕过分录改余额的地方
+SELECT
+  a.id,
+  a.balance,
+  COALESCE(SUM(e.amount), 0)::bigint AS entry_total
+FROM accounts AS a
+LEFT JOIN entries AS e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id;
@@ -0,0 +1,66 @@
+-- name: CreateExternalPayment :one
+INSERT INTO external_payments (
+  transfer_id,
+  network,
+  creditor_account_number,
+  creditor_name,
+  creditor_routing_number
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetExternalPayment :one
+SELECT * FROM external_payments
+WHERE transfer_id = $1 LIMIT 1;
+
+-- name: GetExternalPaymentByReference :one
+SELECT * FROM external_payments
+WHERE network = $1 AND network_reference = $2
+LIMIT 1;
+
+-- name: ListInitiatedExternalPayments :many
+SELECT sqlc.embed(external_payments), sqlc.embed(transfers)
+FROM external_payments
+JOIN transfers ON transfers.id = external_payments.transfer_id
+WHERE external_payments.network = $1
+  AND transfers.status = 'initiated'
+ORDER BY external_payments.transfer_id;
+
+-- name: MarkExternalPaymentSubmitted :one
+UPDATE external_payments
+SET
+  network_reference = $2,
+  ach_file_id = sqlc.narg(ach_file_id),
+  submitted_at = now(),
+  updated_at = now()
+WHERE transfer_id = $1
+RETURNING *;
+
+-- name: MarkExternalPaymentSettled :one
+UPDATE external_payments
+SET
+  settlement_transfer_id = $2,
+  settled_at = now(),
+  updated_at = now()
+WHERE transfer_id = $1
+RETURNING *;
+
+-- name: MarkExternalPaymentReversed :one
+UPDATE external_payments
+SET
+  reversal_transfer_id = $2,
+  failure_reason = $3,
+  updated_at = now()
+WHERE transfer_id = $1
+RETURNING *;
+
+-- name: ListSettleableExternalPayments :many
+-- 提交后过了退回期限还没被退回的付款，可以认为已经清算
+SELECT external_payments.transfer_id
+FROM external_payments
+JOIN transfers ON transfers.id = external_payments.transfer_id
+WHERE external_payments.network = sqlc.arg(network)
+  AND transfers.status = 'pending'
+  AND external_payments.submitted_at <= sqlc.arg(submitted_before)::timestamptz
+ORDER BY external_payments.transfer_id;
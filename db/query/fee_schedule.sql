@@ -0,0 +1,29 @@
+-- name: CreateFeeSchedule :one
+INSERT INTO fee_schedules (
+  transfer_type,
+  product_type,
+  flat_fee,
+  percentage_bps,
+  percentage_threshold,
+  max_fee
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetTransferFeeSchedule :one
+-- 产品专属的配置优先于通用配置
+SELECT * FROM fee_schedules
+WHERE transfer_type = sqlc.arg(transfer_type)
+  AND (product_type = sqlc.arg(product_type)::varchar OR product_type IS NULL)
+ORDER BY product_type NULLS LAST
+LIMIT 1;
+
+-- name: ListFeeSchedules :many
+SELECT * FROM fee_schedules
+ORDER BY transfer_type, product_type NULLS FIRST;
+
+-- name: DeleteFeeSchedule :one
+DELETE FROM fee_schedules
+WHERE id = $1
+RETURNING *;
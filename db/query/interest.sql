@@ -0,0 +1,75 @@
+-- name: ListInterestBearingAccounts :many
+-- 日终余额 = 当前余额 减去 日终之后发生的分录
+SELECT
+  a.id,
+  a.currency,
+  p.annual_interest_rate_bps,
+  p.day_count_convention,
+  (a.balance - COALESCE((
+    SELECT SUM(e.amount) FROM entries e
+    WHERE e.account_id = a.id AND e.created_at >= sqlc.arg(end_of_day)
+  ), 0))::bigint AS end_of_day_balance
+FROM accounts a
+JOIN account_products p ON p.code = a.product_type
+WHERE p.annual_interest_rate_bps > 0
+AND a.created_at < sqlc.arg(end_of_day)
+ORDER BY a.id;
+
+-- name: CreateInterestAccrual :one
+INSERT INTO interest_accruals (
+  account_id,
+  accrual_date,
+  balance,
+  annual_rate_bps,
+  day_count_convention,
+  amount_micros
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (account_id, accrual_date) DO NOTHING
+RETURNING *;
+
+-- name: ListAccountsWithUnpostedInterest :many
+SELECT DISTINCT account_id FROM interest_accruals
+WHERE posting_id IS NULL
+AND accrual_date < sqlc.arg(period_end)
+ORDER BY account_id;
+
+-- name: SumUnpostedInterest :one
+SELECT COALESCE(SUM(amount_micros), 0)::bigint FROM interest_accruals
+WHERE account_id = $1
+AND posting_id IS NULL
+AND accrual_date < sqlc.arg(period_end);
+
+-- name: MarkInterestAccrualsPosted :exec
+UPDATE interest_accruals
+SET posting_id = sqlc.arg(posting_id)
+WHERE account_id = sqlc.arg(account_id)
+AND posting_id IS NULL
+AND accrual_date < sqlc.arg(period_end);
+
+-- name: GetLatestInterestPosting :one
+SELECT * FROM interest_postings
+WHERE account_id = $1
+AND period < sqlc.arg(before_period)
+ORDER BY period DESC
+LIMIT 1;
+
+-- name: CreateInterestPosting :one
+INSERT INTO interest_postings (
+  account_id,
+  period,
+  accrued_micros,
+  amount,
+  residual_micros
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (account_id, period) DO NOTHING
+RETURNING *;
+
+-- name: UpdateInterestPostingTransfer :one
+UPDATE interest_postings
+SET transfer_id = $2
+WHERE id = $1
+RETURNING *;
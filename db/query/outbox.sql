@@ -0,0 +1,39 @@
+-- name: CreateOutboxMessage :one
+INSERT INTO outbox (
+  task_type,
+  payload,
+  queue,
+  max_retry,
+  process_at
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetOutboxMessage :one
+SELECT * FROM outbox
+WHERE id = $1 LIMIT 1;
+
+-- name: ListUnpublishedOutboxMessages :many
+-- 锁住待投递的消息，多个 relay 同时跑时互相跳过
+SELECT * FROM outbox
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxMessagePublished :exec
+UPDATE outbox
+SET published_at = now()
+WHERE id = $1;
+
+-- name: RecordOutboxMessageFailure :exec
+UPDATE outbox
+SET
+  attempts = attempts + 1,
+  last_error = $2
+WHERE id = $1;
+
+-- name: DeletePublishedOutboxMessages :execrows
+DELETE FROM outbox
+WHERE published_at < sqlc.arg(before)::timestamptz;
@@ -0,0 +1,51 @@
+-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: ListOverdrawnAccounts :many
+SELECT * FROM (
+  SELECT
+    a.id,
+    a.currency,
+    p.overdraft_interest_rate_bps,
+    p.day_count_convention,
+    (a.balance - COALESCE((
+      SELECT SUM(e.amount) FROM entries e
+      WHERE e.account_id = a.id AND e.created_at >= sqlc.arg(end_of_day)
+    ), 0))::bigint AS end_of_day_balance
+  FROM accounts a
+  JOIN account_products p ON p.code = a.product_type
+  WHERE p.overdraft_interest_rate_bps > 0
+  AND a.owner <> 'bank'
+) overdrawn
+WHERE end_of_day_balance < 0
+ORDER BY id;
+
+-- name: GetLatestOverdraftCharge :one
+SELECT * FROM overdraft_charges
+WHERE account_id = $1
+ORDER BY charge_date DESC
+LIMIT 1;
+
+-- name: CreateOverdraftCharge :one
+INSERT INTO overdraft_charges (
+  account_id,
+  charge_date,
+  balance,
+  annual_rate_bps,
+  amount_micros,
+  amount,
+  residual_micros
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (account_id, charge_date) DO NOTHING
+RETURNING *;
+
+-- name: UpdateOverdraftChargeTransfer :one
+UPDATE overdraft_charges
+SET transfer_id = $2
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,31 @@
+-- name: CreatePayee :one
+INSERT INTO payees (
+  owner,
+  account_id,
+  nickname,
+  holder_name
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetPayee :one
+SELECT * FROM payees
+WHERE id = $1 LIMIT 1;
+
+-- name: ListPayees :many
+SELECT * FROM payees
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdatePayeeNickname :one
+UPDATE payees
+SET nickname = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: DeletePayee :exec
+DELETE FROM payees
+WHERE id = $1;
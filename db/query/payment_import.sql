@@ -0,0 +1,24 @@
+-- name: CreatePaymentImport :one
+INSERT INTO payment_imports (
+  username,
+  message_id,
+  instruction_count,
+  accepted_count,
+  status_report
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetPaymentImport :one
+SELECT * FROM payment_imports
+WHERE username = $1 AND message_id = $2
+LIMIT 1;
+
+-- name: UpdatePaymentImportResult :one
+UPDATE payment_imports
+SET
+  accepted_count = $2,
+  status_report = $3
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,90 @@
+-- name: CreatePaymentRequest :one
+INSERT INTO payment_requests (
+  requester,
+  payer,
+  to_account_id,
+  amount,
+  currency,
+  memo,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetPaymentRequest :one
+SELECT * FROM payment_requests
+WHERE id = $1 LIMIT 1;
+
+-- name: GetPaymentRequestForUpdate :one
+SELECT * FROM payment_requests
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListIncomingPaymentRequests :many
+SELECT * FROM payment_requests
+WHERE payer = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ListOutgoingPaymentRequests :many
+SELECT * FROM payment_requests
+WHERE requester = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: MarkPaymentRequestPaid :one
+UPDATE payment_requests
+SET
+  status = 'paid',
+  transfer_id = sqlc.arg(transfer_id),
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: MarkPaymentRequestPendingApproval :one
+UPDATE payment_requests
+SET
+  status = 'pending_approval',
+  transfer_id = sqlc.arg(transfer_id),
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CompletePaymentRequestTransfer :one
+-- 待审批的付款被批准，转账没有关联付款请求时返回 no rows
+UPDATE payment_requests
+SET
+  status = 'paid',
+  updated_at = now()
+WHERE transfer_id = $1 AND status = 'pending_approval'
+RETURNING *;
+
+-- name: ReopenPaymentRequest :one
+-- 待审批的付款被拒绝或过期，请求回到 pending，付款人可以重新付款
+UPDATE payment_requests
+SET
+  status = 'pending',
+  transfer_id = NULL,
+  updated_at = now()
+WHERE transfer_id = $1 AND status = 'pending_approval'
+RETURNING *;
+
+-- name: DeclinePaymentRequest :one
+-- 只有 pending 的请求能拒绝，否则返回 no rows
+UPDATE payment_requests
+SET
+  status = 'declined',
+  updated_at = now()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: ExpirePaymentRequests :many
+UPDATE payment_requests
+SET
+  status = 'expired',
+  updated_at = now()
+WHERE status = 'pending' AND expires_at <= now()
+RETURNING *;
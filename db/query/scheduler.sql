@@ -0,0 +1,41 @@
+-- name: AcquireSchedulerLease :one
+-- 没人持有、已经过期或者自己持有时拿到租约并续期，否则返回 sql.ErrNoRows
+INSERT INTO scheduler_leases (
+  name,
+  holder,
+  expires_at
+) VALUES (
+  sqlc.arg(name),
+  sqlc.arg(holder),
+  now() + make_interval(secs => sqlc.arg(ttl_seconds)::int)
+)
+ON CONFLICT (name) DO UPDATE
+SET
+  holder = EXCLUDED.holder,
+  expires_at = EXCLUDED.expires_at,
+  acquired_at = CASE
+    WHEN scheduler_leases.holder = EXCLUDED.holder THEN scheduler_leases.acquired_at
+    ELSE now()
+  END
+WHERE scheduler_leases.holder = EXCLUDED.holder
+  OR scheduler_leases.expires_at < now()
+RETURNING *;
+
+-- name: ReleaseSchedulerLease :exec
+DELETE FROM scheduler_leases
+WHERE name = $1 AND holder = $2;
+
+-- name: CreatePeriodicJobRun :one
+-- 这一次已经投递过时返回 sql.ErrNoRows
+INSERT INTO periodic_job_runs (
+  job,
+  scheduled_at
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (job, scheduled_at) DO NOTHING
+RETURNING *;
+
+-- name: DeletePeriodicJobRuns :execrows
+DELETE FROM periodic_job_runs
+WHERE scheduled_at < $1;
@@ -14,4 +14,8 @@ RETURNING *;
 
 -- name: GetSession :one
 SELECT * FROM sessions
-WHERE id = $1 LIMIT 1;
\ No newline at end of file
+WHERE id = $1 LIMIT 1;
+
+-- name: DeleteExpiredSessions :execrows
+DELETE FROM sessions
+WHERE expires_at < $1;
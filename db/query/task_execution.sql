@@ -0,0 +1,19 @@
+-- name: GetTaskExecution :one
+SELECT * FROM task_executions
+WHERE task_id = $1 AND step = $2
+LIMIT 1;
+
+-- name: CreateTaskExecution :exec
+-- 同一个任务并发执行时先记的为准
+INSERT INTO task_executions (
+  task_id,
+  step,
+  result
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (task_id, step) DO NOTHING;
+
+-- name: DeleteTaskExecutions :execrows
+DELETE FROM task_executions
+WHERE created_at < $1;
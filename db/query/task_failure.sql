@@ -0,0 +1,23 @@
+-- name: CreateTaskFailure :one
+INSERT INTO task_failures (
+  task_id,
+  task_type,
+  queue,
+  error,
+  retried,
+  max_retry,
+  archived
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: ListTaskFailures :many
+-- 一个任务最近的几次失败，最新的在前
+SELECT * FROM task_failures
+WHERE task_id = $1
+ORDER BY id DESC
+LIMIT $2;
+
+-- name: DeleteTaskFailures :execrows
+DELETE FROM task_failures
+WHERE created_at < $1;
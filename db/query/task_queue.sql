@@ -0,0 +1,117 @@
+-- name: EnqueueTask :one
+-- task_id 已经存在时不插入，返回 sql.ErrNoRows
+INSERT INTO task_queue (
+  task_id,
+  task_type,
+  payload,
+  queue,
+  max_retry,
+  process_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (task_id) DO NOTHING
+RETURNING *;
+
+-- name: GetQueuedTask :one
+SELECT * FROM task_queue
+WHERE task_id = $1 LIMIT 1;
+
+-- name: ClaimTask :one
+-- 按 queues 的顺序取一个到期的任务，租约到期前其他 worker 取不到
+-- 租约过期的 active 任务说明处理它的 worker 已经退出，算一次失败：
+-- 还有重试次数的重新取出来处理，retried 加一；用完的直接归档，不再处理
+WITH exhausted AS (
+  UPDATE task_queue
+  SET
+    state = 'archived',
+    lease_expires_at = NULL,
+    last_error = 'task lease expired before the worker finished',
+    last_failed_at = now()
+  WHERE queue = ANY(sqlc.arg(queues)::varchar[])
+    AND state = 'active'
+    AND lease_expires_at < now()
+    AND retried >= max_retry
+)
+UPDATE task_queue
+SET
+  retried = retried + CASE WHEN state = 'active' THEN 1 ELSE 0 END,
+  last_error = CASE WHEN state = 'active' THEN 'task lease expired before the worker finished' ELSE last_error END,
+  last_failed_at = CASE WHEN state = 'active' THEN now() ELSE last_failed_at END,
+  state = 'active',
+  lease_expires_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::int)
+WHERE id = (
+  SELECT id FROM task_queue AS t
+  WHERE t.queue = ANY(sqlc.arg(queues)::varchar[])
+    AND (
+      (t.state = 'pending' AND t.process_at <= now())
+      OR (t.state = 'active' AND t.lease_expires_at < now() AND t.retried < t.max_retry)
+    )
+  ORDER BY array_position(sqlc.arg(queues)::varchar[], t.queue), t.process_at, t.id
+  FOR UPDATE SKIP LOCKED
+  LIMIT 1
+)
+RETURNING *;
+
+-- name: CompleteTask :exec
+UPDATE task_queue
+SET
+  state = 'completed',
+  lease_expires_at = NULL,
+  completed_at = now()
+WHERE id = $1;
+
+-- name: RetryTask :exec
+UPDATE task_queue
+SET
+  state = 'pending',
+  retried = retried + 1,
+  process_at = sqlc.arg(process_at),
+  lease_expires_at = NULL,
+  last_error = sqlc.arg(last_error),
+  last_failed_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: ArchiveTask :exec
+-- 重试用完或者不用重试的任务，留着排查，不再处理
+UPDATE task_queue
+SET
+  state = 'archived',
+  lease_expires_at = NULL,
+  last_error = sqlc.arg(last_error),
+  last_failed_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: ListFailedTasks :many
+-- state 是 retry 时列出失败后等待重试的任务，archived 时列出不再重试的任务
+SELECT * FROM task_queue
+WHERE (
+    (sqlc.arg(state)::text = 'archived' AND state = 'archived')
+    OR (sqlc.arg(state)::text = 'retry' AND state = 'pending' AND retried > 0)
+  )
+  AND (sqlc.narg(queue)::varchar IS NULL OR queue = sqlc.narg(queue))
+  AND (sqlc.narg(task_type)::varchar IS NULL OR task_type = sqlc.narg(task_type))
+ORDER BY last_failed_at DESC, id DESC
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
+
+-- name: RunFailedTask :one
+-- 失败的任务马上再处理一次，重试次数不清零
+UPDATE task_queue
+SET
+  state = 'pending',
+  process_at = now()
+WHERE task_id = $1
+  AND (state = 'archived' OR (state = 'pending' AND retried > 0))
+RETURNING *;
+
+-- name: DeleteFailedTask :one
+DELETE FROM task_queue
+WHERE task_id = $1
+  AND (state = 'archived' OR (state = 'pending' AND retried > 0))
+RETURNING *;
+
+-- name: DeleteCompletedTasks :execrows
+-- 删掉以后同一个任务 ID 可以再入队，去重靠 task_executions
+DELETE FROM task_queue
+WHERE state = 'completed' AND completed_at < sqlc.arg(before)::timestamptz;
@@ -2,9 +2,17 @@
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  memo,
+  private_note,
+  external_reference,
+  metadata,
+  fee,
+  quote_id,
+  status,
+  hide_to_account
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
 RETURNING *;
 
@@ -12,6 +20,11 @@ RETURNING *;
 SELECT * FROM transfers
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
 -- name: ListTransfers :many
 SELECT * FROM transfers
 ORDER BY id
@@ -40,4 +53,37 @@ RETURNING *;
 
 -- name: DeleteTransfer :exec
 DELETE FROM transfers
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: CountMonthlyTransfersFromAccount :one
+-- 等待审批和他行转账在途的也占次数，被拒绝、过期和失败的不算
+SELECT count(*) FROM transfers
+WHERE from_account_id = $1
+AND status NOT IN ('rejected', 'expired', 'failed')
+AND created_at >= date_trunc('month', now());
+
+-- name: UpdateTransferStatus :one
+-- 调用方先锁住转账并检查状态流转是否合法
+UPDATE transfers
+SET status = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdatePendingTransferStatus :one
+-- 只有等待审批的转账能改状态，否则返回 no rows
+UPDATE transfers
+SET status = $2
+WHERE id = $1 AND status = 'pending_approval'
+RETURNING *;
+
+-- name: ListAccountTransfers :many
+-- 账户流水，search 为空时不过滤，否则按备注全文搜索
+SELECT * FROM transfers
+WHERE (from_account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id))
+  AND (
+    sqlc.arg(search)::text = ''
+    OR to_tsvector('simple', memo) @@ plainto_tsquery('simple', sqlc.arg(search)::text)
+  )
+ORDER BY id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
\ No newline at end of file
@@ -0,0 +1,47 @@
+-- name: CreateTransferApproval :one
+INSERT INTO transfer_approvals (
+  transfer_id,
+  requested_by,
+  expires_at
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetTransferApproval :one
+SELECT * FROM transfer_approvals
+WHERE transfer_id = $1 LIMIT 1;
+
+-- name: DecideTransferApproval :one
+UPDATE transfer_approvals
+SET
+  decided_by = sqlc.narg(decided_by),
+  decided_at = now()
+WHERE transfer_id = sqlc.arg(transfer_id)
+RETURNING *;
+
+-- name: ListPendingTransferApprovals :many
+-- 银行职员看全部，其他用户只看自己有转账权限的账户
+SELECT sqlc.embed(transfers), sqlc.embed(transfer_approvals)
+FROM transfers
+JOIN transfer_approvals ON transfer_approvals.transfer_id = transfers.id
+WHERE transfers.status = 'pending_approval'
+  AND (
+    sqlc.arg(all_accounts)::bool
+    OR EXISTS (
+      SELECT 1 FROM account_members
+      WHERE account_members.account_id = transfers.from_account_id
+        AND account_members.username = sqlc.arg(username)
+        AND account_members.role IN ('owner', 'co_owner')
+    )
+  )
+ORDER BY transfers.id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: ListExpiredTransferApprovals :many
+SELECT transfer_approvals.transfer_id
+FROM transfer_approvals
+JOIN transfers ON transfers.id = transfer_approvals.transfer_id
+WHERE transfers.status = 'pending_approval' AND transfer_approvals.expires_at <= now()
+ORDER BY transfer_approvals.transfer_id;
@@ -16,4 +16,19 @@ WHERE
     AND secret_code = $2
     AND is_used = FALSE
     AND expired_at > now()
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: GetActiveVerifyEmail :one
+-- 还没用过、离过期还有一段时间的验证，重发邮件时复用同一个链接
+SELECT * FROM verify_emails
+WHERE
+    username = $1
+    AND email = $2
+    AND is_used = FALSE
+    AND expired_at > now() + interval '5 minutes'
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: DeleteExpiredVerifyEmails :execrows
+DELETE FROM verify_emails
+WHERE expired_at < $1;
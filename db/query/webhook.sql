@@ -0,0 +1,71 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+  owner,
+  url,
+  event_types,
+  secret
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscriptions
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions
+WHERE owner = $1
+ORDER BY id;
+
+-- name: ListWebhookSubscriptionsForEvent :many
+SELECT * FROM webhook_subscriptions
+WHERE owner = sqlc.arg(owner) AND sqlc.arg(event_type)::varchar = ANY(event_types)
+ORDER BY id;
+
+-- name: DeleteWebhookSubscription :exec
+DELETE FROM webhook_subscriptions
+WHERE id = $1;
+
+-- name: CreateWebhookDelivery :one
+-- 同一个事件对同一个订阅只投递一次，重复调用返回已有的记录
+INSERT INTO webhook_deliveries (
+  subscription_id,
+  event_id,
+  event_type,
+  payload
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (subscription_id, event_id) DO UPDATE
+SET event_type = EXCLUDED.event_type
+RETURNING *;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE subscription_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: RecordWebhookDeliveryAttempt :one
+UPDATE webhook_deliveries
+SET
+  status = sqlc.arg(status),
+  attempts = attempts + 1,
+  response_status = sqlc.arg(response_status),
+  last_error = sqlc.arg(last_error),
+  delivered_at = sqlc.narg(delivered_at)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ResetWebhookDelivery :one
+-- 手动重投，已经成功的也可以再投一次
+UPDATE webhook_deliveries
+SET status = 'pending'
+WHERE id = $1
+RETURNING *;
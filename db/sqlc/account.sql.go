@@ -13,7 +13,7 @@ const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, freeze_status, product_type, overdraft_limit, account_number, held_amount
 `
 
 type AddAccountBalanceParams struct {
@@ -30,6 +30,41 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.FreezeStatus,
+		&i.ProductType,
+		&i.OverdraftLimit,
+		&i.AccountNumber,
+		&i.HeldAmount,
+	)
+	return i, err
+}
+
+const addAccountHeldAmount = `-- name: AddAccountHeldAmount :one
+UPDATE accounts
+SET held_amount = held_amount + $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, freeze_status, product_type, overdraft_limit, account_number, held_amount
+`
+
+type AddAccountHeldAmountParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddAccountHeldAmount(ctx context.Context, arg AddAccountHeldAmountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, addAccountHeldAmount, arg.Amount, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.FreezeStatus,
+		&i.ProductType,
+		&i.OverdraftLimit,
+		&i.AccountNumber,
+		&i.HeldAmount,
 	)
 	return i, err
 }
@@ -38,21 +73,31 @@ const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  product_type,
+  account_number
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 )
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, freeze_status, product_type, overdraft_limit, account_number, held_amount
 `
 
 type CreateAccountParams struct {
-	Owner    string `json:"owner"`
-	Balance  int64  `json:"balance"`
-	Currency string `json:"currency"`
+	Owner         string `json:"owner"`
+	Balance       int64  `json:"balance"`
+	Currency      string `json:"currency"`
+	ProductType   string `json:"product_type"`
+	AccountNumber string `json:"account_number"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRowContext(ctx, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+		arg.ProductType,
+		arg.AccountNumber,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -60,6 +105,11 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.FreezeStatus,
+		&i.ProductType,
+		&i.OverdraftLimit,
+		&i.AccountNumber,
+		&i.HeldAmount,
 	)
 	return i, err
 }
@@ -74,8 +124,47 @@ func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
 	return err
 }
 
+const ensureSystemAccount = `-- name: EnsureSystemAccount :one
+INSERT INTO accounts (
+  owner,
+  balance,
+  currency,
+  product_type,
+  account_number
+) VALUES (
+  'bank', 0, $1, $2, $3
+)
+ON CONFLICT (owner, currency, product_type) DO UPDATE SET owner = EXCLUDED.owner
+RETURNING id, owner, balance, currency, created_at, freeze_status, product_type, overdraft_limit, account_number, held_amount
+`
+
+type EnsureSystemAccountParams struct {
+	Currency      string `json:"currency"`
+	ProductType   string `json:"product_type"`
+	AccountNumber string `json:"account_number"`
+}
+
+// 系统账户按 (币种, 用途) 懒创建，冲突时返回已存在的那一行
+func (q *Queries) EnsureSystemAccount(ctx context.Context, arg EnsureSystemAccountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, ensureSystemAccount, arg.Currency, arg.ProductType, arg.AccountNumber)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.FreezeStatus,
+		&i.ProductType,
+		&i.OverdraftLimit,
+		&i.AccountNumber,
+		&i.HeldAmount,
+	)
+	return i, err
+}
+
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, freeze_status, product_type, overdraft_limit, account_number, held_amount FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -88,26 +177,116 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.FreezeStatus,
+		&i.ProductType,
+		&i.OverdraftLimit,
+		&i.AccountNumber,
+		&i.HeldAmount,
+	)
+	return i, err
+}
+
+const getAccountByNumber = `-- name: GetAccountByNumber :one
+SELECT id, owner, balance, currency, created_at, freeze_status, product_type, overdraft_limit, account_number, held_amount FROM accounts
+WHERE account_number = $1 LIMIT 1
+`
+
+func (q *Queries) GetAccountByNumber(ctx context.Context, accountNumber string) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountByNumber, accountNumber)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.FreezeStatus,
+		&i.ProductType,
+		&i.OverdraftLimit,
+		&i.AccountNumber,
+		&i.HeldAmount,
+	)
+	return i, err
+}
+
+const getAccountForUpdate = `-- name: GetAccountForUpdate :one
+SELECT id, owner, balance, currency, created_at, freeze_status, product_type, overdraft_limit, account_number, held_amount FROM accounts
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountForUpdate, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.FreezeStatus,
+		&i.ProductType,
+		&i.OverdraftLimit,
+		&i.AccountNumber,
+		&i.HeldAmount,
+	)
+	return i, err
+}
+
+const getRecipientAccount = `-- name: GetRecipientAccount :one
+SELECT accounts.id, accounts.owner, accounts.balance, accounts.currency, accounts.created_at, accounts.freeze_status, accounts.product_type, accounts.overdraft_limit, accounts.account_number, accounts.held_amount FROM accounts
+JOIN users ON users.username = accounts.owner
+WHERE (users.username = $1 OR users.email = $1)
+  AND users.is_email_verified = true
+  AND accounts.owner <> 'bank'
+  AND accounts.currency = $2
+  AND accounts.product_type = $3
+  AND accounts.freeze_status <> 'frozen'
+LIMIT 1
+`
+
+type GetRecipientAccountParams struct {
+	Recipient   string `json:"recipient"`
+	Currency    string `json:"currency"`
+	ProductType string `json:"product_type"`
+}
+
+// 按用户名或已验证邮箱找收款账户，查不到的原因一律不区分
+func (q *Queries) GetRecipientAccount(ctx context.Context, arg GetRecipientAccountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getRecipientAccount, arg.Recipient, arg.Currency, arg.ProductType)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.FreezeStatus,
+		&i.ProductType,
+		&i.OverdraftLimit,
+		&i.AccountNumber,
+		&i.HeldAmount,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
-WHERE owner = $1
-ORDER BY id
+SELECT accounts.id, accounts.owner, accounts.balance, accounts.currency, accounts.created_at, accounts.freeze_status, accounts.product_type, accounts.overdraft_limit, accounts.account_number, accounts.held_amount FROM accounts
+JOIN account_members ON account_members.account_id = accounts.id
+WHERE account_members.username = $1
+ORDER BY accounts.id
 LIMIT $2
 OFFSET $3
 `
 
 type ListAccountsParams struct {
-	Owner  string `json:"owner"`
-	Limit  int32  `json:"limit"`
-	Offset int32  `json:"offset"`
+	Username string `json:"username"`
+	Limit    int32  `json:"limit"`
+	Offset   int32  `json:"offset"`
 }
 
 func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
-	rows, err := q.db.QueryContext(ctx, listAccounts, arg.Owner, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listAccounts, arg.Username, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -121,6 +300,11 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.FreezeStatus,
+			&i.ProductType,
+			&i.OverdraftLimit,
+			&i.AccountNumber,
+			&i.HeldAmount,
 		); err != nil {
 			return nil, err
 		}
@@ -139,7 +323,7 @@ const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
 SET balance = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, freeze_status, product_type, overdraft_limit, account_number, held_amount
 `
 
 type UpdateAccountParams struct {
@@ -156,6 +340,41 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.FreezeStatus,
+		&i.ProductType,
+		&i.OverdraftLimit,
+		&i.AccountNumber,
+		&i.HeldAmount,
+	)
+	return i, err
+}
+
+const updateAccountFreezeStatus = `-- name: UpdateAccountFreezeStatus :one
+UPDATE accounts
+SET freeze_status = $2
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, freeze_status, product_type, overdraft_limit, account_number, held_amount
+`
+
+type UpdateAccountFreezeStatusParams struct {
+	ID           int64  `json:"id"`
+	FreezeStatus string `json:"freeze_status"`
+}
+
+func (q *Queries) UpdateAccountFreezeStatus(ctx context.Context, arg UpdateAccountFreezeStatusParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountFreezeStatus, arg.ID, arg.FreezeStatus)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.FreezeStatus,
+		&i.ProductType,
+		&i.OverdraftLimit,
+		&i.AccountNumber,
+		&i.HeldAmount,
 	)
 	return i, err
 }
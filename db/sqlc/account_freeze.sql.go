@@ -0,0 +1,92 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: account_freeze.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createAccountFreeze = `-- name: CreateAccountFreeze :one
+INSERT INTO account_freezes (
+  account_id,
+  freeze_status,
+  reason,
+  actor
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, account_id, freeze_status, reason, actor, created_at
+`
+
+type CreateAccountFreezeParams struct {
+	AccountID    int64  `json:"account_id"`
+	FreezeStatus string `json:"freeze_status"`
+	Reason       string `json:"reason"`
+	Actor        string `json:"actor"`
+}
+
+func (q *Queries) CreateAccountFreeze(ctx context.Context, arg CreateAccountFreezeParams) (AccountFreeze, error) {
+	row := q.db.QueryRowContext(ctx, createAccountFreeze,
+		arg.AccountID,
+		arg.FreezeStatus,
+		arg.Reason,
+		arg.Actor,
+	)
+	var i AccountFreeze
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.FreezeStatus,
+		&i.Reason,
+		&i.Actor,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listAccountFreezes = `-- name: ListAccountFreezes :many
+SELECT id, account_id, freeze_status, reason, actor, created_at FROM account_freezes
+WHERE account_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListAccountFreezesParams struct {
+	AccountID int64 `json:"account_id"`
+	Limit     int32 `json:"limit"`
+	Offset    int32 `json:"offset"`
+}
+
+func (q *Queries) ListAccountFreezes(ctx context.Context, arg ListAccountFreezesParams) ([]AccountFreeze, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountFreezes, arg.AccountID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AccountFreeze{}
+	for rows.Next() {
+		var i AccountFreeze
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.FreezeStatus,
+			&i.Reason,
+			&i.Actor,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,70 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: account_product.sql
+
+package db
+
+import (
+	"context"
+)
+
+const getAccountProduct = `-- name: GetAccountProduct :one
+SELECT code, name, max_monthly_outgoing_transfers, min_balance, allow_external_transfers, created_at, annual_interest_rate_bps, day_count_convention, overdraft_interest_rate_bps FROM account_products
+WHERE code = $1 LIMIT 1
+`
+
+func (q *Queries) GetAccountProduct(ctx context.Context, code string) (AccountProduct, error) {
+	row := q.db.QueryRowContext(ctx, getAccountProduct, code)
+	var i AccountProduct
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.MaxMonthlyOutgoingTransfers,
+		&i.MinBalance,
+		&i.AllowExternalTransfers,
+		&i.CreatedAt,
+		&i.AnnualInterestRateBps,
+		&i.DayCountConvention,
+		&i.OverdraftInterestRateBps,
+	)
+	return i, err
+}
+
+const listAccountProducts = `-- name: ListAccountProducts :many
+SELECT code, name, max_monthly_outgoing_transfers, min_balance, allow_external_transfers, created_at, annual_interest_rate_bps, day_count_convention, overdraft_interest_rate_bps FROM account_products
+ORDER BY code
+`
+
+func (q *Queries) ListAccountProducts(ctx context.Context) ([]AccountProduct, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountProducts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AccountProduct{}
+	for rows.Next() {
+		var i AccountProduct
+		if err := rows.Scan(
+			&i.Code,
+			&i.Name,
+			&i.MaxMonthlyOutgoingTransfers,
+			&i.MinBalance,
+			&i.AllowExternalTransfers,
+			&i.CreatedAt,
+			&i.AnnualInterestRateBps,
+			&i.DayCountConvention,
+			&i.OverdraftInterestRateBps,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"simplebank/util"
 	"testing"
 	"time"
@@ -13,9 +14,11 @@ func createRandomAccount(t *testing.T) (account Account) {
 	user := createRandomUser(t)
 
 	arg := CreateAccountParams{
-		Owner:    user.Username,
-		Balance:  util.RandomMoney(),
-		Currency: util.RandomCurrency(),
+		Owner:         user.Username,
+		Balance:       util.RandomMoney(),
+		Currency:      util.RandomCurrency(),
+		ProductType:   util.CheckingProduct,
+		AccountNumber: util.RandomAccountNumber(),
 	}
 	account, err := testQueries.CreateAccount(context.Background(), arg)
 	require.NoError(t, err)
@@ -24,6 +27,8 @@ func createRandomAccount(t *testing.T) (account Account) {
 	require.Equal(t, arg.Owner, account.Owner)
 	require.Equal(t, arg.Balance, account.Balance)
 	require.Equal(t, arg.Currency, account.Currency)
+	require.Equal(t, arg.ProductType, account.ProductType)
+	require.Equal(t, arg.AccountNumber, account.AccountNumber)
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
@@ -49,6 +54,15 @@ func TestGetAccount(t *testing.T) {
 	require.WithinDuration(t, account.CreatedAt, account1.CreatedAt, time.Second)
 }
 
+func TestGetAccountByNumber(t *testing.T) {
+	account := createRandomAccount(t)
+	account1, err := testQueries.GetAccountByNumber(context.Background(), account.AccountNumber)
+
+	require.NoError(t, err)
+	require.Equal(t, account.ID, account1.ID)
+	require.True(t, util.ValidAccountNumber(account1.AccountNumber))
+}
+
 func TestUpdateAccount(t *testing.T) {
 	account := createRandomAccount(t)
 
@@ -67,3 +81,34 @@ func TestUpdateAccount(t *testing.T) {
 	require.Equal(t, account.Currency, account1.Currency)
 	require.WithinDuration(t, account.CreatedAt, account1.CreatedAt, time.Second)
 }
+
+func TestGetRecipientAccount(t *testing.T) {
+	account := createRandomAccount(t)
+	owner, err := testQueries.GetUser(context.Background(), account.Owner)
+	require.NoError(t, err)
+
+	arg := GetRecipientAccountParams{
+		Recipient:   owner.Email,
+		Currency:    account.Currency,
+		ProductType: util.CheckingProduct,
+	}
+
+	// 邮箱未验证的用户不能被找到
+	_, err = testQueries.GetRecipientAccount(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username:        owner.Username,
+		IsEmailVerified: sql.NullBool{Bool: true, Valid: true},
+	})
+	require.NoError(t, err)
+
+	account1, err := testQueries.GetRecipientAccount(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, account1.ID)
+
+	arg.Recipient = owner.Username
+	account1, err = testQueries.GetRecipientAccount(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, account1.ID)
+}
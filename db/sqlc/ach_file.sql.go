@@ -0,0 +1,91 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: ach_file.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const countACHFilesSince = `-- name: CountACHFilesSince :one
+SELECT count(*) FROM ach_files
+WHERE created_at >= $1
+`
+
+func (q *Queries) CountACHFilesSince(ctx context.Context, createdAt time.Time) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countACHFilesSince, createdAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createACHFile = `-- name: CreateACHFile :one
+INSERT INTO ach_files (
+  file_id_modifier,
+  entry_count,
+  total_credit,
+  content
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, file_id_modifier, entry_count, total_credit, content, created_at
+`
+
+type CreateACHFileParams struct {
+	FileIDModifier string `json:"file_id_modifier"`
+	EntryCount     int32  `json:"entry_count"`
+	TotalCredit    int64  `json:"total_credit"`
+	Content        string `json:"content"`
+}
+
+func (q *Queries) CreateACHFile(ctx context.Context, arg CreateACHFileParams) (AchFile, error) {
+	row := q.db.QueryRowContext(ctx, createACHFile,
+		arg.FileIDModifier,
+		arg.EntryCount,
+		arg.TotalCredit,
+		arg.Content,
+	)
+	var i AchFile
+	err := row.Scan(
+		&i.ID,
+		&i.FileIDModifier,
+		&i.EntryCount,
+		&i.TotalCredit,
+		&i.Content,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getACHFile = `-- name: GetACHFile :one
+SELECT id, file_id_modifier, entry_count, total_credit, content, created_at FROM ach_files
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetACHFile(ctx context.Context, id int64) (AchFile, error) {
+	row := q.db.QueryRowContext(ctx, getACHFile, id)
+	var i AchFile
+	err := row.Scan(
+		&i.ID,
+		&i.FileIDModifier,
+		&i.EntryCount,
+		&i.TotalCredit,
+		&i.Content,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const nextACHTraceSequence = `-- name: NextACHTraceSequence :one
+SELECT nextval('ach_trace_numbers')::bigint
+`
+
+func (q *Queries) NextACHTraceSequence(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, nextACHTraceSequence)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+type CheckTransferResult struct {
+	FromAccount Account `json:"from_account"`
+	ToAccount   Account `json:"to_account"`
+	Fee         int64   `json:"fee"`
+	// 转出账户产品没有次数限制时为空
+	RemainingMonthlyTransfers sql.NullInt64 `json:"remaining_monthly_transfers"`
+}
+
+// CheckTransfer 按 TransferTX 的规则试算一笔转账，不加锁也不写库
+// 结果只代表当前状态，真正转账时还会在锁内重新校验
+func (store *SQLStore) CheckTransfer(ctx context.Context, arg TransferTxParams) (CheckTransferResult, error) {
+	var result CheckTransferResult
+
+	fromAccount, err := store.GetAccount(ctx, arg.FromAccountID)
+	if err != nil {
+		return result, err
+	}
+
+	toAccount, err := store.GetAccount(ctx, arg.ToAccountID)
+	if err != nil {
+		return result, err
+	}
+
+	fee, err := checkTransfer(ctx, store.Queries, fromAccount, toAccount, arg.Amount, arg.QuotedFee)
+	if err != nil {
+		return result, err
+	}
+
+	product, err := store.GetAccountProduct(ctx, fromAccount.ProductType)
+	if err != nil {
+		return result, err
+	}
+
+	if product.MaxMonthlyOutgoingTransfers.Valid {
+		count, err := store.CountMonthlyTransfersFromAccount(ctx, fromAccount.ID)
+		if err != nil {
+			return result, err
+		}
+		result.RemainingMonthlyTransfers = sql.NullInt64{
+			Int64: int64(product.MaxMonthlyOutgoingTransfers.Int32) - count,
+			Valid: true,
+		}
+	}
+
+	result.FromAccount = fromAccount
+	result.ToAccount = toAccount
+	result.Fee = fee
+	return result, nil
+}
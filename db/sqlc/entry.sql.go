@@ -7,37 +7,63 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
   account_id,
-  amount
+  amount,
+  transfer_id
 ) VALUES (
-  $1, $2
+  $1, $2, $3
 )
-RETURNING id, account_id, amount, created_at
+RETURNING id, account_id, amount, created_at, transfer_id
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"account_id"`
-	Amount    int64 `json:"amount"`
+	AccountID  int64         `json:"account_id"`
+	Amount     int64         `json:"amount"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount, arg.TransferID)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
 
+const getAccountActivityCursor = `-- name: GetAccountActivityCursor :one
+SELECT
+  accounts.balance,
+  COALESCE((SELECT MAX(entries.id) FROM entries WHERE entries.account_id = accounts.id), 0)::bigint AS last_entry_id
+FROM accounts
+WHERE accounts.id = $1
+`
+
+type GetAccountActivityCursorRow struct {
+	Balance     int64 `json:"balance"`
+	LastEntryID int64 `json:"last_entry_id"`
+}
+
+// 账户当前的余额和最后一条分录的 ID，开始订阅时作为起点
+func (q *Queries) GetAccountActivityCursor(ctx context.Context, id int64) (GetAccountActivityCursorRow, error) {
+	row := q.db.QueryRowContext(ctx, getAccountActivityCursor, id)
+	var i GetAccountActivityCursorRow
+	err := row.Scan(&i.Balance, &i.LastEntryID)
+	return i, err
+}
+
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
 WHERE id = $1 LIMIT 1
 `
 
@@ -49,12 +75,80 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
 
+const listAccountActivity = `-- name: ListAccountActivity :many
+WITH later AS (
+  SELECT id, account_id, amount, created_at, transfer_id FROM entries
+  WHERE entries.account_id = $2 AND entries.id > $3
+)
+SELECT
+  later.id,
+  later.account_id,
+  later.amount,
+  later.created_at,
+  later.transfer_id,
+  (accounts.balance - COALESCE(SUM(later.amount) OVER (
+    ORDER BY later.id DESC ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+  ), 0))::bigint AS balance
+FROM later
+JOIN accounts ON accounts.id = later.account_id
+ORDER BY later.id
+LIMIT $1
+`
+
+type ListAccountActivityParams struct {
+	LimitCount   int32 `json:"limit_count"`
+	AccountID    int64 `json:"account_id"`
+	AfterEntryID int64 `json:"after_entry_id"`
+}
+
+type ListAccountActivityRow struct {
+	ID         int64         `json:"id"`
+	AccountID  int64         `json:"account_id"`
+	Amount     int64         `json:"amount"`
+	CreatedAt  time.Time     `json:"created_at"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	Balance    int64         `json:"balance"`
+}
+
+// 游标之后的分录，balance 是这条分录入账后的余额，由当前余额减去之后的分录倒推
+// 同一条语句里读余额和分录，看到的是同一个快照
+func (q *Queries) ListAccountActivity(ctx context.Context, arg ListAccountActivityParams) ([]ListAccountActivityRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountActivity, arg.LimitCount, arg.AccountID, arg.AfterEntryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAccountActivityRow{}
+	for rows.Next() {
+		var i ListAccountActivityRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+			&i.Balance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -78,6 +172,7 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
 		); err != nil {
 			return nil, err
 		}
@@ -93,7 +188,7 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 }
 
 const listEntriesByAccountID = `-- name: ListEntriesByAccountID :many
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
 WHERE account_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -119,6 +214,85 @@ func (q *Queries) ListEntriesByAccountID(ctx context.Context, arg ListEntriesByA
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStatementEntries = `-- name: ListStatementEntries :many
+SELECT
+  entries.id,
+  entries.amount,
+  entries.created_at,
+  entries.transfer_id,
+  COALESCE(transfers.memo, '')::text AS memo,
+  COALESCE(transfers.external_reference, '')::text AS external_reference,
+  COALESCE(transfers.fee, 0)::bigint AS fee,
+  COALESCE(counterparty.account_number, '')::text AS counterparty_account_number,
+  COALESCE(counterparty_owner.full_name, '')::text AS counterparty_name
+FROM entries
+LEFT JOIN transfers ON transfers.id = entries.transfer_id
+LEFT JOIN accounts AS counterparty ON counterparty.id = CASE
+  WHEN transfers.from_account_id = entries.account_id AND transfers.hide_to_account THEN NULL
+  WHEN transfers.from_account_id = entries.account_id THEN transfers.to_account_id
+  ELSE transfers.from_account_id
+END
+LEFT JOIN users AS counterparty_owner ON counterparty_owner.username = counterparty.owner
+WHERE entries.account_id = $1
+  AND entries.created_at >= $2
+  AND entries.created_at < $3
+ORDER BY entries.id
+`
+
+type ListStatementEntriesParams struct {
+	AccountID int64     `json:"account_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+}
+
+type ListStatementEntriesRow struct {
+	ID                        int64         `json:"id"`
+	Amount                    int64         `json:"amount"`
+	CreatedAt                 time.Time     `json:"created_at"`
+	TransferID                sql.NullInt64 `json:"transfer_id"`
+	Memo                      string        `json:"memo"`
+	ExternalReference         string        `json:"external_reference"`
+	Fee                       int64         `json:"fee"`
+	CounterpartyAccountNumber string        `json:"counterparty_account_number"`
+	CounterpartyName          string        `json:"counterparty_name"`
+}
+
+// 对账单的分录，转账带出备注、参考号和对方账户，[from_time, to_time) 左闭右开
+// 按用户名转账时收款账户对转出方隐藏，转出方这边不带对方账户
+func (q *Queries) ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStatementEntries, arg.AccountID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStatementEntriesRow{}
+	for rows.Next() {
+		var i ListStatementEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+			&i.Memo,
+			&i.ExternalReference,
+			&i.Fee,
+			&i.CounterpartyAccountNumber,
+			&i.CounterpartyName,
 		); err != nil {
 			return nil, err
 		}
@@ -132,3 +306,62 @@ func (q *Queries) ListEntriesByAccountID(ctx context.Context, arg ListEntriesByA
 	}
 	return items, nil
 }
+
+const listUnbalancedAccounts = `-- name: ListUnbalancedAccounts :many
+SELECT
+  a.id,
+  a.balance,
+  COALESCE(SUM(e.amount), 0)::bigint AS entry_total
+FROM accounts AS a
+LEFT JOIN entries AS e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id
+`
+
+type ListUnbalancedAccountsRow struct {
+	ID         int64 `json:"id"`
+	Balance    int64 `json:"balance"`
+	EntryTotal int64 `json:"entry_total"`
+}
+
+// 余额应该等于全部分录之和，对不上的账户说明有绕过分录改余额的地方
+func (q *Queries) ListUnbalancedAccounts(ctx context.Context) ([]ListUnbalancedAccountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUnbalancedAccounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUnbalancedAccountsRow{}
+	for rows.Next() {
+		var i ListUnbalancedAccountsRow
+		if err := rows.Scan(&i.ID, &i.Balance, &i.EntryTotal); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumAccountEntriesSince = `-- name: SumAccountEntriesSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint FROM entries
+WHERE account_id = $1 AND created_at >= $2
+`
+
+type SumAccountEntriesSinceParams struct {
+	AccountID int64     `json:"account_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) SumAccountEntriesSince(ctx context.Context, arg SumAccountEntriesSinceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumAccountEntriesSince, arg.AccountID, arg.CreatedAt)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
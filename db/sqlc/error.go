@@ -0,0 +1,17 @@
+package db
+
+import "errors"
+
+// 事务内业务校验失败时返回的错误，调用方用 errors.Is 判断
+var (
+	ErrAccountFrozen              = errors.New("account is frozen")
+	ErrTransferLimitExceeded      = errors.New("monthly outgoing transfer limit exceeded")
+	ErrMinimumBalance             = errors.New("balance would fall below product minimum")
+	ErrInsufficientFunds          = errors.New("insufficient available balance including overdraft")
+	ErrPaymentRequestClosed       = errors.New("payment request is no longer pending")
+	ErrPaymentRequestExpired      = errors.New("payment request has expired")
+	ErrTransferNotPending         = errors.New("transfer is not pending approval")
+	ErrApprovalExpired            = errors.New("transfer approval has expired")
+	ErrExternalTransferNotAllowed = errors.New("account product does not allow external transfers")
+	ErrInvalidTransferTransition  = errors.New("invalid transfer status transition")
+)
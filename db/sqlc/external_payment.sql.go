@@ -0,0 +1,339 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: external_payment.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createExternalPayment = `-- name: CreateExternalPayment :one
+INSERT INTO external_payments (
+  transfer_id,
+  network,
+  creditor_account_number,
+  creditor_name,
+  creditor_routing_number
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING transfer_id, network, creditor_account_number, creditor_name, network_reference, failure_reason, settlement_transfer_id, reversal_transfer_id, submitted_at, settled_at, updated_at, created_at, creditor_routing_number, ach_file_id
+`
+
+type CreateExternalPaymentParams struct {
+	TransferID            int64  `json:"transfer_id"`
+	Network               string `json:"network"`
+	CreditorAccountNumber string `json:"creditor_account_number"`
+	CreditorName          string `json:"creditor_name"`
+	CreditorRoutingNumber string `json:"creditor_routing_number"`
+}
+
+func (q *Queries) CreateExternalPayment(ctx context.Context, arg CreateExternalPaymentParams) (ExternalPayment, error) {
+	row := q.db.QueryRowContext(ctx, createExternalPayment,
+		arg.TransferID,
+		arg.Network,
+		arg.CreditorAccountNumber,
+		arg.CreditorName,
+		arg.CreditorRoutingNumber,
+	)
+	var i ExternalPayment
+	err := row.Scan(
+		&i.TransferID,
+		&i.Network,
+		&i.CreditorAccountNumber,
+		&i.CreditorName,
+		&i.NetworkReference,
+		&i.FailureReason,
+		&i.SettlementTransferID,
+		&i.ReversalTransferID,
+		&i.SubmittedAt,
+		&i.SettledAt,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.CreditorRoutingNumber,
+		&i.AchFileID,
+	)
+	return i, err
+}
+
+const getExternalPayment = `-- name: GetExternalPayment :one
+SELECT transfer_id, network, creditor_account_number, creditor_name, network_reference, failure_reason, settlement_transfer_id, reversal_transfer_id, submitted_at, settled_at, updated_at, created_at, creditor_routing_number, ach_file_id FROM external_payments
+WHERE transfer_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetExternalPayment(ctx context.Context, transferID int64) (ExternalPayment, error) {
+	row := q.db.QueryRowContext(ctx, getExternalPayment, transferID)
+	var i ExternalPayment
+	err := row.Scan(
+		&i.TransferID,
+		&i.Network,
+		&i.CreditorAccountNumber,
+		&i.CreditorName,
+		&i.NetworkReference,
+		&i.FailureReason,
+		&i.SettlementTransferID,
+		&i.ReversalTransferID,
+		&i.SubmittedAt,
+		&i.SettledAt,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.CreditorRoutingNumber,
+		&i.AchFileID,
+	)
+	return i, err
+}
+
+const getExternalPaymentByReference = `-- name: GetExternalPaymentByReference :one
+SELECT transfer_id, network, creditor_account_number, creditor_name, network_reference, failure_reason, settlement_transfer_id, reversal_transfer_id, submitted_at, settled_at, updated_at, created_at, creditor_routing_number, ach_file_id FROM external_payments
+WHERE network = $1 AND network_reference = $2
+LIMIT 1
+`
+
+type GetExternalPaymentByReferenceParams struct {
+	Network          string         `json:"network"`
+	NetworkReference sql.NullString `json:"network_reference"`
+}
+
+func (q *Queries) GetExternalPaymentByReference(ctx context.Context, arg GetExternalPaymentByReferenceParams) (ExternalPayment, error) {
+	row := q.db.QueryRowContext(ctx, getExternalPaymentByReference, arg.Network, arg.NetworkReference)
+	var i ExternalPayment
+	err := row.Scan(
+		&i.TransferID,
+		&i.Network,
+		&i.CreditorAccountNumber,
+		&i.CreditorName,
+		&i.NetworkReference,
+		&i.FailureReason,
+		&i.SettlementTransferID,
+		&i.ReversalTransferID,
+		&i.SubmittedAt,
+		&i.SettledAt,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.CreditorRoutingNumber,
+		&i.AchFileID,
+	)
+	return i, err
+}
+
+const listInitiatedExternalPayments = `-- name: ListInitiatedExternalPayments :many
+SELECT external_payments.transfer_id, external_payments.network, external_payments.creditor_account_number, external_payments.creditor_name, external_payments.network_reference, external_payments.failure_reason, external_payments.settlement_transfer_id, external_payments.reversal_transfer_id, external_payments.submitted_at, external_payments.settled_at, external_payments.updated_at, external_payments.created_at, external_payments.creditor_routing_number, external_payments.ach_file_id, transfers.id, transfers.from_account_id, transfers.to_account_id, transfers.amount, transfers.created_at, transfers.memo, transfers.private_note, transfers.external_reference, transfers.metadata, transfers.fee, transfers.quote_id, transfers.status, transfers.hide_to_account
+FROM external_payments
+JOIN transfers ON transfers.id = external_payments.transfer_id
+WHERE external_payments.network = $1
+  AND transfers.status = 'initiated'
+ORDER BY external_payments.transfer_id
+`
+
+type ListInitiatedExternalPaymentsRow struct {
+	ExternalPayment ExternalPayment `json:"external_payment"`
+	Transfer        Transfer        `json:"transfer"`
+}
+
+func (q *Queries) ListInitiatedExternalPayments(ctx context.Context, network string) ([]ListInitiatedExternalPaymentsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listInitiatedExternalPayments, network)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListInitiatedExternalPaymentsRow{}
+	for rows.Next() {
+		var i ListInitiatedExternalPaymentsRow
+		if err := rows.Scan(
+			&i.ExternalPayment.TransferID,
+			&i.ExternalPayment.Network,
+			&i.ExternalPayment.CreditorAccountNumber,
+			&i.ExternalPayment.CreditorName,
+			&i.ExternalPayment.NetworkReference,
+			&i.ExternalPayment.FailureReason,
+			&i.ExternalPayment.SettlementTransferID,
+			&i.ExternalPayment.ReversalTransferID,
+			&i.ExternalPayment.SubmittedAt,
+			&i.ExternalPayment.SettledAt,
+			&i.ExternalPayment.UpdatedAt,
+			&i.ExternalPayment.CreatedAt,
+			&i.ExternalPayment.CreditorRoutingNumber,
+			&i.ExternalPayment.AchFileID,
+			&i.Transfer.ID,
+			&i.Transfer.FromAccountID,
+			&i.Transfer.ToAccountID,
+			&i.Transfer.Amount,
+			&i.Transfer.CreatedAt,
+			&i.Transfer.Memo,
+			&i.Transfer.PrivateNote,
+			&i.Transfer.ExternalReference,
+			&i.Transfer.Metadata,
+			&i.Transfer.Fee,
+			&i.Transfer.QuoteID,
+			&i.Transfer.Status,
+			&i.Transfer.HideToAccount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSettleableExternalPayments = `-- name: ListSettleableExternalPayments :many
+SELECT external_payments.transfer_id
+FROM external_payments
+JOIN transfers ON transfers.id = external_payments.transfer_id
+WHERE external_payments.network = $1
+  AND transfers.status = 'pending'
+  AND external_payments.submitted_at <= $2::timestamptz
+ORDER BY external_payments.transfer_id
+`
+
+type ListSettleableExternalPaymentsParams struct {
+	Network         string    `json:"network"`
+	SubmittedBefore time.Time `json:"submitted_before"`
+}
+
+// 提交后过了退回期限还没被退回的付款，可以认为已经清算
+func (q *Queries) ListSettleableExternalPayments(ctx context.Context, arg ListSettleableExternalPaymentsParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listSettleableExternalPayments, arg.Network, arg.SubmittedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var transfer_id int64
+		if err := rows.Scan(&transfer_id); err != nil {
+			return nil, err
+		}
+		items = append(items, transfer_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markExternalPaymentReversed = `-- name: MarkExternalPaymentReversed :one
+UPDATE external_payments
+SET
+  reversal_transfer_id = $2,
+  failure_reason = $3,
+  updated_at = now()
+WHERE transfer_id = $1
+RETURNING transfer_id, network, creditor_account_number, creditor_name, network_reference, failure_reason, settlement_transfer_id, reversal_transfer_id, submitted_at, settled_at, updated_at, created_at, creditor_routing_number, ach_file_id
+`
+
+type MarkExternalPaymentReversedParams struct {
+	TransferID         int64         `json:"transfer_id"`
+	ReversalTransferID sql.NullInt64 `json:"reversal_transfer_id"`
+	FailureReason      string        `json:"failure_reason"`
+}
+
+func (q *Queries) MarkExternalPaymentReversed(ctx context.Context, arg MarkExternalPaymentReversedParams) (ExternalPayment, error) {
+	row := q.db.QueryRowContext(ctx, markExternalPaymentReversed, arg.TransferID, arg.ReversalTransferID, arg.FailureReason)
+	var i ExternalPayment
+	err := row.Scan(
+		&i.TransferID,
+		&i.Network,
+		&i.CreditorAccountNumber,
+		&i.CreditorName,
+		&i.NetworkReference,
+		&i.FailureReason,
+		&i.SettlementTransferID,
+		&i.ReversalTransferID,
+		&i.SubmittedAt,
+		&i.SettledAt,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.CreditorRoutingNumber,
+		&i.AchFileID,
+	)
+	return i, err
+}
+
+const markExternalPaymentSettled = `-- name: MarkExternalPaymentSettled :one
+UPDATE external_payments
+SET
+  settlement_transfer_id = $2,
+  settled_at = now(),
+  updated_at = now()
+WHERE transfer_id = $1
+RETURNING transfer_id, network, creditor_account_number, creditor_name, network_reference, failure_reason, settlement_transfer_id, reversal_transfer_id, submitted_at, settled_at, updated_at, created_at, creditor_routing_number, ach_file_id
+`
+
+type MarkExternalPaymentSettledParams struct {
+	TransferID           int64         `json:"transfer_id"`
+	SettlementTransferID sql.NullInt64 `json:"settlement_transfer_id"`
+}
+
+func (q *Queries) MarkExternalPaymentSettled(ctx context.Context, arg MarkExternalPaymentSettledParams) (ExternalPayment, error) {
+	row := q.db.QueryRowContext(ctx, markExternalPaymentSettled, arg.TransferID, arg.SettlementTransferID)
+	var i ExternalPayment
+	err := row.Scan(
+		&i.TransferID,
+		&i.Network,
+		&i.CreditorAccountNumber,
+		&i.CreditorName,
+		&i.NetworkReference,
+		&i.FailureReason,
+		&i.SettlementTransferID,
+		&i.ReversalTransferID,
+		&i.SubmittedAt,
+		&i.SettledAt,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.CreditorRoutingNumber,
+		&i.AchFileID,
+	)
+	return i, err
+}
+
+const markExternalPaymentSubmitted = `-- name: MarkExternalPaymentSubmitted :one
+UPDATE external_payments
+SET
+  network_reference = $2,
+  ach_file_id = $3,
+  submitted_at = now(),
+  updated_at = now()
+WHERE transfer_id = $1
+RETURNING transfer_id, network, creditor_account_number, creditor_name, network_reference, failure_reason, settlement_transfer_id, reversal_transfer_id, submitted_at, settled_at, updated_at, created_at, creditor_routing_number, ach_file_id
+`
+
+type MarkExternalPaymentSubmittedParams struct {
+	TransferID       int64          `json:"transfer_id"`
+	NetworkReference sql.NullString `json:"network_reference"`
+	AchFileID        sql.NullInt64  `json:"ach_file_id"`
+}
+
+func (q *Queries) MarkExternalPaymentSubmitted(ctx context.Context, arg MarkExternalPaymentSubmittedParams) (ExternalPayment, error) {
+	row := q.db.QueryRowContext(ctx, markExternalPaymentSubmitted, arg.TransferID, arg.NetworkReference, arg.AchFileID)
+	var i ExternalPayment
+	err := row.Scan(
+		&i.TransferID,
+		&i.Network,
+		&i.CreditorAccountNumber,
+		&i.CreditorName,
+		&i.NetworkReference,
+		&i.FailureReason,
+		&i.SettlementTransferID,
+		&i.ReversalTransferID,
+		&i.SubmittedAt,
+		&i.SettledAt,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.CreditorRoutingNumber,
+		&i.AchFileID,
+	)
+	return i, err
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"simplebank/util"
+)
+
+// TransferType 转入清算账户的是转给他行，其余都是行内转账
+// 没有换汇，两边账户的币种在 API 层就要求一致
+func TransferType(fromAccount Account, toAccount Account) string {
+	if toAccount.Owner == util.BankUsername && toAccount.ProductType == util.ExternalClearingProduct {
+		return util.TransferTypeExternal
+	}
+	return util.TransferTypeInternal
+}
+
+// transferFee 按转账类型和转出账户的产品查费率，没有配置时不收费，银行系统账户转出的不收费
+func transferFee(ctx context.Context, q *Queries, fromAccount Account, toAccount Account, amount int64) (int64, error) {
+	if fromAccount.Owner == util.BankUsername {
+		return 0, nil
+	}
+
+	schedule, err := q.GetTransferFeeSchedule(ctx, GetTransferFeeScheduleParams{
+		TransferType: TransferType(fromAccount, toAccount),
+		ProductType:  fromAccount.ProductType,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return util.TransferFee(amount, schedule.FlatFee, schedule.PercentageBps, schedule.PercentageThreshold, schedule.MaxFee), nil
+}
+
+// postFee 把手续费记到对应币种的手续费收入账户，调用方负责从转出账户扣款
+func postFee(ctx context.Context, q *Queries, currency string, fee int64) error {
+	feeAccount, err := getOrCreateSystemAccount(ctx, q, currency, util.FeeIncomeProduct)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: feeAccount.ID,
+		Amount:    fee,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:     feeAccount.ID,
+		Amount: fee,
+	})
+	return err
+}
@@ -0,0 +1,146 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: fee_schedule.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createFeeSchedule = `-- name: CreateFeeSchedule :one
+INSERT INTO fee_schedules (
+  transfer_type,
+  product_type,
+  flat_fee,
+  percentage_bps,
+  percentage_threshold,
+  max_fee
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING id, transfer_type, product_type, flat_fee, percentage_bps, percentage_threshold, max_fee, created_at
+`
+
+type CreateFeeScheduleParams struct {
+	TransferType        string         `json:"transfer_type"`
+	ProductType         sql.NullString `json:"product_type"`
+	FlatFee             int64          `json:"flat_fee"`
+	PercentageBps       int32          `json:"percentage_bps"`
+	PercentageThreshold int64          `json:"percentage_threshold"`
+	MaxFee              int64          `json:"max_fee"`
+}
+
+func (q *Queries) CreateFeeSchedule(ctx context.Context, arg CreateFeeScheduleParams) (FeeSchedule, error) {
+	row := q.db.QueryRowContext(ctx, createFeeSchedule,
+		arg.TransferType,
+		arg.ProductType,
+		arg.FlatFee,
+		arg.PercentageBps,
+		arg.PercentageThreshold,
+		arg.MaxFee,
+	)
+	var i FeeSchedule
+	err := row.Scan(
+		&i.ID,
+		&i.TransferType,
+		&i.ProductType,
+		&i.FlatFee,
+		&i.PercentageBps,
+		&i.PercentageThreshold,
+		&i.MaxFee,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteFeeSchedule = `-- name: DeleteFeeSchedule :one
+DELETE FROM fee_schedules
+WHERE id = $1
+RETURNING id, transfer_type, product_type, flat_fee, percentage_bps, percentage_threshold, max_fee, created_at
+`
+
+func (q *Queries) DeleteFeeSchedule(ctx context.Context, id int64) (FeeSchedule, error) {
+	row := q.db.QueryRowContext(ctx, deleteFeeSchedule, id)
+	var i FeeSchedule
+	err := row.Scan(
+		&i.ID,
+		&i.TransferType,
+		&i.ProductType,
+		&i.FlatFee,
+		&i.PercentageBps,
+		&i.PercentageThreshold,
+		&i.MaxFee,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getTransferFeeSchedule = `-- name: GetTransferFeeSchedule :one
+SELECT id, transfer_type, product_type, flat_fee, percentage_bps, percentage_threshold, max_fee, created_at FROM fee_schedules
+WHERE transfer_type = $1
+  AND (product_type = $2::varchar OR product_type IS NULL)
+ORDER BY product_type NULLS LAST
+LIMIT 1
+`
+
+type GetTransferFeeScheduleParams struct {
+	TransferType string `json:"transfer_type"`
+	ProductType  string `json:"product_type"`
+}
+
+// 产品专属的配置优先于通用配置
+func (q *Queries) GetTransferFeeSchedule(ctx context.Context, arg GetTransferFeeScheduleParams) (FeeSchedule, error) {
+	row := q.db.QueryRowContext(ctx, getTransferFeeSchedule, arg.TransferType, arg.ProductType)
+	var i FeeSchedule
+	err := row.Scan(
+		&i.ID,
+		&i.TransferType,
+		&i.ProductType,
+		&i.FlatFee,
+		&i.PercentageBps,
+		&i.PercentageThreshold,
+		&i.MaxFee,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listFeeSchedules = `-- name: ListFeeSchedules :many
+SELECT id, transfer_type, product_type, flat_fee, percentage_bps, percentage_threshold, max_fee, created_at FROM fee_schedules
+ORDER BY transfer_type, product_type NULLS FIRST
+`
+
+func (q *Queries) ListFeeSchedules(ctx context.Context) ([]FeeSchedule, error) {
+	rows, err := q.db.QueryContext(ctx, listFeeSchedules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FeeSchedule{}
+	for rows.Next() {
+		var i FeeSchedule
+		if err := rows.Scan(
+			&i.ID,
+			&i.TransferType,
+			&i.ProductType,
+			&i.FlatFee,
+			&i.PercentageBps,
+			&i.PercentageThreshold,
+			&i.MaxFee,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,284 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: interest.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createInterestAccrual = `-- name: CreateInterestAccrual :one
+INSERT INTO interest_accruals (
+  account_id,
+  accrual_date,
+  balance,
+  annual_rate_bps,
+  day_count_convention,
+  amount_micros
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (account_id, accrual_date) DO NOTHING
+RETURNING id, account_id, accrual_date, balance, annual_rate_bps, day_count_convention, amount_micros, posting_id, created_at
+`
+
+type CreateInterestAccrualParams struct {
+	AccountID          int64     `json:"account_id"`
+	AccrualDate        time.Time `json:"accrual_date"`
+	Balance            int64     `json:"balance"`
+	AnnualRateBps      int32     `json:"annual_rate_bps"`
+	DayCountConvention string    `json:"day_count_convention"`
+	AmountMicros       int64     `json:"amount_micros"`
+}
+
+func (q *Queries) CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (InterestAccrual, error) {
+	row := q.db.QueryRowContext(ctx, createInterestAccrual,
+		arg.AccountID,
+		arg.AccrualDate,
+		arg.Balance,
+		arg.AnnualRateBps,
+		arg.DayCountConvention,
+		arg.AmountMicros,
+	)
+	var i InterestAccrual
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.AccrualDate,
+		&i.Balance,
+		&i.AnnualRateBps,
+		&i.DayCountConvention,
+		&i.AmountMicros,
+		&i.PostingID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createInterestPosting = `-- name: CreateInterestPosting :one
+INSERT INTO interest_postings (
+  account_id,
+  period,
+  accrued_micros,
+  amount,
+  residual_micros
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (account_id, period) DO NOTHING
+RETURNING id, account_id, period, accrued_micros, amount, transfer_id, created_at, residual_micros
+`
+
+type CreateInterestPostingParams struct {
+	AccountID      int64     `json:"account_id"`
+	Period         time.Time `json:"period"`
+	AccruedMicros  int64     `json:"accrued_micros"`
+	Amount         int64     `json:"amount"`
+	ResidualMicros int64     `json:"residual_micros"`
+}
+
+func (q *Queries) CreateInterestPosting(ctx context.Context, arg CreateInterestPostingParams) (InterestPosting, error) {
+	row := q.db.QueryRowContext(ctx, createInterestPosting,
+		arg.AccountID,
+		arg.Period,
+		arg.AccruedMicros,
+		arg.Amount,
+		arg.ResidualMicros,
+	)
+	var i InterestPosting
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Period,
+		&i.AccruedMicros,
+		&i.Amount,
+		&i.TransferID,
+		&i.CreatedAt,
+		&i.ResidualMicros,
+	)
+	return i, err
+}
+
+const getLatestInterestPosting = `-- name: GetLatestInterestPosting :one
+SELECT id, account_id, period, accrued_micros, amount, transfer_id, created_at, residual_micros FROM interest_postings
+WHERE account_id = $1
+AND period < $2
+ORDER BY period DESC
+LIMIT 1
+`
+
+type GetLatestInterestPostingParams struct {
+	AccountID    int64     `json:"account_id"`
+	BeforePeriod time.Time `json:"before_period"`
+}
+
+func (q *Queries) GetLatestInterestPosting(ctx context.Context, arg GetLatestInterestPostingParams) (InterestPosting, error) {
+	row := q.db.QueryRowContext(ctx, getLatestInterestPosting, arg.AccountID, arg.BeforePeriod)
+	var i InterestPosting
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Period,
+		&i.AccruedMicros,
+		&i.Amount,
+		&i.TransferID,
+		&i.CreatedAt,
+		&i.ResidualMicros,
+	)
+	return i, err
+}
+
+const listAccountsWithUnpostedInterest = `-- name: ListAccountsWithUnpostedInterest :many
+SELECT DISTINCT account_id FROM interest_accruals
+WHERE posting_id IS NULL
+AND accrual_date < $1
+ORDER BY account_id
+`
+
+func (q *Queries) ListAccountsWithUnpostedInterest(ctx context.Context, periodEnd time.Time) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountsWithUnpostedInterest, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var account_id int64
+		if err := rows.Scan(&account_id); err != nil {
+			return nil, err
+		}
+		items = append(items, account_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInterestBearingAccounts = `-- name: ListInterestBearingAccounts :many
+SELECT
+  a.id,
+  a.currency,
+  p.annual_interest_rate_bps,
+  p.day_count_convention,
+  (a.balance - COALESCE((
+    SELECT SUM(e.amount) FROM entries e
+    WHERE e.account_id = a.id AND e.created_at >= $1
+  ), 0))::bigint AS end_of_day_balance
+FROM accounts a
+JOIN account_products p ON p.code = a.product_type
+WHERE p.annual_interest_rate_bps > 0
+AND a.created_at < $1
+ORDER BY a.id
+`
+
+type ListInterestBearingAccountsRow struct {
+	ID                    int64  `json:"id"`
+	Currency              string `json:"currency"`
+	AnnualInterestRateBps int32  `json:"annual_interest_rate_bps"`
+	DayCountConvention    string `json:"day_count_convention"`
+	EndOfDayBalance       int64  `json:"end_of_day_balance"`
+}
+
+// 日终余额 = 当前余额 减去 日终之后发生的分录
+func (q *Queries) ListInterestBearingAccounts(ctx context.Context, endOfDay time.Time) ([]ListInterestBearingAccountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listInterestBearingAccounts, endOfDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListInterestBearingAccountsRow{}
+	for rows.Next() {
+		var i ListInterestBearingAccountsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.AnnualInterestRateBps,
+			&i.DayCountConvention,
+			&i.EndOfDayBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markInterestAccrualsPosted = `-- name: MarkInterestAccrualsPosted :exec
+UPDATE interest_accruals
+SET posting_id = $1
+WHERE account_id = $2
+AND posting_id IS NULL
+AND accrual_date < $3
+`
+
+type MarkInterestAccrualsPostedParams struct {
+	PostingID sql.NullInt64 `json:"posting_id"`
+	AccountID int64         `json:"account_id"`
+	PeriodEnd time.Time     `json:"period_end"`
+}
+
+func (q *Queries) MarkInterestAccrualsPosted(ctx context.Context, arg MarkInterestAccrualsPostedParams) error {
+	_, err := q.db.ExecContext(ctx, markInterestAccrualsPosted, arg.PostingID, arg.AccountID, arg.PeriodEnd)
+	return err
+}
+
+const sumUnpostedInterest = `-- name: SumUnpostedInterest :one
+SELECT COALESCE(SUM(amount_micros), 0)::bigint FROM interest_accruals
+WHERE account_id = $1
+AND posting_id IS NULL
+AND accrual_date < $2
+`
+
+type SumUnpostedInterestParams struct {
+	AccountID int64     `json:"account_id"`
+	PeriodEnd time.Time `json:"period_end"`
+}
+
+func (q *Queries) SumUnpostedInterest(ctx context.Context, arg SumUnpostedInterestParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumUnpostedInterest, arg.AccountID, arg.PeriodEnd)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const updateInterestPostingTransfer = `-- name: UpdateInterestPostingTransfer :one
+UPDATE interest_postings
+SET transfer_id = $2
+WHERE id = $1
+RETURNING id, account_id, period, accrued_micros, amount, transfer_id, created_at, residual_micros
+`
+
+type UpdateInterestPostingTransferParams struct {
+	ID         int64         `json:"id"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+}
+
+func (q *Queries) UpdateInterestPostingTransfer(ctx context.Context, arg UpdateInterestPostingTransferParams) (InterestPosting, error) {
+	row := q.db.QueryRowContext(ctx, updateInterestPostingTransfer, arg.ID, arg.TransferID)
+	var i InterestPosting
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Period,
+		&i.AccruedMicros,
+		&i.Amount,
+		&i.TransferID,
+		&i.CreatedAt,
+		&i.ResidualMicros,
+	)
+	return i, err
+}
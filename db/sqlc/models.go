@@ -5,25 +5,222 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Account struct {
-	ID        int64     `json:"id"`
-	Owner     string    `json:"owner"`
-	Balance   int64     `json:"balance"`
-	Currency  string    `json:"currency"`
+	ID             int64     `json:"id"`
+	Owner          string    `json:"owner"`
+	Balance        int64     `json:"balance"`
+	Currency       string    `json:"currency"`
+	CreatedAt      time.Time `json:"created_at"`
+	FreezeStatus   string    `json:"freeze_status"`
+	ProductType    string    `json:"product_type"`
+	OverdraftLimit int64     `json:"overdraft_limit"`
+	// external IBAN-style account number with mod-97 check digits
+	AccountNumber string `json:"account_number"`
+	// reserved by transfers waiting for approval, not available for spending
+	HeldAmount int64 `json:"held_amount"`
+}
+
+type AccountFreeze struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	// status after the change, active means unfrozen
+	FreezeStatus string    `json:"freeze_status"`
+	Reason       string    `json:"reason"`
+	Actor        string    `json:"actor"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type AccountMember struct {
+	AccountID int64     `json:"account_id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type AccountProduct struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// null means unlimited
+	MaxMonthlyOutgoingTransfers sql.NullInt32 `json:"max_monthly_outgoing_transfers"`
+	MinBalance                  int64         `json:"min_balance"`
+	AllowExternalTransfers      bool          `json:"allow_external_transfers"`
+	CreatedAt                   time.Time     `json:"created_at"`
+	AnnualInterestRateBps       int32         `json:"annual_interest_rate_bps"`
+	DayCountConvention          string        `json:"day_count_convention"`
+	OverdraftInterestRateBps    int32         `json:"overdraft_interest_rate_bps"`
+}
+
+type AchFile struct {
+	ID             int64     `json:"id"`
+	FileIDModifier string    `json:"file_id_modifier"`
+	EntryCount     int32     `json:"entry_count"`
+	TotalCredit    int64     `json:"total_credit"`
+	Content        string    `json:"content"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
 type Entry struct {
 	ID        int64 `json:"id"`
 	AccountID int64 `json:"account_id"`
 	// can be positive or negative
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// null for postings that are not part of a transfer, such as interest
+	TransferID sql.NullInt64 `json:"transfer_id"`
+}
+
+type ExternalPayment struct {
+	// customer to clearing account transfer made at initiation
+	TransferID            int64          `json:"transfer_id"`
+	Network               string         `json:"network"`
+	CreditorAccountNumber string         `json:"creditor_account_number"`
+	CreditorName          string         `json:"creditor_name"`
+	NetworkReference      sql.NullString `json:"network_reference"`
+	FailureReason         string         `json:"failure_reason"`
+	// clearing to settlement account transfer made when the network settles
+	SettlementTransferID sql.NullInt64 `json:"settlement_transfer_id"`
+	// refund to the customer when the payment fails or is returned
+	ReversalTransferID    sql.NullInt64 `json:"reversal_transfer_id"`
+	SubmittedAt           sql.NullTime  `json:"submitted_at"`
+	SettledAt             sql.NullTime  `json:"settled_at"`
+	UpdatedAt             time.Time     `json:"updated_at"`
+	CreatedAt             time.Time     `json:"created_at"`
+	CreditorRoutingNumber string        `json:"creditor_routing_number"`
+	// batch file the payment was exported in, for batch networks only
+	AchFileID sql.NullInt64 `json:"ach_file_id"`
+}
+
+type FeeSchedule struct {
+	ID           int64  `json:"id"`
+	TransferType string `json:"transfer_type"`
+	// product of the sending account, NULL applies to every product
+	ProductType   sql.NullString `json:"product_type"`
+	FlatFee       int64          `json:"flat_fee"`
+	PercentageBps int32          `json:"percentage_bps"`
+	// percentage is charged on the part of the amount above this
+	PercentageThreshold int64 `json:"percentage_threshold"`
+	// 0 means no cap
+	MaxFee    int64     `json:"max_fee"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type InterestAccrual struct {
+	ID                 int64     `json:"id"`
+	AccountID          int64     `json:"account_id"`
+	AccrualDate        time.Time `json:"accrual_date"`
+	Balance            int64     `json:"balance"`
+	AnnualRateBps      int32     `json:"annual_rate_bps"`
+	DayCountConvention string    `json:"day_count_convention"`
+	// millionths of a minor unit
+	AmountMicros int64         `json:"amount_micros"`
+	PostingID    sql.NullInt64 `json:"posting_id"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+type InterestPosting struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	// first day of the posted month
+	Period time.Time `json:"period"`
+	// interest accrued in the period plus residual carried from the previous posting
+	AccruedMicros int64 `json:"accrued_micros"`
+	// whole minor units, rounded down from accrued_micros
+	Amount     int64         `json:"amount"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+	// sub minor unit remainder carried to the next posting
+	ResidualMicros int64 `json:"residual_micros"`
+}
+
+// tasks written in the same transaction as the business change, published to the task queue by the relay
+type Outbox struct {
+	ID       int64           `json:"id"`
+	TaskType string          `json:"task_type"`
+	Payload  json.RawMessage `json:"payload"`
+	Queue    string          `json:"queue"`
+	MaxRetry int32           `json:"max_retry"`
+	// null to process as soon as published
+	ProcessAt sql.NullTime `json:"process_at"`
+	// failed publish attempts
+	Attempts    int32        `json:"attempts"`
+	LastError   string       `json:"last_error"`
+	PublishedAt sql.NullTime `json:"published_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type OverdraftCharge struct {
+	ID            int64     `json:"id"`
+	AccountID     int64     `json:"account_id"`
+	ChargeDate    time.Time `json:"charge_date"`
+	Balance       int64     `json:"balance"`
+	AnnualRateBps int32     `json:"annual_rate_bps"`
+	// interest of the day plus residual carried from the previous charge
+	AmountMicros int64 `json:"amount_micros"`
+	Amount       int64 `json:"amount"`
+	// sub minor unit remainder carried to the next charge
+	ResidualMicros int64         `json:"residual_micros"`
+	TransferID     sql.NullInt64 `json:"transfer_id"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+type Payee struct {
+	ID        int64  `json:"id"`
+	Owner     string `json:"owner"`
+	AccountID int64  `json:"account_id"`
+	Nickname  string `json:"nickname"`
+	// full name of the account owner when the payee was verified
+	HolderName string    `json:"holder_name"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type PaymentImport struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// GrpHdr/MsgId of the pain.001 file, unique per user
+	MessageID        string    `json:"message_id"`
+	InstructionCount int32     `json:"instruction_count"`
+	AcceptedCount    int32     `json:"accepted_count"`
+	StatusReport     string    `json:"status_report"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type PaymentRequest struct {
+	ID        int64  `json:"id"`
+	Requester string `json:"requester"`
+	Payer     string `json:"payer"`
+	// requester account that receives the money
+	ToAccountID int64  `json:"to_account_id"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	Memo        string `json:"memo"`
+	Status      string `json:"status"`
+	// set when the payer accepts the request, points to a pending transfer while it awaits approval
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+// each cron slot of a job is enqueued once, even when the leader changes
+type PeriodicJobRun struct {
+	Job         string    `json:"job"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// leader election for the periodic scheduler, only the holder of an unexpired lease enqueues jobs
+type SchedulerLease struct {
+	Name       string    `json:"name"`
+	Holder     string    `json:"holder"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	AcquiredAt time.Time `json:"acquired_at"`
 }
 
 type Session struct {
@@ -37,6 +234,50 @@ type Session struct {
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+// ledger of worker task steps that already succeeded, retries of the same task skip them
+type TaskExecution struct {
+	TaskID string `json:"task_id"`
+	// completed for the whole task, otherwise a step inside the handler
+	Step      string          `json:"step"`
+	Result    json.RawMessage `json:"result"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// one row per failed attempt of a worker task, on every task queue backend
+type TaskFailure struct {
+	ID       int64  `json:"id"`
+	TaskID   string `json:"task_id"`
+	TaskType string `json:"task_type"`
+	Queue    string `json:"queue"`
+	Error    string `json:"error"`
+	Retried  int32  `json:"retried"`
+	MaxRetry int32  `json:"max_retry"`
+	// the task will not be retried, it needs an operator to run or delete it
+	Archived  bool      `json:"archived"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// task queue backend for deployments without redis, workers claim tasks with SKIP LOCKED
+type TaskQueue struct {
+	ID int64 `json:"id"`
+	// duplicate task ids are rejected while the row exists
+	TaskID   string          `json:"task_id"`
+	TaskType string          `json:"task_type"`
+	Payload  json.RawMessage `json:"payload"`
+	Queue    string          `json:"queue"`
+	// pending, active, completed or archived
+	State     string    `json:"state"`
+	Retried   int32     `json:"retried"`
+	MaxRetry  int32     `json:"max_retry"`
+	ProcessAt time.Time `json:"process_at"`
+	// active tasks whose lease expired are claimed again, the worker probably crashed
+	LeaseExpiresAt sql.NullTime `json:"lease_expires_at"`
+	LastError      string       `json:"last_error"`
+	LastFailedAt   sql.NullTime `json:"last_failed_at"`
+	CompletedAt    sql.NullTime `json:"completed_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
 type Transfer struct {
 	ID            int64 `json:"id"`
 	FromAccountID int64 `json:"from_account_id"`
@@ -44,6 +285,28 @@ type Transfer struct {
 	// must be positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// visible to both parties
+	Memo string `json:"memo"`
+	// visible to the sender only
+	PrivateNote       string          `json:"private_note"`
+	ExternalReference string          `json:"external_reference"`
+	Metadata          json.RawMessage `json:"metadata"`
+	// charged to the sender on top of amount
+	Fee int64 `json:"fee"`
+	// quote whose fee was honoured, each quote can be used once
+	QuoteID uuid.NullUUID `json:"quote_id"`
+	Status  string        `json:"status"`
+	// addressed by recipient username or email, to_account_id is not shown to the sender
+	HideToAccount bool `json:"hide_to_account"`
+}
+
+type TransferApproval struct {
+	TransferID  int64          `json:"transfer_id"`
+	RequestedBy string         `json:"requested_by"`
+	DecidedBy   sql.NullString `json:"decided_by"`
+	ExpiresAt   time.Time      `json:"expires_at"`
+	DecidedAt   sql.NullTime   `json:"decided_at"`
+	CreatedAt   time.Time      `json:"created_at"`
 }
 
 type User struct {
@@ -54,6 +317,7 @@ type User struct {
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
 	IsEmailVerified   bool      `json:"is_email_verified"`
+	Role              string    `json:"role"`
 }
 
 type VerifyEmail struct {
@@ -65,3 +329,29 @@ type VerifyEmail struct {
 	CreatedAt  time.Time `json:"created_at"`
 	ExpiredAt  time.Time `json:"expired_at"`
 }
+
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	// pending, succeeded or failed after the last retry
+	Status   string `json:"status"`
+	Attempts int32  `json:"attempts"`
+	// HTTP status of the last attempt, 0 if the receiver was unreachable
+	ResponseStatus int32        `json:"response_status"`
+	LastError      string       `json:"last_error"`
+	CreatedAt      time.Time    `json:"created_at"`
+	DeliveredAt    sql.NullTime `json:"delivered_at"`
+}
+
+type WebhookSubscription struct {
+	ID         int64    `json:"id"`
+	Owner      string   `json:"owner"`
+	Url        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	// HMAC-SHA256 signing key, only shown to the owner when the subscription is created
+	Secret    string    `json:"secret"`
+	CreatedAt time.Time `json:"created_at"`
+}
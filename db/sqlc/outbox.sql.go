@@ -0,0 +1,166 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: outbox.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+const createOutboxMessage = `-- name: CreateOutboxMessage :one
+INSERT INTO outbox (
+  task_type,
+  payload,
+  queue,
+  max_retry,
+  process_at
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING id, task_type, payload, queue, max_retry, process_at, attempts, last_error, published_at, created_at
+`
+
+type CreateOutboxMessageParams struct {
+	TaskType  string          `json:"task_type"`
+	Payload   json.RawMessage `json:"payload"`
+	Queue     string          `json:"queue"`
+	MaxRetry  int32           `json:"max_retry"`
+	ProcessAt sql.NullTime    `json:"process_at"`
+}
+
+func (q *Queries) CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) (Outbox, error) {
+	row := q.db.QueryRowContext(ctx, createOutboxMessage,
+		arg.TaskType,
+		arg.Payload,
+		arg.Queue,
+		arg.MaxRetry,
+		arg.ProcessAt,
+	)
+	var i Outbox
+	err := row.Scan(
+		&i.ID,
+		&i.TaskType,
+		&i.Payload,
+		&i.Queue,
+		&i.MaxRetry,
+		&i.ProcessAt,
+		&i.Attempts,
+		&i.LastError,
+		&i.PublishedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deletePublishedOutboxMessages = `-- name: DeletePublishedOutboxMessages :execrows
+DELETE FROM outbox
+WHERE published_at < $1::timestamptz
+`
+
+func (q *Queries) DeletePublishedOutboxMessages(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePublishedOutboxMessages, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getOutboxMessage = `-- name: GetOutboxMessage :one
+SELECT id, task_type, payload, queue, max_retry, process_at, attempts, last_error, published_at, created_at FROM outbox
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetOutboxMessage(ctx context.Context, id int64) (Outbox, error) {
+	row := q.db.QueryRowContext(ctx, getOutboxMessage, id)
+	var i Outbox
+	err := row.Scan(
+		&i.ID,
+		&i.TaskType,
+		&i.Payload,
+		&i.Queue,
+		&i.MaxRetry,
+		&i.ProcessAt,
+		&i.Attempts,
+		&i.LastError,
+		&i.PublishedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listUnpublishedOutboxMessages = `-- name: ListUnpublishedOutboxMessages :many
+SELECT id, task_type, payload, queue, max_retry, process_at, attempts, last_error, published_at, created_at FROM outbox
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED
+`
+
+// 锁住待投递的消息，多个 relay 同时跑时互相跳过
+func (q *Queries) ListUnpublishedOutboxMessages(ctx context.Context, limit int32) ([]Outbox, error) {
+	rows, err := q.db.QueryContext(ctx, listUnpublishedOutboxMessages, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Outbox{}
+	for rows.Next() {
+		var i Outbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskType,
+			&i.Payload,
+			&i.Queue,
+			&i.MaxRetry,
+			&i.ProcessAt,
+			&i.Attempts,
+			&i.LastError,
+			&i.PublishedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOutboxMessagePublished = `-- name: MarkOutboxMessagePublished :exec
+UPDATE outbox
+SET published_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) MarkOutboxMessagePublished(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markOutboxMessagePublished, id)
+	return err
+}
+
+const recordOutboxMessageFailure = `-- name: RecordOutboxMessageFailure :exec
+UPDATE outbox
+SET
+  attempts = attempts + 1,
+  last_error = $2
+WHERE id = $1
+`
+
+type RecordOutboxMessageFailureParams struct {
+	ID        int64  `json:"id"`
+	LastError string `json:"last_error"`
+}
+
+func (q *Queries) RecordOutboxMessageFailure(ctx context.Context, arg RecordOutboxMessageFailureParams) error {
+	_, err := q.db.ExecContext(ctx, recordOutboxMessageFailure, arg.ID, arg.LastError)
+	return err
+}
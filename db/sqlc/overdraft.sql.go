@@ -0,0 +1,206 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: overdraft.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createOverdraftCharge = `-- name: CreateOverdraftCharge :one
+INSERT INTO overdraft_charges (
+  account_id,
+  charge_date,
+  balance,
+  annual_rate_bps,
+  amount_micros,
+  amount,
+  residual_micros
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (account_id, charge_date) DO NOTHING
+RETURNING id, account_id, charge_date, balance, annual_rate_bps, amount_micros, amount, residual_micros, transfer_id, created_at
+`
+
+type CreateOverdraftChargeParams struct {
+	AccountID      int64     `json:"account_id"`
+	ChargeDate     time.Time `json:"charge_date"`
+	Balance        int64     `json:"balance"`
+	AnnualRateBps  int32     `json:"annual_rate_bps"`
+	AmountMicros   int64     `json:"amount_micros"`
+	Amount         int64     `json:"amount"`
+	ResidualMicros int64     `json:"residual_micros"`
+}
+
+func (q *Queries) CreateOverdraftCharge(ctx context.Context, arg CreateOverdraftChargeParams) (OverdraftCharge, error) {
+	row := q.db.QueryRowContext(ctx, createOverdraftCharge,
+		arg.AccountID,
+		arg.ChargeDate,
+		arg.Balance,
+		arg.AnnualRateBps,
+		arg.AmountMicros,
+		arg.Amount,
+		arg.ResidualMicros,
+	)
+	var i OverdraftCharge
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ChargeDate,
+		&i.Balance,
+		&i.AnnualRateBps,
+		&i.AmountMicros,
+		&i.Amount,
+		&i.ResidualMicros,
+		&i.TransferID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLatestOverdraftCharge = `-- name: GetLatestOverdraftCharge :one
+SELECT id, account_id, charge_date, balance, annual_rate_bps, amount_micros, amount, residual_micros, transfer_id, created_at FROM overdraft_charges
+WHERE account_id = $1
+ORDER BY charge_date DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestOverdraftCharge(ctx context.Context, accountID int64) (OverdraftCharge, error) {
+	row := q.db.QueryRowContext(ctx, getLatestOverdraftCharge, accountID)
+	var i OverdraftCharge
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ChargeDate,
+		&i.Balance,
+		&i.AnnualRateBps,
+		&i.AmountMicros,
+		&i.Amount,
+		&i.ResidualMicros,
+		&i.TransferID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listOverdrawnAccounts = `-- name: ListOverdrawnAccounts :many
+SELECT id, currency, overdraft_interest_rate_bps, day_count_convention, end_of_day_balance FROM (
+  SELECT
+    a.id,
+    a.currency,
+    p.overdraft_interest_rate_bps,
+    p.day_count_convention,
+    (a.balance - COALESCE((
+      SELECT SUM(e.amount) FROM entries e
+      WHERE e.account_id = a.id AND e.created_at >= $1
+    ), 0))::bigint AS end_of_day_balance
+  FROM accounts a
+  JOIN account_products p ON p.code = a.product_type
+  WHERE p.overdraft_interest_rate_bps > 0
+  AND a.owner <> 'bank'
+) overdrawn
+WHERE end_of_day_balance < 0
+ORDER BY id
+`
+
+type ListOverdrawnAccountsRow struct {
+	ID                       int64  `json:"id"`
+	Currency                 string `json:"currency"`
+	OverdraftInterestRateBps int32  `json:"overdraft_interest_rate_bps"`
+	DayCountConvention       string `json:"day_count_convention"`
+	EndOfDayBalance          int64  `json:"end_of_day_balance"`
+}
+
+func (q *Queries) ListOverdrawnAccounts(ctx context.Context, endOfDay time.Time) ([]ListOverdrawnAccountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOverdrawnAccounts, endOfDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOverdrawnAccountsRow{}
+	for rows.Next() {
+		var i ListOverdrawnAccountsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.OverdraftInterestRateBps,
+			&i.DayCountConvention,
+			&i.EndOfDayBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateAccountOverdraftLimit = `-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $2
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, freeze_status, product_type, overdraft_limit, account_number, held_amount
+`
+
+type UpdateAccountOverdraftLimitParams struct {
+	ID             int64 `json:"id"`
+	OverdraftLimit int64 `json:"overdraft_limit"`
+}
+
+func (q *Queries) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountOverdraftLimit, arg.ID, arg.OverdraftLimit)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.FreezeStatus,
+		&i.ProductType,
+		&i.OverdraftLimit,
+		&i.AccountNumber,
+		&i.HeldAmount,
+	)
+	return i, err
+}
+
+const updateOverdraftChargeTransfer = `-- name: UpdateOverdraftChargeTransfer :one
+UPDATE overdraft_charges
+SET transfer_id = $2
+WHERE id = $1
+RETURNING id, account_id, charge_date, balance, annual_rate_bps, amount_micros, amount, residual_micros, transfer_id, created_at
+`
+
+type UpdateOverdraftChargeTransferParams struct {
+	ID         int64         `json:"id"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+}
+
+func (q *Queries) UpdateOverdraftChargeTransfer(ctx context.Context, arg UpdateOverdraftChargeTransferParams) (OverdraftCharge, error) {
+	row := q.db.QueryRowContext(ctx, updateOverdraftChargeTransfer, arg.ID, arg.TransferID)
+	var i OverdraftCharge
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ChargeDate,
+		&i.Balance,
+		&i.AnnualRateBps,
+		&i.AmountMicros,
+		&i.Amount,
+		&i.ResidualMicros,
+		&i.TransferID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,147 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: payee.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createPayee = `-- name: CreatePayee :one
+INSERT INTO payees (
+  owner,
+  account_id,
+  nickname,
+  holder_name
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, owner, account_id, nickname, holder_name, created_at
+`
+
+type CreatePayeeParams struct {
+	Owner      string `json:"owner"`
+	AccountID  int64  `json:"account_id"`
+	Nickname   string `json:"nickname"`
+	HolderName string `json:"holder_name"`
+}
+
+func (q *Queries) CreatePayee(ctx context.Context, arg CreatePayeeParams) (Payee, error) {
+	row := q.db.QueryRowContext(ctx, createPayee,
+		arg.Owner,
+		arg.AccountID,
+		arg.Nickname,
+		arg.HolderName,
+	)
+	var i Payee
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.AccountID,
+		&i.Nickname,
+		&i.HolderName,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deletePayee = `-- name: DeletePayee :exec
+DELETE FROM payees
+WHERE id = $1
+`
+
+func (q *Queries) DeletePayee(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePayee, id)
+	return err
+}
+
+const getPayee = `-- name: GetPayee :one
+SELECT id, owner, account_id, nickname, holder_name, created_at FROM payees
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetPayee(ctx context.Context, id int64) (Payee, error) {
+	row := q.db.QueryRowContext(ctx, getPayee, id)
+	var i Payee
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.AccountID,
+		&i.Nickname,
+		&i.HolderName,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listPayees = `-- name: ListPayees :many
+SELECT id, owner, account_id, nickname, holder_name, created_at FROM payees
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3
+`
+
+type ListPayeesParams struct {
+	Owner  string `json:"owner"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+}
+
+func (q *Queries) ListPayees(ctx context.Context, arg ListPayeesParams) ([]Payee, error) {
+	rows, err := q.db.QueryContext(ctx, listPayees, arg.Owner, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Payee{}
+	for rows.Next() {
+		var i Payee
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.AccountID,
+			&i.Nickname,
+			&i.HolderName,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updatePayeeNickname = `-- name: UpdatePayeeNickname :one
+UPDATE payees
+SET nickname = $2
+WHERE id = $1
+RETURNING id, owner, account_id, nickname, holder_name, created_at
+`
+
+type UpdatePayeeNicknameParams struct {
+	ID       int64  `json:"id"`
+	Nickname string `json:"nickname"`
+}
+
+func (q *Queries) UpdatePayeeNickname(ctx context.Context, arg UpdatePayeeNicknameParams) (Payee, error) {
+	row := q.db.QueryRowContext(ctx, updatePayeeNickname, arg.ID, arg.Nickname)
+	var i Payee
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.AccountID,
+		&i.Nickname,
+		&i.HolderName,
+		&i.CreatedAt,
+	)
+	return i, err
+}
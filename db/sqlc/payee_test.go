@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"simplebank/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPayee(t *testing.T, owner string) Payee {
+	account := createRandomAccount(t)
+
+	arg := CreatePayeeParams{
+		Owner:      owner,
+		AccountID:  account.ID,
+		Nickname:   util.RandomOwner(),
+		HolderName: util.RandomOwner(),
+	}
+	payee, err := testQueries.CreatePayee(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, payee)
+
+	require.Equal(t, arg.Owner, payee.Owner)
+	require.Equal(t, arg.AccountID, payee.AccountID)
+	require.Equal(t, arg.Nickname, payee.Nickname)
+	require.Equal(t, arg.HolderName, payee.HolderName)
+	require.NotZero(t, payee.CreatedAt)
+
+	return payee
+}
+
+func TestCreatePayee(t *testing.T) {
+	user := createRandomUser(t)
+	payee := createRandomPayee(t, user.Username)
+
+	// 同一个账户不能重复保存
+	_, err := testQueries.CreatePayee(context.Background(), CreatePayeeParams{
+		Owner:      user.Username,
+		AccountID:  payee.AccountID,
+		Nickname:   util.RandomOwner(),
+		HolderName: payee.HolderName,
+	})
+	require.Error(t, err)
+}
+
+func TestUpdatePayeeNickname(t *testing.T) {
+	user := createRandomUser(t)
+	payee := createRandomPayee(t, user.Username)
+
+	nickname := util.RandomOwner()
+	payee1, err := testQueries.UpdatePayeeNickname(context.Background(), UpdatePayeeNicknameParams{
+		ID:       payee.ID,
+		Nickname: nickname,
+	})
+	require.NoError(t, err)
+	require.Equal(t, nickname, payee1.Nickname)
+	require.Equal(t, payee.AccountID, payee1.AccountID)
+}
+
+func TestListPayees(t *testing.T) {
+	user := createRandomUser(t)
+	for i := 0; i < 3; i++ {
+		createRandomPayee(t, user.Username)
+	}
+
+	payees, err := testQueries.ListPayees(context.Background(), ListPayeesParams{
+		Owner:  user.Username,
+		Limit:  5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, payees, 3)
+	for _, payee := range payees {
+		require.Equal(t, user.Username, payee.Owner)
+	}
+}
+
+func TestDeletePayee(t *testing.T) {
+	user := createRandomUser(t)
+	payee := createRandomPayee(t, user.Username)
+
+	err := testQueries.DeletePayee(context.Background(), payee.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetPayee(context.Background(), payee.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
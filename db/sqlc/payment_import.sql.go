@@ -0,0 +1,108 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: payment_import.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createPaymentImport = `-- name: CreatePaymentImport :one
+INSERT INTO payment_imports (
+  username,
+  message_id,
+  instruction_count,
+  accepted_count,
+  status_report
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING id, username, message_id, instruction_count, accepted_count, status_report, created_at
+`
+
+type CreatePaymentImportParams struct {
+	Username         string `json:"username"`
+	MessageID        string `json:"message_id"`
+	InstructionCount int32  `json:"instruction_count"`
+	AcceptedCount    int32  `json:"accepted_count"`
+	StatusReport     string `json:"status_report"`
+}
+
+func (q *Queries) CreatePaymentImport(ctx context.Context, arg CreatePaymentImportParams) (PaymentImport, error) {
+	row := q.db.QueryRowContext(ctx, createPaymentImport,
+		arg.Username,
+		arg.MessageID,
+		arg.InstructionCount,
+		arg.AcceptedCount,
+		arg.StatusReport,
+	)
+	var i PaymentImport
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.MessageID,
+		&i.InstructionCount,
+		&i.AcceptedCount,
+		&i.StatusReport,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPaymentImport = `-- name: GetPaymentImport :one
+SELECT id, username, message_id, instruction_count, accepted_count, status_report, created_at FROM payment_imports
+WHERE username = $1 AND message_id = $2
+LIMIT 1
+`
+
+type GetPaymentImportParams struct {
+	Username  string `json:"username"`
+	MessageID string `json:"message_id"`
+}
+
+func (q *Queries) GetPaymentImport(ctx context.Context, arg GetPaymentImportParams) (PaymentImport, error) {
+	row := q.db.QueryRowContext(ctx, getPaymentImport, arg.Username, arg.MessageID)
+	var i PaymentImport
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.MessageID,
+		&i.InstructionCount,
+		&i.AcceptedCount,
+		&i.StatusReport,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updatePaymentImportResult = `-- name: UpdatePaymentImportResult :one
+UPDATE payment_imports
+SET
+  accepted_count = $2,
+  status_report = $3
+WHERE id = $1
+RETURNING id, username, message_id, instruction_count, accepted_count, status_report, created_at
+`
+
+type UpdatePaymentImportResultParams struct {
+	ID            int64  `json:"id"`
+	AcceptedCount int32  `json:"accepted_count"`
+	StatusReport  string `json:"status_report"`
+}
+
+func (q *Queries) UpdatePaymentImportResult(ctx context.Context, arg UpdatePaymentImportResultParams) (PaymentImport, error) {
+	row := q.db.QueryRowContext(ctx, updatePaymentImportResult, arg.ID, arg.AcceptedCount, arg.StatusReport)
+	var i PaymentImport
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.MessageID,
+		&i.InstructionCount,
+		&i.AcceptedCount,
+		&i.StatusReport,
+		&i.CreatedAt,
+	)
+	return i, err
+}
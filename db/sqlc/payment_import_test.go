@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"simplebank/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentImport(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := CreatePaymentImportParams{
+		Username:         user.Username,
+		MessageID:        util.RandomString(12),
+		InstructionCount: 3,
+	}
+	paymentImport, err := testQueries.CreatePaymentImport(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.MessageID, paymentImport.MessageID)
+	require.Zero(t, paymentImport.AcceptedCount)
+
+	// 同一个用户不能重复导入同一个文件
+	_, err = testQueries.CreatePaymentImport(context.Background(), arg)
+	require.Error(t, err)
+
+	updated, err := testQueries.UpdatePaymentImportResult(context.Background(), UpdatePaymentImportResultParams{
+		ID:            paymentImport.ID,
+		AcceptedCount: 2,
+		StatusReport:  "<Document/>",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), updated.AcceptedCount)
+	require.Equal(t, "<Document/>", updated.StatusReport)
+
+	found, err := testQueries.GetPaymentImport(context.Background(), GetPaymentImportParams{
+		Username:  user.Username,
+		MessageID: arg.MessageID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, updated, found)
+}
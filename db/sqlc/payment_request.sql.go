@@ -0,0 +1,422 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: payment_request.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const completePaymentRequestTransfer = `-- name: CompletePaymentRequestTransfer :one
+UPDATE payment_requests
+SET
+  status = 'paid',
+  updated_at = now()
+WHERE transfer_id = $1 AND status = 'pending_approval'
+RETURNING id, requester, payer, to_account_id, amount, currency, memo, status, transfer_id, expires_at, created_at, updated_at
+`
+
+// 待审批的付款被批准，转账没有关联付款请求时返回 no rows
+func (q *Queries) CompletePaymentRequestTransfer(ctx context.Context, transferID sql.NullInt64) (PaymentRequest, error) {
+	row := q.db.QueryRowContext(ctx, completePaymentRequestTransfer, transferID)
+	var i PaymentRequest
+	err := row.Scan(
+		&i.ID,
+		&i.Requester,
+		&i.Payer,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Memo,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createPaymentRequest = `-- name: CreatePaymentRequest :one
+INSERT INTO payment_requests (
+  requester,
+  payer,
+  to_account_id,
+  amount,
+  currency,
+  memo,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING id, requester, payer, to_account_id, amount, currency, memo, status, transfer_id, expires_at, created_at, updated_at
+`
+
+type CreatePaymentRequestParams struct {
+	Requester   string    `json:"requester"`
+	Payer       string    `json:"payer"`
+	ToAccountID int64     `json:"to_account_id"`
+	Amount      int64     `json:"amount"`
+	Currency    string    `json:"currency"`
+	Memo        string    `json:"memo"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreatePaymentRequest(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error) {
+	row := q.db.QueryRowContext(ctx, createPaymentRequest,
+		arg.Requester,
+		arg.Payer,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Currency,
+		arg.Memo,
+		arg.ExpiresAt,
+	)
+	var i PaymentRequest
+	err := row.Scan(
+		&i.ID,
+		&i.Requester,
+		&i.Payer,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Memo,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const declinePaymentRequest = `-- name: DeclinePaymentRequest :one
+UPDATE payment_requests
+SET
+  status = 'declined',
+  updated_at = now()
+WHERE id = $1 AND status = 'pending'
+RETURNING id, requester, payer, to_account_id, amount, currency, memo, status, transfer_id, expires_at, created_at, updated_at
+`
+
+// 只有 pending 的请求能拒绝，否则返回 no rows
+func (q *Queries) DeclinePaymentRequest(ctx context.Context, id int64) (PaymentRequest, error) {
+	row := q.db.QueryRowContext(ctx, declinePaymentRequest, id)
+	var i PaymentRequest
+	err := row.Scan(
+		&i.ID,
+		&i.Requester,
+		&i.Payer,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Memo,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const expirePaymentRequests = `-- name: ExpirePaymentRequests :many
+UPDATE payment_requests
+SET
+  status = 'expired',
+  updated_at = now()
+WHERE status = 'pending' AND expires_at <= now()
+RETURNING id, requester, payer, to_account_id, amount, currency, memo, status, transfer_id, expires_at, created_at, updated_at
+`
+
+func (q *Queries) ExpirePaymentRequests(ctx context.Context) ([]PaymentRequest, error) {
+	rows, err := q.db.QueryContext(ctx, expirePaymentRequests)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PaymentRequest{}
+	for rows.Next() {
+		var i PaymentRequest
+		if err := rows.Scan(
+			&i.ID,
+			&i.Requester,
+			&i.Payer,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.Currency,
+			&i.Memo,
+			&i.Status,
+			&i.TransferID,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPaymentRequest = `-- name: GetPaymentRequest :one
+SELECT id, requester, payer, to_account_id, amount, currency, memo, status, transfer_id, expires_at, created_at, updated_at FROM payment_requests
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetPaymentRequest(ctx context.Context, id int64) (PaymentRequest, error) {
+	row := q.db.QueryRowContext(ctx, getPaymentRequest, id)
+	var i PaymentRequest
+	err := row.Scan(
+		&i.ID,
+		&i.Requester,
+		&i.Payer,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Memo,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPaymentRequestForUpdate = `-- name: GetPaymentRequestForUpdate :one
+SELECT id, requester, payer, to_account_id, amount, currency, memo, status, transfer_id, expires_at, created_at, updated_at FROM payment_requests
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetPaymentRequestForUpdate(ctx context.Context, id int64) (PaymentRequest, error) {
+	row := q.db.QueryRowContext(ctx, getPaymentRequestForUpdate, id)
+	var i PaymentRequest
+	err := row.Scan(
+		&i.ID,
+		&i.Requester,
+		&i.Payer,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Memo,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listIncomingPaymentRequests = `-- name: ListIncomingPaymentRequests :many
+SELECT id, requester, payer, to_account_id, amount, currency, memo, status, transfer_id, expires_at, created_at, updated_at FROM payment_requests
+WHERE payer = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListIncomingPaymentRequestsParams struct {
+	Payer  string `json:"payer"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+}
+
+func (q *Queries) ListIncomingPaymentRequests(ctx context.Context, arg ListIncomingPaymentRequestsParams) ([]PaymentRequest, error) {
+	rows, err := q.db.QueryContext(ctx, listIncomingPaymentRequests, arg.Payer, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PaymentRequest{}
+	for rows.Next() {
+		var i PaymentRequest
+		if err := rows.Scan(
+			&i.ID,
+			&i.Requester,
+			&i.Payer,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.Currency,
+			&i.Memo,
+			&i.Status,
+			&i.TransferID,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOutgoingPaymentRequests = `-- name: ListOutgoingPaymentRequests :many
+SELECT id, requester, payer, to_account_id, amount, currency, memo, status, transfer_id, expires_at, created_at, updated_at FROM payment_requests
+WHERE requester = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListOutgoingPaymentRequestsParams struct {
+	Requester string `json:"requester"`
+	Limit     int32  `json:"limit"`
+	Offset    int32  `json:"offset"`
+}
+
+func (q *Queries) ListOutgoingPaymentRequests(ctx context.Context, arg ListOutgoingPaymentRequestsParams) ([]PaymentRequest, error) {
+	rows, err := q.db.QueryContext(ctx, listOutgoingPaymentRequests, arg.Requester, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PaymentRequest{}
+	for rows.Next() {
+		var i PaymentRequest
+		if err := rows.Scan(
+			&i.ID,
+			&i.Requester,
+			&i.Payer,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.Currency,
+			&i.Memo,
+			&i.Status,
+			&i.TransferID,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markPaymentRequestPaid = `-- name: MarkPaymentRequestPaid :one
+UPDATE payment_requests
+SET
+  status = 'paid',
+  transfer_id = $1,
+  updated_at = now()
+WHERE id = $2
+RETURNING id, requester, payer, to_account_id, amount, currency, memo, status, transfer_id, expires_at, created_at, updated_at
+`
+
+type MarkPaymentRequestPaidParams struct {
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	ID         int64         `json:"id"`
+}
+
+func (q *Queries) MarkPaymentRequestPaid(ctx context.Context, arg MarkPaymentRequestPaidParams) (PaymentRequest, error) {
+	row := q.db.QueryRowContext(ctx, markPaymentRequestPaid, arg.TransferID, arg.ID)
+	var i PaymentRequest
+	err := row.Scan(
+		&i.ID,
+		&i.Requester,
+		&i.Payer,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Memo,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const markPaymentRequestPendingApproval = `-- name: MarkPaymentRequestPendingApproval :one
+UPDATE payment_requests
+SET
+  status = 'pending_approval',
+  transfer_id = $1,
+  updated_at = now()
+WHERE id = $2
+RETURNING id, requester, payer, to_account_id, amount, currency, memo, status, transfer_id, expires_at, created_at, updated_at
+`
+
+type MarkPaymentRequestPendingApprovalParams struct {
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	ID         int64         `json:"id"`
+}
+
+func (q *Queries) MarkPaymentRequestPendingApproval(ctx context.Context, arg MarkPaymentRequestPendingApprovalParams) (PaymentRequest, error) {
+	row := q.db.QueryRowContext(ctx, markPaymentRequestPendingApproval, arg.TransferID, arg.ID)
+	var i PaymentRequest
+	err := row.Scan(
+		&i.ID,
+		&i.Requester,
+		&i.Payer,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Memo,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const reopenPaymentRequest = `-- name: ReopenPaymentRequest :one
+UPDATE payment_requests
+SET
+  status = 'pending',
+  transfer_id = NULL,
+  updated_at = now()
+WHERE transfer_id = $1 AND status = 'pending_approval'
+RETURNING id, requester, payer, to_account_id, amount, currency, memo, status, transfer_id, expires_at, created_at, updated_at
+`
+
+// 待审批的付款被拒绝或过期，请求回到 pending，付款人可以重新付款
+func (q *Queries) ReopenPaymentRequest(ctx context.Context, transferID sql.NullInt64) (PaymentRequest, error) {
+	row := q.db.QueryRowContext(ctx, reopenPaymentRequest, transferID)
+	var i PaymentRequest
+	err := row.Scan(
+		&i.ID,
+		&i.Requester,
+		&i.Payer,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Memo,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -6,33 +6,186 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	// 没人持有、已经过期或者自己持有时拿到租约并续期，否则返回 sql.ErrNoRows
+	AcquireSchedulerLease(ctx context.Context, arg AcquireSchedulerLeaseParams) (SchedulerLease, error)
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	AddAccountHeldAmount(ctx context.Context, arg AddAccountHeldAmountParams) (Account, error)
+	// 重试用完或者不用重试的任务，留着排查，不再处理
+	ArchiveTask(ctx context.Context, arg ArchiveTaskParams) error
+	// 按 queues 的顺序取一个到期的任务，租约到期前其他 worker 取不到
+	// 租约过期的 active 任务说明处理它的 worker 已经退出，算一次失败：
+	// 还有重试次数的重新取出来处理，retried 加一；用完的直接归档，不再处理
+	ClaimTask(ctx context.Context, arg ClaimTaskParams) (TaskQueue, error)
+	// 待审批的付款被批准，转账没有关联付款请求时返回 no rows
+	CompletePaymentRequestTransfer(ctx context.Context, transferID sql.NullInt64) (PaymentRequest, error)
+	CompleteTask(ctx context.Context, id int64) error
+	CountACHFilesSince(ctx context.Context, createdAt time.Time) (int64, error)
+	// 等待审批和他行转账在途的也占次数，被拒绝、过期和失败的不算
+	CountMonthlyTransfersFromAccount(ctx context.Context, fromAccountID int64) (int64, error)
+	CreateACHFile(ctx context.Context, arg CreateACHFileParams) (AchFile, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountFreeze(ctx context.Context, arg CreateAccountFreezeParams) (AccountFreeze, error)
+	CreateAccountMember(ctx context.Context, arg CreateAccountMemberParams) (AccountMember, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateExternalPayment(ctx context.Context, arg CreateExternalPaymentParams) (ExternalPayment, error)
+	CreateFeeSchedule(ctx context.Context, arg CreateFeeScheduleParams) (FeeSchedule, error)
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (InterestAccrual, error)
+	CreateInterestPosting(ctx context.Context, arg CreateInterestPostingParams) (InterestPosting, error)
+	CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) (Outbox, error)
+	CreateOverdraftCharge(ctx context.Context, arg CreateOverdraftChargeParams) (OverdraftCharge, error)
+	CreatePayee(ctx context.Context, arg CreatePayeeParams) (Payee, error)
+	CreatePaymentImport(ctx context.Context, arg CreatePaymentImportParams) (PaymentImport, error)
+	CreatePaymentRequest(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error)
+	// 这一次已经投递过时返回 sql.ErrNoRows
+	CreatePeriodicJobRun(ctx context.Context, arg CreatePeriodicJobRunParams) (PeriodicJobRun, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	// 同一个任务并发执行时先记的为准
+	CreateTaskExecution(ctx context.Context, arg CreateTaskExecutionParams) error
+	CreateTaskFailure(ctx context.Context, arg CreateTaskFailureParams) (TaskFailure, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferApproval(ctx context.Context, arg CreateTransferApprovalParams) (TransferApproval, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error)
+	// 同一个事件对同一个订阅只投递一次，重复调用返回已有的记录
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error)
+	DecideTransferApproval(ctx context.Context, arg DecideTransferApprovalParams) (TransferApproval, error)
+	// 只有 pending 的请求能拒绝，否则返回 no rows
+	DeclinePaymentRequest(ctx context.Context, id int64) (PaymentRequest, error)
 	DeleteAccount(ctx context.Context, id int64) error
+	DeleteAccountMember(ctx context.Context, arg DeleteAccountMemberParams) error
+	// 删掉以后同一个任务 ID 可以再入队，去重靠 task_executions
+	DeleteCompletedTasks(ctx context.Context, before time.Time) (int64, error)
+	DeleteExpiredSessions(ctx context.Context, expiresAt time.Time) (int64, error)
+	DeleteExpiredVerifyEmails(ctx context.Context, expiredAt time.Time) (int64, error)
+	DeleteFailedTask(ctx context.Context, taskID string) (TaskQueue, error)
+	DeleteFeeSchedule(ctx context.Context, id int64) (FeeSchedule, error)
+	DeletePayee(ctx context.Context, id int64) error
+	DeletePeriodicJobRuns(ctx context.Context, scheduledAt time.Time) (int64, error)
+	DeletePublishedOutboxMessages(ctx context.Context, before time.Time) (int64, error)
+	DeleteTaskExecutions(ctx context.Context, createdAt time.Time) (int64, error)
+	DeleteTaskFailures(ctx context.Context, createdAt time.Time) (int64, error)
 	DeleteTransfer(ctx context.Context, id int64) error
+	DeleteWebhookSubscription(ctx context.Context, id int64) error
+	// task_id 已经存在时不插入，返回 sql.ErrNoRows
+	EnqueueTask(ctx context.Context, arg EnqueueTaskParams) (TaskQueue, error)
+	// 系统账户按 (币种, 用途) 懒创建，冲突时返回已存在的那一行
+	EnsureSystemAccount(ctx context.Context, arg EnsureSystemAccountParams) (Account, error)
+	ExpirePaymentRequests(ctx context.Context) ([]PaymentRequest, error)
+	GetACHFile(ctx context.Context, id int64) (AchFile, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
+	// 账户当前的余额和最后一条分录的 ID，开始订阅时作为起点
+	GetAccountActivityCursor(ctx context.Context, id int64) (GetAccountActivityCursorRow, error)
+	GetAccountByNumber(ctx context.Context, accountNumber string) (Account, error)
+	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountMember(ctx context.Context, arg GetAccountMemberParams) (AccountMember, error)
+	GetAccountProduct(ctx context.Context, code string) (AccountProduct, error)
+	// 还没用过、离过期还有一段时间的验证，重发邮件时复用同一个链接
+	GetActiveVerifyEmail(ctx context.Context, arg GetActiveVerifyEmailParams) (VerifyEmail, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetExternalPayment(ctx context.Context, transferID int64) (ExternalPayment, error)
+	GetExternalPaymentByReference(ctx context.Context, arg GetExternalPaymentByReferenceParams) (ExternalPayment, error)
+	GetLatestInterestPosting(ctx context.Context, arg GetLatestInterestPostingParams) (InterestPosting, error)
+	GetLatestOverdraftCharge(ctx context.Context, accountID int64) (OverdraftCharge, error)
+	GetOutboxMessage(ctx context.Context, id int64) (Outbox, error)
+	GetPayee(ctx context.Context, id int64) (Payee, error)
+	GetPaymentImport(ctx context.Context, arg GetPaymentImportParams) (PaymentImport, error)
+	GetPaymentRequest(ctx context.Context, id int64) (PaymentRequest, error)
+	GetPaymentRequestForUpdate(ctx context.Context, id int64) (PaymentRequest, error)
+	GetQueuedTask(ctx context.Context, taskID string) (TaskQueue, error)
+	// 按用户名或已验证邮箱找收款账户，查不到的原因一律不区分
+	GetRecipientAccount(ctx context.Context, arg GetRecipientAccountParams) (Account, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetTaskExecution(ctx context.Context, arg GetTaskExecutionParams) (TaskExecution, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferApproval(ctx context.Context, transferID int64) (TransferApproval, error)
+	// 产品专属的配置优先于通用配置
+	GetTransferFeeSchedule(ctx context.Context, arg GetTransferFeeScheduleParams) (FeeSchedule, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	GetWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error)
+	// 游标之后的分录，balance 是这条分录入账后的余额，由当前余额减去之后的分录倒推
+	// 同一条语句里读余额和分录，看到的是同一个快照
+	ListAccountActivity(ctx context.Context, arg ListAccountActivityParams) ([]ListAccountActivityRow, error)
+	ListAccountFreezes(ctx context.Context, arg ListAccountFreezesParams) ([]AccountFreeze, error)
+	ListAccountMembers(ctx context.Context, accountID int64) ([]AccountMember, error)
+	ListAccountProducts(ctx context.Context) ([]AccountProduct, error)
+	// 账户流水，search 为空时不过滤，否则按备注全文搜索
+	ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfer, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListAccountsWithUnpostedInterest(ctx context.Context, periodEnd time.Time) ([]int64, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
 	ListEntriesByAccountID(ctx context.Context, arg ListEntriesByAccountIDParams) ([]Entry, error)
+	ListExpiredTransferApprovals(ctx context.Context) ([]int64, error)
+	// state 是 retry 时列出失败后等待重试的任务，archived 时列出不再重试的任务
+	ListFailedTasks(ctx context.Context, arg ListFailedTasksParams) ([]TaskQueue, error)
+	ListFeeSchedules(ctx context.Context) ([]FeeSchedule, error)
+	ListIncomingPaymentRequests(ctx context.Context, arg ListIncomingPaymentRequestsParams) ([]PaymentRequest, error)
+	ListInitiatedExternalPayments(ctx context.Context, network string) ([]ListInitiatedExternalPaymentsRow, error)
+	// 日终余额 = 当前余额 减去 日终之后发生的分录
+	ListInterestBearingAccounts(ctx context.Context, endOfDay time.Time) ([]ListInterestBearingAccountsRow, error)
+	ListOutgoingPaymentRequests(ctx context.Context, arg ListOutgoingPaymentRequestsParams) ([]PaymentRequest, error)
+	ListOverdrawnAccounts(ctx context.Context, endOfDay time.Time) ([]ListOverdrawnAccountsRow, error)
+	ListPayees(ctx context.Context, arg ListPayeesParams) ([]Payee, error)
+	// 银行职员看全部，其他用户只看自己有转账权限的账户
+	ListPendingTransferApprovals(ctx context.Context, arg ListPendingTransferApprovalsParams) ([]ListPendingTransferApprovalsRow, error)
+	// 提交后过了退回期限还没被退回的付款，可以认为已经清算
+	ListSettleableExternalPayments(ctx context.Context, arg ListSettleableExternalPaymentsParams) ([]int64, error)
+	// 对账单的分录，转账带出备注、参考号和对方账户，[from_time, to_time) 左闭右开
+	// 按用户名转账时收款账户对转出方隐藏，转出方这边不带对方账户
+	ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error)
+	// 一个任务最近的几次失败，最新的在前
+	ListTaskFailures(ctx context.Context, arg ListTaskFailuresParams) ([]TaskFailure, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
 	ListTransfersByFromAccount(ctx context.Context, arg ListTransfersByFromAccountParams) ([]Transfer, error)
 	ListTransfersByToAccount(ctx context.Context, arg ListTransfersByToAccountParams) ([]Transfer, error)
+	// 余额应该等于全部分录之和，对不上的账户说明有绕过分录改余额的地方
+	ListUnbalancedAccounts(ctx context.Context) ([]ListUnbalancedAccountsRow, error)
+	// 锁住待投递的消息，多个 relay 同时跑时互相跳过
+	ListUnpublishedOutboxMessages(ctx context.Context, limit int32) ([]Outbox, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookSubscriptions(ctx context.Context, owner string) ([]WebhookSubscription, error)
+	ListWebhookSubscriptionsForEvent(ctx context.Context, arg ListWebhookSubscriptionsForEventParams) ([]WebhookSubscription, error)
+	MarkExternalPaymentReversed(ctx context.Context, arg MarkExternalPaymentReversedParams) (ExternalPayment, error)
+	MarkExternalPaymentSettled(ctx context.Context, arg MarkExternalPaymentSettledParams) (ExternalPayment, error)
+	MarkExternalPaymentSubmitted(ctx context.Context, arg MarkExternalPaymentSubmittedParams) (ExternalPayment, error)
+	MarkInterestAccrualsPosted(ctx context.Context, arg MarkInterestAccrualsPostedParams) error
+	MarkOutboxMessagePublished(ctx context.Context, id int64) error
+	MarkPaymentRequestPaid(ctx context.Context, arg MarkPaymentRequestPaidParams) (PaymentRequest, error)
+	MarkPaymentRequestPendingApproval(ctx context.Context, arg MarkPaymentRequestPendingApprovalParams) (PaymentRequest, error)
+	NextACHTraceSequence(ctx context.Context) (int64, error)
+	RecordOutboxMessageFailure(ctx context.Context, arg RecordOutboxMessageFailureParams) error
+	RecordWebhookDeliveryAttempt(ctx context.Context, arg RecordWebhookDeliveryAttemptParams) (WebhookDelivery, error)
+	ReleaseSchedulerLease(ctx context.Context, arg ReleaseSchedulerLeaseParams) error
+	// 待审批的付款被拒绝或过期，请求回到 pending，付款人可以重新付款
+	ReopenPaymentRequest(ctx context.Context, transferID sql.NullInt64) (PaymentRequest, error)
+	// 手动重投，已经成功的也可以再投一次
+	ResetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	RetryTask(ctx context.Context, arg RetryTaskParams) error
+	// 失败的任务马上再处理一次，重试次数不清零
+	RunFailedTask(ctx context.Context, taskID string) (TaskQueue, error)
+	SumAccountEntriesSince(ctx context.Context, arg SumAccountEntriesSinceParams) (int64, error)
+	SumUnpostedInterest(ctx context.Context, arg SumUnpostedInterestParams) (int64, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateAccountFreezeStatus(ctx context.Context, arg UpdateAccountFreezeStatusParams) (Account, error)
+	UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error)
+	UpdateInterestPostingTransfer(ctx context.Context, arg UpdateInterestPostingTransferParams) (InterestPosting, error)
+	UpdateOverdraftChargeTransfer(ctx context.Context, arg UpdateOverdraftChargeTransferParams) (OverdraftCharge, error)
+	UpdatePayeeNickname(ctx context.Context, arg UpdatePayeeNicknameParams) (Payee, error)
+	UpdatePaymentImportResult(ctx context.Context, arg UpdatePaymentImportResultParams) (PaymentImport, error)
+	// 只有等待审批的转账能改状态，否则返回 no rows
+	UpdatePendingTransferStatus(ctx context.Context, arg UpdatePendingTransferStatusParams) (Transfer, error)
 	UpdateTransfer(ctx context.Context, arg UpdateTransferParams) (Transfer, error)
+	// 调用方先锁住转账并检查状态流转是否合法
+	UpdateTransferStatus(ctx context.Context, arg UpdateTransferStatusParams) (Transfer, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateVerifyEmail(ctx context.Context, arg UpdateVerifyEmailParams) (VerifyEmail, error)
 }
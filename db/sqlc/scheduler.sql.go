@@ -0,0 +1,105 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: scheduler.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const acquireSchedulerLease = `-- name: AcquireSchedulerLease :one
+INSERT INTO scheduler_leases (
+  name,
+  holder,
+  expires_at
+) VALUES (
+  $1,
+  $2,
+  now() + make_interval(secs => $3::int)
+)
+ON CONFLICT (name) DO UPDATE
+SET
+  holder = EXCLUDED.holder,
+  expires_at = EXCLUDED.expires_at,
+  acquired_at = CASE
+    WHEN scheduler_leases.holder = EXCLUDED.holder THEN scheduler_leases.acquired_at
+    ELSE now()
+  END
+WHERE scheduler_leases.holder = EXCLUDED.holder
+  OR scheduler_leases.expires_at < now()
+RETURNING name, holder, expires_at, acquired_at
+`
+
+type AcquireSchedulerLeaseParams struct {
+	Name       string `json:"name"`
+	Holder     string `json:"holder"`
+	TtlSeconds int32  `json:"ttl_seconds"`
+}
+
+// 没人持有、已经过期或者自己持有时拿到租约并续期，否则返回 sql.ErrNoRows
+func (q *Queries) AcquireSchedulerLease(ctx context.Context, arg AcquireSchedulerLeaseParams) (SchedulerLease, error) {
+	row := q.db.QueryRowContext(ctx, acquireSchedulerLease, arg.Name, arg.Holder, arg.TtlSeconds)
+	var i SchedulerLease
+	err := row.Scan(
+		&i.Name,
+		&i.Holder,
+		&i.ExpiresAt,
+		&i.AcquiredAt,
+	)
+	return i, err
+}
+
+const createPeriodicJobRun = `-- name: CreatePeriodicJobRun :one
+INSERT INTO periodic_job_runs (
+  job,
+  scheduled_at
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (job, scheduled_at) DO NOTHING
+RETURNING job, scheduled_at, created_at
+`
+
+type CreatePeriodicJobRunParams struct {
+	Job         string    `json:"job"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+// 这一次已经投递过时返回 sql.ErrNoRows
+func (q *Queries) CreatePeriodicJobRun(ctx context.Context, arg CreatePeriodicJobRunParams) (PeriodicJobRun, error) {
+	row := q.db.QueryRowContext(ctx, createPeriodicJobRun, arg.Job, arg.ScheduledAt)
+	var i PeriodicJobRun
+	err := row.Scan(&i.Job, &i.ScheduledAt, &i.CreatedAt)
+	return i, err
+}
+
+const deletePeriodicJobRuns = `-- name: DeletePeriodicJobRuns :execrows
+DELETE FROM periodic_job_runs
+WHERE scheduled_at < $1
+`
+
+func (q *Queries) DeletePeriodicJobRuns(ctx context.Context, scheduledAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePeriodicJobRuns, scheduledAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const releaseSchedulerLease = `-- name: ReleaseSchedulerLease :exec
+DELETE FROM scheduler_leases
+WHERE name = $1 AND holder = $2
+`
+
+type ReleaseSchedulerLeaseParams struct {
+	Name   string `json:"name"`
+	Holder string `json:"holder"`
+}
+
+func (q *Queries) ReleaseSchedulerLease(ctx context.Context, arg ReleaseSchedulerLeaseParams) error {
+	_, err := q.db.ExecContext(ctx, releaseSchedulerLease, arg.Name, arg.Holder)
+	return err
+}
@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"simplebank/util"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireSchedulerLease(t *testing.T) {
+	name := util.RandomString(10)
+
+	lease, err := testQueries.AcquireSchedulerLease(context.Background(), AcquireSchedulerLeaseParams{
+		Name:       name,
+		Holder:     "alice",
+		TtlSeconds: 30,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "alice", lease.Holder)
+	require.WithinDuration(t, time.Now().Add(30*time.Second), lease.ExpiresAt, 5*time.Second)
+
+	// 别人持有的租约没过期前拿不到
+	_, err = testQueries.AcquireSchedulerLease(context.Background(), AcquireSchedulerLeaseParams{
+		Name:       name,
+		Holder:     "bob",
+		TtlSeconds: 30,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// 续期不改变拿到租约的时间
+	renewed, err := testQueries.AcquireSchedulerLease(context.Background(), AcquireSchedulerLeaseParams{
+		Name:       name,
+		Holder:     "alice",
+		TtlSeconds: 60,
+	})
+	require.NoError(t, err)
+	require.Equal(t, lease.AcquiredAt, renewed.AcquiredAt)
+	require.True(t, renewed.ExpiresAt.After(lease.ExpiresAt))
+
+	// 交出租约后别人马上能拿到
+	err = testQueries.ReleaseSchedulerLease(context.Background(), ReleaseSchedulerLeaseParams{Name: name, Holder: "alice"})
+	require.NoError(t, err)
+
+	lease, err = testQueries.AcquireSchedulerLease(context.Background(), AcquireSchedulerLeaseParams{
+		Name:       name,
+		Holder:     "bob",
+		TtlSeconds: 0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "bob", lease.Holder)
+
+	// 过期的租约谁都能接手
+	time.Sleep(10 * time.Millisecond)
+	lease, err = testQueries.AcquireSchedulerLease(context.Background(), AcquireSchedulerLeaseParams{
+		Name:       name,
+		Holder:     "alice",
+		TtlSeconds: 30,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "alice", lease.Holder)
+}
+
+func TestSchedulePeriodicJobTx(t *testing.T) {
+	store := NewStore(testDB)
+	job := util.RandomString(10)
+	scheduledAt := time.Now().UTC().Truncate(time.Minute)
+
+	var outboxID int64
+	result, err := store.SchedulePeriodicJobTx(context.Background(), SchedulePeriodicJobTxParams{
+		Job:         job,
+		ScheduledAt: scheduledAt,
+		Enqueue: func(q Querier) error {
+			message, err := q.CreateOutboxMessage(context.Background(), CreateOutboxMessageParams{
+				TaskType: "task:" + job,
+				Payload:  json.RawMessage(`{}`),
+				Queue:    "default",
+				MaxRetry: 3,
+			})
+			outboxID = message.ID
+			return err
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, result.Scheduled)
+
+	message, err := testQueries.GetOutboxMessage(context.Background(), outboxID)
+	require.NoError(t, err)
+	require.Equal(t, "task:"+job, message.TaskType)
+
+	// 同一次触发不再投递
+	result, err = store.SchedulePeriodicJobTx(context.Background(), SchedulePeriodicJobTxParams{
+		Job:         job,
+		ScheduledAt: scheduledAt,
+		Enqueue: func(q Querier) error {
+			t.Fatal("job scheduled twice")
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, result.Scheduled)
+
+	// 投递失败时不记这次触发，下次还能再试
+	nextRun := scheduledAt.Add(time.Minute)
+	_, err = store.SchedulePeriodicJobTx(context.Background(), SchedulePeriodicJobTxParams{
+		Job:         job,
+		ScheduledAt: nextRun,
+		Enqueue: func(q Querier) error {
+			return errors.New("enqueue failed")
+		},
+	})
+	require.Error(t, err)
+
+	run, err := testQueries.CreatePeriodicJobRun(context.Background(), CreatePeriodicJobRunParams{
+		Job:         job,
+		ScheduledAt: nextRun,
+	})
+	require.NoError(t, err)
+	require.Equal(t, job, run.Job)
+}
+
+func TestDeleteExpiredSessions(t *testing.T) {
+	user := createRandomUser(t)
+
+	expired, err := testQueries.CreateSession(context.Background(), randomSessionParams(user.Username, time.Now().Add(-time.Minute)))
+	require.NoError(t, err)
+	active, err := testQueries.CreateSession(context.Background(), randomSessionParams(user.Username, time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+
+	deleted, err := testQueries.DeleteExpiredSessions(context.Background(), time.Now())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, deleted, int64(1))
+
+	_, err = testQueries.GetSession(context.Background(), expired.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = testQueries.GetSession(context.Background(), active.ID)
+	require.NoError(t, err)
+}
+
+func randomSessionParams(username string, expiresAt time.Time) CreateSessionParams {
+	return CreateSessionParams{
+		ID:           uuid.New(),
+		Username:     username,
+		RefreshToken: util.RandomString(32),
+		UserAgent:    "test",
+		ClientIp:     "127.0.0.1",
+		ExpiresAt:    expiresAt,
+	}
+}
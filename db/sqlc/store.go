@@ -9,8 +9,31 @@ import (
 type Store interface {
 	Querier
 	TransferTX(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	CheckTransfer(ctx context.Context, arg TransferTxParams) (CheckTransferResult, error)
+	CreateAccountTx(ctx context.Context, arg CreateAccountParams) (CreateAccountTxResult, error)
 	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
+	UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error)
 	VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error)
+	FreezeAccountTx(ctx context.Context, arg FreezeAccountTxParams) (FreezeAccountTxResult, error)
+	PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error)
+	ChargeOverdraftInterestTx(ctx context.Context, arg ChargeOverdraftInterestTxParams) (ChargeOverdraftInterestTxResult, error)
+	CreatePaymentRequestTx(ctx context.Context, arg CreatePaymentRequestTxParams) (CreatePaymentRequestTxResult, error)
+	DeclinePaymentRequestTx(ctx context.Context, arg DeclinePaymentRequestTxParams) (DeclinePaymentRequestTxResult, error)
+	PayPaymentRequestTx(ctx context.Context, arg PayPaymentRequestTxParams) (PayPaymentRequestTxResult, error)
+	PayPaymentRequestPendingTx(ctx context.Context, arg PayPaymentRequestPendingTxParams) (PayPaymentRequestPendingTxResult, error)
+	CreatePendingTransferTx(ctx context.Context, arg CreatePendingTransferTxParams) (CreatePendingTransferTxResult, error)
+	ApproveTransferTx(ctx context.Context, arg ApproveTransferTxParams) (ApproveTransferTxResult, error)
+	RejectTransferTx(ctx context.Context, arg RejectTransferTxParams) (CloseTransferTxResult, error)
+	ExpireTransferTx(ctx context.Context, transferID int64) (CloseTransferTxResult, error)
+	InitiateExternalTransferTx(ctx context.Context, arg InitiateExternalTransferTxParams) (InitiateExternalTransferTxResult, error)
+	InitiatePendingExternalTransferTx(ctx context.Context, arg InitiatePendingExternalTransferTxParams) (InitiatePendingExternalTransferTxResult, error)
+	SubmitExternalTransferTx(ctx context.Context, arg SubmitExternalTransferTxParams) (ExternalTransferTxResult, error)
+	SettleExternalTransferTx(ctx context.Context, transferID int64) (ExternalTransferTxResult, error)
+	ReverseExternalTransferTx(ctx context.Context, arg ReverseExternalTransferTxParams) (ExternalTransferTxResult, error)
+	CreateACHFileTx(ctx context.Context, arg CreateACHFileTxParams) (CreateACHFileTxResult, error)
+	PublishOutboxTx(ctx context.Context, arg PublishOutboxTxParams) (PublishOutboxTxResult, error)
+	SchedulePeriodicJobTx(ctx context.Context, arg SchedulePeriodicJobTxParams) (SchedulePeriodicJobTxResult, error)
+	RedeliverWebhookTx(ctx context.Context, arg RedeliverWebhookTxParams) (RedeliverWebhookTxResult, error)
 }
 
 type SQLStore struct {
@@ -2,9 +2,15 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
+	"simplebank/util"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
@@ -146,3 +152,1395 @@ func TestTransferTxDeadlock(t *testing.T) {
 	require.Equal(t, account1.Balance, updatedAccount1.Balance)
 	require.Equal(t, account2.Balance, updatedAccount2.Balance)
 }
+
+func TestTransferTxFrozenAccount(t *testing.T) {
+	store := NewStore(testDB)
+
+	admin := createRandomUser(t)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	amount := int64(10)
+
+	// 转入方只进不出，仍然可以收款
+	result, err := store.FreezeAccountTx(context.Background(), FreezeAccountTxParams{
+		AccountID:    account2.ID,
+		FreezeStatus: util.FreezeStatusDebitFrozen,
+		Reason:       "investigation",
+		Actor:        admin.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.FreezeStatusDebitFrozen, result.Account.FreezeStatus)
+	require.Equal(t, account2.ID, result.AccountFreeze.AccountID)
+	require.Equal(t, admin.Username, result.AccountFreeze.Actor)
+	require.NotZero(t, result.AccountFreeze.CreatedAt)
+
+	_, err = store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+
+	// 但不能转出
+	_, err = store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        amount,
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	// 双向冻结后也不能收款
+	_, err = store.FreezeAccountTx(context.Background(), FreezeAccountTxParams{
+		AccountID:    account2.ID,
+		FreezeStatus: util.FreezeStatusFrozen,
+		Reason:       "investigation",
+		Actor:        admin.Username,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	// 解冻后恢复
+	result, err = store.FreezeAccountTx(context.Background(), FreezeAccountTxParams{
+		AccountID:    account2.ID,
+		FreezeStatus: util.FreezeStatusActive,
+		Reason:       "cleared",
+		Actor:        admin.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.FreezeStatusActive, result.Account.FreezeStatus)
+
+	_, err = store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+}
+
+func TestTransferTxSavingsMonthlyLimit(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+	savings, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:         user.Username,
+		Balance:       util.RandomMoney(),
+		Currency:      util.RandomCurrency(),
+		ProductType:   util.SavingsProduct,
+		AccountNumber: util.RandomAccountNumber(),
+	})
+	require.NoError(t, err)
+	account2 := createRandomAccount(t)
+
+	product, err := testQueries.GetAccountProduct(context.Background(), util.SavingsProduct)
+	require.NoError(t, err)
+	require.True(t, product.MaxMonthlyOutgoingTransfers.Valid)
+
+	arg := TransferTxParams{
+		FromAccountID: savings.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	}
+	for i := 0; i < int(product.MaxMonthlyOutgoingTransfers.Int32); i++ {
+		_, err = store.TransferTX(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	_, err = store.TransferTX(context.Background(), arg)
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+}
+
+func TestPostInterestTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+	savings, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:         user.Username,
+		Balance:       util.RandomMoney(),
+		Currency:      util.RandomCurrency(),
+		ProductType:   util.SavingsProduct,
+		AccountNumber: util.RandomAccountNumber(),
+	})
+	require.NoError(t, err)
+
+	period := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for day := 0; day < 3; day++ {
+		_, err = testQueries.CreateInterestAccrual(context.Background(), CreateInterestAccrualParams{
+			AccountID:          savings.ID,
+			AccrualDate:        period.AddDate(0, 0, day),
+			Balance:            savings.Balance,
+			AnnualRateBps:      200,
+			DayCountConvention: util.DayCountAct365,
+			AmountMicros:       1_500_000,
+		})
+		require.NoError(t, err)
+	}
+
+	arg := PostInterestTxParams{AccountID: savings.ID, Period: period}
+	result, err := store.PostInterestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, result.AlreadyPosted)
+	require.Equal(t, int64(4_500_000), result.Posting.AccruedMicros)
+	require.Equal(t, int64(4), result.Posting.Amount)
+	require.Equal(t, int64(500_000), result.Posting.ResidualMicros)
+	require.Equal(t, savings.ID, result.Transfer.ToAccountID)
+	require.Equal(t, util.TransferStatusCompleted, result.Transfer.Status)
+
+	// 重跑不会重复入账
+	result, err = store.PostInterestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.AlreadyPosted)
+
+	updated, err := testQueries.GetAccount(context.Background(), savings.ID)
+	require.NoError(t, err)
+	require.Equal(t, savings.Balance+4, updated.Balance)
+
+	// 上个月的零头结转到下个月，合起来够一个最小单位就入账
+	nextPeriod := period.AddDate(0, 1, 0)
+	_, err = testQueries.CreateInterestAccrual(context.Background(), CreateInterestAccrualParams{
+		AccountID:          savings.ID,
+		AccrualDate:        nextPeriod,
+		Balance:            updated.Balance,
+		AnnualRateBps:      200,
+		DayCountConvention: util.DayCountAct365,
+		AmountMicros:       600_000,
+	})
+	require.NoError(t, err)
+
+	result, err = store.PostInterestTx(context.Background(), PostInterestTxParams{AccountID: savings.ID, Period: nextPeriod})
+	require.NoError(t, err)
+	require.Equal(t, int64(1_100_000), result.Posting.AccruedMicros)
+	require.Equal(t, int64(1), result.Posting.Amount)
+	require.Equal(t, int64(100_000), result.Posting.ResidualMicros)
+
+	updated, err = testQueries.GetAccount(context.Background(), savings.ID)
+	require.NoError(t, err)
+	require.Equal(t, savings.Balance+5, updated.Balance)
+
+	// 不够一个最小单位时只结转，计提不会再算第二次
+	thirdPeriod := nextPeriod.AddDate(0, 1, 0)
+	_, err = testQueries.CreateInterestAccrual(context.Background(), CreateInterestAccrualParams{
+		AccountID:          savings.ID,
+		AccrualDate:        thirdPeriod,
+		Balance:            updated.Balance,
+		AnnualRateBps:      200,
+		DayCountConvention: util.DayCountAct365,
+		AmountMicros:       200_000,
+	})
+	require.NoError(t, err)
+
+	result, err = store.PostInterestTx(context.Background(), PostInterestTxParams{AccountID: savings.ID, Period: thirdPeriod})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), result.Posting.Amount)
+	require.Equal(t, int64(300_000), result.Posting.ResidualMicros)
+
+	unposted, err := testQueries.SumUnpostedInterest(context.Background(), SumUnpostedInterestParams{
+		AccountID: savings.ID,
+		PeriodEnd: thirdPeriod.AddDate(0, 1, 0),
+	})
+	require.NoError(t, err)
+	require.Zero(t, unposted)
+}
+
+func TestChargeOverdraftInterestTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:         user.Username,
+		Balance:       0,
+		Currency:      util.USD,
+		ProductType:   util.CheckingProduct,
+		AccountNumber: util.RandomAccountNumber(),
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account.ID,
+		OverdraftLimit: 100_000,
+	})
+	require.NoError(t, err)
+
+	account, err = testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID:     account.ID,
+		Amount: -50_000,
+	})
+	require.NoError(t, err)
+
+	chargeDate := time.Date(2020, time.March, 2, 0, 0, 0, 0, time.UTC)
+	micros, err := util.DailyInterestMicros(50_000, 1800, util.DayCountAct365, chargeDate)
+	require.NoError(t, err)
+	amount := micros / util.MicrosPerMinorUnit
+	require.Positive(t, amount)
+
+	arg := ChargeOverdraftInterestTxParams{
+		AccountID:          account.ID,
+		ChargeDate:         chargeDate,
+		Balance:            account.Balance,
+		AnnualRateBps:      1800,
+		DayCountConvention: util.DayCountAct365,
+	}
+	result, err := store.ChargeOverdraftInterestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, result.AlreadyCharged)
+	require.Equal(t, amount, result.Charge.Amount)
+	require.Equal(t, micros-amount*util.MicrosPerMinorUnit, result.Charge.ResidualMicros)
+	require.Equal(t, account.Balance-amount, result.Account.Balance)
+
+	// 扣息走正常的已完成转账，分录都挂在转账上
+	transfer, err := testQueries.GetTransfer(context.Background(), result.Charge.TransferID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, util.TransferStatusCompleted, transfer.Status)
+	require.Equal(t, account.ID, transfer.FromAccountID)
+	require.Equal(t, amount, transfer.Amount)
+
+	// 同一天不会重复扣息
+	result, err = store.ChargeOverdraftInterestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.AlreadyCharged)
+
+	updated, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance-amount, updated.Balance)
+}
+
+func TestTransferTxOverdraft(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	limit := int64(100)
+
+	account1, err := testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: limit,
+	})
+	require.NoError(t, err)
+
+	// 超过 余额 + 透支额度 会被拒绝
+	_, err = store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + limit + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// 刚好用满透支额度
+	result, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + limit,
+	})
+	require.NoError(t, err)
+	require.Equal(t, -limit, result.FromAccount.Balance)
+}
+
+func TestCreateAccountTxMembers(t *testing.T) {
+	store := NewStore(testDB)
+	owner := createRandomUser(t)
+	viewer := createRandomUser(t)
+
+	result, err := store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:         owner.Username,
+		Balance:       0,
+		Currency:      util.USD,
+		ProductType:   util.CheckingProduct,
+		AccountNumber: util.RandomAccountNumber(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, owner.Username, result.Member.Username)
+	require.Equal(t, util.MemberRoleOwner, result.Member.Role)
+
+	_, err = store.CreateAccountMember(context.Background(), CreateAccountMemberParams{
+		AccountID: result.Account.ID,
+		Username:  viewer.Username,
+		Role:      util.MemberRoleViewer,
+	})
+	require.NoError(t, err)
+
+	// viewer 也能在 ListAccounts 里看到联名账户
+	accounts, err := store.ListAccounts(context.Background(), ListAccountsParams{
+		Username: viewer.Username,
+		Limit:    5,
+		Offset:   0,
+	})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, result.Account.ID, accounts[0].ID)
+
+	members, err := store.ListAccountMembers(context.Background(), result.Account.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+}
+
+func TestCreateAccountTxAccountNumberConflict(t *testing.T) {
+	store := NewStore(testDB)
+	existing := createRandomAccount(t)
+	owner := createRandomUser(t)
+
+	// 账号重复时重新生成，开户照常成功
+	result, err := store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:         owner.Username,
+		Balance:       0,
+		Currency:      util.USD,
+		ProductType:   util.CheckingProduct,
+		AccountNumber: existing.AccountNumber,
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, existing.AccountNumber, result.Account.AccountNumber)
+	require.True(t, util.ValidAccountNumber(result.Account.AccountNumber))
+	require.Equal(t, util.AccountNumberBankCode(existing.AccountNumber), util.AccountNumberBankCode(result.Account.AccountNumber))
+
+	// 同一个用户同币种同产品的账户重复时不重试
+	_, err = store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:         owner.Username,
+		Balance:       0,
+		Currency:      util.USD,
+		ProductType:   util.CheckingProduct,
+		AccountNumber: util.RandomAccountNumber(),
+	})
+	var pqErr *pq.Error
+	require.ErrorAs(t, err, &pqErr)
+	require.Equal(t, "unique_violation", pqErr.Code.Name())
+	require.False(t, isAccountNumberConflict(err))
+}
+
+func TestPayPaymentRequestTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	payerAccount := createRandomAccount(t)
+	requesterAccount := createRandomAccount(t)
+	amount := int64(10)
+
+	request, err := store.CreatePaymentRequest(context.Background(), CreatePaymentRequestParams{
+		Requester:   requesterAccount.Owner,
+		Payer:       payerAccount.Owner,
+		ToAccountID: requesterAccount.ID,
+		Amount:      amount,
+		Currency:    requesterAccount.Currency,
+		Memo:        "dinner",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.PaymentRequestPending, request.Status)
+
+	arg := PayPaymentRequestTxParams{
+		PaymentRequestID: request.ID,
+		FromAccountID:    payerAccount.ID,
+	}
+	result, err := store.PayPaymentRequestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, util.PaymentRequestPaid, result.PaymentRequest.Status)
+	require.Equal(t, result.Transfer.Transfer.ID, result.PaymentRequest.TransferID.Int64)
+	require.Equal(t, payerAccount.Balance-amount, result.Transfer.FromAccount.Balance)
+	require.Equal(t, requesterAccount.Balance+amount, result.Transfer.ToAccount.Balance)
+
+	// 同一个请求不能付两次
+	_, err = store.PayPaymentRequestTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrPaymentRequestClosed)
+}
+
+func TestPayPaymentRequestPendingTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	payerAccount := createRandomAccount(t)
+	requesterAccount := createRandomAccount(t)
+	approver := createRandomUser(t)
+	amount := int64(10)
+
+	request, err := store.CreatePaymentRequest(context.Background(), CreatePaymentRequestParams{
+		Requester:   requesterAccount.Owner,
+		Payer:       payerAccount.Owner,
+		ToAccountID: requesterAccount.ID,
+		Amount:      amount,
+		Currency:    requesterAccount.Currency,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	arg := PayPaymentRequestPendingTxParams{
+		PaymentRequestID: request.ID,
+		FromAccountID:    payerAccount.ID,
+		RequestedBy:      payerAccount.Owner,
+		ExpiresAt:        time.Now().Add(time.Hour),
+	}
+	pending, err := store.PayPaymentRequestPendingTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, util.PaymentRequestPendingApproval, pending.PaymentRequest.Status)
+	require.Equal(t, pending.Transfer.Transfer.ID, pending.PaymentRequest.TransferID.Int64)
+	require.Equal(t, util.TransferStatusPendingApproval, pending.Transfer.Transfer.Status)
+	require.Equal(t, payerAccount.Balance, pending.Transfer.FromAccount.Balance)
+	require.Equal(t, amount, pending.Transfer.FromAccount.HeldAmount)
+
+	// 审批期间不能再付一次
+	_, err = store.PayPaymentRequestTx(context.Background(), PayPaymentRequestTxParams{
+		PaymentRequestID: request.ID,
+		FromAccountID:    payerAccount.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestClosed)
+
+	// 拒绝后请求回到 pending，资金释放
+	rejected, err := store.RejectTransferTx(context.Background(), RejectTransferTxParams{
+		TransferID: pending.Transfer.Transfer.ID,
+		RejectedBy: payerAccount.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, request.ID, rejected.PaymentRequest.ID)
+	require.Equal(t, util.PaymentRequestPending, rejected.PaymentRequest.Status)
+	require.False(t, rejected.PaymentRequest.TransferID.Valid)
+	require.Zero(t, rejected.FromAccount.HeldAmount)
+
+	pending, err = store.PayPaymentRequestPendingTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	approved, err := store.ApproveTransferTx(context.Background(), ApproveTransferTxParams{
+		TransferID: pending.Transfer.Transfer.ID,
+		ApprovedBy: approver.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, request.ID, approved.PaymentRequest.ID)
+	require.Equal(t, util.PaymentRequestPaid, approved.PaymentRequest.Status)
+	require.Equal(t, pending.Transfer.Transfer.ID, approved.PaymentRequest.TransferID.Int64)
+	require.Equal(t, payerAccount.Balance-amount, approved.FromAccount.Balance)
+	require.Equal(t, requesterAccount.Balance+amount, approved.ToAccount.Balance)
+}
+
+func TestExpirePaymentRequests(t *testing.T) {
+	store := NewStore(testDB)
+
+	payerAccount := createRandomAccount(t)
+	requesterAccount := createRandomAccount(t)
+
+	request, err := store.CreatePaymentRequest(context.Background(), CreatePaymentRequestParams{
+		Requester:   requesterAccount.Owner,
+		Payer:       payerAccount.Owner,
+		ToAccountID: requesterAccount.ID,
+		Amount:      10,
+		Currency:    requesterAccount.Currency,
+		ExpiresAt:   time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	_, err = store.PayPaymentRequestTx(context.Background(), PayPaymentRequestTxParams{
+		PaymentRequestID: request.ID,
+		FromAccountID:    payerAccount.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestExpired)
+
+	expired, err := store.ExpirePaymentRequests(context.Background())
+	require.NoError(t, err)
+
+	found := false
+	for _, r := range expired {
+		if r.ID == request.ID {
+			found = true
+			require.Equal(t, util.PaymentRequestExpired, r.Status)
+		}
+	}
+	require.True(t, found)
+}
+
+func TestTransferTxMemoSearch(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	memo := fmt.Sprintf("rent %s", util.RandomString(8))
+
+	result, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID:     account1.ID,
+		ToAccountID:       account2.ID,
+		Amount:            10,
+		Memo:              memo,
+		PrivateNote:       "split with roommate",
+		ExternalReference: "INV-42",
+		Metadata:          json.RawMessage(`{"invoice":"42"}`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, memo, result.Transfer.Memo)
+	require.JSONEq(t, `{"invoice":"42"}`, string(result.Transfer.Metadata))
+
+	// 不带 metadata 时存空对象
+	hidden, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		HideToAccount: true,
+	})
+	require.NoError(t, err)
+	require.True(t, hidden.Transfer.HideToAccount)
+	require.False(t, result.Transfer.HideToAccount)
+
+	transfers, err := store.ListAccountTransfers(context.Background(), ListAccountTransfersParams{
+		AccountID: account2.ID,
+		Search:    memo,
+		Limit:     5,
+		Offset:    0,
+	})
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, result.Transfer.ID, transfers[0].ID)
+
+	transfers, err = store.ListAccountTransfers(context.Background(), ListAccountTransfersParams{
+		AccountID: account1.ID,
+		Limit:     5,
+		Offset:    0,
+	})
+	require.NoError(t, err)
+	require.Len(t, transfers, 2)
+	require.Equal(t, hidden.Transfer.ID, transfers[0].ID)
+	require.True(t, transfers[0].HideToAccount)
+}
+
+func TestTransferTxFee(t *testing.T) {
+	store := NewStore(testDB)
+
+	createUSDAccount := func(productType string) Account {
+		user := createRandomUser(t)
+		account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:         user.Username,
+			Balance:       1_000_000,
+			Currency:      util.USD,
+			ProductType:   productType,
+			AccountNumber: util.RandomAccountNumber(),
+		})
+		require.NoError(t, err)
+		return account
+	}
+	savings := createUSDAccount(util.SavingsProduct)
+	checking := createUSDAccount(util.CheckingProduct)
+
+	// 1.00 固定费用 + 超过 1000.00 部分的 1%
+	schedule, err := testQueries.CreateFeeSchedule(context.Background(), CreateFeeScheduleParams{
+		TransferType:        util.TransferTypeInternal,
+		ProductType:         sql.NullString{String: util.SavingsProduct, Valid: true},
+		FlatFee:             100,
+		PercentageBps:       100,
+		PercentageThreshold: 100_000,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := testQueries.DeleteFeeSchedule(context.Background(), schedule.ID)
+		require.NoError(t, err)
+	})
+
+	amount := int64(150_000)
+	check, err := store.CheckTransfer(context.Background(), TransferTxParams{
+		FromAccountID: savings.ID,
+		ToAccountID:   checking.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(600), check.Fee)
+	fee := check.Fee
+
+	feeAccount, err := getOrCreateSystemAccount(context.Background(), testQueries, util.USD, util.FeeIncomeProduct)
+	require.NoError(t, err)
+
+	result, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: savings.ID,
+		ToAccountID:   checking.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+	require.Equal(t, fee, result.Transfer.Fee)
+	require.Equal(t, -fee, result.FeeEntry.Amount)
+	require.Equal(t, savings.ID, result.FeeEntry.AccountID)
+	require.Equal(t, savings.Balance-amount-fee, result.FromAccount.Balance)
+	require.Equal(t, checking.Balance+amount, result.ToAccount.Balance)
+
+	updated, err := testQueries.GetAccount(context.Background(), feeAccount.ID)
+	require.NoError(t, err)
+	require.Equal(t, feeAccount.Balance+fee, updated.Balance)
+
+	// 费率只配给了储蓄账户，活期转出不收费
+	result, err = store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: checking.ID,
+		ToAccountID:   savings.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Transfer.Fee)
+	require.Zero(t, result.FeeEntry.ID)
+
+	// 不存在的配置删除时返回 ErrNoRows，API 据此返回 NotFound
+	_, err = testQueries.DeleteFeeSchedule(context.Background(), util.RandomInt(1_000_000, 2_000_000))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// 没有换汇，只能给行内和他行转账配费率
+	_, err = testQueries.CreateFeeSchedule(context.Background(), CreateFeeScheduleParams{
+		TransferType: "cross_currency",
+		FlatFee:      100,
+	})
+	require.Error(t, err)
+}
+
+func TestCheckTransfer(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	arg := TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	}
+	result, err := store.CheckTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, result.FromAccount.ID)
+	require.Zero(t, result.Fee)
+	require.False(t, result.RemainingMonthlyTransfers.Valid)
+
+	// 试算不写库
+	updated, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updated.Balance)
+
+	_, err = store.CheckTransfer(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestTransferTxQuote(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	arg := TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		QuotedFee:     sql.NullInt64{Int64: 3, Valid: true},
+		QuoteID:       uuid.NullUUID{UUID: uuid.New(), Valid: true},
+	}
+	result, err := store.TransferTX(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), result.Transfer.Fee)
+	require.Equal(t, arg.QuoteID, result.Transfer.QuoteID)
+	require.Equal(t, account1.Balance-13, result.FromAccount.Balance)
+
+	// 同一个报价不能用两次
+	_, err = store.TransferTX(context.Background(), arg)
+	require.Error(t, err)
+	require.Equal(t, "unique_violation", err.(*pq.Error).Code.Name())
+}
+
+func TestTransferApprovalTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	approver := createRandomUser(t)
+	amount := account1.Balance
+
+	arg := CreatePendingTransferTxParams{
+		TransferTxParams: TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		},
+		RequestedBy: account1.Owner,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	pending, err := store.CreatePendingTransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, util.TransferStatusPendingApproval, pending.Transfer.Status)
+	require.Equal(t, account1.Balance, pending.FromAccount.Balance)
+	require.Equal(t, amount, pending.FromAccount.HeldAmount)
+
+	// 资金被占用，其他转账不能再用
+	_, err = store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	approved, err := store.ApproveTransferTx(context.Background(), ApproveTransferTxParams{
+		TransferID: pending.Transfer.ID,
+		ApprovedBy: approver.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.TransferStatusCompleted, approved.Transfer.Status)
+	require.Zero(t, approved.FromAccount.Balance)
+	require.Zero(t, approved.FromAccount.HeldAmount)
+	require.Equal(t, account2.Balance+amount, approved.ToAccount.Balance)
+	require.Equal(t, approver.Username, approved.Approval.DecidedBy.String)
+
+	_, err = store.ApproveTransferTx(context.Background(), ApproveTransferTxParams{
+		TransferID: pending.Transfer.ID,
+		ApprovedBy: approver.Username,
+	})
+	require.ErrorIs(t, err, ErrTransferNotPending)
+}
+
+func TestCreatePendingTransferTxRollback(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	// webhook 事件写不进 outbox 时转账不提交，资金也不占用
+	_, err := store.CreatePendingTransferTx(context.Background(), CreatePendingTransferTxParams{
+		TransferTxParams: TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        1,
+		},
+		RequestedBy: account1.Owner,
+		ExpiresAt:   time.Now().Add(time.Hour),
+		AfterCreate: func(q Querier, result CreatePendingTransferTxResult) error {
+			require.Equal(t, util.TransferStatusPendingApproval, result.Transfer.Status)
+			return fmt.Errorf("abort")
+		},
+	})
+	require.Error(t, err)
+
+	account, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Zero(t, account.HeldAmount)
+}
+
+func TestRejectAndExpireTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	arg := CreatePendingTransferTxParams{
+		TransferTxParams: TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        1,
+		},
+		RequestedBy: account1.Owner,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	pending, err := store.CreatePendingTransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// 还没到期不会过期
+	_, err = store.ExpireTransferTx(context.Background(), pending.Transfer.ID)
+	require.Error(t, err)
+
+	rejected, err := store.RejectTransferTx(context.Background(), RejectTransferTxParams{
+		TransferID: pending.Transfer.ID,
+		RejectedBy: account1.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.TransferStatusRejected, rejected.Transfer.Status)
+	require.Zero(t, rejected.FromAccount.HeldAmount)
+	require.Equal(t, account1.Balance, rejected.FromAccount.Balance)
+
+	arg.ExpiresAt = time.Now().Add(-time.Minute)
+	pending, err = store.CreatePendingTransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	ids, err := store.ListExpiredTransferApprovals(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, ids, pending.Transfer.ID)
+
+	_, err = store.ApproveTransferTx(context.Background(), ApproveTransferTxParams{
+		TransferID: pending.Transfer.ID,
+		ApprovedBy: account2.Owner,
+	})
+	require.ErrorIs(t, err, ErrApprovalExpired)
+
+	expired, err := store.ExpireTransferTx(context.Background(), pending.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, util.TransferStatusExpired, expired.Transfer.Status)
+	require.False(t, expired.Approval.DecidedBy.Valid)
+	require.Zero(t, expired.FromAccount.HeldAmount)
+}
+
+func initiateExternalTransfer(t *testing.T, account Account, amount int64) InitiateExternalTransferTxResult {
+	result, err := NewStore(testDB).InitiateExternalTransferTx(context.Background(), InitiateExternalTransferTxParams{
+		FromAccountID:         account.ID,
+		Amount:                amount,
+		Network:               "test",
+		CreditorAccountNumber: util.RandomAccountNumber(),
+		CreditorName:          util.RandomOwner(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.TransferStatusInitiated, result.Transfer.Status)
+	require.Equal(t, account.Balance-amount, result.FromAccount.Balance)
+	require.Equal(t, util.ExternalClearingProduct, result.ToAccount.ProductType)
+	require.Equal(t, result.Transfer.ID, result.ExternalPayment.TransferID)
+	return result
+}
+
+func TestExternalTransferSettle(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	amount := int64(10)
+	initiated := initiateExternalTransfer(t, account, amount)
+
+	// 没提交之前不能直接清算
+	_, err := store.SettleExternalTransferTx(context.Background(), initiated.Transfer.ID)
+	require.ErrorIs(t, err, ErrInvalidTransferTransition)
+
+	reference := util.RandomString(12)
+	submitted, err := store.SubmitExternalTransferTx(context.Background(), SubmitExternalTransferTxParams{
+		TransferID:       initiated.Transfer.ID,
+		NetworkReference: reference,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.TransferStatusPending, submitted.Transfer.Status)
+	require.Equal(t, reference, submitted.ExternalPayment.NetworkReference.String)
+	require.True(t, submitted.ExternalPayment.SubmittedAt.Valid)
+
+	settled, err := store.SettleExternalTransferTx(context.Background(), initiated.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, util.TransferStatusSettled, settled.Transfer.Status)
+	require.True(t, settled.ExternalPayment.SettledAt.Valid)
+
+	booking, err := store.GetTransfer(context.Background(), settled.ExternalPayment.SettlementTransferID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, initiated.ToAccount.ID, booking.FromAccountID)
+	require.Equal(t, amount, booking.Amount)
+
+	// 清算后被退回，钱从结算账户退给客户
+	returned, err := store.ReverseExternalTransferTx(context.Background(), ReverseExternalTransferTxParams{
+		TransferID: initiated.Transfer.ID,
+		Status:     util.TransferStatusReturned,
+		Reason:     "account closed",
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.TransferStatusReturned, returned.Transfer.Status)
+	require.Equal(t, "account closed", returned.ExternalPayment.FailureReason)
+
+	refund, err := store.GetTransfer(context.Background(), returned.ExternalPayment.ReversalTransferID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, booking.ToAccountID, refund.FromAccountID)
+	require.Equal(t, account.ID, refund.ToAccountID)
+
+	updated, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, updated.Balance)
+}
+
+func TestExternalTransferFail(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	initiated := initiateExternalTransfer(t, account, 10)
+
+	failed, err := store.ReverseExternalTransferTx(context.Background(), ReverseExternalTransferTxParams{
+		TransferID: initiated.Transfer.ID,
+		Status:     util.TransferStatusFailed,
+		Reason:     "rejected by network",
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.TransferStatusFailed, failed.Transfer.Status)
+
+	refund, err := store.GetTransfer(context.Background(), failed.ExternalPayment.ReversalTransferID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, initiated.ToAccount.ID, refund.FromAccountID)
+
+	updated, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, updated.Balance)
+
+	// 终态不能再变
+	_, err = store.ReverseExternalTransferTx(context.Background(), ReverseExternalTransferTxParams{
+		TransferID: initiated.Transfer.ID,
+		Status:     util.TransferStatusReturned,
+	})
+	require.ErrorIs(t, err, ErrInvalidTransferTransition)
+}
+
+func TestExternalTransferReverseFrozenAccount(t *testing.T) {
+	store := NewStore(testDB)
+
+	admin := createRandomUser(t)
+	account := createRandomAccount(t)
+	initiated := initiateExternalTransfer(t, account, 10)
+
+	// 退款不受冻结限制
+	_, err := store.FreezeAccountTx(context.Background(), FreezeAccountTxParams{
+		AccountID:    account.ID,
+		FreezeStatus: util.FreezeStatusFrozen,
+		Reason:       "investigation",
+		Actor:        admin.Username,
+	})
+	require.NoError(t, err)
+
+	failed, err := store.ReverseExternalTransferTx(context.Background(), ReverseExternalTransferTxParams{
+		TransferID: initiated.Transfer.ID,
+		Status:     util.TransferStatusFailed,
+		Reason:     "rejected by network",
+	})
+	require.NoError(t, err)
+
+	refund, err := store.GetTransfer(context.Background(), failed.ExternalPayment.ReversalTransferID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, util.TransferStatusCompleted, refund.Status)
+	require.Zero(t, refund.Fee)
+
+	updated, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, updated.Balance)
+}
+
+func TestCountMonthlyTransfersFromAccountExternal(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	initiated := initiateExternalTransfer(t, account, 10)
+
+	// 在途的他行转账占次数
+	count, err := store.CountMonthlyTransfersFromAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	// 失败的不算，退款是从清算账户转出的，也不算
+	_, err = store.ReverseExternalTransferTx(context.Background(), ReverseExternalTransferTxParams{
+		TransferID: initiated.Transfer.ID,
+		Status:     util.TransferStatusFailed,
+	})
+	require.NoError(t, err)
+
+	count, err = store.CountMonthlyTransfersFromAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func TestPendingExternalTransferApproval(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	approver := createRandomUser(t)
+	amount := int64(10)
+
+	arg := InitiatePendingExternalTransferTxParams{
+		InitiateExternalTransferTxParams: InitiateExternalTransferTxParams{
+			FromAccountID:         account.ID,
+			Amount:                amount,
+			Network:               "test",
+			CreditorAccountNumber: util.RandomAccountNumber(),
+			CreditorName:          util.RandomOwner(),
+		},
+		RequestedBy: account.Owner,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	pending, err := store.InitiatePendingExternalTransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, util.TransferStatusPendingApproval, pending.Transfer.Status)
+	require.Equal(t, pending.Transfer.ID, pending.ExternalPayment.TransferID)
+	require.Equal(t, account.Balance, pending.FromAccount.Balance)
+	require.Equal(t, amount, pending.FromAccount.HeldAmount)
+
+	submitted := false
+	approved, err := store.ApproveTransferTx(context.Background(), ApproveTransferTxParams{
+		TransferID: pending.Transfer.ID,
+		ApprovedBy: approver.Username,
+		AfterApprove: func(q Querier, result ApproveTransferTxResult) error {
+			submitted = result.ExternalPayment.TransferID == pending.Transfer.ID
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, submitted)
+	// 批准后等待提交清算网络
+	require.Equal(t, util.TransferStatusInitiated, approved.Transfer.Status)
+	require.Equal(t, util.ExternalClearingProduct, approved.ToAccount.ProductType)
+	require.Equal(t, account.Balance-amount, approved.FromAccount.Balance)
+	require.Zero(t, approved.FromAccount.HeldAmount)
+
+	_, err = store.SubmitExternalTransferTx(context.Background(), SubmitExternalTransferTxParams{
+		TransferID:       pending.Transfer.ID,
+		NetworkReference: util.RandomString(12),
+	})
+	require.NoError(t, err)
+
+	// 被拒绝的不会提交
+	pending, err = store.InitiatePendingExternalTransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	_, err = store.RejectTransferTx(context.Background(), RejectTransferTxParams{
+		TransferID: pending.Transfer.ID,
+		RejectedBy: account.Owner,
+	})
+	require.NoError(t, err)
+
+	rows, err := store.ListInitiatedExternalPayments(context.Background(), "test")
+	require.NoError(t, err)
+	for _, row := range rows {
+		require.NotEqual(t, pending.Transfer.ID, row.ExternalPayment.TransferID)
+	}
+}
+
+func TestCreateACHFileTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	initiated := initiateExternalTransfer(t, account, 10)
+
+	arg := CreateACHFileTxParams{
+		FileIDModifier: "A",
+		TotalCredit:    10,
+		Content:        util.RandomString(94),
+		Entries: []ACHFileEntry{{
+			TransferID:  initiated.Transfer.ID,
+			TraceNumber: util.RandomString(15),
+		}},
+	}
+	result, err := store.CreateACHFileTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), result.ACHFile.EntryCount)
+	require.Len(t, result.ExternalPayments, 1)
+	require.Equal(t, result.ACHFile.ID, result.ExternalPayments[0].AchFileID.Int64)
+
+	payment, err := store.GetExternalPaymentByReference(context.Background(), GetExternalPaymentByReferenceParams{
+		Network:          initiated.ExternalPayment.Network,
+		NetworkReference: sql.NullString{String: arg.Entries[0].TraceNumber, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, initiated.Transfer.ID, payment.TransferID)
+
+	transfer, err := store.GetTransfer(context.Background(), initiated.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, util.TransferStatusPending, transfer.Status)
+
+	// 已经导出过的付款不能再进文件，整个文件回滚
+	arg.Entries[0].TraceNumber = util.RandomString(15)
+	_, err = store.CreateACHFileTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInvalidTransferTransition)
+}
+
+func TestListSettleableExternalPayments(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	initiated := initiateExternalTransfer(t, account, 10)
+
+	arg := ListSettleableExternalPaymentsParams{
+		Network:         initiated.ExternalPayment.Network,
+		SubmittedBefore: time.Now().Add(time.Minute),
+	}
+
+	// 还没提交的不算
+	ids, err := store.ListSettleableExternalPayments(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotContains(t, ids, initiated.Transfer.ID)
+
+	_, err = store.SubmitExternalTransferTx(context.Background(), SubmitExternalTransferTxParams{
+		TransferID:       initiated.Transfer.ID,
+		NetworkReference: util.RandomString(12),
+	})
+	require.NoError(t, err)
+
+	ids, err = store.ListSettleableExternalPayments(context.Background(), arg)
+	require.NoError(t, err)
+	require.Contains(t, ids, initiated.Transfer.ID)
+
+	// 还在退回期限内
+	arg.SubmittedBefore = time.Now().Add(-time.Hour)
+	ids, err = store.ListSettleableExternalPayments(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotContains(t, ids, initiated.Transfer.ID)
+
+	// 清算后不再列出
+	_, err = store.SettleExternalTransferTx(context.Background(), initiated.Transfer.ID)
+	require.NoError(t, err)
+
+	arg.SubmittedBefore = time.Now().Add(time.Minute)
+	ids, err = store.ListSettleableExternalPayments(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotContains(t, ids, initiated.Transfer.ID)
+}
+
+func TestListStatementEntries(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	from := time.Now().Add(-time.Minute)
+
+	result, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID:     account1.ID,
+		ToAccountID:       account2.ID,
+		Amount:            10,
+		Memo:              "invoice 42",
+		ExternalReference: "INV-42",
+	})
+	require.NoError(t, err)
+	require.Equal(t, result.Transfer.ID, result.FromEntry.TransferID.Int64)
+	require.Equal(t, result.Transfer.ID, result.ToEntry.TransferID.Int64)
+
+	entries, err := store.ListStatementEntries(context.Background(), ListStatementEntriesParams{
+		AccountID: account2.ID,
+		FromTime:  from,
+		ToTime:    time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, result.ToEntry.ID, entries[0].ID)
+	require.Equal(t, int64(10), entries[0].Amount)
+	require.Equal(t, "invoice 42", entries[0].Memo)
+	require.Equal(t, "INV-42", entries[0].ExternalReference)
+	require.Equal(t, account1.AccountNumber, entries[0].CounterpartyAccountNumber)
+	require.NotEmpty(t, entries[0].CounterpartyName)
+
+	sum, err := store.SumAccountEntriesSince(context.Background(), SumAccountEntriesSinceParams{
+		AccountID: account1.ID,
+		CreatedAt: from,
+	})
+	require.NoError(t, err)
+	require.Equal(t, -10-result.Transfer.Fee, sum)
+
+	// 区间右开，之后的分录不算
+	entries, err = store.ListStatementEntries(context.Background(), ListStatementEntriesParams{
+		AccountID: account2.ID,
+		FromTime:  from,
+		ToTime:    from,
+	})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// 按用户名转账时转出方看不到收款账户，收款方照常能看到转出方
+	hidden, err := store.TransferTX(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		HideToAccount: true,
+	})
+	require.NoError(t, err)
+
+	entries, err = store.ListStatementEntries(context.Background(), ListStatementEntriesParams{
+		AccountID: account1.ID,
+		FromTime:  from,
+		ToTime:    time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	for _, entry := range entries {
+		if entry.TransferID.Int64 == hidden.Transfer.ID {
+			require.Empty(t, entry.CounterpartyAccountNumber)
+			require.Empty(t, entry.CounterpartyName)
+		} else {
+			require.Equal(t, account2.AccountNumber, entry.CounterpartyAccountNumber)
+		}
+	}
+
+	entries, err = store.ListStatementEntries(context.Background(), ListStatementEntriesParams{
+		AccountID: account2.ID,
+		FromTime:  from,
+		ToTime:    time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, hidden.ToEntry.ID, entries[1].ID)
+	require.Equal(t, account1.AccountNumber, entries[1].CounterpartyAccountNumber)
+}
+
+func TestPublishOutboxTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	message, err := testQueries.CreateOutboxMessage(context.Background(), CreateOutboxMessageParams{
+		TaskType: "task:test",
+		Payload:  json.RawMessage(`{"n":1}`),
+		Queue:    "default",
+		MaxRetry: 3,
+	})
+	require.NoError(t, err)
+	require.False(t, message.PublishedAt.Valid)
+
+	publish := func(fail bool) PublishOutboxTxResult {
+		var published bool
+		result, err := store.PublishOutboxTx(context.Background(), PublishOutboxTxParams{
+			Limit: 1000,
+			Publish: func(m Outbox) error {
+				if m.ID != message.ID {
+					return nil
+				}
+				published = true
+				if fail {
+					return fmt.Errorf("redis is down")
+				}
+				return nil
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, published)
+		return result
+	}
+
+	// 投递失败时留在 outbox 里，记下错误
+	result := publish(true)
+	require.GreaterOrEqual(t, result.Failed, 1)
+
+	message, err = testQueries.GetOutboxMessage(context.Background(), message.ID)
+	require.NoError(t, err)
+	require.False(t, message.PublishedAt.Valid)
+	require.Equal(t, int32(1), message.Attempts)
+	require.Equal(t, "redis is down", message.LastError)
+
+	publish(false)
+
+	message, err = testQueries.GetOutboxMessage(context.Background(), message.ID)
+	require.NoError(t, err)
+	require.True(t, message.PublishedAt.Valid)
+}
+
+func TestCreateUserTxOutboxRollback(t *testing.T) {
+	store := NewStore(testDB)
+
+	var outboxID int64
+	_, err := store.CreateUserTx(context.Background(), CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			Username:       util.RandomOwner(),
+			HashedPassword: "secret",
+			FullName:       util.RandomOwner(),
+			Email:          util.RandomEmail(),
+		},
+		AfterCreate: func(q Querier, user User) error {
+			message, err := q.CreateOutboxMessage(context.Background(), CreateOutboxMessageParams{
+				TaskType: "task:test",
+				Payload:  json.RawMessage(`{}`),
+				Queue:    "default",
+				MaxRetry: 3,
+			})
+			require.NoError(t, err)
+			outboxID = message.ID
+			return fmt.Errorf("abort")
+		},
+	})
+	require.Error(t, err)
+
+	// 事务回滚，任务也不会投递
+	_, err = testQueries.GetOutboxMessage(context.Background(), outboxID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestPaymentRequestTxOutbox(t *testing.T) {
+	store := NewStore(testDB)
+
+	payerAccount := createRandomAccount(t)
+	requesterAccount := createRandomAccount(t)
+
+	writeOutbox := func(q Querier) (Outbox, error) {
+		return q.CreateOutboxMessage(context.Background(), CreateOutboxMessageParams{
+			TaskType: "task:test",
+			Payload:  json.RawMessage(`{}`),
+			Queue:    "default",
+			MaxRetry: 3,
+		})
+	}
+
+	var outboxID int64
+	created, err := store.CreatePaymentRequestTx(context.Background(), CreatePaymentRequestTxParams{
+		CreatePaymentRequestParams: CreatePaymentRequestParams{
+			Requester:   requesterAccount.Owner,
+			Payer:       payerAccount.Owner,
+			ToAccountID: requesterAccount.ID,
+			Amount:      10,
+			Currency:    requesterAccount.Currency,
+			ExpiresAt:   time.Now().Add(time.Hour),
+		},
+		AfterCreate: func(q Querier, request PaymentRequest) error {
+			message, err := writeOutbox(q)
+			outboxID = message.ID
+			return err
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.PaymentRequestPending, created.PaymentRequest.Status)
+
+	_, err = testQueries.GetOutboxMessage(context.Background(), outboxID)
+	require.NoError(t, err)
+
+	// 通知写不进去时拒绝也回滚
+	_, err = store.DeclinePaymentRequestTx(context.Background(), DeclinePaymentRequestTxParams{
+		PaymentRequestID: created.PaymentRequest.ID,
+		AfterDecline: func(q Querier, request PaymentRequest) error {
+			message, err := writeOutbox(q)
+			require.NoError(t, err)
+			outboxID = message.ID
+			return fmt.Errorf("abort")
+		},
+	})
+	require.Error(t, err)
+
+	_, err = testQueries.GetOutboxMessage(context.Background(), outboxID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	request, err := store.GetPaymentRequest(context.Background(), created.PaymentRequest.ID)
+	require.NoError(t, err)
+	require.Equal(t, util.PaymentRequestPending, request.Status)
+
+	declined, err := store.DeclinePaymentRequestTx(context.Background(), DeclinePaymentRequestTxParams{
+		PaymentRequestID: created.PaymentRequest.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.PaymentRequestDeclined, declined.PaymentRequest.Status)
+
+	// 已经拒绝过的不能再拒绝
+	_, err = store.DeclinePaymentRequestTx(context.Background(), DeclinePaymentRequestTxParams{
+		PaymentRequestID: created.PaymentRequest.ID,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestListAccountActivity(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	start, err := store.GetAccountActivityCursor(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, start.Balance)
+
+	var results []TransferTxResult
+	for _, amount := range []int64{10, 20} {
+		result, err := store.TransferTX(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		})
+		require.NoError(t, err)
+		results = append(results, result)
+	}
+
+	activity, err := store.ListAccountActivity(context.Background(), ListAccountActivityParams{
+		AccountID:    account2.ID,
+		AfterEntryID: start.LastEntryID,
+		LimitCount:   5,
+	})
+	require.NoError(t, err)
+	require.Len(t, activity, 2)
+
+	// 每条分录带入账后的余额
+	require.Equal(t, results[0].ToEntry.ID, activity[0].ID)
+	require.Equal(t, account2.Balance+10, activity[0].Balance)
+	require.Equal(t, results[1].ToEntry.ID, activity[1].ID)
+	require.Equal(t, account2.Balance+30, activity[1].Balance)
+
+	// 从游标续传时只返回之后的分录
+	activity, err = store.ListAccountActivity(context.Background(), ListAccountActivityParams{
+		AccountID:    account2.ID,
+		AfterEntryID: results[0].ToEntry.ID,
+		LimitCount:   1,
+	})
+	require.NoError(t, err)
+	require.Len(t, activity, 1)
+	require.Equal(t, results[1].ToEntry.ID, activity[0].ID)
+	require.Equal(t, account2.Balance+30, activity[0].Balance)
+
+	end, err := store.GetAccountActivityCursor(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, results[1].ToEntry.ID, end.LastEntryID)
+	require.Equal(t, account2.Balance+30, end.Balance)
+}
@@ -0,0 +1,20 @@
+package db
+
+import (
+	"context"
+	"simplebank/util"
+)
+
+// getOrCreateSystemAccount 懒创建银行系统账户，已存在时生成的新账号会被忽略
+func getOrCreateSystemAccount(ctx context.Context, q *Queries, currency string, productType string) (Account, error) {
+	accountNumber, err := util.NewAccountNumber(util.SystemBankCode)
+	if err != nil {
+		return Account{}, err
+	}
+
+	return q.EnsureSystemAccount(ctx, EnsureSystemAccountParams{
+		Currency:      currency,
+		ProductType:   productType,
+		AccountNumber: accountNumber,
+	})
+}
@@ -0,0 +1,71 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: task_execution.sql
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const createTaskExecution = `-- name: CreateTaskExecution :exec
+INSERT INTO task_executions (
+  task_id,
+  step,
+  result
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (task_id, step) DO NOTHING
+`
+
+type CreateTaskExecutionParams struct {
+	TaskID string          `json:"task_id"`
+	Step   string          `json:"step"`
+	Result json.RawMessage `json:"result"`
+}
+
+// 同一个任务并发执行时先记的为准
+func (q *Queries) CreateTaskExecution(ctx context.Context, arg CreateTaskExecutionParams) error {
+	_, err := q.db.ExecContext(ctx, createTaskExecution, arg.TaskID, arg.Step, arg.Result)
+	return err
+}
+
+const deleteTaskExecutions = `-- name: DeleteTaskExecutions :execrows
+DELETE FROM task_executions
+WHERE created_at < $1
+`
+
+func (q *Queries) DeleteTaskExecutions(ctx context.Context, createdAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTaskExecutions, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getTaskExecution = `-- name: GetTaskExecution :one
+SELECT task_id, step, result, created_at FROM task_executions
+WHERE task_id = $1 AND step = $2
+LIMIT 1
+`
+
+type GetTaskExecutionParams struct {
+	TaskID string `json:"task_id"`
+	Step   string `json:"step"`
+}
+
+func (q *Queries) GetTaskExecution(ctx context.Context, arg GetTaskExecutionParams) (TaskExecution, error) {
+	row := q.db.QueryRowContext(ctx, getTaskExecution, arg.TaskID, arg.Step)
+	var i TaskExecution
+	err := row.Scan(
+		&i.TaskID,
+		&i.Step,
+		&i.Result,
+		&i.CreatedAt,
+	)
+	return i, err
+}
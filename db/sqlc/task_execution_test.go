@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskExecution(t *testing.T) {
+	taskID := uuid.NewString()
+
+	_, err := testQueries.GetTaskExecution(context.Background(), GetTaskExecutionParams{TaskID: taskID, Step: "send_email"})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = testQueries.CreateTaskExecution(context.Background(), CreateTaskExecutionParams{
+		TaskID: taskID,
+		Step:   "send_email",
+		Result: json.RawMessage(`{"id":1}`),
+	})
+	require.NoError(t, err)
+
+	// 先记的为准
+	err = testQueries.CreateTaskExecution(context.Background(), CreateTaskExecutionParams{
+		TaskID: taskID,
+		Step:   "send_email",
+		Result: json.RawMessage(`{"id":2}`),
+	})
+	require.NoError(t, err)
+
+	execution, err := testQueries.GetTaskExecution(context.Background(), GetTaskExecutionParams{TaskID: taskID, Step: "send_email"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":1}`, string(execution.Result))
+
+	_, err = testQueries.GetTaskExecution(context.Background(), GetTaskExecutionParams{TaskID: taskID, Step: "completed"})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,119 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: task_failure.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const createTaskFailure = `-- name: CreateTaskFailure :one
+INSERT INTO task_failures (
+  task_id,
+  task_type,
+  queue,
+  error,
+  retried,
+  max_retry,
+  archived
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING id, task_id, task_type, queue, error, retried, max_retry, archived, created_at
+`
+
+type CreateTaskFailureParams struct {
+	TaskID   string `json:"task_id"`
+	TaskType string `json:"task_type"`
+	Queue    string `json:"queue"`
+	Error    string `json:"error"`
+	Retried  int32  `json:"retried"`
+	MaxRetry int32  `json:"max_retry"`
+	Archived bool   `json:"archived"`
+}
+
+func (q *Queries) CreateTaskFailure(ctx context.Context, arg CreateTaskFailureParams) (TaskFailure, error) {
+	row := q.db.QueryRowContext(ctx, createTaskFailure,
+		arg.TaskID,
+		arg.TaskType,
+		arg.Queue,
+		arg.Error,
+		arg.Retried,
+		arg.MaxRetry,
+		arg.Archived,
+	)
+	var i TaskFailure
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.TaskType,
+		&i.Queue,
+		&i.Error,
+		&i.Retried,
+		&i.MaxRetry,
+		&i.Archived,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteTaskFailures = `-- name: DeleteTaskFailures :execrows
+DELETE FROM task_failures
+WHERE created_at < $1
+`
+
+func (q *Queries) DeleteTaskFailures(ctx context.Context, createdAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTaskFailures, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const listTaskFailures = `-- name: ListTaskFailures :many
+SELECT id, task_id, task_type, queue, error, retried, max_retry, archived, created_at FROM task_failures
+WHERE task_id = $1
+ORDER BY id DESC
+LIMIT $2
+`
+
+type ListTaskFailuresParams struct {
+	TaskID string `json:"task_id"`
+	Limit  int32  `json:"limit"`
+}
+
+// 一个任务最近的几次失败，最新的在前
+func (q *Queries) ListTaskFailures(ctx context.Context, arg ListTaskFailuresParams) ([]TaskFailure, error) {
+	rows, err := q.db.QueryContext(ctx, listTaskFailures, arg.TaskID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TaskFailure{}
+	for rows.Next() {
+		var i TaskFailure
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskID,
+			&i.TaskType,
+			&i.Queue,
+			&i.Error,
+			&i.Retried,
+			&i.MaxRetry,
+			&i.Archived,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTaskFailures(t *testing.T) {
+	taskID := uuid.NewString()
+
+	var failures []TaskFailure
+	for i := 0; i < 3; i++ {
+		failure, err := testQueries.CreateTaskFailure(context.Background(), CreateTaskFailureParams{
+			TaskID:   taskID,
+			TaskType: "task:test",
+			Queue:    "critical",
+			Error:    "temporary error",
+			Retried:  int32(i),
+			MaxRetry: 2,
+			Archived: i == 2,
+		})
+		require.NoError(t, err)
+		require.NotZero(t, failure.CreatedAt)
+		failures = append(failures, failure)
+	}
+
+	// 最新的在前
+	listed, err := testQueries.ListTaskFailures(context.Background(), ListTaskFailuresParams{
+		TaskID: taskID,
+		Limit:  2,
+	})
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	require.Equal(t, failures[2].ID, listed[0].ID)
+	require.True(t, listed[0].Archived)
+	require.Equal(t, failures[1].ID, listed[1].ID)
+}
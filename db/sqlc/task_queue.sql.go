@@ -0,0 +1,360 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: task_queue.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const archiveTask = `-- name: ArchiveTask :exec
+UPDATE task_queue
+SET
+  state = 'archived',
+  lease_expires_at = NULL,
+  last_error = $1,
+  last_failed_at = now()
+WHERE id = $2
+`
+
+type ArchiveTaskParams struct {
+	LastError string `json:"last_error"`
+	ID        int64  `json:"id"`
+}
+
+// 重试用完或者不用重试的任务，留着排查，不再处理
+func (q *Queries) ArchiveTask(ctx context.Context, arg ArchiveTaskParams) error {
+	_, err := q.db.ExecContext(ctx, archiveTask, arg.LastError, arg.ID)
+	return err
+}
+
+const claimTask = `-- name: ClaimTask :one
+WITH exhausted AS (
+  UPDATE task_queue
+  SET
+    state = 'archived',
+    lease_expires_at = NULL,
+    last_error = 'task lease expired before the worker finished',
+    last_failed_at = now()
+  WHERE queue = ANY($2::varchar[])
+    AND state = 'active'
+    AND lease_expires_at < now()
+    AND retried >= max_retry
+)
+UPDATE task_queue
+SET
+  retried = retried + CASE WHEN state = 'active' THEN 1 ELSE 0 END,
+  last_error = CASE WHEN state = 'active' THEN 'task lease expired before the worker finished' ELSE last_error END,
+  last_failed_at = CASE WHEN state = 'active' THEN now() ELSE last_failed_at END,
+  state = 'active',
+  lease_expires_at = now() + make_interval(secs => $1::int)
+WHERE id = (
+  SELECT id FROM task_queue AS t
+  WHERE t.queue = ANY($2::varchar[])
+    AND (
+      (t.state = 'pending' AND t.process_at <= now())
+      OR (t.state = 'active' AND t.lease_expires_at < now() AND t.retried < t.max_retry)
+    )
+  ORDER BY array_position($2::varchar[], t.queue), t.process_at, t.id
+  FOR UPDATE SKIP LOCKED
+  LIMIT 1
+)
+RETURNING id, task_id, task_type, payload, queue, state, retried, max_retry, process_at, lease_expires_at, last_error, last_failed_at, completed_at, created_at
+`
+
+type ClaimTaskParams struct {
+	LeaseSeconds int32    `json:"lease_seconds"`
+	Queues       []string `json:"queues"`
+}
+
+// 按 queues 的顺序取一个到期的任务，租约到期前其他 worker 取不到
+// 租约过期的 active 任务说明处理它的 worker 已经退出，算一次失败：
+// 还有重试次数的重新取出来处理，retried 加一；用完的直接归档，不再处理
+func (q *Queries) ClaimTask(ctx context.Context, arg ClaimTaskParams) (TaskQueue, error) {
+	row := q.db.QueryRowContext(ctx, claimTask, arg.LeaseSeconds, pq.Array(arg.Queues))
+	var i TaskQueue
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.TaskType,
+		&i.Payload,
+		&i.Queue,
+		&i.State,
+		&i.Retried,
+		&i.MaxRetry,
+		&i.ProcessAt,
+		&i.LeaseExpiresAt,
+		&i.LastError,
+		&i.LastFailedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const completeTask = `-- name: CompleteTask :exec
+UPDATE task_queue
+SET
+  state = 'completed',
+  lease_expires_at = NULL,
+  completed_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) CompleteTask(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, completeTask, id)
+	return err
+}
+
+const deleteCompletedTasks = `-- name: DeleteCompletedTasks :execrows
+DELETE FROM task_queue
+WHERE state = 'completed' AND completed_at < $1::timestamptz
+`
+
+// 删掉以后同一个任务 ID 可以再入队，去重靠 task_executions
+func (q *Queries) DeleteCompletedTasks(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCompletedTasks, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteFailedTask = `-- name: DeleteFailedTask :one
+DELETE FROM task_queue
+WHERE task_id = $1
+  AND (state = 'archived' OR (state = 'pending' AND retried > 0))
+RETURNING id, task_id, task_type, payload, queue, state, retried, max_retry, process_at, lease_expires_at, last_error, last_failed_at, completed_at, created_at
+`
+
+func (q *Queries) DeleteFailedTask(ctx context.Context, taskID string) (TaskQueue, error) {
+	row := q.db.QueryRowContext(ctx, deleteFailedTask, taskID)
+	var i TaskQueue
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.TaskType,
+		&i.Payload,
+		&i.Queue,
+		&i.State,
+		&i.Retried,
+		&i.MaxRetry,
+		&i.ProcessAt,
+		&i.LeaseExpiresAt,
+		&i.LastError,
+		&i.LastFailedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const enqueueTask = `-- name: EnqueueTask :one
+INSERT INTO task_queue (
+  task_id,
+  task_type,
+  payload,
+  queue,
+  max_retry,
+  process_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (task_id) DO NOTHING
+RETURNING id, task_id, task_type, payload, queue, state, retried, max_retry, process_at, lease_expires_at, last_error, last_failed_at, completed_at, created_at
+`
+
+type EnqueueTaskParams struct {
+	TaskID    string          `json:"task_id"`
+	TaskType  string          `json:"task_type"`
+	Payload   json.RawMessage `json:"payload"`
+	Queue     string          `json:"queue"`
+	MaxRetry  int32           `json:"max_retry"`
+	ProcessAt time.Time       `json:"process_at"`
+}
+
+// task_id 已经存在时不插入，返回 sql.ErrNoRows
+func (q *Queries) EnqueueTask(ctx context.Context, arg EnqueueTaskParams) (TaskQueue, error) {
+	row := q.db.QueryRowContext(ctx, enqueueTask,
+		arg.TaskID,
+		arg.TaskType,
+		arg.Payload,
+		arg.Queue,
+		arg.MaxRetry,
+		arg.ProcessAt,
+	)
+	var i TaskQueue
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.TaskType,
+		&i.Payload,
+		&i.Queue,
+		&i.State,
+		&i.Retried,
+		&i.MaxRetry,
+		&i.ProcessAt,
+		&i.LeaseExpiresAt,
+		&i.LastError,
+		&i.LastFailedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getQueuedTask = `-- name: GetQueuedTask :one
+SELECT id, task_id, task_type, payload, queue, state, retried, max_retry, process_at, lease_expires_at, last_error, last_failed_at, completed_at, created_at FROM task_queue
+WHERE task_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetQueuedTask(ctx context.Context, taskID string) (TaskQueue, error) {
+	row := q.db.QueryRowContext(ctx, getQueuedTask, taskID)
+	var i TaskQueue
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.TaskType,
+		&i.Payload,
+		&i.Queue,
+		&i.State,
+		&i.Retried,
+		&i.MaxRetry,
+		&i.ProcessAt,
+		&i.LeaseExpiresAt,
+		&i.LastError,
+		&i.LastFailedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listFailedTasks = `-- name: ListFailedTasks :many
+SELECT id, task_id, task_type, payload, queue, state, retried, max_retry, process_at, lease_expires_at, last_error, last_failed_at, completed_at, created_at FROM task_queue
+WHERE (
+    ($1::text = 'archived' AND state = 'archived')
+    OR ($1::text = 'retry' AND state = 'pending' AND retried > 0)
+  )
+  AND ($2::varchar IS NULL OR queue = $2)
+  AND ($3::varchar IS NULL OR task_type = $3)
+ORDER BY last_failed_at DESC, id DESC
+LIMIT $5
+OFFSET $4
+`
+
+type ListFailedTasksParams struct {
+	State       string         `json:"state"`
+	Queue       sql.NullString `json:"queue"`
+	TaskType    sql.NullString `json:"task_type"`
+	OffsetCount int32          `json:"offset_count"`
+	LimitCount  int32          `json:"limit_count"`
+}
+
+// state 是 retry 时列出失败后等待重试的任务，archived 时列出不再重试的任务
+func (q *Queries) ListFailedTasks(ctx context.Context, arg ListFailedTasksParams) ([]TaskQueue, error) {
+	rows, err := q.db.QueryContext(ctx, listFailedTasks,
+		arg.State,
+		arg.Queue,
+		arg.TaskType,
+		arg.OffsetCount,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TaskQueue{}
+	for rows.Next() {
+		var i TaskQueue
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskID,
+			&i.TaskType,
+			&i.Payload,
+			&i.Queue,
+			&i.State,
+			&i.Retried,
+			&i.MaxRetry,
+			&i.ProcessAt,
+			&i.LeaseExpiresAt,
+			&i.LastError,
+			&i.LastFailedAt,
+			&i.CompletedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const retryTask = `-- name: RetryTask :exec
+UPDATE task_queue
+SET
+  state = 'pending',
+  retried = retried + 1,
+  process_at = $1,
+  lease_expires_at = NULL,
+  last_error = $2,
+  last_failed_at = now()
+WHERE id = $3
+`
+
+type RetryTaskParams struct {
+	ProcessAt time.Time `json:"process_at"`
+	LastError string    `json:"last_error"`
+	ID        int64     `json:"id"`
+}
+
+func (q *Queries) RetryTask(ctx context.Context, arg RetryTaskParams) error {
+	_, err := q.db.ExecContext(ctx, retryTask, arg.ProcessAt, arg.LastError, arg.ID)
+	return err
+}
+
+const runFailedTask = `-- name: RunFailedTask :one
+UPDATE task_queue
+SET
+  state = 'pending',
+  process_at = now()
+WHERE task_id = $1
+  AND (state = 'archived' OR (state = 'pending' AND retried > 0))
+RETURNING id, task_id, task_type, payload, queue, state, retried, max_retry, process_at, lease_expires_at, last_error, last_failed_at, completed_at, created_at
+`
+
+// 失败的任务马上再处理一次，重试次数不清零
+func (q *Queries) RunFailedTask(ctx context.Context, taskID string) (TaskQueue, error) {
+	row := q.db.QueryRowContext(ctx, runFailedTask, taskID)
+	var i TaskQueue
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.TaskType,
+		&i.Payload,
+		&i.Queue,
+		&i.State,
+		&i.Retried,
+		&i.MaxRetry,
+		&i.ProcessAt,
+		&i.LeaseExpiresAt,
+		&i.LastError,
+		&i.LastFailedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,197 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"simplebank/util"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func enqueueRandomTask(t *testing.T, queue string, processAt time.Time) TaskQueue {
+	arg := EnqueueTaskParams{
+		TaskID:    uuid.NewString(),
+		TaskType:  "task:test",
+		Payload:   json.RawMessage(`{}`),
+		Queue:     queue,
+		MaxRetry:  3,
+		ProcessAt: processAt,
+	}
+	task, err := testQueries.EnqueueTask(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.TaskID, task.TaskID)
+	require.Equal(t, "pending", task.State)
+	require.Zero(t, task.Retried)
+	return task
+}
+
+func TestEnqueueTaskConflict(t *testing.T) {
+	task := enqueueRandomTask(t, util.RandomString(8), time.Now())
+
+	// 同一个任务 ID 不会再插一行
+	_, err := testQueries.EnqueueTask(context.Background(), EnqueueTaskParams{
+		TaskID:    task.TaskID,
+		TaskType:  "task:other",
+		Payload:   json.RawMessage(`{}`),
+		Queue:     task.Queue,
+		ProcessAt: time.Now(),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	stored, err := testQueries.GetQueuedTask(context.Background(), task.TaskID)
+	require.NoError(t, err)
+	require.Equal(t, task.TaskType, stored.TaskType)
+}
+
+func TestClaimTask(t *testing.T) {
+	critical := util.RandomString(8)
+	normal := util.RandomString(8)
+	queues := []string{critical, normal}
+
+	normalTask := enqueueRandomTask(t, normal, time.Now().Add(-time.Minute))
+	criticalTask := enqueueRandomTask(t, critical, time.Now())
+	enqueueRandomTask(t, critical, time.Now().Add(time.Hour))
+
+	// 排在前面的队列先取，没到期的不取
+	claimed, err := testQueries.ClaimTask(context.Background(), ClaimTaskParams{LeaseSeconds: 60, Queues: queues})
+	require.NoError(t, err)
+	require.Equal(t, criticalTask.ID, claimed.ID)
+	require.Equal(t, "active", claimed.State)
+	require.True(t, claimed.LeaseExpiresAt.Valid)
+
+	claimed, err = testQueries.ClaimTask(context.Background(), ClaimTaskParams{LeaseSeconds: 60, Queues: queues})
+	require.NoError(t, err)
+	require.Equal(t, normalTask.ID, claimed.ID)
+
+	_, err = testQueries.ClaimTask(context.Background(), ClaimTaskParams{LeaseSeconds: 60, Queues: queues})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// 重试的任务到期后重新取出来
+	err = testQueries.RetryTask(context.Background(), RetryTaskParams{
+		ID:        criticalTask.ID,
+		ProcessAt: time.Now(),
+		LastError: "temporary error",
+	})
+	require.NoError(t, err)
+
+	claimed, err = testQueries.ClaimTask(context.Background(), ClaimTaskParams{LeaseSeconds: 60, Queues: queues})
+	require.NoError(t, err)
+	require.Equal(t, criticalTask.ID, claimed.ID)
+	require.Equal(t, int32(1), claimed.Retried)
+	require.Equal(t, "temporary error", claimed.LastError)
+
+	err = testQueries.CompleteTask(context.Background(), claimed.ID)
+	require.NoError(t, err)
+
+	err = testQueries.ArchiveTask(context.Background(), ArchiveTaskParams{ID: normalTask.ID, LastError: "bad payload"})
+	require.NoError(t, err)
+
+	stored, err := testQueries.GetQueuedTask(context.Background(), criticalTask.TaskID)
+	require.NoError(t, err)
+	require.Equal(t, "completed", stored.State)
+	require.True(t, stored.CompletedAt.Valid)
+
+	stored, err = testQueries.GetQueuedTask(context.Background(), normalTask.TaskID)
+	require.NoError(t, err)
+	require.Equal(t, "archived", stored.State)
+	require.False(t, stored.LeaseExpiresAt.Valid)
+}
+
+func TestClaimTaskExpiredLease(t *testing.T) {
+	queues := []string{util.RandomString(8)}
+	task := enqueueRandomTask(t, queues[0], time.Now())
+
+	claimed, err := testQueries.ClaimTask(context.Background(), ClaimTaskParams{LeaseSeconds: 0, Queues: queues})
+	require.NoError(t, err)
+	require.Equal(t, task.ID, claimed.ID)
+
+	// 租约过期说明 worker 没处理完就退出了，任务可以被再取一次
+	claimed, err = testQueries.ClaimTask(context.Background(), ClaimTaskParams{LeaseSeconds: 60, Queues: queues})
+	require.NoError(t, err)
+	require.Equal(t, task.ID, claimed.ID)
+	// 重新取出算一次重试
+	require.Equal(t, int32(1), claimed.Retried)
+	require.NotEmpty(t, claimed.LastError)
+	require.True(t, claimed.LastFailedAt.Valid)
+}
+
+func TestClaimTaskExpiredLeaseExhausted(t *testing.T) {
+	queues := []string{util.RandomString(8)}
+	task := enqueueRandomTask(t, queues[0], time.Now())
+
+	// 第一次取出加上三次租约过期后的重新取出，用完了 max_retry
+	for retried := int32(0); retried <= task.MaxRetry; retried++ {
+		claimed, err := testQueries.ClaimTask(context.Background(), ClaimTaskParams{LeaseSeconds: 0, Queues: queues})
+		require.NoError(t, err)
+		require.Equal(t, task.ID, claimed.ID)
+		require.Equal(t, retried, claimed.Retried)
+	}
+
+	// 再过期就归档，不再处理
+	_, err := testQueries.ClaimTask(context.Background(), ClaimTaskParams{LeaseSeconds: 60, Queues: queues})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	stored, err := testQueries.GetQueuedTask(context.Background(), task.TaskID)
+	require.NoError(t, err)
+	require.Equal(t, "archived", stored.State)
+	require.Equal(t, task.MaxRetry, stored.Retried)
+	require.False(t, stored.LeaseExpiresAt.Valid)
+	require.NotEmpty(t, stored.LastError)
+}
+
+func TestFailedTasks(t *testing.T) {
+	queue := util.RandomString(8)
+	queues := []string{queue}
+
+	archived := enqueueRandomTask(t, queue, time.Now())
+	retrying := enqueueRandomTask(t, queue, time.Now())
+	pending := enqueueRandomTask(t, queue, time.Now().Add(time.Hour))
+
+	for i := 0; i < 2; i++ {
+		_, err := testQueries.ClaimTask(context.Background(), ClaimTaskParams{LeaseSeconds: 60, Queues: queues})
+		require.NoError(t, err)
+	}
+	err := testQueries.ArchiveTask(context.Background(), ArchiveTaskParams{ID: archived.ID, LastError: "bad payload"})
+	require.NoError(t, err)
+	err = testQueries.RetryTask(context.Background(), RetryTaskParams{ID: retrying.ID, ProcessAt: time.Now().Add(time.Hour), LastError: "timeout"})
+	require.NoError(t, err)
+
+	tasks, err := testQueries.ListFailedTasks(context.Background(), ListFailedTasksParams{
+		State:      "archived",
+		Queue:      sql.NullString{String: queue, Valid: true},
+		LimitCount: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Equal(t, archived.ID, tasks[0].ID)
+
+	tasks, err = testQueries.ListFailedTasks(context.Background(), ListFailedTasksParams{
+		State:      "retry",
+		Queue:      sql.NullString{String: queue, Valid: true},
+		TaskType:   sql.NullString{String: "task:test", Valid: true},
+		LimitCount: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Equal(t, retrying.ID, tasks[0].ID)
+
+	// 没失败过的任务不能手动处理或删除
+	_, err = testQueries.RunFailedTask(context.Background(), pending.TaskID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = testQueries.DeleteFailedTask(context.Background(), pending.TaskID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	task, err := testQueries.RunFailedTask(context.Background(), archived.TaskID)
+	require.NoError(t, err)
+	require.Equal(t, "pending", task.State)
+	require.WithinDuration(t, time.Now(), task.ProcessAt, time.Second)
+
+	_, err = testQueries.DeleteFailedTask(context.Background(), retrying.TaskID)
+	require.NoError(t, err)
+	_, err = testQueries.GetQueuedTask(context.Background(), retrying.TaskID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -7,27 +7,73 @@ package db
 
 import (
 	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
 )
 
+const countMonthlyTransfersFromAccount = `-- name: CountMonthlyTransfersFromAccount :one
+SELECT count(*) FROM transfers
+WHERE from_account_id = $1
+AND status NOT IN ('rejected', 'expired', 'failed')
+AND created_at >= date_trunc('month', now())
+`
+
+// 等待审批和他行转账在途的也占次数，被拒绝、过期和失败的不算
+func (q *Queries) CountMonthlyTransfersFromAccount(ctx context.Context, fromAccountID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countMonthlyTransfersFromAccount, fromAccountID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  memo,
+  private_note,
+  external_reference,
+  metadata,
+  fee,
+  quote_id,
+  status,
+  hide_to_account
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
-RETURNING id, from_account_id, to_account_id, amount, created_at
+RETURNING id, from_account_id, to_account_id, amount, created_at, memo, private_note, external_reference, metadata, fee, quote_id, status, hide_to_account
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID     int64           `json:"from_account_id"`
+	ToAccountID       int64           `json:"to_account_id"`
+	Amount            int64           `json:"amount"`
+	Memo              string          `json:"memo"`
+	PrivateNote       string          `json:"private_note"`
+	ExternalReference string          `json:"external_reference"`
+	Metadata          json.RawMessage `json:"metadata"`
+	Fee               int64           `json:"fee"`
+	QuoteID           uuid.NullUUID   `json:"quote_id"`
+	Status            string          `json:"status"`
+	HideToAccount     bool            `json:"hide_to_account"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Memo,
+		arg.PrivateNote,
+		arg.ExternalReference,
+		arg.Metadata,
+		arg.Fee,
+		arg.QuoteID,
+		arg.Status,
+		arg.HideToAccount,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -35,6 +81,14 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Memo,
+		&i.PrivateNote,
+		&i.ExternalReference,
+		&i.Metadata,
+		&i.Fee,
+		&i.QuoteID,
+		&i.Status,
+		&i.HideToAccount,
 	)
 	return i, err
 }
@@ -50,7 +104,7 @@ func (q *Queries) DeleteTransfer(ctx context.Context, id int64) error {
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, private_note, external_reference, metadata, fee, quote_id, status, hide_to_account FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -63,12 +117,109 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Memo,
+		&i.PrivateNote,
+		&i.ExternalReference,
+		&i.Metadata,
+		&i.Fee,
+		&i.QuoteID,
+		&i.Status,
+		&i.HideToAccount,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, private_note, external_reference, metadata, fee, quote_id, status, hide_to_account FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Memo,
+		&i.PrivateNote,
+		&i.ExternalReference,
+		&i.Metadata,
+		&i.Fee,
+		&i.QuoteID,
+		&i.Status,
+		&i.HideToAccount,
 	)
 	return i, err
 }
 
+const listAccountTransfers = `-- name: ListAccountTransfers :many
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, private_note, external_reference, metadata, fee, quote_id, status, hide_to_account FROM transfers
+WHERE (from_account_id = $1 OR to_account_id = $1)
+  AND (
+    $2::text = ''
+    OR to_tsvector('simple', memo) @@ plainto_tsquery('simple', $2::text)
+  )
+ORDER BY id DESC
+LIMIT $4
+OFFSET $3
+`
+
+type ListAccountTransfersParams struct {
+	AccountID int64  `json:"account_id"`
+	Search    string `json:"search"`
+	Offset    int32  `json:"offset"`
+	Limit     int32  `json:"limit"`
+}
+
+// 账户流水，search 为空时不过滤，否则按备注全文搜索
+func (q *Queries) ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountTransfers,
+		arg.AccountID,
+		arg.Search,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Memo,
+			&i.PrivateNote,
+			&i.ExternalReference,
+			&i.Metadata,
+			&i.Fee,
+			&i.QuoteID,
+			&i.Status,
+			&i.HideToAccount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, private_note, external_reference, metadata, fee, quote_id, status, hide_to_account FROM transfers
 ORDER BY id
 LIMIT $1
 OFFSET $2
@@ -94,6 +245,14 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Memo,
+			&i.PrivateNote,
+			&i.ExternalReference,
+			&i.Metadata,
+			&i.Fee,
+			&i.QuoteID,
+			&i.Status,
+			&i.HideToAccount,
 		); err != nil {
 			return nil, err
 		}
@@ -109,7 +268,7 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 }
 
 const listTransfersByFromAccount = `-- name: ListTransfersByFromAccount :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, private_note, external_reference, metadata, fee, quote_id, status, hide_to_account FROM transfers
 WHERE from_account_id = $1
 ORDER BY id
 LIMIT $2
@@ -137,6 +296,14 @@ func (q *Queries) ListTransfersByFromAccount(ctx context.Context, arg ListTransf
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Memo,
+			&i.PrivateNote,
+			&i.ExternalReference,
+			&i.Metadata,
+			&i.Fee,
+			&i.QuoteID,
+			&i.Status,
+			&i.HideToAccount,
 		); err != nil {
 			return nil, err
 		}
@@ -152,7 +319,7 @@ func (q *Queries) ListTransfersByFromAccount(ctx context.Context, arg ListTransf
 }
 
 const listTransfersByToAccount = `-- name: ListTransfersByToAccount :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, private_note, external_reference, metadata, fee, quote_id, status, hide_to_account FROM transfers
 WHERE to_account_id = $1
 ORDER BY id
 LIMIT $2
@@ -180,6 +347,14 @@ func (q *Queries) ListTransfersByToAccount(ctx context.Context, arg ListTransfer
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Memo,
+			&i.PrivateNote,
+			&i.ExternalReference,
+			&i.Metadata,
+			&i.Fee,
+			&i.QuoteID,
+			&i.Status,
+			&i.HideToAccount,
 		); err != nil {
 			return nil, err
 		}
@@ -194,11 +369,45 @@ func (q *Queries) ListTransfersByToAccount(ctx context.Context, arg ListTransfer
 	return items, nil
 }
 
+const updatePendingTransferStatus = `-- name: UpdatePendingTransferStatus :one
+UPDATE transfers
+SET status = $2
+WHERE id = $1 AND status = 'pending_approval'
+RETURNING id, from_account_id, to_account_id, amount, created_at, memo, private_note, external_reference, metadata, fee, quote_id, status, hide_to_account
+`
+
+type UpdatePendingTransferStatusParams struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+}
+
+// 只有等待审批的转账能改状态，否则返回 no rows
+func (q *Queries) UpdatePendingTransferStatus(ctx context.Context, arg UpdatePendingTransferStatusParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, updatePendingTransferStatus, arg.ID, arg.Status)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Memo,
+		&i.PrivateNote,
+		&i.ExternalReference,
+		&i.Metadata,
+		&i.Fee,
+		&i.QuoteID,
+		&i.Status,
+		&i.HideToAccount,
+	)
+	return i, err
+}
+
 const updateTransfer = `-- name: UpdateTransfer :one
 UPDATE transfers
 SET amount = $2
 WHERE id = $1
-RETURNING id, from_account_id, to_account_id, amount, created_at
+RETURNING id, from_account_id, to_account_id, amount, created_at, memo, private_note, external_reference, metadata, fee, quote_id, status, hide_to_account
 `
 
 type UpdateTransferParams struct {
@@ -215,6 +424,48 @@ func (q *Queries) UpdateTransfer(ctx context.Context, arg UpdateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Memo,
+		&i.PrivateNote,
+		&i.ExternalReference,
+		&i.Metadata,
+		&i.Fee,
+		&i.QuoteID,
+		&i.Status,
+		&i.HideToAccount,
+	)
+	return i, err
+}
+
+const updateTransferStatus = `-- name: UpdateTransferStatus :one
+UPDATE transfers
+SET status = $2
+WHERE id = $1
+RETURNING id, from_account_id, to_account_id, amount, created_at, memo, private_note, external_reference, metadata, fee, quote_id, status, hide_to_account
+`
+
+type UpdateTransferStatusParams struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+}
+
+// 调用方先锁住转账并检查状态流转是否合法
+func (q *Queries) UpdateTransferStatus(ctx context.Context, arg UpdateTransferStatusParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, updateTransferStatus, arg.ID, arg.Status)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Memo,
+		&i.PrivateNote,
+		&i.ExternalReference,
+		&i.Metadata,
+		&i.Fee,
+		&i.QuoteID,
+		&i.Status,
+		&i.HideToAccount,
 	)
 	return i, err
 }
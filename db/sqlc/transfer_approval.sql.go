@@ -0,0 +1,201 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: transfer_approval.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createTransferApproval = `-- name: CreateTransferApproval :one
+INSERT INTO transfer_approvals (
+  transfer_id,
+  requested_by,
+  expires_at
+) VALUES (
+  $1, $2, $3
+)
+RETURNING transfer_id, requested_by, decided_by, expires_at, decided_at, created_at
+`
+
+type CreateTransferApprovalParams struct {
+	TransferID  int64     `json:"transfer_id"`
+	RequestedBy string    `json:"requested_by"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateTransferApproval(ctx context.Context, arg CreateTransferApprovalParams) (TransferApproval, error) {
+	row := q.db.QueryRowContext(ctx, createTransferApproval, arg.TransferID, arg.RequestedBy, arg.ExpiresAt)
+	var i TransferApproval
+	err := row.Scan(
+		&i.TransferID,
+		&i.RequestedBy,
+		&i.DecidedBy,
+		&i.ExpiresAt,
+		&i.DecidedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const decideTransferApproval = `-- name: DecideTransferApproval :one
+UPDATE transfer_approvals
+SET
+  decided_by = $1,
+  decided_at = now()
+WHERE transfer_id = $2
+RETURNING transfer_id, requested_by, decided_by, expires_at, decided_at, created_at
+`
+
+type DecideTransferApprovalParams struct {
+	DecidedBy  sql.NullString `json:"decided_by"`
+	TransferID int64          `json:"transfer_id"`
+}
+
+func (q *Queries) DecideTransferApproval(ctx context.Context, arg DecideTransferApprovalParams) (TransferApproval, error) {
+	row := q.db.QueryRowContext(ctx, decideTransferApproval, arg.DecidedBy, arg.TransferID)
+	var i TransferApproval
+	err := row.Scan(
+		&i.TransferID,
+		&i.RequestedBy,
+		&i.DecidedBy,
+		&i.ExpiresAt,
+		&i.DecidedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getTransferApproval = `-- name: GetTransferApproval :one
+SELECT transfer_id, requested_by, decided_by, expires_at, decided_at, created_at FROM transfer_approvals
+WHERE transfer_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetTransferApproval(ctx context.Context, transferID int64) (TransferApproval, error) {
+	row := q.db.QueryRowContext(ctx, getTransferApproval, transferID)
+	var i TransferApproval
+	err := row.Scan(
+		&i.TransferID,
+		&i.RequestedBy,
+		&i.DecidedBy,
+		&i.ExpiresAt,
+		&i.DecidedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listExpiredTransferApprovals = `-- name: ListExpiredTransferApprovals :many
+SELECT transfer_approvals.transfer_id
+FROM transfer_approvals
+JOIN transfers ON transfers.id = transfer_approvals.transfer_id
+WHERE transfers.status = 'pending_approval' AND transfer_approvals.expires_at <= now()
+ORDER BY transfer_approvals.transfer_id
+`
+
+func (q *Queries) ListExpiredTransferApprovals(ctx context.Context) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listExpiredTransferApprovals)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var transfer_id int64
+		if err := rows.Scan(&transfer_id); err != nil {
+			return nil, err
+		}
+		items = append(items, transfer_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingTransferApprovals = `-- name: ListPendingTransferApprovals :many
+SELECT transfers.id, transfers.from_account_id, transfers.to_account_id, transfers.amount, transfers.created_at, transfers.memo, transfers.private_note, transfers.external_reference, transfers.metadata, transfers.fee, transfers.quote_id, transfers.status, transfers.hide_to_account, transfer_approvals.transfer_id, transfer_approvals.requested_by, transfer_approvals.decided_by, transfer_approvals.expires_at, transfer_approvals.decided_at, transfer_approvals.created_at
+FROM transfers
+JOIN transfer_approvals ON transfer_approvals.transfer_id = transfers.id
+WHERE transfers.status = 'pending_approval'
+  AND (
+    $1::bool
+    OR EXISTS (
+      SELECT 1 FROM account_members
+      WHERE account_members.account_id = transfers.from_account_id
+        AND account_members.username = $2
+        AND account_members.role IN ('owner', 'co_owner')
+    )
+  )
+ORDER BY transfers.id
+LIMIT $4
+OFFSET $3
+`
+
+type ListPendingTransferApprovalsParams struct {
+	AllAccounts bool   `json:"all_accounts"`
+	Username    string `json:"username"`
+	Offset      int32  `json:"offset"`
+	Limit       int32  `json:"limit"`
+}
+
+type ListPendingTransferApprovalsRow struct {
+	Transfer         Transfer         `json:"transfer"`
+	TransferApproval TransferApproval `json:"transfer_approval"`
+}
+
+// 银行职员看全部，其他用户只看自己有转账权限的账户
+func (q *Queries) ListPendingTransferApprovals(ctx context.Context, arg ListPendingTransferApprovalsParams) ([]ListPendingTransferApprovalsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPendingTransferApprovals,
+		arg.AllAccounts,
+		arg.Username,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPendingTransferApprovalsRow{}
+	for rows.Next() {
+		var i ListPendingTransferApprovalsRow
+		if err := rows.Scan(
+			&i.Transfer.ID,
+			&i.Transfer.FromAccountID,
+			&i.Transfer.ToAccountID,
+			&i.Transfer.Amount,
+			&i.Transfer.CreatedAt,
+			&i.Transfer.Memo,
+			&i.Transfer.PrivateNote,
+			&i.Transfer.ExternalReference,
+			&i.Transfer.Metadata,
+			&i.Transfer.Fee,
+			&i.Transfer.QuoteID,
+			&i.Transfer.Status,
+			&i.Transfer.HideToAccount,
+			&i.TransferApproval.TransferID,
+			&i.TransferApproval.RequestedBy,
+			&i.TransferApproval.DecidedBy,
+			&i.TransferApproval.ExpiresAt,
+			&i.TransferApproval.DecidedAt,
+			&i.TransferApproval.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"simplebank/util"
+)
+
+type ACHFileEntry struct {
+	TransferID  int64  `json:"transfer_id"`
+	TraceNumber string `json:"trace_number"`
+}
+
+type CreateACHFileTxParams struct {
+	FileIDModifier string         `json:"file_id_modifier"`
+	TotalCredit    int64          `json:"total_credit"`
+	Content        string         `json:"content"`
+	Entries        []ACHFileEntry `json:"entries"`
+}
+
+type CreateACHFileTxResult struct {
+	ACHFile          AchFile           `json:"ach_file"`
+	ExternalPayments []ExternalPayment `json:"external_payments"`
+}
+
+// CreateACHFileTx 保存导出的批量文件，文件里的每笔付款都按 trace number 提交，状态 initiated → pending
+// 任何一笔已经不是 initiated（比如被并发导出过）整个文件作废
+func (store *SQLStore) CreateACHFileTx(ctx context.Context, arg CreateACHFileTxParams) (CreateACHFileTxResult, error) {
+	var result CreateACHFileTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+		result.ACHFile, err = q.CreateACHFile(ctx, CreateACHFileParams{
+			FileIDModifier: arg.FileIDModifier,
+			EntryCount:     int32(len(arg.Entries)),
+			TotalCredit:    arg.TotalCredit,
+			Content:        arg.Content,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range arg.Entries {
+			_, _, err = transitionExternalTransfer(ctx, q, entry.TransferID, util.TransferStatusPending)
+			if err != nil {
+				return err
+			}
+
+			payment, err := q.MarkExternalPaymentSubmitted(ctx, MarkExternalPaymentSubmittedParams{
+				TransferID:       entry.TransferID,
+				NetworkReference: sql.NullString{String: entry.TraceNumber, Valid: true},
+				AchFileID:        sql.NullInt64{Int64: result.ACHFile.ID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			result.ExternalPayments = append(result.ExternalPayments, payment)
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"simplebank/util"
+	"time"
+)
+
+type ChargeOverdraftInterestTxParams struct {
+	AccountID          int64     `json:"account_id"`
+	ChargeDate         time.Time `json:"charge_date"`
+	Balance            int64     `json:"balance"` // 日终余额，必须为负
+	AnnualRateBps      int32     `json:"annual_rate_bps"`
+	DayCountConvention string    `json:"day_count_convention"`
+}
+
+type ChargeOverdraftInterestTxResult struct {
+	Charge         OverdraftCharge `json:"charge"`
+	Account        Account         `json:"account"`
+	AlreadyCharged bool            `json:"already_charged"`
+	// 扣息后用满了透支额度，需要通知客户
+	LimitReached bool `json:"limit_reached"`
+}
+
+// ChargeOverdraftInterestTx 按日终透支余额扣一天的透支利息，转入银行透支利息收入账户
+// 不足一个最小单位的零头结转到下一次，扣息不会突破透支额度，超出部分同样结转
+func (store *SQLStore) ChargeOverdraftInterestTx(ctx context.Context, arg ChargeOverdraftInterestTxParams) (ChargeOverdraftInterestTxResult, error) {
+	var result ChargeOverdraftInterestTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		result.Account = account
+
+		var carried int64
+		latest, err := q.GetLatestOverdraftCharge(ctx, arg.AccountID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if err == nil && latest.ChargeDate.Before(arg.ChargeDate) {
+			carried = latest.ResidualMicros
+		}
+
+		micros, err := util.DailyInterestMicros(-arg.Balance, arg.AnnualRateBps, arg.DayCountConvention, arg.ChargeDate)
+		if err != nil {
+			return err
+		}
+		micros += carried
+
+		amount := micros / util.MicrosPerMinorUnit
+		available := max(account.Balance+account.OverdraftLimit, 0)
+		if amount > 0 && amount >= available {
+			amount = available
+			result.LimitReached = true
+		}
+
+		result.Charge, err = q.CreateOverdraftCharge(ctx, CreateOverdraftChargeParams{
+			AccountID:      arg.AccountID,
+			ChargeDate:     arg.ChargeDate,
+			Balance:        arg.Balance,
+			AnnualRateBps:  arg.AnnualRateBps,
+			AmountMicros:   micros,
+			Amount:         amount,
+			ResidualMicros: micros - amount*util.MicrosPerMinorUnit,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				result.AlreadyCharged = true
+				result.LimitReached = false
+				return nil
+			}
+			return err
+		}
+
+		if amount == 0 {
+			return nil
+		}
+
+		incomeAccount, err := getOrCreateSystemAccount(ctx, q, account.Currency, util.OverdraftIncomeProduct)
+		if err != nil {
+			return err
+		}
+
+		transfer, err := insertTransfer(ctx, q, TransferTxParams{
+			FromAccountID: account.ID,
+			ToAccountID:   incomeAccount.ID,
+			Amount:        amount,
+			Memo:          fmt.Sprintf("Overdraft interest for %s", arg.ChargeDate.Format(time.DateOnly)),
+		}, 0, util.TransferStatusCompleted)
+		if err != nil {
+			return err
+		}
+
+		posted, err := postTransfer(ctx, q, transfer, account.Currency)
+		if err != nil {
+			return err
+		}
+		result.Account = posted.FromAccount
+
+		result.Charge, err = q.UpdateOverdraftChargeTransfer(ctx, UpdateOverdraftChargeTransferParams{
+			ID:         result.Charge.ID,
+			TransferID: sql.NullInt64{Int64: transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"simplebank/util"
+
+	"github.com/lib/pq"
+)
+
+// maxAccountNumberAttempts 账号撞上已有账号时重新生成的次数
+const maxAccountNumberAttempts = 3
+
+type CreateAccountTxResult struct {
+	Account Account       `json:"account"`
+	Member  AccountMember `json:"member"`
+}
+
+// CreateAccountTx 开户的同时把开户人登记为 owner 成员
+// 随机账号和已有账号重复时按同一个银行代码重新生成，其他唯一约束冲突照常返回
+func (store *SQLStore) CreateAccountTx(ctx context.Context, arg CreateAccountParams) (CreateAccountTxResult, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := store.createAccountTx(ctx, arg)
+		if !isAccountNumberConflict(err) {
+			return result, err
+		}
+		if attempt == maxAccountNumberAttempts {
+			// 包一层，调用方不会当成账户已存在
+			return result, fmt.Errorf("failed to generate a unique account number: %w", err)
+		}
+
+		arg.AccountNumber, err = util.NewAccountNumber(util.AccountNumberBankCode(arg.AccountNumber))
+		if err != nil {
+			return result, err
+		}
+	}
+}
+
+func (store *SQLStore) createAccountTx(ctx context.Context, arg CreateAccountParams) (CreateAccountTxResult, error) {
+	var result CreateAccountTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+
+		result.Account, err = q.CreateAccount(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		result.Member, err = q.CreateAccountMember(ctx, CreateAccountMemberParams{
+			AccountID: result.Account.ID,
+			Username:  arg.Owner,
+			Role:      util.MemberRoleOwner,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// isAccountNumberConflict 唯一索引由 000015 迁移创建，用的是 Postgres 默认的索引名
+func isAccountNumberConflict(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code.Name() == "unique_violation" && pqErr.Constraint == "accounts_account_number_idx"
+}
@@ -6,7 +6,8 @@ import (
 
 type CreateUserTxParams struct {
 	CreateUserParams
-	AfterCreate func(user User) error
+	// 在同一个事务里执行，q 是事务内的查询，可以用来写 outbox
+	AfterCreate func(q Querier, user User) error
 }
 
 type CreateUserTxResult struct {
@@ -23,7 +24,7 @@ func (store *SQLStore) CreateUserTx(ctx context.Context, arg CreateUserTxParams)
 			return err
 		}
 
-		return arg.AfterCreate(result.User)
+		return arg.AfterCreate(q, result.User)
 	})
 
 	return result, err
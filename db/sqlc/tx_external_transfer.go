@@ -0,0 +1,312 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"simplebank/util"
+	"time"
+)
+
+type InitiateExternalTransferTxParams struct {
+	FromAccountID         int64           `json:"from_account_id"`
+	Amount                int64           `json:"amount"`
+	Memo                  string          `json:"memo"`
+	PrivateNote           string          `json:"private_note"`
+	ExternalReference     string          `json:"external_reference"`
+	Metadata              json.RawMessage `json:"metadata"`
+	Network               string          `json:"network"`
+	CreditorAccountNumber string          `json:"creditor_account_number"`
+	CreditorName          string          `json:"creditor_name"`
+	// 只有批量网络需要收款行的路由号
+	CreditorRoutingNumber string `json:"creditor_routing_number"`
+	// 在同一个事务里执行，用来写提交清算网络的 outbox 任务
+	AfterInitiate func(q Querier, result InitiateExternalTransferTxResult) error `json:"-"`
+}
+
+type InitiateExternalTransferTxResult struct {
+	TransferTxResult
+	ExternalPayment ExternalPayment `json:"external_payment"`
+}
+
+// InitiateExternalTransferTx 把钱从客户账户转进清算账户，转账状态为 initiated，之后由 worker 提交给清算网络
+func (store *SQLStore) InitiateExternalTransferTx(ctx context.Context, arg InitiateExternalTransferTxParams) (InitiateExternalTransferTxResult, error) {
+	var result InitiateExternalTransferTxResult
+
+	fromAccount, err := store.GetAccount(ctx, arg.FromAccountID)
+	if err != nil {
+		return result, err
+	}
+
+	// 系统账户在事务外创建，事务里只按 ID 顺序加锁，避免和退款事务互相等待
+	clearing, err := getOrCreateSystemAccount(ctx, store.Queries, fromAccount.Currency, util.ExternalClearingProduct)
+	if err != nil {
+		return result, err
+	}
+
+	err = store.execTX(ctx, func(q *Queries) error {
+		var err error
+		result.TransferTxResult, err = transferWithStatus(ctx, q, arg.clearingTransfer(clearing), util.TransferStatusInitiated)
+		if err != nil {
+			return err
+		}
+
+		result.ExternalPayment, err = q.CreateExternalPayment(ctx, arg.externalPayment(result.Transfer.ID))
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterInitiate != nil {
+			return arg.AfterInitiate(q, result)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+type InitiatePendingExternalTransferTxParams struct {
+	// AfterInitiate 不会调用，批准后由 ApproveTransferTxParams.AfterApprove 提交清算网络
+	InitiateExternalTransferTxParams
+	RequestedBy string    `json:"requested_by"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	// 在同一个事务里执行，用来写 webhook 事件的 outbox 任务
+	AfterCreate func(q Querier, result InitiatePendingExternalTransferTxResult) error `json:"-"`
+}
+
+type InitiatePendingExternalTransferTxResult struct {
+	CreatePendingTransferTxResult
+	ExternalPayment ExternalPayment `json:"external_payment"`
+}
+
+// InitiatePendingExternalTransferTx 超过审批阈值的他行转账先待审批并占用资金，
+// 批准后转账变为 initiated 再提交清算网络，拒绝或过期时不会提交
+func (store *SQLStore) InitiatePendingExternalTransferTx(ctx context.Context, arg InitiatePendingExternalTransferTxParams) (InitiatePendingExternalTransferTxResult, error) {
+	var result InitiatePendingExternalTransferTxResult
+
+	fromAccount, err := store.GetAccount(ctx, arg.FromAccountID)
+	if err != nil {
+		return result, err
+	}
+
+	clearing, err := getOrCreateSystemAccount(ctx, store.Queries, fromAccount.Currency, util.ExternalClearingProduct)
+	if err != nil {
+		return result, err
+	}
+
+	err = store.execTX(ctx, func(q *Queries) error {
+		var err error
+		result.CreatePendingTransferTxResult, err = createPendingTransfer(ctx, q, CreatePendingTransferTxParams{
+			TransferTxParams: arg.clearingTransfer(clearing),
+			RequestedBy:      arg.RequestedBy,
+			ExpiresAt:        arg.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ExternalPayment, err = q.CreateExternalPayment(ctx, arg.externalPayment(result.Transfer.ID))
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterCreate != nil {
+			return arg.AfterCreate(q, result)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// clearingTransfer 客户账户转入清算账户的那笔转账
+func (arg InitiateExternalTransferTxParams) clearingTransfer(clearing Account) TransferTxParams {
+	return TransferTxParams{
+		FromAccountID:     arg.FromAccountID,
+		ToAccountID:       clearing.ID,
+		Amount:            arg.Amount,
+		Memo:              arg.Memo,
+		PrivateNote:       arg.PrivateNote,
+		ExternalReference: arg.ExternalReference,
+		Metadata:          arg.Metadata,
+	}
+}
+
+func (arg InitiateExternalTransferTxParams) externalPayment(transferID int64) CreateExternalPaymentParams {
+	return CreateExternalPaymentParams{
+		TransferID:            transferID,
+		Network:               arg.Network,
+		CreditorAccountNumber: arg.CreditorAccountNumber,
+		CreditorName:          arg.CreditorName,
+		CreditorRoutingNumber: arg.CreditorRoutingNumber,
+	}
+}
+
+type ExternalTransferTxResult struct {
+	Transfer        Transfer        `json:"transfer"`
+	ExternalPayment ExternalPayment `json:"external_payment"`
+}
+
+type SubmitExternalTransferTxParams struct {
+	TransferID       int64  `json:"transfer_id"`
+	NetworkReference string `json:"network_reference"`
+}
+
+// SubmitExternalTransferTx 清算网络受理后记下网络流水号，状态 initiated → pending
+func (store *SQLStore) SubmitExternalTransferTx(ctx context.Context, arg SubmitExternalTransferTxParams) (ExternalTransferTxResult, error) {
+	var result ExternalTransferTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+		_, result.Transfer, err = transitionExternalTransfer(ctx, q, arg.TransferID, util.TransferStatusPending)
+		if err != nil {
+			return err
+		}
+
+		result.ExternalPayment, err = q.MarkExternalPaymentSubmitted(ctx, MarkExternalPaymentSubmittedParams{
+			TransferID:       arg.TransferID,
+			NetworkReference: sql.NullString{String: arg.NetworkReference, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// SettleExternalTransferTx 清算完成，钱从清算账户转入结算账户，状态 pending → settled
+func (store *SQLStore) SettleExternalTransferTx(ctx context.Context, transferID int64) (ExternalTransferTxResult, error) {
+	var result ExternalTransferTxResult
+
+	clearing, settlement, err := store.externalSystemAccounts(ctx, transferID)
+	if err != nil {
+		return result, err
+	}
+
+	err = store.execTX(ctx, func(q *Queries) error {
+		var err error
+		_, result.Transfer, err = transitionExternalTransfer(ctx, q, transferID, util.TransferStatusSettled)
+		if err != nil {
+			return err
+		}
+
+		booking, err := transfer(ctx, q, TransferTxParams{
+			FromAccountID: clearing.ID,
+			ToAccountID:   settlement.ID,
+			Amount:        result.Transfer.Amount,
+			Memo:          fmt.Sprintf("Settlement of transfer %d", transferID),
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ExternalPayment, err = q.MarkExternalPaymentSettled(ctx, MarkExternalPaymentSettledParams{
+			TransferID:           transferID,
+			SettlementTransferID: sql.NullInt64{Int64: booking.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
+
+type ReverseExternalTransferTxParams struct {
+	TransferID int64 `json:"transfer_id"`
+	// failed 或 returned
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// ReverseExternalTransferTx 把金额退回客户，手续费不退
+// 还没清算的从清算账户退，已经清算后被退回的从结算账户退
+func (store *SQLStore) ReverseExternalTransferTx(ctx context.Context, arg ReverseExternalTransferTxParams) (ExternalTransferTxResult, error) {
+	var result ExternalTransferTxResult
+
+	clearing, settlement, err := store.externalSystemAccounts(ctx, arg.TransferID)
+	if err != nil {
+		return result, err
+	}
+
+	err = store.execTX(ctx, func(q *Queries) error {
+		previous, updated, err := transitionExternalTransfer(ctx, q, arg.TransferID, arg.Status)
+		if err != nil {
+			return err
+		}
+		result.Transfer = updated
+
+		source := clearing
+		if previous == util.TransferStatusSettled {
+			source = settlement
+		}
+
+		// 退款不是客户发起的，不做冻结、限额这些检查，账户被冻结或关闭也要退回去
+		_, _, err = lockAccounts(ctx, q, source.ID, updated.FromAccountID)
+		if err != nil {
+			return err
+		}
+
+		refund, err := insertTransfer(ctx, q, TransferTxParams{
+			FromAccountID: source.ID,
+			ToAccountID:   updated.FromAccountID,
+			Amount:        updated.Amount,
+			Memo:          fmt.Sprintf("Return of transfer %d", arg.TransferID),
+		}, 0, util.TransferStatusCompleted)
+		if err != nil {
+			return err
+		}
+
+		_, err = postTransfer(ctx, q, refund, source.Currency)
+		if err != nil {
+			return err
+		}
+
+		result.ExternalPayment, err = q.MarkExternalPaymentReversed(ctx, MarkExternalPaymentReversedParams{
+			TransferID:         arg.TransferID,
+			ReversalTransferID: sql.NullInt64{Int64: refund.ID, Valid: true},
+			FailureReason:      arg.Reason,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// externalSystemAccounts 清算账户就是发起时的转入账户，结算账户按同币种懒创建
+func (store *SQLStore) externalSystemAccounts(ctx context.Context, transferID int64) (clearing Account, settlement Account, err error) {
+	original, err := store.GetTransfer(ctx, transferID)
+	if err != nil {
+		return
+	}
+
+	clearing, err = store.GetAccount(ctx, original.ToAccountID)
+	if err != nil {
+		return
+	}
+	if clearing.ProductType != util.ExternalClearingProduct {
+		err = fmt.Errorf("transfer [%d] is not an external transfer: %w", transferID, ErrInvalidTransferTransition)
+		return
+	}
+
+	settlement, err = getOrCreateSystemAccount(ctx, store.Queries, clearing.Currency, util.ExternalSettlementProduct)
+	return
+}
+
+// transitionExternalTransfer 锁住转账后按状态机改状态，返回原来的状态
+func transitionExternalTransfer(ctx context.Context, q *Queries, transferID int64, status string) (previous string, updated Transfer, err error) {
+	locked, err := q.GetTransferForUpdate(ctx, transferID)
+	if err != nil {
+		return
+	}
+
+	previous = locked.Status
+	if !util.CanTransitionExternalTransfer(previous, status) {
+		err = fmt.Errorf("transfer [%d] cannot go from %s to %s: %w", transferID, previous, status, ErrInvalidTransferTransition)
+		return
+	}
+
+	updated, err = q.UpdateTransferStatus(ctx, UpdateTransferStatusParams{
+		ID:     transferID,
+		Status: status,
+	})
+	return
+}
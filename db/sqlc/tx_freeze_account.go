@@ -0,0 +1,49 @@
+package db
+
+import "context"
+
+type FreezeAccountTxParams struct {
+	AccountID    int64  `json:"account_id"`
+	FreezeStatus string `json:"freeze_status"`
+	Reason       string `json:"reason"`
+	Actor        string `json:"actor"`
+}
+
+type FreezeAccountTxResult struct {
+	Account       Account       `json:"account"`
+	AccountFreeze AccountFreeze `json:"account_freeze"`
+}
+
+// FreezeAccountTx 修改账户冻结状态并记录原因、操作人和时间
+// 解冻同样走这里，FreezeStatus 传 active 即可
+func (store *SQLStore) FreezeAccountTx(ctx context.Context, arg FreezeAccountTxParams) (FreezeAccountTxResult, error) {
+	var result FreezeAccountTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+
+		// 先锁住账户行，和 TransferTX 互斥
+		_, err = q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.UpdateAccountFreezeStatus(ctx, UpdateAccountFreezeStatusParams{
+			ID:           arg.AccountID,
+			FreezeStatus: arg.FreezeStatus,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.AccountFreeze, err = q.CreateAccountFreeze(ctx, CreateAccountFreezeParams{
+			AccountID:    arg.AccountID,
+			FreezeStatus: arg.FreezeStatus,
+			Reason:       arg.Reason,
+			Actor:        arg.Actor,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+)
+
+type PublishOutboxTxParams struct {
+	Limit int32
+	// 把一条消息投递到任务队列，返回 error 时这条消息留着下次再投
+	Publish func(message Outbox) error
+}
+
+type PublishOutboxTxResult struct {
+	Published int
+	Failed    int
+}
+
+// PublishOutboxTx 在事务里锁住一批待投递的消息逐条投递，投递成功的标记为已发布
+// 投递成功但提交失败时下次会再投一次，所以投递是 at-least-once，由任务 ID 去重
+func (store *SQLStore) PublishOutboxTx(ctx context.Context, arg PublishOutboxTxParams) (PublishOutboxTxResult, error) {
+	var result PublishOutboxTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		messages, err := q.ListUnpublishedOutboxMessages(ctx, arg.Limit)
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			if publishErr := arg.Publish(message); publishErr != nil {
+				result.Failed++
+				err = q.RecordOutboxMessageFailure(ctx, RecordOutboxMessageFailureParams{
+					ID:        message.ID,
+					LastError: publishErr.Error(),
+				})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			err = q.MarkOutboxMessagePublished(ctx, message.ID)
+			if err != nil {
+				return err
+			}
+			result.Published++
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"simplebank/util"
+	"time"
+)
+
+type PayPaymentRequestTxParams struct {
+	PaymentRequestID int64 `json:"payment_request_id"`
+	FromAccountID    int64 `json:"from_account_id"`
+	// 在同一个事务里执行，用来写通知的 outbox 任务
+	AfterPay func(q Querier, result PayPaymentRequestTxResult) error `json:"-"`
+}
+
+type PayPaymentRequestTxResult struct {
+	PaymentRequest PaymentRequest   `json:"payment_request"`
+	Transfer       TransferTxResult `json:"transfer"`
+}
+
+// PayPaymentRequestTx 转账和把请求标记为已付在同一个事务里，请求行先加锁，防止重复付款
+func (store *SQLStore) PayPaymentRequestTx(ctx context.Context, arg PayPaymentRequestTxParams) (PayPaymentRequestTxResult, error) {
+	var result PayPaymentRequestTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		request, err := lockPendingPaymentRequest(ctx, q, arg.PaymentRequestID)
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, err = transfer(ctx, q, paymentRequestTransfer(request, arg.FromAccountID))
+		if err != nil {
+			return err
+		}
+
+		result.PaymentRequest, err = q.MarkPaymentRequestPaid(ctx, MarkPaymentRequestPaidParams{
+			ID:         request.ID,
+			TransferID: sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterPay != nil {
+			return arg.AfterPay(q, result)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+type PayPaymentRequestPendingTxParams struct {
+	PaymentRequestID int64     `json:"payment_request_id"`
+	FromAccountID    int64     `json:"from_account_id"`
+	RequestedBy      string    `json:"requested_by"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	// 在同一个事务里执行，用来写 webhook 事件的 outbox 任务
+	AfterPay func(q Querier, result PayPaymentRequestPendingTxResult) error `json:"-"`
+}
+
+type PayPaymentRequestPendingTxResult struct {
+	PaymentRequest PaymentRequest                `json:"payment_request"`
+	Transfer       CreatePendingTransferTxResult `json:"transfer"`
+}
+
+// PayPaymentRequestPendingTx 超过审批阈值的付款和 CreatePendingTransferTx 一样先占用资金，
+// 请求在审批期间是 pending_approval，批准后变为已付，拒绝或过期后回到 pending
+func (store *SQLStore) PayPaymentRequestPendingTx(ctx context.Context, arg PayPaymentRequestPendingTxParams) (PayPaymentRequestPendingTxResult, error) {
+	var result PayPaymentRequestPendingTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		request, err := lockPendingPaymentRequest(ctx, q, arg.PaymentRequestID)
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, err = createPendingTransfer(ctx, q, CreatePendingTransferTxParams{
+			TransferTxParams: paymentRequestTransfer(request, arg.FromAccountID),
+			RequestedBy:      arg.RequestedBy,
+			ExpiresAt:        arg.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.PaymentRequest, err = q.MarkPaymentRequestPendingApproval(ctx, MarkPaymentRequestPendingApprovalParams{
+			ID:         request.ID,
+			TransferID: sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterPay != nil {
+			return arg.AfterPay(q, result)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// lockPendingPaymentRequest 锁住请求，只有还没过期的 pending 请求能付款
+func lockPendingPaymentRequest(ctx context.Context, q *Queries, requestID int64) (PaymentRequest, error) {
+	request, err := q.GetPaymentRequestForUpdate(ctx, requestID)
+	if err != nil {
+		return request, err
+	}
+
+	if request.Status != util.PaymentRequestPending {
+		return request, fmt.Errorf("payment request [%d] is %s: %w", request.ID, request.Status, ErrPaymentRequestClosed)
+	}
+	if !time.Now().Before(request.ExpiresAt) {
+		return request, fmt.Errorf("payment request [%d]: %w", request.ID, ErrPaymentRequestExpired)
+	}
+	return request, nil
+}
+
+func paymentRequestTransfer(request PaymentRequest, fromAccountID int64) TransferTxParams {
+	return TransferTxParams{
+		FromAccountID: fromAccountID,
+		ToAccountID:   request.ToAccountID,
+		Amount:        request.Amount,
+		Memo:          request.Memo,
+	}
+}
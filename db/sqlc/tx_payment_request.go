@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+)
+
+type CreatePaymentRequestTxParams struct {
+	CreatePaymentRequestParams
+	// 在同一个事务里执行，用来写通知付款人的 outbox 任务
+	AfterCreate func(q Querier, request PaymentRequest) error
+}
+
+type CreatePaymentRequestTxResult struct {
+	PaymentRequest PaymentRequest
+}
+
+// CreatePaymentRequestTx 请求和给付款人的通知一起提交
+func (store *SQLStore) CreatePaymentRequestTx(ctx context.Context, arg CreatePaymentRequestTxParams) (CreatePaymentRequestTxResult, error) {
+	var result CreatePaymentRequestTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+		result.PaymentRequest, err = q.CreatePaymentRequest(ctx, arg.CreatePaymentRequestParams)
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterCreate != nil {
+			return arg.AfterCreate(q, result.PaymentRequest)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+type DeclinePaymentRequestTxParams struct {
+	PaymentRequestID int64
+	// 在同一个事务里执行，用来写通知请求人的 outbox 任务
+	AfterDecline func(q Querier, request PaymentRequest) error
+}
+
+type DeclinePaymentRequestTxResult struct {
+	PaymentRequest PaymentRequest
+}
+
+// DeclinePaymentRequestTx 只有 pending 的请求能拒绝，否则返回 sql.ErrNoRows
+func (store *SQLStore) DeclinePaymentRequestTx(ctx context.Context, arg DeclinePaymentRequestTxParams) (DeclinePaymentRequestTxResult, error) {
+	var result DeclinePaymentRequestTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+		result.PaymentRequest, err = q.DeclinePaymentRequest(ctx, arg.PaymentRequestID)
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterDecline != nil {
+			return arg.AfterDecline(q, result.PaymentRequest)
+		}
+		return nil
+	})
+
+	return result, err
+}
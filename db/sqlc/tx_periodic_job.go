@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type SchedulePeriodicJobTxParams struct {
+	Job         string
+	ScheduledAt time.Time
+	// 在同一个事务里执行，q 是事务内的查询，用来把任务写进 outbox
+	Enqueue func(q Querier) error
+}
+
+type SchedulePeriodicJobTxResult struct {
+	// 这一次已经被别的实例投递过时为 false
+	Scheduled bool
+}
+
+// SchedulePeriodicJobTx 记下这一次触发再投递任务，选主切换时同一次触发只投递一次
+func (store *SQLStore) SchedulePeriodicJobTx(ctx context.Context, arg SchedulePeriodicJobTxParams) (SchedulePeriodicJobTxResult, error) {
+	var result SchedulePeriodicJobTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		_, err := q.CreatePeriodicJobRun(ctx, CreatePeriodicJobRunParams{
+			Job:         arg.Job,
+			ScheduledAt: arg.ScheduledAt,
+		})
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		result.Scheduled = true
+		return arg.Enqueue(q)
+	})
+
+	return result, err
+}
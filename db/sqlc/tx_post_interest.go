@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"simplebank/util"
+	"time"
+)
+
+type PostInterestTxParams struct {
+	AccountID int64     `json:"account_id"`
+	Period    time.Time `json:"period"` // 当月第一天
+}
+
+type PostInterestTxResult struct {
+	Posting       InterestPosting `json:"posting"`
+	Transfer      Transfer        `json:"transfer"`
+	AlreadyPosted bool            `json:"already_posted"`
+}
+
+// PostInterestTx 把账户当月累计的利息从银行利息支出账户转入
+// interest_postings 上 (account_id, period) 唯一，重复执行不会重复入账
+// 不足一个最小单位的零头记在 residual_micros 里，结转到下一次入账
+func (store *SQLStore) PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error) {
+	var result PostInterestTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		periodEnd := arg.Period.AddDate(0, 1, 0)
+
+		// 先锁账户再读计提和改余额，和其他入账路径一样
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		var carried int64
+		latest, err := q.GetLatestInterestPosting(ctx, GetLatestInterestPostingParams{
+			AccountID:    arg.AccountID,
+			BeforePeriod: arg.Period,
+		})
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if err == nil {
+			carried = latest.ResidualMicros
+		}
+
+		accrued, err := q.SumUnpostedInterest(ctx, SumUnpostedInterestParams{
+			AccountID: arg.AccountID,
+			PeriodEnd: periodEnd,
+		})
+		if err != nil {
+			return err
+		}
+		accrued += carried
+
+		amount := accrued / util.MicrosPerMinorUnit
+		result.Posting, err = q.CreateInterestPosting(ctx, CreateInterestPostingParams{
+			AccountID:      arg.AccountID,
+			Period:         arg.Period,
+			AccruedMicros:  accrued,
+			Amount:         amount,
+			ResidualMicros: accrued - amount*util.MicrosPerMinorUnit,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				result.AlreadyPosted = true
+				return nil
+			}
+			return err
+		}
+
+		// 计提都算进了这次入账，不足一个最小单位的零头结转到下个月
+		err = q.MarkInterestAccrualsPosted(ctx, MarkInterestAccrualsPostedParams{
+			PostingID: sql.NullInt64{Int64: result.Posting.ID, Valid: true},
+			AccountID: account.ID,
+			PeriodEnd: periodEnd,
+		})
+		if err != nil {
+			return err
+		}
+
+		if amount == 0 {
+			return nil
+		}
+
+		expenseAccount, err := getOrCreateSystemAccount(ctx, q, account.Currency, util.InterestExpenseProduct)
+		if err != nil {
+			return err
+		}
+
+		// 利息支出账户在 postTransfer 改余额时才加锁，客户账户已经锁住
+		transfer, err := insertTransfer(ctx, q, TransferTxParams{
+			FromAccountID: expenseAccount.ID,
+			ToAccountID:   account.ID,
+			Amount:        amount,
+			Memo:          fmt.Sprintf("Interest for %s", arg.Period.Format("2006-01")),
+		}, 0, util.TransferStatusCompleted)
+		if err != nil {
+			return err
+		}
+
+		posted, err := postTransfer(ctx, q, transfer, account.Currency)
+		if err != nil {
+			return err
+		}
+		result.Transfer = posted.Transfer
+
+		result.Posting, err = q.UpdateInterestPostingTransfer(ctx, UpdateInterestPostingTransferParams{
+			ID:         result.Posting.ID,
+			TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -1,13 +1,36 @@
 package db
 
-import "context"
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"simplebank/util"
+
+	"github.com/google/uuid"
+)
 
 type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
 	Amount        int64 `json:"amount"`
+	// 以下都是可选的，长度限制由 API 层和表上的约束保证
+	Memo              string          `json:"memo"`
+	PrivateNote       string          `json:"private_note"`
+	ExternalReference string          `json:"external_reference"`
+	Metadata          json.RawMessage `json:"metadata"`
+	// 按报价转账时沿用报价里的手续费，同一个报价只能用一次
+	QuotedFee sql.NullInt64 `json:"quoted_fee"`
+	QuoteID   uuid.NullUUID `json:"quote_id"`
+	// 按收款人用户名或邮箱转账时，转出方看不到对方的账户 ID
+	HideToAccount bool `json:"hide_to_account"`
+	// 只在 TransferTX 的事务里执行，用来写通知的 outbox 任务，其他事务有各自的钩子
+	AfterTransfer func(q Querier, result TransferTxResult) error `json:"-"`
 }
 
+// metadata 列不能为空，没有附加信息时存空对象
+var emptyMetadata = json.RawMessage(`{}`)
+
 // 转账事务输出结果结构体
 type TransferTxResult struct {
 	Transfer    Transfer `json:"transfer"`
@@ -15,6 +38,8 @@ type TransferTxResult struct {
 	ToAccount   Account  `json:"to_account"`
 	FromEntry   Entry    `json:"from_entry"`
 	ToEntry     Entry    `json:"to_entry"`
+	// 手续费单独记一条分录，不收费时为空
+	FeeEntry Entry `json:"fee_entry"`
 }
 
 func (store *SQLStore) TransferTX(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
@@ -22,50 +47,164 @@ func (store *SQLStore) TransferTX(ctx context.Context, arg TransferTxParams) (Tr
 
 	err := store.execTX(ctx, func(q *Queries) error {
 		var err error
-
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.Amount,
-		})
+		result, err = transfer(ctx, q, arg)
 		if err != nil {
 			return err
 		}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
+		if arg.AfterTransfer != nil {
+			return arg.AfterTransfer(q, result)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// transfer 在调用方的事务里完成转账，其他事务（如付款请求）需要和转账一起提交时复用
+func transfer(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	return transferWithStatus(ctx, q, arg, util.TransferStatusCompleted)
+}
+
+// transferWithStatus 立即入账，但转账记录可以是其他状态，比如转给他行时的 initiated
+func transferWithStatus(ctx context.Context, q *Queries, arg TransferTxParams, status string) (result TransferTxResult, err error) {
+	fromAccount, toAccount, err := lockAccounts(ctx, q, arg.FromAccountID, arg.ToAccountID)
+	if err != nil {
+		return result, err
+	}
+
+	// 冻结状态、余额等必须在锁内检查，否则检查完到扣款之间可能发生变化
+	fee, err := checkTransfer(ctx, q, fromAccount, toAccount, arg.Amount, arg.QuotedFee)
+	if err != nil {
+		return result, err
+	}
+
+	created, err := insertTransfer(ctx, q, arg, fee, status)
+	if err != nil {
+		return result, err
+	}
+
+	return postTransfer(ctx, q, created, fromAccount.Currency)
+}
+
+func insertTransfer(ctx context.Context, q *Queries, arg TransferTxParams, fee int64, status string) (Transfer, error) {
+	metadata := arg.Metadata
+	if len(metadata) == 0 {
+		metadata = emptyMetadata
+	}
+
+	return q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID:     arg.FromAccountID,
+		ToAccountID:       arg.ToAccountID,
+		Amount:            arg.Amount,
+		Memo:              arg.Memo,
+		PrivateNote:       arg.PrivateNote,
+		ExternalReference: arg.ExternalReference,
+		Metadata:          metadata,
+		Fee:               fee,
+		QuoteID:           arg.QuoteID,
+		Status:            status,
+		HideToAccount:     arg.HideToAccount,
+	})
+}
+
+// postTransfer 给已经建好的转账记分录、改余额、收手续费，调用前必须已锁住两个账户
+func postTransfer(ctx context.Context, q *Queries, transfer Transfer, currency string) (result TransferTxResult, err error) {
+	result.Transfer = transfer
+	total := transfer.Amount + transfer.Fee
+
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  transfer.FromAccountID,
+		Amount:     -transfer.Amount,
+		TransferID: sql.NullInt64{Int64: transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  transfer.ToAccountID,
+		Amount:     transfer.Amount,
+		TransferID: sql.NullInt64{Int64: transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if transfer.Fee > 0 {
+		result.FeeEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:  transfer.FromAccountID,
+			Amount:     -transfer.Fee,
+			TransferID: sql.NullInt64{Int64: transfer.ID, Valid: true},
 		})
 		if err != nil {
-			return err
+			return result, err
 		}
+	}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
+	if transfer.FromAccountID < transfer.ToAccountID {
+		result.FromAccount, result.ToAccount, err = addMoney(
+			ctx, q,
+			transfer.FromAccountID, -total,
+			transfer.ToAccountID, transfer.Amount,
+		)
+	} else {
+		result.ToAccount, result.FromAccount, err = addMoney(
+			ctx, q,
+			transfer.ToAccountID, transfer.Amount,
+			transfer.FromAccountID, -total,
+		)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	// 手续费账户最后才锁，和两个客户账户之间不会形成循环等待
+	if transfer.Fee > 0 {
+		err = postFee(ctx, q, currency, transfer.Fee)
+	}
+
+	return result, err
+}
+
+// checkTransfer 转账前的全部业务校验，返回要收的手续费
+// 手续费由转出方在转账金额之外另付，余额和产品规则都按合计校验
+func checkTransfer(
+	ctx context.Context,
+	q *Queries,
+	fromAccount Account,
+	toAccount Account,
+	amount int64,
+	quotedFee sql.NullInt64,
+) (fee int64, err error) {
+	err = checkFreezeStatus(fromAccount, toAccount)
+	if err != nil {
+		return
+	}
+
+	if TransferType(fromAccount, toAccount) == util.TransferTypeExternal {
+		err = checkExternalTransfersAllowed(ctx, q, fromAccount)
 		if err != nil {
-			return err
+			return
 		}
+	}
 
-		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = addMoney(
-				ctx, q,
-				arg.FromAccountID, -arg.Amount,
-				arg.ToAccountID, arg.Amount,
-			)
-		} else {
-			result.ToAccount, result.FromAccount, err = addMoney(
-				ctx, q,
-				arg.ToAccountID, arg.Amount,
-				arg.FromAccountID, -arg.Amount,
-			)
+	if quotedFee.Valid {
+		fee = quotedFee.Int64
+	} else {
+		fee, err = transferFee(ctx, q, fromAccount, toAccount, amount)
+		if err != nil {
+			return
 		}
+	}
 
-		return err
-	})
+	err = checkAvailableBalance(fromAccount, amount+fee)
+	if err != nil {
+		return
+	}
 
-	return result, err
+	err = checkProductRules(ctx, q, fromAccount, amount+fee)
+	return
 }
 
 func addMoney(
@@ -90,3 +229,89 @@ func addMoney(
 	})
 	return
 }
+
+// lockAccounts 按 ID 从小到大加行锁，和 addMoney 的顺序一致，避免死锁
+func lockAccounts(
+	ctx context.Context,
+	q *Queries,
+	fromAccountID int64,
+	toAccountID int64,
+) (fromAccount Account, toAccount Account, err error) {
+	if fromAccountID < toAccountID {
+		fromAccount, err = q.GetAccountForUpdate(ctx, fromAccountID)
+		if err != nil {
+			return
+		}
+		toAccount, err = q.GetAccountForUpdate(ctx, toAccountID)
+		return
+	}
+
+	toAccount, err = q.GetAccountForUpdate(ctx, toAccountID)
+	if err != nil {
+		return
+	}
+	fromAccount, err = q.GetAccountForUpdate(ctx, fromAccountID)
+	return
+}
+
+// 转出方任何冻结都不能出账，转入方只有双向冻结时才拒绝入账
+func checkFreezeStatus(fromAccount Account, toAccount Account) error {
+	if fromAccount.FreezeStatus != util.FreezeStatusActive {
+		return fmt.Errorf("account [%d] is %s: %w", fromAccount.ID, fromAccount.FreezeStatus, ErrAccountFrozen)
+	}
+	if toAccount.FreezeStatus == util.FreezeStatusFrozen {
+		return fmt.Errorf("account [%d] is %s: %w", toAccount.ID, toAccount.FreezeStatus, ErrAccountFrozen)
+	}
+	return nil
+}
+
+// 可用余额 = 余额 + 透支额度 - 待审批转账占用的金额，银行系统账户不受限制
+func checkAvailableBalance(fromAccount Account, amount int64) error {
+	if fromAccount.Owner == util.BankUsername {
+		return nil
+	}
+
+	available := fromAccount.Balance + fromAccount.OverdraftLimit - fromAccount.HeldAmount
+	if available < amount {
+		return fmt.Errorf("account [%d] has %d available (overdraft limit %d, held %d), cannot send %d: %w",
+			fromAccount.ID, available, fromAccount.OverdraftLimit, fromAccount.HeldAmount, amount, ErrInsufficientFunds)
+	}
+	return nil
+}
+
+func checkExternalTransfersAllowed(ctx context.Context, q *Queries, fromAccount Account) error {
+	product, err := q.GetAccountProduct(ctx, fromAccount.ProductType)
+	if err != nil {
+		return err
+	}
+	if !product.AllowExternalTransfers {
+		return fmt.Errorf("account [%d] is a %s account: %w", fromAccount.ID, product.Code, ErrExternalTransferNotAllowed)
+	}
+	return nil
+}
+
+// checkProductRules 按转出账户的产品规则校验，调用前必须已锁住转出账户
+func checkProductRules(ctx context.Context, q *Queries, fromAccount Account, amount int64) error {
+	product, err := q.GetAccountProduct(ctx, fromAccount.ProductType)
+	if err != nil {
+		return err
+	}
+
+	if product.MaxMonthlyOutgoingTransfers.Valid {
+		count, err := q.CountMonthlyTransfersFromAccount(ctx, fromAccount.ID)
+		if err != nil {
+			return err
+		}
+		if count >= int64(product.MaxMonthlyOutgoingTransfers.Int32) {
+			return fmt.Errorf("account [%d] allows %d outgoing transfers per month: %w",
+				fromAccount.ID, product.MaxMonthlyOutgoingTransfers.Int32, ErrTransferLimitExceeded)
+		}
+	}
+
+	// 最低余额为 0 时交给 positive_balance 约束处理
+	if product.MinBalance > 0 && fromAccount.Balance-fromAccount.HeldAmount-amount < product.MinBalance {
+		return fmt.Errorf("account [%d] must keep at least %d: %w", fromAccount.ID, product.MinBalance, ErrMinimumBalance)
+	}
+
+	return nil
+}
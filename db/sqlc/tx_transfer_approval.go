@@ -0,0 +1,274 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"simplebank/util"
+	"time"
+)
+
+type CreatePendingTransferTxParams struct {
+	TransferTxParams
+	RequestedBy string    `json:"requested_by"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	// 只在 CreatePendingTransferTx 的事务里执行，用来写 webhook 事件的 outbox 任务
+	AfterCreate func(q Querier, result CreatePendingTransferTxResult) error `json:"-"`
+}
+
+type CreatePendingTransferTxResult struct {
+	Transfer    Transfer         `json:"transfer"`
+	Approval    TransferApproval `json:"approval"`
+	FromAccount Account          `json:"from_account"`
+}
+
+// CreatePendingTransferTx 校验规则和 TransferTX 相同，但只建一笔待审批的转账，
+// 金额加手续费记到转出账户的 held_amount 上，批准前别的转账不能动用
+func (store *SQLStore) CreatePendingTransferTx(ctx context.Context, arg CreatePendingTransferTxParams) (CreatePendingTransferTxResult, error) {
+	var result CreatePendingTransferTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+		result, err = createPendingTransfer(ctx, q, arg)
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterCreate != nil {
+			return arg.AfterCreate(q, result)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// createPendingTransfer 在调用方的事务里建待审批的转账，付款请求超过阈值时复用
+func createPendingTransfer(ctx context.Context, q *Queries, arg CreatePendingTransferTxParams) (result CreatePendingTransferTxResult, err error) {
+	fromAccount, toAccount, err := lockAccounts(ctx, q, arg.FromAccountID, arg.ToAccountID)
+	if err != nil {
+		return
+	}
+
+	fee, err := checkTransfer(ctx, q, fromAccount, toAccount, arg.Amount, arg.QuotedFee)
+	if err != nil {
+		return
+	}
+
+	result.Transfer, err = insertTransfer(ctx, q, arg.TransferTxParams, fee, util.TransferStatusPendingApproval)
+	if err != nil {
+		return
+	}
+
+	result.Approval, err = q.CreateTransferApproval(ctx, CreateTransferApprovalParams{
+		TransferID:  result.Transfer.ID,
+		RequestedBy: arg.RequestedBy,
+		ExpiresAt:   arg.ExpiresAt,
+	})
+	if err != nil {
+		return
+	}
+
+	result.FromAccount, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+		ID:     fromAccount.ID,
+		Amount: arg.Amount + fee,
+	})
+	return
+}
+
+type ApproveTransferTxParams struct {
+	TransferID int64  `json:"transfer_id"`
+	ApprovedBy string `json:"approved_by"`
+	// 在同一个事务里执行，他行转账用来写提交清算网络的 outbox 任务
+	AfterApprove func(q Querier, result ApproveTransferTxResult) error `json:"-"`
+}
+
+type ApproveTransferTxResult struct {
+	TransferTxResult
+	Approval TransferApproval `json:"approval"`
+	// 转账是为了付款请求时，请求随之变为已付，否则 ID 为 0
+	PaymentRequest PaymentRequest `json:"payment_request"`
+	// 他行转账批准后变为 initiated 等待提交，否则 TransferID 为 0
+	ExternalPayment ExternalPayment `json:"external_payment"`
+}
+
+// ApproveTransferTx 释放占用的金额后按申请时的金额和手续费入账
+// 账户在等待期间被冻结时拒绝入账，转账保持待审批
+func (store *SQLStore) ApproveTransferTx(ctx context.Context, arg ApproveTransferTxParams) (ApproveTransferTxResult, error) {
+	var result ApproveTransferTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		pending, approval, err := lockPendingTransfer(ctx, q, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		if !time.Now().Before(approval.ExpiresAt) {
+			return fmt.Errorf("transfer [%d]: %w", pending.ID, ErrApprovalExpired)
+		}
+
+		fromAccount, toAccount, err := lockAccounts(ctx, q, pending.FromAccountID, pending.ToAccountID)
+		if err != nil {
+			return err
+		}
+
+		total := pending.Amount + pending.Fee
+		fromAccount, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+			ID:     fromAccount.ID,
+			Amount: -total,
+		})
+		if err != nil {
+			return err
+		}
+
+		// 资金一直被占着，余额正常情况下一定够，这里防的是透支额度被调低之类的变化
+		err = checkFreezeStatus(fromAccount, toAccount)
+		if err != nil {
+			return err
+		}
+		err = checkAvailableBalance(fromAccount, total)
+		if err != nil {
+			return err
+		}
+
+		status := util.TransferStatusCompleted
+		result.ExternalPayment, err = q.GetExternalPayment(ctx, pending.ID)
+		if err == nil {
+			status = util.TransferStatusInitiated
+		} else if err != sql.ErrNoRows {
+			return err
+		}
+
+		approved, err := q.UpdatePendingTransferStatus(ctx, UpdatePendingTransferStatusParams{
+			ID:     pending.ID,
+			Status: status,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.TransferTxResult, err = postTransfer(ctx, q, approved, fromAccount.Currency)
+		if err != nil {
+			return err
+		}
+
+		result.Approval, err = q.DecideTransferApproval(ctx, DecideTransferApprovalParams{
+			TransferID: pending.ID,
+			DecidedBy:  sql.NullString{String: arg.ApprovedBy, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		result.PaymentRequest, err = q.CompletePaymentRequestTransfer(ctx, sql.NullInt64{Int64: pending.ID, Valid: true})
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		if arg.AfterApprove != nil {
+			return arg.AfterApprove(q, result)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+type RejectTransferTxParams struct {
+	TransferID int64  `json:"transfer_id"`
+	RejectedBy string `json:"rejected_by"`
+}
+
+type CloseTransferTxResult struct {
+	Transfer    Transfer         `json:"transfer"`
+	Approval    TransferApproval `json:"approval"`
+	FromAccount Account          `json:"from_account"`
+	// 转账是为了付款请求时，请求回到 pending，否则 ID 为 0
+	PaymentRequest PaymentRequest `json:"payment_request"`
+}
+
+// RejectTransferTx 拒绝待审批的转账并释放占用的金额
+func (store *SQLStore) RejectTransferTx(ctx context.Context, arg RejectTransferTxParams) (CloseTransferTxResult, error) {
+	var result CloseTransferTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+		result, err = closePendingTransfer(ctx, q, arg.TransferID, util.TransferStatusRejected, sql.NullString{String: arg.RejectedBy, Valid: true})
+		return err
+	})
+
+	return result, err
+}
+
+// ExpireTransferTx 让到期的待审批转账过期并释放占用的金额，还没到期的不处理
+func (store *SQLStore) ExpireTransferTx(ctx context.Context, transferID int64) (CloseTransferTxResult, error) {
+	var result CloseTransferTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+		result, err = closePendingTransfer(ctx, q, transferID, util.TransferStatusExpired, sql.NullString{})
+		return err
+	})
+
+	return result, err
+}
+
+func closePendingTransfer(
+	ctx context.Context,
+	q *Queries,
+	transferID int64,
+	status string,
+	decidedBy sql.NullString,
+) (result CloseTransferTxResult, err error) {
+	pending, approval, err := lockPendingTransfer(ctx, q, transferID)
+	if err != nil {
+		return
+	}
+	if status == util.TransferStatusExpired && time.Now().Before(approval.ExpiresAt) {
+		err = fmt.Errorf("transfer [%d] approval expires at %s", transferID, approval.ExpiresAt)
+		return
+	}
+
+	result.Transfer, err = q.UpdatePendingTransferStatus(ctx, UpdatePendingTransferStatusParams{
+		ID:     pending.ID,
+		Status: status,
+	})
+	if err != nil {
+		return
+	}
+
+	result.Approval, err = q.DecideTransferApproval(ctx, DecideTransferApprovalParams{
+		TransferID: pending.ID,
+		DecidedBy:  decidedBy,
+	})
+	if err != nil {
+		return
+	}
+
+	result.FromAccount, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+		ID:     pending.FromAccountID,
+		Amount: -(pending.Amount + pending.Fee),
+	})
+	if err != nil {
+		return
+	}
+
+	result.PaymentRequest, err = q.ReopenPaymentRequest(ctx, sql.NullInt64{Int64: pending.ID, Valid: true})
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+	return
+}
+
+// lockPendingTransfer 先锁转账再锁账户，和创建、批准的加锁顺序一致
+func lockPendingTransfer(ctx context.Context, q *Queries, transferID int64) (Transfer, TransferApproval, error) {
+	pending, err := q.GetTransferForUpdate(ctx, transferID)
+	if err != nil {
+		return pending, TransferApproval{}, err
+	}
+	if pending.Status != util.TransferStatusPendingApproval {
+		return pending, TransferApproval{}, fmt.Errorf("transfer [%d] is %s: %w", pending.ID, pending.Status, ErrTransferNotPending)
+	}
+
+	approval, err := q.GetTransferApproval(ctx, transferID)
+	return pending, approval, err
+}
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+)
+
+type UpdateUserTxParams struct {
+	UpdateUserParams
+	// 在同一个事务里执行，q 是事务内的查询，可以用来写 outbox
+	AfterUpdate func(q Querier, user User) error
+}
+
+type UpdateUserTxResult struct {
+	User User
+}
+
+func (store *SQLStore) UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error) {
+	var result UpdateUserTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+		result.User, err = q.UpdateUser(ctx, arg.UpdateUserParams)
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterUpdate != nil {
+			return arg.AfterUpdate(q, result.User)
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -8,6 +8,8 @@ import (
 type VerifyEmailTxParams struct {
 	EmailId    int64
 	SecretCode string
+	// 在同一个事务里执行，用来写 webhook 事件的 outbox 任务
+	AfterVerify func(q Querier, user User) error
 }
 
 type VerifyEmailTxResult struct {
@@ -38,6 +40,10 @@ func (store *SQLStore) VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParam
 		if err != nil {
 			return err
 		}
+
+		if arg.AfterVerify != nil {
+			return arg.AfterVerify(q, result.User)
+		}
 		return nil
 	})
 	return result, err
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+)
+
+type RedeliverWebhookTxParams struct {
+	DeliveryID int64
+	// 在同一个事务里执行，用来写重投的 outbox 任务
+	AfterReset func(q Querier, delivery WebhookDelivery) error
+}
+
+type RedeliverWebhookTxResult struct {
+	Delivery WebhookDelivery
+}
+
+// RedeliverWebhookTx 重置投递记录和写重投任务一起提交
+func (store *SQLStore) RedeliverWebhookTx(ctx context.Context, arg RedeliverWebhookTxParams) (RedeliverWebhookTxResult, error) {
+	var result RedeliverWebhookTxResult
+
+	err := store.execTX(ctx, func(q *Queries) error {
+		var err error
+		result.Delivery, err = q.ResetWebhookDelivery(ctx, arg.DeliveryID)
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterReset != nil {
+			return arg.AfterReset(q, result.Delivery)
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 const createVerifyEmail = `-- name: CreateVerifyEmail :one
@@ -40,6 +41,51 @@ func (q *Queries) CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailPa
 	return i, err
 }
 
+const deleteExpiredVerifyEmails = `-- name: DeleteExpiredVerifyEmails :execrows
+DELETE FROM verify_emails
+WHERE expired_at < $1
+`
+
+func (q *Queries) DeleteExpiredVerifyEmails(ctx context.Context, expiredAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredVerifyEmails, expiredAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getActiveVerifyEmail = `-- name: GetActiveVerifyEmail :one
+SELECT id, username, email, secret_code, is_used, created_at, expired_at FROM verify_emails
+WHERE
+    username = $1
+    AND email = $2
+    AND is_used = FALSE
+    AND expired_at > now() + interval '5 minutes'
+ORDER BY id DESC
+LIMIT 1
+`
+
+type GetActiveVerifyEmailParams struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// 还没用过、离过期还有一段时间的验证，重发邮件时复用同一个链接
+func (q *Queries) GetActiveVerifyEmail(ctx context.Context, arg GetActiveVerifyEmailParams) (VerifyEmail, error) {
+	row := q.db.QueryRowContext(ctx, getActiveVerifyEmail, arg.Username, arg.Email)
+	var i VerifyEmail
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.SecretCode,
+		&i.IsUsed,
+		&i.CreatedAt,
+		&i.ExpiredAt,
+	)
+	return i, err
+}
+
 const updateVerifyEmail = `-- name: UpdateVerifyEmail :one
 UPDATE verify_emails
 SET
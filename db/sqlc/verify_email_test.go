@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"simplebank/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetActiveVerifyEmail(t *testing.T) {
+	user := createRandomUser(t)
+	arg := GetActiveVerifyEmailParams{Username: user.Username, Email: user.Email}
+
+	_, err := testQueries.GetActiveVerifyEmail(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	verifyEmail, err := testQueries.CreateVerifyEmail(context.Background(), CreateVerifyEmailParams{
+		Username:   user.Username,
+		Email:      user.Email,
+		SecretCode: util.RandomString(32),
+	})
+	require.NoError(t, err)
+
+	active, err := testQueries.GetActiveVerifyEmail(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, verifyEmail.ID, active.ID)
+
+	// 换了邮箱的验证不复用
+	_, err = testQueries.GetActiveVerifyEmail(context.Background(), GetActiveVerifyEmailParams{Username: user.Username, Email: util.RandomEmail()})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// 用过的不复用
+	_, err = testQueries.UpdateVerifyEmail(context.Background(), UpdateVerifyEmailParams{ID: verifyEmail.ID, SecretCode: verifyEmail.SecretCode})
+	require.NoError(t, err)
+	_, err = testQueries.GetActiveVerifyEmail(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
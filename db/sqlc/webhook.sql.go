@@ -0,0 +1,350 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: webhook.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const createWebhookDelivery = `-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+  subscription_id,
+  event_id,
+  event_type,
+  payload
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (subscription_id, event_id) DO UPDATE
+SET event_type = EXCLUDED.event_type
+RETURNING id, subscription_id, event_id, event_type, payload, status, attempts, response_status, last_error, created_at, delivered_at
+`
+
+type CreateWebhookDeliveryParams struct {
+	SubscriptionID int64           `json:"subscription_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+// 同一个事件对同一个订阅只投递一次，重复调用返回已有的记录
+func (q *Queries) CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, createWebhookDelivery,
+		arg.SubscriptionID,
+		arg.EventID,
+		arg.EventType,
+		arg.Payload,
+	)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.SubscriptionID,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.ResponseStatus,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
+
+const createWebhookSubscription = `-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+  owner,
+  url,
+  event_types,
+  secret
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, owner, url, event_types, secret, created_at
+`
+
+type CreateWebhookSubscriptionParams struct {
+	Owner      string   `json:"owner"`
+	Url        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"`
+}
+
+func (q *Queries) CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error) {
+	row := q.db.QueryRowContext(ctx, createWebhookSubscription,
+		arg.Owner,
+		arg.Url,
+		pq.Array(arg.EventTypes),
+		arg.Secret,
+	)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Url,
+		pq.Array(&i.EventTypes),
+		&i.Secret,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteWebhookSubscription = `-- name: DeleteWebhookSubscription :exec
+DELETE FROM webhook_subscriptions
+WHERE id = $1
+`
+
+func (q *Queries) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteWebhookSubscription, id)
+	return err
+}
+
+const getWebhookDelivery = `-- name: GetWebhookDelivery :one
+SELECT id, subscription_id, event_id, event_type, payload, status, attempts, response_status, last_error, created_at, delivered_at FROM webhook_deliveries
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookDelivery, id)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.SubscriptionID,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.ResponseStatus,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
+
+const getWebhookSubscription = `-- name: GetWebhookSubscription :one
+SELECT id, owner, url, event_types, secret, created_at FROM webhook_subscriptions
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookSubscription, id)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Url,
+		pq.Array(&i.EventTypes),
+		&i.Secret,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
+SELECT id, subscription_id, event_id, event_type, payload, status, attempts, response_status, last_error, created_at, delivered_at FROM webhook_deliveries
+WHERE subscription_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListWebhookDeliveriesParams struct {
+	SubscriptionID int64 `json:"subscription_id"`
+	Limit          int32 `json:"limit"`
+	Offset         int32 `json:"offset"`
+}
+
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookDeliveries, arg.SubscriptionID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WebhookDelivery{}
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.ResponseStatus,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookSubscriptions = `-- name: ListWebhookSubscriptions :many
+SELECT id, owner, url, event_types, secret, created_at FROM webhook_subscriptions
+WHERE owner = $1
+ORDER BY id
+`
+
+func (q *Queries) ListWebhookSubscriptions(ctx context.Context, owner string) ([]WebhookSubscription, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookSubscriptions, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WebhookSubscription{}
+	for rows.Next() {
+		var i WebhookSubscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Url,
+			pq.Array(&i.EventTypes),
+			&i.Secret,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookSubscriptionsForEvent = `-- name: ListWebhookSubscriptionsForEvent :many
+SELECT id, owner, url, event_types, secret, created_at FROM webhook_subscriptions
+WHERE owner = $1 AND $2::varchar = ANY(event_types)
+ORDER BY id
+`
+
+type ListWebhookSubscriptionsForEventParams struct {
+	Owner     string `json:"owner"`
+	EventType string `json:"event_type"`
+}
+
+func (q *Queries) ListWebhookSubscriptionsForEvent(ctx context.Context, arg ListWebhookSubscriptionsForEventParams) ([]WebhookSubscription, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookSubscriptionsForEvent, arg.Owner, arg.EventType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WebhookSubscription{}
+	for rows.Next() {
+		var i WebhookSubscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Url,
+			pq.Array(&i.EventTypes),
+			&i.Secret,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordWebhookDeliveryAttempt = `-- name: RecordWebhookDeliveryAttempt :one
+UPDATE webhook_deliveries
+SET
+  status = $1,
+  attempts = attempts + 1,
+  response_status = $2,
+  last_error = $3,
+  delivered_at = $4
+WHERE id = $5
+RETURNING id, subscription_id, event_id, event_type, payload, status, attempts, response_status, last_error, created_at, delivered_at
+`
+
+type RecordWebhookDeliveryAttemptParams struct {
+	Status         string       `json:"status"`
+	ResponseStatus int32        `json:"response_status"`
+	LastError      string       `json:"last_error"`
+	DeliveredAt    sql.NullTime `json:"delivered_at"`
+	ID             int64        `json:"id"`
+}
+
+func (q *Queries) RecordWebhookDeliveryAttempt(ctx context.Context, arg RecordWebhookDeliveryAttemptParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, recordWebhookDeliveryAttempt,
+		arg.Status,
+		arg.ResponseStatus,
+		arg.LastError,
+		arg.DeliveredAt,
+		arg.ID,
+	)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.SubscriptionID,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.ResponseStatus,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
+
+const resetWebhookDelivery = `-- name: ResetWebhookDelivery :one
+UPDATE webhook_deliveries
+SET status = 'pending'
+WHERE id = $1
+RETURNING id, subscription_id, event_id, event_type, payload, status, attempts, response_status, last_error, created_at, delivered_at
+`
+
+// 手动重投，已经成功的也可以再投一次
+func (q *Queries) ResetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, resetWebhookDelivery, id)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.SubscriptionID,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.ResponseStatus,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWebhookSubscription(t *testing.T, owner string, eventTypes []string) WebhookSubscription {
+	arg := CreateWebhookSubscriptionParams{
+		Owner:      owner,
+		Url:        "https://example.com/webhooks",
+		EventTypes: eventTypes,
+		Secret:     "whsec_test",
+	}
+	subscription, err := testQueries.CreateWebhookSubscription(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Owner, subscription.Owner)
+	require.Equal(t, arg.Url, subscription.Url)
+	require.Equal(t, arg.EventTypes, subscription.EventTypes)
+	return subscription
+}
+
+func TestListWebhookSubscriptionsForEvent(t *testing.T) {
+	user := createRandomUser(t)
+	transfers := createRandomWebhookSubscription(t, user.Username, []string{"transfer.created", "account.credited"})
+	createRandomWebhookSubscription(t, user.Username, []string{"user.email_verified"})
+
+	subscriptions, err := testQueries.ListWebhookSubscriptionsForEvent(context.Background(), ListWebhookSubscriptionsForEventParams{
+		Owner:     user.Username,
+		EventType: "account.credited",
+	})
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	require.Equal(t, transfers.ID, subscriptions[0].ID)
+
+	// 别人的订阅不算
+	subscriptions, err = testQueries.ListWebhookSubscriptionsForEvent(context.Background(), ListWebhookSubscriptionsForEventParams{
+		Owner:     createRandomUser(t).Username,
+		EventType: "account.credited",
+	})
+	require.NoError(t, err)
+	require.Empty(t, subscriptions)
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	user := createRandomUser(t)
+	subscription := createRandomWebhookSubscription(t, user.Username, []string{"transfer.created"})
+
+	arg := CreateWebhookDeliveryParams{
+		SubscriptionID: subscription.ID,
+		EventID:        uuid.New(),
+		EventType:      "transfer.created",
+		Payload:        json.RawMessage(`{"id":"1"}`),
+	}
+	delivery, err := testQueries.CreateWebhookDelivery(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, "pending", delivery.Status)
+	require.Zero(t, delivery.Attempts)
+
+	// 同一个事件重复发布时返回已有的记录
+	again, err := testQueries.CreateWebhookDelivery(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, delivery.ID, again.ID)
+
+	delivery, err = testQueries.RecordWebhookDeliveryAttempt(context.Background(), RecordWebhookDeliveryAttemptParams{
+		ID:             delivery.ID,
+		Status:         "failed",
+		ResponseStatus: 503,
+		LastError:      "unexpected status 503",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "failed", delivery.Status)
+	require.Equal(t, int32(1), delivery.Attempts)
+	require.False(t, delivery.DeliveredAt.Valid)
+
+	delivery, err = testQueries.ResetWebhookDelivery(context.Background(), delivery.ID)
+	require.NoError(t, err)
+	require.Equal(t, "pending", delivery.Status)
+	require.Equal(t, int32(1), delivery.Attempts)
+
+	deliveries, err := testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		SubscriptionID: subscription.ID,
+		Limit:          5,
+		Offset:         0,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+
+	// 删除订阅时投递记录一起删除
+	err = testQueries.DeleteWebhookSubscription(context.Background(), subscription.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetWebhookDelivery(context.Background(), delivery.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestRedeliverWebhookTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+	subscription := createRandomWebhookSubscription(t, user.Username, []string{"transfer.created"})
+
+	delivery, err := testQueries.CreateWebhookDelivery(context.Background(), CreateWebhookDeliveryParams{
+		SubscriptionID: subscription.ID,
+		EventID:        uuid.New(),
+		EventType:      "transfer.created",
+		Payload:        json.RawMessage(`{"id":"1"}`),
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.RecordWebhookDeliveryAttempt(context.Background(), RecordWebhookDeliveryAttemptParams{
+		ID:             delivery.ID,
+		Status:         "failed",
+		ResponseStatus: 503,
+		LastError:      "receiver responded with 503",
+	})
+	require.NoError(t, err)
+
+	writeOutbox := func(q Querier) (Outbox, error) {
+		return q.CreateOutboxMessage(context.Background(), CreateOutboxMessageParams{
+			TaskType: "task:test",
+			Payload:  json.RawMessage(`{}`),
+			Queue:    "default",
+			MaxRetry: 3,
+		})
+	}
+
+	// 任务写不进去时记录保持失败状态
+	var outboxID int64
+	_, err = store.RedeliverWebhookTx(context.Background(), RedeliverWebhookTxParams{
+		DeliveryID: delivery.ID,
+		AfterReset: func(q Querier, delivery WebhookDelivery) error {
+			message, err := writeOutbox(q)
+			require.NoError(t, err)
+			outboxID = message.ID
+			return fmt.Errorf("abort")
+		},
+	})
+	require.Error(t, err)
+
+	_, err = testQueries.GetOutboxMessage(context.Background(), outboxID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	delivery, err = testQueries.GetWebhookDelivery(context.Background(), delivery.ID)
+	require.NoError(t, err)
+	require.Equal(t, "failed", delivery.Status)
+
+	result, err := store.RedeliverWebhookTx(context.Background(), RedeliverWebhookTxParams{
+		DeliveryID: delivery.ID,
+		AfterReset: func(q Querier, delivery WebhookDelivery) error {
+			message, err := writeOutbox(q)
+			outboxID = message.ID
+			return err
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "pending", result.Delivery.Status)
+
+	_, err = testQueries.GetOutboxMessage(context.Background(), outboxID)
+	require.NoError(t, err)
+}
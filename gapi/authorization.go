@@ -2,11 +2,15 @@ package gapi
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	db "simplebank/db/sqlc"
 	"simplebank/token"
 	"strings"
 
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -14,7 +18,7 @@ const (
 	authorizationBearer = "bearer"
 )
 
-func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error) {
+func (server *Server) authorizeUser(ctx context.Context, accessibleRoles []string) (*token.Payload, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, fmt.Errorf("missing metadata")
@@ -42,5 +46,38 @@ func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error)
 		return nil, fmt.Errorf("invalid access token %w", err)
 	}
 
+	if !hasPermission(payload.Role, accessibleRoles) {
+		return nil, fmt.Errorf("permission denied")
+	}
+
 	return payload, nil
 }
+
+func hasPermission(userRole string, accessibleRoles []string) bool {
+	for _, role := range accessibleRoles {
+		if userRole == role {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeMember 通过成员表校验用户对账户的权限，返回的 error 已经是 gRPC 状态
+func (server *Server) authorizeMember(ctx context.Context, accountID int64, username string, allowed func(role string) bool) (db.AccountMember, error) {
+	member, err := server.store.GetAccountMember(ctx, db.GetAccountMemberParams{
+		AccountID: accountID,
+		Username:  username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return member, status.Errorf(codes.PermissionDenied, "account [%d] doesn't belong to the authenticated user", accountID)
+		}
+		return member, status.Errorf(codes.Internal, "failed to get account member: %s", err)
+	}
+
+	if allowed != nil && !allowed(member.Role) {
+		return member, status.Errorf(codes.PermissionDenied, "account member with role %s is not allowed", member.Role)
+	}
+
+	return member, nil
+}
@@ -1,9 +1,12 @@
 package gapi
 
 import (
+	"encoding/json"
 	db "simplebank/db/sqlc"
 	"simplebank/pb"
+	"time"
 
+	"github.com/hibiken/asynq"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -17,3 +20,188 @@ func convertUser(user db.User) *pb.User {
 		CreatedAt:         timestamppb.New(user.CreatedAt),
 	}
 }
+
+func convertAccount(account db.Account) *pb.Account {
+	return &pb.Account{
+		Id:             account.ID,
+		Owner:          account.Owner,
+		Balance:        account.Balance,
+		Currency:       account.Currency,
+		FreezeStatus:   account.FreezeStatus,
+		CreatedAt:      timestamppb.New(account.CreatedAt),
+		ProductType:    account.ProductType,
+		OverdraftLimit: account.OverdraftLimit,
+		AccountNumber:  account.AccountNumber,
+		HeldAmount:     account.HeldAmount,
+	}
+}
+
+func convertTransfer(transfer db.Transfer) *pb.Transfer {
+	// metadata 入库前已经校验过是字符串 map，解析失败时就不返回
+	var metadata map[string]string
+	_ = json.Unmarshal(transfer.Metadata, &metadata)
+
+	return &pb.Transfer{
+		Id:                transfer.ID,
+		FromAccountId:     transfer.FromAccountID,
+		ToAccountId:       transfer.ToAccountID,
+		Amount:            transfer.Amount,
+		CreatedAt:         timestamppb.New(transfer.CreatedAt),
+		Memo:              transfer.Memo,
+		PrivateNote:       transfer.PrivateNote,
+		ExternalReference: transfer.ExternalReference,
+		Metadata:          metadata,
+		Fee:               transfer.Fee,
+		Status:            transfer.Status,
+	}
+}
+
+// convertSentTransfer 转出方看到的转账，按收款人转账时不暴露对方的账户 ID
+func convertSentTransfer(transfer db.Transfer) *pb.Transfer {
+	rsp := convertTransfer(transfer)
+	if transfer.HideToAccount {
+		rsp.ToAccountId = 0
+	}
+	return rsp
+}
+
+func convertAccountMember(member db.AccountMember) *pb.AccountMember {
+	return &pb.AccountMember{
+		AccountId: member.AccountID,
+		Username:  member.Username,
+		Role:      member.Role,
+		CreatedAt: timestamppb.New(member.CreatedAt),
+	}
+}
+
+func convertPayee(payee db.Payee, coolingOffPeriod time.Duration) *pb.Payee {
+	return &pb.Payee{
+		Id:              payee.ID,
+		AccountId:       payee.AccountID,
+		Nickname:        payee.Nickname,
+		HolderName:      payee.HolderName,
+		CreatedAt:       timestamppb.New(payee.CreatedAt),
+		CoolingOffUntil: timestamppb.New(payee.CreatedAt.Add(coolingOffPeriod)),
+	}
+}
+
+func convertPaymentRequest(request db.PaymentRequest) *pb.PaymentRequest {
+	return &pb.PaymentRequest{
+		Id:          request.ID,
+		Requester:   request.Requester,
+		Payer:       request.Payer,
+		ToAccountId: request.ToAccountID,
+		Amount:      request.Amount,
+		Currency:    request.Currency,
+		Memo:        request.Memo,
+		Status:      request.Status,
+		TransferId:  request.TransferID.Int64,
+		ExpiresAt:   timestamppb.New(request.ExpiresAt),
+		CreatedAt:   timestamppb.New(request.CreatedAt),
+	}
+}
+
+func convertExternalPayment(payment db.ExternalPayment) *pb.ExternalPayment {
+	rsp := &pb.ExternalPayment{
+		TransferId:            payment.TransferID,
+		Network:               payment.Network,
+		CreditorAccountNumber: payment.CreditorAccountNumber,
+		CreditorName:          payment.CreditorName,
+		NetworkReference:      payment.NetworkReference.String,
+		FailureReason:         payment.FailureReason,
+		ReversalTransferId:    payment.ReversalTransferID.Int64,
+		UpdatedAt:             timestamppb.New(payment.UpdatedAt),
+		CreditorRoutingNumber: payment.CreditorRoutingNumber,
+		AchFileId:             payment.AchFileID.Int64,
+	}
+	if payment.SubmittedAt.Valid {
+		rsp.SubmittedAt = timestamppb.New(payment.SubmittedAt.Time)
+	}
+	if payment.SettledAt.Valid {
+		rsp.SettledAt = timestamppb.New(payment.SettledAt.Time)
+	}
+	return rsp
+}
+
+func convertACHFile(file db.AchFile) *pb.ACHFile {
+	return &pb.ACHFile{
+		Id:             file.ID,
+		FileIdModifier: file.FileIDModifier,
+		EntryCount:     file.EntryCount,
+		TotalCredit:    file.TotalCredit,
+		CreatedAt:      timestamppb.New(file.CreatedAt),
+	}
+}
+
+func convertFeeSchedule(schedule db.FeeSchedule) *pb.FeeSchedule {
+	feeSchedule := &pb.FeeSchedule{
+		Id:                  schedule.ID,
+		TransferType:        schedule.TransferType,
+		FlatFee:             schedule.FlatFee,
+		PercentageBps:       schedule.PercentageBps,
+		PercentageThreshold: schedule.PercentageThreshold,
+		MaxFee:              schedule.MaxFee,
+		CreatedAt:           timestamppb.New(schedule.CreatedAt),
+	}
+	if schedule.ProductType.Valid {
+		feeSchedule.ProductType = &schedule.ProductType.String
+	}
+	return feeSchedule
+}
+
+func convertWebhookSubscription(subscription db.WebhookSubscription) *pb.WebhookSubscription {
+	return &pb.WebhookSubscription{
+		Id:         subscription.ID,
+		Url:        subscription.Url,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  timestamppb.New(subscription.CreatedAt),
+	}
+}
+
+func convertWebhookDelivery(delivery db.WebhookDelivery) *pb.WebhookDelivery {
+	rsp := &pb.WebhookDelivery{
+		Id:             delivery.ID,
+		SubscriptionId: delivery.SubscriptionID,
+		EventId:        delivery.EventID.String(),
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		CreatedAt:      timestamppb.New(delivery.CreatedAt),
+	}
+	if delivery.DeliveredAt.Valid {
+		rsp.DeliveredAt = timestamppb.New(delivery.DeliveredAt.Time)
+	}
+	return rsp
+}
+
+func convertTask(info *asynq.TaskInfo) *pb.Task {
+	rsp := &pb.Task{
+		Id:        info.ID,
+		Queue:     info.Queue,
+		Type:      info.Type,
+		Payload:   string(info.Payload),
+		State:     info.State.String(),
+		Retried:   int32(info.Retried),
+		MaxRetry:  int32(info.MaxRetry),
+		LastError: info.LastErr,
+	}
+	if !info.LastFailedAt.IsZero() {
+		rsp.LastFailedAt = timestamppb.New(info.LastFailedAt)
+	}
+	if !info.NextProcessAt.IsZero() {
+		rsp.NextProcessAt = timestamppb.New(info.NextProcessAt)
+	}
+	return rsp
+}
+
+func convertTaskFailure(failure db.TaskFailure) *pb.TaskFailure {
+	return &pb.TaskFailure{
+		Id:        failure.ID,
+		Error:     failure.Error,
+		Retried:   failure.Retried,
+		Archived:  failure.Archived,
+		CreatedAt: timestamppb.New(failure.CreatedAt),
+	}
+}
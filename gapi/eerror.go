@@ -15,6 +15,11 @@ func permissionDeniedError(err error) error {
 	return status.Errorf(codes.PermissionDenied, "permission denied: %v", err)
 }
 
+// 业务前置条件不满足，比如账户被冻结、余额不足 HTTP 400
+func failedPreconditionError(err error) error {
+	return status.Errorf(codes.FailedPrecondition, "failed precondition: %v", err)
+}
+
 // 把一个字段名和错误信息包装成 gRPC 专用的详情对象
 func fieldViolation(field string, err error) *errdetails.BadRequest_FieldViolation {
 	return &errdetails.BadRequest_FieldViolation{
@@ -22,7 +22,28 @@ func GrpcLogger(
 
 	result, err := handler(ctx, req)
 
-	duration := time.Since(startTime)
+	logGrpcRequest(info.FullMethod, time.Since(startTime), err)
+
+	return result, err
+}
+
+// GrpcStreamLogger 流式接口在连接结束时记一条日志
+func GrpcStreamLogger(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	startTime := time.Now()
+
+	err := handler(srv, stream)
+
+	logGrpcRequest(info.FullMethod, time.Since(startTime), err)
+
+	return err
+}
+
+func logGrpcRequest(method string, duration time.Duration, err error) {
 	statusCode := codes.Unknown
 	if st, ok := status.FromError(err); ok {
 		statusCode = st.Code()
@@ -30,7 +51,7 @@ func GrpcLogger(
 
 	logger := slog.With(
 		slog.String("protocol", "grpc"),
-		slog.String("method", info.FullMethod),
+		slog.String("method", method),
 		slog.Int("status_code", int(statusCode)),
 		slog.String("status_text", statusCode.String()),
 		slog.Duration("duration", duration),
@@ -41,8 +62,6 @@ func GrpcLogger(
 	} else {
 		logger.Info("received a gRPC request")
 	}
-
-	return result, err
 }
 
 type responseBodyWriter struct {
@@ -55,6 +74,11 @@ func (w *responseBodyWriter) WriteHeader(code int) {
 	w.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap 让 http.ResponseController 能找到底层连接，SSE 需要 Flush
+func (w *responseBodyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 func HttpLogger(nextHandler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		startTime := time.Now()
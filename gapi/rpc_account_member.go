@@ -0,0 +1,151 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"simplebank/pb"
+	"simplebank/util"
+	"simplebank/val"
+
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func isAccountOwner(role string) bool {
+	return role == util.MemberRoleOwner
+}
+
+// AddAccountMember 只有 owner 可以把其他用户加为 co_owner 或 viewer
+func (server *Server) AddAccountMember(ctx context.Context, req *pb.AddAccountMemberRequest) (*pb.AddAccountMemberResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateAddAccountMemberRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	_, err = server.authorizeMember(ctx, req.GetAccountId(), authPayload.Username, isAccountOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := server.store.CreateAccountMember(ctx, db.CreateAccountMemberParams{
+		AccountID: req.GetAccountId(),
+		Username:  req.GetUsername(),
+		Role:      req.GetRole(),
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				return nil, status.Errorf(codes.AlreadyExists, "%s is already a member of account [%d]", req.GetUsername(), req.GetAccountId())
+			case "foreign_key_violation":
+				return nil, status.Errorf(codes.NotFound, "user %s not found", req.GetUsername())
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "failed to add account member: %s", err)
+	}
+
+	return &pb.AddAccountMemberResponse{Member: convertAccountMember(member)}, nil
+}
+
+// RemoveAccountMember owner 不能被移除，账户至少保留一个 owner
+func (server *Server) RemoveAccountMember(ctx context.Context, req *pb.RemoveAccountMemberRequest) (*pb.RemoveAccountMemberResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateRemoveAccountMemberRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	_, err = server.authorizeMember(ctx, req.GetAccountId(), authPayload.Username, isAccountOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := server.authorizeMember(ctx, req.GetAccountId(), req.GetUsername(), nil)
+	if err != nil {
+		if status.Code(err) == codes.PermissionDenied {
+			return nil, status.Errorf(codes.NotFound, "%s is not a member of account [%d]", req.GetUsername(), req.GetAccountId())
+		}
+		return nil, err
+	}
+	if target.Role == util.MemberRoleOwner {
+		return nil, failedPreconditionError(fmt.Errorf("cannot remove the account owner"))
+	}
+
+	err = server.store.DeleteAccountMember(ctx, db.DeleteAccountMemberParams{
+		AccountID: req.GetAccountId(),
+		Username:  req.GetUsername(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to remove account member: %s", err)
+	}
+
+	return &pb.RemoveAccountMemberResponse{}, nil
+}
+
+// ListAccountMembers 任何成员都可以查看成员列表
+func (server *Server) ListAccountMembers(ctx context.Context, req *pb.ListAccountMembersRequest) (*pb.ListAccountMembersResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("account_id", err)})
+	}
+
+	_, err = server.authorizeMember(ctx, req.GetAccountId(), authPayload.Username, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := server.store.ListAccountMembers(ctx, req.GetAccountId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list account members: %s", err)
+	}
+
+	rsp := &pb.ListAccountMembersResponse{}
+	for _, member := range members {
+		rsp.Members = append(rsp.Members, convertAccountMember(member))
+	}
+	return rsp, nil
+}
+
+func validateAddAccountMemberRequest(req *pb.AddAccountMemberRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if err := val.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
+
+	if err := val.ValidateMemberRole(req.GetRole()); err != nil {
+		violations = append(violations, fieldViolation("role", err))
+	}
+
+	return violations
+}
+
+func validateRemoveAccountMemberRequest(req *pb.RemoveAccountMemberRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if err := val.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
+
+	return violations
+}
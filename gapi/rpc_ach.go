@@ -0,0 +1,72 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"simplebank/nacha"
+	"simplebank/pb"
+	"simplebank/util"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ExportACHFile 运营导出待提交的他行付款，文件里的付款都变成 pending
+func (server *Server) ExportACHFile(ctx context.Context, req *pb.ExportACHFileRequest) (*pb.ExportACHFileResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	result, err := server.achService.Export(ctx, time.Now())
+	if err != nil {
+		if errors.Is(err, nacha.ErrNoPayments) {
+			return nil, failedPreconditionError(err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to export ACH file: %s", err)
+	}
+
+	rsp := &pb.ExportACHFileResponse{
+		AchFile: convertACHFile(result.ACHFile),
+		Content: result.ACHFile.Content,
+	}
+	return rsp, nil
+}
+
+// ProcessACHReturns 处理收款行的退回文件，逐笔返回结果
+func (server *Server) ProcessACHReturns(ctx context.Context, req *pb.ProcessACHReturnsRequest) (*pb.ProcessACHReturnsResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if strings.TrimSpace(req.GetContent()) == "" {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("content", fmt.Errorf("must not be empty"))})
+	}
+
+	results, err := server.achService.ProcessReturns(ctx, strings.NewReader(req.GetContent()))
+	if err != nil {
+		// 解析失败，整个文件都没处理
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("content", err)})
+	}
+
+	rsp := &pb.ProcessACHReturnsResponse{
+		Returns: make([]*pb.ACHReturn, 0, len(results)),
+	}
+	for _, result := range results {
+		rsp.Returns = append(rsp.Returns, &pb.ACHReturn{
+			OriginalTraceNumber: result.OriginalTraceNumber,
+			ReturnCode:          result.ReturnCode,
+			Reason:              nacha.ReturnReason(result.ReturnCode),
+			Amount:              result.Amount,
+			TransferId:          result.TransferID,
+			Status:              result.Status,
+			Error:               result.Error,
+		})
+	}
+	return rsp, nil
+}
@@ -0,0 +1,69 @@
+package gapi
+
+import (
+	"context"
+	db "simplebank/db/sqlc"
+	"simplebank/pb"
+	"simplebank/util"
+	"simplebank/val"
+
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreateAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	productType := util.CheckingProduct
+	if req.ProductType != nil {
+		productType = req.GetProductType()
+	}
+
+	accountNumber, err := util.NewAccountNumber(server.config.AccountNumberBankCode)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate account number: %s", err)
+	}
+
+	result, err := server.store.CreateAccountTx(ctx, db.CreateAccountParams{
+		Owner:         authPayload.Username,
+		Balance:       0,
+		Currency:      req.GetCurrency(),
+		ProductType:   productType,
+		AccountNumber: accountNumber,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				return nil, status.Errorf(codes.AlreadyExists, "%s account in %s already exists", productType, req.GetCurrency())
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create account: %s", err)
+	}
+
+	return &pb.CreateAccountResponse{Account: convertAccount(result.Account)}, nil
+}
+
+func validateCreateAccountRequest(req *pb.CreateAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	if req.ProductType != nil {
+		if err := val.ValidateProductType(req.GetProductType()); err != nil {
+			violations = append(violations, fieldViolation("product_type", err))
+		}
+	}
+
+	return violations
+}
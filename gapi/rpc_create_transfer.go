@@ -0,0 +1,367 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"simplebank/pb"
+	"simplebank/quote"
+	"simplebank/util"
+	"simplebank/val"
+	"simplebank/worker"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateTransfer(ctx context.Context, req *pb.CreateTransferRequest) (*pb.CreateTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreateTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	fromAccount, toAccount, err := server.transferAccounts(ctx, req, authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.TransferTxParams{
+		FromAccountID:     fromAccount.ID,
+		ToAccountID:       toAccount.ID,
+		Amount:            req.GetAmount(),
+		Memo:              req.GetMemo(),
+		PrivateNote:       req.GetPrivateNote(),
+		ExternalReference: req.GetExternalReference(),
+		HideToAccount:     req.Recipient != nil,
+	}
+	if len(req.GetMetadata()) > 0 {
+		arg.Metadata, err = json.Marshal(req.GetMetadata())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal metadata: %s", err)
+		}
+	}
+
+	if req.QuoteId != nil {
+		terms, err := server.verifyQuote(req, authPayload.Username, fromAccount.ID, toAccount.ID)
+		if err != nil {
+			return nil, err
+		}
+		arg.QuotedFee = sql.NullInt64{Int64: terms.Fee, Valid: true}
+		arg.QuoteID = uuid.NullUUID{UUID: terms.ID, Valid: true}
+	}
+
+	if server.requiresApproval(req.GetAmount()) {
+		return server.createPendingTransfer(ctx, arg, authPayload.Username)
+	}
+
+	arg.AfterTransfer = func(q db.Querier, result db.TransferTxResult) error {
+		return publishTransferPosted(ctx, worker.NewOutboxTaskDistributor(q), result)
+	}
+
+	result, err := server.store.TransferTX(ctx, arg)
+	if err != nil {
+		return nil, transferError(err)
+	}
+
+	rsp := &pb.CreateTransferResponse{
+		Transfer:    convertSentTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+	}
+	return rsp, nil
+}
+
+// transferParties 是 CreateTransferRequest 和 QuoteTransferRequest 共用的收付款方字段
+type transferParties interface {
+	GetFromAccountId() int64
+	GetFromAccountNumber() string
+	GetToAccountId() int64
+	GetToAccountNumber() string
+	GetPayeeId() int64
+	GetRecipient() string
+	GetAmount() int64
+	GetCurrency() string
+}
+
+// transferAccounts 找出转出和转入账户，并检查当前用户有转出权限
+// 可选字段都已经校验过非空，这里直接按取值判断用的是哪种方式
+func (server *Server) transferAccounts(ctx context.Context, req transferParties, username string) (fromAccount db.Account, toAccount db.Account, err error) {
+	fromAccount, err = server.validAccountRef(ctx, req.GetFromAccountId(), req.GetFromAccountNumber(), req.GetCurrency())
+	if err != nil {
+		return
+	}
+
+	_, err = server.authorizeMember(ctx, fromAccount.ID, username, util.CanTransfer)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case req.GetPayeeId() != 0:
+		var toAccountID int64
+		toAccountID, err = server.payeeAccount(ctx, req.GetPayeeId(), username, req.GetAmount())
+		if err != nil {
+			return
+		}
+		toAccount, err = server.validAccount(ctx, toAccountID, req.GetCurrency())
+	case req.GetRecipient() != "":
+		toAccount, err = server.recipientAccount(ctx, req.GetRecipient(), req.GetCurrency())
+	case req.GetToAccountNumber() != "":
+		toAccount, err = server.validAccountByNumber(ctx, req.GetToAccountNumber(), req.GetCurrency())
+	default:
+		toAccount, err = server.validAccount(ctx, req.GetToAccountId(), req.GetCurrency())
+	}
+	return
+}
+
+// verifyQuote 报价必须是发给当前用户的，且收付款方、金额、币种和这次转账完全一致
+func (server *Server) verifyQuote(req *pb.CreateTransferRequest, username string, fromAccountID int64, toAccountID int64) (*quote.Terms, error) {
+	terms, err := server.quoteMaker.VerifyQuote(req.GetQuoteId())
+	if err != nil {
+		if errors.Is(err, quote.ErrExpiredQuote) {
+			return nil, failedPreconditionError(err)
+		}
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+	}
+
+	if terms.Username != username {
+		return nil, status.Errorf(codes.PermissionDenied, "quote was issued to another user")
+	}
+
+	if terms.FromAccountID != fromAccountID ||
+		terms.ToAccountID != toAccountID ||
+		terms.Amount != req.GetAmount() ||
+		terms.Currency != req.GetCurrency() {
+		return nil, status.Errorf(codes.InvalidArgument, "transfer does not match the quote")
+	}
+
+	return terms, nil
+}
+
+// recipientAccount 找收款人在该币种下的活期账户
+// 用户不存在、邮箱未验证、没有该币种账户都返回同样的 NotFound
+func (server *Server) recipientAccount(ctx context.Context, recipient string, currency string) (db.Account, error) {
+	account, err := server.store.GetRecipientAccount(ctx, db.GetRecipientAccountParams{
+		Recipient:   recipient,
+		Currency:    currency,
+		ProductType: util.CheckingProduct,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return account, status.Errorf(codes.NotFound, "recipient cannot receive transfers in %s", currency)
+		}
+		return account, status.Errorf(codes.Internal, "failed to get recipient account")
+	}
+
+	return account, nil
+}
+
+func (server *Server) validAccount(ctx context.Context, accountID int64, currency string) (db.Account, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	// 银行内部账户对外当作不存在，客户不能往里转账
+	if err == sql.ErrNoRows || (err == nil && account.Owner == util.BankUsername) {
+		return db.Account{}, status.Errorf(codes.NotFound, "account [%d] not found", accountID)
+	}
+	if err != nil {
+		return account, status.Errorf(codes.Internal, "failed to get account")
+	}
+
+	return account, checkAccountCurrency(account, currency)
+}
+
+func (server *Server) validAccountByNumber(ctx context.Context, accountNumber string, currency string) (db.Account, error) {
+	account, err := server.store.GetAccountByNumber(ctx, accountNumber)
+	if err == sql.ErrNoRows || (err == nil && account.Owner == util.BankUsername) {
+		return db.Account{}, status.Errorf(codes.NotFound, "account %s not found", accountNumber)
+	}
+	if err != nil {
+		return account, status.Errorf(codes.Internal, "failed to get account")
+	}
+
+	return account, checkAccountCurrency(account, currency)
+}
+
+// validAccountRef 请求里账户 ID 和对外账号二选一，已经校验过只填了一个
+func (server *Server) validAccountRef(ctx context.Context, accountID int64, accountNumber string, currency string) (db.Account, error) {
+	if accountNumber != "" {
+		return server.validAccountByNumber(ctx, accountNumber, currency)
+	}
+	return server.validAccount(ctx, accountID, currency)
+}
+
+// lookupAccount 和 validAccountRef 一样按 ID 或账号找账户，不检查币种
+// 调用方自己检查权限，运营接口也能找到银行系统账户
+func (server *Server) lookupAccount(ctx context.Context, accountID int64, accountNumber string) (db.Account, error) {
+	if accountNumber != "" {
+		account, err := server.store.GetAccountByNumber(ctx, accountNumber)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return account, status.Errorf(codes.NotFound, "account %s not found", accountNumber)
+			}
+			return account, status.Errorf(codes.Internal, "failed to get account")
+		}
+		return account, nil
+	}
+
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return account, status.Errorf(codes.NotFound, "account [%d] not found", accountID)
+		}
+		return account, status.Errorf(codes.Internal, "failed to get account")
+	}
+	return account, nil
+}
+
+func checkAccountCurrency(account db.Account, currency string) error {
+	if account.Currency != currency {
+		return status.Errorf(codes.InvalidArgument, "account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, currency)
+	}
+	return nil
+}
+
+// notifyOverdraft 转账后余额由正转负、或者用满透支额度时给客户发通知，amount 是含手续费的扣款合计
+// 在转账事务的钩子里调用，通知任务写进 outbox 和转账一起提交
+func notifyOverdraft(ctx context.Context, distributor worker.TaskDistributor, account db.Account, amount int64) error {
+	var event string
+	switch {
+	case account.OverdraftLimit > 0 && account.Balance == -account.OverdraftLimit:
+		event = worker.OverdraftEventLimitReached
+	case account.Balance < 0 && account.Balance+amount >= 0:
+		event = worker.OverdraftEventOverdrawn
+	default:
+		return nil
+	}
+
+	return worker.TaskSendOverdraftNotice.Distribute(ctx, distributor, &worker.PayloadSendOverdraftNotice{
+		AccountID: account.ID,
+		Event:     event,
+	})
+}
+
+// 把 TransferTX 返回的业务错误翻译成 gRPC 状态码
+func transferError(err error) error {
+	if errors.Is(err, db.ErrAccountFrozen) ||
+		errors.Is(err, db.ErrTransferLimitExceeded) ||
+		errors.Is(err, db.ErrMinimumBalance) ||
+		errors.Is(err, db.ErrInsufficientFunds) ||
+		errors.Is(err, db.ErrPaymentRequestClosed) ||
+		errors.Is(err, db.ErrPaymentRequestExpired) ||
+		errors.Is(err, db.ErrTransferNotPending) ||
+		errors.Is(err, db.ErrApprovalExpired) ||
+		errors.Is(err, db.ErrExternalTransferNotAllowed) ||
+		errors.Is(err, db.ErrInvalidTransferTransition) {
+		return failedPreconditionError(err)
+	}
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Code.Name() {
+		case "check_violation":
+			return failedPreconditionError(fmt.Errorf("insufficient balance"))
+		case "unique_violation":
+			// 目前只有 quote_id 是唯一的
+			return status.Errorf(codes.AlreadyExists, "quote has already been used")
+		}
+	}
+	return status.Errorf(codes.Internal, "failed to transfer: %s", err)
+}
+
+func validateCreateTransferRequest(req *pb.CreateTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	violations = validateTransferParties(req)
+
+	if err := val.ValidateMemo(req.GetMemo()); err != nil {
+		violations = append(violations, fieldViolation("memo", err))
+	}
+
+	if err := val.ValidatePrivateNote(req.GetPrivateNote()); err != nil {
+		violations = append(violations, fieldViolation("private_note", err))
+	}
+
+	if err := val.ValidateExternalReference(req.GetExternalReference()); err != nil {
+		violations = append(violations, fieldViolation("external_reference", err))
+	}
+
+	if err := val.ValidateMetadata(req.GetMetadata()); err != nil {
+		violations = append(violations, fieldViolation("metadata", err))
+	}
+
+	if req.QuoteId != nil {
+		if err := val.ValidateString(req.GetQuoteId(), 1, 1000); err != nil {
+			violations = append(violations, fieldViolation("quote_id", err))
+		}
+	}
+
+	return violations
+}
+
+func validateTransferParties(req transferParties) (violations []*errdetails.BadRequest_FieldViolation) {
+	violations = append(violations, validateAccountRef("from_account", req.GetFromAccountId(), req.GetFromAccountNumber())...)
+
+	targets := 0
+	for _, set := range []bool{
+		req.GetToAccountId() != 0,
+		req.GetToAccountNumber() != "",
+		req.GetPayeeId() != 0,
+		req.GetRecipient() != "",
+	} {
+		if set {
+			targets++
+		}
+	}
+	if targets > 1 {
+		violations = append(violations, fieldViolation("to_account_id", fmt.Errorf("only one of to_account_id, to_account_number, payee_id or recipient can be set")))
+	}
+
+	switch {
+	case req.GetPayeeId() != 0:
+		if err := val.ValidateID(req.GetPayeeId()); err != nil {
+			violations = append(violations, fieldViolation("payee_id", err))
+		}
+	case req.GetRecipient() != "":
+		if err := val.ValidateString(req.GetRecipient(), 3, 200); err != nil {
+			violations = append(violations, fieldViolation("recipient", err))
+		}
+	case req.GetToAccountNumber() != "":
+		if err := val.ValidateAccountNumber(req.GetToAccountNumber()); err != nil {
+			violations = append(violations, fieldViolation("to_account_number", err))
+		}
+	default:
+		if err := val.ValidateID(req.GetToAccountId()); err != nil {
+			violations = append(violations, fieldViolation("to_account_id", err))
+		}
+	}
+
+	if err := val.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	return violations
+}
+
+// validateAccountRef 校验 <name>_id 和 <name>_number 二选一
+func validateAccountRef(name string, accountID int64, accountNumber string) (violations []*errdetails.BadRequest_FieldViolation) {
+	if accountNumber != "" {
+		if accountID != 0 {
+			violations = append(violations, fieldViolation(name+"_id", fmt.Errorf("only one of %s_id or %s_number can be set", name, name)))
+		}
+		if err := val.ValidateAccountNumber(accountNumber); err != nil {
+			violations = append(violations, fieldViolation(name+"_number", err))
+		}
+	} else if err := val.ValidateID(accountID); err != nil {
+		violations = append(violations, fieldViolation(name+"_id", err))
+	}
+	return violations
+}
@@ -34,18 +34,11 @@ func (server *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest)
 			FullName:       req.GetFullName(),
 			Email:          req.GetEmail(),
 		},
-		AfterCreate: func(user db.User) error {
+		// 验证邮件任务写进 outbox，和用户一起提交
+		AfterCreate: func(q db.Querier, user db.User) error {
 			payload := &worker.PayloadSendVerifyEmail{Username: user.Username}
-			opts := []asynq.Option{
-				asynq.MaxRetry(10),
-				asynq.ProcessIn(10 * time.Second),
-				asynq.Queue("critical"),
-			}
-			err = server.taskDistributor.DistributeTaskSendVerifyEmail(ctx, payload, opts...)
-			if err != nil {
-				return status.Error(codes.Internal, "failed to distribute task to send verify email")
-			}
-			return nil
+			distributor := worker.NewOutboxTaskDistributor(q)
+			return worker.TaskSendVerifyEmail.Distribute(ctx, distributor, payload, asynq.ProcessIn(10*time.Second))
 		},
 	}
 
@@ -0,0 +1,151 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"simplebank/iso20022"
+	"simplebank/pb"
+	"simplebank/util"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxStatementPeriod 一份对账单最长的时间跨度
+const maxStatementPeriod = 366 * 24 * time.Hour
+
+// ExportStatement 把账户一段时间的分录导出成 camt.053 对账单，账户成员都可以导
+func (server *Server) ExportStatement(ctx context.Context, req *pb.ExportStatementRequest) (*pb.ExportStatementResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateExportStatementRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.lookupAccount(ctx, req.GetAccountId(), req.GetAccountNumber())
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = server.authorizeMember(ctx, account.ID, authPayload.Username, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := server.store.GetUser(ctx, account.Owner)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get account owner: %s", err)
+	}
+
+	fromTime := req.GetFromTime().AsTime()
+	toTime := req.GetToTime().AsTime()
+
+	// 余额等于全部分录之和，从当前余额倒推期初和期末
+	sinceFrom, err := server.store.SumAccountEntriesSince(ctx, db.SumAccountEntriesSinceParams{
+		AccountID: account.ID,
+		CreatedAt: fromTime,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to sum entries: %s", err)
+	}
+
+	sinceTo, err := server.store.SumAccountEntriesSince(ctx, db.SumAccountEntriesSinceParams{
+		AccountID: account.ID,
+		CreatedAt: toTime,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to sum entries: %s", err)
+	}
+
+	entries, err := server.store.ListStatementEntries(ctx, db.ListStatementEntriesParams{
+		AccountID: account.ID,
+		FromTime:  fromTime,
+		ToTime:    toTime,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list entries: %s", err)
+	}
+
+	now := time.Now()
+	statement := iso20022.Statement{
+		MessageID:      fmt.Sprintf("STMT-%d-%s", account.ID, now.UTC().Format("20060102150405")),
+		CreatedAt:      now,
+		AccountNumber:  account.AccountNumber,
+		Currency:       account.Currency,
+		OwnerName:      owner.FullName,
+		From:           fromTime,
+		To:             toTime,
+		OpeningBalance: account.Balance - sinceFrom,
+		ClosingBalance: account.Balance - sinceTo,
+		Entries:        convertStatementEntries(entries),
+	}
+
+	content, err := iso20022.MarshalStatement(statement)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal statement: %s", err)
+	}
+
+	rsp := &pb.ExportStatementResponse{
+		Content:        string(content),
+		OpeningBalance: statement.OpeningBalance,
+		ClosingBalance: statement.ClosingBalance,
+		EntryCount:     int32(len(entries)),
+	}
+	return rsp, nil
+}
+
+// convertStatementEntries 转出方的同一笔转账有本金和手续费两条借记分录，第二条是手续费
+func convertStatementEntries(entries []db.ListStatementEntriesRow) []iso20022.StatementEntry {
+	debited := make(map[int64]bool)
+	result := make([]iso20022.StatementEntry, 0, len(entries))
+	for _, entry := range entries {
+		statementEntry := iso20022.StatementEntry{
+			EntryID:                   entry.ID,
+			Amount:                    entry.Amount,
+			BookedAt:                  entry.CreatedAt,
+			Memo:                      entry.Memo,
+			EndToEndID:                entry.ExternalReference,
+			CounterpartyName:          entry.CounterpartyName,
+			CounterpartyAccountNumber: entry.CounterpartyAccountNumber,
+		}
+
+		if entry.TransferID.Valid {
+			statementEntry.TransferID = entry.TransferID.Int64
+			if entry.Amount < 0 {
+				statementEntry.Fee = debited[entry.TransferID.Int64] && entry.Fee > 0
+				debited[entry.TransferID.Int64] = true
+			}
+		}
+
+		result = append(result, statementEntry)
+	}
+	return result
+}
+
+func validateExportStatementRequest(req *pb.ExportStatementRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	violations = append(violations, validateAccountRef("account", req.GetAccountId(), req.GetAccountNumber())...)
+
+	if req.FromTime == nil {
+		violations = append(violations, fieldViolation("from_time", fmt.Errorf("must be set")))
+	}
+
+	if req.ToTime == nil {
+		violations = append(violations, fieldViolation("to_time", fmt.Errorf("must be set")))
+	}
+
+	if req.FromTime != nil && req.ToTime != nil {
+		period := req.GetToTime().AsTime().Sub(req.GetFromTime().AsTime())
+		if period <= 0 || period > maxStatementPeriod {
+			violations = append(violations, fieldViolation("to_time", fmt.Errorf("must be after from_time and within %s", maxStatementPeriod)))
+		}
+	}
+
+	return violations
+}
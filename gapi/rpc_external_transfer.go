@@ -0,0 +1,194 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"simplebank/nacha"
+	"simplebank/pb"
+	"simplebank/util"
+	"simplebank/val"
+	"simplebank/worker"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateExternalTransfer 向他行账户转账，钱先进清算账户，由 worker 异步提交给清算网络
+func (server *Server) CreateExternalTransfer(ctx context.Context, req *pb.CreateExternalTransferRequest) (*pb.CreateExternalTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreateExternalTransferRequest(req, server.config.SettlementNetwork)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// 本行账号走 CreateTransfer，避免钱绕清算网络一圈
+	bankCode := util.AccountNumberBankCode(req.GetCreditorAccountNumber())
+	if bankCode == server.config.AccountNumberBankCode || bankCode == util.SystemBankCode {
+		return nil, status.Errorf(codes.InvalidArgument, "creditor account belongs to this bank, use CreateTransfer instead")
+	}
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = server.authorizeMember(ctx, fromAccount.ID, authPayload.Username, util.CanTransfer)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.InitiateExternalTransferTxParams{
+		FromAccountID:         fromAccount.ID,
+		Amount:                req.GetAmount(),
+		Memo:                  req.GetMemo(),
+		PrivateNote:           req.GetPrivateNote(),
+		ExternalReference:     req.GetExternalReference(),
+		Network:               server.config.SettlementNetwork,
+		CreditorAccountNumber: req.GetCreditorAccountNumber(),
+		CreditorName:          req.GetCreditorName(),
+		CreditorRoutingNumber: req.GetCreditorRoutingNumber(),
+		// 提交任务和转账一起提交，不会停在 initiated 没人处理
+		AfterInitiate: func(q db.Querier, result db.InitiateExternalTransferTxResult) error {
+			distributor := worker.NewOutboxTaskDistributor(q)
+			payload := &worker.PayloadSubmitExternalTransfer{TransferID: result.Transfer.ID}
+			err := worker.TaskSubmitExternalTransfer.Distribute(ctx, distributor, payload)
+			if err != nil {
+				return err
+			}
+			err = notifyOverdraft(ctx, distributor, result.FromAccount, result.Transfer.Amount+result.Transfer.Fee)
+			if err != nil {
+				return err
+			}
+			// 转入的是清算账户，不发 account.credited
+			return publishTransferCreated(ctx, distributor, result.Transfer, result.FromAccount)
+		},
+	}
+
+	if server.requiresApproval(req.GetAmount()) {
+		return server.createPendingExternalTransfer(ctx, arg, authPayload.Username)
+	}
+
+	result, err := server.store.InitiateExternalTransferTx(ctx, arg)
+	if err != nil {
+		return nil, transferError(err)
+	}
+
+	rsp := &pb.CreateExternalTransferResponse{
+		Transfer:        convertTransfer(result.Transfer),
+		ExternalPayment: convertExternalPayment(result.ExternalPayment),
+		FromAccount:     convertAccount(result.FromAccount),
+	}
+	return rsp, nil
+}
+
+// createPendingExternalTransfer 超过审批阈值的他行转账先占用资金，批准后才提交清算网络
+func (server *Server) createPendingExternalTransfer(ctx context.Context, arg db.InitiateExternalTransferTxParams, username string) (*pb.CreateExternalTransferResponse, error) {
+	result, err := server.store.InitiatePendingExternalTransferTx(ctx, db.InitiatePendingExternalTransferTxParams{
+		InitiateExternalTransferTxParams: arg,
+		RequestedBy:                      username,
+		ExpiresAt:                        time.Now().Add(server.config.TransferApprovalTimeout),
+		AfterCreate: func(q db.Querier, result db.InitiatePendingExternalTransferTxResult) error {
+			return publishTransferCreated(ctx, worker.NewOutboxTaskDistributor(q), result.Transfer, result.FromAccount)
+		},
+	})
+	if err != nil {
+		return nil, transferError(err)
+	}
+
+	rsp := &pb.CreateExternalTransferResponse{
+		Transfer:        convertTransfer(result.Transfer),
+		ExternalPayment: convertExternalPayment(result.ExternalPayment),
+		FromAccount:     convertAccount(result.FromAccount),
+	}
+	return rsp, nil
+}
+
+func validateCreateExternalTransferRequest(req *pb.CreateExternalTransferRequest, network string) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+
+	if err := val.ValidateAccountNumber(req.GetCreditorAccountNumber()); err != nil {
+		violations = append(violations, fieldViolation("creditor_account_number", err))
+	}
+
+	if err := val.ValidateString(req.GetCreditorName(), 1, 100); err != nil {
+		violations = append(violations, fieldViolation("creditor_name", err))
+	}
+
+	if err := val.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	// 批量文件按路由号找收款行，金额只能是美元
+	if network == nacha.NetworkName {
+		if !nacha.ValidRoutingNumber(req.GetCreditorRoutingNumber()) {
+			violations = append(violations, fieldViolation("creditor_routing_number", fmt.Errorf("must be a valid 9-digit routing number")))
+		}
+		if req.GetCurrency() != util.USD {
+			violations = append(violations, fieldViolation("currency", fmt.Errorf("%s payments only support %s", network, util.USD)))
+		}
+	}
+
+	if err := val.ValidateMemo(req.GetMemo()); err != nil {
+		violations = append(violations, fieldViolation("memo", err))
+	}
+
+	if err := val.ValidatePrivateNote(req.GetPrivateNote()); err != nil {
+		violations = append(violations, fieldViolation("private_note", err))
+	}
+
+	if err := val.ValidateExternalReference(req.GetExternalReference()); err != nil {
+		violations = append(violations, fieldViolation("external_reference", err))
+	}
+
+	return violations
+}
+
+// GetExternalTransfer 查询他行转账的清算进度，转出账户的成员都可以看
+func (server *Server) GetExternalTransfer(ctx context.Context, req *pb.GetExternalTransferRequest) (*pb.GetExternalTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := val.ValidateID(req.GetTransferId()); err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("transfer_id", err)})
+	}
+
+	payment, err := server.store.GetExternalPayment(ctx, req.GetTransferId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "external transfer [%d] not found", req.GetTransferId())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get external payment: %s", err)
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, payment.TransferID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get transfer: %s", err)
+	}
+
+	_, err = server.authorizeMember(ctx, transfer.FromAccountID, authPayload.Username, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.GetExternalTransferResponse{
+		Transfer:        convertTransfer(transfer),
+		ExternalPayment: convertExternalPayment(payment),
+	}
+	return rsp, nil
+}
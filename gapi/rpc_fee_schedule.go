@@ -0,0 +1,121 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"simplebank/pb"
+	"simplebank/util"
+	"simplebank/val"
+
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateFeeSchedule 运营配置手续费，之后的转账和报价按新费率计算
+func (server *Server) CreateFeeSchedule(ctx context.Context, req *pb.CreateFeeScheduleRequest) (*pb.CreateFeeScheduleResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreateFeeScheduleRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	schedule, err := server.store.CreateFeeSchedule(ctx, db.CreateFeeScheduleParams{
+		TransferType: req.GetTransferType(),
+		ProductType: sql.NullString{
+			String: req.GetProductType(),
+			Valid:  req.ProductType != nil,
+		},
+		FlatFee:             req.GetFlatFee(),
+		PercentageBps:       req.GetPercentageBps(),
+		PercentageThreshold: req.GetPercentageThreshold(),
+		MaxFee:              req.GetMaxFee(),
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			return nil, status.Errorf(codes.AlreadyExists, "fee schedule for %s transfers already exists", req.GetTransferType())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create fee schedule: %s", err)
+	}
+
+	return &pb.CreateFeeScheduleResponse{FeeSchedule: convertFeeSchedule(schedule)}, nil
+}
+
+func (server *Server) ListFeeSchedules(ctx context.Context, req *pb.ListFeeSchedulesRequest) (*pb.ListFeeSchedulesResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	schedules, err := server.store.ListFeeSchedules(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list fee schedules: %s", err)
+	}
+
+	rsp := &pb.ListFeeSchedulesResponse{
+		FeeSchedules: make([]*pb.FeeSchedule, 0, len(schedules)),
+	}
+	for _, schedule := range schedules {
+		rsp.FeeSchedules = append(rsp.FeeSchedules, convertFeeSchedule(schedule))
+	}
+	return rsp, nil
+}
+
+// DeleteFeeSchedule 删掉以后按通用配置收费，没有通用配置时不收费
+func (server *Server) DeleteFeeSchedule(ctx context.Context, req *pb.DeleteFeeScheduleRequest) (*pb.DeleteFeeScheduleResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := val.ValidateID(req.GetId()); err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("id", err)})
+	}
+
+	_, err = server.store.DeleteFeeSchedule(ctx, req.GetId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "fee schedule [%d] not found", req.GetId())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to delete fee schedule: %s", err)
+	}
+
+	return &pb.DeleteFeeScheduleResponse{}, nil
+}
+
+func validateCreateFeeScheduleRequest(req *pb.CreateFeeScheduleRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateTransferType(req.GetTransferType()); err != nil {
+		violations = append(violations, fieldViolation("transfer_type", err))
+	}
+
+	if req.ProductType != nil {
+		if err := val.ValidateProductType(req.GetProductType()); err != nil {
+			violations = append(violations, fieldViolation("product_type", err))
+		}
+	}
+
+	if req.GetFlatFee() < 0 {
+		violations = append(violations, fieldViolation("flat_fee", fmt.Errorf("must not be negative")))
+	}
+
+	if req.GetPercentageBps() < 0 || req.GetPercentageBps() > 10_000 {
+		violations = append(violations, fieldViolation("percentage_bps", fmt.Errorf("must be between 0 and 10000")))
+	}
+
+	if req.GetPercentageThreshold() < 0 {
+		violations = append(violations, fieldViolation("percentage_threshold", fmt.Errorf("must not be negative")))
+	}
+
+	if req.GetMaxFee() < 0 {
+		violations = append(violations, fieldViolation("max_fee", fmt.Errorf("must not be negative")))
+	}
+
+	return violations
+}
@@ -0,0 +1,107 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	db "simplebank/db/sqlc"
+	"simplebank/pb"
+	"simplebank/util"
+	"simplebank/val"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) FreezeAccount(ctx context.Context, req *pb.FreezeAccountRequest) (*pb.FreezeAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateFreezeAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.lookupAccount(ctx, req.GetAccountId(), req.GetAccountNumber())
+	if err != nil {
+		return nil, err
+	}
+
+	account, err = server.updateFreezeStatus(ctx, db.FreezeAccountTxParams{
+		AccountID:    account.ID,
+		FreezeStatus: req.GetFreezeStatus(),
+		Reason:       req.GetReason(),
+		Actor:        authPayload.Username,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.FreezeAccountResponse{Account: convertAccount(account)}, nil
+}
+
+func (server *Server) UnfreezeAccount(ctx context.Context, req *pb.UnfreezeAccountRequest) (*pb.UnfreezeAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateUnfreezeAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.lookupAccount(ctx, req.GetAccountId(), req.GetAccountNumber())
+	if err != nil {
+		return nil, err
+	}
+
+	account, err = server.updateFreezeStatus(ctx, db.FreezeAccountTxParams{
+		AccountID:    account.ID,
+		FreezeStatus: util.FreezeStatusActive,
+		Reason:       req.GetReason(),
+		Actor:        authPayload.Username,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UnfreezeAccountResponse{Account: convertAccount(account)}, nil
+}
+
+func (server *Server) updateFreezeStatus(ctx context.Context, arg db.FreezeAccountTxParams) (db.Account, error) {
+	result, err := server.store.FreezeAccountTx(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return result.Account, status.Errorf(codes.NotFound, "account not found")
+		}
+		return result.Account, status.Errorf(codes.Internal, "failed to update account freeze status: %s", err)
+	}
+	return result.Account, nil
+}
+
+func validateFreezeAccountRequest(req *pb.FreezeAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	violations = append(violations, validateAccountRef("account", req.GetAccountId(), req.GetAccountNumber())...)
+
+	if err := val.ValidateFreezeStatus(req.GetFreezeStatus()); err != nil {
+		violations = append(violations, fieldViolation("freeze_status", err))
+	}
+
+	if err := val.ValidateReason(req.GetReason()); err != nil {
+		violations = append(violations, fieldViolation("reason", err))
+	}
+
+	return violations
+}
+
+func validateUnfreezeAccountRequest(req *pb.UnfreezeAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	violations = append(violations, validateAccountRef("account", req.GetAccountId(), req.GetAccountNumber())...)
+
+	if err := val.ValidateReason(req.GetReason()); err != nil {
+		violations = append(violations, fieldViolation("reason", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,255 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	db "simplebank/db/sqlc"
+	"simplebank/iso20022"
+	"simplebank/pb"
+	"simplebank/util"
+	"simplebank/val"
+	"simplebank/worker"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ImportPayments 导入 pain.001 批量付款，逐笔按 CreateTransfer 的规则校验和入账
+// 单笔失败不影响其他笔，结果用 pain.002 返回；同一个 MsgId 只能导入一次
+func (server *Server) ImportPayments(ctx context.Context, req *pb.ImportPaymentsRequest) (*pb.ImportPaymentsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if strings.TrimSpace(req.GetContent()) == "" {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("content", fmt.Errorf("must not be empty"))})
+	}
+
+	initiation, err := iso20022.ParseCreditTransferInitiation(strings.NewReader(req.GetContent()))
+	if err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("content", err)})
+	}
+
+	// 先占住 MsgId，重复上传的文件不会再付一次款
+	paymentImport, err := server.store.CreatePaymentImport(ctx, db.CreatePaymentImportParams{
+		Username:         authPayload.Username,
+		MessageID:        initiation.MessageID,
+		InstructionCount: int32(initiation.NumberOfTransactions),
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			return nil, status.Errorf(codes.AlreadyExists, "payment file %s has already been imported", initiation.MessageID)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create payment import: %s", err)
+	}
+
+	report := iso20022.StatusReport{
+		MessageID:         fmt.Sprintf("STS-%d", paymentImport.ID),
+		CreatedAt:         time.Now(),
+		OriginalMessageID: initiation.MessageID,
+	}
+	endToEndIDs := make(map[string]bool)
+	for _, payment := range initiation.Payments {
+		paymentStatus := iso20022.PaymentStatus{
+			PaymentInformationID: payment.ID,
+		}
+		for _, creditTransfer := range payment.CreditTransfers {
+			txStatus := server.importCreditTransfer(ctx, authPayload.Username, payment, creditTransfer, endToEndIDs)
+			paymentStatus.Transactions = append(paymentStatus.Transactions, txStatus)
+		}
+		report.Payments = append(report.Payments, paymentStatus)
+	}
+
+	rsp := &pb.ImportPaymentsResponse{
+		GroupStatus: report.GroupStatus(),
+	}
+	for _, payment := range report.Payments {
+		for _, tx := range payment.Transactions {
+			switch tx.Status {
+			case iso20022.StatusAcceptedSettlementCompleted:
+				rsp.CompletedCount++
+			case iso20022.StatusPending:
+				rsp.PendingCount++
+			default:
+				rsp.RejectedCount++
+			}
+		}
+	}
+
+	content, err := iso20022.MarshalStatusReport(report)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal status report: %s", err)
+	}
+	rsp.StatusReport = string(content)
+
+	// 款已经付了，结果存不下来只记日志，报告照常返回
+	_, err = server.store.UpdatePaymentImportResult(ctx, db.UpdatePaymentImportResultParams{
+		ID:            paymentImport.ID,
+		AcceptedCount: rsp.CompletedCount + rsp.PendingCount,
+		StatusReport:  rsp.StatusReport,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to save payment import result",
+			slog.Int64("payment_import_id", paymentImport.ID),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	return rsp, nil
+}
+
+// importCreditTransfer 处理一笔付款，失败时返回 RJCT 和原因码而不是 error
+func (server *Server) importCreditTransfer(
+	ctx context.Context,
+	username string,
+	payment iso20022.PaymentInstruction,
+	creditTransfer iso20022.CreditTransfer,
+	endToEndIDs map[string]bool,
+) iso20022.TransactionStatus {
+	txStatus := iso20022.TransactionStatus{
+		InstructionID: creditTransfer.InstructionID,
+		EndToEndID:    creditTransfer.EndToEndID,
+	}
+	reject := func(reasonCode string, err error) iso20022.TransactionStatus {
+		txStatus.Status = iso20022.StatusRejected
+		txStatus.ReasonCode = reasonCode
+		txStatus.AdditionalInfo = status.Convert(err).Message()
+		return txStatus
+	}
+	// 内部错误不把细节写进报告
+	rejectTransfer := func(err error) iso20022.TransactionStatus {
+		reasonCode := importTransferReason(err)
+		if reasonCode == iso20022.ReasonNarrative {
+			slog.ErrorContext(ctx, "failed to import credit transfer",
+				slog.String("instruction_id", creditTransfer.InstructionID),
+				slog.String("error", err.Error()),
+			)
+			err = errors.New("internal error, please retry the payment")
+		}
+		return reject(reasonCode, err)
+	}
+
+	// NOTPROVIDED 是标准里没有端到端 ID 时的占位值
+	endToEndID := creditTransfer.EndToEndID
+	if endToEndID == "NOTPROVIDED" {
+		endToEndID = ""
+	}
+	if endToEndID != "" {
+		if endToEndIDs[endToEndID] {
+			return reject(iso20022.ReasonDuplication, fmt.Errorf("duplicate end to end id %s", endToEndID))
+		}
+		endToEndIDs[endToEndID] = true
+	}
+
+	if err := val.ValidateAmount(creditTransfer.Amount); err != nil {
+		return reject(iso20022.ReasonNotAllowedAmount, err)
+	}
+	if err := val.ValidateCurrency(creditTransfer.Currency); err != nil {
+		return reject(iso20022.ReasonNotAllowedCurrency, err)
+	}
+	if err := val.ValidateAccountNumber(payment.DebtorAccount); err != nil {
+		return reject(iso20022.ReasonIncorrectAccountNumber, fmt.Errorf("debtor account: %w", err))
+	}
+	if err := val.ValidateAccountNumber(creditTransfer.CreditorAccount); err != nil {
+		return reject(iso20022.ReasonInvalidCreditorAccount, fmt.Errorf("creditor account: %w", err))
+	}
+	// 他行付款要走清算网络，批量导入只支持本行账户
+	if util.AccountNumberBankCode(creditTransfer.CreditorAccount) != server.config.AccountNumberBankCode {
+		return reject(iso20022.ReasonInvalidCreditorAccount, fmt.Errorf("creditor account must belong to this bank"))
+	}
+	if err := val.ValidateMemo(creditTransfer.Remittance); err != nil {
+		return reject(iso20022.ReasonNarrative, fmt.Errorf("remittance information: %w", err))
+	}
+	if err := val.ValidateExternalReference(endToEndID); err != nil {
+		return reject(iso20022.ReasonNarrative, fmt.Errorf("end to end id: %w", err))
+	}
+
+	fromAccount, err := server.validAccountByNumber(ctx, payment.DebtorAccount, creditTransfer.Currency)
+	if err != nil {
+		return reject(importAccountReason(err, iso20022.ReasonIncorrectAccountNumber), err)
+	}
+
+	_, err = server.authorizeMember(ctx, fromAccount.ID, username, util.CanTransfer)
+	if err != nil {
+		return reject(importAccountReason(err, iso20022.ReasonTransactionForbidden), err)
+	}
+
+	toAccount, err := server.validAccountByNumber(ctx, creditTransfer.CreditorAccount, creditTransfer.Currency)
+	if err != nil {
+		return reject(importAccountReason(err, iso20022.ReasonInvalidCreditorAccount), err)
+	}
+
+	arg := db.TransferTxParams{
+		FromAccountID:     fromAccount.ID,
+		ToAccountID:       toAccount.ID,
+		Amount:            creditTransfer.Amount,
+		Memo:              creditTransfer.Remittance,
+		ExternalReference: endToEndID,
+	}
+
+	if server.requiresApproval(creditTransfer.Amount) {
+		result, err := server.store.CreatePendingTransferTx(ctx, db.CreatePendingTransferTxParams{
+			TransferTxParams: arg,
+			RequestedBy:      username,
+			ExpiresAt:        time.Now().Add(server.config.TransferApprovalTimeout),
+			AfterCreate: func(q db.Querier, result db.CreatePendingTransferTxResult) error {
+				return publishTransferCreated(ctx, worker.NewOutboxTaskDistributor(q), result.Transfer, result.FromAccount)
+			},
+		})
+		if err != nil {
+			return rejectTransfer(err)
+		}
+
+		txStatus.Status = iso20022.StatusPending
+		txStatus.TransferID = result.Transfer.ID
+		return txStatus
+	}
+
+	arg.AfterTransfer = func(q db.Querier, result db.TransferTxResult) error {
+		return publishTransferPosted(ctx, worker.NewOutboxTaskDistributor(q), result)
+	}
+
+	result, err := server.store.TransferTX(ctx, arg)
+	if err != nil {
+		return rejectTransfer(err)
+	}
+
+	txStatus.Status = iso20022.StatusAcceptedSettlementCompleted
+	txStatus.TransferID = result.Transfer.ID
+	return txStatus
+}
+
+// importAccountReason 把查账户和校验成员返回的 gRPC 状态换成拒绝原因码
+func importAccountReason(err error, notFound string) string {
+	switch status.Code(err) {
+	case codes.NotFound, codes.PermissionDenied:
+		return notFound
+	case codes.InvalidArgument:
+		return iso20022.ReasonNotAllowedCurrency
+	}
+	return iso20022.ReasonNarrative
+}
+
+// importTransferReason 和 transferError 对应，把入账失败的原因换成拒绝原因码
+func importTransferReason(err error) string {
+	switch {
+	case errors.Is(err, db.ErrAccountFrozen):
+		return iso20022.ReasonBlockedAccount
+	case errors.Is(err, db.ErrInsufficientFunds),
+		errors.Is(err, db.ErrMinimumBalance):
+		return iso20022.ReasonInsufficientFunds
+	case errors.Is(err, db.ErrTransferLimitExceeded):
+		return iso20022.ReasonNotAllowedAmount
+	}
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "check_violation" {
+		return iso20022.ReasonInsufficientFunds
+	}
+	return iso20022.ReasonNarrative
+}
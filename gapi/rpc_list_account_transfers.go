@@ -0,0 +1,74 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"simplebank/pb"
+	"simplebank/util"
+	"simplebank/val"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListAccountTransfers 账户流水，任何成员都能看，私人备注只在转出的那一笔上返回
+// 按收款人转出的那一笔和创建时一样不返回对方的账户 ID
+func (server *Server) ListAccountTransfers(ctx context.Context, req *pb.ListAccountTransfersRequest) (*pb.ListAccountTransfersResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListAccountTransfersRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	_, err = server.authorizeMember(ctx, req.GetAccountId(), authPayload.Username, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers, err := server.store.ListAccountTransfers(ctx, db.ListAccountTransfersParams{
+		AccountID: req.GetAccountId(),
+		Search:    req.GetSearch(),
+		Limit:     req.GetPageSize(),
+		Offset:    (req.GetPageId() - 1) * req.GetPageSize(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list transfers: %s", err)
+	}
+
+	rsp := &pb.ListAccountTransfersResponse{}
+	for _, transfer := range transfers {
+		if transfer.FromAccountID != req.GetAccountId() {
+			transfer.PrivateNote = ""
+			rsp.Transfers = append(rsp.Transfers, convertTransfer(transfer))
+			continue
+		}
+		rsp.Transfers = append(rsp.Transfers, convertSentTransfer(transfer))
+	}
+	return rsp, nil
+}
+
+func validateListAccountTransfersRequest(req *pb.ListAccountTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if err := val.ValidateString(req.GetSearch(), 0, 100); err != nil {
+		violations = append(violations, fieldViolation("search", err))
+	}
+
+	if req.GetPageId() < 1 {
+		violations = append(violations, fieldViolation("page_id", fmt.Errorf("must be at least 1")))
+	}
+
+	if req.GetPageSize() < 5 || req.GetPageSize() > 10 {
+		violations = append(violations, fieldViolation("page_size", fmt.Errorf("must be between 5 and 10")))
+	}
+
+	return violations
+}
@@ -0,0 +1,53 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"simplebank/pb"
+	"simplebank/util"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListAccounts 返回当前用户作为成员（含 co_owner / viewer）能看到的所有账户
+func (server *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListAccountsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	accounts, err := server.store.ListAccounts(ctx, db.ListAccountsParams{
+		Username: authPayload.Username,
+		Limit:    req.GetPageSize(),
+		Offset:   (req.GetPageId() - 1) * req.GetPageSize(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list accounts: %s", err)
+	}
+
+	rsp := &pb.ListAccountsResponse{}
+	for _, account := range accounts {
+		rsp.Accounts = append(rsp.Accounts, convertAccount(account))
+	}
+	return rsp, nil
+}
+
+func validateListAccountsRequest(req *pb.ListAccountsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetPageId() < 1 {
+		violations = append(violations, fieldViolation("page_id", fmt.Errorf("must be at least 1")))
+	}
+
+	if req.GetPageSize() < 5 || req.GetPageSize() > 10 {
+		violations = append(violations, fieldViolation("page_size", fmt.Errorf("must be between 5 and 10")))
+	}
+
+	return violations
+}
@@ -0,0 +1,234 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"simplebank/pb"
+	"simplebank/util"
+	"simplebank/val"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreatePayee 保存收款人前先核验账户：必须存在、不是银行内部账户、能够入账
+// 户名只告诉调用方是否一致，不返回账户上的真实户名
+func (server *Server) CreatePayee(ctx context.Context, req *pb.CreatePayeeRequest) (*pb.CreatePayeeResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreatePayeeRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccountByNumber(ctx, req.GetAccountNumber())
+	// 银行内部账户对外当作不存在
+	if err == sql.ErrNoRows || (err == nil && account.Owner == util.BankUsername) {
+		return nil, status.Errorf(codes.NotFound, "account not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if account.FreezeStatus == util.FreezeStatusFrozen {
+		return nil, failedPreconditionError(fmt.Errorf("account %s cannot receive transfers", req.GetAccountNumber()))
+	}
+
+	holder, err := server.store.GetUser(ctx, account.Owner)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get account holder: %s", err)
+	}
+
+	if !holderNameMatches(holder.FullName, req.GetHolderName()) {
+		return &pb.CreatePayeeResponse{HolderNameMatched: false}, nil
+	}
+
+	payee, err := server.store.CreatePayee(ctx, db.CreatePayeeParams{
+		Owner:      authPayload.Username,
+		AccountID:  account.ID,
+		Nickname:   req.GetNickname(),
+		HolderName: req.GetHolderName(),
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				return nil, status.Errorf(codes.AlreadyExists, "account %s is already saved as a payee", req.GetAccountNumber())
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create payee: %s", err)
+	}
+
+	rsp := &pb.CreatePayeeResponse{
+		Payee:             convertPayee(payee, server.config.PayeeCoolingOffPeriod),
+		HolderNameMatched: true,
+	}
+	return rsp, nil
+}
+
+// holderNameMatches 户名不区分大小写，多余的空白不影响比较
+func holderNameMatches(fullName string, holderName string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(fullName), " "), strings.Join(strings.Fields(holderName), " "))
+}
+
+func (server *Server) ListPayees(ctx context.Context, req *pb.ListPayeesRequest) (*pb.ListPayeesResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListPayeesRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	payees, err := server.store.ListPayees(ctx, db.ListPayeesParams{
+		Owner:  authPayload.Username,
+		Limit:  req.GetPageSize(),
+		Offset: (req.GetPageId() - 1) * req.GetPageSize(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list payees: %s", err)
+	}
+
+	rsp := &pb.ListPayeesResponse{}
+	for _, payee := range payees {
+		rsp.Payees = append(rsp.Payees, convertPayee(payee, server.config.PayeeCoolingOffPeriod))
+	}
+	return rsp, nil
+}
+
+// UpdatePayee 只允许改昵称，换账户要删掉重新添加并重新进入冷静期
+func (server *Server) UpdatePayee(ctx context.Context, req *pb.UpdatePayeeRequest) (*pb.UpdatePayeeResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateUpdatePayeeRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	_, err = server.ownPayee(ctx, req.GetPayeeId(), authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	payee, err := server.store.UpdatePayeeNickname(ctx, db.UpdatePayeeNicknameParams{
+		ID:       req.GetPayeeId(),
+		Nickname: req.GetNickname(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update payee: %s", err)
+	}
+
+	return &pb.UpdatePayeeResponse{Payee: convertPayee(payee, server.config.PayeeCoolingOffPeriod)}, nil
+}
+
+func (server *Server) DeletePayee(ctx context.Context, req *pb.DeletePayeeRequest) (*pb.DeletePayeeResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := val.ValidateID(req.GetPayeeId()); err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("payee_id", err)})
+	}
+
+	_, err = server.ownPayee(ctx, req.GetPayeeId(), authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.store.DeletePayee(ctx, req.GetPayeeId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete payee: %s", err)
+	}
+
+	return &pb.DeletePayeeResponse{}, nil
+}
+
+// ownPayee 别人的收款人和不存在一样返回 NotFound
+func (server *Server) ownPayee(ctx context.Context, payeeID int64, username string) (db.Payee, error) {
+	payee, err := server.store.GetPayee(ctx, payeeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return payee, status.Errorf(codes.NotFound, "payee [%d] not found", payeeID)
+		}
+		return payee, status.Errorf(codes.Internal, "failed to get payee: %s", err)
+	}
+
+	if payee.Owner != username {
+		return payee, status.Errorf(codes.NotFound, "payee [%d] not found", payeeID)
+	}
+
+	return payee, nil
+}
+
+// payeeAccount 把 payee_id 解析成收款账户，冷静期内单笔金额不能超过配置的上限
+func (server *Server) payeeAccount(ctx context.Context, payeeID int64, username string, amount int64) (int64, error) {
+	payee, err := server.ownPayee(ctx, payeeID, username)
+	if err != nil {
+		return 0, err
+	}
+
+	coolingOffUntil := payee.CreatedAt.Add(server.config.PayeeCoolingOffPeriod)
+	if time.Now().Before(coolingOffUntil) && amount > server.config.PayeeCoolingOffLimit {
+		return 0, failedPreconditionError(fmt.Errorf(
+			"payee is in cooling-off period until %s, amount must not exceed %d",
+			coolingOffUntil.Format(time.RFC3339), server.config.PayeeCoolingOffLimit,
+		))
+	}
+
+	return payee.AccountID, nil
+}
+
+func validateCreatePayeeRequest(req *pb.CreatePayeeRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateAccountNumber(req.GetAccountNumber()); err != nil {
+		violations = append(violations, fieldViolation("account_number", err))
+	}
+
+	if err := val.ValidateFullName(req.GetHolderName()); err != nil {
+		violations = append(violations, fieldViolation("holder_name", err))
+	}
+
+	if err := val.ValidateNickname(req.GetNickname()); err != nil {
+		violations = append(violations, fieldViolation("nickname", err))
+	}
+
+	return violations
+}
+
+func validateListPayeesRequest(req *pb.ListPayeesRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetPageId() < 1 {
+		violations = append(violations, fieldViolation("page_id", fmt.Errorf("must be at least 1")))
+	}
+
+	if req.GetPageSize() < 5 || req.GetPageSize() > 10 {
+		violations = append(violations, fieldViolation("page_size", fmt.Errorf("must be between 5 and 10")))
+	}
+
+	return violations
+}
+
+func validateUpdatePayeeRequest(req *pb.UpdatePayeeRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetPayeeId()); err != nil {
+		violations = append(violations, fieldViolation("payee_id", err))
+	}
+
+	if err := val.ValidateNickname(req.GetNickname()); err != nil {
+		violations = append(violations, fieldViolation("nickname", err))
+	}
+
+	return violations
+}
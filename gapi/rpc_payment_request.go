@@ -0,0 +1,306 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"simplebank/pb"
+	"simplebank/util"
+	"simplebank/val"
+	"simplebank/worker"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPaymentRequestTTL = 7 * 24 * time.Hour
+	maxPaymentRequestTTL     = 30 * 24 * time.Hour
+)
+
+func (server *Server) CreatePaymentRequest(ctx context.Context, req *pb.CreatePaymentRequestRequest) (*pb.CreatePaymentRequestResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreatePaymentRequestRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if req.GetPayer() == authPayload.Username {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			fieldViolation("payer", fmt.Errorf("cannot request money from yourself")),
+		})
+	}
+
+	toAccount, err := server.validAccountRef(ctx, req.GetToAccountId(), req.GetToAccountNumber(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = server.authorizeMember(ctx, toAccount.ID, authPayload.Username, util.CanTransfer)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(defaultPaymentRequestTTL)
+	if req.ExpiresAt != nil {
+		expiresAt = req.GetExpiresAt().AsTime()
+	}
+
+	result, err := server.store.CreatePaymentRequestTx(ctx, db.CreatePaymentRequestTxParams{
+		CreatePaymentRequestParams: db.CreatePaymentRequestParams{
+			Requester:   authPayload.Username,
+			Payer:       req.GetPayer(),
+			ToAccountID: toAccount.ID,
+			Amount:      req.GetAmount(),
+			Currency:    req.GetCurrency(),
+			Memo:        req.GetMemo(),
+			ExpiresAt:   expiresAt,
+		},
+		AfterCreate: func(q db.Querier, request db.PaymentRequest) error {
+			return notifyPaymentRequest(ctx, worker.NewOutboxTaskDistributor(q), request.ID, worker.PaymentRequestEventCreated)
+		},
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				return nil, status.Errorf(codes.NotFound, "payer %s not found", req.GetPayer())
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create payment request: %s", err)
+	}
+
+	return &pb.CreatePaymentRequestResponse{PaymentRequest: convertPaymentRequest(result.PaymentRequest)}, nil
+}
+
+func (server *Server) ListPaymentRequests(ctx context.Context, req *pb.ListPaymentRequestsRequest) (*pb.ListPaymentRequestsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListPaymentRequestsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	limit := req.GetPageSize()
+	offset := (req.GetPageId() - 1) * req.GetPageSize()
+
+	var requests []db.PaymentRequest
+	if req.GetOutgoing() {
+		requests, err = server.store.ListOutgoingPaymentRequests(ctx, db.ListOutgoingPaymentRequestsParams{
+			Requester: authPayload.Username,
+			Limit:     limit,
+			Offset:    offset,
+		})
+	} else {
+		requests, err = server.store.ListIncomingPaymentRequests(ctx, db.ListIncomingPaymentRequestsParams{
+			Payer:  authPayload.Username,
+			Limit:  limit,
+			Offset: offset,
+		})
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list payment requests: %s", err)
+	}
+
+	rsp := &pb.ListPaymentRequestsResponse{}
+	for _, request := range requests {
+		rsp.PaymentRequests = append(rsp.PaymentRequests, convertPaymentRequest(request))
+	}
+	return rsp, nil
+}
+
+// AcceptPaymentRequest 付款人选一个自己能转账的账户付款
+func (server *Server) AcceptPaymentRequest(ctx context.Context, req *pb.AcceptPaymentRequestRequest) (*pb.AcceptPaymentRequestResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateAcceptPaymentRequestRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	request, err := server.incomingPaymentRequest(ctx, req.GetPaymentRequestId(), authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	fromAccount, err := server.validAccountRef(ctx, req.GetFromAccountId(), req.GetFromAccountNumber(), request.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = server.authorizeMember(ctx, fromAccount.ID, authPayload.Username, util.CanTransfer)
+	if err != nil {
+		return nil, err
+	}
+
+	if server.requiresApproval(request.Amount) {
+		return server.payPaymentRequestPending(ctx, request, fromAccount.ID, authPayload.Username)
+	}
+
+	result, err := server.store.PayPaymentRequestTx(ctx, db.PayPaymentRequestTxParams{
+		PaymentRequestID: request.ID,
+		FromAccountID:    fromAccount.ID,
+		AfterPay: func(q db.Querier, result db.PayPaymentRequestTxResult) error {
+			distributor := worker.NewOutboxTaskDistributor(q)
+			err := notifyPaymentRequest(ctx, distributor, result.PaymentRequest.ID, worker.PaymentRequestEventPaid)
+			if err != nil {
+				return err
+			}
+			return publishTransferPosted(ctx, distributor, result.Transfer)
+		},
+	})
+	if err != nil {
+		return nil, transferError(err)
+	}
+
+	rsp := &pb.AcceptPaymentRequestResponse{
+		PaymentRequest: convertPaymentRequest(result.PaymentRequest),
+		Transfer:       convertTransfer(result.Transfer.Transfer),
+		FromAccount:    convertAccount(result.Transfer.FromAccount),
+	}
+	return rsp, nil
+}
+
+// payPaymentRequestPending 超过审批阈值的付款先占用资金，批准后请求才变为已付
+func (server *Server) payPaymentRequestPending(ctx context.Context, request db.PaymentRequest, fromAccountID int64, username string) (*pb.AcceptPaymentRequestResponse, error) {
+	result, err := server.store.PayPaymentRequestPendingTx(ctx, db.PayPaymentRequestPendingTxParams{
+		PaymentRequestID: request.ID,
+		FromAccountID:    fromAccountID,
+		RequestedBy:      username,
+		ExpiresAt:        time.Now().Add(server.config.TransferApprovalTimeout),
+		AfterPay: func(q db.Querier, result db.PayPaymentRequestPendingTxResult) error {
+			return publishTransferCreated(ctx, worker.NewOutboxTaskDistributor(q), result.Transfer.Transfer, result.Transfer.FromAccount)
+		},
+	})
+	if err != nil {
+		return nil, transferError(err)
+	}
+
+	rsp := &pb.AcceptPaymentRequestResponse{
+		PaymentRequest: convertPaymentRequest(result.PaymentRequest),
+		Transfer:       convertTransfer(result.Transfer.Transfer),
+		FromAccount:    convertAccount(result.Transfer.FromAccount),
+	}
+	return rsp, nil
+}
+
+func (server *Server) DeclinePaymentRequest(ctx context.Context, req *pb.DeclinePaymentRequestRequest) (*pb.DeclinePaymentRequestResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := val.ValidateID(req.GetPaymentRequestId()); err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("payment_request_id", err)})
+	}
+
+	_, err = server.incomingPaymentRequest(ctx, req.GetPaymentRequestId(), authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := server.store.DeclinePaymentRequestTx(ctx, db.DeclinePaymentRequestTxParams{
+		PaymentRequestID: req.GetPaymentRequestId(),
+		AfterDecline: func(q db.Querier, request db.PaymentRequest) error {
+			return notifyPaymentRequest(ctx, worker.NewOutboxTaskDistributor(q), request.ID, worker.PaymentRequestEventDeclined)
+		},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, failedPreconditionError(db.ErrPaymentRequestClosed)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to decline payment request: %s", err)
+	}
+
+	return &pb.DeclinePaymentRequestResponse{PaymentRequest: convertPaymentRequest(result.PaymentRequest)}, nil
+}
+
+// incomingPaymentRequest 只有付款人能处理请求，其他人看到的和不存在一样
+func (server *Server) incomingPaymentRequest(ctx context.Context, requestID int64, username string) (db.PaymentRequest, error) {
+	request, err := server.store.GetPaymentRequest(ctx, requestID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return request, status.Errorf(codes.NotFound, "payment request [%d] not found", requestID)
+		}
+		return request, status.Errorf(codes.Internal, "failed to get payment request: %s", err)
+	}
+
+	if request.Payer != username {
+		return request, status.Errorf(codes.NotFound, "payment request [%d] not found", requestID)
+	}
+
+	return request, nil
+}
+
+// notifyPaymentRequest 在改请求状态的事务钩子里调用，通知任务写进 outbox 和状态一起提交
+func notifyPaymentRequest(ctx context.Context, distributor worker.TaskDistributor, requestID int64, event string) error {
+	return worker.TaskSendPaymentRequestNotice.Distribute(ctx, distributor, &worker.PayloadSendPaymentRequestNotice{
+		PaymentRequestID: requestID,
+		Event:            event,
+	})
+}
+
+func validateCreatePaymentRequestRequest(req *pb.CreatePaymentRequestRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateUsername(req.GetPayer()); err != nil {
+		violations = append(violations, fieldViolation("payer", err))
+	}
+
+	violations = append(violations, validateAccountRef("to_account", req.GetToAccountId(), req.GetToAccountNumber())...)
+
+	if err := val.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	if err := val.ValidateMemo(req.GetMemo()); err != nil {
+		violations = append(violations, fieldViolation("memo", err))
+	}
+
+	if req.ExpiresAt != nil {
+		ttl := time.Until(req.GetExpiresAt().AsTime())
+		if ttl <= 0 || ttl > maxPaymentRequestTTL {
+			violations = append(violations, fieldViolation("expires_at", fmt.Errorf("must be in the future and within %s", maxPaymentRequestTTL)))
+		}
+	}
+
+	return violations
+}
+
+func validateListPaymentRequestsRequest(req *pb.ListPaymentRequestsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetPageId() < 1 {
+		violations = append(violations, fieldViolation("page_id", fmt.Errorf("must be at least 1")))
+	}
+
+	if req.GetPageSize() < 5 || req.GetPageSize() > 10 {
+		violations = append(violations, fieldViolation("page_size", fmt.Errorf("must be between 5 and 10")))
+	}
+
+	return violations
+}
+
+func validateAcceptPaymentRequestRequest(req *pb.AcceptPaymentRequestRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetPaymentRequestId()); err != nil {
+		violations = append(violations, fieldViolation("payment_request_id", err))
+	}
+
+	violations = append(violations, validateAccountRef("from_account", req.GetFromAccountId(), req.GetFromAccountNumber())...)
+
+	return violations
+}
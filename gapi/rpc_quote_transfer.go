@@ -0,0 +1,70 @@
+package gapi
+
+import (
+	"context"
+	db "simplebank/db/sqlc"
+	"simplebank/pb"
+	"simplebank/quote"
+	"simplebank/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// QuoteTransfer 按 CreateTransfer 的全部规则试算，不写库
+// 校验不通过时返回和 CreateTransfer 一样的错误，通过时返回报价 ID，期内按报价的手续费转账
+func (server *Server) QuoteTransfer(ctx context.Context, req *pb.QuoteTransferRequest) (*pb.QuoteTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateTransferParties(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	fromAccount, toAccount, err := server.transferAccounts(ctx, req, authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := server.store.CheckTransfer(ctx, db.TransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        req.GetAmount(),
+	})
+	if err != nil {
+		return nil, transferError(err)
+	}
+
+	quoteID, terms, err := server.quoteMaker.CreateQuote(quote.Terms{
+		Username:      authPayload.Username,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        req.GetAmount(),
+		Currency:      req.GetCurrency(),
+		Fee:           result.Fee,
+	}, server.config.QuoteDuration)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create quote: %s", err)
+	}
+
+	rsp := &pb.QuoteTransferResponse{
+		QuoteId:          quoteID,
+		Amount:           req.GetAmount(),
+		RecipientAmount:  req.GetAmount(),
+		Fee:              result.Fee,
+		TotalDebit:       req.GetAmount() + result.Fee,
+		Currency:         req.GetCurrency(),
+		TransferType:     db.TransferType(result.FromAccount, result.ToAccount),
+		BalanceAfter:     result.FromAccount.Balance - req.GetAmount() - result.Fee,
+		ExpiresAt:        timestamppb.New(terms.ExpiresAt),
+		RequiresApproval: server.requiresApproval(req.GetAmount()),
+	}
+	if result.RemainingMonthlyTransfers.Valid {
+		rsp.RemainingMonthlyTransfers = &result.RemainingMonthlyTransfers.Int64
+	}
+	return rsp, nil
+}
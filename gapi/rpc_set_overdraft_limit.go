@@ -0,0 +1,62 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"simplebank/pb"
+	"simplebank/util"
+
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) SetOverdraftLimit(ctx context.Context, req *pb.SetOverdraftLimitRequest) (*pb.SetOverdraftLimitResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateSetOverdraftLimitRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.lookupAccount(ctx, req.GetAccountId(), req.GetAccountNumber())
+	if err != nil {
+		return nil, err
+	}
+
+	account, err = server.store.UpdateAccountOverdraftLimit(ctx, db.UpdateAccountOverdraftLimitParams{
+		ID:             account.ID,
+		OverdraftLimit: req.GetOverdraftLimit(),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "check_violation":
+				// 新额度小于当前透支金额
+				return nil, failedPreconditionError(fmt.Errorf("account is overdrawn beyond the new limit"))
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "failed to set overdraft limit: %s", err)
+	}
+
+	return &pb.SetOverdraftLimitResponse{Account: convertAccount(account)}, nil
+}
+
+func validateSetOverdraftLimitRequest(req *pb.SetOverdraftLimitRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	violations = append(violations, validateAccountRef("account", req.GetAccountId(), req.GetAccountNumber())...)
+
+	if req.GetOverdraftLimit() < 0 {
+		violations = append(violations, fieldViolation("overdraft_limit", fmt.Errorf("must not be negative")))
+	}
+
+	return violations
+}
@@ -0,0 +1,180 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"simplebank/pb"
+	"simplebank/util"
+	"simplebank/val"
+	"simplebank/worker"
+
+	"github.com/hibiken/asynq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxTaskFailures 查看任务时带上的失败记录条数
+const maxTaskFailures = 20
+
+// ListFailedTasks 列出等待重试或重试用完被归档的任务，最近失败的在前
+func (server *Server) ListFailedTasks(ctx context.Context, req *pb.ListFailedTasksRequest) (*pb.ListFailedTasksResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListFailedTasksRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	state, _ := parseFailedTaskState(req.GetState())
+	tasks, err := server.taskInspector.ListFailedTasks(ctx, worker.FailedTaskFilter{
+		State:    state,
+		Queue:    req.GetQueue(),
+		Type:     req.GetType(),
+		PageID:   int(req.GetPageId()),
+		PageSize: int(req.GetPageSize()),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list failed tasks: %s", err)
+	}
+
+	rsp := &pb.ListFailedTasksResponse{
+		Tasks: make([]*pb.Task, 0, len(tasks)),
+	}
+	for _, task := range tasks {
+		rsp.Tasks = append(rsp.Tasks, convertTask(task))
+	}
+	return rsp, nil
+}
+
+// GetFailedTask 任务的 payload、最后的错误和失败记录，还没失败的任务也能查
+func (server *Server) GetFailedTask(ctx context.Context, req *pb.GetFailedTaskRequest) (*pb.GetFailedTaskResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateTaskID(req.GetTaskId())
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	task, err := server.taskInspector.GetTaskInfo(ctx, req.GetQueue(), req.GetTaskId())
+	if err != nil {
+		return nil, taskAdminError(err, req.GetTaskId())
+	}
+
+	failures, err := server.store.ListTaskFailures(ctx, db.ListTaskFailuresParams{
+		TaskID: task.ID,
+		Limit:  maxTaskFailures,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list task failures: %s", err)
+	}
+
+	rsp := &pb.GetFailedTaskResponse{
+		Task:     convertTask(task),
+		Failures: make([]*pb.TaskFailure, 0, len(failures)),
+	}
+	for _, failure := range failures {
+		rsp.Failures = append(rsp.Failures, convertTaskFailure(failure))
+	}
+	return rsp, nil
+}
+
+// RetryFailedTask 马上再处理一次，重试次数不清零，再失败时直接归档
+func (server *Server) RetryFailedTask(ctx context.Context, req *pb.RetryFailedTaskRequest) (*pb.RetryFailedTaskResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateTaskID(req.GetTaskId())
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	err = server.taskInspector.RunTask(ctx, req.GetQueue(), req.GetTaskId())
+	if err != nil {
+		return nil, taskAdminError(err, req.GetTaskId())
+	}
+
+	task, err := server.taskInspector.GetTaskInfo(ctx, req.GetQueue(), req.GetTaskId())
+	if err != nil {
+		// 任务可能已经处理完被清掉了
+		return &pb.RetryFailedTaskResponse{}, nil
+	}
+
+	rsp := &pb.RetryFailedTaskResponse{
+		Task: convertTask(task),
+	}
+	return rsp, nil
+}
+
+// DeleteFailedTask 删掉不需要再处理的失败任务，失败记录保留
+func (server *Server) DeleteFailedTask(ctx context.Context, req *pb.DeleteFailedTaskRequest) (*pb.DeleteFailedTaskResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.AdminRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateTaskID(req.GetTaskId())
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	err = server.taskInspector.DeleteTask(ctx, req.GetQueue(), req.GetTaskId())
+	if err != nil {
+		return nil, taskAdminError(err, req.GetTaskId())
+	}
+
+	return &pb.DeleteFailedTaskResponse{}, nil
+}
+
+func taskAdminError(err error, taskID string) error {
+	switch {
+	case errors.Is(err, asynq.ErrTaskNotFound):
+		return status.Errorf(codes.NotFound, "task %s not found", taskID)
+	case errors.Is(err, worker.ErrTaskNotFailed):
+		return failedPreconditionError(err)
+	}
+	return status.Errorf(codes.Internal, "failed to inspect task: %s", err)
+}
+
+func parseFailedTaskState(value string) (asynq.TaskState, error) {
+	switch value {
+	case asynq.TaskStateRetry.String():
+		return asynq.TaskStateRetry, nil
+	case asynq.TaskStateArchived.String():
+		return asynq.TaskStateArchived, nil
+	}
+	return 0, fmt.Errorf("must be %s or %s", asynq.TaskStateRetry, asynq.TaskStateArchived)
+}
+
+func validateListFailedTasksRequest(req *pb.ListFailedTasksRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if _, err := parseFailedTaskState(req.GetState()); err != nil {
+		violations = append(violations, fieldViolation("state", err))
+	}
+
+	if req.GetPageId() < 1 {
+		violations = append(violations, fieldViolation("page_id", fmt.Errorf("must be at least 1")))
+	}
+
+	if req.GetPageSize() < 5 || req.GetPageSize() > 10 {
+		violations = append(violations, fieldViolation("page_size", fmt.Errorf("must be between 5 and 10")))
+	}
+
+	return violations
+}
+
+func validateTaskID(taskID string) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateString(taskID, 1, 100); err != nil {
+		violations = append(violations, fieldViolation("task_id", err))
+	}
+	return violations
+}
@@ -0,0 +1,203 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"simplebank/pb"
+	"simplebank/token"
+	"simplebank/util"
+	"simplebank/val"
+	"simplebank/worker"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// requiresApproval 超过阈值的转账走四眼审批，阈值为 0 时关闭
+func (server *Server) requiresApproval(amount int64) bool {
+	return server.config.TransferApprovalThreshold > 0 && amount > server.config.TransferApprovalThreshold
+}
+
+// createPendingTransfer 建待审批的转账并占用资金，由另一个有权限的人批准后才入账
+func (server *Server) createPendingTransfer(ctx context.Context, arg db.TransferTxParams, username string) (*pb.CreateTransferResponse, error) {
+	result, err := server.store.CreatePendingTransferTx(ctx, db.CreatePendingTransferTxParams{
+		TransferTxParams: arg,
+		RequestedBy:      username,
+		ExpiresAt:        time.Now().Add(server.config.TransferApprovalTimeout),
+		AfterCreate: func(q db.Querier, result db.CreatePendingTransferTxResult) error {
+			return publishTransferCreated(ctx, worker.NewOutboxTaskDistributor(q), result.Transfer, result.FromAccount)
+		},
+	})
+	if err != nil {
+		return nil, transferError(err)
+	}
+
+	rsp := &pb.CreateTransferResponse{
+		Transfer:    convertSentTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+	}
+	return rsp, nil
+}
+
+func (server *Server) ApproveTransfer(ctx context.Context, req *pb.ApproveTransferRequest) (*pb.ApproveTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := val.ValidateID(req.GetTransferId()); err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("transfer_id", err)})
+	}
+
+	// 申请人不能批准自己的转账
+	err = server.authorizeApprover(ctx, authPayload, req.GetTransferId(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := server.store.ApproveTransferTx(ctx, db.ApproveTransferTxParams{
+		TransferID: req.GetTransferId(),
+		ApprovedBy: authPayload.Username,
+		// 他行转账批准后和 CreateExternalTransfer 一样在事务里写提交任务，通知和 webhook 事件也一起写进 outbox
+		AfterApprove: func(q db.Querier, result db.ApproveTransferTxResult) error {
+			distributor := worker.NewOutboxTaskDistributor(q)
+			if result.PaymentRequest.ID != 0 {
+				err := notifyPaymentRequest(ctx, distributor, result.PaymentRequest.ID, worker.PaymentRequestEventPaid)
+				if err != nil {
+					return err
+				}
+			}
+
+			err := notifyOverdraft(ctx, distributor, result.FromAccount, result.Transfer.Amount+result.Transfer.Fee)
+			if err != nil {
+				return err
+			}
+
+			// transfer.created 在提交审批时已经发过，他行转账转入的是清算账户，不发 account.credited
+			if result.ExternalPayment.TransferID != 0 {
+				payload := &worker.PayloadSubmitExternalTransfer{TransferID: result.Transfer.ID}
+				return worker.TaskSubmitExternalTransfer.Distribute(ctx, distributor, payload)
+			}
+			return publishAccountCredited(ctx, distributor, result.Transfer, result.ToAccount)
+		},
+	})
+	if err != nil {
+		return nil, transferError(err)
+	}
+
+	rsp := &pb.ApproveTransferResponse{
+		Transfer:    convertSentTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+	}
+	return rsp, nil
+}
+
+func (server *Server) RejectTransfer(ctx context.Context, req *pb.RejectTransferRequest) (*pb.RejectTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := val.ValidateID(req.GetTransferId()); err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("transfer_id", err)})
+	}
+
+	// 申请人可以自己撤回
+	err = server.authorizeApprover(ctx, authPayload, req.GetTransferId(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := server.store.RejectTransferTx(ctx, db.RejectTransferTxParams{
+		TransferID: req.GetTransferId(),
+		RejectedBy: authPayload.Username,
+	})
+	if err != nil {
+		return nil, transferError(err)
+	}
+
+	rsp := &pb.RejectTransferResponse{
+		Transfer: convertSentTransfer(result.Transfer),
+	}
+	return rsp, nil
+}
+
+// ListPendingTransfers 银行职员看全部，其他用户看自己有转账权限的账户上的待审批转账
+func (server *Server) ListPendingTransfers(ctx context.Context, req *pb.ListPendingTransfersRequest) (*pb.ListPendingTransfersResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListPendingTransfersRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	rows, err := server.store.ListPendingTransferApprovals(ctx, db.ListPendingTransferApprovalsParams{
+		AllAccounts: authPayload.Role == util.BankerRole,
+		Username:    authPayload.Username,
+		Limit:       req.GetPageSize(),
+		Offset:      (req.GetPageId() - 1) * req.GetPageSize(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list pending transfers: %s", err)
+	}
+
+	rsp := &pb.ListPendingTransfersResponse{}
+	for _, row := range rows {
+		rsp.Transfers = append(rsp.Transfers, &pb.PendingTransfer{
+			Transfer:    convertSentTransfer(row.Transfer),
+			RequestedBy: row.TransferApproval.RequestedBy,
+			ExpiresAt:   timestamppb.New(row.TransferApproval.ExpiresAt),
+		})
+	}
+	return rsp, nil
+}
+
+// authorizeApprover 银行职员可以审批任何转账，其他人必须是转出账户上有转账权限的成员
+func (server *Server) authorizeApprover(ctx context.Context, authPayload *token.Payload, transferID int64, allowRequester bool) error {
+	transfer, err := server.store.GetTransfer(ctx, transferID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return status.Errorf(codes.NotFound, "transfer [%d] not found", transferID)
+		}
+		return status.Errorf(codes.Internal, "failed to get transfer: %s", err)
+	}
+
+	approval, err := server.store.GetTransferApproval(ctx, transferID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return failedPreconditionError(fmt.Errorf("transfer [%d] does not need approval", transferID))
+		}
+		return status.Errorf(codes.Internal, "failed to get transfer approval: %s", err)
+	}
+
+	if !allowRequester && approval.RequestedBy == authPayload.Username {
+		return status.Errorf(codes.PermissionDenied, "transfer must be approved by someone other than the requester")
+	}
+
+	if authPayload.Role == util.BankerRole {
+		return nil
+	}
+
+	_, err = server.authorizeMember(ctx, transfer.FromAccountID, authPayload.Username, util.CanTransfer)
+	return err
+}
+
+func validateListPendingTransfersRequest(req *pb.ListPendingTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetPageId() < 1 {
+		violations = append(violations, fieldViolation("page_id", fmt.Errorf("must be at least 1")))
+	}
+
+	if req.GetPageSize() < 5 || req.GetPageSize() > 10 {
+		violations = append(violations, fieldViolation("page_size", fmt.Errorf("must be between 5 and 10")))
+	}
+
+	return violations
+}
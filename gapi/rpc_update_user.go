@@ -22,7 +22,7 @@ func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest)
 		return nil, invalidArgumentError(violations)
 	}
 
-	authPayload, err := server.authorizeUser(ctx)
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole, util.AdminRole})
 	if err != nil {
 		return nil, unauthenticatedError(err)
 	}
@@ -54,24 +54,24 @@ func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest)
 		arg.FullName = sql.NullString{String: req.GetFullName(), Valid: true}
 	}
 
-	user, err := server.store.UpdateUser(ctx, arg)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to update user")
+	txArg := db.UpdateUserTxParams{
+		UpdateUserParams: arg,
 	}
-
-	opts := []asynq.Option{
-		asynq.MaxRetry(10),
-		asynq.ProcessIn(10 * time.Second),
-		asynq.Queue("critical"),
+	// 改了邮箱要重新验证，验证邮件任务和新邮箱一起提交
+	if req.Email != nil {
+		txArg.AfterUpdate = func(q db.Querier, user db.User) error {
+			payload := &worker.PayloadSendVerifyEmail{Username: user.Username}
+			distributor := worker.NewOutboxTaskDistributor(q)
+			return worker.TaskSendVerifyEmail.Distribute(ctx, distributor, payload, asynq.ProcessIn(10*time.Second))
+		}
 	}
 
-	if req.Email != nil {
-		_ = server.taskDistributor.DistributeTaskSendVerifyEmail(ctx, &worker.PayloadSendVerifyEmail{
-			Username: user.Username,
-		}, opts...)
+	result, err := server.store.UpdateUserTx(ctx, txArg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update user")
 	}
 
-	return &pb.UpdateUserResponse{User: convertUser(user)}, nil
+	return &pb.UpdateUserResponse{User: convertUser(result.User)}, nil
 
 }
 
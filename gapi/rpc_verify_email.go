@@ -4,6 +4,8 @@ import (
 	"context"
 	db "simplebank/db/sqlc"
 	"simplebank/pb"
+	"simplebank/webhook"
+	"simplebank/worker"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -14,6 +16,12 @@ func (server *Server) VerifyEmail(ctx context.Context, req *pb.VerifyEmailReques
 	_, err := server.store.VerifyEmailTx(ctx, db.VerifyEmailTxParams{
 		EmailId:    req.GetEmailId(),
 		SecretCode: req.GetSecretCode(),
+		AfterVerify: func(q db.Querier, user db.User) error {
+			return publishWebhookEvent(ctx, worker.NewOutboxTaskDistributor(q), user.Username, webhook.EventUserEmailVerified, webhook.EmailVerifiedData{
+				Username: user.Username,
+				Email:    user.Email,
+			})
+		},
 	})
 
 	if err != nil {
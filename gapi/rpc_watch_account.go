@@ -0,0 +1,151 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"simplebank/pb"
+	"simplebank/util"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// activityBatchSize 落后很多的连接分批追上
+	activityBatchSize = 100
+	// activityRecheckInterval 没收到通知也定时补查一次，同时检查令牌和成员权限是否还有效
+	activityRecheckInterval = 30 * time.Second
+)
+
+// WatchAccount 推送账户的新分录和入账后的余额，直到客户端断开
+// 游标是分录 ID：同一个账户的分录都是锁住账户之后写的，ID 的顺序和提交顺序一致
+func (server *Server) WatchAccount(req *pb.WatchAccountRequest, stream pb.SimpleBank_WatchAccountServer) error {
+	return server.watchAccount(stream.Context(), req, stream.Send)
+}
+
+// watchAccount gRPC 和 SSE 共用，send 出错时结束
+func (server *Server) watchAccount(ctx context.Context, req *pb.WatchAccountRequest, send func(*pb.WatchAccountResponse) error) error {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return unauthenticatedError(err)
+	}
+
+	violations := validateWatchAccountRequest(req)
+	if violations != nil {
+		return invalidArgumentError(violations)
+	}
+
+	account, err := server.lookupAccount(ctx, req.GetAccountId(), req.GetAccountNumber())
+	if err != nil {
+		return err
+	}
+
+	_, err = server.authorizeMember(ctx, account.ID, authPayload.Username, nil)
+	if err != nil {
+		return err
+	}
+
+	// 先订阅再查，查完到开始等待之间提交的分录不会漏
+	sub := server.activityHub.Subscribe(account.ID)
+	defer sub.Close()
+
+	cursor := req.GetAfterEntryId()
+	if cursor == 0 {
+		snapshot, err := server.store.GetAccountActivityCursor(ctx, account.ID)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to get account activity cursor: %s", err)
+		}
+		err = send(&pb.WatchAccountResponse{
+			AccountId: account.ID,
+			Balance:   snapshot.Balance,
+			Currency:  account.Currency,
+			Cursor:    snapshot.LastEntryID,
+		})
+		if err != nil {
+			return err
+		}
+		cursor = snapshot.LastEntryID
+	}
+
+	ticker := time.NewTicker(activityRecheckInterval)
+	defer ticker.Stop()
+
+	for {
+		cursor, err = server.sendAccountActivity(ctx, account, cursor, send)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-server.activityHub.Done():
+			// 客户端带着游标重连到其他实例
+			return status.Errorf(codes.Unavailable, "server is shutting down")
+		case <-sub.C():
+		case <-ticker.C:
+			if authPayload.ExpiresAt != nil && time.Now().After(authPayload.ExpiresAt.Time) {
+				return unauthenticatedError(fmt.Errorf("access token has expired"))
+			}
+			_, err = server.authorizeMember(ctx, account.ID, authPayload.Username, nil)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendAccountActivity 推送游标之后的全部分录，返回新的游标
+func (server *Server) sendAccountActivity(ctx context.Context, account db.Account, cursor int64, send func(*pb.WatchAccountResponse) error) (int64, error) {
+	for {
+		entries, err := server.store.ListAccountActivity(ctx, db.ListAccountActivityParams{
+			AccountID:    account.ID,
+			AfterEntryID: cursor,
+			LimitCount:   activityBatchSize,
+		})
+		if err != nil {
+			return cursor, status.Errorf(codes.Internal, "failed to list account activity: %s", err)
+		}
+
+		for _, entry := range entries {
+			err = send(&pb.WatchAccountResponse{
+				AccountId: account.ID,
+				Balance:   entry.Balance,
+				Currency:  account.Currency,
+				Entry: &pb.Entry{
+					Id:         entry.ID,
+					AccountId:  entry.AccountID,
+					Amount:     entry.Amount,
+					TransferId: entry.TransferID.Int64,
+					CreatedAt:  timestamppb.New(entry.CreatedAt),
+				},
+				Cursor: entry.ID,
+			})
+			if err != nil {
+				return cursor, err
+			}
+			cursor = entry.ID
+		}
+
+		if len(entries) < activityBatchSize {
+			return cursor, nil
+		}
+	}
+}
+
+func validateWatchAccountRequest(req *pb.WatchAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	violations = append(violations, validateAccountRef("account", req.GetAccountId(), req.GetAccountNumber())...)
+
+	if req.GetAfterEntryId() < 0 {
+		violations = append(violations, fieldViolation("after_entry_id", fmt.Errorf("must not be negative")))
+	}
+
+	return violations
+}
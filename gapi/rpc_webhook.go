@@ -0,0 +1,234 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"simplebank/pb"
+	"simplebank/util"
+	"simplebank/val"
+	"simplebank/webhook"
+	"simplebank/worker"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxWebhookSubscriptions 每个用户最多的订阅数
+const maxWebhookSubscriptions = 10
+
+// CreateWebhookSubscription 签名密钥只在这里返回一次，丢了只能删掉重建
+func (server *Server) CreateWebhookSubscription(ctx context.Context, req *pb.CreateWebhookSubscriptionRequest) (*pb.CreateWebhookSubscriptionResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreateWebhookSubscriptionRequest(req, server.config.Environment == "production")
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// 不能让订阅指向内网，投递时连接前还会再查一次
+	if err := webhook.CheckURL(ctx, req.GetUrl()); err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("url", err)})
+	}
+
+	subscriptions, err := server.store.ListWebhookSubscriptions(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list webhook subscriptions: %s", err)
+	}
+	if len(subscriptions) >= maxWebhookSubscriptions {
+		return nil, failedPreconditionError(fmt.Errorf("at most %d webhook subscriptions are allowed", maxWebhookSubscriptions))
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate webhook secret: %s", err)
+	}
+
+	subscription, err := server.store.CreateWebhookSubscription(ctx, db.CreateWebhookSubscriptionParams{
+		Owner:      authPayload.Username,
+		Url:        req.GetUrl(),
+		EventTypes: req.GetEventTypes(),
+		Secret:     secret,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create webhook subscription: %s", err)
+	}
+
+	rsp := &pb.CreateWebhookSubscriptionResponse{
+		Subscription: convertWebhookSubscription(subscription),
+		Secret:       secret,
+	}
+	return rsp, nil
+}
+
+func (server *Server) ListWebhookSubscriptions(ctx context.Context, req *pb.ListWebhookSubscriptionsRequest) (*pb.ListWebhookSubscriptionsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	subscriptions, err := server.store.ListWebhookSubscriptions(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list webhook subscriptions: %s", err)
+	}
+
+	rsp := &pb.ListWebhookSubscriptionsResponse{
+		Subscriptions: make([]*pb.WebhookSubscription, 0, len(subscriptions)),
+	}
+	for _, subscription := range subscriptions {
+		rsp.Subscriptions = append(rsp.Subscriptions, convertWebhookSubscription(subscription))
+	}
+	return rsp, nil
+}
+
+// DeleteWebhookSubscription 投递记录一起删除，还没投出去的不再投
+func (server *Server) DeleteWebhookSubscription(ctx context.Context, req *pb.DeleteWebhookSubscriptionRequest) (*pb.DeleteWebhookSubscriptionResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := val.ValidateID(req.GetSubscriptionId()); err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("subscription_id", err)})
+	}
+
+	_, err = server.ownWebhookSubscription(ctx, req.GetSubscriptionId(), authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.store.DeleteWebhookSubscription(ctx, req.GetSubscriptionId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete webhook subscription: %s", err)
+	}
+
+	return &pb.DeleteWebhookSubscriptionResponse{}, nil
+}
+
+// ListWebhookDeliveries 投递记录按时间倒序
+func (server *Server) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListWebhookDeliveriesRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	_, err = server.ownWebhookSubscription(ctx, req.GetSubscriptionId(), authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		SubscriptionID: req.GetSubscriptionId(),
+		Limit:          req.GetPageSize(),
+		Offset:         (req.GetPageId() - 1) * req.GetPageSize(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list webhook deliveries: %s", err)
+	}
+
+	rsp := &pb.ListWebhookDeliveriesResponse{
+		Deliveries: make([]*pb.WebhookDelivery, 0, len(deliveries)),
+	}
+	for _, delivery := range deliveries {
+		rsp.Deliveries = append(rsp.Deliveries, convertWebhookDelivery(delivery))
+	}
+	return rsp, nil
+}
+
+// RedeliverWebhook 手动重投一条投递记录，事件 ID 不变，重试次数重新计算
+func (server *Server) RedeliverWebhook(ctx context.Context, req *pb.RedeliverWebhookRequest) (*pb.RedeliverWebhookResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	if err := val.ValidateID(req.GetDeliveryId()); err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("delivery_id", err)})
+	}
+
+	delivery, err := server.store.GetWebhookDelivery(ctx, req.GetDeliveryId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "webhook delivery [%d] not found", req.GetDeliveryId())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get webhook delivery: %s", err)
+	}
+
+	_, err = server.ownWebhookSubscription(ctx, delivery.SubscriptionID, authPayload.Username)
+	if err != nil {
+		// 别人的投递记录和不存在一样
+		if status.Code(err) == codes.NotFound {
+			return nil, status.Errorf(codes.NotFound, "webhook delivery [%d] not found", req.GetDeliveryId())
+		}
+		return nil, err
+	}
+
+	result, err := server.store.RedeliverWebhookTx(ctx, db.RedeliverWebhookTxParams{
+		DeliveryID: delivery.ID,
+		AfterReset: func(q db.Querier, delivery db.WebhookDelivery) error {
+			return worker.TaskDeliverWebhook.Distribute(ctx, worker.NewOutboxTaskDistributor(q), &worker.PayloadDeliverWebhook{
+				DeliveryID: delivery.ID,
+			})
+		},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to redeliver webhook: %s", err)
+	}
+
+	return &pb.RedeliverWebhookResponse{Delivery: convertWebhookDelivery(result.Delivery)}, nil
+}
+
+// ownWebhookSubscription 别人的订阅和不存在一样返回 NotFound
+func (server *Server) ownWebhookSubscription(ctx context.Context, subscriptionID int64, username string) (db.WebhookSubscription, error) {
+	subscription, err := server.store.GetWebhookSubscription(ctx, subscriptionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return subscription, status.Errorf(codes.NotFound, "webhook subscription [%d] not found", subscriptionID)
+		}
+		return subscription, status.Errorf(codes.Internal, "failed to get webhook subscription: %s", err)
+	}
+
+	if subscription.Owner != username {
+		return subscription, status.Errorf(codes.NotFound, "webhook subscription [%d] not found", subscriptionID)
+	}
+
+	return subscription, nil
+}
+
+func validateCreateWebhookSubscriptionRequest(req *pb.CreateWebhookSubscriptionRequest, requireHTTPS bool) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateWebhookURL(req.GetUrl(), requireHTTPS); err != nil {
+		violations = append(violations, fieldViolation("url", err))
+	}
+
+	if err := val.ValidateWebhookEventTypes(req.GetEventTypes()); err != nil {
+		violations = append(violations, fieldViolation("event_types", err))
+	}
+
+	return violations
+}
+
+func validateListWebhookDeliveriesRequest(req *pb.ListWebhookDeliveriesRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetSubscriptionId()); err != nil {
+		violations = append(violations, fieldViolation("subscription_id", err))
+	}
+
+	if req.GetPageId() < 1 {
+		violations = append(violations, fieldViolation("page_id", fmt.Errorf("must be at least 1")))
+	}
+
+	if req.GetPageSize() < 5 || req.GetPageSize() > 10 {
+		violations = append(violations, fieldViolation("page_size", fmt.Errorf("must be between 5 and 10")))
+	}
+
+	return violations
+}
@@ -1,8 +1,11 @@
 package gapi
 
 import (
+	"simplebank/activity"
 	db "simplebank/db/sqlc"
+	"simplebank/nacha"
 	"simplebank/pb"
+	"simplebank/quote"
 	"simplebank/token"
 	"simplebank/util"
 	"simplebank/worker"
@@ -13,20 +16,33 @@ type Server struct {
 	config          util.Config
 	store           db.Store
 	tokenMaker      token.Maker
+	quoteMaker      *quote.Maker
+	achService      *nacha.Service
 	taskDistributor worker.TaskDistributor
+	taskInspector   worker.TaskInspector
+	activityHub     *activity.Hub
 }
 
-func NewServer(config util.Config, store db.Store, taskDistributor worker.TaskDistributor) (*Server, error) {
+func NewServer(config util.Config, store db.Store, taskDistributor worker.TaskDistributor, taskInspector worker.TaskInspector, activityHub *activity.Hub) (*Server, error) {
 	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
 	if err != nil {
 		return nil, err
 	}
 
+	quoteMaker, err := quote.NewMaker(config.TokenSymmetricKey)
+	if err != nil {
+		return nil, err
+	}
+
 	server := &Server{
 		config:          config,
 		store:           store,
 		tokenMaker:      tokenMaker,
+		quoteMaker:      quoteMaker,
+		achService:      nacha.NewService(store, nacha.OriginFromConfig(config)),
 		taskDistributor: taskDistributor,
+		taskInspector:   taskInspector,
+		activityHub:     activityHub,
 	}
 
 	return server, nil
@@ -0,0 +1,195 @@
+package gapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"simplebank/pb"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// sseHeartbeatInterval 没有新分录时定时发注释行，防止代理因为空闲断开连接
+const sseHeartbeatInterval = 15 * time.Second
+
+// WatchAccountSSE 是 WatchAccount 在 HTTP 网关上的 Server-Sent Events 版本
+//
+//	GET /v1/watch_account?account_id=1&after_entry_id=0
+//	GET /v1/watch_account?account_number=SB55SMPL0123456789
+//
+// 每条消息的 id 是游标，浏览器重连时会带上 Last-Event-ID，优先于 after_entry_id
+// 令牌和其他接口一样放在 Authorization 头里，不接受放在 URL 里，避免写进访问日志
+func (server *Server) WatchAccountSSE(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeSSEError(w, status.Error(codes.Unimplemented, "method not allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	req, err := parseWatchAccountSSERequest(r)
+	if err != nil {
+		writeSSEError(w, status.Error(codes.InvalidArgument, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	md := metadata.Pairs(
+		authorizationHeader, r.Header.Get("Authorization"),
+		grpcGatewayUserAgent, r.UserAgent(),
+	)
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+
+	stream := &sseStream{
+		w:          w,
+		controller: http.NewResponseController(w),
+	}
+	stopHeartbeat := stream.startHeartbeat(ctx)
+	defer stopHeartbeat()
+
+	err = server.watchAccount(ctx, req, stream.send)
+	if err == nil {
+		return
+	}
+
+	// 已经开始推送时状态码发不出去了，用 error 事件告诉客户端
+	if !stream.writeError(err) {
+		writeSSEError(w, err, runtime.HTTPStatusFromCode(status.Code(err)))
+	}
+}
+
+func parseWatchAccountSSERequest(r *http.Request) (*pb.WatchAccountRequest, error) {
+	query := r.URL.Query()
+	req := &pb.WatchAccountRequest{}
+	if query.Has("account_number") {
+		accountNumber := query.Get("account_number")
+		req.AccountNumber = &accountNumber
+	}
+	if query.Has("account_id") || req.AccountNumber == nil {
+		accountID, err := strconv.ParseInt(query.Get("account_id"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid account_id")
+		}
+		req.AccountId = accountID
+	}
+
+	cursor := r.Header.Get("Last-Event-ID")
+	if cursor == "" {
+		cursor = query.Get("after_entry_id")
+	}
+	if cursor != "" {
+		afterEntryID, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid after_entry_id")
+		}
+		req.AfterEntryId = afterEntryID
+	}
+
+	return req, nil
+}
+
+// sseStream 推送和心跳在不同的 goroutine 里写同一个连接，需要加锁
+type sseStream struct {
+	mu         sync.Mutex
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	started    bool
+}
+
+func (stream *sseStream) send(rsp *pb.WatchAccountResponse) error {
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(rsp)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal account activity: %s", err)
+	}
+
+	return stream.write(fmt.Sprintf("id: %d\nevent: activity\ndata: %s\n\n", rsp.GetCursor(), data))
+}
+
+// writeError 还没开始推送时返回 false，由调用方返回普通的 HTTP 错误
+func (stream *sseStream) writeError(err error) bool {
+	stream.mu.Lock()
+	started := stream.started
+	stream.mu.Unlock()
+	if !started {
+		return false
+	}
+
+	data, _ := json.Marshal(sseErrorBody(err))
+	if err := stream.write(fmt.Sprintf("event: error\ndata: %s\n\n", data)); err != nil {
+		slog.Error("failed to write SSE error", slog.String("error", err.Error()))
+	}
+	return true
+}
+
+func (stream *sseStream) write(message string) error {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	if !stream.started {
+		header := stream.w.Header()
+		header.Set("Content-Type", "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+		header.Set("Connection", "keep-alive")
+		// 关掉 nginx 的响应缓冲
+		header.Set("X-Accel-Buffering", "no")
+		stream.w.WriteHeader(http.StatusOK)
+		stream.started = true
+	}
+
+	if _, err := stream.w.Write([]byte(message)); err != nil {
+		return err
+	}
+	return stream.controller.Flush()
+}
+
+func (stream *sseStream) startHeartbeat(ctx context.Context) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(sseHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				stream.mu.Lock()
+				started := stream.started
+				stream.mu.Unlock()
+				if started {
+					stream.write(": keepalive\n\n")
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+func sseErrorBody(err error) map[string]any {
+	st := status.Convert(err)
+	return map[string]any{
+		"code":    st.Code(),
+		"message": st.Message(),
+	}
+}
+
+func writeSSEError(w http.ResponseWriter, err error, httpStatus int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	json.NewEncoder(w).Encode(sseErrorBody(err))
+}
@@ -0,0 +1,64 @@
+package gapi
+
+import (
+	"context"
+	db "simplebank/db/sqlc"
+	"simplebank/webhook"
+	"simplebank/worker"
+)
+
+// publishWebhookEvent 在业务事务的钩子里调用，事件写进 outbox 和业务数据一起提交
+// 没有订阅的用户也会走一次 worker，由 worker 查订阅
+func publishWebhookEvent(ctx context.Context, distributor worker.TaskDistributor, username string, eventType string, data any) error {
+	event, err := webhook.NewEvent(eventType, data)
+	if err != nil {
+		return err
+	}
+
+	return worker.TaskPublishWebhookEvent.Distribute(ctx, distributor, &worker.PayloadPublishWebhookEvent{
+		Username: username,
+		Event:    event,
+	})
+}
+
+// publishTransferCreated 发给转出账户的户主，不带转入账户 ID，按用户名或邮箱转账时转出方不该看到
+func publishTransferCreated(ctx context.Context, distributor worker.TaskDistributor, transfer db.Transfer, fromAccount db.Account) error {
+	return publishWebhookEvent(ctx, distributor, fromAccount.Owner, webhook.EventTransferCreated, webhook.TransferData{
+		TransferID:        transfer.ID,
+		FromAccountID:     transfer.FromAccountID,
+		Amount:            transfer.Amount,
+		Fee:               transfer.Fee,
+		Currency:          fromAccount.Currency,
+		Status:            transfer.Status,
+		Memo:              transfer.Memo,
+		ExternalReference: transfer.ExternalReference,
+	})
+}
+
+// publishAccountCredited 钱到账时发给转入账户的户主，余额是入账后的
+func publishAccountCredited(ctx context.Context, distributor worker.TaskDistributor, transfer db.Transfer, toAccount db.Account) error {
+	return publishWebhookEvent(ctx, distributor, toAccount.Owner, webhook.EventAccountCredited, webhook.AccountCreditedData{
+		AccountID:     toAccount.ID,
+		AccountNumber: toAccount.AccountNumber,
+		TransferID:    transfer.ID,
+		Amount:        transfer.Amount,
+		Currency:      toAccount.Currency,
+		Balance:       toAccount.Balance,
+		Memo:          transfer.Memo,
+	})
+}
+
+// publishTransferPosted 行内转账入账后的透支通知和两边的 webhook 事件
+func publishTransferPosted(ctx context.Context, distributor worker.TaskDistributor, result db.TransferTxResult) error {
+	err := notifyOverdraft(ctx, distributor, result.FromAccount, result.Transfer.Amount+result.Transfer.Fee)
+	if err != nil {
+		return err
+	}
+
+	err = publishTransferCreated(ctx, distributor, result.Transfer, result.FromAccount)
+	if err != nil {
+		return err
+	}
+
+	return publishAccountCredited(ctx, distributor, result.Transfer, result.ToAccount)
+}
@@ -0,0 +1,44 @@
+package iso20022
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 支持的币种都是两位小数，金额在库里以最小单位存储
+var isDecimalAmount = regexp.MustCompile(`^[0-9]{1,16}(\.[0-9]{1,2})?$`).MatchString
+
+// FormatAmount 把最小单位的金额格式化成 XML 里的十进制数，不带符号
+func FormatAmount(amount int64) string {
+	if amount < 0 {
+		amount = -amount
+	}
+	return fmt.Sprintf("%d.%02d", amount/100, amount%100)
+}
+
+// ParseAmount 把 XML 里的十进制数换成最小单位，超过两位小数的拒绝而不是四舍五入
+func ParseAmount(value string) (int64, error) {
+	if !isDecimalAmount(value) {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	fraction += strings.Repeat("0", 2-len(fraction))
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	cents, _ := strconv.ParseInt(fraction, 10, 64)
+	return units*100 + cents, nil
+}
+
+// creditDebit 按金额符号给出 CRDT 或 DBIT
+func creditDebit(amount int64) string {
+	if amount < 0 {
+		return "DBIT"
+	}
+	return "CRDT"
+}
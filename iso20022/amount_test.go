@@ -0,0 +1,43 @@
+package iso20022
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAmount(t *testing.T) {
+	require.Equal(t, "0.00", FormatAmount(0))
+	require.Equal(t, "0.05", FormatAmount(5))
+	require.Equal(t, "1234.50", FormatAmount(123450))
+	require.Equal(t, "12.34", FormatAmount(-1234))
+}
+
+func TestParseAmount(t *testing.T) {
+	testCases := []struct {
+		value  string
+		amount int64
+		valid  bool
+	}{
+		{"1000", 100000, true},
+		{"500.2", 50020, true},
+		{"0.05", 5, true},
+		{"12.345", 0, false},
+		{"-1.00", 0, false},
+		{"1,00", 0, false},
+		{".5", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			amount, err := ParseAmount(tc.value)
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.amount, amount)
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"strconv"
+	"time"
+)
+
+const Camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+// 对账单里的交易类型，用自定义代码 (Prtry)
+const (
+	TransactionCodeTransfer = "TRANSFER"
+	TransactionCodeFee      = "FEE"
+	TransactionCodePosting  = "POSTING"
+)
+
+// Statement 一个账户一段时间的对账单，期初期末余额和分录由调用方从库里算好
+type Statement struct {
+	MessageID      string
+	CreatedAt      time.Time
+	AccountNumber  string
+	Currency       string
+	OwnerName      string
+	From           time.Time
+	To             time.Time
+	OpeningBalance int64
+	ClosingBalance int64
+	Entries        []StatementEntry
+}
+
+// StatementEntry 一条分录，Amount 带符号，贷记为正
+type StatementEntry struct {
+	EntryID    int64
+	Amount     int64
+	BookedAt   time.Time
+	TransferID int64
+	// 转账的手续费分录，和本金分开记
+	Fee                       bool
+	Memo                      string
+	EndToEndID                string
+	CounterpartyName          string
+	CounterpartyAccountNumber string
+}
+
+type camt053Document struct {
+	XMLName   xml.Name           `xml:"Document"`
+	Namespace string             `xml:"xmlns,attr"`
+	Statement bankToCustomerStmt `xml:"BkToCstmrStmt"`
+}
+
+type bankToCustomerStmt struct {
+	GroupHeader groupHeader      `xml:"GrpHdr"`
+	Statement   accountStatement `xml:"Stmt"`
+}
+
+type groupHeader struct {
+	MessageID string `xml:"MsgId"`
+	CreatedAt string `xml:"CreDtTm"`
+}
+
+type accountStatement struct {
+	ID         string              `xml:"Id"`
+	CreatedAt  string              `xml:"CreDtTm"`
+	FromToDate fromToDate          `xml:"FrToDt"`
+	Account    cashAccount         `xml:"Acct"`
+	Balances   []balance           `xml:"Bal"`
+	Summary    transactionsSummary `xml:"TxsSummry"`
+	Entries    []reportEntry       `xml:"Ntry"`
+}
+
+type fromToDate struct {
+	From string `xml:"FrDtTm"`
+	To   string `xml:"ToDtTm"`
+}
+
+type cashAccount struct {
+	ID       accountID  `xml:"Id"`
+	Currency string     `xml:"Ccy,omitempty"`
+	Owner    *partyName `xml:"Ownr,omitempty"`
+}
+
+type accountID struct {
+	IBAN string `xml:"IBAN"`
+}
+
+type partyName struct {
+	Name string `xml:"Nm"`
+}
+
+type amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type dateTime struct {
+	DateTime string `xml:"DtTm"`
+}
+
+type balance struct {
+	Type        balanceType `xml:"Tp"`
+	Amount      amount      `xml:"Amt"`
+	CreditDebit string      `xml:"CdtDbtInd"`
+	Date        dateTime    `xml:"Dt"`
+}
+
+type balanceType struct {
+	Code string `xml:"CdOrPrtry>Cd"`
+}
+
+type transactionsSummary struct {
+	Credits numberAndSum `xml:"TtlCdtNtries"`
+	Debits  numberAndSum `xml:"TtlDbtNtries"`
+}
+
+type numberAndSum struct {
+	Count string `xml:"NbOfNtries"`
+	Sum   string `xml:"Sum"`
+}
+
+type reportEntry struct {
+	Reference       string        `xml:"NtryRef"`
+	Amount          amount        `xml:"Amt"`
+	CreditDebit     string        `xml:"CdtDbtInd"`
+	Status          string        `xml:"Sts"`
+	BookingDate     dateTime      `xml:"BookgDt"`
+	ValueDate       dateTime      `xml:"ValDt"`
+	ServicerRef     string        `xml:"AcctSvcrRef,omitempty"`
+	TransactionCode string        `xml:"BkTxCd>Prtry>Cd"`
+	Details         *entryDetails `xml:"NtryDtls,omitempty"`
+	AdditionalInfo  string        `xml:"AddtlNtryInf,omitempty"`
+}
+
+type entryDetails struct {
+	Transaction entryTransaction `xml:"TxDtls"`
+}
+
+type entryTransaction struct {
+	EndToEndID     string          `xml:"Refs>EndToEndId"`
+	RelatedParties *relatedParties `xml:"RltdPties,omitempty"`
+	Remittance     *remittance     `xml:"RmtInf,omitempty"`
+}
+
+type remittance struct {
+	Unstructured string `xml:"Ustrd"`
+}
+
+type relatedParties struct {
+	Debtor          *partyName   `xml:"Dbtr,omitempty"`
+	DebtorAccount   *cashAccount `xml:"DbtrAcct,omitempty"`
+	Creditor        *partyName   `xml:"Cdtr,omitempty"`
+	CreditorAccount *cashAccount `xml:"CdtrAcct,omitempty"`
+}
+
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// MarshalStatement 生成 camt.053.001.02 对账单
+func MarshalStatement(statement Statement) ([]byte, error) {
+	stmt := accountStatement{
+		ID:        statement.MessageID,
+		CreatedAt: formatDateTime(statement.CreatedAt),
+		FromToDate: fromToDate{
+			From: formatDateTime(statement.From),
+			To:   formatDateTime(statement.To),
+		},
+		Account: cashAccount{
+			ID:       accountID{IBAN: statement.AccountNumber},
+			Currency: statement.Currency,
+			Owner:    newPartyName(statement.OwnerName),
+		},
+		Balances: []balance{
+			statement.balance("OPBD", statement.OpeningBalance, statement.From),
+			statement.balance("CLBD", statement.ClosingBalance, statement.To),
+		},
+	}
+
+	var credits, debits, creditSum, debitSum int64
+	for _, entry := range statement.Entries {
+		if entry.Amount < 0 {
+			debits++
+			debitSum -= entry.Amount
+		} else {
+			credits++
+			creditSum += entry.Amount
+		}
+		stmt.Entries = append(stmt.Entries, statement.entry(entry))
+	}
+	stmt.Summary = transactionsSummary{
+		Credits: numberAndSum{Count: strconv.FormatInt(credits, 10), Sum: FormatAmount(creditSum)},
+		Debits:  numberAndSum{Count: strconv.FormatInt(debits, 10), Sum: FormatAmount(debitSum)},
+	}
+
+	document := camt053Document{
+		Namespace: Camt053Namespace,
+		Statement: bankToCustomerStmt{
+			GroupHeader: groupHeader{
+				MessageID: statement.MessageID,
+				CreatedAt: formatDateTime(statement.CreatedAt),
+			},
+			Statement: stmt,
+		},
+	}
+	return marshalDocument(document)
+}
+
+func (statement Statement) balance(code string, value int64, at time.Time) balance {
+	return balance{
+		Type:        balanceType{Code: code},
+		Amount:      amount{Currency: statement.Currency, Value: FormatAmount(value)},
+		CreditDebit: creditDebit(value),
+		Date:        dateTime{DateTime: formatDateTime(at)},
+	}
+}
+
+func (statement Statement) entry(entry StatementEntry) reportEntry {
+	booked := dateTime{DateTime: formatDateTime(entry.BookedAt)}
+	ntry := reportEntry{
+		Reference:       strconv.FormatInt(entry.EntryID, 10),
+		Amount:          amount{Currency: statement.Currency, Value: FormatAmount(entry.Amount)},
+		CreditDebit:     creditDebit(entry.Amount),
+		Status:          "BOOK",
+		BookingDate:     booked,
+		ValueDate:       booked,
+		TransactionCode: TransactionCodePosting,
+	}
+
+	// 利息等不属于转账的分录没有明细
+	if entry.TransferID == 0 {
+		return ntry
+	}
+	ntry.ServicerRef = strconv.FormatInt(entry.TransferID, 10)
+
+	if entry.Fee {
+		ntry.TransactionCode = TransactionCodeFee
+		ntry.AdditionalInfo = "Fee for transfer " + ntry.ServicerRef
+		return ntry
+	}
+	ntry.TransactionCode = TransactionCodeTransfer
+
+	endToEndID := entry.EndToEndID
+	if endToEndID == "" {
+		endToEndID = "NOTPROVIDED"
+	}
+	transaction := entryTransaction{
+		EndToEndID: maxText(endToEndID, 35),
+	}
+	if entry.Memo != "" {
+		transaction.Remittance = &remittance{Unstructured: maxText(entry.Memo, 140)}
+	}
+
+	if entry.CounterpartyAccountNumber != "" {
+		party := newPartyName(entry.CounterpartyName)
+		account := &cashAccount{ID: accountID{IBAN: entry.CounterpartyAccountNumber}}
+		if entry.Amount < 0 {
+			transaction.RelatedParties = &relatedParties{Creditor: party, CreditorAccount: account}
+		} else {
+			transaction.RelatedParties = &relatedParties{Debtor: party, DebtorAccount: account}
+		}
+	}
+
+	ntry.Details = &entryDetails{Transaction: transaction}
+	return ntry
+}
+
+// newPartyName 名字是必填的 Max140Text，没有名字时整个节点不输出
+func newPartyName(name string) *partyName {
+	if name == "" {
+		return nil
+	}
+	return &partyName{Name: maxText(name, 140)}
+}
+
+// maxText 截断到 XSD 里 MaxNText 的长度
+func maxText(value string, n int) string {
+	runes := []rune(value)
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return value
+}
+
+func marshalDocument(document any) ([]byte, error) {
+	content, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(content, '\n')...), nil
+}
@@ -0,0 +1,63 @@
+package iso20022
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalStatement(t *testing.T) {
+	from := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+
+	statement := Statement{
+		MessageID:      "STMT-42-20261001-20261101",
+		CreatedAt:      time.Date(2026, time.November, 1, 6, 0, 0, 0, time.UTC),
+		AccountNumber:  "SB29SMPL0000000001",
+		Currency:       "USD",
+		OwnerName:      "Acme Corp",
+		From:           from,
+		To:             to,
+		OpeningBalance: 100000,
+		ClosingBalance: 149912,
+		Entries: []StatementEntry{
+			{
+				EntryID:                   7,
+				Amount:                    75000,
+				BookedAt:                  from.Add(26 * time.Hour),
+				TransferID:                3,
+				Memo:                      "Invoice 2026-17",
+				EndToEndID:                "INV-2026-17",
+				CounterpartyName:          "Widgets Ltd",
+				CounterpartyAccountNumber: "SB02SMPL0000000002",
+			},
+			{
+				EntryID:                   9,
+				Amount:                    -25000,
+				BookedAt:                  from.Add(50 * time.Hour),
+				TransferID:                4,
+				CounterpartyName:          "Jane Doe",
+				CounterpartyAccountNumber: "SB72SMPL0000000003",
+			},
+			{
+				EntryID:    10,
+				Amount:     -100,
+				BookedAt:   from.Add(50 * time.Hour),
+				TransferID: 4,
+				Fee:        true,
+			},
+			{
+				// 利息入账，不属于任何转账
+				EntryID:  12,
+				Amount:   12,
+				BookedAt: to.Add(-time.Hour),
+			},
+		},
+	}
+
+	content, err := MarshalStatement(statement)
+	require.NoError(t, err)
+	requireGolden(t, "camt.053.golden.xml", content)
+	requireValidXML(t, "camt.053.001.02.xsd", content)
+}
@@ -0,0 +1,40 @@
+package iso20022
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test ./iso20022 -update 重新生成 testdata 里的 golden 文件
+var update = flag.Bool("update", false, "update golden files")
+
+func requireGolden(t *testing.T, name string, actual []byte) {
+	golden := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(golden, actual, 0o644))
+	}
+
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual))
+}
+
+// requireValidXML 用 xmllint 按 testdata 里的 XSD 校验，没装 xmllint 的环境只跳过这一步
+func requireValidXML(t *testing.T, schema string, content []byte) {
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Log("xmllint not found, skipping schema validation")
+		return
+	}
+
+	document := filepath.Join(t.TempDir(), "document.xml")
+	require.NoError(t, os.WriteFile(document, content, 0o644))
+
+	output, err := exec.Command(xmllint, "--noout", "--schema", filepath.Join("testdata", schema), document).CombinedOutput()
+	require.NoError(t, err, string(output))
+}
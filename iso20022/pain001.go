@@ -0,0 +1,147 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const Pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"
+
+// MaxCreditTransfers 一个文件最多的付款笔数，超过的请拆成多个文件
+const MaxCreditTransfers = 500
+
+// CreditTransferInitiation 客户上传的批量付款指令
+type CreditTransferInitiation struct {
+	MessageID            string
+	CreatedAt            string
+	NumberOfTransactions int
+	Payments             []PaymentInstruction
+}
+
+// PaymentInstruction 同一个付款账户下的一组付款
+type PaymentInstruction struct {
+	ID              string
+	DebtorName      string
+	DebtorAccount   string
+	CreditTransfers []CreditTransfer
+}
+
+type CreditTransfer struct {
+	InstructionID   string
+	EndToEndID      string
+	Amount          int64
+	Currency        string
+	CreditorName    string
+	CreditorAccount string
+	Remittance      string
+}
+
+type pain001Document struct {
+	XMLName    xml.Name               `xml:"Document"`
+	Initiation customerCreditTransfer `xml:"CstmrCdtTrfInitn"`
+}
+
+type customerCreditTransfer struct {
+	GroupHeader struct {
+		MessageID            string `xml:"MsgId"`
+		CreatedAt            string `xml:"CreDtTm"`
+		NumberOfTransactions string `xml:"NbOfTxs"`
+		ControlSum           string `xml:"CtrlSum"`
+	} `xml:"GrpHdr"`
+	Payments []struct {
+		ID            string    `xml:"PmtInfId"`
+		Method        string    `xml:"PmtMtd"`
+		Debtor        partyName `xml:"Dbtr"`
+		DebtorAccount struct {
+			IBAN string `xml:"Id>IBAN"`
+		} `xml:"DbtrAcct"`
+		Transactions []struct {
+			InstructionID   string    `xml:"PmtId>InstrId"`
+			EndToEndID      string    `xml:"PmtId>EndToEndId"`
+			Amount          amount    `xml:"Amt>InstdAmt"`
+			Creditor        partyName `xml:"Cdtr"`
+			CreditorAccount struct {
+				IBAN string `xml:"Id>IBAN"`
+			} `xml:"CdtrAcct"`
+			Remittance []string `xml:"RmtInf>Ustrd"`
+		} `xml:"CdtTrfTxInf"`
+	} `xml:"PmtInf"`
+}
+
+// ParseCreditTransferInitiation 解析 pain.001.001.03
+// 格式错误、笔数或控制总额对不上时整个文件拒绝，单笔业务校验由调用方做
+func ParseCreditTransferInitiation(r io.Reader) (*CreditTransferInitiation, error) {
+	var document pain001Document
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid XML: %w", err)
+	}
+	if document.XMLName.Space != Pain001Namespace {
+		return nil, fmt.Errorf("unsupported document namespace %q, want %s", document.XMLName.Space, Pain001Namespace)
+	}
+
+	initiation := document.Initiation
+	header := initiation.GroupHeader
+	if header.MessageID == "" {
+		return nil, errors.New("GrpHdr/MsgId is required")
+	}
+
+	result := &CreditTransferInitiation{
+		MessageID: header.MessageID,
+		CreatedAt: header.CreatedAt,
+	}
+
+	var controlSum int64
+	for _, payment := range initiation.Payments {
+		if payment.Method != "TRF" {
+			return nil, fmt.Errorf("payment %s: unsupported payment method %q", payment.ID, payment.Method)
+		}
+
+		instruction := PaymentInstruction{
+			ID:            payment.ID,
+			DebtorName:    payment.Debtor.Name,
+			DebtorAccount: payment.DebtorAccount.IBAN,
+		}
+		for _, tx := range payment.Transactions {
+			value, err := ParseAmount(tx.Amount.Value)
+			if err != nil {
+				return nil, fmt.Errorf("payment %s transaction %s: %w", payment.ID, tx.EndToEndID, err)
+			}
+			controlSum += value
+
+			instruction.CreditTransfers = append(instruction.CreditTransfers, CreditTransfer{
+				InstructionID:   tx.InstructionID,
+				EndToEndID:      tx.EndToEndID,
+				Amount:          value,
+				Currency:        tx.Amount.Currency,
+				CreditorName:    tx.Creditor.Name,
+				CreditorAccount: tx.CreditorAccount.IBAN,
+				// 非结构化附言可以有多行，拼起来作为转账备注
+				Remittance: strings.Join(tx.Remittance, " "),
+			})
+			result.NumberOfTransactions++
+		}
+		result.Payments = append(result.Payments, instruction)
+	}
+
+	if result.NumberOfTransactions == 0 {
+		return nil, errors.New("file has no credit transfers")
+	}
+	if result.NumberOfTransactions > MaxCreditTransfers {
+		return nil, fmt.Errorf("file has %d credit transfers, at most %d are allowed", result.NumberOfTransactions, MaxCreditTransfers)
+	}
+	if header.NumberOfTransactions != strconv.Itoa(result.NumberOfTransactions) {
+		return nil, fmt.Errorf("GrpHdr/NbOfTxs is %s but the file has %d credit transfers", header.NumberOfTransactions, result.NumberOfTransactions)
+	}
+	if header.ControlSum != "" {
+		sum, err := ParseAmount(header.ControlSum)
+		if err != nil || sum != controlSum {
+			return nil, fmt.Errorf("GrpHdr/CtrlSum %s does not match the total %s", header.ControlSum, FormatAmount(controlSum))
+		}
+	}
+
+	return result, nil
+}
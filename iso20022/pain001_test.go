@@ -0,0 +1,73 @@
+package iso20022
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCreditTransferInitiation(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "pain.001.xml"))
+	require.NoError(t, err)
+	requireValidXML(t, "pain.001.001.03.xsd", content)
+
+	initiation, err := ParseCreditTransferInitiation(strings.NewReader(string(content)))
+	require.NoError(t, err)
+	require.Equal(t, "ACME-20261019-001", initiation.MessageID)
+	require.Equal(t, 3, initiation.NumberOfTransactions)
+	require.Len(t, initiation.Payments, 2)
+
+	payroll := initiation.Payments[0]
+	require.Equal(t, "PAYROLL-OCT", payroll.ID)
+	require.Equal(t, "SB29SMPL0000000001", payroll.DebtorAccount)
+	require.Equal(t, []CreditTransfer{
+		{
+			InstructionID:   "INSTR-1",
+			EndToEndID:      "SALARY-JANE",
+			Amount:          100000,
+			Currency:        "USD",
+			CreditorName:    "Jane Doe",
+			CreditorAccount: "SB02SMPL0000000002",
+			Remittance:      "October salary",
+		},
+		{
+			EndToEndID:      "SALARY-JOHN",
+			Amount:          50020,
+			Currency:        "USD",
+			CreditorName:    "John Roe",
+			CreditorAccount: "SB72SMPL0000000003",
+			Remittance:      "October salary incl. bonus",
+		},
+	}, payroll.CreditTransfers)
+
+	require.Equal(t, int64(5005), initiation.Payments[1].CreditTransfers[0].Amount)
+}
+
+func TestParseCreditTransferInitiationInvalid(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "pain.001.xml"))
+	require.NoError(t, err)
+	valid := string(content)
+
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"NotXML", "hello"},
+		{"WrongNamespace", strings.Replace(valid, "pain.001.001.03", "pain.001.001.09", 1)},
+		{"MissingMessageID", strings.Replace(valid, "<MsgId>ACME-20261019-001</MsgId>", "", 1)},
+		{"NumberOfTransactionsMismatch", strings.Replace(valid, "<NbOfTxs>3</NbOfTxs>", "<NbOfTxs>4</NbOfTxs>", 1)},
+		{"ControlSumMismatch", strings.Replace(valid, "<CtrlSum>1550.25</CtrlSum>", "<CtrlSum>1550.26</CtrlSum>", 1)},
+		{"TooManyDecimals", strings.Replace(valid, `Ccy="USD">50.05<`, `Ccy="USD">50.055<`, 1)},
+		{"UnsupportedMethod", strings.Replace(valid, "<PmtMtd>TRF</PmtMtd>", "<PmtMtd>CHK</PmtMtd>", 1)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseCreditTransferInitiation(strings.NewReader(tc.content))
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"strconv"
+	"time"
+)
+
+const Pain002Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.002.001.03"
+
+// 单笔付款的状态
+const (
+	// 已入账
+	StatusAcceptedSettlementCompleted = "ACSC"
+	// 等待审批
+	StatusPending  = "PDNG"
+	StatusRejected = "RJCT"
+	// 只用于组和批次：部分成功
+	StatusPartiallyAccepted = "PART"
+)
+
+// 拒绝原因码 (ExternalStatusReason1Code)
+const (
+	ReasonIncorrectAccountNumber = "AC01"
+	ReasonInvalidCreditorAccount = "AC03"
+	ReasonBlockedAccount         = "AC06"
+	ReasonTransactionForbidden   = "AG01"
+	ReasonNotAllowedAmount       = "AM02"
+	ReasonNotAllowedCurrency     = "AM03"
+	ReasonInsufficientFunds      = "AM04"
+	ReasonDuplication            = "AM05"
+	ReasonNarrative              = "NARR"
+)
+
+// StatusReport 对一个 pain.001 文件逐笔回报结果
+type StatusReport struct {
+	MessageID         string
+	CreatedAt         time.Time
+	OriginalMessageID string
+	Payments          []PaymentStatus
+}
+
+type PaymentStatus struct {
+	PaymentInformationID string
+	Transactions         []TransactionStatus
+}
+
+type TransactionStatus struct {
+	InstructionID string
+	EndToEndID    string
+	Status        string
+	// 拒绝时的原因码和说明
+	ReasonCode     string
+	AdditionalInfo string
+	// 入账或待审批时本行的转账 ID
+	TransferID int64
+}
+
+type pain002Document struct {
+	XMLName   xml.Name         `xml:"Document"`
+	Namespace string           `xml:"xmlns,attr"`
+	Report    paymentStatusRpt `xml:"CstmrPmtStsRpt"`
+}
+
+type paymentStatusRpt struct {
+	GroupHeader   groupHeader             `xml:"GrpHdr"`
+	OriginalGroup originalGroupStatus     `xml:"OrgnlGrpInfAndSts"`
+	Payments      []originalPaymentStatus `xml:"OrgnlPmtInfAndSts"`
+}
+
+type originalGroupStatus struct {
+	MessageID   string `xml:"OrgnlMsgId"`
+	MessageName string `xml:"OrgnlMsgNmId"`
+	NumberOfTxs string `xml:"OrgnlNbOfTxs"`
+	GroupStatus string `xml:"GrpSts"`
+}
+
+type originalPaymentStatus struct {
+	ID           string              `xml:"OrgnlPmtInfId"`
+	NumberOfTxs  string              `xml:"OrgnlNbOfTxs"`
+	Status       string              `xml:"PmtInfSts"`
+	Transactions []transactionStatus `xml:"TxInfAndSts"`
+}
+
+type transactionStatus struct {
+	InstructionID string        `xml:"OrgnlInstrId,omitempty"`
+	EndToEndID    string        `xml:"OrgnlEndToEndId"`
+	Status        string        `xml:"TxSts"`
+	Reason        *statusReason `xml:"StsRsnInf,omitempty"`
+	ServicerRef   string        `xml:"AcctSvcrRef,omitempty"`
+}
+
+type statusReason struct {
+	Code           string `xml:"Rsn>Cd"`
+	AdditionalInfo string `xml:"AddtlInf,omitempty"`
+}
+
+// MarshalStatusReport 生成 pain.002.001.03，批次和组的状态由单笔状态汇总
+func MarshalStatusReport(report StatusReport) ([]byte, error) {
+	rpt := paymentStatusRpt{
+		GroupHeader: groupHeader{
+			MessageID: report.MessageID,
+			CreatedAt: formatDateTime(report.CreatedAt),
+		},
+	}
+
+	for _, payment := range report.Payments {
+		var statuses []string
+		original := originalPaymentStatus{
+			ID:          payment.PaymentInformationID,
+			NumberOfTxs: strconv.Itoa(len(payment.Transactions)),
+		}
+		for _, tx := range payment.Transactions {
+			status := transactionStatus{
+				InstructionID: tx.InstructionID,
+				EndToEndID:    tx.EndToEndID,
+				Status:        tx.Status,
+			}
+			if tx.ReasonCode != "" {
+				status.Reason = &statusReason{
+					Code:           tx.ReasonCode,
+					AdditionalInfo: maxText(tx.AdditionalInfo, 105),
+				}
+			}
+			if tx.TransferID != 0 {
+				status.ServicerRef = strconv.FormatInt(tx.TransferID, 10)
+			}
+			original.Transactions = append(original.Transactions, status)
+			statuses = append(statuses, tx.Status)
+		}
+		original.Status = summaryStatus(statuses)
+		rpt.Payments = append(rpt.Payments, original)
+	}
+
+	statuses := report.statuses()
+	rpt.OriginalGroup = originalGroupStatus{
+		MessageID:   report.OriginalMessageID,
+		MessageName: "pain.001.001.03",
+		NumberOfTxs: strconv.Itoa(len(statuses)),
+		GroupStatus: summaryStatus(statuses),
+	}
+
+	return marshalDocument(pain002Document{
+		Namespace: Pain002Namespace,
+		Report:    rpt,
+	})
+}
+
+// GroupStatus 整个文件的汇总状态，和报告里 OrgnlGrpInfAndSts/GrpSts 一致
+func (report StatusReport) GroupStatus() string {
+	return summaryStatus(report.statuses())
+}
+
+func (report StatusReport) statuses() (statuses []string) {
+	for _, payment := range report.Payments {
+		for _, tx := range payment.Transactions {
+			statuses = append(statuses, tx.Status)
+		}
+	}
+	return statuses
+}
+
+// summaryStatus 汇总批次和组的状态：全部拒绝是 RJCT，部分拒绝是 PART，
+// 没有拒绝时全部入账是 ACSC，否则还有待审批的是 PDNG
+func summaryStatus(statuses []string) string {
+	var rejected, completed int
+	for _, status := range statuses {
+		switch status {
+		case StatusRejected:
+			rejected++
+		case StatusAcceptedSettlementCompleted:
+			completed++
+		}
+	}
+
+	switch {
+	case rejected == len(statuses):
+		return StatusRejected
+	case rejected > 0:
+		return StatusPartiallyAccepted
+	case completed == len(statuses):
+		return StatusAcceptedSettlementCompleted
+	}
+	return StatusPending
+}
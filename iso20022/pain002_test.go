@@ -0,0 +1,62 @@
+package iso20022
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalStatusReport(t *testing.T) {
+	report := StatusReport{
+		MessageID:         "STS-ACME-20261019-001",
+		CreatedAt:         time.Date(2026, time.October, 19, 9, 30, 5, 0, time.UTC),
+		OriginalMessageID: "ACME-20261019-001",
+		Payments: []PaymentStatus{
+			{
+				PaymentInformationID: "PAYROLL-OCT",
+				Transactions: []TransactionStatus{
+					{InstructionID: "INSTR-1", EndToEndID: "SALARY-JANE", Status: StatusAcceptedSettlementCompleted, TransferID: 101},
+					{EndToEndID: "SALARY-JOHN", Status: StatusPending, TransferID: 102},
+				},
+			},
+			{
+				PaymentInformationID: "SUPPLIERS",
+				Transactions: []TransactionStatus{
+					{
+						EndToEndID:     "INV-4711",
+						Status:         StatusRejected,
+						ReasonCode:     ReasonInvalidCreditorAccount,
+						AdditionalInfo: "creditor account must be held at this bank",
+					},
+				},
+			},
+		},
+	}
+
+	require.Equal(t, StatusPartiallyAccepted, report.GroupStatus())
+
+	content, err := MarshalStatusReport(report)
+	require.NoError(t, err)
+	requireGolden(t, "pain.002.golden.xml", content)
+	requireValidXML(t, "pain.002.001.03.xsd", content)
+}
+
+func TestSummaryStatus(t *testing.T) {
+	testCases := []struct {
+		name     string
+		statuses []string
+		summary  string
+	}{
+		{"AllCompleted", []string{StatusAcceptedSettlementCompleted, StatusAcceptedSettlementCompleted}, StatusAcceptedSettlementCompleted},
+		{"SomePending", []string{StatusAcceptedSettlementCompleted, StatusPending}, StatusPending},
+		{"SomeRejected", []string{StatusAcceptedSettlementCompleted, StatusRejected}, StatusPartiallyAccepted},
+		{"AllRejected", []string{StatusRejected, StatusRejected}, StatusRejected},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.summary, summaryStatus(tc.statuses))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// SMTP 认证方式
+const (
+	AuthPlain   = "plain"
+	AuthLogin   = "login"
+	AuthCRAMMD5 = "cram-md5"
+	AuthNone    = "none"
+)
+
+func smtpAuth(config SMTPConfig) (smtp.Auth, error) {
+	switch config.Auth {
+	case AuthPlain:
+		return smtp.PlainAuth("", config.Username, config.Password, config.Host), nil
+	case AuthLogin:
+		return &loginAuth{username: config.Username, password: config.Password, host: config.Host}, nil
+	case AuthCRAMMD5:
+		return smtp.CRAMMD5Auth(config.Username, config.Password), nil
+	case AuthNone:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown smtp auth mechanism %q", config.Auth)
+}
+
+// loginAuth AUTH LOGIN，标准库没有实现，Outlook 和一些老的服务器只支持这种
+// 和 PLAIN 一样明文发密码，只在 TLS 连接或者本机上用
+type loginAuth struct {
+	username string
+	password string
+	host     string
+}
+
+func (auth *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != auth.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	return "LOGIN", nil, nil
+}
+
+// Next 服务器依次问用户名和密码，提示语各家不完全一样
+func (auth *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	prompt := strings.ToLower(string(fromServer))
+	switch {
+	case strings.Contains(prompt, "user"):
+		return []byte(auth.username), nil
+	case strings.Contains(prompt, "password"):
+		return []byte(auth.password), nil
+	}
+	return nil, fmt.Errorf("unexpected server challenge %q", fromServer)
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
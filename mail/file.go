@@ -0,0 +1,64 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// FileSender 开发时用，每封信写成目录下的一个 .eml 文件，可以直接用邮件客户端打开
+type FileSender struct {
+	dir  string
+	from string
+}
+
+func NewFileSender(dir string, name string, fromEmailAddress string) (EmailSender, error) {
+	if dir == "" {
+		return nil, errors.New("email file directory must not be empty")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create email file directory: %w", err)
+	}
+
+	return &FileSender{
+		dir:  dir,
+		from: fromAddress(name, fromEmailAddress),
+	}, nil
+}
+
+func (sender *FileSender) SendEmail(
+	subject string,
+	content string,
+	to []string,
+	cc []string,
+	bcc []string,
+	attachFiles []string,
+) error {
+	e, err := newEmail(sender.from, subject, content, to, cc, bcc, attachFiles)
+	if err != nil {
+		return err
+	}
+	// 真实发信时密送不出现在邮件头里，写文件时留下来方便检查
+	if len(bcc) > 0 {
+		e.Headers.Set("Bcc", strings.Join(bcc, ", "))
+	}
+
+	msg, err := e.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+
+	// 文件名按时间排序，同一时刻的信由随机后缀区分
+	file, err := os.CreateTemp(sender.dir, time.Now().UTC().Format("20060102T150405.000000000")+"-*.eml")
+	if err != nil {
+		return fmt.Errorf("failed to create email file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(msg); err != nil {
+		return fmt.Errorf("failed to write email file: %w", err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,70 @@
+package mail
+
+import "sync"
+
+// Message 内存发件记下的一封信
+type Message struct {
+	From        string
+	Subject     string
+	Content     string
+	To          []string
+	Cc          []string
+	Bcc         []string
+	AttachFiles []string
+}
+
+// MemorySender 测试用，信只留在内存里供断言
+type MemorySender struct {
+	mu       sync.Mutex
+	from     string
+	messages []Message
+}
+
+func NewMemorySender(name string, fromEmailAddress string) *MemorySender {
+	return &MemorySender{
+		from: fromAddress(name, fromEmailAddress),
+	}
+}
+
+func (sender *MemorySender) SendEmail(
+	subject string,
+	content string,
+	to []string,
+	cc []string,
+	bcc []string,
+	attachFiles []string,
+) error {
+	// 附件读不到时和真实发信一样失败
+	if _, err := newEmail(sender.from, subject, content, to, cc, bcc, attachFiles); err != nil {
+		return err
+	}
+
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+
+	sender.messages = append(sender.messages, Message{
+		From:        sender.from,
+		Subject:     subject,
+		Content:     content,
+		To:          to,
+		Cc:          cc,
+		Bcc:         bcc,
+		AttachFiles: attachFiles,
+	})
+	return nil
+}
+
+// Messages 按发送顺序返回发过的信
+func (sender *MemorySender) Messages() []Message {
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+
+	return append([]Message(nil), sender.messages...)
+}
+
+func (sender *MemorySender) Reset() {
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+
+	sender.messages = nil
+}
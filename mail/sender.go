@@ -2,14 +2,16 @@ package mail
 
 import (
 	"fmt"
-	"net/smtp"
+	"simplebank/util"
 
 	"github.com/jordan-wright/email"
 )
 
+// 发件实现：真实发信的 smtp，开发时写 .eml 文件的 file，测试里断言用的 memory
 const (
-	smtpAuthAddress   = "smtp.gmail.com"
-	smtpServerAddress = "smtp.gmail.com:465"
+	SenderSMTP   = "smtp"
+	SenderFile   = "file"
+	SenderMemory = "memory"
 )
 
 type EmailSender interface {
@@ -23,30 +25,47 @@ type EmailSender interface {
 	) error
 }
 
-type GmailSender struct {
-	name              string
-	fromEmailAddress  string
-	fromEmailPassword string
+// NewEmailSender 按配置选发件实现
+func NewEmailSender(config util.Config) (EmailSender, error) {
+	switch config.EmailSender {
+	case SenderSMTP:
+		username := config.SMTPUsername
+		if username == "" {
+			username = config.EmailSenderAddress
+		}
+		return NewSMTPSender(SMTPConfig{
+			Host:     config.SMTPHost,
+			Port:     config.SMTPPort,
+			TLS:      config.SMTPTLS,
+			Auth:     config.SMTPAuth,
+			Username: username,
+			Password: config.EmailSenderPassword,
+		}, config.EmailSenderName, config.EmailSenderAddress)
+	case SenderFile:
+		return NewFileSender(config.EmailFileDir, config.EmailSenderName, config.EmailSenderAddress)
+	case SenderMemory:
+		return NewMemorySender(config.EmailSenderName, config.EmailSenderAddress), nil
+	}
+	return nil, fmt.Errorf("unknown email sender %q", config.EmailSender)
 }
 
+// NewGmailSender 用 Gmail 的 SMTP 服务器发信，fromEmailPassword 是应用专用密码
 func NewGmailSender(name string, fromEmailAddress string, fromEmailPassword string) EmailSender {
-	return &GmailSender{
-		name:              name,
-		fromEmailAddress:  fromEmailAddress,
-		fromEmailPassword: fromEmailPassword,
-	}
+	sender, _ := NewSMTPSender(SMTPConfig{
+		Host:     "smtp.gmail.com",
+		Port:     465,
+		TLS:      TLSImplicit,
+		Auth:     AuthPlain,
+		Username: fromEmailAddress,
+		Password: fromEmailPassword,
+	}, name, fromEmailAddress)
+	return sender
 }
 
-func (sender *GmailSender) SendEmail(
-	subject string,
-	content string,
-	to []string,
-	cc []string,
-	bcc []string,
-	attachFiles []string,
-) error {
+// newEmail 各个发件实现共用的邮件内容，附件读不到时直接报错
+func newEmail(from string, subject string, content string, to []string, cc []string, bcc []string, attachFiles []string) (*email.Email, error) {
 	e := email.NewEmail()
-	e.From = fmt.Sprintf("%s <%s>", sender.name, sender.fromEmailAddress)
+	e.From = from
 	e.Subject = subject
 	e.HTML = []byte(content)
 	e.To = to
@@ -57,13 +76,13 @@ func (sender *GmailSender) SendEmail(
 	for _, f := range attachFiles {
 		_, err := e.AttachFile(f)
 		if err != nil {
-			return fmt.Errorf("failed to attach file %s: %w", f, err)
+			return nil, fmt.Errorf("failed to attach file %s: %w", f, err)
 		}
 	}
 
-	// 设置 SMTP 认证
-	auth := smtp.PlainAuth("", sender.fromEmailAddress, sender.fromEmailPassword, smtpAuthAddress)
+	return e, nil
+}
 
-	// 发送邮件
-	return e.Send(smtpServerAddress, auth)
+func fromAddress(name string, address string) string {
+	return fmt.Sprintf("%s <%s>", name, address)
 }
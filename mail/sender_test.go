@@ -0,0 +1,103 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"simplebank/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEmailSender(t *testing.T) {
+	config := util.Config{
+		EmailSenderName:    "Simple Bank",
+		EmailSenderAddress: "bank@example.com",
+		SMTPHost:           "localhost",
+		SMTPPort:           1025,
+		SMTPTLS:            TLSNone,
+		SMTPAuth:           AuthNone,
+	}
+
+	config.EmailSender = SenderSMTP
+	sender, err := NewEmailSender(config)
+	require.NoError(t, err)
+	require.IsType(t, &SMTPSender{}, sender)
+	require.Nil(t, sender.(*SMTPSender).auth)
+
+	config.EmailSender = SenderFile
+	config.EmailFileDir = t.TempDir()
+	sender, err = NewEmailSender(config)
+	require.NoError(t, err)
+	require.IsType(t, &FileSender{}, sender)
+
+	config.EmailSender = SenderMemory
+	sender, err = NewEmailSender(config)
+	require.NoError(t, err)
+	require.IsType(t, &MemorySender{}, sender)
+
+	config.EmailSender = "sendgrid"
+	_, err = NewEmailSender(config)
+	require.Error(t, err)
+
+	config.EmailSender = SenderFile
+	config.EmailFileDir = ""
+	_, err = NewEmailSender(config)
+	require.Error(t, err)
+}
+
+func TestFileSender(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	sender, err := NewFileSender(dir, "Simple Bank", "bank@example.com")
+	require.NoError(t, err)
+
+	attachment := filepath.Join(t.TempDir(), "statement.txt")
+	require.NoError(t, os.WriteFile(attachment, []byte("statement"), 0o644))
+
+	err = sender.SendEmail("hello", "<p>hi</p>", []string{"alice@example.com"}, nil, []string{"carol@example.com"}, []string{attachment})
+	require.NoError(t, err)
+	err = sender.SendEmail("again", "<p>hi</p>", []string{"alice@example.com"}, nil, nil, nil)
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	require.Contains(t, string(content), "From: \"Simple Bank\" <bank@example.com>")
+	require.Contains(t, string(content), "Subject: hello")
+	require.Contains(t, string(content), "Bcc: <carol@example.com>")
+	require.Contains(t, string(content), "statement.txt")
+
+	// 附件不存在时不写文件
+	err = sender.SendEmail("broken", "hi", []string{"alice@example.com"}, nil, nil, []string{"missing.pdf"})
+	require.Error(t, err)
+	files, err = filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+}
+
+func TestMemorySender(t *testing.T) {
+	sender := NewMemorySender("Simple Bank", "bank@example.com")
+
+	err := sender.SendEmail("hello", "<p>hi</p>", []string{"alice@example.com"}, []string{"bob@example.com"}, nil, nil)
+	require.NoError(t, err)
+
+	messages := sender.Messages()
+	require.Len(t, messages, 1)
+	require.Equal(t, Message{
+		From:    "Simple Bank <bank@example.com>",
+		Subject: "hello",
+		Content: "<p>hi</p>",
+		To:      []string{"alice@example.com"},
+		Cc:      []string{"bob@example.com"},
+	}, messages[0])
+
+	err = sender.SendEmail("broken", "hi", []string{"alice@example.com"}, nil, nil, []string{"missing.pdf"})
+	require.Error(t, err)
+	require.Len(t, sender.Messages(), 1)
+
+	sender.Reset()
+	require.Empty(t, sender.Messages())
+}
@@ -0,0 +1,182 @@
+package mail
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// 和服务器之间的加密方式：连上就是 TLS 的 implicit (465)，明文连上再升级的 starttls (587)，
+// 不加密的 none 只用于本地的 MailHog 之类
+const (
+	TLSImplicit = "implicit"
+	TLSStartTLS = "starttls"
+	TLSNone     = "none"
+)
+
+const (
+	smtpDialTimeout = 10 * time.Second
+	// 一封信从连接到发完的总时长
+	smtpSendTimeout = time.Minute
+)
+
+type SMTPConfig struct {
+	Host string
+	Port int
+	TLS  string
+	// 认证方式 plain、login、cram-md5，none 时不认证
+	Auth     string
+	Username string
+	Password string
+}
+
+// SMTPSender 通过任意 SMTP 服务器发信
+type SMTPSender struct {
+	config SMTPConfig
+	from   string
+	auth   smtp.Auth
+}
+
+func NewSMTPSender(config SMTPConfig, name string, fromEmailAddress string) (EmailSender, error) {
+	if config.Host == "" {
+		return nil, errors.New("smtp host must not be empty")
+	}
+	if config.Port <= 0 || config.Port > 65535 {
+		return nil, fmt.Errorf("invalid smtp port %d", config.Port)
+	}
+	switch config.TLS {
+	case TLSImplicit, TLSStartTLS, TLSNone:
+	default:
+		return nil, fmt.Errorf("unknown smtp tls mode %q", config.TLS)
+	}
+
+	auth, err := smtpAuth(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SMTPSender{
+		config: config,
+		from:   fromAddress(name, fromEmailAddress),
+		auth:   auth,
+	}, nil
+}
+
+func (sender *SMTPSender) SendEmail(
+	subject string,
+	content string,
+	to []string,
+	cc []string,
+	bcc []string,
+	attachFiles []string,
+) error {
+	e, err := newEmail(sender.from, subject, content, to, cc, bcc, attachFiles)
+	if err != nil {
+		return err
+	}
+
+	from, err := mail.ParseAddress(e.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	// 信封上的收件人包括密送，邮件头里不带密送
+	recipients := make([]string, 0, len(to)+len(cc)+len(bcc))
+	for _, list := range [][]string{to, cc, bcc} {
+		for _, recipient := range list {
+			address, err := mail.ParseAddress(recipient)
+			if err != nil {
+				return fmt.Errorf("invalid recipient %s: %w", recipient, err)
+			}
+			recipients = append(recipients, address.Address)
+		}
+	}
+	if len(recipients) == 0 {
+		return errors.New("email must have at least one recipient")
+	}
+
+	msg, err := e.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+
+	return sender.send(from.Address, recipients, msg)
+}
+
+func (sender *SMTPSender) send(from string, recipients []string, msg []byte) error {
+	client, err := sender.dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if sender.config.TLS == TLSStartTLS {
+		// 服务器不支持时不能退回明文，否则密码和内容都是明文发出去的
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: sender.config.Host}); err != nil {
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+
+	if sender.auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support AUTH")
+		}
+		if err := client.Auth(sender.auth); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("failed to set sender: %w", err)
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("failed to add recipient %s: %w", recipient, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to start data: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return client.Quit()
+}
+
+func (sender *SMTPSender) dial() (*smtp.Client, error) {
+	addr := net.JoinHostPort(sender.config.Host, strconv.Itoa(sender.config.Port))
+	dialer := &net.Dialer{Timeout: smtpDialTimeout}
+
+	var conn net.Conn
+	var err error
+	if sender.config.TLS == TLSImplicit {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: sender.config.Host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to smtp server %s: %w", addr, err)
+	}
+	conn.SetDeadline(time.Now().Add(smtpSendTimeout))
+
+	client, err := smtp.NewClient(conn, sender.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	return client, nil
+}
@@ -0,0 +1,280 @@
+package mail
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer 只实现发一封信用到的命令，记下认证结果、信封和内容
+type fakeSMTPServer struct {
+	listener   net.Listener
+	extensions []string
+	password   string
+
+	user       string
+	from       string
+	recipients []string
+	data       string
+}
+
+func newFakeSMTPServer(t *testing.T, extensions ...string) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	return &fakeSMTPServer{listener: listener, extensions: extensions, password: "secret"}
+}
+
+func (server *fakeSMTPServer) port() int {
+	return server.listener.Addr().(*net.TCPAddr).Port
+}
+
+// serve 处理一个连接，done 在会话结束时关闭
+func (server *fakeSMTPServer) serve() <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer conn.Close()
+		done <- server.session(bufio.NewReader(conn), conn)
+	}()
+	return done
+}
+
+func (server *fakeSMTPServer) session(r *bufio.Reader, w net.Conn) error {
+	reply := func(format string, args ...any) {
+		fmt.Fprintf(w, format+"\r\n", args...)
+	}
+	readLine := func() (string, error) {
+		line, err := r.ReadString('\n')
+		return strings.TrimRight(line, "\r\n"), err
+	}
+	decode := func(line string) string {
+		b, _ := base64.StdEncoding.DecodeString(line)
+		return string(b)
+	}
+
+	reply("220 fake smtp ready")
+	for {
+		line, err := readLine()
+		if err != nil {
+			return err
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			lines := append([]string{"fake"}, server.extensions...)
+			for i, ext := range lines {
+				sep := "-"
+				if i == len(lines)-1 {
+					sep = " "
+				}
+				reply("250%s%s", sep, ext)
+			}
+		case "AUTH":
+			mechanism, initial, _ := strings.Cut(arg, " ")
+			switch mechanism {
+			case "PLAIN":
+				parts := strings.Split(decode(initial), "\x00")
+				server.user = parts[1]
+				if parts[2] != server.password {
+					reply("535 bad credentials")
+					continue
+				}
+			case "LOGIN":
+				reply("334 %s", base64.StdEncoding.EncodeToString([]byte("Username:")))
+				user, _ := readLine()
+				reply("334 %s", base64.StdEncoding.EncodeToString([]byte("Password:")))
+				password, _ := readLine()
+				server.user = decode(user)
+				if decode(password) != server.password {
+					reply("535 bad credentials")
+					continue
+				}
+			case "CRAM-MD5":
+				challenge := "<1234@fake>"
+				reply("334 %s", base64.StdEncoding.EncodeToString([]byte(challenge)))
+				response, _ := readLine()
+				user, digest, _ := strings.Cut(decode(response), " ")
+				server.user = user
+				mac := hmac.New(md5.New, []byte(server.password))
+				mac.Write([]byte(challenge))
+				if digest != hex.EncodeToString(mac.Sum(nil)) {
+					reply("535 bad credentials")
+					continue
+				}
+			default:
+				reply("504 unsupported mechanism")
+				continue
+			}
+			reply("235 authenticated")
+		case "MAIL":
+			server.from = strings.TrimSuffix(strings.TrimPrefix(arg, "FROM:<"), ">")
+			reply("250 ok")
+		case "RCPT":
+			server.recipients = append(server.recipients, strings.TrimSuffix(strings.TrimPrefix(arg, "TO:<"), ">"))
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err := readLine()
+				if err != nil {
+					return err
+				}
+				if line == "." {
+					break
+				}
+				data.WriteString(line + "\n")
+			}
+			server.data = data.String()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return nil
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPSenderAuth(t *testing.T) {
+	for _, mechanism := range []string{AuthPlain, AuthLogin, AuthCRAMMD5} {
+		t.Run(mechanism, func(t *testing.T) {
+			server := newFakeSMTPServer(t, "AUTH PLAIN LOGIN CRAM-MD5")
+			done := server.serve()
+
+			sender, err := NewSMTPSender(SMTPConfig{
+				Host:     "127.0.0.1",
+				Port:     server.port(),
+				TLS:      TLSNone,
+				Auth:     mechanism,
+				Username: "bank@example.com",
+				Password: "secret",
+			}, "Simple Bank", "bank@example.com")
+			require.NoError(t, err)
+
+			err = sender.SendEmail("hello", "<p>hi</p>", []string{"Alice <alice@example.com>"}, []string{"bob@example.com"}, []string{"carol@example.com"}, nil)
+			require.NoError(t, err)
+			require.NoError(t, <-done)
+
+			require.Equal(t, "bank@example.com", server.user)
+			require.Equal(t, "bank@example.com", server.from)
+			require.Equal(t, []string{"alice@example.com", "bob@example.com", "carol@example.com"}, server.recipients)
+			require.Contains(t, server.data, "Subject: hello")
+			require.Contains(t, server.data, "<p>hi</p>")
+			// 密送只在信封上
+			require.NotContains(t, server.data, "carol@example.com")
+		})
+	}
+}
+
+func TestSMTPSenderWrongPassword(t *testing.T) {
+	server := newFakeSMTPServer(t, "AUTH LOGIN")
+	done := server.serve()
+
+	sender, err := NewSMTPSender(SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     server.port(),
+		TLS:      TLSNone,
+		Auth:     AuthLogin,
+		Username: "bank@example.com",
+		Password: "wrong",
+	}, "Simple Bank", "bank@example.com")
+	require.NoError(t, err)
+
+	err = sender.SendEmail("hello", "hi", []string{"alice@example.com"}, nil, nil, nil)
+	require.ErrorContains(t, err, "failed to authenticate")
+	server.listener.Close()
+	<-done
+	require.Empty(t, server.data)
+}
+
+func TestSMTPSenderRequiresStartTLS(t *testing.T) {
+	server := newFakeSMTPServer(t, "AUTH PLAIN")
+	done := server.serve()
+
+	sender, err := NewSMTPSender(SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     server.port(),
+		TLS:      TLSStartTLS,
+		Auth:     AuthPlain,
+		Username: "bank@example.com",
+		Password: "secret",
+	}, "Simple Bank", "bank@example.com")
+	require.NoError(t, err)
+
+	// 服务器不支持 STARTTLS 时不能退回明文
+	err = sender.SendEmail("hello", "hi", []string{"alice@example.com"}, nil, nil, nil)
+	require.ErrorContains(t, err, "STARTTLS")
+	server.listener.Close()
+	<-done
+	require.Empty(t, server.user)
+}
+
+func TestNewSMTPSenderInvalidConfig(t *testing.T) {
+	valid := SMTPConfig{Host: "smtp.example.com", Port: 587, TLS: TLSStartTLS, Auth: AuthPlain}
+
+	testCases := []struct {
+		name   string
+		modify func(config *SMTPConfig)
+	}{
+		{"EmptyHost", func(config *SMTPConfig) { config.Host = "" }},
+		{"InvalidPort", func(config *SMTPConfig) { config.Port = 0 }},
+		{"UnknownTLS", func(config *SMTPConfig) { config.TLS = "ssl" }},
+		{"UnknownAuth", func(config *SMTPConfig) { config.Auth = "xoauth2" }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := valid
+			tc.modify(&config)
+			_, err := NewSMTPSender(config, "Simple Bank", "bank@example.com")
+			require.Error(t, err)
+		})
+	}
+
+	_, err := NewSMTPSender(valid, "Simple Bank", "bank@example.com")
+	require.NoError(t, err)
+}
+
+func TestLoginAuth(t *testing.T) {
+	auth := &loginAuth{username: "alice", password: "secret", host: "smtp.example.com"}
+
+	// 明文连接上不发密码
+	_, _, err := auth.Start(&smtp.ServerInfo{Name: "smtp.example.com", TLS: false})
+	require.Error(t, err)
+
+	mechanism, initial, err := auth.Start(&smtp.ServerInfo{Name: "smtp.example.com", TLS: true})
+	require.NoError(t, err)
+	require.Equal(t, "LOGIN", mechanism)
+	require.Nil(t, initial)
+
+	_, _, err = auth.Start(&smtp.ServerInfo{Name: "evil.example.com", TLS: true})
+	require.Error(t, err)
+
+	username, err := auth.Next([]byte("User Name"), true)
+	require.NoError(t, err)
+	require.Equal(t, "alice", string(username))
+
+	password, err := auth.Next([]byte("Password:"), true)
+	require.NoError(t, err)
+	require.Equal(t, "secret", string(password))
+
+	_, err = auth.Next([]byte("Token:"), true)
+	require.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"io/fs"
 	"log"
 	"log/slog"
@@ -11,12 +12,15 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"simplebank/activity"
 	"simplebank/api"
 	db "simplebank/db/sqlc"
 	"simplebank/doc"
 	"simplebank/gapi"
 	"simplebank/mail"
+	"simplebank/nacha"
 	"simplebank/pb"
+	"simplebank/settlement"
 	"simplebank/util"
 	"simplebank/worker"
 	"syscall"
@@ -68,18 +72,45 @@ func main() {
 
 	store := db.NewStore(conn)
 
-	mailer := mail.NewGmailSender(config.EmailSenderName, config.EmailSenderAddress, config.EmailSenderPassword)
+	// 带参数时执行运维命令，不启动服务
+	if len(os.Args) > 1 {
+		if err := runCommand(ctx, config, store, os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	mailer, err := mail.NewEmailSender(config)
+	if err != nil {
+		log.Fatal("cannot create email sender:", err)
+	}
 
 	redisOpt := asynq.RedisClientOpt{
 		Addr: config.RedisAddress,
 	}
-	taskDistributor := worker.NewRedisTaskDistributor(redisOpt)
+	// 任务先写进 outbox，由 relay 投递到配置的任务队列
+	taskDistributor := worker.NewOutboxTaskDistributor(store)
+	taskBackend, err := worker.NewTaskBackend(config.TaskQueueBackend, redisOpt, store)
+	if err != nil {
+		log.Fatal("cannot create task queue backend:", err)
+	}
+	// 管理接口查看和处理失败的任务
+	taskInspector := taskBackend.Inspector()
+	defer taskInspector.Close()
+
+	// 分录提交的通知由 LISTEN 收到后分发给 gRPC 和网关上的 WatchAccount 连接
+	activityHub := activity.NewHub()
 
 	waitGroup, ctx := errgroup.WithContext(ctx)
 
-	go runGrpcServer(ctx, waitGroup, config, store, taskDistributor)
-	go runTaskProcessor(ctx, waitGroup, config, redisOpt, store, mailer)
-	runGatewayServer(ctx, waitGroup, config, store, taskDistributor)
+	go runActivityListener(ctx, waitGroup, config, activityHub)
+	go runGrpcServer(ctx, waitGroup, config, store, taskDistributor, taskInspector, activityHub)
+	go runTaskProcessor(ctx, waitGroup, config, taskBackend, store, mailer, taskDistributor)
+	go runOutboxRelay(ctx, waitGroup, config, taskBackend, store)
+	if config.SchedulerEnabled {
+		go runScheduler(ctx, waitGroup, config, store)
+	}
+	runGatewayServer(ctx, waitGroup, config, store, taskDistributor, taskInspector, activityHub)
 
 	if err := waitGroup.Wait(); err != nil {
 		log.Fatal("service exit with error:", err)
@@ -93,8 +124,10 @@ func runGatewayServer(
 	config util.Config,
 	store db.Store,
 	distributor worker.TaskDistributor,
+	inspector worker.TaskInspector,
+	activityHub *activity.Hub,
 ) {
-	server, err := gapi.NewServer(config, store, distributor)
+	server, err := gapi.NewServer(config, store, distributor, inspector, activityHub)
 	if err != nil {
 		log.Fatal("cannot create server:", err)
 	}
@@ -118,6 +151,8 @@ func runGatewayServer(
 
 	mux := http.NewServeMux()
 	mux.Handle("/", grpcMux)
+	// 网关不支持流式接口，浏览器通过 SSE 订阅
+	mux.HandleFunc("/v1/watch_account", server.WatchAccountSSE)
 
 	subFS, err := fs.Sub(doc.SwaggerFiles, "swagger")
 	if err != nil {
@@ -183,14 +218,17 @@ func runGrpcServer(
 	config util.Config,
 	store db.Store,
 	distributor worker.TaskDistributor,
+	inspector worker.TaskInspector,
+	activityHub *activity.Hub,
 ) {
-	server, err := gapi.NewServer(config, store, distributor)
+	server, err := gapi.NewServer(config, store, distributor, inspector, activityHub)
 	if err != nil {
 		log.Fatal("cannot create server:", err)
 	}
 
 	grpcLogger := grpc.UnaryInterceptor(gapi.GrpcLogger)
-	grpcServer := grpc.NewServer(grpcLogger)
+	grpcStreamLogger := grpc.StreamInterceptor(gapi.GrpcStreamLogger)
+	grpcServer := grpc.NewServer(grpcLogger, grpcStreamLogger)
 
 	pb.RegisterSimpleBankServer(grpcServer, server)
 
@@ -225,11 +263,17 @@ func runTaskProcessor(
 	ctx context.Context,
 	waitGroup *errgroup.Group,
 	config util.Config,
-	redisOpt asynq.RedisClientOpt,
+	backend worker.TaskBackend,
 	store db.Store,
 	mailer mail.EmailSender,
+	distributor worker.TaskDistributor,
 ) {
-	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, mailer)
+	network, err := newSettlementNetwork(config, distributor)
+	if err != nil {
+		log.Fatal("cannot create settlement network:", err)
+	}
+
+	taskProcessor := worker.NewTaskProcessor(backend, store, mailer, network, distributor)
 
 	waitGroup.Go(func() error {
 		log.Printf("start task processor")
@@ -249,3 +293,75 @@ func runTaskProcessor(
 		return nil
 	})
 }
+
+func runOutboxRelay(
+	ctx context.Context,
+	waitGroup *errgroup.Group,
+	config util.Config,
+	backend worker.TaskBackend,
+	store db.Store,
+) {
+	relay := worker.NewOutboxRelay(backend.Enqueuer(), store, config.OutboxPollInterval)
+
+	waitGroup.Go(func() error {
+		log.Printf("start outbox relay")
+		err := relay.Start(ctx)
+		relay.Shutdown()
+		log.Println("outbox relay stopped")
+		return err
+	})
+}
+
+// runScheduler 每个实例都可以开，同一时间只有一个实例投递周期任务
+func runScheduler(
+	ctx context.Context,
+	waitGroup *errgroup.Group,
+	config util.Config,
+	store db.Store,
+) {
+	jobs, err := worker.PeriodicJobs(config)
+	if err != nil {
+		log.Fatal("cannot load periodic jobs:", err)
+	}
+
+	scheduler, err := worker.NewScheduler(store, jobs)
+	if err != nil {
+		log.Fatal("cannot create scheduler:", err)
+	}
+
+	waitGroup.Go(func() error {
+		log.Printf("start periodic scheduler")
+		err := scheduler.Start(ctx)
+		log.Println("periodic scheduler stopped")
+		return err
+	})
+}
+
+func runActivityListener(
+	ctx context.Context,
+	waitGroup *errgroup.Group,
+	config util.Config,
+	hub *activity.Hub,
+) {
+	waitGroup.Go(func() error {
+		log.Printf("start account activity listener")
+		err := activity.Listen(ctx, config.DBSource, hub)
+		hub.Close()
+		log.Println("account activity listener stopped")
+		return err
+	})
+}
+
+// newSettlementNetwork 按配置选清算网络，接入真实网络时在这里加实现
+func newSettlementNetwork(config util.Config, distributor worker.TaskDistributor) (settlement.SettlementNetwork, error) {
+	switch config.SettlementNetwork {
+	case settlement.SimulatorNetworkName:
+		return worker.NewSimulatedNetwork(distributor, config.SettlementSimulatorDelay), nil
+	case nacha.NetworkName:
+		if err := nacha.OriginFromConfig(config).Validate(); err != nil {
+			return nil, err
+		}
+		return settlement.NewBatchNetwork(nacha.NetworkName), nil
+	}
+	return nil, fmt.Errorf("unsupported settlement network %q", config.SettlementNetwork)
+}
@@ -0,0 +1,226 @@
+package nacha
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// 只发贷记，服务类别码 220
+	serviceClassCreditsOnly = "220"
+	// 对个人账户的付款
+	standardEntryClassPPD = "PPD"
+	// 贷记支票账户
+	transactionCodeCheckingCredit = "22"
+	// 金额字段 10 位，以分为单位
+	maxEntryAmount = 9_999_999_999
+)
+
+var fileIDModifiers = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// Origin 发起行 (ODFI) 和发起公司的信息，来自配置
+type Origin struct {
+	// 接收文件的 ACH operator 路由号
+	ImmediateDestination     string
+	ImmediateDestinationName string
+	// 本行路由号
+	RoutingNumber string
+	Name          string
+	CompanyID     string
+	CompanyName   string
+}
+
+func (origin Origin) Validate() error {
+	if !ValidRoutingNumber(origin.ImmediateDestination) {
+		return fmt.Errorf("invalid immediate destination %q", origin.ImmediateDestination)
+	}
+	if !ValidRoutingNumber(origin.RoutingNumber) {
+		return fmt.Errorf("invalid origin routing number %q", origin.RoutingNumber)
+	}
+	if origin.CompanyID == "" || len(origin.CompanyID) > 10 {
+		return fmt.Errorf("company id must be 1 to 10 characters")
+	}
+	if origin.CompanyName == "" {
+		return errors.New("company name is required")
+	}
+	return nil
+}
+
+// Entry 一笔付款
+type Entry struct {
+	// 收款行 (RDFI) 的 9 位路由号
+	RoutingNumber  string
+	AccountNumber  string
+	Amount         int64
+	IndividualID   string
+	IndividualName string
+	TraceNumber    string
+}
+
+// File 只含一个贷记批次的出账文件
+type File struct {
+	Origin         Origin
+	CreatedAt      time.Time
+	FileIDModifier string
+	EffectiveDate  time.Time
+	Entries        []Entry
+}
+
+func (file *File) TotalCredit() int64 {
+	var total int64
+	for _, entry := range file.Entries {
+		total += entry.Amount
+	}
+	return total
+}
+
+// Marshal 生成定长文本，行尾用 \n，最后补 9 填满 block
+func (file *File) Marshal() ([]byte, error) {
+	if err := file.Origin.Validate(); err != nil {
+		return nil, err
+	}
+	if len(file.FileIDModifier) != 1 || !strings.Contains(fileIDModifiers, file.FileIDModifier) {
+		return nil, fmt.Errorf("invalid file id modifier %q", file.FileIDModifier)
+	}
+	if len(file.Entries) == 0 {
+		return nil, errors.New("file has no entries")
+	}
+
+	records := []string{file.fileHeader(), file.batchHeader()}
+
+	var entryHash int64
+	for _, entry := range file.Entries {
+		record, err := entry.record()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+
+		// entry hash 是收款行路由号前 8 位之和
+		prefix, _ := strconv.ParseInt(entry.RoutingNumber[:8], 10, 64)
+		entryHash += prefix
+	}
+
+	records = append(records, file.batchControl(entryHash), file.fileControl(len(records)+2, entryHash))
+	for len(records)%blockingFactor != 0 {
+		records = append(records, strings.Repeat("9", recordLength))
+	}
+
+	return []byte(strings.Join(records, "\n") + "\n"), nil
+}
+
+func (file *File) fileHeader() string {
+	return string(fileHeaderRecord) +
+		"01" +
+		" " + file.Origin.ImmediateDestination +
+		" " + file.Origin.RoutingNumber +
+		file.CreatedAt.Format("060102") +
+		file.CreatedAt.Format("1504") +
+		file.FileIDModifier +
+		"094" +
+		numeric(blockingFactor, 2) +
+		"1" +
+		alphanumeric(file.Origin.ImmediateDestinationName, 23) +
+		alphanumeric(file.Origin.Name, 23) +
+		alphanumeric("", 8)
+}
+
+func (file *File) batchHeader() string {
+	return string(batchHeaderRecord) +
+		serviceClassCreditsOnly +
+		alphanumeric(file.Origin.CompanyName, 16) +
+		alphanumeric("", 20) +
+		alphanumeric(file.Origin.CompanyID, 10) +
+		standardEntryClassPPD +
+		alphanumeric("PAYMENT", 10) +
+		alphanumeric("", 6) +
+		file.EffectiveDate.Format("060102") +
+		alphanumeric("", 3) +
+		"1" +
+		file.Origin.RoutingNumber[:8] +
+		numeric(1, 7)
+}
+
+// Validate 检查能不能写进文件，不合格的付款导出时直接判失败
+func (entry Entry) Validate() error {
+	if !ValidRoutingNumber(entry.RoutingNumber) {
+		return fmt.Errorf("invalid routing number %q", entry.RoutingNumber)
+	}
+	if entry.Amount <= 0 || entry.Amount > maxEntryAmount {
+		return fmt.Errorf("amount %d out of range", entry.Amount)
+	}
+	if len(entry.AccountNumber) == 0 || len(entry.AccountNumber) > 17 {
+		return fmt.Errorf("account number must be 1 to 17 characters")
+	}
+	if len(entry.TraceNumber) != 15 {
+		return fmt.Errorf("invalid trace number %q", entry.TraceNumber)
+	}
+	return nil
+}
+
+func (entry Entry) record() (string, error) {
+	if err := entry.Validate(); err != nil {
+		return "", fmt.Errorf("trace %s: %w", entry.TraceNumber, err)
+	}
+
+	return string(entryDetailRecord) +
+		transactionCodeCheckingCredit +
+		entry.RoutingNumber +
+		alphanumeric(entry.AccountNumber, 17) +
+		numeric(entry.Amount, 10) +
+		alphanumeric(entry.IndividualID, 15) +
+		alphanumeric(entry.IndividualName, 22) +
+		alphanumeric("", 2) +
+		"0" +
+		entry.TraceNumber, nil
+}
+
+func (file *File) batchControl(entryHash int64) string {
+	return string(batchControlRecord) +
+		serviceClassCreditsOnly +
+		numeric(int64(len(file.Entries)), 6) +
+		numeric(entryHash, 10) +
+		numeric(0, 12) +
+		numeric(file.TotalCredit(), 12) +
+		alphanumeric(file.Origin.CompanyID, 10) +
+		alphanumeric("", 19) +
+		alphanumeric("", 6) +
+		file.Origin.RoutingNumber[:8] +
+		numeric(1, 7)
+}
+
+// fileControl 的 block 数要算上自己，recordCount 是补 9 之前的总行数
+func (file *File) fileControl(recordCount int, entryHash int64) string {
+	blocks := (recordCount + blockingFactor - 1) / blockingFactor
+	return string(fileControlRecord) +
+		numeric(1, 6) +
+		numeric(int64(blocks), 6) +
+		numeric(int64(len(file.Entries)), 8) +
+		numeric(entryHash, 10) +
+		numeric(0, 12) +
+		numeric(file.TotalCredit(), 12) +
+		alphanumeric("", 39)
+}
+
+// TraceNumber 本行路由号前 8 位加 7 位序号
+func TraceNumber(originRoutingNumber string, sequence int64) string {
+	return originRoutingNumber[:8] + numeric(sequence, 7)
+}
+
+// FileIDModifier 同一天的第 n 个文件（从 0 开始）用 A-Z、0-9 区分，超过 36 个从头循环
+func FileIDModifier(n int64) string {
+	i := n % int64(len(fileIDModifiers))
+	return fileIDModifiers[i : i+1]
+}
+
+// EffectiveEntryDate 下一个工作日入账，不考虑节假日
+func EffectiveEntryDate(t time.Time) time.Time {
+	date := t.AddDate(0, 0, 1)
+	for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date
+}
@@ -0,0 +1,130 @@
+package nacha
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test ./nacha -update 重新生成 testdata 里的 golden 文件
+var update = flag.Bool("update", false, "update golden files")
+
+var testOrigin = Origin{
+	ImmediateDestination:     "011000015",
+	ImmediateDestinationName: "Federal Reserve Bank",
+	RoutingNumber:            "026009593",
+	Name:                     "Simple Bank",
+	CompanyID:                "1234567890",
+	CompanyName:              "Simple Bank",
+}
+
+func requireGolden(t *testing.T, name string, actual []byte) {
+	golden := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(golden, actual, 0o644))
+	}
+
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual))
+}
+
+func TestFileMarshal(t *testing.T) {
+	// 周五生成的文件下周一入账
+	createdAt := time.Date(2026, time.October, 16, 17, 45, 0, 0, time.UTC)
+
+	file := File{
+		Origin:         testOrigin,
+		CreatedAt:      createdAt,
+		FileIDModifier: FileIDModifier(1),
+		EffectiveDate:  EffectiveEntryDate(createdAt),
+		Entries: []Entry{
+			{
+				RoutingNumber:  "021000021",
+				AccountNumber:  "EXTB0000000042",
+				Amount:         12500,
+				IndividualID:   "101",
+				IndividualName: "Jane Doe",
+				TraceNumber:    TraceNumber(testOrigin.RoutingNumber, 1),
+			},
+			{
+				RoutingNumber:  "011000015",
+				AccountNumber:  "EXTC0000000005",
+				Amount:         990,
+				IndividualID:   "103",
+				IndividualName: "A Creditor Name Longer Than Twenty Two",
+				TraceNumber:    TraceNumber(testOrigin.RoutingNumber, 2),
+			},
+		},
+	}
+	require.Equal(t, int64(13490), file.TotalCredit())
+
+	content, err := file.Marshal()
+	require.NoError(t, err)
+	requireGolden(t, "outbound.ach", content)
+
+	records := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	require.Len(t, records, blockingFactor)
+	for _, record := range records {
+		require.Len(t, record, recordLength)
+	}
+}
+
+func TestFileMarshalInvalid(t *testing.T) {
+	entry := Entry{
+		RoutingNumber:  "021000021",
+		AccountNumber:  "EXTB0000000042",
+		Amount:         100,
+		IndividualName: "Jane Doe",
+		TraceNumber:    TraceNumber(testOrigin.RoutingNumber, 1),
+	}
+
+	testCases := []struct {
+		name   string
+		modify func(file *File)
+	}{
+		{"NoEntries", func(file *File) { file.Entries = nil }},
+		{"InvalidOrigin", func(file *File) { file.Origin.RoutingNumber = "123" }},
+		{"InvalidFileIDModifier", func(file *File) { file.FileIDModifier = "a" }},
+		{"InvalidRoutingNumber", func(file *File) { file.Entries[0].RoutingNumber = "021000022" }},
+		{"AmountTooLarge", func(file *File) { file.Entries[0].Amount = maxEntryAmount + 1 }},
+		{"AccountNumberTooLong", func(file *File) { file.Entries[0].AccountNumber = strings.Repeat("1", 18) }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file := File{
+				Origin:         testOrigin,
+				CreatedAt:      time.Now(),
+				FileIDModifier: "A",
+				EffectiveDate:  time.Now(),
+				Entries:        []Entry{entry},
+			}
+			tc.modify(&file)
+
+			_, err := file.Marshal()
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestEffectiveEntryDate(t *testing.T) {
+	friday := time.Date(2026, time.October, 16, 10, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Monday, EffectiveEntryDate(friday).Weekday())
+	require.Equal(t, 19, EffectiveEntryDate(friday).Day())
+
+	tuesday := time.Date(2026, time.October, 20, 10, 0, 0, 0, time.UTC)
+	require.Equal(t, 21, EffectiveEntryDate(tuesday).Day())
+}
+
+func TestFileIDModifier(t *testing.T) {
+	require.Equal(t, "A", FileIDModifier(0))
+	require.Equal(t, "Z", FileIDModifier(25))
+	require.Equal(t, "0", FileIDModifier(26))
+	require.Equal(t, "A", FileIDModifier(36))
+}
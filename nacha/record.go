@@ -0,0 +1,42 @@
+package nacha
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 所有记录都是 94 个字符的定长行，每 10 行一个 block
+const (
+	recordLength   = 94
+	blockingFactor = 10
+)
+
+// 记录类型，即每行的第一个字符
+const (
+	fileHeaderRecord   = '1'
+	batchHeaderRecord  = '5'
+	entryDetailRecord  = '6'
+	addendaRecord      = '7'
+	batchControlRecord = '8'
+	fileControlRecord  = '9'
+)
+
+// alphanumeric 字母数字字段左对齐右补空格，超长截断，统一大写
+func alphanumeric(value string, width int) string {
+	value = strings.ToUpper(value)
+	if len(value) > width {
+		return value[:width]
+	}
+	return value + strings.Repeat(" ", width-len(value))
+}
+
+// numeric 数字字段右对齐左补 0，超长时只保留低位（entry hash 就是这么规定的）
+func numeric(value int64, width int) string {
+	s := fmt.Sprintf("%0*d", width, value)
+	return s[len(s)-width:]
+}
+
+// field 按 NACHA 文档里从 1 开始的闭区间位置取字段
+func field(record string, start int, end int) string {
+	return record[start-1 : end]
+}
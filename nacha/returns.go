@@ -0,0 +1,114 @@
+package nacha
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// 退回原因码的说明，只列常见的几个
+var returnReasons = map[string]string{
+	"R01": "Insufficient Funds",
+	"R02": "Account Closed",
+	"R03": "No Account/Unable to Locate Account",
+	"R04": "Invalid Account Number Structure",
+	"R06": "Returned per ODFI's Request",
+	"R07": "Authorization Revoked by Customer",
+	"R08": "Payment Stopped",
+	"R10": "Customer Advises Not Authorized",
+	"R16": "Account Frozen",
+	"R20": "Non-Transaction Account",
+	"R23": "Credit Entry Refused by Receiver",
+	"R29": "Corporate Customer Advises Not Authorized",
+}
+
+// ReturnReason 原因码的说明，不认识的码原样返回
+func ReturnReason(code string) string {
+	if reason, ok := returnReasons[code]; ok {
+		return code + " " + reason
+	}
+	return code
+}
+
+// Return 收款行退回的一笔付款，用原始 trace number 找回本行的转账
+type Return struct {
+	ReturnCode          string `json:"return_code"`
+	OriginalTraceNumber string `json:"original_trace_number"`
+	Amount              int64  `json:"amount"`
+	AccountNumber       string `json:"account_number"`
+	IndividualID        string `json:"individual_id"`
+	AddendaInformation  string `json:"addenda_information"`
+}
+
+// ParseReturns 解析退回文件，只关心 entry 和紧跟着的 99 类 addenda
+// 变更通知 (98 类 addenda) 不是退回，直接跳过
+func ParseReturns(r io.Reader) ([]Return, error) {
+	var returns []Return
+	var entry string
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		record := strings.TrimRight(scanner.Text(), "\r")
+		if record == "" {
+			continue
+		}
+		if len(record) != recordLength {
+			return nil, fmt.Errorf("line %d: record must be %d characters, got %d", line, recordLength, len(record))
+		}
+
+		switch record[0] {
+		case entryDetailRecord:
+			if entry != "" {
+				return nil, fmt.Errorf("line %d: entry without return addenda", line-1)
+			}
+			if field(record, 79, 79) != "1" {
+				return nil, fmt.Errorf("line %d: return entry must have an addenda record", line)
+			}
+			entry = record
+		case addendaRecord:
+			if entry == "" {
+				return nil, fmt.Errorf("line %d: addenda without entry", line)
+			}
+			if field(record, 2, 3) == "99" {
+				ret, err := parseReturn(entry, record)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", line, err)
+				}
+				returns = append(returns, ret)
+			}
+			entry = ""
+		default:
+			if entry != "" {
+				return nil, fmt.Errorf("line %d: entry without return addenda", line-1)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if entry != "" {
+		return nil, fmt.Errorf("line %d: entry without return addenda", line)
+	}
+
+	return returns, nil
+}
+
+func parseReturn(entry string, addenda string) (Return, error) {
+	amount, err := strconv.ParseInt(field(entry, 30, 39), 10, 64)
+	if err != nil {
+		return Return{}, fmt.Errorf("invalid amount %q", field(entry, 30, 39))
+	}
+
+	return Return{
+		ReturnCode:          field(addenda, 4, 6),
+		OriginalTraceNumber: field(addenda, 7, 21),
+		Amount:              amount,
+		AccountNumber:       strings.TrimSpace(field(entry, 13, 29)),
+		IndividualID:        strings.TrimSpace(field(entry, 40, 54)),
+		AddendaInformation:  strings.TrimSpace(field(addenda, 36, 79)),
+	}, nil
+}
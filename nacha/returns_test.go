@@ -0,0 +1,54 @@
+package nacha
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReturns(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "returns.ach"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	returns, err := ParseReturns(file)
+	require.NoError(t, err)
+	// 变更通知不算退回
+	require.Len(t, returns, 2)
+
+	content, err := json.MarshalIndent(returns, "", "  ")
+	require.NoError(t, err)
+	requireGolden(t, "returns.golden.json", append(content, '\n'))
+}
+
+func TestParseReturnsInvalid(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "returns.ach"))
+	require.NoError(t, err)
+	records := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+
+	testCases := []struct {
+		name    string
+		records []string
+	}{
+		{"ShortRecord", []string{records[0][:93]}},
+		{"EntryWithoutAddenda", []string{records[2], records[4], records[5]}},
+		{"AddendaWithoutEntry", []string{records[3]}},
+		{"MissingAddendaAtEnd", []string{records[2]}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseReturns(strings.NewReader(strings.Join(tc.records, "\n")))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestReturnReason(t *testing.T) {
+	require.Equal(t, "R01 Insufficient Funds", ReturnReason("R01"))
+	require.Equal(t, "R99", ReturnReason("R99"))
+}
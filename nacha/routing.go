@@ -0,0 +1,19 @@
+package nacha
+
+import "regexp"
+
+var isRoutingNumber = regexp.MustCompile(`^[0-9]{9}$`).MatchString
+
+// ValidRoutingNumber 校验 9 位 ABA 路由号，最后一位是按 3-7-1 权重算的校验位
+func ValidRoutingNumber(routingNumber string) bool {
+	if !isRoutingNumber(routingNumber) {
+		return false
+	}
+
+	weights := []int{3, 7, 1, 3, 7, 1, 3, 7, 1}
+	sum := 0
+	for i, c := range routingNumber {
+		sum += int(c-'0') * weights[i]
+	}
+	return sum%10 == 0
+}
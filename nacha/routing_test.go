@@ -0,0 +1,28 @@
+package nacha
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidRoutingNumber(t *testing.T) {
+	testCases := []struct {
+		name          string
+		routingNumber string
+		valid         bool
+	}{
+		{"OK", "021000021", true},
+		{"OKLeadingZero", "011000015", true},
+		{"WrongCheckDigit", "021000022", false},
+		{"TooShort", "02100002", false},
+		{"NotDigits", "02100002A", false},
+		{"Empty", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.valid, ValidRoutingNumber(tc.routingNumber))
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package nacha
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	db "simplebank/db/sqlc"
+	"simplebank/util"
+	"strconv"
+	"time"
+)
+
+// NetworkName 批量文件网络，转账留在 initiated，运营导出文件后才变成 pending
+const NetworkName = "nacha"
+
+var ErrNoPayments = errors.New("no outbound payments to export")
+
+// Service 把 initiated 的付款导出成批量文件，并按退回文件冲正
+type Service struct {
+	store  db.Store
+	origin Origin
+}
+
+func NewService(store db.Store, origin Origin) *Service {
+	return &Service{
+		store:  store,
+		origin: origin,
+	}
+}
+
+func OriginFromConfig(config util.Config) Origin {
+	return Origin{
+		ImmediateDestination:     config.ACHImmediateDestination,
+		ImmediateDestinationName: config.ACHImmediateDestinationName,
+		RoutingNumber:            config.ACHOriginRoutingNumber,
+		Name:                     config.ACHOriginName,
+		CompanyID:                config.ACHCompanyID,
+		CompanyName:              config.ACHCompanyName,
+	}
+}
+
+// DFIAccountNumber 文件里的账号字段只有 17 位，去掉国家码和校验码只放银行代码加账号
+func DFIAccountNumber(accountNumber string) string {
+	if len(accountNumber) <= 4 {
+		return accountNumber
+	}
+	return accountNumber[4:]
+}
+
+// Export 导出所有 initiated 的付款，文件和状态变更在同一个事务里保存
+// 写不进文件的付款（比如路由号不对）直接判失败退款
+func (service *Service) Export(ctx context.Context, now time.Time) (db.CreateACHFileTxResult, error) {
+	var result db.CreateACHFileTxResult
+
+	if err := service.origin.Validate(); err != nil {
+		return result, fmt.Errorf("ACH origin is not configured: %w", err)
+	}
+
+	rows, err := service.store.ListInitiatedExternalPayments(ctx, NetworkName)
+	if err != nil {
+		return result, fmt.Errorf("failed to list payments: %w", err)
+	}
+
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	count, err := service.store.CountACHFilesSince(ctx, startOfDay)
+	if err != nil {
+		return result, fmt.Errorf("failed to count files: %w", err)
+	}
+
+	file := File{
+		Origin:         service.origin,
+		CreatedAt:      now,
+		FileIDModifier: FileIDModifier(count),
+		EffectiveDate:  EffectiveEntryDate(now),
+	}
+	var entries []db.ACHFileEntry
+
+	for _, row := range rows {
+		sequence, err := service.store.NextACHTraceSequence(ctx)
+		if err != nil {
+			return result, fmt.Errorf("failed to get trace number: %w", err)
+		}
+
+		entry := Entry{
+			RoutingNumber:  row.ExternalPayment.CreditorRoutingNumber,
+			AccountNumber:  DFIAccountNumber(row.ExternalPayment.CreditorAccountNumber),
+			Amount:         row.Transfer.Amount,
+			IndividualID:   strconv.FormatInt(row.Transfer.ID, 10),
+			IndividualName: row.ExternalPayment.CreditorName,
+			TraceNumber:    TraceNumber(service.origin.RoutingNumber, sequence),
+		}
+		if err := entry.Validate(); err != nil {
+			service.reject(ctx, row.Transfer.ID, err)
+			continue
+		}
+
+		file.Entries = append(file.Entries, entry)
+		entries = append(entries, db.ACHFileEntry{
+			TransferID:  row.Transfer.ID,
+			TraceNumber: entry.TraceNumber,
+		})
+	}
+
+	if len(entries) == 0 {
+		return result, ErrNoPayments
+	}
+
+	content, err := file.Marshal()
+	if err != nil {
+		return result, fmt.Errorf("failed to generate file: %w", err)
+	}
+
+	return service.store.CreateACHFileTx(ctx, db.CreateACHFileTxParams{
+		FileIDModifier: file.FileIDModifier,
+		TotalCredit:    file.TotalCredit(),
+		Content:        string(content),
+		Entries:        entries,
+	})
+}
+
+func (service *Service) reject(ctx context.Context, transferID int64, reason error) {
+	_, err := service.store.ReverseExternalTransferTx(ctx, db.ReverseExternalTransferTxParams{
+		TransferID: transferID,
+		Status:     util.TransferStatusFailed,
+		Reason:     reason.Error(),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to reject ACH payment",
+			slog.Int64("transfer_id", transferID),
+			slog.String("reason", reason.Error()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// ReturnResult 一笔退回的处理结果，Status 是转账的新状态，处理失败时为空
+type ReturnResult struct {
+	Return
+	TransferID int64  `json:"transfer_id"`
+	Status     string `json:"status"`
+	Error      string `json:"error"`
+}
+
+// ProcessReturns 逐笔冲正，单笔出错不影响其他退回
+// 同一个文件重复处理时，已经冲正过的会因为状态不对报错，不会重复退款
+func (service *Service) ProcessReturns(ctx context.Context, r io.Reader) ([]ReturnResult, error) {
+	returns, err := ParseReturns(r)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ReturnResult, 0, len(returns))
+	for _, ret := range returns {
+		result := ReturnResult{Return: ret}
+
+		transfer, err := service.applyReturn(ctx, ret)
+		result.TransferID = transfer.ID
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Status = transfer.Status
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// applyReturn 还没清算的判失败，已经清算的判退回，都把金额退给客户
+func (service *Service) applyReturn(ctx context.Context, ret Return) (db.Transfer, error) {
+	payment, err := service.store.GetExternalPaymentByReference(ctx, db.GetExternalPaymentByReferenceParams{
+		Network:          NetworkName,
+		NetworkReference: sql.NullString{String: ret.OriginalTraceNumber, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Transfer{}, fmt.Errorf("no payment with trace number %s", ret.OriginalTraceNumber)
+		}
+		return db.Transfer{}, fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	transfer, err := service.store.GetTransfer(ctx, payment.TransferID)
+	if err != nil {
+		return transfer, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	if transfer.Amount != ret.Amount {
+		return transfer, fmt.Errorf("returned amount %d does not match transfer amount %d", ret.Amount, transfer.Amount)
+	}
+
+	status := util.TransferStatusFailed
+	if transfer.Status == util.TransferStatusSettled {
+		status = util.TransferStatusReturned
+	}
+
+	result, err := service.store.ReverseExternalTransferTx(ctx, db.ReverseExternalTransferTxParams{
+		TransferID: transfer.ID,
+		Status:     status,
+		Reason:     ReturnReason(ret.ReturnCode),
+	})
+	if err != nil {
+		return transfer, err
+	}
+	return result.Transfer, nil
+}
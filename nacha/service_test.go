@@ -0,0 +1,137 @@
+package nacha
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	mockdb "simplebank/db/mock"
+	db "simplebank/db/sqlc"
+	"simplebank/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestServiceExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	service := NewService(store, testOrigin)
+
+	now := time.Date(2026, time.October, 16, 17, 45, 0, 0, time.UTC)
+	rows := []db.ListInitiatedExternalPaymentsRow{
+		{
+			ExternalPayment: db.ExternalPayment{
+				TransferID:            101,
+				CreditorAccountNumber: "SB27EXTB0000000042",
+				CreditorName:          "Jane Doe",
+				CreditorRoutingNumber: "021000021",
+			},
+			Transfer: db.Transfer{ID: 101, Amount: 12500, Status: util.TransferStatusInitiated},
+		},
+		{
+			ExternalPayment: db.ExternalPayment{
+				TransferID:            102,
+				CreditorAccountNumber: "SB27EXTB0000000077",
+				CreditorName:          "John Roe",
+				CreditorRoutingNumber: "021000022",
+			},
+			Transfer: db.Transfer{ID: 102, Amount: 300, Status: util.TransferStatusInitiated},
+		},
+	}
+
+	store.EXPECT().ListInitiatedExternalPayments(gomock.Any(), NetworkName).Return(rows, nil)
+	store.EXPECT().CountACHFilesSince(gomock.Any(), time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC)).Return(int64(2), nil)
+	gomock.InOrder(
+		store.EXPECT().NextACHTraceSequence(gomock.Any()).Return(int64(7), nil),
+		store.EXPECT().NextACHTraceSequence(gomock.Any()).Return(int64(8), nil),
+	)
+
+	// 路由号校验位不对，直接判失败
+	store.EXPECT().
+		ReverseExternalTransferTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.ReverseExternalTransferTxParams) (db.ExternalTransferTxResult, error) {
+			require.Equal(t, int64(102), arg.TransferID)
+			require.Equal(t, util.TransferStatusFailed, arg.Status)
+			require.Contains(t, arg.Reason, "routing number")
+			return db.ExternalTransferTxResult{}, nil
+		})
+
+	store.EXPECT().
+		CreateACHFileTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateACHFileTxParams) (db.CreateACHFileTxResult, error) {
+			require.Equal(t, "C", arg.FileIDModifier)
+			require.Equal(t, int64(12500), arg.TotalCredit)
+			require.Equal(t, []db.ACHFileEntry{{TransferID: 101, TraceNumber: "026009590000007"}}, arg.Entries)
+			requireGolden(t, "export.ach", []byte(arg.Content))
+
+			return db.CreateACHFileTxResult{ACHFile: db.AchFile{ID: 1, Content: arg.Content}}, nil
+		})
+
+	result, err := service.Export(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.ACHFile.ID)
+}
+
+func TestServiceExportNoPayments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().ListInitiatedExternalPayments(gomock.Any(), NetworkName).Return(nil, nil)
+	store.EXPECT().CountACHFilesSince(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+	store.EXPECT().CreateACHFileTx(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := NewService(store, testOrigin).Export(context.Background(), time.Now())
+	require.ErrorIs(t, err, ErrNoPayments)
+
+	// 没配置发起行时不查库
+	_, err = NewService(store, Origin{}).Export(context.Background(), time.Now())
+	require.Error(t, err)
+}
+
+func TestServiceProcessReturns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		GetExternalPaymentByReference(gomock.Any(), db.GetExternalPaymentByReferenceParams{
+			Network:          NetworkName,
+			NetworkReference: sql.NullString{String: "011000010000001", Valid: true},
+		}).
+		Return(db.ExternalPayment{TransferID: 101}, nil)
+	store.EXPECT().
+		GetTransfer(gomock.Any(), int64(101)).
+		Return(db.Transfer{ID: 101, Amount: 12500, Status: util.TransferStatusSettled}, nil)
+	store.EXPECT().
+		ReverseExternalTransferTx(gomock.Any(), db.ReverseExternalTransferTxParams{
+			TransferID: 101,
+			Status:     util.TransferStatusReturned,
+			Reason:     "R01 Insufficient Funds",
+		}).
+		Return(db.ExternalTransferTxResult{Transfer: db.Transfer{ID: 101, Status: util.TransferStatusReturned}}, nil)
+
+	store.EXPECT().
+		GetExternalPaymentByReference(gomock.Any(), db.GetExternalPaymentByReferenceParams{
+			Network:          NetworkName,
+			NetworkReference: sql.NullString{String: "011000010000002", Valid: true},
+		}).
+		Return(db.ExternalPayment{}, sql.ErrNoRows)
+
+	file, err := os.Open(filepath.Join("testdata", "returns.ach"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	results, err := NewService(store, testOrigin).ProcessReturns(context.Background(), file)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, int64(101), results[0].TransferID)
+	require.Equal(t, util.TransferStatusReturned, results[0].Status)
+	require.Empty(t, results[0].Error)
+
+	require.Zero(t, results[1].TransferID)
+	require.Empty(t, results[1].Status)
+	require.Contains(t, results[1].Error, "no payment")
+}
@@ -0,0 +1,269 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: ach_file.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 导出的批量付款文件，内容是 NACHA 定长格式
+type ACHFile struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FileIdModifier string                 `protobuf:"bytes,2,opt,name=file_id_modifier,json=fileIdModifier,proto3" json:"file_id_modifier,omitempty"`
+	EntryCount     int32                  `protobuf:"varint,3,opt,name=entry_count,json=entryCount,proto3" json:"entry_count,omitempty"`
+	TotalCredit    int64                  `protobuf:"varint,4,opt,name=total_credit,json=totalCredit,proto3" json:"total_credit,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ACHFile) Reset() {
+	*x = ACHFile{}
+	mi := &file_ach_file_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ACHFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ACHFile) ProtoMessage() {}
+
+func (x *ACHFile) ProtoReflect() protoreflect.Message {
+	mi := &file_ach_file_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ACHFile.ProtoReflect.Descriptor instead.
+func (*ACHFile) Descriptor() ([]byte, []int) {
+	return file_ach_file_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ACHFile) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ACHFile) GetFileIdModifier() string {
+	if x != nil {
+		return x.FileIdModifier
+	}
+	return ""
+}
+
+func (x *ACHFile) GetEntryCount() int32 {
+	if x != nil {
+		return x.EntryCount
+	}
+	return 0
+}
+
+func (x *ACHFile) GetTotalCredit() int64 {
+	if x != nil {
+		return x.TotalCredit
+	}
+	return 0
+}
+
+func (x *ACHFile) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// 退回文件里的一笔退回及处理结果
+type ACHReturn struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	OriginalTraceNumber string                 `protobuf:"bytes,1,opt,name=original_trace_number,json=originalTraceNumber,proto3" json:"original_trace_number,omitempty"`
+	ReturnCode          string                 `protobuf:"bytes,2,opt,name=return_code,json=returnCode,proto3" json:"return_code,omitempty"`
+	Reason              string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Amount              int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	TransferId          int64                  `protobuf:"varint,5,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	// 转账的新状态，处理失败时为空
+	Status        string `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ACHReturn) Reset() {
+	*x = ACHReturn{}
+	mi := &file_ach_file_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ACHReturn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ACHReturn) ProtoMessage() {}
+
+func (x *ACHReturn) ProtoReflect() protoreflect.Message {
+	mi := &file_ach_file_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ACHReturn.ProtoReflect.Descriptor instead.
+func (*ACHReturn) Descriptor() ([]byte, []int) {
+	return file_ach_file_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ACHReturn) GetOriginalTraceNumber() string {
+	if x != nil {
+		return x.OriginalTraceNumber
+	}
+	return ""
+}
+
+func (x *ACHReturn) GetReturnCode() string {
+	if x != nil {
+		return x.ReturnCode
+	}
+	return ""
+}
+
+func (x *ACHReturn) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ACHReturn) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ACHReturn) GetTransferId() int64 {
+	if x != nil {
+		return x.TransferId
+	}
+	return 0
+}
+
+func (x *ACHReturn) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ACHReturn) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_ach_file_proto protoreflect.FileDescriptor
+
+const file_ach_file_proto_rawDesc = "" +
+	"\n" +
+	"\x0each_file.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc2\x01\n" +
+	"\aACHFile\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12(\n" +
+	"\x10file_id_modifier\x18\x02 \x01(\tR\x0efileIdModifier\x12\x1f\n" +
+	"\ventry_count\x18\x03 \x01(\x05R\n" +
+	"entryCount\x12!\n" +
+	"\ftotal_credit\x18\x04 \x01(\x03R\vtotalCredit\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xdf\x01\n" +
+	"\tACHReturn\x122\n" +
+	"\x15original_trace_number\x18\x01 \x01(\tR\x13originalTraceNumber\x12\x1f\n" +
+	"\vreturn_code\x18\x02 \x01(\tR\n" +
+	"returnCode\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x03R\x06amount\x12\x1f\n" +
+	"\vtransfer_id\x18\x05 \x01(\x03R\n" +
+	"transferId\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05errorB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_ach_file_proto_rawDescOnce sync.Once
+	file_ach_file_proto_rawDescData []byte
+)
+
+func file_ach_file_proto_rawDescGZIP() []byte {
+	file_ach_file_proto_rawDescOnce.Do(func() {
+		file_ach_file_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ach_file_proto_rawDesc), len(file_ach_file_proto_rawDesc)))
+	})
+	return file_ach_file_proto_rawDescData
+}
+
+var file_ach_file_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_ach_file_proto_goTypes = []any{
+	(*ACHFile)(nil),               // 0: pb.ACHFile
+	(*ACHReturn)(nil),             // 1: pb.ACHReturn
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+}
+var file_ach_file_proto_depIdxs = []int32{
+	2, // 0: pb.ACHFile.created_at:type_name -> google.protobuf.Timestamp
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_ach_file_proto_init() }
+func file_ach_file_proto_init() {
+	if File_ach_file_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ach_file_proto_rawDesc), len(file_ach_file_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_ach_file_proto_goTypes,
+		DependencyIndexes: file_ach_file_proto_depIdxs,
+		MessageInfos:      file_ach_file_proto_msgTypes,
+	}.Build()
+	File_ach_file_proto = out.File
+	file_ach_file_proto_goTypes = nil
+	file_ach_file_proto_depIdxs = nil
+}
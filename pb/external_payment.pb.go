@@ -0,0 +1,235 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: external_payment.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 他行转账的清算信息，状态在对应 Transfer 的 status 上
+type ExternalPayment struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	TransferId            int64                  `protobuf:"varint,1,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	Network               string                 `protobuf:"bytes,2,opt,name=network,proto3" json:"network,omitempty"`
+	CreditorAccountNumber string                 `protobuf:"bytes,3,opt,name=creditor_account_number,json=creditorAccountNumber,proto3" json:"creditor_account_number,omitempty"`
+	CreditorName          string                 `protobuf:"bytes,4,opt,name=creditor_name,json=creditorName,proto3" json:"creditor_name,omitempty"`
+	// 清算网络受理后返回的流水号
+	NetworkReference string `protobuf:"bytes,5,opt,name=network_reference,json=networkReference,proto3" json:"network_reference,omitempty"`
+	FailureReason    string `protobuf:"bytes,6,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`
+	// 失败或被退回时退款的转账
+	ReversalTransferId int64                  `protobuf:"varint,7,opt,name=reversal_transfer_id,json=reversalTransferId,proto3" json:"reversal_transfer_id,omitempty"`
+	SubmittedAt        *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=submitted_at,json=submittedAt,proto3" json:"submitted_at,omitempty"`
+	SettledAt          *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=settled_at,json=settledAt,proto3" json:"settled_at,omitempty"`
+	UpdatedAt          *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// 批量网络 (nacha) 的收款行路由号
+	CreditorRoutingNumber string `protobuf:"bytes,11,opt,name=creditor_routing_number,json=creditorRoutingNumber,proto3" json:"creditor_routing_number,omitempty"`
+	// 批量网络导出时所在的文件
+	AchFileId     int64 `protobuf:"varint,12,opt,name=ach_file_id,json=achFileId,proto3" json:"ach_file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExternalPayment) Reset() {
+	*x = ExternalPayment{}
+	mi := &file_external_payment_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExternalPayment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExternalPayment) ProtoMessage() {}
+
+func (x *ExternalPayment) ProtoReflect() protoreflect.Message {
+	mi := &file_external_payment_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExternalPayment.ProtoReflect.Descriptor instead.
+func (*ExternalPayment) Descriptor() ([]byte, []int) {
+	return file_external_payment_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExternalPayment) GetTransferId() int64 {
+	if x != nil {
+		return x.TransferId
+	}
+	return 0
+}
+
+func (x *ExternalPayment) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *ExternalPayment) GetCreditorAccountNumber() string {
+	if x != nil {
+		return x.CreditorAccountNumber
+	}
+	return ""
+}
+
+func (x *ExternalPayment) GetCreditorName() string {
+	if x != nil {
+		return x.CreditorName
+	}
+	return ""
+}
+
+func (x *ExternalPayment) GetNetworkReference() string {
+	if x != nil {
+		return x.NetworkReference
+	}
+	return ""
+}
+
+func (x *ExternalPayment) GetFailureReason() string {
+	if x != nil {
+		return x.FailureReason
+	}
+	return ""
+}
+
+func (x *ExternalPayment) GetReversalTransferId() int64 {
+	if x != nil {
+		return x.ReversalTransferId
+	}
+	return 0
+}
+
+func (x *ExternalPayment) GetSubmittedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SubmittedAt
+	}
+	return nil
+}
+
+func (x *ExternalPayment) GetSettledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SettledAt
+	}
+	return nil
+}
+
+func (x *ExternalPayment) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *ExternalPayment) GetCreditorRoutingNumber() string {
+	if x != nil {
+		return x.CreditorRoutingNumber
+	}
+	return ""
+}
+
+func (x *ExternalPayment) GetAchFileId() int64 {
+	if x != nil {
+		return x.AchFileId
+	}
+	return 0
+}
+
+var File_external_payment_proto protoreflect.FileDescriptor
+
+const file_external_payment_proto_rawDesc = "" +
+	"\n" +
+	"\x16external_payment.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbc\x04\n" +
+	"\x0fExternalPayment\x12\x1f\n" +
+	"\vtransfer_id\x18\x01 \x01(\x03R\n" +
+	"transferId\x12\x18\n" +
+	"\anetwork\x18\x02 \x01(\tR\anetwork\x126\n" +
+	"\x17creditor_account_number\x18\x03 \x01(\tR\x15creditorAccountNumber\x12#\n" +
+	"\rcreditor_name\x18\x04 \x01(\tR\fcreditorName\x12+\n" +
+	"\x11network_reference\x18\x05 \x01(\tR\x10networkReference\x12%\n" +
+	"\x0efailure_reason\x18\x06 \x01(\tR\rfailureReason\x120\n" +
+	"\x14reversal_transfer_id\x18\a \x01(\x03R\x12reversalTransferId\x12=\n" +
+	"\fsubmitted_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vsubmittedAt\x129\n" +
+	"\n" +
+	"settled_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tsettledAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x126\n" +
+	"\x17creditor_routing_number\x18\v \x01(\tR\x15creditorRoutingNumber\x12\x1e\n" +
+	"\vach_file_id\x18\f \x01(\x03R\tachFileIdB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_external_payment_proto_rawDescOnce sync.Once
+	file_external_payment_proto_rawDescData []byte
+)
+
+func file_external_payment_proto_rawDescGZIP() []byte {
+	file_external_payment_proto_rawDescOnce.Do(func() {
+		file_external_payment_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_external_payment_proto_rawDesc), len(file_external_payment_proto_rawDesc)))
+	})
+	return file_external_payment_proto_rawDescData
+}
+
+var file_external_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_external_payment_proto_goTypes = []any{
+	(*ExternalPayment)(nil),       // 0: pb.ExternalPayment
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_external_payment_proto_depIdxs = []int32{
+	1, // 0: pb.ExternalPayment.submitted_at:type_name -> google.protobuf.Timestamp
+	1, // 1: pb.ExternalPayment.settled_at:type_name -> google.protobuf.Timestamp
+	1, // 2: pb.ExternalPayment.updated_at:type_name -> google.protobuf.Timestamp
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_external_payment_proto_init() }
+func file_external_payment_proto_init() {
+	if File_external_payment_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_external_payment_proto_rawDesc), len(file_external_payment_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_external_payment_proto_goTypes,
+		DependencyIndexes: file_external_payment_proto_depIdxs,
+		MessageInfos:      file_external_payment_proto_msgTypes,
+	}.Build()
+	File_external_payment_proto = out.File
+	file_external_payment_proto_goTypes = nil
+	file_external_payment_proto_depIdxs = nil
+}
@@ -0,0 +1,193 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: fee_schedule.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 手续费 = flat_fee + 超过 percentage_threshold 部分的 percentage_bps 万分比，max_fee 为 0 时不封顶
+type FeeSchedule struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	TransferType string                 `protobuf:"bytes,2,opt,name=transfer_type,json=transferType,proto3" json:"transfer_type,omitempty"`
+	// 转出账户的产品，不填时对所有产品生效，产品专属的配置优先
+	ProductType         *string                `protobuf:"bytes,3,opt,name=product_type,json=productType,proto3,oneof" json:"product_type,omitempty"`
+	FlatFee             int64                  `protobuf:"varint,4,opt,name=flat_fee,json=flatFee,proto3" json:"flat_fee,omitempty"`
+	PercentageBps       int32                  `protobuf:"varint,5,opt,name=percentage_bps,json=percentageBps,proto3" json:"percentage_bps,omitempty"`
+	PercentageThreshold int64                  `protobuf:"varint,6,opt,name=percentage_threshold,json=percentageThreshold,proto3" json:"percentage_threshold,omitempty"`
+	MaxFee              int64                  `protobuf:"varint,7,opt,name=max_fee,json=maxFee,proto3" json:"max_fee,omitempty"`
+	CreatedAt           *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *FeeSchedule) Reset() {
+	*x = FeeSchedule{}
+	mi := &file_fee_schedule_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeeSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeeSchedule) ProtoMessage() {}
+
+func (x *FeeSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_fee_schedule_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeeSchedule.ProtoReflect.Descriptor instead.
+func (*FeeSchedule) Descriptor() ([]byte, []int) {
+	return file_fee_schedule_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FeeSchedule) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *FeeSchedule) GetTransferType() string {
+	if x != nil {
+		return x.TransferType
+	}
+	return ""
+}
+
+func (x *FeeSchedule) GetProductType() string {
+	if x != nil && x.ProductType != nil {
+		return *x.ProductType
+	}
+	return ""
+}
+
+func (x *FeeSchedule) GetFlatFee() int64 {
+	if x != nil {
+		return x.FlatFee
+	}
+	return 0
+}
+
+func (x *FeeSchedule) GetPercentageBps() int32 {
+	if x != nil {
+		return x.PercentageBps
+	}
+	return 0
+}
+
+func (x *FeeSchedule) GetPercentageThreshold() int64 {
+	if x != nil {
+		return x.PercentageThreshold
+	}
+	return 0
+}
+
+func (x *FeeSchedule) GetMaxFee() int64 {
+	if x != nil {
+		return x.MaxFee
+	}
+	return 0
+}
+
+func (x *FeeSchedule) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+var File_fee_schedule_proto protoreflect.FileDescriptor
+
+const file_fee_schedule_proto_rawDesc = "" +
+	"\n" +
+	"\x12fee_schedule.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc4\x02\n" +
+	"\vFeeSchedule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12#\n" +
+	"\rtransfer_type\x18\x02 \x01(\tR\ftransferType\x12&\n" +
+	"\fproduct_type\x18\x03 \x01(\tH\x00R\vproductType\x88\x01\x01\x12\x19\n" +
+	"\bflat_fee\x18\x04 \x01(\x03R\aflatFee\x12%\n" +
+	"\x0epercentage_bps\x18\x05 \x01(\x05R\rpercentageBps\x121\n" +
+	"\x14percentage_threshold\x18\x06 \x01(\x03R\x13percentageThreshold\x12\x17\n" +
+	"\amax_fee\x18\a \x01(\x03R\x06maxFee\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtB\x0f\n" +
+	"\r_product_typeB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_fee_schedule_proto_rawDescOnce sync.Once
+	file_fee_schedule_proto_rawDescData []byte
+)
+
+func file_fee_schedule_proto_rawDescGZIP() []byte {
+	file_fee_schedule_proto_rawDescOnce.Do(func() {
+		file_fee_schedule_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_fee_schedule_proto_rawDesc), len(file_fee_schedule_proto_rawDesc)))
+	})
+	return file_fee_schedule_proto_rawDescData
+}
+
+var file_fee_schedule_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_fee_schedule_proto_goTypes = []any{
+	(*FeeSchedule)(nil),           // 0: pb.FeeSchedule
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_fee_schedule_proto_depIdxs = []int32{
+	1, // 0: pb.FeeSchedule.created_at:type_name -> google.protobuf.Timestamp
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_fee_schedule_proto_init() }
+func file_fee_schedule_proto_init() {
+	if File_fee_schedule_proto != nil {
+		return
+	}
+	file_fee_schedule_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_fee_schedule_proto_rawDesc), len(file_fee_schedule_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_fee_schedule_proto_goTypes,
+		DependencyIndexes: file_fee_schedule_proto_depIdxs,
+		MessageInfos:      file_fee_schedule_proto_msgTypes,
+	}.Build()
+	File_fee_schedule_proto = out.File
+	file_fee_schedule_proto_goTypes = nil
+	file_fee_schedule_proto_depIdxs = nil
+}
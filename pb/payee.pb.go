@@ -0,0 +1,176 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: payee.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Payee struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountId int64                  `protobuf:"varint,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Nickname  string                 `protobuf:"bytes,3,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	// 保存时和账户户名核验一致的户名
+	HolderName string                 `protobuf:"bytes,4,opt,name=holder_name,json=holderName,proto3" json:"holder_name,omitempty"`
+	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// 冷静期结束之前单笔转账受 PAYEE_COOLING_OFF_LIMIT 限制
+	CoolingOffUntil *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=cooling_off_until,json=coolingOffUntil,proto3" json:"cooling_off_until,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Payee) Reset() {
+	*x = Payee{}
+	mi := &file_payee_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payee) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payee) ProtoMessage() {}
+
+func (x *Payee) ProtoReflect() protoreflect.Message {
+	mi := &file_payee_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payee.ProtoReflect.Descriptor instead.
+func (*Payee) Descriptor() ([]byte, []int) {
+	return file_payee_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Payee) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Payee) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *Payee) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+func (x *Payee) GetHolderName() string {
+	if x != nil {
+		return x.HolderName
+	}
+	return ""
+}
+
+func (x *Payee) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Payee) GetCoolingOffUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CoolingOffUntil
+	}
+	return nil
+}
+
+var File_payee_proto protoreflect.FileDescriptor
+
+const file_payee_proto_rawDesc = "" +
+	"\n" +
+	"\vpayee.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf6\x01\n" +
+	"\x05Payee\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\x03R\taccountId\x12\x1a\n" +
+	"\bnickname\x18\x03 \x01(\tR\bnickname\x12\x1f\n" +
+	"\vholder_name\x18\x04 \x01(\tR\n" +
+	"holderName\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12F\n" +
+	"\x11cooling_off_until\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x0fcoolingOffUntilB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_payee_proto_rawDescOnce sync.Once
+	file_payee_proto_rawDescData []byte
+)
+
+func file_payee_proto_rawDescGZIP() []byte {
+	file_payee_proto_rawDescOnce.Do(func() {
+		file_payee_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_payee_proto_rawDesc), len(file_payee_proto_rawDesc)))
+	})
+	return file_payee_proto_rawDescData
+}
+
+var file_payee_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_payee_proto_goTypes = []any{
+	(*Payee)(nil),                 // 0: pb.Payee
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_payee_proto_depIdxs = []int32{
+	1, // 0: pb.Payee.created_at:type_name -> google.protobuf.Timestamp
+	1, // 1: pb.Payee.cooling_off_until:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_payee_proto_init() }
+func file_payee_proto_init() {
+	if File_payee_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_payee_proto_rawDesc), len(file_payee_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_payee_proto_goTypes,
+		DependencyIndexes: file_payee_proto_depIdxs,
+		MessageInfos:      file_payee_proto_msgTypes,
+	}.Build()
+	File_payee_proto = out.File
+	file_payee_proto_goTypes = nil
+	file_payee_proto_depIdxs = nil
+}
@@ -0,0 +1,383 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: rpc_account_member.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AddAccountMemberRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AccountId int64                  `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Username  string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	// co_owner: 可以转账; viewer: 只能查看
+	Role          string `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddAccountMemberRequest) Reset() {
+	*x = AddAccountMemberRequest{}
+	mi := &file_rpc_account_member_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddAccountMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddAccountMemberRequest) ProtoMessage() {}
+
+func (x *AddAccountMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_account_member_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddAccountMemberRequest.ProtoReflect.Descriptor instead.
+func (*AddAccountMemberRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_account_member_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AddAccountMemberRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *AddAccountMemberRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *AddAccountMemberRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type AddAccountMemberResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Member        *AccountMember         `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddAccountMemberResponse) Reset() {
+	*x = AddAccountMemberResponse{}
+	mi := &file_rpc_account_member_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddAccountMemberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddAccountMemberResponse) ProtoMessage() {}
+
+func (x *AddAccountMemberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_account_member_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddAccountMemberResponse.ProtoReflect.Descriptor instead.
+func (*AddAccountMemberResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_account_member_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AddAccountMemberResponse) GetMember() *AccountMember {
+	if x != nil {
+		return x.Member
+	}
+	return nil
+}
+
+type RemoveAccountMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     int64                  `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveAccountMemberRequest) Reset() {
+	*x = RemoveAccountMemberRequest{}
+	mi := &file_rpc_account_member_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveAccountMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveAccountMemberRequest) ProtoMessage() {}
+
+func (x *RemoveAccountMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_account_member_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveAccountMemberRequest.ProtoReflect.Descriptor instead.
+func (*RemoveAccountMemberRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_account_member_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RemoveAccountMemberRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *RemoveAccountMemberRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type RemoveAccountMemberResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveAccountMemberResponse) Reset() {
+	*x = RemoveAccountMemberResponse{}
+	mi := &file_rpc_account_member_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveAccountMemberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveAccountMemberResponse) ProtoMessage() {}
+
+func (x *RemoveAccountMemberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_account_member_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveAccountMemberResponse.ProtoReflect.Descriptor instead.
+func (*RemoveAccountMemberResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_account_member_proto_rawDescGZIP(), []int{3}
+}
+
+type ListAccountMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     int64                  `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAccountMembersRequest) Reset() {
+	*x = ListAccountMembersRequest{}
+	mi := &file_rpc_account_member_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAccountMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAccountMembersRequest) ProtoMessage() {}
+
+func (x *ListAccountMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_account_member_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAccountMembersRequest.ProtoReflect.Descriptor instead.
+func (*ListAccountMembersRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_account_member_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListAccountMembersRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+type ListAccountMembersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Members       []*AccountMember       `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAccountMembersResponse) Reset() {
+	*x = ListAccountMembersResponse{}
+	mi := &file_rpc_account_member_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAccountMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAccountMembersResponse) ProtoMessage() {}
+
+func (x *ListAccountMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_account_member_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAccountMembersResponse.ProtoReflect.Descriptor instead.
+func (*ListAccountMembersResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_account_member_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListAccountMembersResponse) GetMembers() []*AccountMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+var File_rpc_account_member_proto protoreflect.FileDescriptor
+
+const file_rpc_account_member_proto_rawDesc = "" +
+	"\n" +
+	"\x18rpc_account_member.proto\x12\x02pb\x1a\x14account_member.proto\"h\n" +
+	"\x17AddAccountMemberRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\"E\n" +
+	"\x18AddAccountMemberResponse\x12)\n" +
+	"\x06member\x18\x01 \x01(\v2\x11.pb.AccountMemberR\x06member\"W\n" +
+	"\x1aRemoveAccountMemberRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\"\x1d\n" +
+	"\x1bRemoveAccountMemberResponse\":\n" +
+	"\x19ListAccountMembersRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\"I\n" +
+	"\x1aListAccountMembersResponse\x12+\n" +
+	"\amembers\x18\x01 \x03(\v2\x11.pb.AccountMemberR\amembersB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_rpc_account_member_proto_rawDescOnce sync.Once
+	file_rpc_account_member_proto_rawDescData []byte
+)
+
+func file_rpc_account_member_proto_rawDescGZIP() []byte {
+	file_rpc_account_member_proto_rawDescOnce.Do(func() {
+		file_rpc_account_member_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_account_member_proto_rawDesc), len(file_rpc_account_member_proto_rawDesc)))
+	})
+	return file_rpc_account_member_proto_rawDescData
+}
+
+var file_rpc_account_member_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_rpc_account_member_proto_goTypes = []any{
+	(*AddAccountMemberRequest)(nil),     // 0: pb.AddAccountMemberRequest
+	(*AddAccountMemberResponse)(nil),    // 1: pb.AddAccountMemberResponse
+	(*RemoveAccountMemberRequest)(nil),  // 2: pb.RemoveAccountMemberRequest
+	(*RemoveAccountMemberResponse)(nil), // 3: pb.RemoveAccountMemberResponse
+	(*ListAccountMembersRequest)(nil),   // 4: pb.ListAccountMembersRequest
+	(*ListAccountMembersResponse)(nil),  // 5: pb.ListAccountMembersResponse
+	(*AccountMember)(nil),               // 6: pb.AccountMember
+}
+var file_rpc_account_member_proto_depIdxs = []int32{
+	6, // 0: pb.AddAccountMemberResponse.member:type_name -> pb.AccountMember
+	6, // 1: pb.ListAccountMembersResponse.members:type_name -> pb.AccountMember
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_account_member_proto_init() }
+func file_rpc_account_member_proto_init() {
+	if File_rpc_account_member_proto != nil {
+		return
+	}
+	file_account_member_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_account_member_proto_rawDesc), len(file_rpc_account_member_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_account_member_proto_goTypes,
+		DependencyIndexes: file_rpc_account_member_proto_depIdxs,
+		MessageInfos:      file_rpc_account_member_proto_msgTypes,
+	}.Build()
+	File_rpc_account_member_proto = out.File
+	file_rpc_account_member_proto_goTypes = nil
+	file_rpc_account_member_proto_depIdxs = nil
+}
@@ -0,0 +1,269 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: rpc_ach.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExportACHFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportACHFileRequest) Reset() {
+	*x = ExportACHFileRequest{}
+	mi := &file_rpc_ach_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportACHFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportACHFileRequest) ProtoMessage() {}
+
+func (x *ExportACHFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_ach_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportACHFileRequest.ProtoReflect.Descriptor instead.
+func (*ExportACHFileRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_ach_proto_rawDescGZIP(), []int{0}
+}
+
+type ExportACHFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AchFile       *ACHFile               `protobuf:"bytes,1,opt,name=ach_file,json=achFile,proto3" json:"ach_file,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportACHFileResponse) Reset() {
+	*x = ExportACHFileResponse{}
+	mi := &file_rpc_ach_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportACHFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportACHFileResponse) ProtoMessage() {}
+
+func (x *ExportACHFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_ach_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportACHFileResponse.ProtoReflect.Descriptor instead.
+func (*ExportACHFileResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_ach_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ExportACHFileResponse) GetAchFile() *ACHFile {
+	if x != nil {
+		return x.AchFile
+	}
+	return nil
+}
+
+func (x *ExportACHFileResponse) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type ProcessACHReturnsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 退回文件的全文
+	Content       string `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessACHReturnsRequest) Reset() {
+	*x = ProcessACHReturnsRequest{}
+	mi := &file_rpc_ach_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessACHReturnsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessACHReturnsRequest) ProtoMessage() {}
+
+func (x *ProcessACHReturnsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_ach_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessACHReturnsRequest.ProtoReflect.Descriptor instead.
+func (*ProcessACHReturnsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_ach_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProcessACHReturnsRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type ProcessACHReturnsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Returns       []*ACHReturn           `protobuf:"bytes,1,rep,name=returns,proto3" json:"returns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessACHReturnsResponse) Reset() {
+	*x = ProcessACHReturnsResponse{}
+	mi := &file_rpc_ach_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessACHReturnsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessACHReturnsResponse) ProtoMessage() {}
+
+func (x *ProcessACHReturnsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_ach_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessACHReturnsResponse.ProtoReflect.Descriptor instead.
+func (*ProcessACHReturnsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_ach_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ProcessACHReturnsResponse) GetReturns() []*ACHReturn {
+	if x != nil {
+		return x.Returns
+	}
+	return nil
+}
+
+var File_rpc_ach_proto protoreflect.FileDescriptor
+
+const file_rpc_ach_proto_rawDesc = "" +
+	"\n" +
+	"\rrpc_ach.proto\x12\x02pb\x1a\x0each_file.proto\"\x16\n" +
+	"\x14ExportACHFileRequest\"Y\n" +
+	"\x15ExportACHFileResponse\x12&\n" +
+	"\bach_file\x18\x01 \x01(\v2\v.pb.ACHFileR\aachFile\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\"4\n" +
+	"\x18ProcessACHReturnsRequest\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\tR\acontent\"D\n" +
+	"\x19ProcessACHReturnsResponse\x12'\n" +
+	"\areturns\x18\x01 \x03(\v2\r.pb.ACHReturnR\areturnsB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_rpc_ach_proto_rawDescOnce sync.Once
+	file_rpc_ach_proto_rawDescData []byte
+)
+
+func file_rpc_ach_proto_rawDescGZIP() []byte {
+	file_rpc_ach_proto_rawDescOnce.Do(func() {
+		file_rpc_ach_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_ach_proto_rawDesc), len(file_rpc_ach_proto_rawDesc)))
+	})
+	return file_rpc_ach_proto_rawDescData
+}
+
+var file_rpc_ach_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_rpc_ach_proto_goTypes = []any{
+	(*ExportACHFileRequest)(nil),      // 0: pb.ExportACHFileRequest
+	(*ExportACHFileResponse)(nil),     // 1: pb.ExportACHFileResponse
+	(*ProcessACHReturnsRequest)(nil),  // 2: pb.ProcessACHReturnsRequest
+	(*ProcessACHReturnsResponse)(nil), // 3: pb.ProcessACHReturnsResponse
+	(*ACHFile)(nil),                   // 4: pb.ACHFile
+	(*ACHReturn)(nil),                 // 5: pb.ACHReturn
+}
+var file_rpc_ach_proto_depIdxs = []int32{
+	4, // 0: pb.ExportACHFileResponse.ach_file:type_name -> pb.ACHFile
+	5, // 1: pb.ProcessACHReturnsResponse.returns:type_name -> pb.ACHReturn
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_ach_proto_init() }
+func file_rpc_ach_proto_init() {
+	if File_rpc_ach_proto != nil {
+		return
+	}
+	file_ach_file_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_ach_proto_rawDesc), len(file_rpc_ach_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_ach_proto_goTypes,
+		DependencyIndexes: file_rpc_ach_proto_depIdxs,
+		MessageInfos:      file_rpc_ach_proto_msgTypes,
+	}.Build()
+	File_rpc_ach_proto = out.File
+	file_rpc_ach_proto_goTypes = nil
+	file_rpc_ach_proto_depIdxs = nil
+}
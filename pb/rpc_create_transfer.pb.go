@@ -0,0 +1,313 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: rpc_create_transfer.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateTransferRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// from_account_id 和 from_account_number 二选一
+	FromAccountId int64 `protobuf:"varint,1,opt,name=from_account_id,json=fromAccountId,proto3" json:"from_account_id,omitempty"`
+	// to_account_id、to_account_number、payee_id、recipient 四选一
+	ToAccountId int64  `protobuf:"varint,2,opt,name=to_account_id,json=toAccountId,proto3" json:"to_account_id,omitempty"`
+	Amount      int64  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency    string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	PayeeId     *int64 `protobuf:"varint,5,opt,name=payee_id,json=payeeId,proto3,oneof" json:"payee_id,omitempty"`
+	// 收款人用户名或已验证邮箱，转到对方该币种的活期账户
+	Recipient *string `protobuf:"bytes,6,opt,name=recipient,proto3,oneof" json:"recipient,omitempty"`
+	// 对外账号，如 SB55SMPL0123456789
+	FromAccountNumber *string `protobuf:"bytes,7,opt,name=from_account_number,json=fromAccountNumber,proto3,oneof" json:"from_account_number,omitempty"`
+	ToAccountNumber   *string `protobuf:"bytes,8,opt,name=to_account_number,json=toAccountNumber,proto3,oneof" json:"to_account_number,omitempty"`
+	// 备注双方可见，最长 140
+	Memo string `protobuf:"bytes,9,opt,name=memo,proto3" json:"memo,omitempty"`
+	// 只有转出方能看到，最长 500
+	PrivateNote       string `protobuf:"bytes,10,opt,name=private_note,json=privateNote,proto3" json:"private_note,omitempty"`
+	ExternalReference string `protobuf:"bytes,11,opt,name=external_reference,json=externalReference,proto3" json:"external_reference,omitempty"`
+	// 最多 20 个键，序列化后不超过 4KB
+	Metadata map[string]string `protobuf:"bytes,12,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// QuoteTransfer 返回的报价，收付款方、金额和币种必须和报价一致
+	QuoteId       *string `protobuf:"bytes,13,opt,name=quote_id,json=quoteId,proto3,oneof" json:"quote_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTransferRequest) Reset() {
+	*x = CreateTransferRequest{}
+	mi := &file_rpc_create_transfer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTransferRequest) ProtoMessage() {}
+
+func (x *CreateTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_create_transfer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTransferRequest.ProtoReflect.Descriptor instead.
+func (*CreateTransferRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_create_transfer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateTransferRequest) GetFromAccountId() int64 {
+	if x != nil {
+		return x.FromAccountId
+	}
+	return 0
+}
+
+func (x *CreateTransferRequest) GetToAccountId() int64 {
+	if x != nil {
+		return x.ToAccountId
+	}
+	return 0
+}
+
+func (x *CreateTransferRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CreateTransferRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreateTransferRequest) GetPayeeId() int64 {
+	if x != nil && x.PayeeId != nil {
+		return *x.PayeeId
+	}
+	return 0
+}
+
+func (x *CreateTransferRequest) GetRecipient() string {
+	if x != nil && x.Recipient != nil {
+		return *x.Recipient
+	}
+	return ""
+}
+
+func (x *CreateTransferRequest) GetFromAccountNumber() string {
+	if x != nil && x.FromAccountNumber != nil {
+		return *x.FromAccountNumber
+	}
+	return ""
+}
+
+func (x *CreateTransferRequest) GetToAccountNumber() string {
+	if x != nil && x.ToAccountNumber != nil {
+		return *x.ToAccountNumber
+	}
+	return ""
+}
+
+func (x *CreateTransferRequest) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
+func (x *CreateTransferRequest) GetPrivateNote() string {
+	if x != nil {
+		return x.PrivateNote
+	}
+	return ""
+}
+
+func (x *CreateTransferRequest) GetExternalReference() string {
+	if x != nil {
+		return x.ExternalReference
+	}
+	return ""
+}
+
+func (x *CreateTransferRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *CreateTransferRequest) GetQuoteId() string {
+	if x != nil && x.QuoteId != nil {
+		return *x.QuoteId
+	}
+	return ""
+}
+
+type CreateTransferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transfer      *Transfer              `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	FromAccount   *Account               `protobuf:"bytes,2,opt,name=from_account,json=fromAccount,proto3" json:"from_account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTransferResponse) Reset() {
+	*x = CreateTransferResponse{}
+	mi := &file_rpc_create_transfer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTransferResponse) ProtoMessage() {}
+
+func (x *CreateTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_create_transfer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTransferResponse.ProtoReflect.Descriptor instead.
+func (*CreateTransferResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_create_transfer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateTransferResponse) GetTransfer() *Transfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *CreateTransferResponse) GetFromAccount() *Account {
+	if x != nil {
+		return x.FromAccount
+	}
+	return nil
+}
+
+var File_rpc_create_transfer_proto protoreflect.FileDescriptor
+
+const file_rpc_create_transfer_proto_rawDesc = "" +
+	"\n" +
+	"\x19rpc_create_transfer.proto\x12\x02pb\x1a\raccount.proto\x1a\x0etransfer.proto\"\x9e\x05\n" +
+	"\x15CreateTransferRequest\x12&\n" +
+	"\x0ffrom_account_id\x18\x01 \x01(\x03R\rfromAccountId\x12\"\n" +
+	"\rto_account_id\x18\x02 \x01(\x03R\vtoAccountId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x1e\n" +
+	"\bpayee_id\x18\x05 \x01(\x03H\x00R\apayeeId\x88\x01\x01\x12!\n" +
+	"\trecipient\x18\x06 \x01(\tH\x01R\trecipient\x88\x01\x01\x123\n" +
+	"\x13from_account_number\x18\a \x01(\tH\x02R\x11fromAccountNumber\x88\x01\x01\x12/\n" +
+	"\x11to_account_number\x18\b \x01(\tH\x03R\x0ftoAccountNumber\x88\x01\x01\x12\x12\n" +
+	"\x04memo\x18\t \x01(\tR\x04memo\x12!\n" +
+	"\fprivate_note\x18\n" +
+	" \x01(\tR\vprivateNote\x12-\n" +
+	"\x12external_reference\x18\v \x01(\tR\x11externalReference\x12C\n" +
+	"\bmetadata\x18\f \x03(\v2'.pb.CreateTransferRequest.MetadataEntryR\bmetadata\x12\x1e\n" +
+	"\bquote_id\x18\r \x01(\tH\x04R\aquoteId\x88\x01\x01\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\v\n" +
+	"\t_payee_idB\f\n" +
+	"\n" +
+	"_recipientB\x16\n" +
+	"\x14_from_account_numberB\x14\n" +
+	"\x12_to_account_numberB\v\n" +
+	"\t_quote_id\"r\n" +
+	"\x16CreateTransferResponse\x12(\n" +
+	"\btransfer\x18\x01 \x01(\v2\f.pb.TransferR\btransfer\x12.\n" +
+	"\ffrom_account\x18\x02 \x01(\v2\v.pb.AccountR\vfromAccountB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_rpc_create_transfer_proto_rawDescOnce sync.Once
+	file_rpc_create_transfer_proto_rawDescData []byte
+)
+
+func file_rpc_create_transfer_proto_rawDescGZIP() []byte {
+	file_rpc_create_transfer_proto_rawDescOnce.Do(func() {
+		file_rpc_create_transfer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_create_transfer_proto_rawDesc), len(file_rpc_create_transfer_proto_rawDesc)))
+	})
+	return file_rpc_create_transfer_proto_rawDescData
+}
+
+var file_rpc_create_transfer_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_rpc_create_transfer_proto_goTypes = []any{
+	(*CreateTransferRequest)(nil),  // 0: pb.CreateTransferRequest
+	(*CreateTransferResponse)(nil), // 1: pb.CreateTransferResponse
+	nil,                            // 2: pb.CreateTransferRequest.MetadataEntry
+	(*Transfer)(nil),               // 3: pb.Transfer
+	(*Account)(nil),                // 4: pb.Account
+}
+var file_rpc_create_transfer_proto_depIdxs = []int32{
+	2, // 0: pb.CreateTransferRequest.metadata:type_name -> pb.CreateTransferRequest.MetadataEntry
+	3, // 1: pb.CreateTransferResponse.transfer:type_name -> pb.Transfer
+	4, // 2: pb.CreateTransferResponse.from_account:type_name -> pb.Account
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_rpc_create_transfer_proto_init() }
+func file_rpc_create_transfer_proto_init() {
+	if File_rpc_create_transfer_proto != nil {
+		return
+	}
+	file_account_proto_init()
+	file_transfer_proto_init()
+	file_rpc_create_transfer_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_create_transfer_proto_rawDesc), len(file_rpc_create_transfer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_create_transfer_proto_goTypes,
+		DependencyIndexes: file_rpc_create_transfer_proto_depIdxs,
+		MessageInfos:      file_rpc_create_transfer_proto_msgTypes,
+	}.Build()
+	File_rpc_create_transfer_proto = out.File
+	file_rpc_create_transfer_proto_goTypes = nil
+	file_rpc_create_transfer_proto_depIdxs = nil
+}
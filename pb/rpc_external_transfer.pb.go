@@ -0,0 +1,377 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: rpc_external_transfer.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateExternalTransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromAccountId int64                  `protobuf:"varint,1,opt,name=from_account_id,json=fromAccountId,proto3" json:"from_account_id,omitempty"`
+	// 他行的对外账号，本行账号请用 CreateTransfer
+	CreditorAccountNumber string `protobuf:"bytes,2,opt,name=creditor_account_number,json=creditorAccountNumber,proto3" json:"creditor_account_number,omitempty"`
+	CreditorName          string `protobuf:"bytes,3,opt,name=creditor_name,json=creditorName,proto3" json:"creditor_name,omitempty"`
+	Amount                int64  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency              string `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	Memo                  string `protobuf:"bytes,6,opt,name=memo,proto3" json:"memo,omitempty"`
+	PrivateNote           string `protobuf:"bytes,7,opt,name=private_note,json=privateNote,proto3" json:"private_note,omitempty"`
+	ExternalReference     string `protobuf:"bytes,8,opt,name=external_reference,json=externalReference,proto3" json:"external_reference,omitempty"`
+	// 清算网络是 nacha 时必填，9 位 ABA 路由号
+	CreditorRoutingNumber string `protobuf:"bytes,9,opt,name=creditor_routing_number,json=creditorRoutingNumber,proto3" json:"creditor_routing_number,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *CreateExternalTransferRequest) Reset() {
+	*x = CreateExternalTransferRequest{}
+	mi := &file_rpc_external_transfer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateExternalTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateExternalTransferRequest) ProtoMessage() {}
+
+func (x *CreateExternalTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_external_transfer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateExternalTransferRequest.ProtoReflect.Descriptor instead.
+func (*CreateExternalTransferRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_external_transfer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateExternalTransferRequest) GetFromAccountId() int64 {
+	if x != nil {
+		return x.FromAccountId
+	}
+	return 0
+}
+
+func (x *CreateExternalTransferRequest) GetCreditorAccountNumber() string {
+	if x != nil {
+		return x.CreditorAccountNumber
+	}
+	return ""
+}
+
+func (x *CreateExternalTransferRequest) GetCreditorName() string {
+	if x != nil {
+		return x.CreditorName
+	}
+	return ""
+}
+
+func (x *CreateExternalTransferRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CreateExternalTransferRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreateExternalTransferRequest) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
+func (x *CreateExternalTransferRequest) GetPrivateNote() string {
+	if x != nil {
+		return x.PrivateNote
+	}
+	return ""
+}
+
+func (x *CreateExternalTransferRequest) GetExternalReference() string {
+	if x != nil {
+		return x.ExternalReference
+	}
+	return ""
+}
+
+func (x *CreateExternalTransferRequest) GetCreditorRoutingNumber() string {
+	if x != nil {
+		return x.CreditorRoutingNumber
+	}
+	return ""
+}
+
+type CreateExternalTransferResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 状态为 initiated，资金已转入清算账户
+	Transfer        *Transfer        `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	ExternalPayment *ExternalPayment `protobuf:"bytes,2,opt,name=external_payment,json=externalPayment,proto3" json:"external_payment,omitempty"`
+	FromAccount     *Account         `protobuf:"bytes,3,opt,name=from_account,json=fromAccount,proto3" json:"from_account,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreateExternalTransferResponse) Reset() {
+	*x = CreateExternalTransferResponse{}
+	mi := &file_rpc_external_transfer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateExternalTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateExternalTransferResponse) ProtoMessage() {}
+
+func (x *CreateExternalTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_external_transfer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateExternalTransferResponse.ProtoReflect.Descriptor instead.
+func (*CreateExternalTransferResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_external_transfer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateExternalTransferResponse) GetTransfer() *Transfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *CreateExternalTransferResponse) GetExternalPayment() *ExternalPayment {
+	if x != nil {
+		return x.ExternalPayment
+	}
+	return nil
+}
+
+func (x *CreateExternalTransferResponse) GetFromAccount() *Account {
+	if x != nil {
+		return x.FromAccount
+	}
+	return nil
+}
+
+type GetExternalTransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransferId    int64                  `protobuf:"varint,1,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExternalTransferRequest) Reset() {
+	*x = GetExternalTransferRequest{}
+	mi := &file_rpc_external_transfer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExternalTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExternalTransferRequest) ProtoMessage() {}
+
+func (x *GetExternalTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_external_transfer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExternalTransferRequest.ProtoReflect.Descriptor instead.
+func (*GetExternalTransferRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_external_transfer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetExternalTransferRequest) GetTransferId() int64 {
+	if x != nil {
+		return x.TransferId
+	}
+	return 0
+}
+
+type GetExternalTransferResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Transfer        *Transfer              `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	ExternalPayment *ExternalPayment       `protobuf:"bytes,2,opt,name=external_payment,json=externalPayment,proto3" json:"external_payment,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetExternalTransferResponse) Reset() {
+	*x = GetExternalTransferResponse{}
+	mi := &file_rpc_external_transfer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExternalTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExternalTransferResponse) ProtoMessage() {}
+
+func (x *GetExternalTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_external_transfer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExternalTransferResponse.ProtoReflect.Descriptor instead.
+func (*GetExternalTransferResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_external_transfer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetExternalTransferResponse) GetTransfer() *Transfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *GetExternalTransferResponse) GetExternalPayment() *ExternalPayment {
+	if x != nil {
+		return x.ExternalPayment
+	}
+	return nil
+}
+
+var File_rpc_external_transfer_proto protoreflect.FileDescriptor
+
+const file_rpc_external_transfer_proto_rawDesc = "" +
+	"\n" +
+	"\x1brpc_external_transfer.proto\x12\x02pb\x1a\raccount.proto\x1a\x16external_payment.proto\x1a\x0etransfer.proto\"\xf6\x02\n" +
+	"\x1dCreateExternalTransferRequest\x12&\n" +
+	"\x0ffrom_account_id\x18\x01 \x01(\x03R\rfromAccountId\x126\n" +
+	"\x17creditor_account_number\x18\x02 \x01(\tR\x15creditorAccountNumber\x12#\n" +
+	"\rcreditor_name\x18\x03 \x01(\tR\fcreditorName\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12\x12\n" +
+	"\x04memo\x18\x06 \x01(\tR\x04memo\x12!\n" +
+	"\fprivate_note\x18\a \x01(\tR\vprivateNote\x12-\n" +
+	"\x12external_reference\x18\b \x01(\tR\x11externalReference\x126\n" +
+	"\x17creditor_routing_number\x18\t \x01(\tR\x15creditorRoutingNumber\"\xba\x01\n" +
+	"\x1eCreateExternalTransferResponse\x12(\n" +
+	"\btransfer\x18\x01 \x01(\v2\f.pb.TransferR\btransfer\x12>\n" +
+	"\x10external_payment\x18\x02 \x01(\v2\x13.pb.ExternalPaymentR\x0fexternalPayment\x12.\n" +
+	"\ffrom_account\x18\x03 \x01(\v2\v.pb.AccountR\vfromAccount\"=\n" +
+	"\x1aGetExternalTransferRequest\x12\x1f\n" +
+	"\vtransfer_id\x18\x01 \x01(\x03R\n" +
+	"transferId\"\x87\x01\n" +
+	"\x1bGetExternalTransferResponse\x12(\n" +
+	"\btransfer\x18\x01 \x01(\v2\f.pb.TransferR\btransfer\x12>\n" +
+	"\x10external_payment\x18\x02 \x01(\v2\x13.pb.ExternalPaymentR\x0fexternalPaymentB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_rpc_external_transfer_proto_rawDescOnce sync.Once
+	file_rpc_external_transfer_proto_rawDescData []byte
+)
+
+func file_rpc_external_transfer_proto_rawDescGZIP() []byte {
+	file_rpc_external_transfer_proto_rawDescOnce.Do(func() {
+		file_rpc_external_transfer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_external_transfer_proto_rawDesc), len(file_rpc_external_transfer_proto_rawDesc)))
+	})
+	return file_rpc_external_transfer_proto_rawDescData
+}
+
+var file_rpc_external_transfer_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_rpc_external_transfer_proto_goTypes = []any{
+	(*CreateExternalTransferRequest)(nil),  // 0: pb.CreateExternalTransferRequest
+	(*CreateExternalTransferResponse)(nil), // 1: pb.CreateExternalTransferResponse
+	(*GetExternalTransferRequest)(nil),     // 2: pb.GetExternalTransferRequest
+	(*GetExternalTransferResponse)(nil),    // 3: pb.GetExternalTransferResponse
+	(*Transfer)(nil),                       // 4: pb.Transfer
+	(*ExternalPayment)(nil),                // 5: pb.ExternalPayment
+	(*Account)(nil),                        // 6: pb.Account
+}
+var file_rpc_external_transfer_proto_depIdxs = []int32{
+	4, // 0: pb.CreateExternalTransferResponse.transfer:type_name -> pb.Transfer
+	5, // 1: pb.CreateExternalTransferResponse.external_payment:type_name -> pb.ExternalPayment
+	6, // 2: pb.CreateExternalTransferResponse.from_account:type_name -> pb.Account
+	4, // 3: pb.GetExternalTransferResponse.transfer:type_name -> pb.Transfer
+	5, // 4: pb.GetExternalTransferResponse.external_payment:type_name -> pb.ExternalPayment
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_rpc_external_transfer_proto_init() }
+func file_rpc_external_transfer_proto_init() {
+	if File_rpc_external_transfer_proto != nil {
+		return
+	}
+	file_account_proto_init()
+	file_external_payment_proto_init()
+	file_transfer_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_external_transfer_proto_rawDesc), len(file_rpc_external_transfer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_external_transfer_proto_goTypes,
+		DependencyIndexes: file_rpc_external_transfer_proto_depIdxs,
+		MessageInfos:      file_rpc_external_transfer_proto_msgTypes,
+	}.Build()
+	File_rpc_external_transfer_proto = out.File
+	file_rpc_external_transfer_proto_goTypes = nil
+	file_rpc_external_transfer_proto_depIdxs = nil
+}
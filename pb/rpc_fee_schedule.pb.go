@@ -0,0 +1,391 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: rpc_fee_schedule.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 同一转账类型下每个产品只能有一条配置，改费率要先删掉旧的
+type CreateFeeScheduleRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	TransferType        string                 `protobuf:"bytes,1,opt,name=transfer_type,json=transferType,proto3" json:"transfer_type,omitempty"`
+	ProductType         *string                `protobuf:"bytes,2,opt,name=product_type,json=productType,proto3,oneof" json:"product_type,omitempty"`
+	FlatFee             int64                  `protobuf:"varint,3,opt,name=flat_fee,json=flatFee,proto3" json:"flat_fee,omitempty"`
+	PercentageBps       int32                  `protobuf:"varint,4,opt,name=percentage_bps,json=percentageBps,proto3" json:"percentage_bps,omitempty"`
+	PercentageThreshold int64                  `protobuf:"varint,5,opt,name=percentage_threshold,json=percentageThreshold,proto3" json:"percentage_threshold,omitempty"`
+	MaxFee              int64                  `protobuf:"varint,6,opt,name=max_fee,json=maxFee,proto3" json:"max_fee,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *CreateFeeScheduleRequest) Reset() {
+	*x = CreateFeeScheduleRequest{}
+	mi := &file_rpc_fee_schedule_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFeeScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFeeScheduleRequest) ProtoMessage() {}
+
+func (x *CreateFeeScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_fee_schedule_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFeeScheduleRequest.ProtoReflect.Descriptor instead.
+func (*CreateFeeScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_fee_schedule_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateFeeScheduleRequest) GetTransferType() string {
+	if x != nil {
+		return x.TransferType
+	}
+	return ""
+}
+
+func (x *CreateFeeScheduleRequest) GetProductType() string {
+	if x != nil && x.ProductType != nil {
+		return *x.ProductType
+	}
+	return ""
+}
+
+func (x *CreateFeeScheduleRequest) GetFlatFee() int64 {
+	if x != nil {
+		return x.FlatFee
+	}
+	return 0
+}
+
+func (x *CreateFeeScheduleRequest) GetPercentageBps() int32 {
+	if x != nil {
+		return x.PercentageBps
+	}
+	return 0
+}
+
+func (x *CreateFeeScheduleRequest) GetPercentageThreshold() int64 {
+	if x != nil {
+		return x.PercentageThreshold
+	}
+	return 0
+}
+
+func (x *CreateFeeScheduleRequest) GetMaxFee() int64 {
+	if x != nil {
+		return x.MaxFee
+	}
+	return 0
+}
+
+type CreateFeeScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FeeSchedule   *FeeSchedule           `protobuf:"bytes,1,opt,name=fee_schedule,json=feeSchedule,proto3" json:"fee_schedule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFeeScheduleResponse) Reset() {
+	*x = CreateFeeScheduleResponse{}
+	mi := &file_rpc_fee_schedule_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFeeScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFeeScheduleResponse) ProtoMessage() {}
+
+func (x *CreateFeeScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_fee_schedule_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFeeScheduleResponse.ProtoReflect.Descriptor instead.
+func (*CreateFeeScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_fee_schedule_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateFeeScheduleResponse) GetFeeSchedule() *FeeSchedule {
+	if x != nil {
+		return x.FeeSchedule
+	}
+	return nil
+}
+
+type ListFeeSchedulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFeeSchedulesRequest) Reset() {
+	*x = ListFeeSchedulesRequest{}
+	mi := &file_rpc_fee_schedule_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFeeSchedulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFeeSchedulesRequest) ProtoMessage() {}
+
+func (x *ListFeeSchedulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_fee_schedule_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFeeSchedulesRequest.ProtoReflect.Descriptor instead.
+func (*ListFeeSchedulesRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_fee_schedule_proto_rawDescGZIP(), []int{2}
+}
+
+type ListFeeSchedulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FeeSchedules  []*FeeSchedule         `protobuf:"bytes,1,rep,name=fee_schedules,json=feeSchedules,proto3" json:"fee_schedules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFeeSchedulesResponse) Reset() {
+	*x = ListFeeSchedulesResponse{}
+	mi := &file_rpc_fee_schedule_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFeeSchedulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFeeSchedulesResponse) ProtoMessage() {}
+
+func (x *ListFeeSchedulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_fee_schedule_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFeeSchedulesResponse.ProtoReflect.Descriptor instead.
+func (*ListFeeSchedulesResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_fee_schedule_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListFeeSchedulesResponse) GetFeeSchedules() []*FeeSchedule {
+	if x != nil {
+		return x.FeeSchedules
+	}
+	return nil
+}
+
+type DeleteFeeScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFeeScheduleRequest) Reset() {
+	*x = DeleteFeeScheduleRequest{}
+	mi := &file_rpc_fee_schedule_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFeeScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFeeScheduleRequest) ProtoMessage() {}
+
+func (x *DeleteFeeScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_fee_schedule_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFeeScheduleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFeeScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_fee_schedule_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DeleteFeeScheduleRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteFeeScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFeeScheduleResponse) Reset() {
+	*x = DeleteFeeScheduleResponse{}
+	mi := &file_rpc_fee_schedule_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFeeScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFeeScheduleResponse) ProtoMessage() {}
+
+func (x *DeleteFeeScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_fee_schedule_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFeeScheduleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteFeeScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_fee_schedule_proto_rawDescGZIP(), []int{5}
+}
+
+var File_rpc_fee_schedule_proto protoreflect.FileDescriptor
+
+const file_rpc_fee_schedule_proto_rawDesc = "" +
+	"\n" +
+	"\x16rpc_fee_schedule.proto\x12\x02pb\x1a\x12fee_schedule.proto\"\x86\x02\n" +
+	"\x18CreateFeeScheduleRequest\x12#\n" +
+	"\rtransfer_type\x18\x01 \x01(\tR\ftransferType\x12&\n" +
+	"\fproduct_type\x18\x02 \x01(\tH\x00R\vproductType\x88\x01\x01\x12\x19\n" +
+	"\bflat_fee\x18\x03 \x01(\x03R\aflatFee\x12%\n" +
+	"\x0epercentage_bps\x18\x04 \x01(\x05R\rpercentageBps\x121\n" +
+	"\x14percentage_threshold\x18\x05 \x01(\x03R\x13percentageThreshold\x12\x17\n" +
+	"\amax_fee\x18\x06 \x01(\x03R\x06maxFeeB\x0f\n" +
+	"\r_product_type\"O\n" +
+	"\x19CreateFeeScheduleResponse\x122\n" +
+	"\ffee_schedule\x18\x01 \x01(\v2\x0f.pb.FeeScheduleR\vfeeSchedule\"\x19\n" +
+	"\x17ListFeeSchedulesRequest\"P\n" +
+	"\x18ListFeeSchedulesResponse\x124\n" +
+	"\rfee_schedules\x18\x01 \x03(\v2\x0f.pb.FeeScheduleR\ffeeSchedules\"*\n" +
+	"\x18DeleteFeeScheduleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x1b\n" +
+	"\x19DeleteFeeScheduleResponseB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_rpc_fee_schedule_proto_rawDescOnce sync.Once
+	file_rpc_fee_schedule_proto_rawDescData []byte
+)
+
+func file_rpc_fee_schedule_proto_rawDescGZIP() []byte {
+	file_rpc_fee_schedule_proto_rawDescOnce.Do(func() {
+		file_rpc_fee_schedule_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_fee_schedule_proto_rawDesc), len(file_rpc_fee_schedule_proto_rawDesc)))
+	})
+	return file_rpc_fee_schedule_proto_rawDescData
+}
+
+var file_rpc_fee_schedule_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_rpc_fee_schedule_proto_goTypes = []any{
+	(*CreateFeeScheduleRequest)(nil),  // 0: pb.CreateFeeScheduleRequest
+	(*CreateFeeScheduleResponse)(nil), // 1: pb.CreateFeeScheduleResponse
+	(*ListFeeSchedulesRequest)(nil),   // 2: pb.ListFeeSchedulesRequest
+	(*ListFeeSchedulesResponse)(nil),  // 3: pb.ListFeeSchedulesResponse
+	(*DeleteFeeScheduleRequest)(nil),  // 4: pb.DeleteFeeScheduleRequest
+	(*DeleteFeeScheduleResponse)(nil), // 5: pb.DeleteFeeScheduleResponse
+	(*FeeSchedule)(nil),               // 6: pb.FeeSchedule
+}
+var file_rpc_fee_schedule_proto_depIdxs = []int32{
+	6, // 0: pb.CreateFeeScheduleResponse.fee_schedule:type_name -> pb.FeeSchedule
+	6, // 1: pb.ListFeeSchedulesResponse.fee_schedules:type_name -> pb.FeeSchedule
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_fee_schedule_proto_init() }
+func file_rpc_fee_schedule_proto_init() {
+	if File_rpc_fee_schedule_proto != nil {
+		return
+	}
+	file_fee_schedule_proto_init()
+	file_rpc_fee_schedule_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_fee_schedule_proto_rawDesc), len(file_rpc_fee_schedule_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_fee_schedule_proto_goTypes,
+		DependencyIndexes: file_rpc_fee_schedule_proto_depIdxs,
+		MessageInfos:      file_rpc_fee_schedule_proto_msgTypes,
+	}.Build()
+	File_rpc_fee_schedule_proto = out.File
+	file_rpc_fee_schedule_proto_goTypes = nil
+	file_rpc_fee_schedule_proto_depIdxs = nil
+}
@@ -0,0 +1,321 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: rpc_freeze_account.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type FreezeAccountRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// account_id 和 account_number 二选一
+	AccountId int64 `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// debit_frozen: 只进不出; frozen: 双向冻结
+	FreezeStatus  string  `protobuf:"bytes,2,opt,name=freeze_status,json=freezeStatus,proto3" json:"freeze_status,omitempty"`
+	Reason        string  `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	AccountNumber *string `protobuf:"bytes,4,opt,name=account_number,json=accountNumber,proto3,oneof" json:"account_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreezeAccountRequest) Reset() {
+	*x = FreezeAccountRequest{}
+	mi := &file_rpc_freeze_account_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreezeAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreezeAccountRequest) ProtoMessage() {}
+
+func (x *FreezeAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_freeze_account_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreezeAccountRequest.ProtoReflect.Descriptor instead.
+func (*FreezeAccountRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_freeze_account_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FreezeAccountRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *FreezeAccountRequest) GetFreezeStatus() string {
+	if x != nil {
+		return x.FreezeStatus
+	}
+	return ""
+}
+
+func (x *FreezeAccountRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *FreezeAccountRequest) GetAccountNumber() string {
+	if x != nil && x.AccountNumber != nil {
+		return *x.AccountNumber
+	}
+	return ""
+}
+
+type FreezeAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreezeAccountResponse) Reset() {
+	*x = FreezeAccountResponse{}
+	mi := &file_rpc_freeze_account_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreezeAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreezeAccountResponse) ProtoMessage() {}
+
+func (x *FreezeAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_freeze_account_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreezeAccountResponse.ProtoReflect.Descriptor instead.
+func (*FreezeAccountResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_freeze_account_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FreezeAccountResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+type UnfreezeAccountRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// account_id 和 account_number 二选一
+	AccountId     int64   `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Reason        string  `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	AccountNumber *string `protobuf:"bytes,3,opt,name=account_number,json=accountNumber,proto3,oneof" json:"account_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnfreezeAccountRequest) Reset() {
+	*x = UnfreezeAccountRequest{}
+	mi := &file_rpc_freeze_account_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnfreezeAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnfreezeAccountRequest) ProtoMessage() {}
+
+func (x *UnfreezeAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_freeze_account_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnfreezeAccountRequest.ProtoReflect.Descriptor instead.
+func (*UnfreezeAccountRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_freeze_account_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UnfreezeAccountRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *UnfreezeAccountRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *UnfreezeAccountRequest) GetAccountNumber() string {
+	if x != nil && x.AccountNumber != nil {
+		return *x.AccountNumber
+	}
+	return ""
+}
+
+type UnfreezeAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnfreezeAccountResponse) Reset() {
+	*x = UnfreezeAccountResponse{}
+	mi := &file_rpc_freeze_account_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnfreezeAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnfreezeAccountResponse) ProtoMessage() {}
+
+func (x *UnfreezeAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_freeze_account_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnfreezeAccountResponse.ProtoReflect.Descriptor instead.
+func (*UnfreezeAccountResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_freeze_account_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UnfreezeAccountResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+var File_rpc_freeze_account_proto protoreflect.FileDescriptor
+
+const file_rpc_freeze_account_proto_rawDesc = "" +
+	"\n" +
+	"\x18rpc_freeze_account.proto\x12\x02pb\x1a\raccount.proto\"\xb1\x01\n" +
+	"\x14FreezeAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\x12#\n" +
+	"\rfreeze_status\x18\x02 \x01(\tR\ffreezeStatus\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12*\n" +
+	"\x0eaccount_number\x18\x04 \x01(\tH\x00R\raccountNumber\x88\x01\x01B\x11\n" +
+	"\x0f_account_number\">\n" +
+	"\x15FreezeAccountResponse\x12%\n" +
+	"\aaccount\x18\x01 \x01(\v2\v.pb.AccountR\aaccount\"\x8e\x01\n" +
+	"\x16UnfreezeAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12*\n" +
+	"\x0eaccount_number\x18\x03 \x01(\tH\x00R\raccountNumber\x88\x01\x01B\x11\n" +
+	"\x0f_account_number\"@\n" +
+	"\x17UnfreezeAccountResponse\x12%\n" +
+	"\aaccount\x18\x01 \x01(\v2\v.pb.AccountR\aaccountB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_rpc_freeze_account_proto_rawDescOnce sync.Once
+	file_rpc_freeze_account_proto_rawDescData []byte
+)
+
+func file_rpc_freeze_account_proto_rawDescGZIP() []byte {
+	file_rpc_freeze_account_proto_rawDescOnce.Do(func() {
+		file_rpc_freeze_account_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_freeze_account_proto_rawDesc), len(file_rpc_freeze_account_proto_rawDesc)))
+	})
+	return file_rpc_freeze_account_proto_rawDescData
+}
+
+var file_rpc_freeze_account_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_rpc_freeze_account_proto_goTypes = []any{
+	(*FreezeAccountRequest)(nil),    // 0: pb.FreezeAccountRequest
+	(*FreezeAccountResponse)(nil),   // 1: pb.FreezeAccountResponse
+	(*UnfreezeAccountRequest)(nil),  // 2: pb.UnfreezeAccountRequest
+	(*UnfreezeAccountResponse)(nil), // 3: pb.UnfreezeAccountResponse
+	(*Account)(nil),                 // 4: pb.Account
+}
+var file_rpc_freeze_account_proto_depIdxs = []int32{
+	4, // 0: pb.FreezeAccountResponse.account:type_name -> pb.Account
+	4, // 1: pb.UnfreezeAccountResponse.account:type_name -> pb.Account
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_freeze_account_proto_init() }
+func file_rpc_freeze_account_proto_init() {
+	if File_rpc_freeze_account_proto != nil {
+		return
+	}
+	file_account_proto_init()
+	file_rpc_freeze_account_proto_msgTypes[0].OneofWrappers = []any{}
+	file_rpc_freeze_account_proto_msgTypes[2].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_freeze_account_proto_rawDesc), len(file_rpc_freeze_account_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_freeze_account_proto_goTypes,
+		DependencyIndexes: file_rpc_freeze_account_proto_depIdxs,
+		MessageInfos:      file_rpc_freeze_account_proto_msgTypes,
+	}.Build()
+	File_rpc_freeze_account_proto = out.File
+	file_rpc_freeze_account_proto_goTypes = nil
+	file_rpc_freeze_account_proto_depIdxs = nil
+}
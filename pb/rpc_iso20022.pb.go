@@ -0,0 +1,366 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: rpc_iso20022.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExportStatementRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// account_id 和 account_number 二选一
+	AccountId int64 `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// 左闭右开，最长 366 天
+	FromTime      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from_time,json=fromTime,proto3" json:"from_time,omitempty"`
+	ToTime        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to_time,json=toTime,proto3" json:"to_time,omitempty"`
+	AccountNumber *string                `protobuf:"bytes,4,opt,name=account_number,json=accountNumber,proto3,oneof" json:"account_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportStatementRequest) Reset() {
+	*x = ExportStatementRequest{}
+	mi := &file_rpc_iso20022_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportStatementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportStatementRequest) ProtoMessage() {}
+
+func (x *ExportStatementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_iso20022_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportStatementRequest.ProtoReflect.Descriptor instead.
+func (*ExportStatementRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_iso20022_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExportStatementRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *ExportStatementRequest) GetFromTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FromTime
+	}
+	return nil
+}
+
+func (x *ExportStatementRequest) GetToTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ToTime
+	}
+	return nil
+}
+
+func (x *ExportStatementRequest) GetAccountNumber() string {
+	if x != nil && x.AccountNumber != nil {
+		return *x.AccountNumber
+	}
+	return ""
+}
+
+type ExportStatementResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// camt.053.001.02 XML
+	Content        string `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	OpeningBalance int64  `protobuf:"varint,2,opt,name=opening_balance,json=openingBalance,proto3" json:"opening_balance,omitempty"`
+	ClosingBalance int64  `protobuf:"varint,3,opt,name=closing_balance,json=closingBalance,proto3" json:"closing_balance,omitempty"`
+	EntryCount     int32  `protobuf:"varint,4,opt,name=entry_count,json=entryCount,proto3" json:"entry_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExportStatementResponse) Reset() {
+	*x = ExportStatementResponse{}
+	mi := &file_rpc_iso20022_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportStatementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportStatementResponse) ProtoMessage() {}
+
+func (x *ExportStatementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_iso20022_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportStatementResponse.ProtoReflect.Descriptor instead.
+func (*ExportStatementResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_iso20022_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ExportStatementResponse) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ExportStatementResponse) GetOpeningBalance() int64 {
+	if x != nil {
+		return x.OpeningBalance
+	}
+	return 0
+}
+
+func (x *ExportStatementResponse) GetClosingBalance() int64 {
+	if x != nil {
+		return x.ClosingBalance
+	}
+	return 0
+}
+
+func (x *ExportStatementResponse) GetEntryCount() int32 {
+	if x != nil {
+		return x.EntryCount
+	}
+	return 0
+}
+
+type ImportPaymentsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// pain.001.001.03 XML，MsgId 对同一个用户只能导入一次
+	Content       string `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportPaymentsRequest) Reset() {
+	*x = ImportPaymentsRequest{}
+	mi := &file_rpc_iso20022_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportPaymentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportPaymentsRequest) ProtoMessage() {}
+
+func (x *ImportPaymentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_iso20022_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportPaymentsRequest.ProtoReflect.Descriptor instead.
+func (*ImportPaymentsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_iso20022_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ImportPaymentsRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type ImportPaymentsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// pain.002.001.03 XML，逐笔给出结果
+	StatusReport   string `protobuf:"bytes,1,opt,name=status_report,json=statusReport,proto3" json:"status_report,omitempty"`
+	GroupStatus    string `protobuf:"bytes,2,opt,name=group_status,json=groupStatus,proto3" json:"group_status,omitempty"`
+	CompletedCount int32  `protobuf:"varint,3,opt,name=completed_count,json=completedCount,proto3" json:"completed_count,omitempty"`
+	PendingCount   int32  `protobuf:"varint,4,opt,name=pending_count,json=pendingCount,proto3" json:"pending_count,omitempty"`
+	RejectedCount  int32  `protobuf:"varint,5,opt,name=rejected_count,json=rejectedCount,proto3" json:"rejected_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ImportPaymentsResponse) Reset() {
+	*x = ImportPaymentsResponse{}
+	mi := &file_rpc_iso20022_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportPaymentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportPaymentsResponse) ProtoMessage() {}
+
+func (x *ImportPaymentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_iso20022_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportPaymentsResponse.ProtoReflect.Descriptor instead.
+func (*ImportPaymentsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_iso20022_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ImportPaymentsResponse) GetStatusReport() string {
+	if x != nil {
+		return x.StatusReport
+	}
+	return ""
+}
+
+func (x *ImportPaymentsResponse) GetGroupStatus() string {
+	if x != nil {
+		return x.GroupStatus
+	}
+	return ""
+}
+
+func (x *ImportPaymentsResponse) GetCompletedCount() int32 {
+	if x != nil {
+		return x.CompletedCount
+	}
+	return 0
+}
+
+func (x *ImportPaymentsResponse) GetPendingCount() int32 {
+	if x != nil {
+		return x.PendingCount
+	}
+	return 0
+}
+
+func (x *ImportPaymentsResponse) GetRejectedCount() int32 {
+	if x != nil {
+		return x.RejectedCount
+	}
+	return 0
+}
+
+var File_rpc_iso20022_proto protoreflect.FileDescriptor
+
+const file_rpc_iso20022_proto_rawDesc = "" +
+	"\n" +
+	"\x12rpc_iso20022.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe4\x01\n" +
+	"\x16ExportStatementRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\x127\n" +
+	"\tfrom_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bfromTime\x123\n" +
+	"\ato_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x06toTime\x12*\n" +
+	"\x0eaccount_number\x18\x04 \x01(\tH\x00R\raccountNumber\x88\x01\x01B\x11\n" +
+	"\x0f_account_number\"\xa6\x01\n" +
+	"\x17ExportStatementResponse\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\tR\acontent\x12'\n" +
+	"\x0fopening_balance\x18\x02 \x01(\x03R\x0eopeningBalance\x12'\n" +
+	"\x0fclosing_balance\x18\x03 \x01(\x03R\x0eclosingBalance\x12\x1f\n" +
+	"\ventry_count\x18\x04 \x01(\x05R\n" +
+	"entryCount\"1\n" +
+	"\x15ImportPaymentsRequest\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\tR\acontent\"\xd5\x01\n" +
+	"\x16ImportPaymentsResponse\x12#\n" +
+	"\rstatus_report\x18\x01 \x01(\tR\fstatusReport\x12!\n" +
+	"\fgroup_status\x18\x02 \x01(\tR\vgroupStatus\x12'\n" +
+	"\x0fcompleted_count\x18\x03 \x01(\x05R\x0ecompletedCount\x12#\n" +
+	"\rpending_count\x18\x04 \x01(\x05R\fpendingCount\x12%\n" +
+	"\x0erejected_count\x18\x05 \x01(\x05R\rrejectedCountB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_rpc_iso20022_proto_rawDescOnce sync.Once
+	file_rpc_iso20022_proto_rawDescData []byte
+)
+
+func file_rpc_iso20022_proto_rawDescGZIP() []byte {
+	file_rpc_iso20022_proto_rawDescOnce.Do(func() {
+		file_rpc_iso20022_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_iso20022_proto_rawDesc), len(file_rpc_iso20022_proto_rawDesc)))
+	})
+	return file_rpc_iso20022_proto_rawDescData
+}
+
+var file_rpc_iso20022_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_rpc_iso20022_proto_goTypes = []any{
+	(*ExportStatementRequest)(nil),  // 0: pb.ExportStatementRequest
+	(*ExportStatementResponse)(nil), // 1: pb.ExportStatementResponse
+	(*ImportPaymentsRequest)(nil),   // 2: pb.ImportPaymentsRequest
+	(*ImportPaymentsResponse)(nil),  // 3: pb.ImportPaymentsResponse
+	(*timestamppb.Timestamp)(nil),   // 4: google.protobuf.Timestamp
+}
+var file_rpc_iso20022_proto_depIdxs = []int32{
+	4, // 0: pb.ExportStatementRequest.from_time:type_name -> google.protobuf.Timestamp
+	4, // 1: pb.ExportStatementRequest.to_time:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_iso20022_proto_init() }
+func file_rpc_iso20022_proto_init() {
+	if File_rpc_iso20022_proto != nil {
+		return
+	}
+	file_rpc_iso20022_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_iso20022_proto_rawDesc), len(file_rpc_iso20022_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_iso20022_proto_goTypes,
+		DependencyIndexes: file_rpc_iso20022_proto_depIdxs,
+		MessageInfos:      file_rpc_iso20022_proto_msgTypes,
+	}.Build()
+	File_rpc_iso20022_proto = out.File
+	file_rpc_iso20022_proto_goTypes = nil
+	file_rpc_iso20022_proto_depIdxs = nil
+}
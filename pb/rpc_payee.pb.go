@@ -0,0 +1,496 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: rpc_payee.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreatePayeeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nickname      string                 `protobuf:"bytes,2,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	AccountNumber string                 `protobuf:"bytes,3,opt,name=account_number,json=accountNumber,proto3" json:"account_number,omitempty"`
+	// 客户填写的收款人户名，和账户户名一致时才保存
+	HolderName    string `protobuf:"bytes,4,opt,name=holder_name,json=holderName,proto3" json:"holder_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePayeeRequest) Reset() {
+	*x = CreatePayeeRequest{}
+	mi := &file_rpc_payee_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePayeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePayeeRequest) ProtoMessage() {}
+
+func (x *CreatePayeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePayeeRequest.ProtoReflect.Descriptor instead.
+func (*CreatePayeeRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreatePayeeRequest) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+func (x *CreatePayeeRequest) GetAccountNumber() string {
+	if x != nil {
+		return x.AccountNumber
+	}
+	return ""
+}
+
+func (x *CreatePayeeRequest) GetHolderName() string {
+	if x != nil {
+		return x.HolderName
+	}
+	return ""
+}
+
+type CreatePayeeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 户名不一致时为空
+	Payee             *Payee `protobuf:"bytes,1,opt,name=payee,proto3" json:"payee,omitempty"`
+	HolderNameMatched bool   `protobuf:"varint,2,opt,name=holder_name_matched,json=holderNameMatched,proto3" json:"holder_name_matched,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CreatePayeeResponse) Reset() {
+	*x = CreatePayeeResponse{}
+	mi := &file_rpc_payee_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePayeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePayeeResponse) ProtoMessage() {}
+
+func (x *CreatePayeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePayeeResponse.ProtoReflect.Descriptor instead.
+func (*CreatePayeeResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreatePayeeResponse) GetPayee() *Payee {
+	if x != nil {
+		return x.Payee
+	}
+	return nil
+}
+
+func (x *CreatePayeeResponse) GetHolderNameMatched() bool {
+	if x != nil {
+		return x.HolderNameMatched
+	}
+	return false
+}
+
+type ListPayeesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageId        int32                  `protobuf:"varint,1,opt,name=page_id,json=pageId,proto3" json:"page_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPayeesRequest) Reset() {
+	*x = ListPayeesRequest{}
+	mi := &file_rpc_payee_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPayeesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPayeesRequest) ProtoMessage() {}
+
+func (x *ListPayeesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPayeesRequest.ProtoReflect.Descriptor instead.
+func (*ListPayeesRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListPayeesRequest) GetPageId() int32 {
+	if x != nil {
+		return x.PageId
+	}
+	return 0
+}
+
+func (x *ListPayeesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListPayeesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payees        []*Payee               `protobuf:"bytes,1,rep,name=payees,proto3" json:"payees,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPayeesResponse) Reset() {
+	*x = ListPayeesResponse{}
+	mi := &file_rpc_payee_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPayeesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPayeesResponse) ProtoMessage() {}
+
+func (x *ListPayeesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPayeesResponse.ProtoReflect.Descriptor instead.
+func (*ListPayeesResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListPayeesResponse) GetPayees() []*Payee {
+	if x != nil {
+		return x.Payees
+	}
+	return nil
+}
+
+type UpdatePayeeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PayeeId       int64                  `protobuf:"varint,1,opt,name=payee_id,json=payeeId,proto3" json:"payee_id,omitempty"`
+	Nickname      string                 `protobuf:"bytes,2,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePayeeRequest) Reset() {
+	*x = UpdatePayeeRequest{}
+	mi := &file_rpc_payee_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePayeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePayeeRequest) ProtoMessage() {}
+
+func (x *UpdatePayeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePayeeRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePayeeRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdatePayeeRequest) GetPayeeId() int64 {
+	if x != nil {
+		return x.PayeeId
+	}
+	return 0
+}
+
+func (x *UpdatePayeeRequest) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+type UpdatePayeeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payee         *Payee                 `protobuf:"bytes,1,opt,name=payee,proto3" json:"payee,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePayeeResponse) Reset() {
+	*x = UpdatePayeeResponse{}
+	mi := &file_rpc_payee_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePayeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePayeeResponse) ProtoMessage() {}
+
+func (x *UpdatePayeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePayeeResponse.ProtoReflect.Descriptor instead.
+func (*UpdatePayeeResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdatePayeeResponse) GetPayee() *Payee {
+	if x != nil {
+		return x.Payee
+	}
+	return nil
+}
+
+type DeletePayeeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PayeeId       int64                  `protobuf:"varint,1,opt,name=payee_id,json=payeeId,proto3" json:"payee_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePayeeRequest) Reset() {
+	*x = DeletePayeeRequest{}
+	mi := &file_rpc_payee_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePayeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePayeeRequest) ProtoMessage() {}
+
+func (x *DeletePayeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePayeeRequest.ProtoReflect.Descriptor instead.
+func (*DeletePayeeRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeletePayeeRequest) GetPayeeId() int64 {
+	if x != nil {
+		return x.PayeeId
+	}
+	return 0
+}
+
+type DeletePayeeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePayeeResponse) Reset() {
+	*x = DeletePayeeResponse{}
+	mi := &file_rpc_payee_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePayeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePayeeResponse) ProtoMessage() {}
+
+func (x *DeletePayeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payee_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePayeeResponse.ProtoReflect.Descriptor instead.
+func (*DeletePayeeResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_payee_proto_rawDescGZIP(), []int{7}
+}
+
+var File_rpc_payee_proto protoreflect.FileDescriptor
+
+const file_rpc_payee_proto_rawDesc = "" +
+	"\n" +
+	"\x0frpc_payee.proto\x12\x02pb\x1a\vpayee.proto\"\x8a\x01\n" +
+	"\x12CreatePayeeRequest\x12\x1a\n" +
+	"\bnickname\x18\x02 \x01(\tR\bnickname\x12%\n" +
+	"\x0eaccount_number\x18\x03 \x01(\tR\raccountNumber\x12\x1f\n" +
+	"\vholder_name\x18\x04 \x01(\tR\n" +
+	"holderNameJ\x04\b\x01\x10\x02R\n" +
+	"account_id\"f\n" +
+	"\x13CreatePayeeResponse\x12\x1f\n" +
+	"\x05payee\x18\x01 \x01(\v2\t.pb.PayeeR\x05payee\x12.\n" +
+	"\x13holder_name_matched\x18\x02 \x01(\bR\x11holderNameMatched\"I\n" +
+	"\x11ListPayeesRequest\x12\x17\n" +
+	"\apage_id\x18\x01 \x01(\x05R\x06pageId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\"7\n" +
+	"\x12ListPayeesResponse\x12!\n" +
+	"\x06payees\x18\x01 \x03(\v2\t.pb.PayeeR\x06payees\"K\n" +
+	"\x12UpdatePayeeRequest\x12\x19\n" +
+	"\bpayee_id\x18\x01 \x01(\x03R\apayeeId\x12\x1a\n" +
+	"\bnickname\x18\x02 \x01(\tR\bnickname\"6\n" +
+	"\x13UpdatePayeeResponse\x12\x1f\n" +
+	"\x05payee\x18\x01 \x01(\v2\t.pb.PayeeR\x05payee\"/\n" +
+	"\x12DeletePayeeRequest\x12\x19\n" +
+	"\bpayee_id\x18\x01 \x01(\x03R\apayeeId\"\x15\n" +
+	"\x13DeletePayeeResponseB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_rpc_payee_proto_rawDescOnce sync.Once
+	file_rpc_payee_proto_rawDescData []byte
+)
+
+func file_rpc_payee_proto_rawDescGZIP() []byte {
+	file_rpc_payee_proto_rawDescOnce.Do(func() {
+		file_rpc_payee_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_payee_proto_rawDesc), len(file_rpc_payee_proto_rawDesc)))
+	})
+	return file_rpc_payee_proto_rawDescData
+}
+
+var file_rpc_payee_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_rpc_payee_proto_goTypes = []any{
+	(*CreatePayeeRequest)(nil),  // 0: pb.CreatePayeeRequest
+	(*CreatePayeeResponse)(nil), // 1: pb.CreatePayeeResponse
+	(*ListPayeesRequest)(nil),   // 2: pb.ListPayeesRequest
+	(*ListPayeesResponse)(nil),  // 3: pb.ListPayeesResponse
+	(*UpdatePayeeRequest)(nil),  // 4: pb.UpdatePayeeRequest
+	(*UpdatePayeeResponse)(nil), // 5: pb.UpdatePayeeResponse
+	(*DeletePayeeRequest)(nil),  // 6: pb.DeletePayeeRequest
+	(*DeletePayeeResponse)(nil), // 7: pb.DeletePayeeResponse
+	(*Payee)(nil),               // 8: pb.Payee
+}
+var file_rpc_payee_proto_depIdxs = []int32{
+	8, // 0: pb.CreatePayeeResponse.payee:type_name -> pb.Payee
+	8, // 1: pb.ListPayeesResponse.payees:type_name -> pb.Payee
+	8, // 2: pb.UpdatePayeeResponse.payee:type_name -> pb.Payee
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_rpc_payee_proto_init() }
+func file_rpc_payee_proto_init() {
+	if File_rpc_payee_proto != nil {
+		return
+	}
+	file_payee_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_payee_proto_rawDesc), len(file_rpc_payee_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_payee_proto_goTypes,
+		DependencyIndexes: file_rpc_payee_proto_depIdxs,
+		MessageInfos:      file_rpc_payee_proto_msgTypes,
+	}.Build()
+	File_rpc_payee_proto = out.File
+	file_rpc_payee_proto_goTypes = nil
+	file_rpc_payee_proto_depIdxs = nil
+}
@@ -0,0 +1,584 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: rpc_payment_request.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreatePaymentRequestRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Payer string                 `protobuf:"bytes,1,opt,name=payer,proto3" json:"payer,omitempty"`
+	// 发起人自己的收款账户，to_account_id 和 to_account_number 二选一
+	ToAccountId int64  `protobuf:"varint,2,opt,name=to_account_id,json=toAccountId,proto3" json:"to_account_id,omitempty"`
+	Amount      int64  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency    string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	Memo        string `protobuf:"bytes,5,opt,name=memo,proto3" json:"memo,omitempty"`
+	// 不传默认 7 天后过期
+	ExpiresAt       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3,oneof" json:"expires_at,omitempty"`
+	ToAccountNumber *string                `protobuf:"bytes,7,opt,name=to_account_number,json=toAccountNumber,proto3,oneof" json:"to_account_number,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreatePaymentRequestRequest) Reset() {
+	*x = CreatePaymentRequestRequest{}
+	mi := &file_rpc_payment_request_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePaymentRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePaymentRequestRequest) ProtoMessage() {}
+
+func (x *CreatePaymentRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payment_request_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePaymentRequestRequest.ProtoReflect.Descriptor instead.
+func (*CreatePaymentRequestRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_payment_request_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreatePaymentRequestRequest) GetPayer() string {
+	if x != nil {
+		return x.Payer
+	}
+	return ""
+}
+
+func (x *CreatePaymentRequestRequest) GetToAccountId() int64 {
+	if x != nil {
+		return x.ToAccountId
+	}
+	return 0
+}
+
+func (x *CreatePaymentRequestRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CreatePaymentRequestRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreatePaymentRequestRequest) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
+func (x *CreatePaymentRequestRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *CreatePaymentRequestRequest) GetToAccountNumber() string {
+	if x != nil && x.ToAccountNumber != nil {
+		return *x.ToAccountNumber
+	}
+	return ""
+}
+
+type CreatePaymentRequestResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PaymentRequest *PaymentRequest        `protobuf:"bytes,1,opt,name=payment_request,json=paymentRequest,proto3" json:"payment_request,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreatePaymentRequestResponse) Reset() {
+	*x = CreatePaymentRequestResponse{}
+	mi := &file_rpc_payment_request_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePaymentRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePaymentRequestResponse) ProtoMessage() {}
+
+func (x *CreatePaymentRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payment_request_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePaymentRequestResponse.ProtoReflect.Descriptor instead.
+func (*CreatePaymentRequestResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_payment_request_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreatePaymentRequestResponse) GetPaymentRequest() *PaymentRequest {
+	if x != nil {
+		return x.PaymentRequest
+	}
+	return nil
+}
+
+type ListPaymentRequestsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	PageId   int32                  `protobuf:"varint,1,opt,name=page_id,json=pageId,proto3" json:"page_id,omitempty"`
+	PageSize int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// false: 别人向我发起的; true: 我发起的
+	Outgoing      bool `protobuf:"varint,3,opt,name=outgoing,proto3" json:"outgoing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPaymentRequestsRequest) Reset() {
+	*x = ListPaymentRequestsRequest{}
+	mi := &file_rpc_payment_request_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentRequestsRequest) ProtoMessage() {}
+
+func (x *ListPaymentRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payment_request_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentRequestsRequest.ProtoReflect.Descriptor instead.
+func (*ListPaymentRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_payment_request_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListPaymentRequestsRequest) GetPageId() int32 {
+	if x != nil {
+		return x.PageId
+	}
+	return 0
+}
+
+func (x *ListPaymentRequestsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListPaymentRequestsRequest) GetOutgoing() bool {
+	if x != nil {
+		return x.Outgoing
+	}
+	return false
+}
+
+type ListPaymentRequestsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PaymentRequests []*PaymentRequest      `protobuf:"bytes,1,rep,name=payment_requests,json=paymentRequests,proto3" json:"payment_requests,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListPaymentRequestsResponse) Reset() {
+	*x = ListPaymentRequestsResponse{}
+	mi := &file_rpc_payment_request_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentRequestsResponse) ProtoMessage() {}
+
+func (x *ListPaymentRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payment_request_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentRequestsResponse.ProtoReflect.Descriptor instead.
+func (*ListPaymentRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_payment_request_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListPaymentRequestsResponse) GetPaymentRequests() []*PaymentRequest {
+	if x != nil {
+		return x.PaymentRequests
+	}
+	return nil
+}
+
+type AcceptPaymentRequestRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	PaymentRequestId int64                  `protobuf:"varint,1,opt,name=payment_request_id,json=paymentRequestId,proto3" json:"payment_request_id,omitempty"`
+	// from_account_id 和 from_account_number 二选一
+	FromAccountId     int64   `protobuf:"varint,2,opt,name=from_account_id,json=fromAccountId,proto3" json:"from_account_id,omitempty"`
+	FromAccountNumber *string `protobuf:"bytes,3,opt,name=from_account_number,json=fromAccountNumber,proto3,oneof" json:"from_account_number,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AcceptPaymentRequestRequest) Reset() {
+	*x = AcceptPaymentRequestRequest{}
+	mi := &file_rpc_payment_request_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptPaymentRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptPaymentRequestRequest) ProtoMessage() {}
+
+func (x *AcceptPaymentRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payment_request_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptPaymentRequestRequest.ProtoReflect.Descriptor instead.
+func (*AcceptPaymentRequestRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_payment_request_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AcceptPaymentRequestRequest) GetPaymentRequestId() int64 {
+	if x != nil {
+		return x.PaymentRequestId
+	}
+	return 0
+}
+
+func (x *AcceptPaymentRequestRequest) GetFromAccountId() int64 {
+	if x != nil {
+		return x.FromAccountId
+	}
+	return 0
+}
+
+func (x *AcceptPaymentRequestRequest) GetFromAccountNumber() string {
+	if x != nil && x.FromAccountNumber != nil {
+		return *x.FromAccountNumber
+	}
+	return ""
+}
+
+type AcceptPaymentRequestResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PaymentRequest *PaymentRequest        `protobuf:"bytes,1,opt,name=payment_request,json=paymentRequest,proto3" json:"payment_request,omitempty"`
+	Transfer       *Transfer              `protobuf:"bytes,2,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	FromAccount    *Account               `protobuf:"bytes,3,opt,name=from_account,json=fromAccount,proto3" json:"from_account,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AcceptPaymentRequestResponse) Reset() {
+	*x = AcceptPaymentRequestResponse{}
+	mi := &file_rpc_payment_request_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptPaymentRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptPaymentRequestResponse) ProtoMessage() {}
+
+func (x *AcceptPaymentRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payment_request_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptPaymentRequestResponse.ProtoReflect.Descriptor instead.
+func (*AcceptPaymentRequestResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_payment_request_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AcceptPaymentRequestResponse) GetPaymentRequest() *PaymentRequest {
+	if x != nil {
+		return x.PaymentRequest
+	}
+	return nil
+}
+
+func (x *AcceptPaymentRequestResponse) GetTransfer() *Transfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *AcceptPaymentRequestResponse) GetFromAccount() *Account {
+	if x != nil {
+		return x.FromAccount
+	}
+	return nil
+}
+
+type DeclinePaymentRequestRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	PaymentRequestId int64                  `protobuf:"varint,1,opt,name=payment_request_id,json=paymentRequestId,proto3" json:"payment_request_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DeclinePaymentRequestRequest) Reset() {
+	*x = DeclinePaymentRequestRequest{}
+	mi := &file_rpc_payment_request_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeclinePaymentRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeclinePaymentRequestRequest) ProtoMessage() {}
+
+func (x *DeclinePaymentRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payment_request_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeclinePaymentRequestRequest.ProtoReflect.Descriptor instead.
+func (*DeclinePaymentRequestRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_payment_request_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeclinePaymentRequestRequest) GetPaymentRequestId() int64 {
+	if x != nil {
+		return x.PaymentRequestId
+	}
+	return 0
+}
+
+type DeclinePaymentRequestResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PaymentRequest *PaymentRequest        `protobuf:"bytes,1,opt,name=payment_request,json=paymentRequest,proto3" json:"payment_request,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DeclinePaymentRequestResponse) Reset() {
+	*x = DeclinePaymentRequestResponse{}
+	mi := &file_rpc_payment_request_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeclinePaymentRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeclinePaymentRequestResponse) ProtoMessage() {}
+
+func (x *DeclinePaymentRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_payment_request_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeclinePaymentRequestResponse.ProtoReflect.Descriptor instead.
+func (*DeclinePaymentRequestResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_payment_request_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeclinePaymentRequestResponse) GetPaymentRequest() *PaymentRequest {
+	if x != nil {
+		return x.PaymentRequest
+	}
+	return nil
+}
+
+var File_rpc_payment_request_proto protoreflect.FileDescriptor
+
+const file_rpc_payment_request_proto_rawDesc = "" +
+	"\n" +
+	"\x19rpc_payment_request.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\raccount.proto\x1a\x15payment_request.proto\x1a\x0etransfer.proto\"\xb5\x02\n" +
+	"\x1bCreatePaymentRequestRequest\x12\x14\n" +
+	"\x05payer\x18\x01 \x01(\tR\x05payer\x12\"\n" +
+	"\rto_account_id\x18\x02 \x01(\x03R\vtoAccountId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x12\n" +
+	"\x04memo\x18\x05 \x01(\tR\x04memo\x12>\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampH\x00R\texpiresAt\x88\x01\x01\x12/\n" +
+	"\x11to_account_number\x18\a \x01(\tH\x01R\x0ftoAccountNumber\x88\x01\x01B\r\n" +
+	"\v_expires_atB\x14\n" +
+	"\x12_to_account_number\"[\n" +
+	"\x1cCreatePaymentRequestResponse\x12;\n" +
+	"\x0fpayment_request\x18\x01 \x01(\v2\x12.pb.PaymentRequestR\x0epaymentRequest\"n\n" +
+	"\x1aListPaymentRequestsRequest\x12\x17\n" +
+	"\apage_id\x18\x01 \x01(\x05R\x06pageId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1a\n" +
+	"\boutgoing\x18\x03 \x01(\bR\boutgoing\"\\\n" +
+	"\x1bListPaymentRequestsResponse\x12=\n" +
+	"\x10payment_requests\x18\x01 \x03(\v2\x12.pb.PaymentRequestR\x0fpaymentRequests\"\xc0\x01\n" +
+	"\x1bAcceptPaymentRequestRequest\x12,\n" +
+	"\x12payment_request_id\x18\x01 \x01(\x03R\x10paymentRequestId\x12&\n" +
+	"\x0ffrom_account_id\x18\x02 \x01(\x03R\rfromAccountId\x123\n" +
+	"\x13from_account_number\x18\x03 \x01(\tH\x00R\x11fromAccountNumber\x88\x01\x01B\x16\n" +
+	"\x14_from_account_number\"\xb5\x01\n" +
+	"\x1cAcceptPaymentRequestResponse\x12;\n" +
+	"\x0fpayment_request\x18\x01 \x01(\v2\x12.pb.PaymentRequestR\x0epaymentRequest\x12(\n" +
+	"\btransfer\x18\x02 \x01(\v2\f.pb.TransferR\btransfer\x12.\n" +
+	"\ffrom_account\x18\x03 \x01(\v2\v.pb.AccountR\vfromAccount\"L\n" +
+	"\x1cDeclinePaymentRequestRequest\x12,\n" +
+	"\x12payment_request_id\x18\x01 \x01(\x03R\x10paymentRequestId\"\\\n" +
+	"\x1dDeclinePaymentRequestResponse\x12;\n" +
+	"\x0fpayment_request\x18\x01 \x01(\v2\x12.pb.PaymentRequestR\x0epaymentRequestB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_rpc_payment_request_proto_rawDescOnce sync.Once
+	file_rpc_payment_request_proto_rawDescData []byte
+)
+
+func file_rpc_payment_request_proto_rawDescGZIP() []byte {
+	file_rpc_payment_request_proto_rawDescOnce.Do(func() {
+		file_rpc_payment_request_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_payment_request_proto_rawDesc), len(file_rpc_payment_request_proto_rawDesc)))
+	})
+	return file_rpc_payment_request_proto_rawDescData
+}
+
+var file_rpc_payment_request_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_rpc_payment_request_proto_goTypes = []any{
+	(*CreatePaymentRequestRequest)(nil),   // 0: pb.CreatePaymentRequestRequest
+	(*CreatePaymentRequestResponse)(nil),  // 1: pb.CreatePaymentRequestResponse
+	(*ListPaymentRequestsRequest)(nil),    // 2: pb.ListPaymentRequestsRequest
+	(*ListPaymentRequestsResponse)(nil),   // 3: pb.ListPaymentRequestsResponse
+	(*AcceptPaymentRequestRequest)(nil),   // 4: pb.AcceptPaymentRequestRequest
+	(*AcceptPaymentRequestResponse)(nil),  // 5: pb.AcceptPaymentRequestResponse
+	(*DeclinePaymentRequestRequest)(nil),  // 6: pb.DeclinePaymentRequestRequest
+	(*DeclinePaymentRequestResponse)(nil), // 7: pb.DeclinePaymentRequestResponse
+	(*timestamppb.Timestamp)(nil),         // 8: google.protobuf.Timestamp
+	(*PaymentRequest)(nil),                // 9: pb.PaymentRequest
+	(*Transfer)(nil),                      // 10: pb.Transfer
+	(*Account)(nil),                       // 11: pb.Account
+}
+var file_rpc_payment_request_proto_depIdxs = []int32{
+	8,  // 0: pb.CreatePaymentRequestRequest.expires_at:type_name -> google.protobuf.Timestamp
+	9,  // 1: pb.CreatePaymentRequestResponse.payment_request:type_name -> pb.PaymentRequest
+	9,  // 2: pb.ListPaymentRequestsResponse.payment_requests:type_name -> pb.PaymentRequest
+	9,  // 3: pb.AcceptPaymentRequestResponse.payment_request:type_name -> pb.PaymentRequest
+	10, // 4: pb.AcceptPaymentRequestResponse.transfer:type_name -> pb.Transfer
+	11, // 5: pb.AcceptPaymentRequestResponse.from_account:type_name -> pb.Account
+	9,  // 6: pb.DeclinePaymentRequestResponse.payment_request:type_name -> pb.PaymentRequest
+	7,  // [7:7] is the sub-list for method output_type
+	7,  // [7:7] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_rpc_payment_request_proto_init() }
+func file_rpc_payment_request_proto_init() {
+	if File_rpc_payment_request_proto != nil {
+		return
+	}
+	file_account_proto_init()
+	file_payment_request_proto_init()
+	file_transfer_proto_init()
+	file_rpc_payment_request_proto_msgTypes[0].OneofWrappers = []any{}
+	file_rpc_payment_request_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_payment_request_proto_rawDesc), len(file_rpc_payment_request_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_payment_request_proto_goTypes,
+		DependencyIndexes: file_rpc_payment_request_proto_depIdxs,
+		MessageInfos:      file_rpc_payment_request_proto_msgTypes,
+	}.Build()
+	File_rpc_payment_request_proto = out.File
+	file_rpc_payment_request_proto_goTypes = nil
+	file_rpc_payment_request_proto_depIdxs = nil
+}
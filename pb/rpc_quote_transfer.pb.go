@@ -0,0 +1,342 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: rpc_quote_transfer.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 收付款方字段和 CreateTransferRequest 相同
+type QuoteTransferRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	FromAccountId     int64                  `protobuf:"varint,1,opt,name=from_account_id,json=fromAccountId,proto3" json:"from_account_id,omitempty"`
+	ToAccountId       int64                  `protobuf:"varint,2,opt,name=to_account_id,json=toAccountId,proto3" json:"to_account_id,omitempty"`
+	Amount            int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency          string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	PayeeId           *int64                 `protobuf:"varint,5,opt,name=payee_id,json=payeeId,proto3,oneof" json:"payee_id,omitempty"`
+	Recipient         *string                `protobuf:"bytes,6,opt,name=recipient,proto3,oneof" json:"recipient,omitempty"`
+	FromAccountNumber *string                `protobuf:"bytes,7,opt,name=from_account_number,json=fromAccountNumber,proto3,oneof" json:"from_account_number,omitempty"`
+	ToAccountNumber   *string                `protobuf:"bytes,8,opt,name=to_account_number,json=toAccountNumber,proto3,oneof" json:"to_account_number,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *QuoteTransferRequest) Reset() {
+	*x = QuoteTransferRequest{}
+	mi := &file_rpc_quote_transfer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuoteTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuoteTransferRequest) ProtoMessage() {}
+
+func (x *QuoteTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_quote_transfer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuoteTransferRequest.ProtoReflect.Descriptor instead.
+func (*QuoteTransferRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_quote_transfer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *QuoteTransferRequest) GetFromAccountId() int64 {
+	if x != nil {
+		return x.FromAccountId
+	}
+	return 0
+}
+
+func (x *QuoteTransferRequest) GetToAccountId() int64 {
+	if x != nil {
+		return x.ToAccountId
+	}
+	return 0
+}
+
+func (x *QuoteTransferRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *QuoteTransferRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *QuoteTransferRequest) GetPayeeId() int64 {
+	if x != nil && x.PayeeId != nil {
+		return *x.PayeeId
+	}
+	return 0
+}
+
+func (x *QuoteTransferRequest) GetRecipient() string {
+	if x != nil && x.Recipient != nil {
+		return *x.Recipient
+	}
+	return ""
+}
+
+func (x *QuoteTransferRequest) GetFromAccountNumber() string {
+	if x != nil && x.FromAccountNumber != nil {
+		return *x.FromAccountNumber
+	}
+	return ""
+}
+
+func (x *QuoteTransferRequest) GetToAccountNumber() string {
+	if x != nil && x.ToAccountNumber != nil {
+		return *x.ToAccountNumber
+	}
+	return ""
+}
+
+type QuoteTransferResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 在 expires_at 之前带上它调用 CreateTransfer，按报价的手续费转账，只能用一次
+	QuoteId string `protobuf:"bytes,1,opt,name=quote_id,json=quoteId,proto3" json:"quote_id,omitempty"`
+	Amount  int64  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// 暂不支持换汇，两边币种必须一致，收款金额等于 amount
+	RecipientAmount int64  `protobuf:"varint,3,opt,name=recipient_amount,json=recipientAmount,proto3" json:"recipient_amount,omitempty"`
+	Fee             int64  `protobuf:"varint,4,opt,name=fee,proto3" json:"fee,omitempty"`
+	TotalDebit      int64  `protobuf:"varint,5,opt,name=total_debit,json=totalDebit,proto3" json:"total_debit,omitempty"`
+	Currency        string `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	TransferType    string `protobuf:"bytes,7,opt,name=transfer_type,json=transferType,proto3" json:"transfer_type,omitempty"`
+	// 转出账户按当前余额试算的转账后余额
+	BalanceAfter int64 `protobuf:"varint,8,opt,name=balance_after,json=balanceAfter,proto3" json:"balance_after,omitempty"`
+	// 转出账户产品本月剩余的转出次数，没有限制时不返回
+	RemainingMonthlyTransfers *int64                 `protobuf:"varint,9,opt,name=remaining_monthly_transfers,json=remainingMonthlyTransfers,proto3,oneof" json:"remaining_monthly_transfers,omitempty"`
+	ExpiresAt                 *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// 超过审批阈值，转账会先进入待审批状态
+	RequiresApproval bool `protobuf:"varint,11,opt,name=requires_approval,json=requiresApproval,proto3" json:"requires_approval,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *QuoteTransferResponse) Reset() {
+	*x = QuoteTransferResponse{}
+	mi := &file_rpc_quote_transfer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuoteTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuoteTransferResponse) ProtoMessage() {}
+
+func (x *QuoteTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_quote_transfer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuoteTransferResponse.ProtoReflect.Descriptor instead.
+func (*QuoteTransferResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_quote_transfer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *QuoteTransferResponse) GetQuoteId() string {
+	if x != nil {
+		return x.QuoteId
+	}
+	return ""
+}
+
+func (x *QuoteTransferResponse) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *QuoteTransferResponse) GetRecipientAmount() int64 {
+	if x != nil {
+		return x.RecipientAmount
+	}
+	return 0
+}
+
+func (x *QuoteTransferResponse) GetFee() int64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *QuoteTransferResponse) GetTotalDebit() int64 {
+	if x != nil {
+		return x.TotalDebit
+	}
+	return 0
+}
+
+func (x *QuoteTransferResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *QuoteTransferResponse) GetTransferType() string {
+	if x != nil {
+		return x.TransferType
+	}
+	return ""
+}
+
+func (x *QuoteTransferResponse) GetBalanceAfter() int64 {
+	if x != nil {
+		return x.BalanceAfter
+	}
+	return 0
+}
+
+func (x *QuoteTransferResponse) GetRemainingMonthlyTransfers() int64 {
+	if x != nil && x.RemainingMonthlyTransfers != nil {
+		return *x.RemainingMonthlyTransfers
+	}
+	return 0
+}
+
+func (x *QuoteTransferResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *QuoteTransferResponse) GetRequiresApproval() bool {
+	if x != nil {
+		return x.RequiresApproval
+	}
+	return false
+}
+
+var File_rpc_quote_transfer_proto protoreflect.FileDescriptor
+
+const file_rpc_quote_transfer_proto_rawDesc = "" +
+	"\n" +
+	"\x18rpc_quote_transfer.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\"\x88\x03\n" +
+	"\x14QuoteTransferRequest\x12&\n" +
+	"\x0ffrom_account_id\x18\x01 \x01(\x03R\rfromAccountId\x12\"\n" +
+	"\rto_account_id\x18\x02 \x01(\x03R\vtoAccountId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x1e\n" +
+	"\bpayee_id\x18\x05 \x01(\x03H\x00R\apayeeId\x88\x01\x01\x12!\n" +
+	"\trecipient\x18\x06 \x01(\tH\x01R\trecipient\x88\x01\x01\x123\n" +
+	"\x13from_account_number\x18\a \x01(\tH\x02R\x11fromAccountNumber\x88\x01\x01\x12/\n" +
+	"\x11to_account_number\x18\b \x01(\tH\x03R\x0ftoAccountNumber\x88\x01\x01B\v\n" +
+	"\t_payee_idB\f\n" +
+	"\n" +
+	"_recipientB\x16\n" +
+	"\x14_from_account_numberB\x14\n" +
+	"\x12_to_account_number\"\xdb\x03\n" +
+	"\x15QuoteTransferResponse\x12\x19\n" +
+	"\bquote_id\x18\x01 \x01(\tR\aquoteId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12)\n" +
+	"\x10recipient_amount\x18\x03 \x01(\x03R\x0frecipientAmount\x12\x10\n" +
+	"\x03fee\x18\x04 \x01(\x03R\x03fee\x12\x1f\n" +
+	"\vtotal_debit\x18\x05 \x01(\x03R\n" +
+	"totalDebit\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12#\n" +
+	"\rtransfer_type\x18\a \x01(\tR\ftransferType\x12#\n" +
+	"\rbalance_after\x18\b \x01(\x03R\fbalanceAfter\x12C\n" +
+	"\x1bremaining_monthly_transfers\x18\t \x01(\x03H\x00R\x19remainingMonthlyTransfers\x88\x01\x01\x129\n" +
+	"\n" +
+	"expires_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12+\n" +
+	"\x11requires_approval\x18\v \x01(\bR\x10requiresApprovalB\x1e\n" +
+	"\x1c_remaining_monthly_transfersB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_rpc_quote_transfer_proto_rawDescOnce sync.Once
+	file_rpc_quote_transfer_proto_rawDescData []byte
+)
+
+func file_rpc_quote_transfer_proto_rawDescGZIP() []byte {
+	file_rpc_quote_transfer_proto_rawDescOnce.Do(func() {
+		file_rpc_quote_transfer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_quote_transfer_proto_rawDesc), len(file_rpc_quote_transfer_proto_rawDesc)))
+	})
+	return file_rpc_quote_transfer_proto_rawDescData
+}
+
+var file_rpc_quote_transfer_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_quote_transfer_proto_goTypes = []any{
+	(*QuoteTransferRequest)(nil),  // 0: pb.QuoteTransferRequest
+	(*QuoteTransferResponse)(nil), // 1: pb.QuoteTransferResponse
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+}
+var file_rpc_quote_transfer_proto_depIdxs = []int32{
+	2, // 0: pb.QuoteTransferResponse.expires_at:type_name -> google.protobuf.Timestamp
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_quote_transfer_proto_init() }
+func file_rpc_quote_transfer_proto_init() {
+	if File_rpc_quote_transfer_proto != nil {
+		return
+	}
+	file_rpc_quote_transfer_proto_msgTypes[0].OneofWrappers = []any{}
+	file_rpc_quote_transfer_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_quote_transfer_proto_rawDesc), len(file_rpc_quote_transfer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_quote_transfer_proto_goTypes,
+		DependencyIndexes: file_rpc_quote_transfer_proto_depIdxs,
+		MessageInfos:      file_rpc_quote_transfer_proto_msgTypes,
+	}.Build()
+	File_rpc_quote_transfer_proto = out.File
+	file_rpc_quote_transfer_proto_goTypes = nil
+	file_rpc_quote_transfer_proto_depIdxs = nil
+}
@@ -0,0 +1,195 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: rpc_set_overdraft_limit.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SetOverdraftLimitRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// account_id 和 account_number 二选一
+	AccountId int64 `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// 0 表示取消透支
+	OverdraftLimit int64   `protobuf:"varint,2,opt,name=overdraft_limit,json=overdraftLimit,proto3" json:"overdraft_limit,omitempty"`
+	AccountNumber  *string `protobuf:"bytes,3,opt,name=account_number,json=accountNumber,proto3,oneof" json:"account_number,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SetOverdraftLimitRequest) Reset() {
+	*x = SetOverdraftLimitRequest{}
+	mi := &file_rpc_set_overdraft_limit_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetOverdraftLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetOverdraftLimitRequest) ProtoMessage() {}
+
+func (x *SetOverdraftLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_set_overdraft_limit_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetOverdraftLimitRequest.ProtoReflect.Descriptor instead.
+func (*SetOverdraftLimitRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_set_overdraft_limit_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SetOverdraftLimitRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *SetOverdraftLimitRequest) GetOverdraftLimit() int64 {
+	if x != nil {
+		return x.OverdraftLimit
+	}
+	return 0
+}
+
+func (x *SetOverdraftLimitRequest) GetAccountNumber() string {
+	if x != nil && x.AccountNumber != nil {
+		return *x.AccountNumber
+	}
+	return ""
+}
+
+type SetOverdraftLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetOverdraftLimitResponse) Reset() {
+	*x = SetOverdraftLimitResponse{}
+	mi := &file_rpc_set_overdraft_limit_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetOverdraftLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetOverdraftLimitResponse) ProtoMessage() {}
+
+func (x *SetOverdraftLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_set_overdraft_limit_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetOverdraftLimitResponse.ProtoReflect.Descriptor instead.
+func (*SetOverdraftLimitResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_set_overdraft_limit_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SetOverdraftLimitResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+var File_rpc_set_overdraft_limit_proto protoreflect.FileDescriptor
+
+const file_rpc_set_overdraft_limit_proto_rawDesc = "" +
+	"\n" +
+	"\x1drpc_set_overdraft_limit.proto\x12\x02pb\x1a\raccount.proto\"\xa1\x01\n" +
+	"\x18SetOverdraftLimitRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\x12'\n" +
+	"\x0foverdraft_limit\x18\x02 \x01(\x03R\x0eoverdraftLimit\x12*\n" +
+	"\x0eaccount_number\x18\x03 \x01(\tH\x00R\raccountNumber\x88\x01\x01B\x11\n" +
+	"\x0f_account_number\"B\n" +
+	"\x19SetOverdraftLimitResponse\x12%\n" +
+	"\aaccount\x18\x01 \x01(\v2\v.pb.AccountR\aaccountB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_rpc_set_overdraft_limit_proto_rawDescOnce sync.Once
+	file_rpc_set_overdraft_limit_proto_rawDescData []byte
+)
+
+func file_rpc_set_overdraft_limit_proto_rawDescGZIP() []byte {
+	file_rpc_set_overdraft_limit_proto_rawDescOnce.Do(func() {
+		file_rpc_set_overdraft_limit_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_set_overdraft_limit_proto_rawDesc), len(file_rpc_set_overdraft_limit_proto_rawDesc)))
+	})
+	return file_rpc_set_overdraft_limit_proto_rawDescData
+}
+
+var file_rpc_set_overdraft_limit_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_set_overdraft_limit_proto_goTypes = []any{
+	(*SetOverdraftLimitRequest)(nil),  // 0: pb.SetOverdraftLimitRequest
+	(*SetOverdraftLimitResponse)(nil), // 1: pb.SetOverdraftLimitResponse
+	(*Account)(nil),                   // 2: pb.Account
+}
+var file_rpc_set_overdraft_limit_proto_depIdxs = []int32{
+	2, // 0: pb.SetOverdraftLimitResponse.account:type_name -> pb.Account
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_set_overdraft_limit_proto_init() }
+func file_rpc_set_overdraft_limit_proto_init() {
+	if File_rpc_set_overdraft_limit_proto != nil {
+		return
+	}
+	file_account_proto_init()
+	file_rpc_set_overdraft_limit_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_set_overdraft_limit_proto_rawDesc), len(file_rpc_set_overdraft_limit_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_set_overdraft_limit_proto_goTypes,
+		DependencyIndexes: file_rpc_set_overdraft_limit_proto_depIdxs,
+		MessageInfos:      file_rpc_set_overdraft_limit_proto_msgTypes,
+	}.Build()
+	File_rpc_set_overdraft_limit_proto = out.File
+	file_rpc_set_overdraft_limit_proto_goTypes = nil
+	file_rpc_set_overdraft_limit_proto_depIdxs = nil
+}
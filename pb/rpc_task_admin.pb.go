@@ -0,0 +1,526 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: rpc_task_admin.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListFailedTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// retry 或 archived
+	State string `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	// 为空时不按队列、类型过滤
+	Queue         string `protobuf:"bytes,2,opt,name=queue,proto3" json:"queue,omitempty"`
+	Type          string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	PageId        int32  `protobuf:"varint,4,opt,name=page_id,json=pageId,proto3" json:"page_id,omitempty"`
+	PageSize      int32  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFailedTasksRequest) Reset() {
+	*x = ListFailedTasksRequest{}
+	mi := &file_rpc_task_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFailedTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFailedTasksRequest) ProtoMessage() {}
+
+func (x *ListFailedTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_task_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFailedTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListFailedTasksRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_task_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListFailedTasksRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ListFailedTasksRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *ListFailedTasksRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ListFailedTasksRequest) GetPageId() int32 {
+	if x != nil {
+		return x.PageId
+	}
+	return 0
+}
+
+func (x *ListFailedTasksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListFailedTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFailedTasksResponse) Reset() {
+	*x = ListFailedTasksResponse{}
+	mi := &file_rpc_task_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFailedTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFailedTasksResponse) ProtoMessage() {}
+
+func (x *ListFailedTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_task_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFailedTasksResponse.ProtoReflect.Descriptor instead.
+func (*ListFailedTasksResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_task_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListFailedTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type GetFailedTaskRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// 为空时在所有队列里找
+	Queue         string `protobuf:"bytes,2,opt,name=queue,proto3" json:"queue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFailedTaskRequest) Reset() {
+	*x = GetFailedTaskRequest{}
+	mi := &file_rpc_task_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFailedTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFailedTaskRequest) ProtoMessage() {}
+
+func (x *GetFailedTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_task_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFailedTaskRequest.ProtoReflect.Descriptor instead.
+func (*GetFailedTaskRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_task_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetFailedTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *GetFailedTaskRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+type GetFailedTaskResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Task  *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	// 最近的失败记录，最新的在前
+	Failures      []*TaskFailure `protobuf:"bytes,2,rep,name=failures,proto3" json:"failures,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFailedTaskResponse) Reset() {
+	*x = GetFailedTaskResponse{}
+	mi := &file_rpc_task_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFailedTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFailedTaskResponse) ProtoMessage() {}
+
+func (x *GetFailedTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_task_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFailedTaskResponse.ProtoReflect.Descriptor instead.
+func (*GetFailedTaskResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_task_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetFailedTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *GetFailedTaskResponse) GetFailures() []*TaskFailure {
+	if x != nil {
+		return x.Failures
+	}
+	return nil
+}
+
+type RetryFailedTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Queue         string                 `protobuf:"bytes,2,opt,name=queue,proto3" json:"queue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryFailedTaskRequest) Reset() {
+	*x = RetryFailedTaskRequest{}
+	mi := &file_rpc_task_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryFailedTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryFailedTaskRequest) ProtoMessage() {}
+
+func (x *RetryFailedTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_task_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryFailedTaskRequest.ProtoReflect.Descriptor instead.
+func (*RetryFailedTaskRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_task_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RetryFailedTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *RetryFailedTaskRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+type RetryFailedTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryFailedTaskResponse) Reset() {
+	*x = RetryFailedTaskResponse{}
+	mi := &file_rpc_task_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryFailedTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryFailedTaskResponse) ProtoMessage() {}
+
+func (x *RetryFailedTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_task_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryFailedTaskResponse.ProtoReflect.Descriptor instead.
+func (*RetryFailedTaskResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_task_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RetryFailedTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+type DeleteFailedTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Queue         string                 `protobuf:"bytes,2,opt,name=queue,proto3" json:"queue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFailedTaskRequest) Reset() {
+	*x = DeleteFailedTaskRequest{}
+	mi := &file_rpc_task_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFailedTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFailedTaskRequest) ProtoMessage() {}
+
+func (x *DeleteFailedTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_task_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFailedTaskRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFailedTaskRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_task_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteFailedTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *DeleteFailedTaskRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+type DeleteFailedTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFailedTaskResponse) Reset() {
+	*x = DeleteFailedTaskResponse{}
+	mi := &file_rpc_task_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFailedTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFailedTaskResponse) ProtoMessage() {}
+
+func (x *DeleteFailedTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_task_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFailedTaskResponse.ProtoReflect.Descriptor instead.
+func (*DeleteFailedTaskResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_task_admin_proto_rawDescGZIP(), []int{7}
+}
+
+var File_rpc_task_admin_proto protoreflect.FileDescriptor
+
+const file_rpc_task_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x14rpc_task_admin.proto\x12\x02pb\x1a\n" +
+	"task.proto\"\x8e\x01\n" +
+	"\x16ListFailedTasksRequest\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\x12\x14\n" +
+	"\x05queue\x18\x02 \x01(\tR\x05queue\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x17\n" +
+	"\apage_id\x18\x04 \x01(\x05R\x06pageId\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\"9\n" +
+	"\x17ListFailedTasksResponse\x12\x1e\n" +
+	"\x05tasks\x18\x01 \x03(\v2\b.pb.TaskR\x05tasks\"E\n" +
+	"\x14GetFailedTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x14\n" +
+	"\x05queue\x18\x02 \x01(\tR\x05queue\"b\n" +
+	"\x15GetFailedTaskResponse\x12\x1c\n" +
+	"\x04task\x18\x01 \x01(\v2\b.pb.TaskR\x04task\x12+\n" +
+	"\bfailures\x18\x02 \x03(\v2\x0f.pb.TaskFailureR\bfailures\"G\n" +
+	"\x16RetryFailedTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x14\n" +
+	"\x05queue\x18\x02 \x01(\tR\x05queue\"7\n" +
+	"\x17RetryFailedTaskResponse\x12\x1c\n" +
+	"\x04task\x18\x01 \x01(\v2\b.pb.TaskR\x04task\"H\n" +
+	"\x17DeleteFailedTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x14\n" +
+	"\x05queue\x18\x02 \x01(\tR\x05queue\"\x1a\n" +
+	"\x18DeleteFailedTaskResponseB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_rpc_task_admin_proto_rawDescOnce sync.Once
+	file_rpc_task_admin_proto_rawDescData []byte
+)
+
+func file_rpc_task_admin_proto_rawDescGZIP() []byte {
+	file_rpc_task_admin_proto_rawDescOnce.Do(func() {
+		file_rpc_task_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_task_admin_proto_rawDesc), len(file_rpc_task_admin_proto_rawDesc)))
+	})
+	return file_rpc_task_admin_proto_rawDescData
+}
+
+var file_rpc_task_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_rpc_task_admin_proto_goTypes = []any{
+	(*ListFailedTasksRequest)(nil),   // 0: pb.ListFailedTasksRequest
+	(*ListFailedTasksResponse)(nil),  // 1: pb.ListFailedTasksResponse
+	(*GetFailedTaskRequest)(nil),     // 2: pb.GetFailedTaskRequest
+	(*GetFailedTaskResponse)(nil),    // 3: pb.GetFailedTaskResponse
+	(*RetryFailedTaskRequest)(nil),   // 4: pb.RetryFailedTaskRequest
+	(*RetryFailedTaskResponse)(nil),  // 5: pb.RetryFailedTaskResponse
+	(*DeleteFailedTaskRequest)(nil),  // 6: pb.DeleteFailedTaskRequest
+	(*DeleteFailedTaskResponse)(nil), // 7: pb.DeleteFailedTaskResponse
+	(*Task)(nil),                     // 8: pb.Task
+	(*TaskFailure)(nil),              // 9: pb.TaskFailure
+}
+var file_rpc_task_admin_proto_depIdxs = []int32{
+	8, // 0: pb.ListFailedTasksResponse.tasks:type_name -> pb.Task
+	8, // 1: pb.GetFailedTaskResponse.task:type_name -> pb.Task
+	9, // 2: pb.GetFailedTaskResponse.failures:type_name -> pb.TaskFailure
+	8, // 3: pb.RetryFailedTaskResponse.task:type_name -> pb.Task
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_rpc_task_admin_proto_init() }
+func file_rpc_task_admin_proto_init() {
+	if File_rpc_task_admin_proto != nil {
+		return
+	}
+	file_task_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_task_admin_proto_rawDesc), len(file_rpc_task_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_task_admin_proto_goTypes,
+		DependencyIndexes: file_rpc_task_admin_proto_depIdxs,
+		MessageInfos:      file_rpc_task_admin_proto_msgTypes,
+	}.Build()
+	File_rpc_task_admin_proto = out.File
+	file_rpc_task_admin_proto_goTypes = nil
+	file_rpc_task_admin_proto_depIdxs = nil
+}
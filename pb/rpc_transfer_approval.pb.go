@@ -0,0 +1,387 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: rpc_transfer_approval.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ApproveTransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransferId    int64                  `protobuf:"varint,1,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveTransferRequest) Reset() {
+	*x = ApproveTransferRequest{}
+	mi := &file_rpc_transfer_approval_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveTransferRequest) ProtoMessage() {}
+
+func (x *ApproveTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_transfer_approval_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveTransferRequest.ProtoReflect.Descriptor instead.
+func (*ApproveTransferRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_transfer_approval_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ApproveTransferRequest) GetTransferId() int64 {
+	if x != nil {
+		return x.TransferId
+	}
+	return 0
+}
+
+type ApproveTransferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transfer      *Transfer              `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	FromAccount   *Account               `protobuf:"bytes,2,opt,name=from_account,json=fromAccount,proto3" json:"from_account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveTransferResponse) Reset() {
+	*x = ApproveTransferResponse{}
+	mi := &file_rpc_transfer_approval_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveTransferResponse) ProtoMessage() {}
+
+func (x *ApproveTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_transfer_approval_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveTransferResponse.ProtoReflect.Descriptor instead.
+func (*ApproveTransferResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_transfer_approval_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ApproveTransferResponse) GetTransfer() *Transfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *ApproveTransferResponse) GetFromAccount() *Account {
+	if x != nil {
+		return x.FromAccount
+	}
+	return nil
+}
+
+type RejectTransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransferId    int64                  `protobuf:"varint,1,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectTransferRequest) Reset() {
+	*x = RejectTransferRequest{}
+	mi := &file_rpc_transfer_approval_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectTransferRequest) ProtoMessage() {}
+
+func (x *RejectTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_transfer_approval_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectTransferRequest.ProtoReflect.Descriptor instead.
+func (*RejectTransferRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_transfer_approval_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RejectTransferRequest) GetTransferId() int64 {
+	if x != nil {
+		return x.TransferId
+	}
+	return 0
+}
+
+type RejectTransferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transfer      *Transfer              `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectTransferResponse) Reset() {
+	*x = RejectTransferResponse{}
+	mi := &file_rpc_transfer_approval_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectTransferResponse) ProtoMessage() {}
+
+func (x *RejectTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_transfer_approval_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectTransferResponse.ProtoReflect.Descriptor instead.
+func (*RejectTransferResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_transfer_approval_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RejectTransferResponse) GetTransfer() *Transfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+type ListPendingTransfersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageId        int32                  `protobuf:"varint,1,opt,name=page_id,json=pageId,proto3" json:"page_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingTransfersRequest) Reset() {
+	*x = ListPendingTransfersRequest{}
+	mi := &file_rpc_transfer_approval_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingTransfersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingTransfersRequest) ProtoMessage() {}
+
+func (x *ListPendingTransfersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_transfer_approval_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingTransfersRequest.ProtoReflect.Descriptor instead.
+func (*ListPendingTransfersRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_transfer_approval_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListPendingTransfersRequest) GetPageId() int32 {
+	if x != nil {
+		return x.PageId
+	}
+	return 0
+}
+
+func (x *ListPendingTransfersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListPendingTransfersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transfers     []*PendingTransfer     `protobuf:"bytes,1,rep,name=transfers,proto3" json:"transfers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingTransfersResponse) Reset() {
+	*x = ListPendingTransfersResponse{}
+	mi := &file_rpc_transfer_approval_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingTransfersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingTransfersResponse) ProtoMessage() {}
+
+func (x *ListPendingTransfersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_transfer_approval_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingTransfersResponse.ProtoReflect.Descriptor instead.
+func (*ListPendingTransfersResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_transfer_approval_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListPendingTransfersResponse) GetTransfers() []*PendingTransfer {
+	if x != nil {
+		return x.Transfers
+	}
+	return nil
+}
+
+var File_rpc_transfer_approval_proto protoreflect.FileDescriptor
+
+const file_rpc_transfer_approval_proto_rawDesc = "" +
+	"\n" +
+	"\x1brpc_transfer_approval.proto\x12\x02pb\x1a\raccount.proto\x1a\x0etransfer.proto\x1a\x17transfer_approval.proto\"9\n" +
+	"\x16ApproveTransferRequest\x12\x1f\n" +
+	"\vtransfer_id\x18\x01 \x01(\x03R\n" +
+	"transferId\"s\n" +
+	"\x17ApproveTransferResponse\x12(\n" +
+	"\btransfer\x18\x01 \x01(\v2\f.pb.TransferR\btransfer\x12.\n" +
+	"\ffrom_account\x18\x02 \x01(\v2\v.pb.AccountR\vfromAccount\"8\n" +
+	"\x15RejectTransferRequest\x12\x1f\n" +
+	"\vtransfer_id\x18\x01 \x01(\x03R\n" +
+	"transferId\"B\n" +
+	"\x16RejectTransferResponse\x12(\n" +
+	"\btransfer\x18\x01 \x01(\v2\f.pb.TransferR\btransfer\"S\n" +
+	"\x1bListPendingTransfersRequest\x12\x17\n" +
+	"\apage_id\x18\x01 \x01(\x05R\x06pageId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\"Q\n" +
+	"\x1cListPendingTransfersResponse\x121\n" +
+	"\ttransfers\x18\x01 \x03(\v2\x13.pb.PendingTransferR\ttransfersB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_rpc_transfer_approval_proto_rawDescOnce sync.Once
+	file_rpc_transfer_approval_proto_rawDescData []byte
+)
+
+func file_rpc_transfer_approval_proto_rawDescGZIP() []byte {
+	file_rpc_transfer_approval_proto_rawDescOnce.Do(func() {
+		file_rpc_transfer_approval_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_transfer_approval_proto_rawDesc), len(file_rpc_transfer_approval_proto_rawDesc)))
+	})
+	return file_rpc_transfer_approval_proto_rawDescData
+}
+
+var file_rpc_transfer_approval_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_rpc_transfer_approval_proto_goTypes = []any{
+	(*ApproveTransferRequest)(nil),       // 0: pb.ApproveTransferRequest
+	(*ApproveTransferResponse)(nil),      // 1: pb.ApproveTransferResponse
+	(*RejectTransferRequest)(nil),        // 2: pb.RejectTransferRequest
+	(*RejectTransferResponse)(nil),       // 3: pb.RejectTransferResponse
+	(*ListPendingTransfersRequest)(nil),  // 4: pb.ListPendingTransfersRequest
+	(*ListPendingTransfersResponse)(nil), // 5: pb.ListPendingTransfersResponse
+	(*Transfer)(nil),                     // 6: pb.Transfer
+	(*Account)(nil),                      // 7: pb.Account
+	(*PendingTransfer)(nil),              // 8: pb.PendingTransfer
+}
+var file_rpc_transfer_approval_proto_depIdxs = []int32{
+	6, // 0: pb.ApproveTransferResponse.transfer:type_name -> pb.Transfer
+	7, // 1: pb.ApproveTransferResponse.from_account:type_name -> pb.Account
+	6, // 2: pb.RejectTransferResponse.transfer:type_name -> pb.Transfer
+	8, // 3: pb.ListPendingTransfersResponse.transfers:type_name -> pb.PendingTransfer
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_rpc_transfer_approval_proto_init() }
+func file_rpc_transfer_approval_proto_init() {
+	if File_rpc_transfer_approval_proto != nil {
+		return
+	}
+	file_account_proto_init()
+	file_transfer_proto_init()
+	file_transfer_approval_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_transfer_approval_proto_rawDesc), len(file_rpc_transfer_approval_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_transfer_approval_proto_goTypes,
+		DependencyIndexes: file_rpc_transfer_approval_proto_depIdxs,
+		MessageInfos:      file_rpc_transfer_approval_proto_msgTypes,
+	}.Build()
+	File_rpc_transfer_approval_proto = out.File
+	file_rpc_transfer_approval_proto_goTypes = nil
+	file_rpc_transfer_approval_proto_depIdxs = nil
+}
@@ -0,0 +1,235 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: rpc_watch_account.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type WatchAccountRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// account_id 和 account_number 二选一
+	AccountId int64 `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// 断线重连时传最后收到的 cursor，为 0 时从当前余额开始推送
+	AfterEntryId  int64   `protobuf:"varint,2,opt,name=after_entry_id,json=afterEntryId,proto3" json:"after_entry_id,omitempty"`
+	AccountNumber *string `protobuf:"bytes,3,opt,name=account_number,json=accountNumber,proto3,oneof" json:"account_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchAccountRequest) Reset() {
+	*x = WatchAccountRequest{}
+	mi := &file_rpc_watch_account_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAccountRequest) ProtoMessage() {}
+
+func (x *WatchAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_watch_account_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAccountRequest.ProtoReflect.Descriptor instead.
+func (*WatchAccountRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_watch_account_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *WatchAccountRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *WatchAccountRequest) GetAfterEntryId() int64 {
+	if x != nil {
+		return x.AfterEntryId
+	}
+	return 0
+}
+
+func (x *WatchAccountRequest) GetAccountNumber() string {
+	if x != nil && x.AccountNumber != nil {
+		return *x.AccountNumber
+	}
+	return ""
+}
+
+type WatchAccountResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AccountId int64                  `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// 这条分录入账后的余额
+	Balance  int64  `protobuf:"varint,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	Currency string `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	// 订阅开始时的第一条消息只有余额，没有分录
+	Entry *Entry `protobuf:"bytes,4,opt,name=entry,proto3" json:"entry,omitempty"`
+	// 重连时作为 after_entry_id 传回
+	Cursor        int64 `protobuf:"varint,5,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchAccountResponse) Reset() {
+	*x = WatchAccountResponse{}
+	mi := &file_rpc_watch_account_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAccountResponse) ProtoMessage() {}
+
+func (x *WatchAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_watch_account_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAccountResponse.ProtoReflect.Descriptor instead.
+func (*WatchAccountResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_watch_account_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *WatchAccountResponse) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *WatchAccountResponse) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *WatchAccountResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *WatchAccountResponse) GetEntry() *Entry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *WatchAccountResponse) GetCursor() int64 {
+	if x != nil {
+		return x.Cursor
+	}
+	return 0
+}
+
+var File_rpc_watch_account_proto protoreflect.FileDescriptor
+
+const file_rpc_watch_account_proto_rawDesc = "" +
+	"\n" +
+	"\x17rpc_watch_account.proto\x12\x02pb\x1a\ventry.proto\"\x99\x01\n" +
+	"\x13WatchAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\x12$\n" +
+	"\x0eafter_entry_id\x18\x02 \x01(\x03R\fafterEntryId\x12*\n" +
+	"\x0eaccount_number\x18\x03 \x01(\tH\x00R\raccountNumber\x88\x01\x01B\x11\n" +
+	"\x0f_account_number\"\xa4\x01\n" +
+	"\x14WatchAccountResponse\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\x03R\abalance\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x1f\n" +
+	"\x05entry\x18\x04 \x01(\v2\t.pb.EntryR\x05entry\x12\x16\n" +
+	"\x06cursor\x18\x05 \x01(\x03R\x06cursorB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_rpc_watch_account_proto_rawDescOnce sync.Once
+	file_rpc_watch_account_proto_rawDescData []byte
+)
+
+func file_rpc_watch_account_proto_rawDescGZIP() []byte {
+	file_rpc_watch_account_proto_rawDescOnce.Do(func() {
+		file_rpc_watch_account_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_watch_account_proto_rawDesc), len(file_rpc_watch_account_proto_rawDesc)))
+	})
+	return file_rpc_watch_account_proto_rawDescData
+}
+
+var file_rpc_watch_account_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_watch_account_proto_goTypes = []any{
+	(*WatchAccountRequest)(nil),  // 0: pb.WatchAccountRequest
+	(*WatchAccountResponse)(nil), // 1: pb.WatchAccountResponse
+	(*Entry)(nil),                // 2: pb.Entry
+}
+var file_rpc_watch_account_proto_depIdxs = []int32{
+	2, // 0: pb.WatchAccountResponse.entry:type_name -> pb.Entry
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_watch_account_proto_init() }
+func file_rpc_watch_account_proto_init() {
+	if File_rpc_watch_account_proto != nil {
+		return
+	}
+	file_entry_proto_init()
+	file_rpc_watch_account_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_watch_account_proto_rawDesc), len(file_rpc_watch_account_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_watch_account_proto_goTypes,
+		DependencyIndexes: file_rpc_watch_account_proto_depIdxs,
+		MessageInfos:      file_rpc_watch_account_proto_msgTypes,
+	}.Build()
+	File_rpc_watch_account_proto = out.File
+	file_rpc_watch_account_proto_goTypes = nil
+	file_rpc_watch_account_proto_depIdxs = nil
+}
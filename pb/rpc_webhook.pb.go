@@ -0,0 +1,576 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: rpc_webhook.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateWebhookSubscriptionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Url   string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// transfer.created, account.credited, user.email_verified
+	EventTypes    []string `protobuf:"bytes,2,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookSubscriptionRequest) Reset() {
+	*x = CreateWebhookSubscriptionRequest{}
+	mi := &file_rpc_webhook_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookSubscriptionRequest) ProtoMessage() {}
+
+func (x *CreateWebhookSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_webhook_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*CreateWebhookSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_webhook_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateWebhookSubscriptionRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateWebhookSubscriptionRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+type CreateWebhookSubscriptionResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Subscription *WebhookSubscription   `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	// 签名密钥只在创建时返回一次
+	Secret        string `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookSubscriptionResponse) Reset() {
+	*x = CreateWebhookSubscriptionResponse{}
+	mi := &file_rpc_webhook_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookSubscriptionResponse) ProtoMessage() {}
+
+func (x *CreateWebhookSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_webhook_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*CreateWebhookSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_webhook_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateWebhookSubscriptionResponse) GetSubscription() *WebhookSubscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *CreateWebhookSubscriptionResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type ListWebhookSubscriptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookSubscriptionsRequest) Reset() {
+	*x = ListWebhookSubscriptionsRequest{}
+	mi := &file_rpc_webhook_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookSubscriptionsRequest) ProtoMessage() {}
+
+func (x *ListWebhookSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_webhook_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_webhook_proto_rawDescGZIP(), []int{2}
+}
+
+type ListWebhookSubscriptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*WebhookSubscription `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookSubscriptionsResponse) Reset() {
+	*x = ListWebhookSubscriptionsResponse{}
+	mi := &file_rpc_webhook_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookSubscriptionsResponse) ProtoMessage() {}
+
+func (x *ListWebhookSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_webhook_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_webhook_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListWebhookSubscriptionsResponse) GetSubscriptions() []*WebhookSubscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+type DeleteWebhookSubscriptionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId int64                  `protobuf:"varint,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookSubscriptionRequest) Reset() {
+	*x = DeleteWebhookSubscriptionRequest{}
+	mi := &file_rpc_webhook_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookSubscriptionRequest) ProtoMessage() {}
+
+func (x *DeleteWebhookSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_webhook_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_webhook_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DeleteWebhookSubscriptionRequest) GetSubscriptionId() int64 {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return 0
+}
+
+type DeleteWebhookSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookSubscriptionResponse) Reset() {
+	*x = DeleteWebhookSubscriptionResponse{}
+	mi := &file_rpc_webhook_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookSubscriptionResponse) ProtoMessage() {}
+
+func (x *DeleteWebhookSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_webhook_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_webhook_proto_rawDescGZIP(), []int{5}
+}
+
+type ListWebhookDeliveriesRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId int64                  `protobuf:"varint,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	PageId         int32                  `protobuf:"varint,2,opt,name=page_id,json=pageId,proto3" json:"page_id,omitempty"`
+	PageSize       int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesRequest) Reset() {
+	*x = ListWebhookDeliveriesRequest{}
+	mi := &file_rpc_webhook_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesRequest) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_webhook_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_webhook_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListWebhookDeliveriesRequest) GetSubscriptionId() int64 {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return 0
+}
+
+func (x *ListWebhookDeliveriesRequest) GetPageId() int32 {
+	if x != nil {
+		return x.PageId
+	}
+	return 0
+}
+
+func (x *ListWebhookDeliveriesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListWebhookDeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesResponse) Reset() {
+	*x = ListWebhookDeliveriesResponse{}
+	mi := &file_rpc_webhook_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_webhook_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_webhook_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListWebhookDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+type RedeliverWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeliveryId    int64                  `protobuf:"varint,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedeliverWebhookRequest) Reset() {
+	*x = RedeliverWebhookRequest{}
+	mi := &file_rpc_webhook_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedeliverWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedeliverWebhookRequest) ProtoMessage() {}
+
+func (x *RedeliverWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_webhook_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedeliverWebhookRequest.ProtoReflect.Descriptor instead.
+func (*RedeliverWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_webhook_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RedeliverWebhookRequest) GetDeliveryId() int64 {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return 0
+}
+
+type RedeliverWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Delivery      *WebhookDelivery       `protobuf:"bytes,1,opt,name=delivery,proto3" json:"delivery,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedeliverWebhookResponse) Reset() {
+	*x = RedeliverWebhookResponse{}
+	mi := &file_rpc_webhook_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedeliverWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedeliverWebhookResponse) ProtoMessage() {}
+
+func (x *RedeliverWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_webhook_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedeliverWebhookResponse.ProtoReflect.Descriptor instead.
+func (*RedeliverWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_webhook_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RedeliverWebhookResponse) GetDelivery() *WebhookDelivery {
+	if x != nil {
+		return x.Delivery
+	}
+	return nil
+}
+
+var File_rpc_webhook_proto protoreflect.FileDescriptor
+
+const file_rpc_webhook_proto_rawDesc = "" +
+	"\n" +
+	"\x11rpc_webhook.proto\x12\x02pb\x1a\rwebhook.proto\"U\n" +
+	" CreateWebhookSubscriptionRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1f\n" +
+	"\vevent_types\x18\x02 \x03(\tR\n" +
+	"eventTypes\"x\n" +
+	"!CreateWebhookSubscriptionResponse\x12;\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x17.pb.WebhookSubscriptionR\fsubscription\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"!\n" +
+	"\x1fListWebhookSubscriptionsRequest\"a\n" +
+	" ListWebhookSubscriptionsResponse\x12=\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x17.pb.WebhookSubscriptionR\rsubscriptions\"K\n" +
+	" DeleteWebhookSubscriptionRequest\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\x03R\x0esubscriptionId\"#\n" +
+	"!DeleteWebhookSubscriptionResponse\"}\n" +
+	"\x1cListWebhookDeliveriesRequest\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\x03R\x0esubscriptionId\x12\x17\n" +
+	"\apage_id\x18\x02 \x01(\x05R\x06pageId\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"T\n" +
+	"\x1dListWebhookDeliveriesResponse\x123\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x13.pb.WebhookDeliveryR\n" +
+	"deliveries\":\n" +
+	"\x17RedeliverWebhookRequest\x12\x1f\n" +
+	"\vdelivery_id\x18\x01 \x01(\x03R\n" +
+	"deliveryId\"K\n" +
+	"\x18RedeliverWebhookResponse\x12/\n" +
+	"\bdelivery\x18\x01 \x01(\v2\x13.pb.WebhookDeliveryR\bdeliveryB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_rpc_webhook_proto_rawDescOnce sync.Once
+	file_rpc_webhook_proto_rawDescData []byte
+)
+
+func file_rpc_webhook_proto_rawDescGZIP() []byte {
+	file_rpc_webhook_proto_rawDescOnce.Do(func() {
+		file_rpc_webhook_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_webhook_proto_rawDesc), len(file_rpc_webhook_proto_rawDesc)))
+	})
+	return file_rpc_webhook_proto_rawDescData
+}
+
+var file_rpc_webhook_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_rpc_webhook_proto_goTypes = []any{
+	(*CreateWebhookSubscriptionRequest)(nil),  // 0: pb.CreateWebhookSubscriptionRequest
+	(*CreateWebhookSubscriptionResponse)(nil), // 1: pb.CreateWebhookSubscriptionResponse
+	(*ListWebhookSubscriptionsRequest)(nil),   // 2: pb.ListWebhookSubscriptionsRequest
+	(*ListWebhookSubscriptionsResponse)(nil),  // 3: pb.ListWebhookSubscriptionsResponse
+	(*DeleteWebhookSubscriptionRequest)(nil),  // 4: pb.DeleteWebhookSubscriptionRequest
+	(*DeleteWebhookSubscriptionResponse)(nil), // 5: pb.DeleteWebhookSubscriptionResponse
+	(*ListWebhookDeliveriesRequest)(nil),      // 6: pb.ListWebhookDeliveriesRequest
+	(*ListWebhookDeliveriesResponse)(nil),     // 7: pb.ListWebhookDeliveriesResponse
+	(*RedeliverWebhookRequest)(nil),           // 8: pb.RedeliverWebhookRequest
+	(*RedeliverWebhookResponse)(nil),          // 9: pb.RedeliverWebhookResponse
+	(*WebhookSubscription)(nil),               // 10: pb.WebhookSubscription
+	(*WebhookDelivery)(nil),                   // 11: pb.WebhookDelivery
+}
+var file_rpc_webhook_proto_depIdxs = []int32{
+	10, // 0: pb.CreateWebhookSubscriptionResponse.subscription:type_name -> pb.WebhookSubscription
+	10, // 1: pb.ListWebhookSubscriptionsResponse.subscriptions:type_name -> pb.WebhookSubscription
+	11, // 2: pb.ListWebhookDeliveriesResponse.deliveries:type_name -> pb.WebhookDelivery
+	11, // 3: pb.RedeliverWebhookResponse.delivery:type_name -> pb.WebhookDelivery
+	4,  // [4:4] is the sub-list for method output_type
+	4,  // [4:4] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_rpc_webhook_proto_init() }
+func file_rpc_webhook_proto_init() {
+	if File_rpc_webhook_proto != nil {
+		return
+	}
+	file_webhook_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_webhook_proto_rawDesc), len(file_rpc_webhook_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_webhook_proto_goTypes,
+		DependencyIndexes: file_rpc_webhook_proto_depIdxs,
+		MessageInfos:      file_rpc_webhook_proto_msgTypes,
+	}.Build()
+	File_rpc_webhook_proto = out.File
+	file_rpc_webhook_proto_goTypes = nil
+	file_rpc_webhook_proto_depIdxs = nil
+}
@@ -25,7 +25,7 @@ var File_service_simple_bank_proto protoreflect.FileDescriptor
 
 const file_service_simple_bank_proto_rawDesc = "" +
 	"\n" +
-	"\x19service_simple_bank.proto\x12\x02pb\x1a\x1cgoogle/api/annotations.proto\x1a\x15rpc_create_user.proto\x1a\x14rpc_login_user.proto\x1a\x16rpc_verify_email.proto\x1a\x15rpc_update_user.proto2\xed\x02\n" +
+	"\x19service_simple_bank.proto\x12\x02pb\x1a\x1cgoogle/api/annotations.proto\x1a\x15rpc_create_user.proto\x1a\x14rpc_login_user.proto\x1a\x16rpc_verify_email.proto\x1a\x15rpc_update_user.proto\x1a\x18rpc_create_account.proto\x1a\x19rpc_create_transfer.proto\x1a\x18rpc_freeze_account.proto\x1a\x1drpc_set_overdraft_limit.proto\x1a\x17rpc_list_accounts.proto\x1a\x18rpc_account_member.proto\x1a\x0frpc_payee.proto\x1a\x19rpc_payment_request.proto\x1a rpc_list_account_transfers.proto\x1a\x16rpc_fee_schedule.proto\x1a\x18rpc_quote_transfer.proto\x1a\x1brpc_transfer_approval.proto\x1a\x1brpc_external_transfer.proto\x1a\rrpc_ach.proto\x1a\x12rpc_iso20022.proto\x1a\x11rpc_webhook.proto\x1a\x17rpc_watch_account.proto\x1a\x14rpc_task_admin.proto2\xd7'\n" +
 	"\n" +
 	"SimpleBank\x12W\n" +
 	"\n" +
@@ -33,32 +33,238 @@ const file_service_simple_bank_proto_rawDesc = "" +
 	"\tLoginUser\x12\x14.pb.LoginUserRequest\x1a\x15.pb.LoginUserResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/login_user\x12X\n" +
 	"\vVerifyEmail\x12\x16.pb.VerifyEmailRequest\x1a\x17.pb.VerifyEmailResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/verify_email\x12W\n" +
 	"\n" +
-	"UpdateUser\x12\x15.pb.UpdateUserRequest\x1a\x16.pb.UpdateUserResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/update_userB\x0fZ\rsimplebank/pbb\x06proto3"
+	"UpdateUser\x12\x15.pb.UpdateUserRequest\x1a\x16.pb.UpdateUserResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/update_user\x12c\n" +
+	"\rCreateAccount\x12\x18.pb.CreateAccountRequest\x1a\x19.pb.CreateAccountResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/create_account\x12g\n" +
+	"\x0eCreateTransfer\x12\x19.pb.CreateTransferRequest\x1a\x1a.pb.CreateTransferResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/create_transfer\x12c\n" +
+	"\rFreezeAccount\x12\x18.pb.FreezeAccountRequest\x1a\x19.pb.FreezeAccountResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/freeze_account\x12k\n" +
+	"\x0fUnfreezeAccount\x12\x1a.pb.UnfreezeAccountRequest\x1a\x1b.pb.UnfreezeAccountResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/unfreeze_account\x12t\n" +
+	"\x11SetOverdraftLimit\x12\x1c.pb.SetOverdraftLimitRequest\x1a\x1d.pb.SetOverdraftLimitResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/set_overdraft_limit\x12_\n" +
+	"\fListAccounts\x12\x17.pb.ListAccountsRequest\x1a\x18.pb.ListAccountsResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/list_accounts\x12p\n" +
+	"\x10AddAccountMember\x12\x1b.pb.AddAccountMemberRequest\x1a\x1c.pb.AddAccountMemberResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/add_account_member\x12|\n" +
+	"\x13RemoveAccountMember\x12\x1e.pb.RemoveAccountMemberRequest\x1a\x1f.pb.RemoveAccountMemberResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/remove_account_member\x12x\n" +
+	"\x12ListAccountMembers\x12\x1d.pb.ListAccountMembersRequest\x1a\x1e.pb.ListAccountMembersResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/list_account_members\x12[\n" +
+	"\vCreatePayee\x12\x16.pb.CreatePayeeRequest\x1a\x17.pb.CreatePayeeResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/create_payee\x12W\n" +
+	"\n" +
+	"ListPayees\x12\x15.pb.ListPayeesRequest\x1a\x16.pb.ListPayeesResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/list_payees\x12[\n" +
+	"\vUpdatePayee\x12\x16.pb.UpdatePayeeRequest\x1a\x17.pb.UpdatePayeeResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/update_payee\x12[\n" +
+	"\vDeletePayee\x12\x16.pb.DeletePayeeRequest\x1a\x17.pb.DeletePayeeResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/delete_payee\x12\x80\x01\n" +
+	"\x14CreatePaymentRequest\x12\x1f.pb.CreatePaymentRequestRequest\x1a .pb.CreatePaymentRequestResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/create_payment_request\x12|\n" +
+	"\x13ListPaymentRequests\x12\x1e.pb.ListPaymentRequestsRequest\x1a\x1f.pb.ListPaymentRequestsResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/list_payment_requests\x12\x80\x01\n" +
+	"\x14AcceptPaymentRequest\x12\x1f.pb.AcceptPaymentRequestRequest\x1a .pb.AcceptPaymentRequestResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/accept_payment_request\x12\x84\x01\n" +
+	"\x15DeclinePaymentRequest\x12 .pb.DeclinePaymentRequestRequest\x1a!.pb.DeclinePaymentRequestResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/decline_payment_request\x12\x80\x01\n" +
+	"\x14ListAccountTransfers\x12\x1f.pb.ListAccountTransfersRequest\x1a .pb.ListAccountTransfersResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/list_account_transfers\x12c\n" +
+	"\rQuoteTransfer\x12\x18.pb.QuoteTransferRequest\x1a\x19.pb.QuoteTransferResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/quote_transfer\x12k\n" +
+	"\x0fApproveTransfer\x12\x1a.pb.ApproveTransferRequest\x1a\x1b.pb.ApproveTransferResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/approve_transfer\x12g\n" +
+	"\x0eRejectTransfer\x12\x19.pb.RejectTransferRequest\x1a\x1a.pb.RejectTransferResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/reject_transfer\x12\x80\x01\n" +
+	"\x14ListPendingTransfers\x12\x1f.pb.ListPendingTransfersRequest\x1a .pb.ListPendingTransfersResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/list_pending_transfers\x12\x88\x01\n" +
+	"\x16CreateExternalTransfer\x12!.pb.CreateExternalTransferRequest\x1a\".pb.CreateExternalTransferResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/create_external_transfer\x12|\n" +
+	"\x13GetExternalTransfer\x12\x1e.pb.GetExternalTransferRequest\x1a\x1f.pb.GetExternalTransferResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/get_external_transfer\x12d\n" +
+	"\rExportACHFile\x12\x18.pb.ExportACHFileRequest\x1a\x19.pb.ExportACHFileResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/export_ach_file\x12t\n" +
+	"\x11ProcessACHReturns\x12\x1c.pb.ProcessACHReturnsRequest\x1a\x1d.pb.ProcessACHReturnsResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/process_ach_returns\x12k\n" +
+	"\x0fExportStatement\x12\x1a.pb.ExportStatementRequest\x1a\x1b.pb.ExportStatementResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/export_statement\x12g\n" +
+	"\x0eImportPayments\x12\x19.pb.ImportPaymentsRequest\x1a\x1a.pb.ImportPaymentsResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/import_payments\x12\x94\x01\n" +
+	"\x19CreateWebhookSubscription\x12$.pb.CreateWebhookSubscriptionRequest\x1a%.pb.CreateWebhookSubscriptionResponse\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/create_webhook_subscription\x12\x90\x01\n" +
+	"\x18ListWebhookSubscriptions\x12#.pb.ListWebhookSubscriptionsRequest\x1a$.pb.ListWebhookSubscriptionsResponse\")\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/v1/list_webhook_subscriptions\x12\x94\x01\n" +
+	"\x19DeleteWebhookSubscription\x12$.pb.DeleteWebhookSubscriptionRequest\x1a%.pb.DeleteWebhookSubscriptionResponse\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/delete_webhook_subscription\x12\x84\x01\n" +
+	"\x15ListWebhookDeliveries\x12 .pb.ListWebhookDeliveriesRequest\x1a!.pb.ListWebhookDeliveriesResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/list_webhook_deliveries\x12o\n" +
+	"\x10RedeliverWebhook\x12\x1b.pb.RedeliverWebhookRequest\x1a\x1c.pb.RedeliverWebhookResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/redeliver_webhook\x12E\n" +
+	"\fWatchAccount\x12\x17.pb.WatchAccountRequest\x1a\x18.pb.WatchAccountResponse\"\x000\x01\x12l\n" +
+	"\x0fListFailedTasks\x12\x1a.pb.ListFailedTasksRequest\x1a\x1b.pb.ListFailedTasksResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/list_failed_tasks\x12d\n" +
+	"\rGetFailedTask\x12\x18.pb.GetFailedTaskRequest\x1a\x19.pb.GetFailedTaskResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/get_failed_task\x12l\n" +
+	"\x0fRetryFailedTask\x12\x1a.pb.RetryFailedTaskRequest\x1a\x1b.pb.RetryFailedTaskResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/retry_failed_task\x12p\n" +
+	"\x10DeleteFailedTask\x12\x1b.pb.DeleteFailedTaskRequest\x1a\x1c.pb.DeleteFailedTaskResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/delete_failed_task\x12t\n" +
+	"\x11CreateFeeSchedule\x12\x1c.pb.CreateFeeScheduleRequest\x1a\x1d.pb.CreateFeeScheduleResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/create_fee_schedule\x12p\n" +
+	"\x10ListFeeSchedules\x12\x1b.pb.ListFeeSchedulesRequest\x1a\x1c.pb.ListFeeSchedulesResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/list_fee_schedules\x12t\n" +
+	"\x11DeleteFeeSchedule\x12\x1c.pb.DeleteFeeScheduleRequest\x1a\x1d.pb.DeleteFeeScheduleResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/delete_fee_scheduleB\x0fZ\rsimplebank/pbb\x06proto3"
 
 var file_service_simple_bank_proto_goTypes = []any{
-	(*CreateUserRequest)(nil),   // 0: pb.CreateUserRequest
-	(*LoginUserRequest)(nil),    // 1: pb.LoginUserRequest
-	(*VerifyEmailRequest)(nil),  // 2: pb.VerifyEmailRequest
-	(*UpdateUserRequest)(nil),   // 3: pb.UpdateUserRequest
-	(*CreateUserResponse)(nil),  // 4: pb.CreateUserResponse
-	(*LoginUserResponse)(nil),   // 5: pb.LoginUserResponse
-	(*VerifyEmailResponse)(nil), // 6: pb.VerifyEmailResponse
-	(*UpdateUserResponse)(nil),  // 7: pb.UpdateUserResponse
+	(*CreateUserRequest)(nil),                 // 0: pb.CreateUserRequest
+	(*LoginUserRequest)(nil),                  // 1: pb.LoginUserRequest
+	(*VerifyEmailRequest)(nil),                // 2: pb.VerifyEmailRequest
+	(*UpdateUserRequest)(nil),                 // 3: pb.UpdateUserRequest
+	(*CreateAccountRequest)(nil),              // 4: pb.CreateAccountRequest
+	(*CreateTransferRequest)(nil),             // 5: pb.CreateTransferRequest
+	(*FreezeAccountRequest)(nil),              // 6: pb.FreezeAccountRequest
+	(*UnfreezeAccountRequest)(nil),            // 7: pb.UnfreezeAccountRequest
+	(*SetOverdraftLimitRequest)(nil),          // 8: pb.SetOverdraftLimitRequest
+	(*ListAccountsRequest)(nil),               // 9: pb.ListAccountsRequest
+	(*AddAccountMemberRequest)(nil),           // 10: pb.AddAccountMemberRequest
+	(*RemoveAccountMemberRequest)(nil),        // 11: pb.RemoveAccountMemberRequest
+	(*ListAccountMembersRequest)(nil),         // 12: pb.ListAccountMembersRequest
+	(*CreatePayeeRequest)(nil),                // 13: pb.CreatePayeeRequest
+	(*ListPayeesRequest)(nil),                 // 14: pb.ListPayeesRequest
+	(*UpdatePayeeRequest)(nil),                // 15: pb.UpdatePayeeRequest
+	(*DeletePayeeRequest)(nil),                // 16: pb.DeletePayeeRequest
+	(*CreatePaymentRequestRequest)(nil),       // 17: pb.CreatePaymentRequestRequest
+	(*ListPaymentRequestsRequest)(nil),        // 18: pb.ListPaymentRequestsRequest
+	(*AcceptPaymentRequestRequest)(nil),       // 19: pb.AcceptPaymentRequestRequest
+	(*DeclinePaymentRequestRequest)(nil),      // 20: pb.DeclinePaymentRequestRequest
+	(*ListAccountTransfersRequest)(nil),       // 21: pb.ListAccountTransfersRequest
+	(*QuoteTransferRequest)(nil),              // 22: pb.QuoteTransferRequest
+	(*ApproveTransferRequest)(nil),            // 23: pb.ApproveTransferRequest
+	(*RejectTransferRequest)(nil),             // 24: pb.RejectTransferRequest
+	(*ListPendingTransfersRequest)(nil),       // 25: pb.ListPendingTransfersRequest
+	(*CreateExternalTransferRequest)(nil),     // 26: pb.CreateExternalTransferRequest
+	(*GetExternalTransferRequest)(nil),        // 27: pb.GetExternalTransferRequest
+	(*ExportACHFileRequest)(nil),              // 28: pb.ExportACHFileRequest
+	(*ProcessACHReturnsRequest)(nil),          // 29: pb.ProcessACHReturnsRequest
+	(*ExportStatementRequest)(nil),            // 30: pb.ExportStatementRequest
+	(*ImportPaymentsRequest)(nil),             // 31: pb.ImportPaymentsRequest
+	(*CreateWebhookSubscriptionRequest)(nil),  // 32: pb.CreateWebhookSubscriptionRequest
+	(*ListWebhookSubscriptionsRequest)(nil),   // 33: pb.ListWebhookSubscriptionsRequest
+	(*DeleteWebhookSubscriptionRequest)(nil),  // 34: pb.DeleteWebhookSubscriptionRequest
+	(*ListWebhookDeliveriesRequest)(nil),      // 35: pb.ListWebhookDeliveriesRequest
+	(*RedeliverWebhookRequest)(nil),           // 36: pb.RedeliverWebhookRequest
+	(*WatchAccountRequest)(nil),               // 37: pb.WatchAccountRequest
+	(*ListFailedTasksRequest)(nil),            // 38: pb.ListFailedTasksRequest
+	(*GetFailedTaskRequest)(nil),              // 39: pb.GetFailedTaskRequest
+	(*RetryFailedTaskRequest)(nil),            // 40: pb.RetryFailedTaskRequest
+	(*DeleteFailedTaskRequest)(nil),           // 41: pb.DeleteFailedTaskRequest
+	(*CreateFeeScheduleRequest)(nil),          // 42: pb.CreateFeeScheduleRequest
+	(*ListFeeSchedulesRequest)(nil),           // 43: pb.ListFeeSchedulesRequest
+	(*DeleteFeeScheduleRequest)(nil),          // 44: pb.DeleteFeeScheduleRequest
+	(*CreateUserResponse)(nil),                // 45: pb.CreateUserResponse
+	(*LoginUserResponse)(nil),                 // 46: pb.LoginUserResponse
+	(*VerifyEmailResponse)(nil),               // 47: pb.VerifyEmailResponse
+	(*UpdateUserResponse)(nil),                // 48: pb.UpdateUserResponse
+	(*CreateAccountResponse)(nil),             // 49: pb.CreateAccountResponse
+	(*CreateTransferResponse)(nil),            // 50: pb.CreateTransferResponse
+	(*FreezeAccountResponse)(nil),             // 51: pb.FreezeAccountResponse
+	(*UnfreezeAccountResponse)(nil),           // 52: pb.UnfreezeAccountResponse
+	(*SetOverdraftLimitResponse)(nil),         // 53: pb.SetOverdraftLimitResponse
+	(*ListAccountsResponse)(nil),              // 54: pb.ListAccountsResponse
+	(*AddAccountMemberResponse)(nil),          // 55: pb.AddAccountMemberResponse
+	(*RemoveAccountMemberResponse)(nil),       // 56: pb.RemoveAccountMemberResponse
+	(*ListAccountMembersResponse)(nil),        // 57: pb.ListAccountMembersResponse
+	(*CreatePayeeResponse)(nil),               // 58: pb.CreatePayeeResponse
+	(*ListPayeesResponse)(nil),                // 59: pb.ListPayeesResponse
+	(*UpdatePayeeResponse)(nil),               // 60: pb.UpdatePayeeResponse
+	(*DeletePayeeResponse)(nil),               // 61: pb.DeletePayeeResponse
+	(*CreatePaymentRequestResponse)(nil),      // 62: pb.CreatePaymentRequestResponse
+	(*ListPaymentRequestsResponse)(nil),       // 63: pb.ListPaymentRequestsResponse
+	(*AcceptPaymentRequestResponse)(nil),      // 64: pb.AcceptPaymentRequestResponse
+	(*DeclinePaymentRequestResponse)(nil),     // 65: pb.DeclinePaymentRequestResponse
+	(*ListAccountTransfersResponse)(nil),      // 66: pb.ListAccountTransfersResponse
+	(*QuoteTransferResponse)(nil),             // 67: pb.QuoteTransferResponse
+	(*ApproveTransferResponse)(nil),           // 68: pb.ApproveTransferResponse
+	(*RejectTransferResponse)(nil),            // 69: pb.RejectTransferResponse
+	(*ListPendingTransfersResponse)(nil),      // 70: pb.ListPendingTransfersResponse
+	(*CreateExternalTransferResponse)(nil),    // 71: pb.CreateExternalTransferResponse
+	(*GetExternalTransferResponse)(nil),       // 72: pb.GetExternalTransferResponse
+	(*ExportACHFileResponse)(nil),             // 73: pb.ExportACHFileResponse
+	(*ProcessACHReturnsResponse)(nil),         // 74: pb.ProcessACHReturnsResponse
+	(*ExportStatementResponse)(nil),           // 75: pb.ExportStatementResponse
+	(*ImportPaymentsResponse)(nil),            // 76: pb.ImportPaymentsResponse
+	(*CreateWebhookSubscriptionResponse)(nil), // 77: pb.CreateWebhookSubscriptionResponse
+	(*ListWebhookSubscriptionsResponse)(nil),  // 78: pb.ListWebhookSubscriptionsResponse
+	(*DeleteWebhookSubscriptionResponse)(nil), // 79: pb.DeleteWebhookSubscriptionResponse
+	(*ListWebhookDeliveriesResponse)(nil),     // 80: pb.ListWebhookDeliveriesResponse
+	(*RedeliverWebhookResponse)(nil),          // 81: pb.RedeliverWebhookResponse
+	(*WatchAccountResponse)(nil),              // 82: pb.WatchAccountResponse
+	(*ListFailedTasksResponse)(nil),           // 83: pb.ListFailedTasksResponse
+	(*GetFailedTaskResponse)(nil),             // 84: pb.GetFailedTaskResponse
+	(*RetryFailedTaskResponse)(nil),           // 85: pb.RetryFailedTaskResponse
+	(*DeleteFailedTaskResponse)(nil),          // 86: pb.DeleteFailedTaskResponse
+	(*CreateFeeScheduleResponse)(nil),         // 87: pb.CreateFeeScheduleResponse
+	(*ListFeeSchedulesResponse)(nil),          // 88: pb.ListFeeSchedulesResponse
+	(*DeleteFeeScheduleResponse)(nil),         // 89: pb.DeleteFeeScheduleResponse
 }
 var file_service_simple_bank_proto_depIdxs = []int32{
-	0, // 0: pb.SimpleBank.CreateUser:input_type -> pb.CreateUserRequest
-	1, // 1: pb.SimpleBank.LoginUser:input_type -> pb.LoginUserRequest
-	2, // 2: pb.SimpleBank.VerifyEmail:input_type -> pb.VerifyEmailRequest
-	3, // 3: pb.SimpleBank.UpdateUser:input_type -> pb.UpdateUserRequest
-	4, // 4: pb.SimpleBank.CreateUser:output_type -> pb.CreateUserResponse
-	5, // 5: pb.SimpleBank.LoginUser:output_type -> pb.LoginUserResponse
-	6, // 6: pb.SimpleBank.VerifyEmail:output_type -> pb.VerifyEmailResponse
-	7, // 7: pb.SimpleBank.UpdateUser:output_type -> pb.UpdateUserResponse
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: pb.SimpleBank.CreateUser:input_type -> pb.CreateUserRequest
+	1,  // 1: pb.SimpleBank.LoginUser:input_type -> pb.LoginUserRequest
+	2,  // 2: pb.SimpleBank.VerifyEmail:input_type -> pb.VerifyEmailRequest
+	3,  // 3: pb.SimpleBank.UpdateUser:input_type -> pb.UpdateUserRequest
+	4,  // 4: pb.SimpleBank.CreateAccount:input_type -> pb.CreateAccountRequest
+	5,  // 5: pb.SimpleBank.CreateTransfer:input_type -> pb.CreateTransferRequest
+	6,  // 6: pb.SimpleBank.FreezeAccount:input_type -> pb.FreezeAccountRequest
+	7,  // 7: pb.SimpleBank.UnfreezeAccount:input_type -> pb.UnfreezeAccountRequest
+	8,  // 8: pb.SimpleBank.SetOverdraftLimit:input_type -> pb.SetOverdraftLimitRequest
+	9,  // 9: pb.SimpleBank.ListAccounts:input_type -> pb.ListAccountsRequest
+	10, // 10: pb.SimpleBank.AddAccountMember:input_type -> pb.AddAccountMemberRequest
+	11, // 11: pb.SimpleBank.RemoveAccountMember:input_type -> pb.RemoveAccountMemberRequest
+	12, // 12: pb.SimpleBank.ListAccountMembers:input_type -> pb.ListAccountMembersRequest
+	13, // 13: pb.SimpleBank.CreatePayee:input_type -> pb.CreatePayeeRequest
+	14, // 14: pb.SimpleBank.ListPayees:input_type -> pb.ListPayeesRequest
+	15, // 15: pb.SimpleBank.UpdatePayee:input_type -> pb.UpdatePayeeRequest
+	16, // 16: pb.SimpleBank.DeletePayee:input_type -> pb.DeletePayeeRequest
+	17, // 17: pb.SimpleBank.CreatePaymentRequest:input_type -> pb.CreatePaymentRequestRequest
+	18, // 18: pb.SimpleBank.ListPaymentRequests:input_type -> pb.ListPaymentRequestsRequest
+	19, // 19: pb.SimpleBank.AcceptPaymentRequest:input_type -> pb.AcceptPaymentRequestRequest
+	20, // 20: pb.SimpleBank.DeclinePaymentRequest:input_type -> pb.DeclinePaymentRequestRequest
+	21, // 21: pb.SimpleBank.ListAccountTransfers:input_type -> pb.ListAccountTransfersRequest
+	22, // 22: pb.SimpleBank.QuoteTransfer:input_type -> pb.QuoteTransferRequest
+	23, // 23: pb.SimpleBank.ApproveTransfer:input_type -> pb.ApproveTransferRequest
+	24, // 24: pb.SimpleBank.RejectTransfer:input_type -> pb.RejectTransferRequest
+	25, // 25: pb.SimpleBank.ListPendingTransfers:input_type -> pb.ListPendingTransfersRequest
+	26, // 26: pb.SimpleBank.CreateExternalTransfer:input_type -> pb.CreateExternalTransferRequest
+	27, // 27: pb.SimpleBank.GetExternalTransfer:input_type -> pb.GetExternalTransferRequest
+	28, // 28: pb.SimpleBank.ExportACHFile:input_type -> pb.ExportACHFileRequest
+	29, // 29: pb.SimpleBank.ProcessACHReturns:input_type -> pb.ProcessACHReturnsRequest
+	30, // 30: pb.SimpleBank.ExportStatement:input_type -> pb.ExportStatementRequest
+	31, // 31: pb.SimpleBank.ImportPayments:input_type -> pb.ImportPaymentsRequest
+	32, // 32: pb.SimpleBank.CreateWebhookSubscription:input_type -> pb.CreateWebhookSubscriptionRequest
+	33, // 33: pb.SimpleBank.ListWebhookSubscriptions:input_type -> pb.ListWebhookSubscriptionsRequest
+	34, // 34: pb.SimpleBank.DeleteWebhookSubscription:input_type -> pb.DeleteWebhookSubscriptionRequest
+	35, // 35: pb.SimpleBank.ListWebhookDeliveries:input_type -> pb.ListWebhookDeliveriesRequest
+	36, // 36: pb.SimpleBank.RedeliverWebhook:input_type -> pb.RedeliverWebhookRequest
+	37, // 37: pb.SimpleBank.WatchAccount:input_type -> pb.WatchAccountRequest
+	38, // 38: pb.SimpleBank.ListFailedTasks:input_type -> pb.ListFailedTasksRequest
+	39, // 39: pb.SimpleBank.GetFailedTask:input_type -> pb.GetFailedTaskRequest
+	40, // 40: pb.SimpleBank.RetryFailedTask:input_type -> pb.RetryFailedTaskRequest
+	41, // 41: pb.SimpleBank.DeleteFailedTask:input_type -> pb.DeleteFailedTaskRequest
+	42, // 42: pb.SimpleBank.CreateFeeSchedule:input_type -> pb.CreateFeeScheduleRequest
+	43, // 43: pb.SimpleBank.ListFeeSchedules:input_type -> pb.ListFeeSchedulesRequest
+	44, // 44: pb.SimpleBank.DeleteFeeSchedule:input_type -> pb.DeleteFeeScheduleRequest
+	45, // 45: pb.SimpleBank.CreateUser:output_type -> pb.CreateUserResponse
+	46, // 46: pb.SimpleBank.LoginUser:output_type -> pb.LoginUserResponse
+	47, // 47: pb.SimpleBank.VerifyEmail:output_type -> pb.VerifyEmailResponse
+	48, // 48: pb.SimpleBank.UpdateUser:output_type -> pb.UpdateUserResponse
+	49, // 49: pb.SimpleBank.CreateAccount:output_type -> pb.CreateAccountResponse
+	50, // 50: pb.SimpleBank.CreateTransfer:output_type -> pb.CreateTransferResponse
+	51, // 51: pb.SimpleBank.FreezeAccount:output_type -> pb.FreezeAccountResponse
+	52, // 52: pb.SimpleBank.UnfreezeAccount:output_type -> pb.UnfreezeAccountResponse
+	53, // 53: pb.SimpleBank.SetOverdraftLimit:output_type -> pb.SetOverdraftLimitResponse
+	54, // 54: pb.SimpleBank.ListAccounts:output_type -> pb.ListAccountsResponse
+	55, // 55: pb.SimpleBank.AddAccountMember:output_type -> pb.AddAccountMemberResponse
+	56, // 56: pb.SimpleBank.RemoveAccountMember:output_type -> pb.RemoveAccountMemberResponse
+	57, // 57: pb.SimpleBank.ListAccountMembers:output_type -> pb.ListAccountMembersResponse
+	58, // 58: pb.SimpleBank.CreatePayee:output_type -> pb.CreatePayeeResponse
+	59, // 59: pb.SimpleBank.ListPayees:output_type -> pb.ListPayeesResponse
+	60, // 60: pb.SimpleBank.UpdatePayee:output_type -> pb.UpdatePayeeResponse
+	61, // 61: pb.SimpleBank.DeletePayee:output_type -> pb.DeletePayeeResponse
+	62, // 62: pb.SimpleBank.CreatePaymentRequest:output_type -> pb.CreatePaymentRequestResponse
+	63, // 63: pb.SimpleBank.ListPaymentRequests:output_type -> pb.ListPaymentRequestsResponse
+	64, // 64: pb.SimpleBank.AcceptPaymentRequest:output_type -> pb.AcceptPaymentRequestResponse
+	65, // 65: pb.SimpleBank.DeclinePaymentRequest:output_type -> pb.DeclinePaymentRequestResponse
+	66, // 66: pb.SimpleBank.ListAccountTransfers:output_type -> pb.ListAccountTransfersResponse
+	67, // 67: pb.SimpleBank.QuoteTransfer:output_type -> pb.QuoteTransferResponse
+	68, // 68: pb.SimpleBank.ApproveTransfer:output_type -> pb.ApproveTransferResponse
+	69, // 69: pb.SimpleBank.RejectTransfer:output_type -> pb.RejectTransferResponse
+	70, // 70: pb.SimpleBank.ListPendingTransfers:output_type -> pb.ListPendingTransfersResponse
+	71, // 71: pb.SimpleBank.CreateExternalTransfer:output_type -> pb.CreateExternalTransferResponse
+	72, // 72: pb.SimpleBank.GetExternalTransfer:output_type -> pb.GetExternalTransferResponse
+	73, // 73: pb.SimpleBank.ExportACHFile:output_type -> pb.ExportACHFileResponse
+	74, // 74: pb.SimpleBank.ProcessACHReturns:output_type -> pb.ProcessACHReturnsResponse
+	75, // 75: pb.SimpleBank.ExportStatement:output_type -> pb.ExportStatementResponse
+	76, // 76: pb.SimpleBank.ImportPayments:output_type -> pb.ImportPaymentsResponse
+	77, // 77: pb.SimpleBank.CreateWebhookSubscription:output_type -> pb.CreateWebhookSubscriptionResponse
+	78, // 78: pb.SimpleBank.ListWebhookSubscriptions:output_type -> pb.ListWebhookSubscriptionsResponse
+	79, // 79: pb.SimpleBank.DeleteWebhookSubscription:output_type -> pb.DeleteWebhookSubscriptionResponse
+	80, // 80: pb.SimpleBank.ListWebhookDeliveries:output_type -> pb.ListWebhookDeliveriesResponse
+	81, // 81: pb.SimpleBank.RedeliverWebhook:output_type -> pb.RedeliverWebhookResponse
+	82, // 82: pb.SimpleBank.WatchAccount:output_type -> pb.WatchAccountResponse
+	83, // 83: pb.SimpleBank.ListFailedTasks:output_type -> pb.ListFailedTasksResponse
+	84, // 84: pb.SimpleBank.GetFailedTask:output_type -> pb.GetFailedTaskResponse
+	85, // 85: pb.SimpleBank.RetryFailedTask:output_type -> pb.RetryFailedTaskResponse
+	86, // 86: pb.SimpleBank.DeleteFailedTask:output_type -> pb.DeleteFailedTaskResponse
+	87, // 87: pb.SimpleBank.CreateFeeSchedule:output_type -> pb.CreateFeeScheduleResponse
+	88, // 88: pb.SimpleBank.ListFeeSchedules:output_type -> pb.ListFeeSchedulesResponse
+	89, // 89: pb.SimpleBank.DeleteFeeSchedule:output_type -> pb.DeleteFeeScheduleResponse
+	45, // [45:90] is the sub-list for method output_type
+	0,  // [0:45] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_service_simple_bank_proto_init() }
@@ -70,6 +276,24 @@ func file_service_simple_bank_proto_init() {
 	file_rpc_login_user_proto_init()
 	file_rpc_verify_email_proto_init()
 	file_rpc_update_user_proto_init()
+	file_rpc_create_account_proto_init()
+	file_rpc_create_transfer_proto_init()
+	file_rpc_freeze_account_proto_init()
+	file_rpc_set_overdraft_limit_proto_init()
+	file_rpc_list_accounts_proto_init()
+	file_rpc_account_member_proto_init()
+	file_rpc_payee_proto_init()
+	file_rpc_payment_request_proto_init()
+	file_rpc_list_account_transfers_proto_init()
+	file_rpc_fee_schedule_proto_init()
+	file_rpc_quote_transfer_proto_init()
+	file_rpc_transfer_approval_proto_init()
+	file_rpc_external_transfer_proto_init()
+	file_rpc_ach_proto_init()
+	file_rpc_iso20022_proto_init()
+	file_rpc_webhook_proto_init()
+	file_rpc_watch_account_proto_init()
+	file_rpc_task_admin_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
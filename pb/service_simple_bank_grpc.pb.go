@@ -19,10 +19,51 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	SimpleBank_CreateUser_FullMethodName  = "/pb.SimpleBank/CreateUser"
-	SimpleBank_LoginUser_FullMethodName   = "/pb.SimpleBank/LoginUser"
-	SimpleBank_VerifyEmail_FullMethodName = "/pb.SimpleBank/VerifyEmail"
-	SimpleBank_UpdateUser_FullMethodName  = "/pb.SimpleBank/UpdateUser"
+	SimpleBank_CreateUser_FullMethodName                = "/pb.SimpleBank/CreateUser"
+	SimpleBank_LoginUser_FullMethodName                 = "/pb.SimpleBank/LoginUser"
+	SimpleBank_VerifyEmail_FullMethodName               = "/pb.SimpleBank/VerifyEmail"
+	SimpleBank_UpdateUser_FullMethodName                = "/pb.SimpleBank/UpdateUser"
+	SimpleBank_CreateAccount_FullMethodName             = "/pb.SimpleBank/CreateAccount"
+	SimpleBank_CreateTransfer_FullMethodName            = "/pb.SimpleBank/CreateTransfer"
+	SimpleBank_FreezeAccount_FullMethodName             = "/pb.SimpleBank/FreezeAccount"
+	SimpleBank_UnfreezeAccount_FullMethodName           = "/pb.SimpleBank/UnfreezeAccount"
+	SimpleBank_SetOverdraftLimit_FullMethodName         = "/pb.SimpleBank/SetOverdraftLimit"
+	SimpleBank_ListAccounts_FullMethodName              = "/pb.SimpleBank/ListAccounts"
+	SimpleBank_AddAccountMember_FullMethodName          = "/pb.SimpleBank/AddAccountMember"
+	SimpleBank_RemoveAccountMember_FullMethodName       = "/pb.SimpleBank/RemoveAccountMember"
+	SimpleBank_ListAccountMembers_FullMethodName        = "/pb.SimpleBank/ListAccountMembers"
+	SimpleBank_CreatePayee_FullMethodName               = "/pb.SimpleBank/CreatePayee"
+	SimpleBank_ListPayees_FullMethodName                = "/pb.SimpleBank/ListPayees"
+	SimpleBank_UpdatePayee_FullMethodName               = "/pb.SimpleBank/UpdatePayee"
+	SimpleBank_DeletePayee_FullMethodName               = "/pb.SimpleBank/DeletePayee"
+	SimpleBank_CreatePaymentRequest_FullMethodName      = "/pb.SimpleBank/CreatePaymentRequest"
+	SimpleBank_ListPaymentRequests_FullMethodName       = "/pb.SimpleBank/ListPaymentRequests"
+	SimpleBank_AcceptPaymentRequest_FullMethodName      = "/pb.SimpleBank/AcceptPaymentRequest"
+	SimpleBank_DeclinePaymentRequest_FullMethodName     = "/pb.SimpleBank/DeclinePaymentRequest"
+	SimpleBank_ListAccountTransfers_FullMethodName      = "/pb.SimpleBank/ListAccountTransfers"
+	SimpleBank_QuoteTransfer_FullMethodName             = "/pb.SimpleBank/QuoteTransfer"
+	SimpleBank_ApproveTransfer_FullMethodName           = "/pb.SimpleBank/ApproveTransfer"
+	SimpleBank_RejectTransfer_FullMethodName            = "/pb.SimpleBank/RejectTransfer"
+	SimpleBank_ListPendingTransfers_FullMethodName      = "/pb.SimpleBank/ListPendingTransfers"
+	SimpleBank_CreateExternalTransfer_FullMethodName    = "/pb.SimpleBank/CreateExternalTransfer"
+	SimpleBank_GetExternalTransfer_FullMethodName       = "/pb.SimpleBank/GetExternalTransfer"
+	SimpleBank_ExportACHFile_FullMethodName             = "/pb.SimpleBank/ExportACHFile"
+	SimpleBank_ProcessACHReturns_FullMethodName         = "/pb.SimpleBank/ProcessACHReturns"
+	SimpleBank_ExportStatement_FullMethodName           = "/pb.SimpleBank/ExportStatement"
+	SimpleBank_ImportPayments_FullMethodName            = "/pb.SimpleBank/ImportPayments"
+	SimpleBank_CreateWebhookSubscription_FullMethodName = "/pb.SimpleBank/CreateWebhookSubscription"
+	SimpleBank_ListWebhookSubscriptions_FullMethodName  = "/pb.SimpleBank/ListWebhookSubscriptions"
+	SimpleBank_DeleteWebhookSubscription_FullMethodName = "/pb.SimpleBank/DeleteWebhookSubscription"
+	SimpleBank_ListWebhookDeliveries_FullMethodName     = "/pb.SimpleBank/ListWebhookDeliveries"
+	SimpleBank_RedeliverWebhook_FullMethodName          = "/pb.SimpleBank/RedeliverWebhook"
+	SimpleBank_WatchAccount_FullMethodName              = "/pb.SimpleBank/WatchAccount"
+	SimpleBank_ListFailedTasks_FullMethodName           = "/pb.SimpleBank/ListFailedTasks"
+	SimpleBank_GetFailedTask_FullMethodName             = "/pb.SimpleBank/GetFailedTask"
+	SimpleBank_RetryFailedTask_FullMethodName           = "/pb.SimpleBank/RetryFailedTask"
+	SimpleBank_DeleteFailedTask_FullMethodName          = "/pb.SimpleBank/DeleteFailedTask"
+	SimpleBank_CreateFeeSchedule_FullMethodName         = "/pb.SimpleBank/CreateFeeSchedule"
+	SimpleBank_ListFeeSchedules_FullMethodName          = "/pb.SimpleBank/ListFeeSchedules"
+	SimpleBank_DeleteFeeSchedule_FullMethodName         = "/pb.SimpleBank/DeleteFeeSchedule"
 )
 
 // SimpleBankClient is the client API for SimpleBank service.
@@ -33,6 +74,48 @@ type SimpleBankClient interface {
 	LoginUser(ctx context.Context, in *LoginUserRequest, opts ...grpc.CallOption) (*LoginUserResponse, error)
 	VerifyEmail(ctx context.Context, in *VerifyEmailRequest, opts ...grpc.CallOption) (*VerifyEmailResponse, error)
 	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*UpdateUserResponse, error)
+	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error)
+	CreateTransfer(ctx context.Context, in *CreateTransferRequest, opts ...grpc.CallOption) (*CreateTransferResponse, error)
+	FreezeAccount(ctx context.Context, in *FreezeAccountRequest, opts ...grpc.CallOption) (*FreezeAccountResponse, error)
+	UnfreezeAccount(ctx context.Context, in *UnfreezeAccountRequest, opts ...grpc.CallOption) (*UnfreezeAccountResponse, error)
+	SetOverdraftLimit(ctx context.Context, in *SetOverdraftLimitRequest, opts ...grpc.CallOption) (*SetOverdraftLimitResponse, error)
+	ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error)
+	AddAccountMember(ctx context.Context, in *AddAccountMemberRequest, opts ...grpc.CallOption) (*AddAccountMemberResponse, error)
+	RemoveAccountMember(ctx context.Context, in *RemoveAccountMemberRequest, opts ...grpc.CallOption) (*RemoveAccountMemberResponse, error)
+	ListAccountMembers(ctx context.Context, in *ListAccountMembersRequest, opts ...grpc.CallOption) (*ListAccountMembersResponse, error)
+	CreatePayee(ctx context.Context, in *CreatePayeeRequest, opts ...grpc.CallOption) (*CreatePayeeResponse, error)
+	ListPayees(ctx context.Context, in *ListPayeesRequest, opts ...grpc.CallOption) (*ListPayeesResponse, error)
+	UpdatePayee(ctx context.Context, in *UpdatePayeeRequest, opts ...grpc.CallOption) (*UpdatePayeeResponse, error)
+	DeletePayee(ctx context.Context, in *DeletePayeeRequest, opts ...grpc.CallOption) (*DeletePayeeResponse, error)
+	CreatePaymentRequest(ctx context.Context, in *CreatePaymentRequestRequest, opts ...grpc.CallOption) (*CreatePaymentRequestResponse, error)
+	ListPaymentRequests(ctx context.Context, in *ListPaymentRequestsRequest, opts ...grpc.CallOption) (*ListPaymentRequestsResponse, error)
+	AcceptPaymentRequest(ctx context.Context, in *AcceptPaymentRequestRequest, opts ...grpc.CallOption) (*AcceptPaymentRequestResponse, error)
+	DeclinePaymentRequest(ctx context.Context, in *DeclinePaymentRequestRequest, opts ...grpc.CallOption) (*DeclinePaymentRequestResponse, error)
+	ListAccountTransfers(ctx context.Context, in *ListAccountTransfersRequest, opts ...grpc.CallOption) (*ListAccountTransfersResponse, error)
+	QuoteTransfer(ctx context.Context, in *QuoteTransferRequest, opts ...grpc.CallOption) (*QuoteTransferResponse, error)
+	ApproveTransfer(ctx context.Context, in *ApproveTransferRequest, opts ...grpc.CallOption) (*ApproveTransferResponse, error)
+	RejectTransfer(ctx context.Context, in *RejectTransferRequest, opts ...grpc.CallOption) (*RejectTransferResponse, error)
+	ListPendingTransfers(ctx context.Context, in *ListPendingTransfersRequest, opts ...grpc.CallOption) (*ListPendingTransfersResponse, error)
+	CreateExternalTransfer(ctx context.Context, in *CreateExternalTransferRequest, opts ...grpc.CallOption) (*CreateExternalTransferResponse, error)
+	GetExternalTransfer(ctx context.Context, in *GetExternalTransferRequest, opts ...grpc.CallOption) (*GetExternalTransferResponse, error)
+	ExportACHFile(ctx context.Context, in *ExportACHFileRequest, opts ...grpc.CallOption) (*ExportACHFileResponse, error)
+	ProcessACHReturns(ctx context.Context, in *ProcessACHReturnsRequest, opts ...grpc.CallOption) (*ProcessACHReturnsResponse, error)
+	ExportStatement(ctx context.Context, in *ExportStatementRequest, opts ...grpc.CallOption) (*ExportStatementResponse, error)
+	ImportPayments(ctx context.Context, in *ImportPaymentsRequest, opts ...grpc.CallOption) (*ImportPaymentsResponse, error)
+	CreateWebhookSubscription(ctx context.Context, in *CreateWebhookSubscriptionRequest, opts ...grpc.CallOption) (*CreateWebhookSubscriptionResponse, error)
+	ListWebhookSubscriptions(ctx context.Context, in *ListWebhookSubscriptionsRequest, opts ...grpc.CallOption) (*ListWebhookSubscriptionsResponse, error)
+	DeleteWebhookSubscription(ctx context.Context, in *DeleteWebhookSubscriptionRequest, opts ...grpc.CallOption) (*DeleteWebhookSubscriptionResponse, error)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	RedeliverWebhook(ctx context.Context, in *RedeliverWebhookRequest, opts ...grpc.CallOption) (*RedeliverWebhookResponse, error)
+	// 流式接口，网关上没有对应的 REST 路由，浏览器用 GET /v1/watch_account 的 SSE
+	WatchAccount(ctx context.Context, in *WatchAccountRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchAccountResponse], error)
+	ListFailedTasks(ctx context.Context, in *ListFailedTasksRequest, opts ...grpc.CallOption) (*ListFailedTasksResponse, error)
+	GetFailedTask(ctx context.Context, in *GetFailedTaskRequest, opts ...grpc.CallOption) (*GetFailedTaskResponse, error)
+	RetryFailedTask(ctx context.Context, in *RetryFailedTaskRequest, opts ...grpc.CallOption) (*RetryFailedTaskResponse, error)
+	DeleteFailedTask(ctx context.Context, in *DeleteFailedTaskRequest, opts ...grpc.CallOption) (*DeleteFailedTaskResponse, error)
+	CreateFeeSchedule(ctx context.Context, in *CreateFeeScheduleRequest, opts ...grpc.CallOption) (*CreateFeeScheduleResponse, error)
+	ListFeeSchedules(ctx context.Context, in *ListFeeSchedulesRequest, opts ...grpc.CallOption) (*ListFeeSchedulesResponse, error)
+	DeleteFeeSchedule(ctx context.Context, in *DeleteFeeScheduleRequest, opts ...grpc.CallOption) (*DeleteFeeScheduleResponse, error)
 }
 
 type simpleBankClient struct {
@@ -83,6 +166,425 @@ func (c *simpleBankClient) UpdateUser(ctx context.Context, in *UpdateUserRequest
 	return out, nil
 }
 
+func (c *simpleBankClient) CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateAccountResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_CreateAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) CreateTransfer(ctx context.Context, in *CreateTransferRequest, opts ...grpc.CallOption) (*CreateTransferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateTransferResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_CreateTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) FreezeAccount(ctx context.Context, in *FreezeAccountRequest, opts ...grpc.CallOption) (*FreezeAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FreezeAccountResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_FreezeAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) UnfreezeAccount(ctx context.Context, in *UnfreezeAccountRequest, opts ...grpc.CallOption) (*UnfreezeAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnfreezeAccountResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_UnfreezeAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) SetOverdraftLimit(ctx context.Context, in *SetOverdraftLimitRequest, opts ...grpc.CallOption) (*SetOverdraftLimitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetOverdraftLimitResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_SetOverdraftLimit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAccountsResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ListAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) AddAccountMember(ctx context.Context, in *AddAccountMemberRequest, opts ...grpc.CallOption) (*AddAccountMemberResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddAccountMemberResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_AddAccountMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) RemoveAccountMember(ctx context.Context, in *RemoveAccountMemberRequest, opts ...grpc.CallOption) (*RemoveAccountMemberResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveAccountMemberResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_RemoveAccountMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ListAccountMembers(ctx context.Context, in *ListAccountMembersRequest, opts ...grpc.CallOption) (*ListAccountMembersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAccountMembersResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ListAccountMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) CreatePayee(ctx context.Context, in *CreatePayeeRequest, opts ...grpc.CallOption) (*CreatePayeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatePayeeResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_CreatePayee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ListPayees(ctx context.Context, in *ListPayeesRequest, opts ...grpc.CallOption) (*ListPayeesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPayeesResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ListPayees_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) UpdatePayee(ctx context.Context, in *UpdatePayeeRequest, opts ...grpc.CallOption) (*UpdatePayeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdatePayeeResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_UpdatePayee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) DeletePayee(ctx context.Context, in *DeletePayeeRequest, opts ...grpc.CallOption) (*DeletePayeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeletePayeeResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_DeletePayee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) CreatePaymentRequest(ctx context.Context, in *CreatePaymentRequestRequest, opts ...grpc.CallOption) (*CreatePaymentRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatePaymentRequestResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_CreatePaymentRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ListPaymentRequests(ctx context.Context, in *ListPaymentRequestsRequest, opts ...grpc.CallOption) (*ListPaymentRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPaymentRequestsResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ListPaymentRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) AcceptPaymentRequest(ctx context.Context, in *AcceptPaymentRequestRequest, opts ...grpc.CallOption) (*AcceptPaymentRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcceptPaymentRequestResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_AcceptPaymentRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) DeclinePaymentRequest(ctx context.Context, in *DeclinePaymentRequestRequest, opts ...grpc.CallOption) (*DeclinePaymentRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeclinePaymentRequestResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_DeclinePaymentRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ListAccountTransfers(ctx context.Context, in *ListAccountTransfersRequest, opts ...grpc.CallOption) (*ListAccountTransfersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAccountTransfersResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ListAccountTransfers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) QuoteTransfer(ctx context.Context, in *QuoteTransferRequest, opts ...grpc.CallOption) (*QuoteTransferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuoteTransferResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_QuoteTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ApproveTransfer(ctx context.Context, in *ApproveTransferRequest, opts ...grpc.CallOption) (*ApproveTransferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApproveTransferResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ApproveTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) RejectTransfer(ctx context.Context, in *RejectTransferRequest, opts ...grpc.CallOption) (*RejectTransferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RejectTransferResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_RejectTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ListPendingTransfers(ctx context.Context, in *ListPendingTransfersRequest, opts ...grpc.CallOption) (*ListPendingTransfersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPendingTransfersResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ListPendingTransfers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) CreateExternalTransfer(ctx context.Context, in *CreateExternalTransferRequest, opts ...grpc.CallOption) (*CreateExternalTransferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateExternalTransferResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_CreateExternalTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) GetExternalTransfer(ctx context.Context, in *GetExternalTransferRequest, opts ...grpc.CallOption) (*GetExternalTransferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetExternalTransferResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_GetExternalTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ExportACHFile(ctx context.Context, in *ExportACHFileRequest, opts ...grpc.CallOption) (*ExportACHFileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportACHFileResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ExportACHFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ProcessACHReturns(ctx context.Context, in *ProcessACHReturnsRequest, opts ...grpc.CallOption) (*ProcessACHReturnsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProcessACHReturnsResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ProcessACHReturns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ExportStatement(ctx context.Context, in *ExportStatementRequest, opts ...grpc.CallOption) (*ExportStatementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportStatementResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ExportStatement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ImportPayments(ctx context.Context, in *ImportPaymentsRequest, opts ...grpc.CallOption) (*ImportPaymentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportPaymentsResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ImportPayments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) CreateWebhookSubscription(ctx context.Context, in *CreateWebhookSubscriptionRequest, opts ...grpc.CallOption) (*CreateWebhookSubscriptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateWebhookSubscriptionResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_CreateWebhookSubscription_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ListWebhookSubscriptions(ctx context.Context, in *ListWebhookSubscriptionsRequest, opts ...grpc.CallOption) (*ListWebhookSubscriptionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhookSubscriptionsResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ListWebhookSubscriptions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) DeleteWebhookSubscription(ctx context.Context, in *DeleteWebhookSubscriptionRequest, opts ...grpc.CallOption) (*DeleteWebhookSubscriptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteWebhookSubscriptionResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_DeleteWebhookSubscription_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ListWebhookDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) RedeliverWebhook(ctx context.Context, in *RedeliverWebhookRequest, opts ...grpc.CallOption) (*RedeliverWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RedeliverWebhookResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_RedeliverWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) WatchAccount(ctx context.Context, in *WatchAccountRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchAccountResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SimpleBank_ServiceDesc.Streams[0], SimpleBank_WatchAccount_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchAccountRequest, WatchAccountResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SimpleBank_WatchAccountClient = grpc.ServerStreamingClient[WatchAccountResponse]
+
+func (c *simpleBankClient) ListFailedTasks(ctx context.Context, in *ListFailedTasksRequest, opts ...grpc.CallOption) (*ListFailedTasksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFailedTasksResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ListFailedTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) GetFailedTask(ctx context.Context, in *GetFailedTaskRequest, opts ...grpc.CallOption) (*GetFailedTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFailedTaskResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_GetFailedTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) RetryFailedTask(ctx context.Context, in *RetryFailedTaskRequest, opts ...grpc.CallOption) (*RetryFailedTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetryFailedTaskResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_RetryFailedTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) DeleteFailedTask(ctx context.Context, in *DeleteFailedTaskRequest, opts ...grpc.CallOption) (*DeleteFailedTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteFailedTaskResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_DeleteFailedTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) CreateFeeSchedule(ctx context.Context, in *CreateFeeScheduleRequest, opts ...grpc.CallOption) (*CreateFeeScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateFeeScheduleResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_CreateFeeSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) ListFeeSchedules(ctx context.Context, in *ListFeeSchedulesRequest, opts ...grpc.CallOption) (*ListFeeSchedulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFeeSchedulesResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_ListFeeSchedules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simpleBankClient) DeleteFeeSchedule(ctx context.Context, in *DeleteFeeScheduleRequest, opts ...grpc.CallOption) (*DeleteFeeScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteFeeScheduleResponse)
+	err := c.cc.Invoke(ctx, SimpleBank_DeleteFeeSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SimpleBankServer is the server API for SimpleBank service.
 // All implementations must embed UnimplementedSimpleBankServer
 // for forward compatibility.
@@ -91,117 +593,1013 @@ type SimpleBankServer interface {
 	LoginUser(context.Context, *LoginUserRequest) (*LoginUserResponse, error)
 	VerifyEmail(context.Context, *VerifyEmailRequest) (*VerifyEmailResponse, error)
 	UpdateUser(context.Context, *UpdateUserRequest) (*UpdateUserResponse, error)
+	CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error)
+	CreateTransfer(context.Context, *CreateTransferRequest) (*CreateTransferResponse, error)
+	FreezeAccount(context.Context, *FreezeAccountRequest) (*FreezeAccountResponse, error)
+	UnfreezeAccount(context.Context, *UnfreezeAccountRequest) (*UnfreezeAccountResponse, error)
+	SetOverdraftLimit(context.Context, *SetOverdraftLimitRequest) (*SetOverdraftLimitResponse, error)
+	ListAccounts(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error)
+	AddAccountMember(context.Context, *AddAccountMemberRequest) (*AddAccountMemberResponse, error)
+	RemoveAccountMember(context.Context, *RemoveAccountMemberRequest) (*RemoveAccountMemberResponse, error)
+	ListAccountMembers(context.Context, *ListAccountMembersRequest) (*ListAccountMembersResponse, error)
+	CreatePayee(context.Context, *CreatePayeeRequest) (*CreatePayeeResponse, error)
+	ListPayees(context.Context, *ListPayeesRequest) (*ListPayeesResponse, error)
+	UpdatePayee(context.Context, *UpdatePayeeRequest) (*UpdatePayeeResponse, error)
+	DeletePayee(context.Context, *DeletePayeeRequest) (*DeletePayeeResponse, error)
+	CreatePaymentRequest(context.Context, *CreatePaymentRequestRequest) (*CreatePaymentRequestResponse, error)
+	ListPaymentRequests(context.Context, *ListPaymentRequestsRequest) (*ListPaymentRequestsResponse, error)
+	AcceptPaymentRequest(context.Context, *AcceptPaymentRequestRequest) (*AcceptPaymentRequestResponse, error)
+	DeclinePaymentRequest(context.Context, *DeclinePaymentRequestRequest) (*DeclinePaymentRequestResponse, error)
+	ListAccountTransfers(context.Context, *ListAccountTransfersRequest) (*ListAccountTransfersResponse, error)
+	QuoteTransfer(context.Context, *QuoteTransferRequest) (*QuoteTransferResponse, error)
+	ApproveTransfer(context.Context, *ApproveTransferRequest) (*ApproveTransferResponse, error)
+	RejectTransfer(context.Context, *RejectTransferRequest) (*RejectTransferResponse, error)
+	ListPendingTransfers(context.Context, *ListPendingTransfersRequest) (*ListPendingTransfersResponse, error)
+	CreateExternalTransfer(context.Context, *CreateExternalTransferRequest) (*CreateExternalTransferResponse, error)
+	GetExternalTransfer(context.Context, *GetExternalTransferRequest) (*GetExternalTransferResponse, error)
+	ExportACHFile(context.Context, *ExportACHFileRequest) (*ExportACHFileResponse, error)
+	ProcessACHReturns(context.Context, *ProcessACHReturnsRequest) (*ProcessACHReturnsResponse, error)
+	ExportStatement(context.Context, *ExportStatementRequest) (*ExportStatementResponse, error)
+	ImportPayments(context.Context, *ImportPaymentsRequest) (*ImportPaymentsResponse, error)
+	CreateWebhookSubscription(context.Context, *CreateWebhookSubscriptionRequest) (*CreateWebhookSubscriptionResponse, error)
+	ListWebhookSubscriptions(context.Context, *ListWebhookSubscriptionsRequest) (*ListWebhookSubscriptionsResponse, error)
+	DeleteWebhookSubscription(context.Context, *DeleteWebhookSubscriptionRequest) (*DeleteWebhookSubscriptionResponse, error)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	RedeliverWebhook(context.Context, *RedeliverWebhookRequest) (*RedeliverWebhookResponse, error)
+	// 流式接口，网关上没有对应的 REST 路由，浏览器用 GET /v1/watch_account 的 SSE
+	WatchAccount(*WatchAccountRequest, grpc.ServerStreamingServer[WatchAccountResponse]) error
+	ListFailedTasks(context.Context, *ListFailedTasksRequest) (*ListFailedTasksResponse, error)
+	GetFailedTask(context.Context, *GetFailedTaskRequest) (*GetFailedTaskResponse, error)
+	RetryFailedTask(context.Context, *RetryFailedTaskRequest) (*RetryFailedTaskResponse, error)
+	DeleteFailedTask(context.Context, *DeleteFailedTaskRequest) (*DeleteFailedTaskResponse, error)
+	CreateFeeSchedule(context.Context, *CreateFeeScheduleRequest) (*CreateFeeScheduleResponse, error)
+	ListFeeSchedules(context.Context, *ListFeeSchedulesRequest) (*ListFeeSchedulesResponse, error)
+	DeleteFeeSchedule(context.Context, *DeleteFeeScheduleRequest) (*DeleteFeeScheduleResponse, error)
+	mustEmbedUnimplementedSimpleBankServer()
+}
+
+// UnimplementedSimpleBankServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSimpleBankServer struct{}
+
+func (UnimplementedSimpleBankServer) CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedSimpleBankServer) LoginUser(context.Context, *LoginUserRequest) (*LoginUserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method LoginUser not implemented")
+}
+func (UnimplementedSimpleBankServer) VerifyEmail(context.Context, *VerifyEmailRequest) (*VerifyEmailResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method VerifyEmail not implemented")
+}
+func (UnimplementedSimpleBankServer) UpdateUser(context.Context, *UpdateUserRequest) (*UpdateUserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (UnimplementedSimpleBankServer) CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateAccount not implemented")
+}
+func (UnimplementedSimpleBankServer) CreateTransfer(context.Context, *CreateTransferRequest) (*CreateTransferResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateTransfer not implemented")
+}
+func (UnimplementedSimpleBankServer) FreezeAccount(context.Context, *FreezeAccountRequest) (*FreezeAccountResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method FreezeAccount not implemented")
+}
+func (UnimplementedSimpleBankServer) UnfreezeAccount(context.Context, *UnfreezeAccountRequest) (*UnfreezeAccountResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnfreezeAccount not implemented")
+}
+func (UnimplementedSimpleBankServer) SetOverdraftLimit(context.Context, *SetOverdraftLimitRequest) (*SetOverdraftLimitResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetOverdraftLimit not implemented")
+}
+func (UnimplementedSimpleBankServer) ListAccounts(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAccounts not implemented")
+}
+func (UnimplementedSimpleBankServer) AddAccountMember(context.Context, *AddAccountMemberRequest) (*AddAccountMemberResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddAccountMember not implemented")
+}
+func (UnimplementedSimpleBankServer) RemoveAccountMember(context.Context, *RemoveAccountMemberRequest) (*RemoveAccountMemberResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveAccountMember not implemented")
+}
+func (UnimplementedSimpleBankServer) ListAccountMembers(context.Context, *ListAccountMembersRequest) (*ListAccountMembersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAccountMembers not implemented")
+}
+func (UnimplementedSimpleBankServer) CreatePayee(context.Context, *CreatePayeeRequest) (*CreatePayeeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreatePayee not implemented")
+}
+func (UnimplementedSimpleBankServer) ListPayees(context.Context, *ListPayeesRequest) (*ListPayeesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListPayees not implemented")
+}
+func (UnimplementedSimpleBankServer) UpdatePayee(context.Context, *UpdatePayeeRequest) (*UpdatePayeeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdatePayee not implemented")
+}
+func (UnimplementedSimpleBankServer) DeletePayee(context.Context, *DeletePayeeRequest) (*DeletePayeeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeletePayee not implemented")
+}
+func (UnimplementedSimpleBankServer) CreatePaymentRequest(context.Context, *CreatePaymentRequestRequest) (*CreatePaymentRequestResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreatePaymentRequest not implemented")
+}
+func (UnimplementedSimpleBankServer) ListPaymentRequests(context.Context, *ListPaymentRequestsRequest) (*ListPaymentRequestsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListPaymentRequests not implemented")
+}
+func (UnimplementedSimpleBankServer) AcceptPaymentRequest(context.Context, *AcceptPaymentRequestRequest) (*AcceptPaymentRequestResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AcceptPaymentRequest not implemented")
+}
+func (UnimplementedSimpleBankServer) DeclinePaymentRequest(context.Context, *DeclinePaymentRequestRequest) (*DeclinePaymentRequestResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeclinePaymentRequest not implemented")
+}
+func (UnimplementedSimpleBankServer) ListAccountTransfers(context.Context, *ListAccountTransfersRequest) (*ListAccountTransfersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAccountTransfers not implemented")
+}
+func (UnimplementedSimpleBankServer) QuoteTransfer(context.Context, *QuoteTransferRequest) (*QuoteTransferResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method QuoteTransfer not implemented")
+}
+func (UnimplementedSimpleBankServer) ApproveTransfer(context.Context, *ApproveTransferRequest) (*ApproveTransferResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ApproveTransfer not implemented")
+}
+func (UnimplementedSimpleBankServer) RejectTransfer(context.Context, *RejectTransferRequest) (*RejectTransferResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RejectTransfer not implemented")
+}
+func (UnimplementedSimpleBankServer) ListPendingTransfers(context.Context, *ListPendingTransfersRequest) (*ListPendingTransfersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListPendingTransfers not implemented")
+}
+func (UnimplementedSimpleBankServer) CreateExternalTransfer(context.Context, *CreateExternalTransferRequest) (*CreateExternalTransferResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateExternalTransfer not implemented")
+}
+func (UnimplementedSimpleBankServer) GetExternalTransfer(context.Context, *GetExternalTransferRequest) (*GetExternalTransferResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetExternalTransfer not implemented")
+}
+func (UnimplementedSimpleBankServer) ExportACHFile(context.Context, *ExportACHFileRequest) (*ExportACHFileResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportACHFile not implemented")
+}
+func (UnimplementedSimpleBankServer) ProcessACHReturns(context.Context, *ProcessACHReturnsRequest) (*ProcessACHReturnsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ProcessACHReturns not implemented")
+}
+func (UnimplementedSimpleBankServer) ExportStatement(context.Context, *ExportStatementRequest) (*ExportStatementResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportStatement not implemented")
+}
+func (UnimplementedSimpleBankServer) ImportPayments(context.Context, *ImportPaymentsRequest) (*ImportPaymentsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ImportPayments not implemented")
+}
+func (UnimplementedSimpleBankServer) CreateWebhookSubscription(context.Context, *CreateWebhookSubscriptionRequest) (*CreateWebhookSubscriptionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateWebhookSubscription not implemented")
+}
+func (UnimplementedSimpleBankServer) ListWebhookSubscriptions(context.Context, *ListWebhookSubscriptionsRequest) (*ListWebhookSubscriptionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListWebhookSubscriptions not implemented")
+}
+func (UnimplementedSimpleBankServer) DeleteWebhookSubscription(context.Context, *DeleteWebhookSubscriptionRequest) (*DeleteWebhookSubscriptionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteWebhookSubscription not implemented")
+}
+func (UnimplementedSimpleBankServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedSimpleBankServer) RedeliverWebhook(context.Context, *RedeliverWebhookRequest) (*RedeliverWebhookResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RedeliverWebhook not implemented")
+}
+func (UnimplementedSimpleBankServer) WatchAccount(*WatchAccountRequest, grpc.ServerStreamingServer[WatchAccountResponse]) error {
+	return status.Error(codes.Unimplemented, "method WatchAccount not implemented")
+}
+func (UnimplementedSimpleBankServer) ListFailedTasks(context.Context, *ListFailedTasksRequest) (*ListFailedTasksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListFailedTasks not implemented")
+}
+func (UnimplementedSimpleBankServer) GetFailedTask(context.Context, *GetFailedTaskRequest) (*GetFailedTaskResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetFailedTask not implemented")
+}
+func (UnimplementedSimpleBankServer) RetryFailedTask(context.Context, *RetryFailedTaskRequest) (*RetryFailedTaskResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RetryFailedTask not implemented")
+}
+func (UnimplementedSimpleBankServer) DeleteFailedTask(context.Context, *DeleteFailedTaskRequest) (*DeleteFailedTaskResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteFailedTask not implemented")
+}
+func (UnimplementedSimpleBankServer) CreateFeeSchedule(context.Context, *CreateFeeScheduleRequest) (*CreateFeeScheduleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateFeeSchedule not implemented")
+}
+func (UnimplementedSimpleBankServer) ListFeeSchedules(context.Context, *ListFeeSchedulesRequest) (*ListFeeSchedulesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListFeeSchedules not implemented")
+}
+func (UnimplementedSimpleBankServer) DeleteFeeSchedule(context.Context, *DeleteFeeScheduleRequest) (*DeleteFeeScheduleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteFeeSchedule not implemented")
+}
+func (UnimplementedSimpleBankServer) mustEmbedUnimplementedSimpleBankServer() {}
+func (UnimplementedSimpleBankServer) testEmbeddedByValue()                    {}
+
+// UnsafeSimpleBankServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SimpleBankServer will
+// result in compilation errors.
+type UnsafeSimpleBankServer interface {
 	mustEmbedUnimplementedSimpleBankServer()
 }
 
-// UnimplementedSimpleBankServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedSimpleBankServer struct{}
+func RegisterSimpleBankServer(s grpc.ServiceRegistrar, srv SimpleBankServer) {
+	// If the following call panics, it indicates UnimplementedSimpleBankServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SimpleBank_ServiceDesc, srv)
+}
+
+func _SimpleBank_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_LoginUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).LoginUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_LoginUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).LoginUser(ctx, req.(*LoginUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_VerifyEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).VerifyEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_VerifyEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).VerifyEmail(ctx, req.(*VerifyEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_UpdateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).UpdateUser(ctx, req.(*UpdateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_CreateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).CreateAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_CreateAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).CreateAccount(ctx, req.(*CreateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_CreateTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).CreateTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_CreateTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).CreateTransfer(ctx, req.(*CreateTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_FreezeAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreezeAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).FreezeAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_FreezeAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).FreezeAccount(ctx, req.(*FreezeAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_UnfreezeAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnfreezeAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).UnfreezeAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_UnfreezeAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).UnfreezeAccount(ctx, req.(*UnfreezeAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_SetOverdraftLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetOverdraftLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).SetOverdraftLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_SetOverdraftLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).SetOverdraftLimit(ctx, req.(*SetOverdraftLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_ListAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ListAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ListAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ListAccounts(ctx, req.(*ListAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_AddAccountMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddAccountMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).AddAccountMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_AddAccountMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).AddAccountMember(ctx, req.(*AddAccountMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_RemoveAccountMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveAccountMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).RemoveAccountMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_RemoveAccountMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).RemoveAccountMember(ctx, req.(*RemoveAccountMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_ListAccountMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccountMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ListAccountMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ListAccountMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ListAccountMembers(ctx, req.(*ListAccountMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_CreatePayee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePayeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).CreatePayee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_CreatePayee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).CreatePayee(ctx, req.(*CreatePayeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_ListPayees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPayeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ListPayees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ListPayees_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ListPayees(ctx, req.(*ListPayeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_UpdatePayee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePayeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).UpdatePayee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_UpdatePayee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).UpdatePayee(ctx, req.(*UpdatePayeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_DeletePayee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePayeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).DeletePayee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_DeletePayee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).DeletePayee(ctx, req.(*DeletePayeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_CreatePaymentRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePaymentRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).CreatePaymentRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_CreatePaymentRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).CreatePaymentRequest(ctx, req.(*CreatePaymentRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_ListPaymentRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPaymentRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ListPaymentRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ListPaymentRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ListPaymentRequests(ctx, req.(*ListPaymentRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_AcceptPaymentRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptPaymentRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).AcceptPaymentRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_AcceptPaymentRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).AcceptPaymentRequest(ctx, req.(*AcceptPaymentRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_DeclinePaymentRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeclinePaymentRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).DeclinePaymentRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_DeclinePaymentRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).DeclinePaymentRequest(ctx, req.(*DeclinePaymentRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_ListAccountTransfers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccountTransfersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ListAccountTransfers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ListAccountTransfers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ListAccountTransfers(ctx, req.(*ListAccountTransfersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_QuoteTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuoteTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).QuoteTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_QuoteTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).QuoteTransfer(ctx, req.(*QuoteTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_ApproveTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ApproveTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ApproveTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ApproveTransfer(ctx, req.(*ApproveTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_RejectTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).RejectTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_RejectTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).RejectTransfer(ctx, req.(*RejectTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_ListPendingTransfers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingTransfersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ListPendingTransfers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ListPendingTransfers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ListPendingTransfers(ctx, req.(*ListPendingTransfersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_CreateExternalTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateExternalTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).CreateExternalTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_CreateExternalTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).CreateExternalTransfer(ctx, req.(*CreateExternalTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_GetExternalTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExternalTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).GetExternalTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_GetExternalTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).GetExternalTransfer(ctx, req.(*GetExternalTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_ExportACHFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportACHFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ExportACHFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ExportACHFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ExportACHFile(ctx, req.(*ExportACHFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_ProcessACHReturns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProcessACHReturnsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ProcessACHReturns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ProcessACHReturns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ProcessACHReturns(ctx, req.(*ProcessACHReturnsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_ExportStatement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportStatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ExportStatement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ExportStatement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ExportStatement(ctx, req.(*ExportStatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-func (UnimplementedSimpleBankServer) CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method CreateUser not implemented")
+func _SimpleBank_ImportPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ImportPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ImportPayments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ImportPayments(ctx, req.(*ImportPaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedSimpleBankServer) LoginUser(context.Context, *LoginUserRequest) (*LoginUserResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method LoginUser not implemented")
+
+func _SimpleBank_CreateWebhookSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).CreateWebhookSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_CreateWebhookSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).CreateWebhookSubscription(ctx, req.(*CreateWebhookSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedSimpleBankServer) VerifyEmail(context.Context, *VerifyEmailRequest) (*VerifyEmailResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method VerifyEmail not implemented")
+
+func _SimpleBank_ListWebhookSubscriptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookSubscriptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ListWebhookSubscriptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ListWebhookSubscriptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ListWebhookSubscriptions(ctx, req.(*ListWebhookSubscriptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedSimpleBankServer) UpdateUser(context.Context, *UpdateUserRequest) (*UpdateUserResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method UpdateUser not implemented")
+
+func _SimpleBank_DeleteWebhookSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).DeleteWebhookSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_DeleteWebhookSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).DeleteWebhookSubscription(ctx, req.(*DeleteWebhookSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedSimpleBankServer) mustEmbedUnimplementedSimpleBankServer() {}
-func (UnimplementedSimpleBankServer) testEmbeddedByValue()                    {}
 
-// UnsafeSimpleBankServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to SimpleBankServer will
-// result in compilation errors.
-type UnsafeSimpleBankServer interface {
-	mustEmbedUnimplementedSimpleBankServer()
+func _SimpleBank_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ListWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterSimpleBankServer(s grpc.ServiceRegistrar, srv SimpleBankServer) {
-	// If the following call panics, it indicates UnimplementedSimpleBankServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func _SimpleBank_RedeliverWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeliverWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&SimpleBank_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(SimpleBankServer).RedeliverWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_RedeliverWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).RedeliverWebhook(ctx, req.(*RedeliverWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _SimpleBank_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateUserRequest)
+func _SimpleBank_WatchAccount_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAccountRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SimpleBankServer).WatchAccount(m, &grpc.GenericServerStream[WatchAccountRequest, WatchAccountResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SimpleBank_WatchAccountServer = grpc.ServerStreamingServer[WatchAccountResponse]
+
+func _SimpleBank_ListFailedTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFailedTasksRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SimpleBankServer).CreateUser(ctx, in)
+		return srv.(SimpleBankServer).ListFailedTasks(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SimpleBank_CreateUser_FullMethodName,
+		FullMethod: SimpleBank_ListFailedTasks_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SimpleBankServer).CreateUser(ctx, req.(*CreateUserRequest))
+		return srv.(SimpleBankServer).ListFailedTasks(ctx, req.(*ListFailedTasksRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SimpleBank_LoginUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoginUserRequest)
+func _SimpleBank_GetFailedTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFailedTaskRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SimpleBankServer).LoginUser(ctx, in)
+		return srv.(SimpleBankServer).GetFailedTask(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SimpleBank_LoginUser_FullMethodName,
+		FullMethod: SimpleBank_GetFailedTask_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SimpleBankServer).LoginUser(ctx, req.(*LoginUserRequest))
+		return srv.(SimpleBankServer).GetFailedTask(ctx, req.(*GetFailedTaskRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SimpleBank_VerifyEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(VerifyEmailRequest)
+func _SimpleBank_RetryFailedTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetryFailedTaskRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SimpleBankServer).VerifyEmail(ctx, in)
+		return srv.(SimpleBankServer).RetryFailedTask(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SimpleBank_VerifyEmail_FullMethodName,
+		FullMethod: SimpleBank_RetryFailedTask_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SimpleBankServer).VerifyEmail(ctx, req.(*VerifyEmailRequest))
+		return srv.(SimpleBankServer).RetryFailedTask(ctx, req.(*RetryFailedTaskRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SimpleBank_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateUserRequest)
+func _SimpleBank_DeleteFailedTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFailedTaskRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SimpleBankServer).UpdateUser(ctx, in)
+		return srv.(SimpleBankServer).DeleteFailedTask(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SimpleBank_UpdateUser_FullMethodName,
+		FullMethod: SimpleBank_DeleteFailedTask_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SimpleBankServer).UpdateUser(ctx, req.(*UpdateUserRequest))
+		return srv.(SimpleBankServer).DeleteFailedTask(ctx, req.(*DeleteFailedTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_CreateFeeSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFeeScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).CreateFeeSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_CreateFeeSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).CreateFeeSchedule(ctx, req.(*CreateFeeScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_ListFeeSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFeeSchedulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).ListFeeSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_ListFeeSchedules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).ListFeeSchedules(ctx, req.(*ListFeeSchedulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SimpleBank_DeleteFeeSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFeeScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimpleBankServer).DeleteFeeSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SimpleBank_DeleteFeeSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimpleBankServer).DeleteFeeSchedule(ctx, req.(*DeleteFeeScheduleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -229,7 +1627,173 @@ var SimpleBank_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateUser",
 			Handler:    _SimpleBank_UpdateUser_Handler,
 		},
+		{
+			MethodName: "CreateAccount",
+			Handler:    _SimpleBank_CreateAccount_Handler,
+		},
+		{
+			MethodName: "CreateTransfer",
+			Handler:    _SimpleBank_CreateTransfer_Handler,
+		},
+		{
+			MethodName: "FreezeAccount",
+			Handler:    _SimpleBank_FreezeAccount_Handler,
+		},
+		{
+			MethodName: "UnfreezeAccount",
+			Handler:    _SimpleBank_UnfreezeAccount_Handler,
+		},
+		{
+			MethodName: "SetOverdraftLimit",
+			Handler:    _SimpleBank_SetOverdraftLimit_Handler,
+		},
+		{
+			MethodName: "ListAccounts",
+			Handler:    _SimpleBank_ListAccounts_Handler,
+		},
+		{
+			MethodName: "AddAccountMember",
+			Handler:    _SimpleBank_AddAccountMember_Handler,
+		},
+		{
+			MethodName: "RemoveAccountMember",
+			Handler:    _SimpleBank_RemoveAccountMember_Handler,
+		},
+		{
+			MethodName: "ListAccountMembers",
+			Handler:    _SimpleBank_ListAccountMembers_Handler,
+		},
+		{
+			MethodName: "CreatePayee",
+			Handler:    _SimpleBank_CreatePayee_Handler,
+		},
+		{
+			MethodName: "ListPayees",
+			Handler:    _SimpleBank_ListPayees_Handler,
+		},
+		{
+			MethodName: "UpdatePayee",
+			Handler:    _SimpleBank_UpdatePayee_Handler,
+		},
+		{
+			MethodName: "DeletePayee",
+			Handler:    _SimpleBank_DeletePayee_Handler,
+		},
+		{
+			MethodName: "CreatePaymentRequest",
+			Handler:    _SimpleBank_CreatePaymentRequest_Handler,
+		},
+		{
+			MethodName: "ListPaymentRequests",
+			Handler:    _SimpleBank_ListPaymentRequests_Handler,
+		},
+		{
+			MethodName: "AcceptPaymentRequest",
+			Handler:    _SimpleBank_AcceptPaymentRequest_Handler,
+		},
+		{
+			MethodName: "DeclinePaymentRequest",
+			Handler:    _SimpleBank_DeclinePaymentRequest_Handler,
+		},
+		{
+			MethodName: "ListAccountTransfers",
+			Handler:    _SimpleBank_ListAccountTransfers_Handler,
+		},
+		{
+			MethodName: "QuoteTransfer",
+			Handler:    _SimpleBank_QuoteTransfer_Handler,
+		},
+		{
+			MethodName: "ApproveTransfer",
+			Handler:    _SimpleBank_ApproveTransfer_Handler,
+		},
+		{
+			MethodName: "RejectTransfer",
+			Handler:    _SimpleBank_RejectTransfer_Handler,
+		},
+		{
+			MethodName: "ListPendingTransfers",
+			Handler:    _SimpleBank_ListPendingTransfers_Handler,
+		},
+		{
+			MethodName: "CreateExternalTransfer",
+			Handler:    _SimpleBank_CreateExternalTransfer_Handler,
+		},
+		{
+			MethodName: "GetExternalTransfer",
+			Handler:    _SimpleBank_GetExternalTransfer_Handler,
+		},
+		{
+			MethodName: "ExportACHFile",
+			Handler:    _SimpleBank_ExportACHFile_Handler,
+		},
+		{
+			MethodName: "ProcessACHReturns",
+			Handler:    _SimpleBank_ProcessACHReturns_Handler,
+		},
+		{
+			MethodName: "ExportStatement",
+			Handler:    _SimpleBank_ExportStatement_Handler,
+		},
+		{
+			MethodName: "ImportPayments",
+			Handler:    _SimpleBank_ImportPayments_Handler,
+		},
+		{
+			MethodName: "CreateWebhookSubscription",
+			Handler:    _SimpleBank_CreateWebhookSubscription_Handler,
+		},
+		{
+			MethodName: "ListWebhookSubscriptions",
+			Handler:    _SimpleBank_ListWebhookSubscriptions_Handler,
+		},
+		{
+			MethodName: "DeleteWebhookSubscription",
+			Handler:    _SimpleBank_DeleteWebhookSubscription_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _SimpleBank_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "RedeliverWebhook",
+			Handler:    _SimpleBank_RedeliverWebhook_Handler,
+		},
+		{
+			MethodName: "ListFailedTasks",
+			Handler:    _SimpleBank_ListFailedTasks_Handler,
+		},
+		{
+			MethodName: "GetFailedTask",
+			Handler:    _SimpleBank_GetFailedTask_Handler,
+		},
+		{
+			MethodName: "RetryFailedTask",
+			Handler:    _SimpleBank_RetryFailedTask_Handler,
+		},
+		{
+			MethodName: "DeleteFailedTask",
+			Handler:    _SimpleBank_DeleteFailedTask_Handler,
+		},
+		{
+			MethodName: "CreateFeeSchedule",
+			Handler:    _SimpleBank_CreateFeeSchedule_Handler,
+		},
+		{
+			MethodName: "ListFeeSchedules",
+			Handler:    _SimpleBank_ListFeeSchedules_Handler,
+		},
+		{
+			MethodName: "DeleteFeeSchedule",
+			Handler:    _SimpleBank_DeleteFeeSchedule_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAccount",
+			Handler:       _SimpleBank_WatchAccount_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "service_simple_bank.proto",
 }
@@ -0,0 +1,147 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: transfer_approval.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PendingTransfer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transfer      *Transfer              `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	RequestedBy   string                 `protobuf:"bytes,2,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PendingTransfer) Reset() {
+	*x = PendingTransfer{}
+	mi := &file_transfer_approval_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PendingTransfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingTransfer) ProtoMessage() {}
+
+func (x *PendingTransfer) ProtoReflect() protoreflect.Message {
+	mi := &file_transfer_approval_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingTransfer.ProtoReflect.Descriptor instead.
+func (*PendingTransfer) Descriptor() ([]byte, []int) {
+	return file_transfer_approval_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PendingTransfer) GetTransfer() *Transfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *PendingTransfer) GetRequestedBy() string {
+	if x != nil {
+		return x.RequestedBy
+	}
+	return ""
+}
+
+func (x *PendingTransfer) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+var File_transfer_approval_proto protoreflect.FileDescriptor
+
+const file_transfer_approval_proto_rawDesc = "" +
+	"\n" +
+	"\x17transfer_approval.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x0etransfer.proto\"\x99\x01\n" +
+	"\x0fPendingTransfer\x12(\n" +
+	"\btransfer\x18\x01 \x01(\v2\f.pb.TransferR\btransfer\x12!\n" +
+	"\frequested_by\x18\x02 \x01(\tR\vrequestedBy\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAtB\x0fZ\rsimplebank/pbb\x06proto3"
+
+var (
+	file_transfer_approval_proto_rawDescOnce sync.Once
+	file_transfer_approval_proto_rawDescData []byte
+)
+
+func file_transfer_approval_proto_rawDescGZIP() []byte {
+	file_transfer_approval_proto_rawDescOnce.Do(func() {
+		file_transfer_approval_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_transfer_approval_proto_rawDesc), len(file_transfer_approval_proto_rawDesc)))
+	})
+	return file_transfer_approval_proto_rawDescData
+}
+
+var file_transfer_approval_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_transfer_approval_proto_goTypes = []any{
+	(*PendingTransfer)(nil),       // 0: pb.PendingTransfer
+	(*Transfer)(nil),              // 1: pb.Transfer
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+}
+var file_transfer_approval_proto_depIdxs = []int32{
+	1, // 0: pb.PendingTransfer.transfer:type_name -> pb.Transfer
+	2, // 1: pb.PendingTransfer.expires_at:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_transfer_approval_proto_init() }
+func file_transfer_approval_proto_init() {
+	if File_transfer_approval_proto != nil {
+		return
+	}
+	file_transfer_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_transfer_approval_proto_rawDesc), len(file_transfer_approval_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_transfer_approval_proto_goTypes,
+		DependencyIndexes: file_transfer_approval_proto_depIdxs,
+		MessageInfos:      file_transfer_approval_proto_msgTypes,
+	}.Build()
+	File_transfer_approval_proto = out.File
+	file_transfer_approval_proto_goTypes = nil
+	file_transfer_approval_proto_depIdxs = nil
+}
@@ -0,0 +1,80 @@
+package quote
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aead/chacha20poly1305"
+	"github.com/google/uuid"
+	"github.com/o1egl/paseto"
+)
+
+var (
+	ErrInvalidQuote = errors.New("invalid quote")
+	ErrExpiredQuote = errors.New("quote has expired")
+)
+
+// Terms 报价承诺的转账条款，报价 ID 就是加密后的 Terms，客户端看不到收款账户
+type Terms struct {
+	ID            uuid.UUID `json:"id"`
+	Username      string    `json:"username"`
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	Currency      string    `json:"currency"`
+	Fee           int64     `json:"fee"`
+	IssuedAt      time.Time `json:"issued_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// Maker 用 PASETO v2.local 生成和校验报价 ID
+type Maker struct {
+	paseto       *paseto.V2
+	symmetricKey []byte
+}
+
+// NewMaker 从访问令牌的密钥派生专用密钥，报价 ID 和访问令牌不能互相冒用
+func NewMaker(symmetricKey string) (*Maker, error) {
+	if len(symmetricKey) != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("invalid key size: must be exactly %d characters", chacha20poly1305.KeySize)
+	}
+
+	key := sha256.Sum256([]byte("transfer-quote:" + symmetricKey))
+	maker := &Maker{
+		paseto:       paseto.NewV2(),
+		symmetricKey: key[:],
+	}
+	return maker, nil
+}
+
+// CreateQuote 生成报价 ID，ID、签发时间和过期时间由这里填
+func (maker *Maker) CreateQuote(terms Terms, duration time.Duration) (string, *Terms, error) {
+	quoteID, err := uuid.NewRandom()
+	if err != nil {
+		return "", nil, err
+	}
+
+	terms.ID = quoteID
+	terms.IssuedAt = time.Now()
+	terms.ExpiresAt = terms.IssuedAt.Add(duration)
+
+	quote, err := maker.paseto.Encrypt(maker.symmetricKey, terms, nil)
+	return quote, &terms, err
+}
+
+func (maker *Maker) VerifyQuote(quote string) (*Terms, error) {
+	terms := &Terms{}
+
+	err := maker.paseto.Decrypt(quote, maker.symmetricKey, terms, nil)
+	if err != nil {
+		return nil, ErrInvalidQuote
+	}
+
+	if time.Now().After(terms.ExpiresAt) {
+		return nil, ErrExpiredQuote
+	}
+
+	return terms, nil
+}
@@ -0,0 +1,81 @@
+package quote
+
+import (
+	"testing"
+	"time"
+
+	"simplebank/token"
+	"simplebank/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomTerms() Terms {
+	return Terms{
+		Username:      util.RandomOwner(),
+		FromAccountID: util.RandomInt(1, 1000),
+		ToAccountID:   util.RandomInt(1, 1000),
+		Amount:        util.RandomMoney(),
+		Currency:      util.RandomCurrency(),
+		Fee:           util.RandomInt(0, 100),
+	}
+}
+
+func TestQuote(t *testing.T) {
+	maker, err := NewMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	terms := randomTerms()
+	duration := time.Minute
+
+	quote, created, err := maker.CreateQuote(terms, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, quote)
+	require.NotZero(t, created.ID)
+
+	verified, err := maker.VerifyQuote(quote)
+	require.NoError(t, err)
+	require.Equal(t, created.ID, verified.ID)
+	require.Equal(t, terms.Username, verified.Username)
+	require.Equal(t, terms.FromAccountID, verified.FromAccountID)
+	require.Equal(t, terms.ToAccountID, verified.ToAccountID)
+	require.Equal(t, terms.Amount, verified.Amount)
+	require.Equal(t, terms.Currency, verified.Currency)
+	require.Equal(t, terms.Fee, verified.Fee)
+	require.WithinDuration(t, time.Now().Add(duration), verified.ExpiresAt, time.Second)
+}
+
+func TestExpiredQuote(t *testing.T) {
+	maker, err := NewMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	quote, _, err := maker.CreateQuote(randomTerms(), -time.Minute)
+	require.NoError(t, err)
+
+	terms, err := maker.VerifyQuote(quote)
+	require.ErrorIs(t, err, ErrExpiredQuote)
+	require.Nil(t, terms)
+}
+
+func TestQuoteRejectsAccessToken(t *testing.T) {
+	key := util.RandomString(32)
+	maker, err := NewMaker(key)
+	require.NoError(t, err)
+
+	tokenMaker, err := token.NewPasetoMaker(key)
+	require.NoError(t, err)
+
+	// 同一个密钥签发的访问令牌不能当报价用
+	accessToken, _, err := tokenMaker.CreateToken(util.RandomOwner(), util.DepositorRole, time.Minute)
+	require.NoError(t, err)
+
+	terms, err := maker.VerifyQuote(accessToken)
+	require.ErrorIs(t, err, ErrInvalidQuote)
+	require.Nil(t, terms)
+}
+
+func TestInvalidQuoteKeySize(t *testing.T) {
+	maker, err := NewMaker(util.RandomString(16))
+	require.Error(t, err)
+	require.Nil(t, maker)
+}
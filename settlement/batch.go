@@ -0,0 +1,20 @@
+package settlement
+
+import "context"
+
+// BatchNetwork 按文件批量提交的网络，提交由运营导出文件完成，这里什么都不做
+type BatchNetwork struct {
+	name string
+}
+
+func NewBatchNetwork(name string) SettlementNetwork {
+	return &BatchNetwork{name: name}
+}
+
+func (network *BatchNetwork) Name() string {
+	return network.name
+}
+
+func (network *BatchNetwork) Submit(ctx context.Context, payment Payment) (string, error) {
+	return "", ErrQueuedForBatch
+}
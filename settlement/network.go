@@ -0,0 +1,36 @@
+package settlement
+
+import (
+	"context"
+	"errors"
+)
+
+// Payment 提交给清算网络的一笔他行付款，金额已经在清算账户里
+type Payment struct {
+	TransferID            int64
+	Amount                int64
+	Currency              string
+	DebtorAccountNumber   string
+	DebtorName            string
+	CreditorAccountNumber string
+	CreditorName          string
+	Memo                  string
+}
+
+// 清算网络异步回报的结果
+const (
+	OutcomeSettled  = "settled"
+	OutcomeFailed   = "failed"
+	OutcomeReturned = "returned"
+)
+
+// ErrQueuedForBatch 批量网络不逐笔提交，付款留在 initiated 等下一个批量文件
+var ErrQueuedForBatch = errors.New("payment is queued for the next batch file")
+
+// SettlementNetwork 对接他行的清算网络
+// Submit 只表示网络已受理，结果稍后异步回报，由 worker 调用 store 推进转账状态
+// 批量网络的 Submit 返回 ErrQueuedForBatch
+type SettlementNetwork interface {
+	Name() string
+	Submit(ctx context.Context, payment Payment) (reference string, err error)
+}
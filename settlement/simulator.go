@@ -0,0 +1,26 @@
+package settlement
+
+import "fmt"
+
+// SimulatorNetworkName 本地模拟网络，不连任何外部系统，方便离线测试整个流程
+const SimulatorNetworkName = "simulator"
+
+// SimulatedOutcome 按收款账号最后一位决定模拟结果：8 失败，9 先清算再被退回，其余清算成功
+func SimulatedOutcome(creditorAccountNumber string) string {
+	if creditorAccountNumber == "" {
+		return OutcomeFailed
+	}
+
+	switch creditorAccountNumber[len(creditorAccountNumber)-1] {
+	case '8':
+		return OutcomeFailed
+	case '9':
+		return OutcomeReturned
+	}
+	return OutcomeSettled
+}
+
+// SimulatedReference 模拟网络的流水号，同一笔转账重复提交得到同一个流水号
+func SimulatedReference(transferID int64) string {
+	return fmt.Sprintf("SIM%012d", transferID)
+}
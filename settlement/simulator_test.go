@@ -0,0 +1,31 @@
+package settlement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulatedOutcome(t *testing.T) {
+	testCases := []struct {
+		name          string
+		accountNumber string
+		expected      string
+	}{
+		{"Settled", "SB12OTHR0123456781", OutcomeSettled},
+		{"Failed", "SB12OTHR0123456788", OutcomeFailed},
+		{"Returned", "SB12OTHR0123456789", OutcomeReturned},
+		{"Empty", "", OutcomeFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, SimulatedOutcome(tc.accountNumber))
+		})
+	}
+}
+
+func TestSimulatedReference(t *testing.T) {
+	require.Equal(t, "SIM000000000042", SimulatedReference(42))
+	require.Equal(t, SimulatedReference(7), SimulatedReference(7))
+}
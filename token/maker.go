@@ -3,7 +3,7 @@ package token
 import "time"
 
 type Maker interface {
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
+	CreateToken(username string, role string, duration time.Duration) (string, *Payload, error)
 
 	VerifyToken(token string) (*Payload, error)
 }
@@ -0,0 +1,85 @@
+package util
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// 对外账号格式参照 IBAN: SB + 2 位校验码 + 4 位银行代码 + 10 位随机账号
+// 例如 SB55SMPL0123456789
+const (
+	AccountNumberCountryCode = "SB"
+	DefaultBankCode          = "SMPL"
+	// 银行内部的系统账户不对外，单独用一个银行代码
+	SystemBankCode = "BANK"
+)
+
+var (
+	isValidBankCode      = regexp.MustCompile(`^[A-Z0-9]{4}$`).MatchString
+	isValidAccountNumber = regexp.MustCompile(`^SB[0-9]{2}[A-Z0-9]{4}[0-9]{10}$`).MatchString
+	maxAccountDigits     = big.NewInt(10_000_000_000)
+)
+
+func IsValidBankCode(bankCode string) bool {
+	return isValidBankCode(bankCode)
+}
+
+// NewAccountNumber 随机生成账号，不能从数据库 ID 推出来
+func NewAccountNumber(bankCode string) (string, error) {
+	if !isValidBankCode(bankCode) {
+		return "", fmt.Errorf("invalid bank code %q", bankCode)
+	}
+
+	n, err := rand.Int(rand.Reader, maxAccountDigits)
+	if err != nil {
+		return "", err
+	}
+
+	bban := fmt.Sprintf("%s%010d", bankCode, n)
+	return AccountNumberCountryCode + accountNumberCheckDigits(bban) + bban, nil
+}
+
+// ValidAccountNumber 只做格式和 mod-97 校验，不查数据库
+// 能发现所有单个字符错误和绝大多数相邻字符互换
+func ValidAccountNumber(number string) bool {
+	if !isValidAccountNumber(number) {
+		return false
+	}
+	// 前 4 位移到末尾再算
+	return mod97(number[4:]+number[:4]) == 1
+}
+
+// AccountNumberBankCode 取出账号里的银行代码，账号格式不对时返回空串
+func AccountNumberBankCode(number string) string {
+	if !isValidAccountNumber(number) {
+		return ""
+	}
+	return number[4:8]
+}
+
+func accountNumberCheckDigits(bban string) string {
+	check := 98 - mod97(bban+AccountNumberCountryCode+"00")
+	return fmt.Sprintf("%02d", check)
+}
+
+// mod97 把字母换成两位数字 (A=10 ... Z=35) 后对 97 取模，逐位计算避免大数
+func mod97(s string) int {
+	var digits strings.Builder
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			digits.WriteRune(c)
+		case c >= 'A' && c <= 'Z':
+			fmt.Fprintf(&digits, "%d", c-'A'+10)
+		}
+	}
+
+	remainder := 0
+	for _, c := range digits.String() {
+		remainder = (remainder*10 + int(c-'0')) % 97
+	}
+	return remainder
+}
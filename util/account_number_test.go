@@ -0,0 +1,84 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMod97(t *testing.T) {
+	// ISO 13616 里的示例 IBAN GB82 WEST 1234 5698 7654 32
+	iban := "GB82WEST12345698765432"
+	require.Equal(t, 1, mod97(iban[4:]+iban[:4]))
+}
+
+func TestNewAccountNumber(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		number, err := NewAccountNumber(DefaultBankCode)
+		require.NoError(t, err)
+		require.Len(t, number, 18)
+		require.Equal(t, "SB", number[:2])
+		require.Equal(t, DefaultBankCode, number[4:8])
+		require.True(t, ValidAccountNumber(number), number)
+	}
+
+	_, err := NewAccountNumber("smpl")
+	require.Error(t, err)
+	_, err = NewAccountNumber("TOOLONG")
+	require.Error(t, err)
+}
+
+func TestValidAccountNumber(t *testing.T) {
+	number, err := NewAccountNumber(DefaultBankCode)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name   string
+		number string
+		valid  bool
+	}{
+		{"OK", number, true},
+		{"Empty", "", false},
+		{"Lowercase", "sb" + number[2:], false},
+		{"TooShort", number[:17], false},
+		{"WrongCountry", "XX" + number[2:], false},
+	}
+
+	// 每一位数字改成别的数字都要被发现
+	for i := 8; i < len(number); i++ {
+		wrong := []byte(number)
+		wrong[i] = '0' + (wrong[i]-'0'+1)%10
+		testCases = append(testCases, struct {
+			name   string
+			number string
+			valid  bool
+		}{"SingleDigitError", string(wrong), false})
+	}
+
+	// 相邻两位不同的数字互换要被发现
+	for i := 8; i < len(number)-1; i++ {
+		if number[i] == number[i+1] {
+			continue
+		}
+		swapped := []byte(number)
+		swapped[i], swapped[i+1] = swapped[i+1], swapped[i]
+		testCases = append(testCases, struct {
+			name   string
+			number string
+			valid  bool
+		}{"Transposition", string(swapped), false})
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.valid, ValidAccountNumber(tc.number), tc.number)
+		})
+	}
+}
+
+func TestAccountNumberBankCode(t *testing.T) {
+	number, err := NewAccountNumber("ACME")
+	require.NoError(t, err)
+	require.Equal(t, "ACME", AccountNumberBankCode(number))
+	require.Empty(t, AccountNumberBankCode("SB00"))
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/spf13/viper"
@@ -19,6 +20,48 @@ type Config struct {
 	EmailSenderPassword  string        `mapstructure:"EMAIL_SENDER_PASSWORD"`
 	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	// 新收款人冷静期内单笔转账金额上限，防止账号被盗后马上加收款人转走
+	PayeeCoolingOffPeriod time.Duration `mapstructure:"PAYEE_COOLING_OFF_PERIOD"`
+	PayeeCoolingOffLimit  int64         `mapstructure:"PAYEE_COOLING_OFF_LIMIT"`
+	// 对外账号里的 4 位银行代码
+	AccountNumberBankCode string `mapstructure:"ACCOUNT_NUMBER_BANK_CODE"`
+	// 转账报价的有效期，期内按报价的手续费转账
+	QuoteDuration time.Duration `mapstructure:"QUOTE_DURATION"`
+	// 超过阈值的转账需要第二个人审批，为 0 时不需要审批
+	TransferApprovalThreshold int64         `mapstructure:"TRANSFER_APPROVAL_THRESHOLD"`
+	TransferApprovalTimeout   time.Duration `mapstructure:"TRANSFER_APPROVAL_TIMEOUT"`
+	// 他行转账使用的清算网络：本地模拟网络 simulator 或批量文件 nacha
+	SettlementNetwork        string        `mapstructure:"SETTLEMENT_NETWORK"`
+	SettlementSimulatorDelay time.Duration `mapstructure:"SETTLEMENT_SIMULATOR_DELAY"`
+	// 批量文件的发起行信息，路由号都是 9 位 ABA 号
+	ACHImmediateDestination     string `mapstructure:"ACH_IMMEDIATE_DESTINATION"`
+	ACHImmediateDestinationName string `mapstructure:"ACH_IMMEDIATE_DESTINATION_NAME"`
+	ACHOriginRoutingNumber      string `mapstructure:"ACH_ORIGIN_ROUTING_NUMBER"`
+	ACHOriginName               string `mapstructure:"ACH_ORIGIN_NAME"`
+	ACHCompanyID                string `mapstructure:"ACH_COMPANY_ID"`
+	ACHCompanyName              string `mapstructure:"ACH_COMPANY_NAME"`
+	// 导出后过了退回期限还没被退回的付款记为已清算
+	ACHReturnWindow time.Duration `mapstructure:"ACH_RETURN_WINDOW"`
+	// outbox relay 扫描待投递任务的间隔
+	OutboxPollInterval time.Duration `mapstructure:"OUTBOX_POLL_INTERVAL"`
+	// 任务队列后端：redis、postgres 或只用于测试的 memory
+	TaskQueueBackend string `mapstructure:"TASK_QUEUE_BACKEND"`
+	// 周期任务调度，多个实例里只有拿到租约的那个投递
+	SchedulerEnabled bool `mapstructure:"SCHEDULER_ENABLED"`
+	// 覆盖默认的 cron 表达式 (UTC)，格式 job=spec;job=spec，spec 为 off 时不调度这个任务
+	SchedulerSpecs string `mapstructure:"SCHEDULER_SPECS"`
+	// 已处理的任务、outbox 消息和失败记录保留多久
+	TaskHistoryRetention time.Duration `mapstructure:"TASK_HISTORY_RETENTION"`
+	// 发件实现：smtp、写 .eml 文件的 file 或只用于测试的 memory
+	EmailSender  string `mapstructure:"EMAIL_SENDER"`
+	EmailFileDir string `mapstructure:"EMAIL_FILE_DIR"`
+	// SMTP 服务器，默认是 Gmail；TLS 为 implicit、starttls 或 none，认证为 plain、login、cram-md5 或 none
+	SMTPHost string `mapstructure:"SMTP_HOST"`
+	SMTPPort int    `mapstructure:"SMTP_PORT"`
+	SMTPTLS  string `mapstructure:"SMTP_TLS"`
+	SMTPAuth string `mapstructure:"SMTP_AUTH"`
+	// 为空时用发件地址登录
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -28,6 +71,27 @@ func LoadConfig(path string) (config Config, err error) {
 
 	viper.AutomaticEnv()
 
+	viper.SetDefault("PAYEE_COOLING_OFF_PERIOD", 24*time.Hour)
+	viper.SetDefault("PAYEE_COOLING_OFF_LIMIT", 100)
+	viper.SetDefault("ACCOUNT_NUMBER_BANK_CODE", DefaultBankCode)
+	viper.SetDefault("QUOTE_DURATION", 5*time.Minute)
+	viper.SetDefault("TRANSFER_APPROVAL_THRESHOLD", 1_000_000)
+	viper.SetDefault("TRANSFER_APPROVAL_TIMEOUT", 24*time.Hour)
+	viper.SetDefault("SETTLEMENT_NETWORK", "simulator")
+	viper.SetDefault("SETTLEMENT_SIMULATOR_DELAY", 30*time.Second)
+	viper.SetDefault("ACH_ORIGIN_NAME", "SIMPLE BANK")
+	viper.SetDefault("ACH_COMPANY_NAME", "SIMPLE BANK")
+	viper.SetDefault("ACH_RETURN_WINDOW", 48*time.Hour)
+	viper.SetDefault("OUTBOX_POLL_INTERVAL", time.Second)
+	viper.SetDefault("TASK_QUEUE_BACKEND", "redis")
+	viper.SetDefault("SCHEDULER_ENABLED", true)
+	viper.SetDefault("TASK_HISTORY_RETENTION", 7*24*time.Hour)
+	viper.SetDefault("EMAIL_SENDER", "smtp")
+	viper.SetDefault("SMTP_HOST", "smtp.gmail.com")
+	viper.SetDefault("SMTP_PORT", 465)
+	viper.SetDefault("SMTP_TLS", "implicit")
+	viper.SetDefault("SMTP_AUTH", "plain")
+
 	err = viper.ReadInConfig()
 	if err != nil {
 		// 如果错误是“找不到配置文件”，不应该报错返回
@@ -43,11 +107,23 @@ func LoadConfig(path string) (config Config, err error) {
 	viper.BindEnv("SERVER_ADDRESS")
 	viper.BindEnv("TOKEN_SYMMETRIC_KEY")
 	viper.BindEnv("ACCESS_TOKEN_DURATION")
+	viper.BindEnv("ACH_IMMEDIATE_DESTINATION")
+	viper.BindEnv("ACH_IMMEDIATE_DESTINATION_NAME")
+	viper.BindEnv("ACH_ORIGIN_ROUTING_NUMBER")
+	viper.BindEnv("ACH_COMPANY_ID")
+	viper.BindEnv("SCHEDULER_SPECS")
+	viper.BindEnv("EMAIL_FILE_DIR")
+	viper.BindEnv("SMTP_USERNAME")
 
 	err = viper.Unmarshal(&config)
 	if err != nil {
 		return
 	}
 
+	if !IsValidBankCode(config.AccountNumberBankCode) {
+		err = fmt.Errorf("invalid ACCOUNT_NUMBER_BANK_CODE %q, must be 4 uppercase letters or digits", config.AccountNumberBankCode)
+		return
+	}
+
 	return
 }
@@ -0,0 +1,34 @@
+package util
+
+import "math/big"
+
+// 转账类型，手续费按转账类型和转出账户的产品配置在 fee_schedules 表里
+const (
+	TransferTypeInternal = "internal"
+	TransferTypeExternal = "external"
+)
+
+func IsSupportedTransferType(transferType string) bool {
+	switch transferType {
+	case TransferTypeInternal, TransferTypeExternal:
+		return true
+	}
+	return false
+}
+
+// TransferFee 固定费用加上超出阈值部分的百分比费用，百分比部分四舍五入，maxFee 为 0 时不封顶
+func TransferFee(amount int64, flatFee int64, percentageBps int32, threshold int64, maxFee int64) int64 {
+	fee := flatFee
+
+	if percentageBps > 0 && amount > threshold {
+		// (amount - threshold) * bps / 10000，用大整数防止溢出
+		n := new(big.Int).Mul(big.NewInt(amount-threshold), big.NewInt(int64(percentageBps)))
+		n.Add(n, big.NewInt(5000))
+		fee += n.Quo(n, big.NewInt(10000)).Int64()
+	}
+
+	if maxFee > 0 && fee > maxFee {
+		fee = maxFee
+	}
+	return fee
+}
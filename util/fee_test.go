@@ -0,0 +1,45 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferFee(t *testing.T) {
+	testCases := []struct {
+		name          string
+		amount        int64
+		flatFee       int64
+		percentageBps int32
+		threshold     int64
+		maxFee        int64
+		expected      int64
+	}{
+		{"NoFee", 10000, 0, 0, 0, 0, 0},
+		{"FlatOnly", 10000, 300, 0, 0, 0, 300},
+		// 1% of (150000 - 100000)
+		{"PercentageAboveThreshold", 150000, 0, 100, 100000, 0, 500},
+		{"BelowThreshold", 90000, 0, 100, 100000, 0, 0},
+		{"AtThreshold", 100000, 0, 100, 100000, 0, 0},
+		{"FlatPlusPercentage", 20000, 50, 25, 0, 0, 100},
+		// 0.25% of 1234 = 3.085
+		{"RoundDown", 1234, 0, 25, 0, 0, 3},
+		// 0.25% of 1400 = 3.5
+		{"RoundHalfUp", 1400, 0, 25, 0, 0, 4},
+		{"Capped", 10000000, 100, 100, 0, 5000, 5000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fee := TransferFee(tc.amount, tc.flatFee, tc.percentageBps, tc.threshold, tc.maxFee)
+			require.Equal(t, tc.expected, fee)
+		})
+	}
+}
+
+func TestIsSupportedTransferType(t *testing.T) {
+	require.True(t, IsSupportedTransferType(TransferTypeInternal))
+	require.True(t, IsSupportedTransferType(TransferTypeExternal))
+	require.False(t, IsSupportedTransferType("cross_currency"))
+}
@@ -0,0 +1,17 @@
+package util
+
+// 账户冻结状态
+// DebitFrozen: 只进不出; Frozen: 双向冻结
+const (
+	FreezeStatusActive      = "active"
+	FreezeStatusDebitFrozen = "debit_frozen"
+	FreezeStatusFrozen      = "frozen"
+)
+
+func IsSupportedFreezeStatus(status string) bool {
+	switch status {
+	case FreezeStatusActive, FreezeStatusDebitFrozen, FreezeStatusFrozen:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package util
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// 计息天数惯例
+const (
+	DayCountAct365 = "ACT/365"
+	DayCountAct360 = "ACT/360"
+	DayCountActAct = "ACT/ACT"
+)
+
+// MicrosPerMinorUnit 利息按最小货币单位的百万分之一累计，避免每天四舍五入丢精度
+const MicrosPerMinorUnit = 1_000_000
+
+// DailyInterestMicros 计算某一天的利息，单位是最小货币单位的百万分之一
+// 余额为负或利率为 0 时不计息，结果向下取整
+func DailyInterestMicros(balance int64, annualRateBps int32, convention string, day time.Time) (int64, error) {
+	if balance <= 0 || annualRateBps <= 0 {
+		return 0, nil
+	}
+
+	daysInYear, err := yearBasis(convention, day)
+	if err != nil {
+		return 0, err
+	}
+
+	// balance * rate / 10000 / daysInYear * 1e6，用大整数防止溢出
+	n := new(big.Int).Mul(big.NewInt(balance), big.NewInt(int64(annualRateBps)))
+	n.Mul(n, big.NewInt(MicrosPerMinorUnit))
+	d := big.NewInt(10000 * daysInYear)
+
+	return n.Quo(n, d).Int64(), nil
+}
+
+func yearBasis(convention string, day time.Time) (int64, error) {
+	switch convention {
+	case DayCountAct365:
+		return 365, nil
+	case DayCountAct360:
+		return 360, nil
+	case DayCountActAct:
+		if isLeapYear(day.Year()) {
+			return 366, nil
+		}
+		return 365, nil
+	}
+	return 0, fmt.Errorf("unsupported day count convention %s", convention)
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
@@ -0,0 +1,42 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyInterestMicros(t *testing.T) {
+	day := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	leapDay := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		balance    int64
+		rateBps    int32
+		convention string
+		day        time.Time
+		expected   int64
+	}{
+		// 100000 * 2% / 365 = 5.479452...
+		{"Act365", 100000, 200, DayCountAct365, day, 5479452},
+		// 100000 * 2% / 360 = 5.555555...
+		{"Act360", 100000, 200, DayCountAct360, day, 5555555},
+		{"ActActLeapYear", 100000, 200, DayCountActAct, leapDay, 5464480},
+		{"ActActCommonYear", 100000, 200, DayCountActAct, day, 5479452},
+		{"NegativeBalance", -100000, 200, DayCountAct365, day, 0},
+		{"ZeroRate", 100000, 0, DayCountAct365, day, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			micros, err := DailyInterestMicros(tc.balance, tc.rateBps, tc.convention, tc.day)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, micros)
+		})
+	}
+
+	_, err := DailyInterestMicros(100000, 200, "30/360", day)
+	require.Error(t, err)
+}
@@ -0,0 +1,21 @@
+package util
+
+// 联名账户成员角色
+// owner 和 co_owner 可以转账，viewer 只能查看
+const (
+	MemberRoleOwner   = "owner"
+	MemberRoleCoOwner = "co_owner"
+	MemberRoleViewer  = "viewer"
+)
+
+func IsSupportedMemberRole(role string) bool {
+	switch role {
+	case MemberRoleOwner, MemberRoleCoOwner, MemberRoleViewer:
+		return true
+	}
+	return false
+}
+
+func CanTransfer(memberRole string) bool {
+	return memberRole == MemberRoleOwner || memberRole == MemberRoleCoOwner
+}
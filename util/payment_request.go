@@ -0,0 +1,11 @@
+package util
+
+// 付款请求状态，只有 pending 可以被接受或拒绝
+// 超过审批阈值的付款在转账审批期间是 pending_approval
+const (
+	PaymentRequestPending         = "pending"
+	PaymentRequestPendingApproval = "pending_approval"
+	PaymentRequestPaid            = "paid"
+	PaymentRequestDeclined        = "declined"
+	PaymentRequestExpired         = "expired"
+)
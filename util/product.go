@@ -0,0 +1,26 @@
+package util
+
+// 账户产品类型，具体规则（月转出次数、最低余额等）配置在 account_products 表里
+const (
+	CheckingProduct = "checking"
+	SavingsProduct  = "savings"
+)
+
+// 银行内部系统账户，全部挂在 BankUsername 下，用户不能自己开
+const (
+	BankUsername           = "bank"
+	InterestExpenseProduct = "interest_expense"
+	OverdraftIncomeProduct = "overdraft_interest_income"
+	FeeIncomeProduct       = "fee_income"
+	// 转给他行的钱先进清算账户，清算成功后转入结算账户
+	ExternalClearingProduct   = "external_clearing"
+	ExternalSettlementProduct = "external_settlement"
+)
+
+func IsSupportedProductType(productType string) bool {
+	switch productType {
+	case CheckingProduct, SavingsProduct:
+		return true
+	}
+	return false
+}
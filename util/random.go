@@ -41,3 +41,8 @@ func RandomCurrency() string {
 func RandomEmail() string {
 	return fmt.Sprintf("%s@email.com", RandomString(6))
 }
+
+func RandomAccountNumber() string {
+	bban := fmt.Sprintf("%s%010d", DefaultBankCode, RandomInt(0, 9_999_999_999))
+	return AccountNumberCountryCode + accountNumberCheckDigits(bban) + bban
+}
@@ -0,0 +1,8 @@
+package util
+
+const (
+	DepositorRole = "depositor"
+	BankerRole    = "banker"
+	AdminRole     = "admin"
+	SystemRole    = "system"
+)
@@ -0,0 +1,35 @@
+package util
+
+// 转账状态，超过审批阈值的转账先进入 pending_approval，批准后才真正入账
+const (
+	TransferStatusPendingApproval = "pending_approval"
+	TransferStatusCompleted       = "completed"
+	TransferStatusRejected        = "rejected"
+	TransferStatusExpired         = "expired"
+)
+
+// 转给他行的转账：initiated → pending → settled / failed / returned
+// 发起时资金已经转入清算账户，failed 和 returned 会把金额退回客户
+const (
+	TransferStatusInitiated = "initiated"
+	TransferStatusPending   = "pending"
+	TransferStatusSettled   = "settled"
+	TransferStatusFailed    = "failed"
+	TransferStatusReturned  = "returned"
+)
+
+var externalTransferTransitions = map[string][]string{
+	TransferStatusInitiated: {TransferStatusPending, TransferStatusFailed},
+	TransferStatusPending:   {TransferStatusSettled, TransferStatusFailed},
+	TransferStatusSettled:   {TransferStatusReturned},
+}
+
+// CanTransitionExternalTransfer 他行转账的状态只能按上面的顺序往前走
+func CanTransitionExternalTransfer(from string, to string) bool {
+	for _, next := range externalTransferTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
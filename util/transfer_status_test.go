@@ -0,0 +1,33 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransitionExternalTransfer(t *testing.T) {
+	testCases := []struct {
+		from     string
+		to       string
+		expected bool
+	}{
+		{TransferStatusInitiated, TransferStatusPending, true},
+		{TransferStatusInitiated, TransferStatusFailed, true},
+		{TransferStatusInitiated, TransferStatusSettled, false},
+		{TransferStatusPending, TransferStatusSettled, true},
+		{TransferStatusPending, TransferStatusFailed, true},
+		{TransferStatusPending, TransferStatusReturned, false},
+		{TransferStatusSettled, TransferStatusReturned, true},
+		{TransferStatusSettled, TransferStatusFailed, false},
+		{TransferStatusFailed, TransferStatusPending, false},
+		{TransferStatusReturned, TransferStatusSettled, false},
+		{TransferStatusCompleted, TransferStatusSettled, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.from+"_to_"+tc.to, func(t *testing.T) {
+			require.Equal(t, tc.expected, CanTransitionExternalTransfer(tc.from, tc.to))
+		})
+	}
+}
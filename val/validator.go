@@ -1,9 +1,13 @@
 package val
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/mail"
+	"net/url"
 	"regexp"
+	"simplebank/util"
+	"simplebank/webhook"
 )
 
 var (
@@ -55,3 +59,148 @@ func ValidatePassword(value string) error {
 	}
 	return nil
 }
+
+func ValidateID(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be a positive integer")
+	}
+	return nil
+}
+
+func ValidateAmount(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be greater than 0")
+	}
+	return nil
+}
+
+func ValidateCurrency(value string) error {
+	if !util.IsSupportedCurrency(value) {
+		return fmt.Errorf("unsupported currency %s", value)
+	}
+	return nil
+}
+
+func ValidateFreezeStatus(value string) error {
+	if value != util.FreezeStatusDebitFrozen && value != util.FreezeStatusFrozen {
+		return fmt.Errorf("must be %s or %s", util.FreezeStatusDebitFrozen, util.FreezeStatusFrozen)
+	}
+	return nil
+}
+
+func ValidateReason(value string) error {
+	return ValidateString(value, 1, 500)
+}
+
+func ValidateProductType(value string) error {
+	if !util.IsSupportedProductType(value) {
+		return fmt.Errorf("unsupported product type %s", value)
+	}
+	return nil
+}
+
+func ValidateTransferType(value string) error {
+	if !util.IsSupportedTransferType(value) {
+		return fmt.Errorf("unsupported transfer type %s", value)
+	}
+	return nil
+}
+
+// owner 只在开户时产生，不能再授予
+func ValidateMemberRole(value string) error {
+	if value != util.MemberRoleCoOwner && value != util.MemberRoleViewer {
+		return fmt.Errorf("unsupported member role: %s", value)
+	}
+	return nil
+}
+
+func ValidateNickname(value string) error {
+	return ValidateString(value, 1, 50)
+}
+
+// 转账备注双方可见，付款请求的备注在付款时会带到转账上，两者限制一致
+func ValidateMemo(value string) error {
+	return ValidateString(value, 0, 140)
+}
+
+func ValidatePrivateNote(value string) error {
+	return ValidateString(value, 0, 500)
+}
+
+func ValidateExternalReference(value string) error {
+	return ValidateString(value, 0, 64)
+}
+
+const (
+	maxMetadataKeys  = 20
+	maxMetadataBytes = 4096
+)
+
+// ValidateMetadata 键值都是字符串，序列化后不超过 4KB
+// 表上的约束按 jsonb 文本算，留了余量，只是兜底
+func ValidateMetadata(value map[string]string) error {
+	if len(value) > maxMetadataKeys {
+		return fmt.Errorf("must contain at most %d keys", maxMetadataKeys)
+	}
+
+	for k, v := range value {
+		if err := ValidateString(k, 1, 40); err != nil {
+			return fmt.Errorf("key %q %w", k, err)
+		}
+		if err := ValidateString(v, 0, 500); err != nil {
+			return fmt.Errorf("value of %q %w", k, err)
+		}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxMetadataBytes {
+		return fmt.Errorf("must be at most %d bytes", maxMetadataBytes)
+	}
+	return nil
+}
+
+// ValidateAccountNumber 在查库之前用校验码拦下输错的账号
+func ValidateAccountNumber(value string) error {
+	if !util.ValidAccountNumber(value) {
+		return fmt.Errorf("invalid account number")
+	}
+	return nil
+}
+
+// ValidateWebhookURL 生产环境只允许 https，本地开发可以用 http
+func ValidateWebhookURL(value string, requireHTTPS bool) error {
+	if err := ValidateString(value, 1, 2000); err != nil {
+		return err
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return fmt.Errorf("must be an absolute http or https URL")
+	}
+	if requireHTTPS && u.Scheme != "https" {
+		return fmt.Errorf("must use https")
+	}
+	if u.User != nil {
+		return fmt.Errorf("must not contain credentials")
+	}
+	return nil
+}
+
+func ValidateWebhookEventTypes(values []string) error {
+	if len(values) == 0 {
+		return fmt.Errorf("must contain at least one event type")
+	}
+	seen := make(map[string]bool)
+	for _, value := range values {
+		if !webhook.IsSupportedEventType(value) {
+			return fmt.Errorf("unsupported event type %s", value)
+		}
+		if seen[value] {
+			return fmt.Errorf("duplicate event type %s", value)
+		}
+		seen[value] = true
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+)
+
+// ErrDisallowedAddress 订阅的 URL 指向内网、本机或链路本地地址，不能拿 webhook 探测内部服务
+var ErrDisallowedAddress = errors.New("webhook URL must resolve to a public address")
+
+// AllowedIP 只允许公网地址
+func AllowedIP(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified())
+}
+
+// CheckURL 创建订阅时解析主机名，任何一个地址不是公网地址都拒绝
+// 投递时连接前还会再检查一次，防止 DNS 在两次解析之间被改掉
+func CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !AllowedIP(ip) {
+			return ErrDisallowedAddress
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if !AllowedIP(addr.IP) {
+			return ErrDisallowedAddress
+		}
+	}
+	return nil
+}
+
+// dialControl 在解析完、真正建立连接前检查对方地址
+func dialControl(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !AllowedIP(ip) {
+		return ErrDisallowedAddress
+	}
+	return nil
+}
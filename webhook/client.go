@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Client 把签过名的事件 POST 给订阅的 URL，只连公网地址
+type Client struct {
+	httpClient *http.Client
+}
+
+type ClientOption func(dialer *net.Dialer)
+
+// AllowPrivateNetworks 允许连内网和本机地址，只用于测试
+func AllowPrivateNetworks() ClientOption {
+	return func(dialer *net.Dialer) {
+		dialer.Control = nil
+	}
+}
+
+func NewClient(timeout time.Duration, opts ...ClientOption) *Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: dialControl,
+	}
+	for _, opt := range opts {
+		opt(dialer)
+	}
+
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: timeout,
+			// 不走代理，否则检查的是代理的地址而不是接收方的
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+			},
+			// 不跟随跳转，避免签过名的请求被转到别处
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Delivery 一次投递需要的全部内容，Body 是事件序列化后的 JSON
+type Delivery struct {
+	URL       string
+	Secret    string
+	EventID   string
+	EventType string
+	Body      []byte
+}
+
+// Deliver 返回接收方的 HTTP 状态码，连不上时为 0；非 2xx 都算失败
+func (client *Client) Deliver(ctx context.Context, delivery Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "simplebank-webhook/1.0")
+	req.Header.Set(EventIDHeader, delivery.EventID)
+	req.Header.Set(EventTypeHeader, delivery.EventType)
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, time.Now(), delivery.Body))
+
+	rsp, err := client.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	// 响应体不读也不保存，否则订阅者能通过投递记录看到接收方返回的内容
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return rsp.StatusCode, errors.New(FailureMessage(rsp.StatusCode))
+	}
+	return rsp.StatusCode, nil
+}
+
+// FailureMessage 给订阅者看的失败原因，只有状态码，不带响应体和连接错误的细节
+func FailureMessage(responseStatus int) string {
+	if responseStatus == 0 {
+		return "failed to connect to receiver"
+	}
+	return fmt.Sprintf("receiver responded with %d", responseStatus)
+}
+
+// RetryDelay 投递失败后按指数退避重试，从 30 秒开始，最长 12 小时
+func RetryDelay(retried int) time.Duration {
+	const (
+		base     = 30 * time.Second
+		maxDelay = 12 * time.Hour
+	)
+	if retried >= 20 {
+		return maxDelay
+	}
+	delay := base << retried
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
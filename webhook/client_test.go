@@ -0,0 +1,145 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDeliver(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+
+	event, err := NewEvent(EventAccountCredited, AccountCreditedData{AccountID: 1, TransferID: 2, Amount: 100, Currency: "USD"})
+	require.NoError(t, err)
+	body := []byte(`{"id":"` + event.ID.String() + `"}`)
+
+	var received *http.Request
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, body, payload)
+		require.NoError(t, Verify(secret, r.Header.Get(SignatureHeader), payload, DefaultTolerance, time.Now()))
+		received = r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	client := NewClient(time.Second, AllowPrivateNetworks())
+	code, err := client.Deliver(context.Background(), Delivery{
+		URL:       receiver.URL,
+		Secret:    secret,
+		EventID:   event.ID.String(),
+		EventType: event.Type,
+		Body:      body,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, code)
+
+	require.NotNil(t, received)
+	require.Equal(t, http.MethodPost, received.Method)
+	require.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	require.Equal(t, event.ID.String(), received.Header.Get(EventIDHeader))
+	require.Equal(t, EventAccountCredited, received.Header.Get(EventTypeHeader))
+}
+
+func TestClientDeliverFailure(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/error":
+			http.Error(w, "boom", http.StatusInternalServerError)
+		case "/redirect":
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	defer receiver.Close()
+
+	client := NewClient(100*time.Millisecond, AllowPrivateNetworks())
+	deliver := func(path string) (int, error) {
+		return client.Deliver(context.Background(), Delivery{
+			URL:       receiver.URL + path,
+			Secret:    "whsec_test",
+			EventID:   "1",
+			EventType: EventTransferCreated,
+			Body:      []byte(`{}`),
+		})
+	}
+
+	code, err := deliver("/error")
+	require.EqualError(t, err, "receiver responded with 500")
+	require.NotContains(t, err.Error(), "boom")
+	require.Equal(t, http.StatusInternalServerError, code)
+
+	// 不跟随跳转
+	code, err = deliver("/redirect")
+	require.Error(t, err)
+	require.Equal(t, http.StatusFound, code)
+
+	code, err = deliver("/slow")
+	require.Error(t, err)
+	require.Zero(t, code)
+}
+
+func TestClientRefusesPrivateAddress(t *testing.T) {
+	var called bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	client := NewClient(time.Second)
+	code, err := client.Deliver(context.Background(), Delivery{
+		URL:       receiver.URL,
+		Secret:    "whsec_test",
+		EventID:   "1",
+		EventType: EventTransferCreated,
+		Body:      []byte(`{}`),
+	})
+	require.ErrorIs(t, err, ErrDisallowedAddress)
+	require.Zero(t, code)
+	require.False(t, called)
+}
+
+func TestAllowedIP(t *testing.T) {
+	for _, addr := range []string{
+		"127.0.0.1",
+		"::1",
+		"10.0.0.1",
+		"172.16.5.4",
+		"192.168.1.1",
+		"169.254.169.254",
+		"fe80::1",
+		"fd00::1",
+		"0.0.0.0",
+		"::",
+		"224.0.0.1",
+	} {
+		require.False(t, AllowedIP(net.ParseIP(addr)), addr)
+	}
+
+	for _, addr := range []string{"8.8.8.8", "93.184.216.34", "2606:4700::1111"} {
+		require.True(t, AllowedIP(net.ParseIP(addr)), addr)
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	for _, rawURL := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://[::1]/hook",
+		"https://169.254.169.254/latest/meta-data",
+		"https://10.1.2.3/hook",
+		"http://localhost/hook",
+	} {
+		require.ErrorIs(t, CheckURL(context.Background(), rawURL), ErrDisallowedAddress, rawURL)
+	}
+
+	require.NoError(t, CheckURL(context.Background(), "https://8.8.8.8/hook"))
+}
@@ -0,0 +1,96 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 可以订阅的事件
+const (
+	EventTransferCreated   = "transfer.created"
+	EventAccountCredited   = "account.credited"
+	EventUserEmailVerified = "user.email_verified"
+)
+
+var EventTypes = []string{
+	EventTransferCreated,
+	EventAccountCredited,
+	EventUserEmailVerified,
+}
+
+func IsSupportedEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// 投递记录的状态
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	// 重试次数用完，可以手动重投
+	DeliveryStatusFailed = "failed"
+)
+
+// Event 是发给接收方的请求体，同一个事件重投时 ID 不变，接收方按 ID 去重
+type Event struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+func NewEvent(eventType string, data any) (Event, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return Event{}, err
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	event := Event{
+		ID:        id,
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      jsonData,
+	}
+	return event, nil
+}
+
+// TransferData transfer.created 的数据，发给转出账户的户主，不带转入账户
+type TransferData struct {
+	TransferID        int64  `json:"transfer_id"`
+	FromAccountID     int64  `json:"from_account_id"`
+	Amount            int64  `json:"amount"`
+	Fee               int64  `json:"fee"`
+	Currency          string `json:"currency"`
+	Status            string `json:"status"`
+	Memo              string `json:"memo"`
+	ExternalReference string `json:"external_reference"`
+}
+
+// AccountCreditedData account.credited 的数据，发给转入账户的户主
+type AccountCreditedData struct {
+	AccountID     int64  `json:"account_id"`
+	AccountNumber string `json:"account_number"`
+	TransferID    int64  `json:"transfer_id"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	Balance       int64  `json:"balance"`
+	Memo          string `json:"memo"`
+}
+
+// EmailVerifiedData user.email_verified 的数据
+type EmailVerifiedData struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
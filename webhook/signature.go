@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 请求头
+const (
+	// t=<unix 秒>,v1=<hex(HMAC-SHA256(secret, "<t>.<body>"))>
+	SignatureHeader = "Webhook-Signature"
+	EventIDHeader   = "Webhook-Id"
+	EventTypeHeader = "Webhook-Event"
+)
+
+// DefaultTolerance 接收方校验时允许的时间差，超过的当作重放
+const DefaultTolerance = 5 * time.Minute
+
+const secretPrefix = "whsec_"
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrExpiredSignature = errors.New("webhook signature timestamp is outside the tolerance")
+)
+
+// NewSecret 生成订阅的签名密钥
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return secretPrefix + hex.EncodeToString(b), nil
+}
+
+// Sign 时间戳也参与签名，接收方可以拒绝过期的请求
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	return fmt.Sprintf("t=%d,v1=%s", timestamp.Unix(), computeSignature(secret, timestamp.Unix(), body))
+}
+
+// Verify 给接收方用，校验签名和时间戳
+func Verify(secret string, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var timestamp int64
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ErrInvalidSignature
+		}
+		switch key {
+		case "t":
+			t, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrInvalidSignature
+			}
+			timestamp = t
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	diff := now.Sub(time.Unix(timestamp, 0))
+	if diff > tolerance || diff < -tolerance {
+		return ErrExpiredSignature
+	}
+
+	expected := computeSignature(secret, timestamp, body)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func computeSignature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
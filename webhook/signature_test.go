@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignature(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(secret, secretPrefix))
+
+	body := []byte(`{"id":"1","type":"transfer.created"}`)
+	now := time.Now()
+	header := Sign(secret, now, body)
+
+	require.NoError(t, Verify(secret, header, body, DefaultTolerance, now))
+	require.NoError(t, Verify(secret, header, body, DefaultTolerance, now.Add(DefaultTolerance-time.Second)))
+
+	testCases := []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		err    error
+	}{
+		{"WrongSecret", "whsec_other", header, body, now, ErrInvalidSignature},
+		{"TamperedBody", secret, header, []byte(`{"id":"2"}`), now, ErrInvalidSignature},
+		{"Replayed", secret, header, body, now.Add(DefaultTolerance + time.Minute), ErrExpiredSignature},
+		{"FromFuture", secret, header, body, now.Add(-DefaultTolerance - time.Minute), ErrExpiredSignature},
+		{"MissingSignature", secret, strings.Split(header, ",")[0], body, now, ErrInvalidSignature},
+		{"Malformed", secret, "garbage", body, now, ErrInvalidSignature},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Verify(tc.secret, tc.header, tc.body, DefaultTolerance, tc.now)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	require.Equal(t, 30*time.Second, RetryDelay(0))
+	require.Equal(t, time.Minute, RetryDelay(1))
+	require.Equal(t, 8*time.Minute, RetryDelay(4))
+	require.Equal(t, 12*time.Hour, RetryDelay(15))
+	require.Equal(t, 12*time.Hour, RetryDelay(100))
+}
@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// 任务队列后端，由 util.Config.TaskQueueBackend 选择
+const (
+	BackendRedis    = "redis"
+	BackendPostgres = "postgres"
+	// 进程内存，重启丢任务，只用于测试和单进程开发
+	BackendMemory = "memory"
+)
+
+// TaskEnqueuer 是 relay 往队列里投任务的一端，任务 ID 重复时返回 asynq.ErrTaskIDConflict
+type TaskEnqueuer interface {
+	EnqueueContext(ctx context.Context, task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error)
+	Close() error
+}
+
+// TaskServer 是 processor 从队列里取任务的一端，Run 阻塞到 Shutdown
+type TaskServer interface {
+	Run(handler asynq.Handler) error
+	Shutdown()
+}
+
+// ServerConfig 各个后端共用的处理配置，语义和 asynq.Config 里同名的字段一致
+type ServerConfig struct {
+	// 队列的权重，权重高的队列被取到的机会多，但不会饿死其他队列
+	Queues         map[string]int
+	RetryDelayFunc asynq.RetryDelayFunc
+	// 每次处理失败都会调用，包括还会重试的
+	ErrorHandler asynq.ErrorHandler
+}
+
+// TaskBackend 同一个后端的投递端、处理端和运维查看失败任务的一端
+type TaskBackend interface {
+	Enqueuer() TaskEnqueuer
+	Server(config ServerConfig) TaskServer
+	Inspector() TaskInspector
+}
+
+func NewTaskBackend(name string, redisOpt asynq.RedisClientOpt, store db.Store) (TaskBackend, error) {
+	switch name {
+	case BackendRedis:
+		return &redisBackend{redisOpt: redisOpt}, nil
+	case BackendPostgres:
+		return NewPostgresQueue(store), nil
+	case BackendMemory:
+		return NewMemoryQueue(), nil
+	}
+	return nil, fmt.Errorf("unknown task queue backend %q", name)
+}
+
+// queuedTaskState Postgres 和内存队列的状态换成 asynq 的，失败后等待重试的任务是 retry
+func queuedTaskState(state string, retried int, processAt time.Time) asynq.TaskState {
+	switch state {
+	case "active":
+		return asynq.TaskStateActive
+	case "completed":
+		return asynq.TaskStateCompleted
+	case "archived":
+		return asynq.TaskStateArchived
+	}
+	if retried > 0 {
+		return asynq.TaskStateRetry
+	}
+	if processAt.After(time.Now()) {
+		return asynq.TaskStateScheduled
+	}
+	return asynq.TaskStatePending
+}
+
+type redisBackend struct {
+	redisOpt asynq.RedisClientOpt
+}
+
+func (backend *redisBackend) Enqueuer() TaskEnqueuer {
+	return asynq.NewClient(backend.redisOpt)
+}
+
+func (backend *redisBackend) Inspector() TaskInspector {
+	return &redisInspector{inspector: asynq.NewInspector(backend.redisOpt)}
+}
+
+func (backend *redisBackend) Server(config ServerConfig) TaskServer {
+	return asynq.NewServer(backend.redisOpt, asynq.Config{
+		Queues:         config.Queues,
+		RetryDelayFunc: config.RetryDelayFunc,
+		ErrorHandler:   config.ErrorHandler,
+		Logger:         NewLogger(),
+	})
+}
+
+// taskOptions 入队选项解析后的结果，只支持 outbox 里能存下的选项
+type taskOptions struct {
+	queue    string
+	maxRetry int
+	// 零值表示马上处理
+	processAt time.Time
+	taskID    string
+}
+
+func parseTaskOptions(opts []asynq.Option) (taskOptions, error) {
+	options := taskOptions{
+		queue:    defaultQueue,
+		maxRetry: defaultMaxRetry,
+	}
+	for _, opt := range opts {
+		switch opt.Type() {
+		case asynq.QueueOpt:
+			options.queue = opt.Value().(string)
+		case asynq.MaxRetryOpt:
+			options.maxRetry = opt.Value().(int)
+		case asynq.ProcessInOpt:
+			options.processAt = time.Now().Add(opt.Value().(time.Duration))
+		case asynq.ProcessAtOpt:
+			options.processAt = opt.Value().(time.Time)
+		case asynq.TaskIDOpt:
+			options.taskID = opt.Value().(string)
+		case asynq.RetentionOpt:
+			// 完成的任务在被清理之前都会保留，按任务 ID 去重
+		default:
+			return options, fmt.Errorf("unsupported task option %s", opt)
+		}
+	}
+	return options, nil
+}
@@ -2,23 +2,74 @@ package worker
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	db "simplebank/db/sqlc"
 
 	"github.com/hibiken/asynq"
 )
 
+// 和 asynq 的默认值一致
+const (
+	defaultQueue    = QueueDefault
+	defaultMaxRetry = 25
+)
+
+// TaskDistributor 只负责投递序列化好的任务，按类型投递用 Task[P].Distribute
 type TaskDistributor interface {
-	DistributeTaskSendVerifyEmail(
+	DistributeTask(
 		ctx context.Context,
-		payload *PayloadSendVerifyEmail,
+		taskType string,
+		payload []byte,
 		opts ...asynq.Option,
 	) error
 }
 
-type RedisTaskDistributor struct {
-	client *asynq.Client
+// OutboxTaskDistributor 把任务写进 outbox 表，由 OutboxRelay 投递到任务队列
+// 用事务里的 Querier 创建时，任务和业务数据一起提交或回滚
+type OutboxTaskDistributor struct {
+	q db.Querier
+}
+
+func NewOutboxTaskDistributor(q db.Querier) TaskDistributor {
+	return &OutboxTaskDistributor{q: q}
 }
 
-func NewRedisTaskDistributor(redisOpt asynq.RedisClientOpt) TaskDistributor {
-	client := asynq.NewClient(redisOpt)
-	return &RedisTaskDistributor{client: client}
+func (distributor *OutboxTaskDistributor) DistributeTask(
+	ctx context.Context,
+	taskType string,
+	payload []byte,
+	opts ...asynq.Option,
+) error {
+	options, err := parseTaskOptions(opts)
+	if err != nil {
+		return err
+	}
+	// 任务 ID 由 relay 按 outbox ID 生成
+	if options.taskID != "" {
+		return fmt.Errorf("task id is assigned by the outbox relay")
+	}
+
+	arg := db.CreateOutboxMessageParams{
+		TaskType: taskType,
+		Payload:  payload,
+		Queue:    options.queue,
+		MaxRetry: int32(options.maxRetry),
+	}
+	if !options.processAt.IsZero() {
+		arg.ProcessAt = sql.NullTime{Time: options.processAt, Valid: true}
+	}
+
+	message, err := distributor.q.CreateOutboxMessage(ctx, arg)
+	if err != nil {
+		return fmt.Errorf("failed to write task to outbox: %w", err)
+	}
+
+	slog.InfoContext(ctx, "wrote a task to outbox",
+		slog.Int64("outbox_id", message.ID),
+		slog.String("queue", message.Queue),
+		slog.String("type", message.TaskType),
+	)
+	return nil
 }
@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	db "simplebank/db/sqlc"
+
+	"github.com/hibiken/asynq"
+)
+
+// taskCompletedStep 整个任务处理成功后在 task_executions 里记的步骤
+const taskCompletedStep = "completed"
+
+// runOnce 同一个任务里 step 只成功执行一次，任务重试时直接返回第一次执行的结果
+// 发邮件这类外部副作用和记账不在一个事务里，fn 成功后没记下来时重试还会再执行，
+// 所以写库的步骤最好本身也能重复执行，比如先查有没有已经建好的记录
+func runOnce[R any](ctx context.Context, q db.Querier, step string, fn func() (R, error)) (R, error) {
+	var result R
+
+	taskID, ok := getTaskID(ctx)
+	if !ok {
+		// 不是在 processor 里执行的，没有任务 ID 可以去重
+		return fn()
+	}
+
+	execution, err := q.GetTaskExecution(ctx, db.GetTaskExecutionParams{
+		TaskID: taskID,
+		Step:   step,
+	})
+	if err == nil {
+		if err := json.Unmarshal(execution.Result, &result); err != nil {
+			return result, fmt.Errorf("failed to unmarshal result of step %s: %w", step, err)
+		}
+		return result, nil
+	}
+	if err != sql.ErrNoRows {
+		return result, fmt.Errorf("failed to get task execution: %w", err)
+	}
+
+	result, err = fn()
+	if err != nil {
+		return result, err
+	}
+
+	// 步骤已经成功，记不下来只是重试时会再执行一次，不让后面的步骤跟着失败
+	if err := recordTaskExecution(ctx, q, taskID, step, result); err != nil {
+		slog.ErrorContext(ctx, "failed to record task step",
+			slog.String("task_id", taskID),
+			slog.String("step", step),
+			slog.String("error", err.Error()),
+		)
+	}
+	return result, nil
+}
+
+func recordTaskExecution(ctx context.Context, q db.Querier, taskID string, step string, result any) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return q.CreateTaskExecution(context.WithoutCancel(ctx), db.CreateTaskExecutionParams{
+		TaskID: taskID,
+		Step:   step,
+		Result: data,
+	})
+}
+
+// skipCompletedTasks 处理成功的任务按任务 ID 记下来，同一个任务再送过来时直接跳过
+// outbox 至少投递一次，asynq 只在保留期内按任务 ID 去重，过了保留期重复投递的任务在这里挡住
+func (processor *QueueTaskProcessor) skipCompletedTasks(next asynq.Handler) asynq.Handler {
+	return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		taskID, ok := getTaskID(ctx)
+		if !ok {
+			return next.ProcessTask(ctx, task)
+		}
+
+		_, err := processor.store.GetTaskExecution(ctx, db.GetTaskExecutionParams{
+			TaskID: taskID,
+			Step:   taskCompletedStep,
+		})
+		if err == nil {
+			slog.InfoContext(ctx, "skip completed task",
+				slog.String("task_id", taskID),
+				slog.String("type", task.Type()),
+			)
+			return nil
+		}
+		if err != sql.ErrNoRows {
+			return fmt.Errorf("failed to get task execution: %w", err)
+		}
+
+		if err := next.ProcessTask(ctx, task); err != nil {
+			return err
+		}
+
+		if err := recordTaskExecution(ctx, processor.store, taskID, taskCompletedStep, nil); err != nil {
+			slog.ErrorContext(ctx, "failed to record task completion",
+				slog.String("task_id", taskID),
+				slog.String("error", err.Error()),
+			)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,155 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	mockdb "simplebank/db/mock"
+	db "simplebank/db/sqlc"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type fakeMailer struct {
+	sent []string
+	err  error
+}
+
+func (mailer *fakeMailer) SendEmail(subject string, content string, to []string, cc []string, bcc []string, attachFiles []string) error {
+	if mailer.err != nil {
+		return mailer.err
+	}
+	mailer.sent = append(mailer.sent, subject)
+	return nil
+}
+
+func taskContext(taskID string) context.Context {
+	return withTaskMetadata(context.Background(), &queuedTask{id: taskID, maxRetry: defaultMaxRetry})
+}
+
+func TestRunOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	ctx := taskContext("task-1")
+	step := db.GetTaskExecutionParams{TaskID: "task-1", Step: "charge"}
+
+	calls := 0
+	charge := func() (int64, error) {
+		calls++
+		return 42, nil
+	}
+
+	// 第一次执行后记下结果
+	store.EXPECT().GetTaskExecution(gomock.Any(), step).Return(db.TaskExecution{}, sql.ErrNoRows)
+	store.EXPECT().
+		CreateTaskExecution(gomock.Any(), db.CreateTaskExecutionParams{TaskID: "task-1", Step: "charge", Result: json.RawMessage("42")}).
+		Return(nil)
+
+	result, err := runOnce(ctx, store, "charge", charge)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), result)
+	require.Equal(t, 1, calls)
+
+	// 重试时直接用记下的结果
+	store.EXPECT().GetTaskExecution(gomock.Any(), step).Return(db.TaskExecution{TaskID: "task-1", Step: "charge", Result: json.RawMessage("42")}, nil)
+
+	result, err = runOnce(ctx, store, "charge", charge)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), result)
+	require.Equal(t, 1, calls)
+
+	// 失败的步骤不记，下次还会执行
+	store.EXPECT().GetTaskExecution(gomock.Any(), step).Return(db.TaskExecution{}, sql.ErrNoRows)
+	store.EXPECT().CreateTaskExecution(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err = runOnce(ctx, store, "charge", func() (int64, error) {
+		return 0, errors.New("gateway timeout")
+	})
+	require.EqualError(t, err, "gateway timeout")
+
+	// 没有任务 ID 时不去重
+	result, err = runOnce(context.Background(), store, "charge", charge)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), result)
+	require.Equal(t, 2, calls)
+}
+
+func TestSkipCompletedTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	processor := &QueueTaskProcessor{store: store}
+
+	calls := 0
+	handler := processor.skipCompletedTasks(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		calls++
+		return nil
+	}))
+	task := asynq.NewTask("task:test", nil)
+	completed := db.GetTaskExecutionParams{TaskID: "outbox:7", Step: taskCompletedStep}
+
+	store.EXPECT().GetTaskExecution(gomock.Any(), completed).Return(db.TaskExecution{}, sql.ErrNoRows)
+	store.EXPECT().
+		CreateTaskExecution(gomock.Any(), db.CreateTaskExecutionParams{TaskID: "outbox:7", Step: taskCompletedStep, Result: json.RawMessage("null")}).
+		Return(nil)
+
+	err := handler.ProcessTask(taskContext("outbox:7"), task)
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+
+	// 同一个 outbox 消息又投递了一次
+	store.EXPECT().GetTaskExecution(gomock.Any(), completed).Return(db.TaskExecution{TaskID: "outbox:7", Step: taskCompletedStep}, nil)
+
+	err = handler.ProcessTask(taskContext("outbox:7"), task)
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+
+	// 查不了账本时不处理，等重试
+	store.EXPECT().GetTaskExecution(gomock.Any(), gomock.Any()).Return(db.TaskExecution{}, sql.ErrConnDone)
+
+	err = handler.ProcessTask(taskContext("outbox:8"), task)
+	require.ErrorIs(t, err, sql.ErrConnDone)
+	require.Equal(t, 1, calls)
+}
+
+func TestProcessTaskSendVerifyEmailRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	mailer := &fakeMailer{err: errors.New("smtp: connection refused")}
+	processor := &QueueTaskProcessor{store: store, mailer: mailer}
+	ctx := taskContext("outbox:9")
+
+	user := db.User{Username: "alice", Email: "alice@example.com", FullName: "Alice"}
+	verifyEmail := db.VerifyEmail{ID: 3, Username: user.Username, Email: user.Email, SecretCode: "secret"}
+	sendStep := db.GetTaskExecutionParams{TaskID: "outbox:9", Step: "send_email"}
+
+	store.EXPECT().GetUser(gomock.Any(), user.Username).Times(3).Return(user, nil)
+	// 第一次建验证，之后的重试复用同一个
+	gomock.InOrder(
+		store.EXPECT().GetActiveVerifyEmail(gomock.Any(), gomock.Any()).Return(db.VerifyEmail{}, sql.ErrNoRows),
+		store.EXPECT().GetActiveVerifyEmail(gomock.Any(), gomock.Any()).Times(2).Return(verifyEmail, nil),
+	)
+	store.EXPECT().CreateVerifyEmail(gomock.Any(), gomock.Any()).Times(1).Return(verifyEmail, nil)
+	gomock.InOrder(
+		store.EXPECT().GetTaskExecution(gomock.Any(), sendStep).Times(2).Return(db.TaskExecution{}, sql.ErrNoRows),
+		store.EXPECT().GetTaskExecution(gomock.Any(), sendStep).Return(db.TaskExecution{TaskID: "outbox:9", Step: "send_email", Result: json.RawMessage("{}")}, nil),
+	)
+	store.EXPECT().CreateTaskExecution(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+
+	payload := &PayloadSendVerifyEmail{Username: user.Username}
+	err := processor.ProcessTaskSendVerifyEmail(ctx, payload)
+	require.Error(t, err)
+
+	mailer.err = nil
+	err = processor.ProcessTaskSendVerifyEmail(ctx, payload)
+	require.NoError(t, err)
+	require.Len(t, mailer.sent, 1)
+
+	// 发信成功但任务没确认就被重新处理，不会再发一封
+	err = processor.ProcessTaskSendVerifyEmail(ctx, payload)
+	require.NoError(t, err)
+	require.Len(t, mailer.sent, 1)
+}
@@ -0,0 +1,165 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/hibiken/asynq"
+)
+
+// ErrTaskNotFailed 只有等待重试和已归档的任务可以手动处理或删除
+var ErrTaskNotFailed = errors.New("task is neither retrying nor archived")
+
+// inspectorScanSize Redis 里一次取多少个任务做过滤
+const inspectorScanSize = 100
+
+// FailedTaskFilter 列失败任务的条件，Queue 和 Type 为空时不过滤
+type FailedTaskFilter struct {
+	// asynq.TaskStateRetry 或 asynq.TaskStateArchived
+	State    asynq.TaskState
+	Queue    string
+	Type     string
+	PageID   int
+	PageSize int
+}
+
+func (filter FailedTaskFilter) validate() error {
+	if filter.State != asynq.TaskStateRetry && filter.State != asynq.TaskStateArchived {
+		return fmt.Errorf("unsupported task state %s", filter.State)
+	}
+	if filter.PageID < 1 || filter.PageSize < 1 {
+		return errors.New("page id and page size must be positive")
+	}
+	return nil
+}
+
+// TaskInspector 运维查看和处理失败的任务，queue 为空时在 processor 处理的所有队列里找
+// 找不到任务时返回 asynq.ErrTaskNotFound
+type TaskInspector interface {
+	ListFailedTasks(ctx context.Context, filter FailedTaskFilter) ([]*asynq.TaskInfo, error)
+	GetTaskInfo(ctx context.Context, queue string, id string) (*asynq.TaskInfo, error)
+	// RunTask 失败的任务马上再处理一次
+	RunTask(ctx context.Context, queue string, id string) error
+	DeleteTask(ctx context.Context, queue string, id string) error
+	Close() error
+}
+
+// processorQueues processor 处理的队列，按名字排序
+func processorQueues() []string {
+	queues := make([]string, 0, len(taskQueues))
+	for queue := range taskQueues {
+		queues = append(queues, queue)
+	}
+	slices.Sort(queues)
+	return queues
+}
+
+func isFailedTask(info *asynq.TaskInfo) bool {
+	return info.State == asynq.TaskStateRetry || info.State == asynq.TaskStateArchived
+}
+
+// redisInspector asynq 的 Inspector 只能按队列列任务，按类型过滤和分页在这里做
+type redisInspector struct {
+	inspector *asynq.Inspector
+}
+
+func (inspector *redisInspector) ListFailedTasks(ctx context.Context, filter FailedTaskFilter) ([]*asynq.TaskInfo, error) {
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+
+	queues := processorQueues()
+	if filter.Queue != "" {
+		queues = []string{filter.Queue}
+	}
+
+	list := inspector.inspector.ListRetryTasks
+	if filter.State == asynq.TaskStateArchived {
+		list = inspector.inspector.ListArchivedTasks
+	}
+
+	offset := (filter.PageID - 1) * filter.PageSize
+	var tasks []*asynq.TaskInfo
+	for _, queue := range queues {
+		for page := 1; ; page++ {
+			infos, err := list(queue, asynq.Page(page), asynq.PageSize(inspectorScanSize))
+			if errors.Is(err, asynq.ErrQueueNotFound) {
+				// 还没有任务进过这个队列
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to list %s tasks in queue %s: %w", filter.State, queue, err)
+			}
+
+			for _, info := range infos {
+				if filter.Type != "" && info.Type != filter.Type {
+					continue
+				}
+				if offset > 0 {
+					offset--
+					continue
+				}
+				tasks = append(tasks, info)
+				if len(tasks) == filter.PageSize {
+					return tasks, nil
+				}
+			}
+			if len(infos) < inspectorScanSize {
+				break
+			}
+		}
+	}
+	return tasks, nil
+}
+
+func (inspector *redisInspector) GetTaskInfo(ctx context.Context, queue string, id string) (*asynq.TaskInfo, error) {
+	queues := processorQueues()
+	if queue != "" {
+		queues = []string{queue}
+	}
+
+	for _, queue := range queues {
+		info, err := inspector.inspector.GetTaskInfo(queue, id)
+		if errors.Is(err, asynq.ErrQueueNotFound) || errors.Is(err, asynq.ErrTaskNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return info, nil
+	}
+	return nil, asynq.ErrTaskNotFound
+}
+
+func (inspector *redisInspector) RunTask(ctx context.Context, queue string, id string) error {
+	info, err := inspector.failedTask(ctx, queue, id)
+	if err != nil {
+		return err
+	}
+	return inspector.inspector.RunTask(info.Queue, info.ID)
+}
+
+func (inspector *redisInspector) DeleteTask(ctx context.Context, queue string, id string) error {
+	info, err := inspector.failedTask(ctx, queue, id)
+	if err != nil {
+		return err
+	}
+	return inspector.inspector.DeleteTask(info.Queue, info.ID)
+}
+
+func (inspector *redisInspector) failedTask(ctx context.Context, queue string, id string) (*asynq.TaskInfo, error) {
+	info, err := inspector.GetTaskInfo(ctx, queue, id)
+	if err != nil {
+		return nil, err
+	}
+	if !isFailedTask(info) {
+		return nil, ErrTaskNotFailed
+	}
+	return info, nil
+}
+
+func (inspector *redisInspector) Close() error {
+	return inspector.inspector.Close()
+}
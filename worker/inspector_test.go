@@ -0,0 +1,151 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	mockdb "simplebank/db/mock"
+	db "simplebank/db/sqlc"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// failTask 让内存队列里的任务失败一次
+func failTask(t *testing.T, server *pollServer, err error) {
+	handler := &recordingHandler{
+		handleFn: func(task *asynq.Task) error {
+			return err
+		},
+	}
+	processed, processErr := server.processNext(context.Background(), handler)
+	require.NoError(t, processErr)
+	require.True(t, processed)
+}
+
+func TestMemoryQueueInspector(t *testing.T) {
+	queue := NewMemoryQueue()
+	server := newTestPollServer(queue, map[string]int{QueueCritical: 1, QueueDefault: 1})
+	inspector := queue.Inspector()
+	ctx := context.Background()
+
+	require.NoError(t, queue.DistributeTask(ctx, "task:email", []byte(`{"username":"alice"}`), asynq.TaskID("archived"), asynq.Queue(QueueCritical), asynq.MaxRetry(0)))
+	failTask(t, server, errors.New("smtp: connection refused"))
+	require.NoError(t, queue.DistributeTask(ctx, "task:webhook", nil, asynq.TaskID("retry"), asynq.MaxRetry(3)))
+	failTask(t, server, errors.New("503 service unavailable"))
+	require.NoError(t, queue.DistributeTask(ctx, "task:email", nil, asynq.TaskID("pending")))
+
+	archived, err := inspector.ListFailedTasks(ctx, FailedTaskFilter{State: asynq.TaskStateArchived, PageID: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	require.Equal(t, "archived", archived[0].ID)
+	require.Equal(t, QueueCritical, archived[0].Queue)
+	require.Equal(t, "smtp: connection refused", archived[0].LastErr)
+	require.JSONEq(t, `{"username":"alice"}`, string(archived[0].Payload))
+	require.False(t, archived[0].LastFailedAt.IsZero())
+
+	retrying, err := inspector.ListFailedTasks(ctx, FailedTaskFilter{State: asynq.TaskStateRetry, Type: "task:webhook", PageID: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, retrying, 1)
+	require.Equal(t, "retry", retrying[0].ID)
+	require.Equal(t, 1, retrying[0].Retried)
+
+	retrying, err = inspector.ListFailedTasks(ctx, FailedTaskFilter{State: asynq.TaskStateRetry, Queue: QueueCritical, PageID: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Empty(t, retrying)
+
+	_, err = inspector.ListFailedTasks(ctx, FailedTaskFilter{State: asynq.TaskStatePending, PageID: 1, PageSize: 10})
+	require.Error(t, err)
+
+	// 还没失败的任务能查，但不能手动处理或删除
+	info, err := inspector.GetTaskInfo(ctx, "", "pending")
+	require.NoError(t, err)
+	require.Equal(t, asynq.TaskStatePending, info.State)
+	require.ErrorIs(t, inspector.RunTask(ctx, "", "pending"), ErrTaskNotFailed)
+	require.ErrorIs(t, inspector.DeleteTask(ctx, "", "pending"), ErrTaskNotFailed)
+
+	_, err = inspector.GetTaskInfo(ctx, QueueDefault, "archived")
+	require.ErrorIs(t, err, asynq.ErrTaskNotFound)
+	require.ErrorIs(t, inspector.RunTask(ctx, "", "unknown"), asynq.ErrTaskNotFound)
+
+	// 归档的任务手动处理后回到队列里
+	require.NoError(t, inspector.RunTask(ctx, QueueCritical, "archived"))
+	info, err = inspector.GetTaskInfo(ctx, "", "archived")
+	require.NoError(t, err)
+	require.Equal(t, asynq.TaskStatePending, info.State)
+
+	require.NoError(t, inspector.DeleteTask(ctx, "", "retry"))
+	_, err = inspector.GetTaskInfo(ctx, "", "retry")
+	require.ErrorIs(t, err, asynq.ErrTaskNotFound)
+}
+
+func TestMemoryQueueInspectorPagination(t *testing.T) {
+	queue := NewMemoryQueue()
+	server := newTestPollServer(queue, nil)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, queue.DistributeTask(ctx, "task:test", nil, asynq.MaxRetry(0)))
+		failTask(t, server, errors.New("failed"))
+	}
+
+	var ids []string
+	for page := 1; page <= 3; page++ {
+		tasks, err := queue.ListFailedTasks(ctx, FailedTaskFilter{State: asynq.TaskStateArchived, PageID: page, PageSize: 2})
+		require.NoError(t, err)
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+	}
+	require.Len(t, ids, 3)
+	require.NotEqual(t, ids[0], ids[1])
+	require.NotEqual(t, ids[1], ids[2])
+}
+
+func TestProcessorHandleError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	processor := &QueueTaskProcessor{store: store}
+
+	queue := NewMemoryQueue()
+	server := newPollServer(queue, ServerConfig{
+		ErrorHandler: asynq.ErrorHandlerFunc(processor.handleError),
+		RetryDelayFunc: func(n int, err error, task *asynq.Task) time.Duration {
+			return 0
+		},
+	}, memoryPollInterval)
+	ctx := context.Background()
+
+	require.NoError(t, queue.DistributeTask(ctx, "task:test", nil, asynq.TaskID("task-1"), asynq.MaxRetry(1)))
+
+	gomock.InOrder(
+		store.EXPECT().
+			CreateTaskFailure(gomock.Any(), db.CreateTaskFailureParams{
+				TaskID:   "task-1",
+				TaskType: "task:test",
+				Queue:    QueueDefault,
+				Error:    "temporary error",
+				Retried:  0,
+				MaxRetry: 1,
+				Archived: false,
+			}).
+			Times(1),
+		// 重试用完的失败记成归档
+		store.EXPECT().
+			CreateTaskFailure(gomock.Any(), db.CreateTaskFailureParams{
+				TaskID:   "task-1",
+				TaskType: "task:test",
+				Queue:    QueueDefault,
+				Error:    "temporary error",
+				Retried:  1,
+				MaxRetry: 1,
+				Archived: true,
+			}).
+			Times(1),
+	)
+
+	failTask(t, server, errors.New("temporary error"))
+	failTask(t, server, errors.New("temporary error"))
+}
@@ -0,0 +1,280 @@
+package worker
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+// memoryPollInterval 内存队列空闲时的轮询间隔，轮询不访问外部，可以很短
+const memoryPollInterval = 10 * time.Millisecond
+
+const (
+	memoryTaskPending  = "pending"
+	memoryTaskActive   = "active"
+	memoryTaskArchived = "archived"
+)
+
+type memoryTask struct {
+	queuedTask
+	state        string
+	processAt    time.Time
+	lastError    string
+	lastFailedAt time.Time
+}
+
+// MemoryQueue 进程内的任务队列，不需要 Redis，用于测试和单进程开发
+// 完成的任务直接删掉，任务 ID 只在任务还在队列里时去重
+type MemoryQueue struct {
+	mu    sync.Mutex
+	tasks map[string]*memoryTask
+}
+
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{
+		tasks: make(map[string]*memoryTask),
+	}
+}
+
+func (queue *MemoryQueue) Enqueuer() TaskEnqueuer {
+	return queue
+}
+
+func (queue *MemoryQueue) Server(config ServerConfig) TaskServer {
+	return newPollServer(queue, config, memoryPollInterval)
+}
+
+func (queue *MemoryQueue) Inspector() TaskInspector {
+	return queue
+}
+
+// DistributeTask 不经过 outbox 直接入队，测试里当 TaskDistributor 用
+func (queue *MemoryQueue) DistributeTask(ctx context.Context, taskType string, payload []byte, opts ...asynq.Option) error {
+	_, err := queue.EnqueueContext(ctx, asynq.NewTask(taskType, payload), opts...)
+	return err
+}
+
+func (queue *MemoryQueue) EnqueueContext(ctx context.Context, task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	options, err := parseTaskOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if options.taskID == "" {
+		options.taskID = uuid.NewString()
+	}
+	if options.processAt.IsZero() {
+		options.processAt = time.Now()
+	}
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	if _, ok := queue.tasks[options.taskID]; ok {
+		return nil, asynq.ErrTaskIDConflict
+	}
+
+	queue.tasks[options.taskID] = &memoryTask{
+		queuedTask: queuedTask{
+			id:       options.taskID,
+			taskType: task.Type(),
+			payload:  task.Payload(),
+			queue:    options.queue,
+			maxRetry: options.maxRetry,
+		},
+		state:     memoryTaskPending,
+		processAt: options.processAt,
+	}
+
+	return taskInfo(options, task), nil
+}
+
+func (queue *MemoryQueue) Close() error {
+	return nil
+}
+
+func (queue *MemoryQueue) claim(ctx context.Context, queues []string) (*queuedTask, error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	now := time.Now()
+	for _, name := range queues {
+		var next *memoryTask
+		for _, task := range queue.tasks {
+			if task.queue != name || task.state != memoryTaskPending || task.processAt.After(now) {
+				continue
+			}
+			if next == nil || task.processAt.Before(next.processAt) {
+				next = task
+			}
+		}
+		if next != nil {
+			next.state = memoryTaskActive
+			claimed := next.queuedTask
+			return &claimed, nil
+		}
+	}
+	return nil, nil
+}
+
+func (queue *MemoryQueue) complete(ctx context.Context, task *queuedTask) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	delete(queue.tasks, task.id)
+	return nil
+}
+
+func (queue *MemoryQueue) retry(ctx context.Context, task *queuedTask, processAt time.Time, err error) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	stored, ok := queue.tasks[task.id]
+	if !ok {
+		return asynq.ErrTaskNotFound
+	}
+	stored.state = memoryTaskPending
+	stored.retried++
+	stored.processAt = processAt
+	stored.lastError = err.Error()
+	stored.lastFailedAt = time.Now()
+	return nil
+}
+
+func (queue *MemoryQueue) archive(ctx context.Context, task *queuedTask, err error) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	stored, ok := queue.tasks[task.id]
+	if !ok {
+		return asynq.ErrTaskNotFound
+	}
+	stored.state = memoryTaskArchived
+	stored.lastError = err.Error()
+	stored.lastFailedAt = time.Now()
+	return nil
+}
+
+func (queue *MemoryQueue) ListFailedTasks(ctx context.Context, filter FailedTaskFilter) ([]*asynq.TaskInfo, error) {
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	var tasks []*asynq.TaskInfo
+	for _, task := range queue.tasks {
+		info := task.info()
+		if info.State != filter.State ||
+			(filter.Queue != "" && info.Queue != filter.Queue) ||
+			(filter.Type != "" && info.Type != filter.Type) {
+			continue
+		}
+		tasks = append(tasks, info)
+	}
+
+	// 和 Postgres 队列一样，最近失败的在前
+	slices.SortFunc(tasks, func(a, b *asynq.TaskInfo) int {
+		if c := b.LastFailedAt.Compare(a.LastFailedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	offset := min((filter.PageID-1)*filter.PageSize, len(tasks))
+	end := min(offset+filter.PageSize, len(tasks))
+	return tasks[offset:end], nil
+}
+
+func (queue *MemoryQueue) GetTaskInfo(ctx context.Context, name string, id string) (*asynq.TaskInfo, error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	task, err := queue.find(name, id)
+	if err != nil {
+		return nil, err
+	}
+	return task.info(), nil
+}
+
+func (queue *MemoryQueue) RunTask(ctx context.Context, name string, id string) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	task, err := queue.findFailed(name, id)
+	if err != nil {
+		return err
+	}
+	task.state = memoryTaskPending
+	task.processAt = time.Now()
+	return nil
+}
+
+func (queue *MemoryQueue) DeleteTask(ctx context.Context, name string, id string) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	task, err := queue.findFailed(name, id)
+	if err != nil {
+		return err
+	}
+	delete(queue.tasks, task.id)
+	return nil
+}
+
+func (queue *MemoryQueue) find(name string, id string) (*memoryTask, error) {
+	task, ok := queue.tasks[id]
+	if !ok || (name != "" && task.queue != name) {
+		return nil, asynq.ErrTaskNotFound
+	}
+	return task, nil
+}
+
+func (queue *MemoryQueue) findFailed(name string, id string) (*memoryTask, error) {
+	task, err := queue.find(name, id)
+	if err != nil {
+		return nil, err
+	}
+	if !isFailedTask(task.info()) {
+		return nil, ErrTaskNotFailed
+	}
+	return task, nil
+}
+
+func (task *memoryTask) info() *asynq.TaskInfo {
+	return &asynq.TaskInfo{
+		ID:            task.id,
+		Queue:         task.queue,
+		Type:          task.taskType,
+		Payload:       task.payload,
+		State:         queuedTaskState(task.state, task.retried, task.processAt),
+		MaxRetry:      task.maxRetry,
+		Retried:       task.retried,
+		LastErr:       task.lastError,
+		LastFailedAt:  task.lastFailedAt,
+		NextProcessAt: task.processAt,
+	}
+}
+
+// taskInfo 入队结果，字段和 asynq 返回的一致
+func taskInfo(options taskOptions, task *asynq.Task) *asynq.TaskInfo {
+	state := asynq.TaskStatePending
+	if options.processAt.After(time.Now()) {
+		state = asynq.TaskStateScheduled
+	}
+
+	return &asynq.TaskInfo{
+		ID:            options.taskID,
+		Queue:         options.queue,
+		Type:          task.Type(),
+		Payload:       task.Payload(),
+		State:         state,
+		MaxRetry:      options.maxRetry,
+		NextProcessAt: options.processAt,
+	}
+}
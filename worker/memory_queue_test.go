@@ -0,0 +1,222 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingHandler struct {
+	tasks    []*asynq.Task
+	retries  []int
+	taskIDs  []string
+	handleFn func(task *asynq.Task) error
+}
+
+func (handler *recordingHandler) ProcessTask(ctx context.Context, task *asynq.Task) error {
+	handler.tasks = append(handler.tasks, task)
+	retried, _, _ := getRetryCount(ctx)
+	handler.retries = append(handler.retries, retried)
+	taskID, _ := getTaskID(ctx)
+	handler.taskIDs = append(handler.taskIDs, taskID)
+	if handler.handleFn != nil {
+		return handler.handleFn(task)
+	}
+	return nil
+}
+
+func newTestPollServer(queue *MemoryQueue, queues map[string]int) *pollServer {
+	return newPollServer(queue, ServerConfig{
+		Queues: queues,
+		RetryDelayFunc: func(n int, err error, task *asynq.Task) time.Duration {
+			return 0
+		},
+	}, memoryPollInterval)
+}
+
+func TestMemoryQueueRetry(t *testing.T) {
+	queue := NewMemoryQueue()
+	server := newTestPollServer(queue, nil)
+	ctx := context.Background()
+
+	err := queue.DistributeTask(ctx, "task:test", []byte(`{}`), asynq.MaxRetry(1), asynq.TaskID("retry"))
+	require.NoError(t, err)
+
+	handler := &recordingHandler{
+		handleFn: func(task *asynq.Task) error {
+			return errors.New("temporary error")
+		},
+	}
+
+	// 第一次失败后重试一次，第二次失败后进 archived
+	for i := 0; i < 2; i++ {
+		processed, err := server.processNext(ctx, handler)
+		require.NoError(t, err)
+		require.True(t, processed)
+	}
+	require.Equal(t, []int{0, 1}, handler.retries)
+	require.Equal(t, []string{"retry", "retry"}, handler.taskIDs)
+
+	processed, err := server.processNext(ctx, handler)
+	require.NoError(t, err)
+	require.False(t, processed)
+
+	stored := queue.tasks["retry"]
+	require.Equal(t, memoryTaskArchived, stored.state)
+	require.Equal(t, "temporary error", stored.lastError)
+
+	// 还在队列里的任务 ID 不能重复投递
+	err = queue.DistributeTask(ctx, "task:test", []byte(`{}`), asynq.TaskID("retry"))
+	require.ErrorIs(t, err, asynq.ErrTaskIDConflict)
+}
+
+func TestMemoryQueueSkipRetry(t *testing.T) {
+	queue := NewMemoryQueue()
+	server := newTestPollServer(queue, nil)
+	ctx := context.Background()
+
+	err := queue.DistributeTask(ctx, "task:test", []byte(`{}`), asynq.MaxRetry(10), asynq.TaskID("skip"))
+	require.NoError(t, err)
+
+	handler := &recordingHandler{
+		handleFn: func(task *asynq.Task) error {
+			return errors.Join(errors.New("bad payload"), asynq.SkipRetry)
+		},
+	}
+
+	processed, err := server.processNext(ctx, handler)
+	require.NoError(t, err)
+	require.True(t, processed)
+	require.Equal(t, memoryTaskArchived, queue.tasks["skip"].state)
+
+	// panic 也按失败处理，不会让 worker 退出
+	err = queue.DistributeTask(ctx, "task:test", []byte(`{}`), asynq.TaskID("panic"))
+	require.NoError(t, err)
+
+	handler.handleFn = func(task *asynq.Task) error {
+		panic("boom")
+	}
+	processed, err = server.processNext(ctx, handler)
+	require.NoError(t, err)
+	require.True(t, processed)
+
+	stored := queue.tasks["panic"]
+	require.Equal(t, memoryTaskPending, stored.state)
+	require.Equal(t, 1, stored.retried)
+	require.Contains(t, stored.lastError, "boom")
+}
+
+func TestMemoryQueueProcessAt(t *testing.T) {
+	queue := NewMemoryQueue()
+	server := newTestPollServer(queue, nil)
+	ctx := context.Background()
+
+	info, err := queue.EnqueueContext(ctx, asynq.NewTask("task:test", nil), asynq.ProcessIn(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, asynq.TaskStateScheduled, info.State)
+	require.NotEmpty(t, info.ID)
+
+	handler := &recordingHandler{}
+	processed, err := server.processNext(ctx, handler)
+	require.NoError(t, err)
+	require.False(t, processed)
+
+	// 到期后才处理，处理完从队列里删掉
+	queue.tasks[info.ID].processAt = time.Now()
+	processed, err = server.processNext(ctx, handler)
+	require.NoError(t, err)
+	require.True(t, processed)
+	require.Len(t, handler.tasks, 1)
+	require.Empty(t, queue.tasks)
+}
+
+func TestMemoryQueuePriority(t *testing.T) {
+	queue := NewMemoryQueue()
+	ctx := context.Background()
+
+	err := queue.DistributeTask(ctx, "task:default", nil, asynq.Queue(QueueDefault))
+	require.NoError(t, err)
+	err = queue.DistributeTask(ctx, "task:critical", nil, asynq.Queue(QueueCritical))
+	require.NoError(t, err)
+
+	task, err := queue.claim(ctx, []string{QueueCritical, QueueDefault})
+	require.NoError(t, err)
+	require.Equal(t, "task:critical", task.taskType)
+
+	// 取出的任务处理完之前不会被再取一次
+	task, err = queue.claim(ctx, []string{QueueCritical, QueueDefault})
+	require.NoError(t, err)
+	require.Equal(t, "task:default", task.taskType)
+
+	task, err = queue.claim(ctx, []string{QueueCritical, QueueDefault})
+	require.NoError(t, err)
+	require.Nil(t, task)
+
+	// 不在配置里的队列不处理
+	err = queue.DistributeTask(ctx, "task:other", nil, asynq.Queue("other"))
+	require.NoError(t, err)
+	task, err = queue.claim(ctx, []string{QueueCritical, QueueDefault})
+	require.NoError(t, err)
+	require.Nil(t, task)
+}
+
+func TestMemoryQueueUnsupportedOption(t *testing.T) {
+	queue := NewMemoryQueue()
+
+	err := queue.DistributeTask(context.Background(), "task:test", nil, asynq.Unique(time.Minute))
+	require.Error(t, err)
+	require.Empty(t, queue.tasks)
+}
+
+func TestQueueOrder(t *testing.T) {
+	first := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		order := queueOrder(map[string]int{QueueCritical: 10, QueueDefault: 5})
+		require.ElementsMatch(t, []string{QueueCritical, QueueDefault}, order)
+		first[order[0]]++
+	}
+
+	// 权重 10:5，critical 排第一的概率约为 2/3
+	require.Greater(t, first[QueueCritical], first[QueueDefault])
+	require.Greater(t, first[QueueDefault], 0)
+}
+
+func TestPollServerRun(t *testing.T) {
+	queue := NewMemoryQueue()
+	server := newTestPollServer(queue, nil)
+
+	processed := make(chan string, 3)
+	handler := asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		processed <- string(task.Payload())
+		return nil
+	})
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- server.Run(handler)
+	}()
+
+	ctx := context.Background()
+	for _, payload := range []string{"1", "2", "3"} {
+		err := queue.DistributeTask(ctx, "task:test", []byte(payload))
+		require.NoError(t, err)
+	}
+
+	var payloads []string
+	for i := 0; i < 3; i++ {
+		select {
+		case payload := <-processed:
+			payloads = append(payloads, payload)
+		case <-time.After(5 * time.Second):
+			t.Fatal("task was not processed")
+		}
+	}
+	require.ElementsMatch(t, []string{"1", "2", "3"}, payloads)
+
+	server.Shutdown()
+	require.NoError(t, <-runErr)
+}
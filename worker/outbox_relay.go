@@ -0,0 +1,118 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	db "simplebank/db/sqlc"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	outboxBatchSize = 100
+	// 完成的任务在 asynq 里保留一段时间，同一条消息重复投递时按任务 ID 去重
+	outboxTaskRetention = time.Hour
+)
+
+// OutboxRelay 定时把 outbox 里待投递的任务发到任务队列，至少投递一次
+type OutboxRelay struct {
+	store    db.Store
+	client   TaskEnqueuer
+	interval time.Duration
+}
+
+func NewOutboxRelay(enqueuer TaskEnqueuer, store db.Store, interval time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		store:    store,
+		client:   enqueuer,
+		interval: interval,
+	}
+}
+
+// Start 阻塞到 ctx 结束，队列不可用时消息留在 outbox 里等下一轮
+func (relay *OutboxRelay) Start(ctx context.Context) error {
+	ticker := time.NewTicker(relay.interval)
+	defer ticker.Stop()
+
+	for {
+		// 一批投满说明还有积压，接着投不等下一轮
+		for {
+			result, err := relay.PublishPending(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to publish outbox", slog.String("error", err.Error()))
+				break
+			}
+			if result.Published < outboxBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (relay *OutboxRelay) Shutdown() {
+	relay.client.Close()
+}
+
+// PublishPending 投递一批待投递的消息
+func (relay *OutboxRelay) PublishPending(ctx context.Context) (db.PublishOutboxTxResult, error) {
+	return relay.store.PublishOutboxTx(ctx, db.PublishOutboxTxParams{
+		Limit: outboxBatchSize,
+		Publish: func(message db.Outbox) error {
+			return relay.publish(ctx, message)
+		},
+	})
+}
+
+func (relay *OutboxRelay) publish(ctx context.Context, message db.Outbox) error {
+	task := asynq.NewTask(message.TaskType, message.Payload)
+	info, err := relay.client.EnqueueContext(ctx, task, outboxTaskOptions(message)...)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		// 上次投递成功但没来得及标记
+		slog.InfoContext(ctx, "outbox task already enqueued", slog.Int64("outbox_id", message.ID))
+		return nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to enqueue task",
+			slog.Int64("outbox_id", message.ID),
+			slog.String("type", task.Type()),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	slog.InfoContext(ctx, "enqueued a task",
+		slog.String("task_id", info.ID),
+		slog.String("queue", info.Queue),
+		slog.String("type", task.Type()),
+		slog.String("payload", string(task.Payload())),
+	)
+	return nil
+}
+
+// outboxTaskOptions 任务 ID 由 outbox ID 决定，重复投递的同一条消息只会进队一次
+func outboxTaskOptions(message db.Outbox) []asynq.Option {
+	opts := []asynq.Option{
+		asynq.TaskID(OutboxTaskID(message.ID)),
+		asynq.Queue(message.Queue),
+		asynq.MaxRetry(int(message.MaxRetry)),
+		asynq.Retention(outboxTaskRetention),
+	}
+	if message.ProcessAt.Valid {
+		opts = append(opts, asynq.ProcessAt(message.ProcessAt.Time))
+	}
+	return opts
+}
+
+// OutboxTaskID 处理任务时可以用任务 ID 找回对应的 outbox 消息
+func OutboxTaskID(outboxID int64) string {
+	return fmt.Sprintf("outbox:%d", outboxID)
+}
@@ -0,0 +1,146 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	mockdb "simplebank/db/mock"
+	db "simplebank/db/sqlc"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOutboxTaskDistributor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	distributor := NewOutboxTaskDistributor(store)
+
+	store.EXPECT().
+		CreateOutboxMessage(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateOutboxMessageParams) (db.Outbox, error) {
+			require.Equal(t, TaskSendVerifyEmail.Type, arg.TaskType)
+			require.JSONEq(t, `{"username":"alice"}`, string(arg.Payload))
+			require.Equal(t, "critical", arg.Queue)
+			require.Equal(t, int32(10), arg.MaxRetry)
+			require.True(t, arg.ProcessAt.Valid)
+			require.WithinDuration(t, time.Now().Add(10*time.Second), arg.ProcessAt.Time, time.Second)
+			return db.Outbox{ID: 1, TaskType: arg.TaskType, Queue: arg.Queue}, nil
+		})
+
+	err := TaskSendVerifyEmail.Distribute(context.Background(), distributor, &PayloadSendVerifyEmail{Username: "alice"},
+		asynq.MaxRetry(10),
+		asynq.ProcessIn(10*time.Second),
+		asynq.Queue("critical"),
+	)
+	require.NoError(t, err)
+
+	// 不带选项时和 asynq 的默认值一致
+	store.EXPECT().
+		CreateOutboxMessage(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateOutboxMessageParams) (db.Outbox, error) {
+			require.Equal(t, defaultQueue, arg.Queue)
+			require.Equal(t, int32(defaultMaxRetry), arg.MaxRetry)
+			require.False(t, arg.ProcessAt.Valid)
+			return db.Outbox{ID: 2}, nil
+		})
+
+	err = TaskExpirePendingTransfers.Distribute(context.Background(), distributor, &PayloadExpirePendingTransfers{})
+	require.NoError(t, err)
+
+	// 任务 ID 由 relay 生成，调用方不能自己指定
+	err = TaskSendVerifyEmail.Distribute(context.Background(), distributor, &PayloadSendVerifyEmail{Username: "alice"},
+		asynq.TaskID("my-id"),
+	)
+	require.Error(t, err)
+}
+
+type fakeEnqueuer struct {
+	tasks []*asynq.Task
+	err   map[string]error
+}
+
+func (enqueuer *fakeEnqueuer) EnqueueContext(ctx context.Context, task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	if err := enqueuer.err[task.Type()]; err != nil {
+		return nil, err
+	}
+	enqueuer.tasks = append(enqueuer.tasks, task)
+	return &asynq.TaskInfo{Type: task.Type()}, nil
+}
+
+func (enqueuer *fakeEnqueuer) Close() error {
+	return nil
+}
+
+func TestOutboxRelayPublishPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	enqueuer := &fakeEnqueuer{
+		err: map[string]error{
+			TaskSendOverdraftNotice.Type:    errors.New("redis: connection refused"),
+			TaskSubmitExternalTransfer.Type: asynq.ErrTaskIDConflict,
+		},
+	}
+	relay := &OutboxRelay{store: store, client: enqueuer, interval: time.Second}
+
+	processAt := time.Now().Add(time.Minute)
+	messages := []db.Outbox{
+		{ID: 1, TaskType: TaskSendVerifyEmail.Type, Payload: json.RawMessage(`{"username":"alice"}`), Queue: "critical", MaxRetry: 10, ProcessAt: sql.NullTime{Time: processAt, Valid: true}},
+		{ID: 2, TaskType: TaskSendOverdraftNotice.Type, Payload: json.RawMessage(`{}`), Queue: "critical", MaxRetry: 10},
+		// 已经投递过，只是上次没来得及标记
+		{ID: 3, TaskType: TaskSubmitExternalTransfer.Type, Payload: json.RawMessage(`{}`), Queue: "critical", MaxRetry: 10},
+	}
+
+	store.EXPECT().
+		PublishOutboxTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.PublishOutboxTxParams) (db.PublishOutboxTxResult, error) {
+			require.Equal(t, int32(outboxBatchSize), arg.Limit)
+
+			var result db.PublishOutboxTxResult
+			for _, message := range messages {
+				if err := arg.Publish(message); err != nil {
+					require.Equal(t, int64(2), message.ID)
+					result.Failed++
+					continue
+				}
+				result.Published++
+			}
+			return result, nil
+		})
+
+	result, err := relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Published)
+	require.Equal(t, 1, result.Failed)
+
+	require.Len(t, enqueuer.tasks, 1)
+	task := enqueuer.tasks[0]
+	require.Equal(t, TaskSendVerifyEmail.Type, task.Type())
+	require.JSONEq(t, `{"username":"alice"}`, string(task.Payload()))
+}
+
+func TestOutboxTaskOptions(t *testing.T) {
+	processAt := time.Now().Add(time.Minute)
+	opts := outboxTaskOptions(db.Outbox{
+		ID:        42,
+		TaskType:  TaskSendVerifyEmail.Type,
+		Payload:   json.RawMessage(`{}`),
+		Queue:     "critical",
+		MaxRetry:  10,
+		ProcessAt: sql.NullTime{Time: processAt, Valid: true},
+	})
+
+	values := make(map[asynq.OptionType]any)
+	for _, opt := range opts {
+		values[opt.Type()] = opt.Value()
+	}
+	require.Equal(t, "outbox:42", values[asynq.TaskIDOpt])
+	require.Equal(t, "critical", values[asynq.QueueOpt])
+	require.Equal(t, 10, values[asynq.MaxRetryOpt])
+	require.Equal(t, processAt, values[asynq.ProcessAtOpt])
+	require.Equal(t, outboxTaskRetention, values[asynq.RetentionOpt])
+}
@@ -0,0 +1,143 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"simplebank/util"
+	"strings"
+	"time"
+)
+
+// scheduleSpecOff 在 SCHEDULER_SPECS 里关掉一个周期任务
+const scheduleSpecOff = "off"
+
+// PeriodicJob 一个周期任务，Spec 是 5 段 cron 表达式，按 UTC 计算
+type PeriodicJob struct {
+	Name string
+	Spec string
+	// Enqueue 投递 scheduledAt 这一次的任务，distributor 在记录这次触发的事务里
+	Enqueue func(ctx context.Context, distributor TaskDistributor, scheduledAt time.Time) error
+}
+
+// PeriodicJobs 所有周期任务和默认的触发时间，新的周期任务在这里注册
+func PeriodicJobs(config util.Config) ([]PeriodicJob, error) {
+	jobs := []PeriodicJob{
+		{
+			Name: "purge_expired_sessions",
+			Spec: "0 * * * *",
+			Enqueue: periodic(TaskPurgeExpiredSessions, func(time.Time) *PayloadPurgeExpiredSessions {
+				return &PayloadPurgeExpiredSessions{}
+			}),
+		},
+		{
+			Name: "purge_expired_verify_emails",
+			Spec: "5 * * * *",
+			Enqueue: periodic(TaskPurgeExpiredVerifyEmails, func(time.Time) *PayloadPurgeExpiredVerifyEmails {
+				return &PayloadPurgeExpiredVerifyEmails{}
+			}),
+		},
+		{
+			Name: "purge_task_history",
+			Spec: "30 3 * * *",
+			Enqueue: periodic(TaskPurgeTaskHistory, func(scheduledAt time.Time) *PayloadPurgeTaskHistory {
+				return &PayloadPurgeTaskHistory{Before: scheduledAt.Add(-config.TaskHistoryRetention)}
+			}),
+		},
+		{
+			Name: "expire_payment_requests",
+			Spec: "*/5 * * * *",
+			Enqueue: periodic(TaskExpirePaymentRequests, func(time.Time) *PayloadExpirePaymentRequests {
+				return &PayloadExpirePaymentRequests{}
+			}),
+		},
+		{
+			Name: "expire_pending_transfers",
+			Spec: "*/5 * * * *",
+			Enqueue: periodic(TaskExpirePendingTransfers, func(time.Time) *PayloadExpirePendingTransfers {
+				return &PayloadExpirePendingTransfers{}
+			}),
+		},
+		// 过了零点按前一天的日终余额计息和扣透支利息
+		{
+			Name: "accrue_interest",
+			Spec: "10 0 * * *",
+			Enqueue: periodic(TaskAccrueInterest, func(scheduledAt time.Time) *PayloadAccrueInterest {
+				return &PayloadAccrueInterest{Date: scheduledAt.AddDate(0, 0, -1).Format(time.DateOnly)}
+			}),
+		},
+		{
+			Name: "charge_overdraft_interest",
+			Spec: "20 0 * * *",
+			Enqueue: periodic(TaskChargeOverdraftInterest, func(scheduledAt time.Time) *PayloadChargeOverdraftInterest {
+				return &PayloadChargeOverdraftInterest{Date: scheduledAt.AddDate(0, 0, -1).Format(time.DateOnly)}
+			}),
+		},
+		// 每月 1 号，等上个月最后一天的利息记完再入账
+		{
+			Name: "post_interest",
+			Spec: "0 2 1 * *",
+			Enqueue: periodic(TaskPostInterest, func(scheduledAt time.Time) *PayloadPostInterest {
+				return &PayloadPostInterest{Period: scheduledAt.AddDate(0, -1, 0).Format("2006-01")}
+			}),
+		},
+		// 批量文件网络过了退回期限才算清算
+		{
+			Name: "settle_ach_payments",
+			Spec: "0 5 * * *",
+			Enqueue: periodic(TaskSettleACHPayments, func(scheduledAt time.Time) *PayloadSettleACHPayments {
+				return &PayloadSettleACHPayments{SubmittedBefore: scheduledAt.Add(-config.ACHReturnWindow)}
+			}),
+		},
+		{
+			Name: "reconcile_accounts",
+			Spec: "0 4 * * *",
+			Enqueue: periodic(TaskReconcileAccounts, func(time.Time) *PayloadReconcileAccounts {
+				return &PayloadReconcileAccounts{}
+			}),
+		},
+	}
+
+	return applyScheduleSpecs(jobs, config.SchedulerSpecs)
+}
+
+// periodic 按触发时间生成载荷并投递
+func periodic[P any](task *Task[P], payload func(scheduledAt time.Time) *P) func(ctx context.Context, distributor TaskDistributor, scheduledAt time.Time) error {
+	return func(ctx context.Context, distributor TaskDistributor, scheduledAt time.Time) error {
+		return task.Distribute(ctx, distributor, payload(scheduledAt))
+	}
+}
+
+// applyScheduleSpecs specs 的格式是 job=spec;job=spec，spec 为 off 时不调度这个任务
+func applyScheduleSpecs(jobs []PeriodicJob, specs string) ([]PeriodicJob, error) {
+	overrides := make(map[string]string)
+	for _, item := range strings.Split(specs, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, spec, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid schedule spec %q, want job=spec", item)
+		}
+		overrides[strings.TrimSpace(name)] = strings.TrimSpace(spec)
+	}
+
+	result := make([]PeriodicJob, 0, len(jobs))
+	for _, job := range jobs {
+		spec, ok := overrides[job.Name]
+		delete(overrides, job.Name)
+		if ok {
+			if spec == scheduleSpecOff {
+				continue
+			}
+			job.Spec = spec
+		}
+		result = append(result, job)
+	}
+
+	for name := range overrides {
+		return nil, fmt.Errorf("unknown periodic job %q", name)
+	}
+	return result, nil
+}
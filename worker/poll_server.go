@@ -0,0 +1,243 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	// 任务的处理时限，也是 Postgres 队列里任务租约的长度，和 asynq 的默认值一致
+	taskTimeout = 30 * time.Minute
+	// Shutdown 等正在处理的任务结束的时间，超时后取消它们的 ctx
+	shutdownTimeout = 8 * time.Second
+)
+
+// queuedTask 从队列里取出来的任务
+type queuedTask struct {
+	// Postgres 队列的行 ID
+	key      int64
+	id       string
+	taskType string
+	payload  []byte
+	queue    string
+	retried  int
+	maxRetry int
+}
+
+// queueStore 是 Postgres 和内存队列的存取，重试、延迟和优先级都由 pollServer 按 asynq 的语义实现
+type queueStore interface {
+	// claim 按 queues 的顺序取一个到期的任务，没有时返回 nil
+	claim(ctx context.Context, queues []string) (*queuedTask, error)
+	complete(ctx context.Context, task *queuedTask) error
+	retry(ctx context.Context, task *queuedTask, processAt time.Time, err error) error
+	archive(ctx context.Context, task *queuedTask, err error) error
+}
+
+// pollServer 用多个 goroutine 轮询 queueStore，实现 TaskServer
+type pollServer struct {
+	store        queueStore
+	config       ServerConfig
+	concurrency  int
+	pollInterval time.Duration
+	stop         chan struct{}
+	stopOnce     sync.Once
+	done         chan struct{}
+}
+
+func newPollServer(store queueStore, config ServerConfig, pollInterval time.Duration) *pollServer {
+	if config.RetryDelayFunc == nil {
+		config.RetryDelayFunc = asynq.DefaultRetryDelayFunc
+	}
+	if len(config.Queues) == 0 {
+		config.Queues = map[string]int{QueueDefault: 1}
+	}
+
+	return &pollServer{
+		store:        store,
+		config:       config,
+		concurrency:  runtime.NumCPU(),
+		pollInterval: pollInterval,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+func (server *pollServer) Run(handler asynq.Handler) error {
+	defer close(server.done)
+
+	// 处理中的任务不跟着 stop 取消，Shutdown 时给它们留时间处理完
+	handlerCtx, cancelHandlers := context.WithCancel(context.Background())
+	defer cancelHandlers()
+
+	var wg sync.WaitGroup
+	for i := 0; i < server.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server.work(handlerCtx, handler)
+		}()
+	}
+
+	<-server.stop
+
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(shutdownTimeout):
+		// 被取消的任务按失败处理，之后会重试
+		cancelHandlers()
+		<-finished
+	}
+	return nil
+}
+
+// Shutdown 不再取新任务，等正在处理的任务结束后返回
+func (server *pollServer) Shutdown() {
+	server.stopOnce.Do(func() {
+		close(server.stop)
+	})
+	<-server.done
+}
+
+func (server *pollServer) work(ctx context.Context, handler asynq.Handler) {
+	for {
+		select {
+		case <-server.stop:
+			return
+		default:
+		}
+
+		processed, err := server.processNext(ctx, handler)
+		if err != nil {
+			slog.Error("failed to process queued task", slog.String("error", err.Error()))
+		}
+		if processed && err == nil {
+			continue
+		}
+
+		select {
+		case <-server.stop:
+			return
+		case <-time.After(server.pollInterval):
+		}
+	}
+}
+
+// processNext 处理一个任务，队列为空时返回 false
+func (server *pollServer) processNext(ctx context.Context, handler asynq.Handler) (bool, error) {
+	queued, err := server.store.claim(ctx, queueOrder(server.config.Queues))
+	if err != nil {
+		return false, fmt.Errorf("failed to claim task: %w", err)
+	}
+	if queued == nil {
+		return false, nil
+	}
+
+	task := asynq.NewTask(queued.taskType, queued.payload)
+	taskCtx, cancel := context.WithTimeout(withTaskMetadata(ctx, queued), taskTimeout)
+	defer cancel()
+
+	err = callHandler(taskCtx, handler, task)
+	// 结果要记下来，不能用可能已经取消的 ctx
+	storeCtx := context.WithoutCancel(ctx)
+	if err == nil {
+		return true, server.store.complete(storeCtx, queued)
+	}
+
+	if server.config.ErrorHandler != nil {
+		server.config.ErrorHandler.HandleError(taskCtx, task, err)
+	}
+
+	if errors.Is(err, asynq.SkipRetry) || queued.retried >= queued.maxRetry {
+		return true, server.store.archive(storeCtx, queued, err)
+	}
+
+	delay := server.config.RetryDelayFunc(queued.retried, err, task)
+	return true, server.store.retry(storeCtx, queued, time.Now().Add(delay), err)
+}
+
+// callHandler 和 asynq 一样把 panic 当成处理失败
+func callHandler(ctx context.Context, handler asynq.Handler, task *asynq.Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler.ProcessTask(ctx, task)
+}
+
+// queueOrder 按权重随机排列队列，权重高的大概率排在前面，和 asynq 的非严格优先级一致
+func queueOrder(queues map[string]int) []string {
+	remaining := make(map[string]int, len(queues))
+	total := 0
+	for queue, weight := range queues {
+		if weight <= 0 {
+			weight = 1
+		}
+		remaining[queue] = weight
+		total += weight
+	}
+
+	order := make([]string, 0, len(queues))
+	for len(remaining) > 0 {
+		n := rand.IntN(total)
+		for queue, weight := range remaining {
+			if n < weight {
+				order = append(order, queue)
+				total -= weight
+				delete(remaining, queue)
+				break
+			}
+			n -= weight
+		}
+	}
+	return order
+}
+
+type taskMetadataKey struct{}
+
+// withTaskMetadata asynq 的 GetTaskID、GetQueueName、GetRetryCount 只认 asynq 自己的 ctx，其他后端用这里的
+func withTaskMetadata(ctx context.Context, task *queuedTask) context.Context {
+	return context.WithValue(ctx, taskMetadataKey{}, task)
+}
+
+func getTaskID(ctx context.Context) (string, bool) {
+	if task, ok := ctx.Value(taskMetadataKey{}).(*queuedTask); ok {
+		return task.id, true
+	}
+	return asynq.GetTaskID(ctx)
+}
+
+func getQueueName(ctx context.Context) (string, bool) {
+	if task, ok := ctx.Value(taskMetadataKey{}).(*queuedTask); ok {
+		return task.queue, true
+	}
+	return asynq.GetQueueName(ctx)
+}
+
+// getRetryCount 返回已经重试的次数和最多重试的次数
+func getRetryCount(ctx context.Context) (retried int, maxRetry int, ok bool) {
+	if task, ok := ctx.Value(taskMetadataKey{}).(*queuedTask); ok {
+		return task.retried, task.maxRetry, true
+	}
+
+	retried, ok = asynq.GetRetryCount(ctx)
+	if !ok {
+		return 0, 0, false
+	}
+	maxRetry, ok = asynq.GetMaxRetry(ctx)
+	return retried, maxRetry, ok
+}
@@ -0,0 +1,202 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	db "simplebank/db/sqlc"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+// postgresPollInterval 队列为空时的轮询间隔，有任务时连续取不等待
+const postgresPollInterval = time.Second
+
+// PostgresQueue 用 task_queue 表做任务队列，worker 用 SKIP LOCKED 取任务，不需要 Redis
+// 任务取出后带一个租约，worker 退出没有回写结果时租约到期后重新处理，算一次重试，次数用完后归档
+type PostgresQueue struct {
+	q db.Querier
+}
+
+func NewPostgresQueue(q db.Querier) *PostgresQueue {
+	return &PostgresQueue{q: q}
+}
+
+func (queue *PostgresQueue) Enqueuer() TaskEnqueuer {
+	return queue
+}
+
+func (queue *PostgresQueue) Server(config ServerConfig) TaskServer {
+	return newPollServer(queue, config, postgresPollInterval)
+}
+
+func (queue *PostgresQueue) Inspector() TaskInspector {
+	return queue
+}
+
+// DistributeTask 直接写队列表，用事务里的 Querier 创建时和业务数据一起提交
+func (queue *PostgresQueue) DistributeTask(ctx context.Context, taskType string, payload []byte, opts ...asynq.Option) error {
+	_, err := queue.EnqueueContext(ctx, asynq.NewTask(taskType, payload), opts...)
+	return err
+}
+
+func (queue *PostgresQueue) EnqueueContext(ctx context.Context, task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	options, err := parseTaskOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if options.taskID == "" {
+		options.taskID = uuid.NewString()
+	}
+	if options.processAt.IsZero() {
+		options.processAt = time.Now()
+	}
+
+	_, err = queue.q.EnqueueTask(ctx, db.EnqueueTaskParams{
+		TaskID:    options.taskID,
+		TaskType:  task.Type(),
+		Payload:   task.Payload(),
+		Queue:     options.queue,
+		MaxRetry:  int32(options.maxRetry),
+		ProcessAt: options.processAt,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, asynq.ErrTaskIDConflict
+		}
+		return nil, fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	return taskInfo(options, task), nil
+}
+
+func (queue *PostgresQueue) Close() error {
+	return nil
+}
+
+func (queue *PostgresQueue) claim(ctx context.Context, queues []string) (*queuedTask, error) {
+	row, err := queue.q.ClaimTask(ctx, db.ClaimTaskParams{
+		LeaseSeconds: int32(taskTimeout / time.Second),
+		Queues:       queues,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	task := &queuedTask{
+		key:      row.ID,
+		id:       row.TaskID,
+		taskType: row.TaskType,
+		payload:  row.Payload,
+		queue:    row.Queue,
+		retried:  int(row.Retried),
+		maxRetry: int(row.MaxRetry),
+	}
+	return task, nil
+}
+
+func (queue *PostgresQueue) complete(ctx context.Context, task *queuedTask) error {
+	return queue.q.CompleteTask(ctx, task.key)
+}
+
+func (queue *PostgresQueue) retry(ctx context.Context, task *queuedTask, processAt time.Time, err error) error {
+	return queue.q.RetryTask(ctx, db.RetryTaskParams{
+		ID:        task.key,
+		ProcessAt: processAt,
+		LastError: err.Error(),
+	})
+}
+
+func (queue *PostgresQueue) archive(ctx context.Context, task *queuedTask, err error) error {
+	return queue.q.ArchiveTask(ctx, db.ArchiveTaskParams{
+		ID:        task.key,
+		LastError: err.Error(),
+	})
+}
+
+func (queue *PostgresQueue) ListFailedTasks(ctx context.Context, filter FailedTaskFilter) ([]*asynq.TaskInfo, error) {
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+
+	rows, err := queue.q.ListFailedTasks(ctx, db.ListFailedTasksParams{
+		State:       filter.State.String(),
+		Queue:       sql.NullString{String: filter.Queue, Valid: filter.Queue != ""},
+		TaskType:    sql.NullString{String: filter.Type, Valid: filter.Type != ""},
+		LimitCount:  int32(filter.PageSize),
+		OffsetCount: int32((filter.PageID - 1) * filter.PageSize),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*asynq.TaskInfo, 0, len(rows))
+	for _, row := range rows {
+		tasks = append(tasks, postgresTaskInfo(row))
+	}
+	return tasks, nil
+}
+
+func (queue *PostgresQueue) GetTaskInfo(ctx context.Context, name string, id string) (*asynq.TaskInfo, error) {
+	row, err := queue.q.GetQueuedTask(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, asynq.ErrTaskNotFound
+		}
+		return nil, err
+	}
+	if name != "" && row.Queue != name {
+		return nil, asynq.ErrTaskNotFound
+	}
+	return postgresTaskInfo(row), nil
+}
+
+func (queue *PostgresQueue) RunTask(ctx context.Context, name string, id string) error {
+	if _, err := queue.GetTaskInfo(ctx, name, id); err != nil {
+		return err
+	}
+
+	_, err := queue.q.RunFailedTask(ctx, id)
+	if err == sql.ErrNoRows {
+		return ErrTaskNotFailed
+	}
+	return err
+}
+
+func (queue *PostgresQueue) DeleteTask(ctx context.Context, name string, id string) error {
+	if _, err := queue.GetTaskInfo(ctx, name, id); err != nil {
+		return err
+	}
+
+	_, err := queue.q.DeleteFailedTask(ctx, id)
+	if err == sql.ErrNoRows {
+		return ErrTaskNotFailed
+	}
+	return err
+}
+
+func postgresTaskInfo(row db.TaskQueue) *asynq.TaskInfo {
+	info := &asynq.TaskInfo{
+		ID:            row.TaskID,
+		Queue:         row.Queue,
+		Type:          row.TaskType,
+		Payload:       row.Payload,
+		State:         queuedTaskState(row.State, int(row.Retried), row.ProcessAt),
+		MaxRetry:      int(row.MaxRetry),
+		Retried:       int(row.Retried),
+		LastErr:       row.LastError,
+		NextProcessAt: row.ProcessAt,
+	}
+	if row.LastFailedAt.Valid {
+		info.LastFailedAt = row.LastFailedAt.Time
+	}
+	if row.CompletedAt.Valid {
+		info.CompletedAt = row.CompletedAt.Time
+	}
+	return info
+}
@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	db "simplebank/db/sqlc"
+	"simplebank/util"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// ProcessTaskAccrueInterest 给所有计息账户记一天的利息
+// (account_id, accrual_date) 唯一，重跑时已经记过的账户直接跳过
+func (processor *QueueTaskProcessor) ProcessTaskAccrueInterest(ctx context.Context, payload *PayloadAccrueInterest) error {
+	day, err := time.Parse(time.DateOnly, payload.Date)
+	if err != nil {
+		return fmt.Errorf("invalid accrual date %q: %w", payload.Date, asynq.SkipRetry)
+	}
+	endOfDay := day.AddDate(0, 0, 1)
+
+	accounts, err := processor.store.ListInterestBearingAccounts(ctx, endOfDay)
+	if err != nil {
+		return fmt.Errorf("failed to list interest bearing accounts: %w", err)
+	}
+
+	accrued := 0
+	for _, account := range accounts {
+		micros, err := util.DailyInterestMicros(
+			account.EndOfDayBalance,
+			account.AnnualInterestRateBps,
+			account.DayCountConvention,
+			day,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to compute interest for account [%d]: %w", account.ID, err)
+		}
+
+		_, err = processor.store.CreateInterestAccrual(ctx, db.CreateInterestAccrualParams{
+			AccountID:          account.ID,
+			AccrualDate:        day,
+			Balance:            account.EndOfDayBalance,
+			AnnualRateBps:      account.AnnualInterestRateBps,
+			DayCountConvention: account.DayCountConvention,
+			AmountMicros:       micros,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			return fmt.Errorf("failed to create interest accrual for account [%d]: %w", account.ID, err)
+		}
+		accrued++
+	}
+
+	slog.Info("accrued interest",
+		slog.String("date", payload.Date),
+		slog.Int("accounts", len(accounts)),
+		slog.Int("accrued", accrued),
+	)
+	return nil
+}
@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	db "simplebank/db/sqlc"
+	"simplebank/settlement"
+	"simplebank/util"
+
+	"github.com/hibiken/asynq"
+)
+
+// ProcessTaskApplySettlementOutcome 按清算结果推进转账状态
+// 已经是目标状态的直接跳过，重复投递不会重复退款；顺序颠倒时状态机拒绝，任务稍后重试
+func (processor *QueueTaskProcessor) ProcessTaskApplySettlementOutcome(ctx context.Context, payload *PayloadApplySettlementOutcome) error {
+	var status string
+	switch payload.Outcome {
+	case settlement.OutcomeSettled:
+		status = util.TransferStatusSettled
+	case settlement.OutcomeFailed:
+		status = util.TransferStatusFailed
+	case settlement.OutcomeReturned:
+		status = util.TransferStatusReturned
+	default:
+		return fmt.Errorf("unknown settlement outcome %q: %w", payload.Outcome, asynq.SkipRetry)
+	}
+
+	transfer, err := processor.store.GetTransfer(ctx, payload.TransferID)
+	if err != nil {
+		return fmt.Errorf("failed to get transfer: %w", err)
+	}
+	if transfer.Status == status {
+		return nil
+	}
+
+	if status == util.TransferStatusSettled {
+		_, err = processor.store.SettleExternalTransferTx(ctx, payload.TransferID)
+	} else {
+		_, err = processor.store.ReverseExternalTransferTx(ctx, db.ReverseExternalTransferTxParams{
+			TransferID: payload.TransferID,
+			Status:     status,
+			Reason:     payload.Reason,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply %s outcome: %w", payload.Outcome, err)
+	}
+
+	slog.Info("applied settlement outcome",
+		slog.Int64("transfer_id", payload.TransferID),
+		slog.String("outcome", payload.Outcome),
+	)
+	return nil
+}
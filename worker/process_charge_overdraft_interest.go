@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	db "simplebank/db/sqlc"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// ProcessTaskChargeOverdraftInterest 对所有日终透支的账户扣息
+// (account_id, charge_date) 唯一，重跑不会重复扣
+func (processor *QueueTaskProcessor) ProcessTaskChargeOverdraftInterest(ctx context.Context, payload *PayloadChargeOverdraftInterest) error {
+	day, err := time.Parse(time.DateOnly, payload.Date)
+	if err != nil {
+		return fmt.Errorf("invalid charge date %q: %w", payload.Date, asynq.SkipRetry)
+	}
+
+	accounts, err := processor.store.ListOverdrawnAccounts(ctx, day.AddDate(0, 0, 1))
+	if err != nil {
+		return fmt.Errorf("failed to list overdrawn accounts: %w", err)
+	}
+
+	charged := 0
+	for _, account := range accounts {
+		result, err := processor.store.ChargeOverdraftInterestTx(ctx, db.ChargeOverdraftInterestTxParams{
+			AccountID:          account.ID,
+			ChargeDate:         day,
+			Balance:            account.EndOfDayBalance,
+			AnnualRateBps:      account.OverdraftInterestRateBps,
+			DayCountConvention: account.DayCountConvention,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to charge overdraft interest for account [%d]: %w", account.ID, err)
+		}
+		if result.AlreadyCharged {
+			continue
+		}
+		charged++
+
+		if result.LimitReached {
+			// 通知失败不影响扣息，扣息已经提交
+			if err := processor.sendOverdraftNotice(ctx, account.ID, OverdraftEventLimitReached); err != nil {
+				slog.Error("failed to notify overdraft limit reached",
+					slog.Int64("account_id", account.ID),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}
+
+	slog.Info("charged overdraft interest",
+		slog.String("date", payload.Date),
+		slog.Int("accounts", len(accounts)),
+		slog.Int("charged", charged),
+	)
+	return nil
+}
@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	db "simplebank/db/sqlc"
+	"simplebank/webhook"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// ProcessTaskDeliverWebhook 投递一次并记下结果，失败时交给 asynq 按 webhook.RetryDelay 重试
+func (processor *QueueTaskProcessor) ProcessTaskDeliverWebhook(ctx context.Context, payload *PayloadDeliverWebhook) error {
+	delivery, err := processor.store.GetWebhookDelivery(ctx, payload.DeliveryID)
+	if err != nil {
+		// 订阅删除时投递记录一起删掉
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("webhook delivery doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	if delivery.Status != webhook.DeliveryStatusPending {
+		slog.Info("webhook delivery already finished",
+			slog.Int64("delivery_id", delivery.ID),
+			slog.String("status", delivery.Status),
+		)
+		return nil
+	}
+
+	subscription, err := processor.store.GetWebhookSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("webhook subscription doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+
+	responseStatus, deliverErr := processor.webhooks.Deliver(ctx, webhook.Delivery{
+		URL:       subscription.Url,
+		Secret:    subscription.Secret,
+		EventID:   delivery.EventID.String(),
+		EventType: delivery.EventType,
+		Body:      delivery.Payload,
+	})
+
+	arg := db.RecordWebhookDeliveryAttemptParams{
+		ID:             delivery.ID,
+		Status:         webhook.DeliveryStatusSucceeded,
+		ResponseStatus: int32(responseStatus),
+	}
+	if deliverErr != nil {
+		arg.Status = webhook.DeliveryStatusPending
+		// 订阅者能看到这个字段，完整的错误只记日志
+		arg.LastError = webhook.FailureMessage(responseStatus)
+		// 最后一次重试也失败了，等人工重投
+		retried, maxRetry, ok := getRetryCount(ctx)
+		if ok && retried >= maxRetry {
+			arg.Status = webhook.DeliveryStatusFailed
+		}
+	} else {
+		arg.DeliveredAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+
+	_, err = processor.store.RecordWebhookDeliveryAttempt(ctx, arg)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+
+	logger := slog.With(
+		slog.Int64("delivery_id", delivery.ID),
+		slog.String("event_type", delivery.EventType),
+		slog.Int("response_status", responseStatus),
+	)
+	if deliverErr != nil {
+		logger.Error("failed to deliver webhook", slog.String("error", deliverErr.Error()))
+		return fmt.Errorf("failed to deliver webhook: %w", deliverErr)
+	}
+
+	logger.Info("delivered webhook")
+	return nil
+}
@@ -0,0 +1,124 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	mockdb "simplebank/db/mock"
+	db "simplebank/db/sqlc"
+	"simplebank/webhook"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProcessTaskDeliverWebhook(t *testing.T) {
+	secret, err := webhook.NewSecret()
+	require.NoError(t, err)
+
+	event, err := webhook.NewEvent(webhook.EventAccountCredited, webhook.AccountCreditedData{AccountID: 7, TransferID: 9, Amount: 100, Currency: "USD"})
+	require.NoError(t, err)
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	// 接收方第一次返回 503，第二次才收下
+	var calls int
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		payload, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, webhook.Verify(secret, r.Header.Get(webhook.SignatureHeader), payload, webhook.DefaultTolerance, time.Now()))
+
+		var received webhook.Event
+		require.NoError(t, json.Unmarshal(payload, &received))
+		require.Equal(t, event.ID, received.ID)
+		require.Equal(t, event.ID.String(), r.Header.Get(webhook.EventIDHeader))
+
+		if calls == 1 {
+			http.Error(w, "upstream db-1.internal down", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	processor := &QueueTaskProcessor{
+		store:    store,
+		webhooks: webhook.NewClient(time.Second, webhook.AllowPrivateNetworks()),
+	}
+
+	subscription := db.WebhookSubscription{ID: 3, Owner: "alice", Url: receiver.URL, Secret: secret}
+	delivery := db.WebhookDelivery{
+		ID:             5,
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Payload:        body,
+		Status:         webhook.DeliveryStatusPending,
+	}
+
+	store.EXPECT().GetWebhookDelivery(gomock.Any(), delivery.ID).Times(2).Return(delivery, nil)
+	store.EXPECT().GetWebhookSubscription(gomock.Any(), subscription.ID).Times(2).Return(subscription, nil)
+	gomock.InOrder(
+		store.EXPECT().
+			RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.RecordWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+				require.Equal(t, webhook.DeliveryStatusPending, arg.Status)
+				require.Equal(t, int32(http.StatusServiceUnavailable), arg.ResponseStatus)
+				// 响应体不能写进订阅者看得到的字段
+				require.Equal(t, "receiver responded with 503", arg.LastError)
+				require.False(t, arg.DeliveredAt.Valid)
+				return delivery, nil
+			}),
+		store.EXPECT().
+			RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.RecordWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+				require.Equal(t, webhook.DeliveryStatusSucceeded, arg.Status)
+				require.Equal(t, int32(http.StatusOK), arg.ResponseStatus)
+				require.Empty(t, arg.LastError)
+				require.True(t, arg.DeliveredAt.Valid)
+				return delivery, nil
+			}),
+	)
+
+	payload, err := json.Marshal(PayloadDeliverWebhook{DeliveryID: delivery.ID})
+	require.NoError(t, err)
+	task := asynq.NewTask(TaskDeliverWebhook.Type, payload)
+	handler := TaskDeliverWebhook.Handle(processor.ProcessTaskDeliverWebhook)
+
+	// 失败时返回 error，由 asynq 重试
+	err = handler.ProcessTask(context.Background(), task)
+	require.Error(t, err)
+
+	err = handler.ProcessTask(context.Background(), task)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+}
+
+func TestProcessTaskDeliverWebhookFinished(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	processor := &QueueTaskProcessor{store: store}
+
+	// 重复的投递任务不会再发一次
+	store.EXPECT().GetWebhookDelivery(gomock.Any(), int64(5)).Return(db.WebhookDelivery{
+		ID:      5,
+		EventID: uuid.New(),
+		Status:  webhook.DeliveryStatusSucceeded,
+	}, nil)
+
+	payload, err := json.Marshal(PayloadDeliverWebhook{DeliveryID: 5})
+	require.NoError(t, err)
+
+	handler := TaskDeliverWebhook.Handle(processor.ProcessTaskDeliverWebhook)
+	err = handler.ProcessTask(context.Background(), asynq.NewTask(TaskDeliverWebhook.Type, payload))
+	require.NoError(t, err)
+}
@@ -0,0 +1,28 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// ProcessTaskExpirePaymentRequests 一条 UPDATE 过期所有到期的请求，再逐个通知发起人
+func (processor *QueueTaskProcessor) ProcessTaskExpirePaymentRequests(ctx context.Context, _ *PayloadExpirePaymentRequests) error {
+	requests, err := processor.store.ExpirePaymentRequests(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to expire payment requests: %w", err)
+	}
+
+	for _, request := range requests {
+		// 状态已经提交，通知失败只记日志，不能让整个任务重试
+		if err := processor.sendPaymentRequestNotice(ctx, request, PaymentRequestEventExpired); err != nil {
+			slog.Error("failed to notify payment request expired",
+				slog.Int64("payment_request_id", request.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	slog.Info("expired payment requests", slog.Int("count", len(requests)))
+	return nil
+}
@@ -0,0 +1,33 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	db "simplebank/db/sqlc"
+)
+
+// ProcessTaskExpirePendingTransfers 每笔到期的转账单独一个事务释放资金
+// 期间被批准或拒绝的转账会返回 ErrTransferNotPending，直接跳过
+func (processor *QueueTaskProcessor) ProcessTaskExpirePendingTransfers(ctx context.Context, _ *PayloadExpirePendingTransfers) error {
+	transferIDs, err := processor.store.ListExpiredTransferApprovals(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list expired transfer approvals: %w", err)
+	}
+
+	expired := 0
+	for _, transferID := range transferIDs {
+		_, err := processor.store.ExpireTransferTx(ctx, transferID)
+		if err != nil {
+			if errors.Is(err, db.ErrTransferNotPending) {
+				continue
+			}
+			return fmt.Errorf("failed to expire transfer [%d]: %w", transferID, err)
+		}
+		expired++
+	}
+
+	slog.Info("expired pending transfers", slog.Int("count", expired))
+	return nil
+}
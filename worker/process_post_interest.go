@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	db "simplebank/db/sqlc"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// ProcessTaskPostInterest 月度入账，每个账户一个独立事务
+// 中途失败重试时，已经入账的账户会被 interest_postings 的唯一索引挡住
+func (processor *QueueTaskProcessor) ProcessTaskPostInterest(ctx context.Context, payload *PayloadPostInterest) error {
+	period, err := time.Parse("2006-01", payload.Period)
+	if err != nil {
+		return fmt.Errorf("invalid posting period %q: %w", payload.Period, asynq.SkipRetry)
+	}
+
+	accountIDs, err := processor.store.ListAccountsWithUnpostedInterest(ctx, period.AddDate(0, 1, 0))
+	if err != nil {
+		return fmt.Errorf("failed to list accounts with unposted interest: %w", err)
+	}
+
+	posted := 0
+	for _, accountID := range accountIDs {
+		result, err := processor.store.PostInterestTx(ctx, db.PostInterestTxParams{
+			AccountID: accountID,
+			Period:    period,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to post interest for account [%d]: %w", accountID, err)
+		}
+		if !result.AlreadyPosted && result.Posting.Amount > 0 {
+			posted++
+		}
+	}
+
+	slog.Info("posted interest",
+		slog.String("period", payload.Period),
+		slog.Int("accounts", len(accountIDs)),
+		slog.Int("posted", posted),
+	)
+	return nil
+}
@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	db "simplebank/db/sqlc"
+
+	"github.com/hibiken/asynq"
+)
+
+// ProcessTaskPublishWebhookEvent 给订阅了这个事件的每个 URL 建一条投递记录
+// 重试时投递记录按 (订阅, 事件 ID) 去重，投递任务重复时由投递记录的状态挡住
+func (processor *QueueTaskProcessor) ProcessTaskPublishWebhookEvent(ctx context.Context, payload *PayloadPublishWebhookEvent) error {
+	subscriptions, err := processor.store.ListWebhookSubscriptionsForEvent(ctx, db.ListWebhookSubscriptionsForEventParams{
+		Owner:     payload.Username,
+		EventType: payload.Event.Type,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(payload.Event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", asynq.SkipRetry)
+	}
+
+	for _, subscription := range subscriptions {
+		delivery, err := processor.store.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+			SubscriptionID: subscription.ID,
+			EventID:        payload.Event.ID,
+			EventType:      payload.Event.Type,
+			Payload:        body,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create webhook delivery: %w", err)
+		}
+
+		err = TaskDeliverWebhook.Distribute(ctx, processor.distributor, &PayloadDeliverWebhook{
+			DeliveryID: delivery.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to distribute webhook delivery: %w", err)
+		}
+	}
+
+	slog.Info("published webhook event",
+		slog.String("event_id", payload.Event.ID.String()),
+		slog.String("type", payload.Event.Type),
+		slog.Int("subscriptions", len(subscriptions)),
+	)
+	return nil
+}
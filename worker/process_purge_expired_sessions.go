@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ProcessTaskPurgeExpiredSessions 过期的 refresh token 已经不能换 access token，会话留着没用
+func (processor *QueueTaskProcessor) ProcessTaskPurgeExpiredSessions(ctx context.Context, _ *PayloadPurgeExpiredSessions) error {
+	deleted, err := processor.store.DeleteExpiredSessions(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+
+	slog.Info("purged expired sessions", slog.Int64("count", deleted))
+	return nil
+}
@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ProcessTaskPurgeExpiredVerifyEmails 过期的验证链接已经不能用，用户要重新发一封
+func (processor *QueueTaskProcessor) ProcessTaskPurgeExpiredVerifyEmails(ctx context.Context, _ *PayloadPurgeExpiredVerifyEmails) error {
+	deleted, err := processor.store.DeleteExpiredVerifyEmails(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to delete expired verify emails: %w", err)
+	}
+
+	slog.Info("purged expired verify emails", slog.Int64("count", deleted))
+	return nil
+}
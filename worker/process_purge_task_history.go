@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ProcessTaskPurgeTaskHistory 每张表单独删，重试时已经删过的表再删一遍也没关系
+// 归档的失败任务还在队列里等运维处理，只删它们过期的失败记录
+func (processor *QueueTaskProcessor) ProcessTaskPurgeTaskHistory(ctx context.Context, payload *PayloadPurgeTaskHistory) error {
+	purges := []struct {
+		table  string
+		delete func(ctx context.Context, before time.Time) (int64, error)
+	}{
+		{"outbox", processor.store.DeletePublishedOutboxMessages},
+		{"task_queue", processor.store.DeleteCompletedTasks},
+		{"task_executions", processor.store.DeleteTaskExecutions},
+		{"task_failures", processor.store.DeleteTaskFailures},
+		{"periodic_job_runs", processor.store.DeletePeriodicJobRuns},
+	}
+
+	for _, purge := range purges {
+		deleted, err := purge.delete(ctx, payload.Before)
+		if err != nil {
+			return fmt.Errorf("failed to purge %s: %w", purge.table, err)
+		}
+		slog.Info("purged task history",
+			slog.String("table", purge.table),
+			slog.Int64("count", deleted),
+		)
+	}
+	return nil
+}
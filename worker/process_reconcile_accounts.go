@@ -0,0 +1,26 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// ProcessTaskReconcileAccounts 余额和分录对不上时只报错不修，需要人工查原因
+func (processor *QueueTaskProcessor) ProcessTaskReconcileAccounts(ctx context.Context, _ *PayloadReconcileAccounts) error {
+	accounts, err := processor.store.ListUnbalancedAccounts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list unbalanced accounts: %w", err)
+	}
+
+	for _, account := range accounts {
+		slog.Error("account balance does not match entries",
+			slog.Int64("account_id", account.ID),
+			slog.Int64("balance", account.Balance),
+			slog.Int64("entry_total", account.EntryTotal),
+		)
+	}
+
+	slog.Info("reconciled accounts", slog.Int("unbalanced", len(accounts)))
+	return nil
+}
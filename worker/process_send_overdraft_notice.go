@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/hibiken/asynq"
+)
+
+func (processor *QueueTaskProcessor) ProcessTaskSendOverdraftNotice(ctx context.Context, payload *PayloadSendOverdraftNotice) error {
+	return processor.sendOverdraftNotice(ctx, payload.AccountID, payload.Event)
+}
+
+func (processor *QueueTaskProcessor) sendOverdraftNotice(ctx context.Context, accountID int64, event string) error {
+	account, err := processor.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("account doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+
+	user, err := processor.store.GetUser(ctx, account.Owner)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var subject, content string
+	switch event {
+	case OverdraftEventOverdrawn:
+		subject = "Your account is overdrawn"
+		content = fmt.Sprintf(`Hello %s,<br/>
+    Your %s account #%d is now overdrawn. Current balance: %d.<br/>
+    Overdrawn balances are charged interest daily.<br/>`,
+			user.FullName, account.Currency, account.ID, account.Balance)
+	case OverdraftEventLimitReached:
+		subject = "Your overdraft limit has been reached"
+		content = fmt.Sprintf(`Hello %s,<br/>
+    Your %s account #%d has reached its overdraft limit of %d. Current balance: %d.<br/>
+    Further outgoing transfers will be declined until you pay in funds.<br/>`,
+			user.FullName, account.Currency, account.ID, account.OverdraftLimit, account.Balance)
+	default:
+		return fmt.Errorf("unknown overdraft event %q: %w", event, asynq.SkipRetry)
+	}
+
+	err = processor.mailer.SendEmail(subject, content, []string{user.Email}, nil, nil, nil)
+	logger := slog.With(
+		slog.Int64("account_id", account.ID),
+		slog.String("event", event),
+		slog.String("email", user.Email),
+	)
+	if err != nil {
+		logger.Error("failed to send overdraft notice", slog.String("error", err.Error()))
+		return fmt.Errorf("failed to send overdraft notice: %w", err)
+	}
+
+	logger.Info("success to send overdraft notice")
+	return nil
+}
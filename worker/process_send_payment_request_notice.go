@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	db "simplebank/db/sqlc"
+
+	"github.com/hibiken/asynq"
+)
+
+func (processor *QueueTaskProcessor) ProcessTaskSendPaymentRequestNotice(ctx context.Context, payload *PayloadSendPaymentRequestNotice) error {
+	request, err := processor.store.GetPaymentRequest(ctx, payload.PaymentRequestID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("payment request doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get payment request: %w", err)
+	}
+
+	return processor.sendPaymentRequestNotice(ctx, request, payload.Event)
+}
+
+func (processor *QueueTaskProcessor) sendPaymentRequestNotice(ctx context.Context, request db.PaymentRequest, event string) error {
+	recipient := request.Requester
+	if event == PaymentRequestEventCreated {
+		recipient = request.Payer
+	}
+
+	user, err := processor.store.GetUser(ctx, recipient)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var subject, content string
+	switch event {
+	case PaymentRequestEventCreated:
+		subject = "You have a new payment request"
+		content = fmt.Sprintf(`Hello %s,<br/>
+    %s requested %d %s from you: %s<br/>
+    The request expires at %s.<br/>`,
+			user.FullName, request.Requester, request.Amount, request.Currency, request.Memo,
+			request.ExpiresAt.UTC().Format("2006-01-02 15:04 MST"))
+	case PaymentRequestEventPaid:
+		subject = "Your payment request has been paid"
+		content = fmt.Sprintf(`Hello %s,<br/>
+    %s paid your request #%d for %d %s.<br/>`,
+			user.FullName, request.Payer, request.ID, request.Amount, request.Currency)
+	case PaymentRequestEventDeclined:
+		subject = "Your payment request has been declined"
+		content = fmt.Sprintf(`Hello %s,<br/>
+    %s declined your request #%d for %d %s.<br/>`,
+			user.FullName, request.Payer, request.ID, request.Amount, request.Currency)
+	case PaymentRequestEventExpired:
+		subject = "Your payment request has expired"
+		content = fmt.Sprintf(`Hello %s,<br/>
+    Your request #%d to %s for %d %s expired without being paid.<br/>`,
+			user.FullName, request.ID, request.Payer, request.Amount, request.Currency)
+	default:
+		return fmt.Errorf("unknown payment request event %q: %w", event, asynq.SkipRetry)
+	}
+
+	err = processor.mailer.SendEmail(subject, content, []string{user.Email}, nil, nil, nil)
+	logger := slog.With(
+		slog.Int64("payment_request_id", request.ID),
+		slog.String("event", event),
+		slog.String("email", user.Email),
+	)
+	if err != nil {
+		logger.Error("failed to send payment request notice", slog.String("error", err.Error()))
+		return fmt.Errorf("failed to send payment request notice: %w", err)
+	}
+
+	logger.Info("success to send payment request notice")
+	return nil
+}
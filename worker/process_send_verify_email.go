@@ -3,7 +3,6 @@ package worker
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	db "simplebank/db/sqlc"
@@ -12,19 +11,7 @@ import (
 	"github.com/hibiken/asynq"
 )
 
-func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error {
-	var payload PayloadSendVerifyEmail
-	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
-		slog.ErrorContext(
-			ctx,
-			"failed to unmarshal verify email task payload",
-			"error", err, // 错误详情
-			"task_type", task.Type(),
-			"task_id", task.ResultWriter().TaskID(),
-		)
-		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
-	}
-
+func (processor *QueueTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail) error {
 	user, err := processor.store.GetUser(ctx, payload.Username)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -33,13 +20,9 @@ func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Cont
 		return fmt.Errorf("failed to get user: %w", err)
 	}
 
-	verifyEmail, err := processor.store.CreateVerifyEmail(ctx, db.CreateVerifyEmailParams{
-		Username:   user.Username,
-		Email:      user.Email,
-		SecretCode: util.RandomString(32),
-	})
+	verifyEmail, err := processor.verifyEmail(ctx, user)
 	if err != nil {
-		return fmt.Errorf("failed to create verify email: %w", err)
+		return err
 	}
 
 	// verifyUrl := fmt.Sprintf("https://api.simplebank.website:4443/v1/verify_email?email_id=%d&secret_code=%s",
@@ -52,12 +35,15 @@ func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Cont
     Please <a href="%s">click here</a> to verify your email address.<br/>`,
 		user.FullName, verifyUrl)
 
-	err = processor.mailer.SendEmail("Verify your email", content, []string{user.Email}, nil, nil, nil)
 	logger := slog.With(
 		slog.String("username", user.Username),
 		slog.String("email", user.Email),
 	)
 
+	// 邮件发出去以后任务再重试不会重复发
+	_, err = runOnce(ctx, processor.store, "send_email", func() (struct{}, error) {
+		return struct{}{}, processor.mailer.SendEmail("Verify your email", content, []string{user.Email}, nil, nil, nil)
+	})
 	if err != nil {
 		logger.Error("failed to send verify email", slog.String("error", err.Error()))
 		return fmt.Errorf("failed to send verify email: %w", err)
@@ -66,3 +52,27 @@ func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Cont
 	logger.Info("success to send verify email")
 	return nil
 }
+
+// verifyEmail 复用还没用过的验证，重试和重发都是同一个链接，不会每次都建一条新的
+func (processor *QueueTaskProcessor) verifyEmail(ctx context.Context, user db.User) (db.VerifyEmail, error) {
+	verifyEmail, err := processor.store.GetActiveVerifyEmail(ctx, db.GetActiveVerifyEmailParams{
+		Username: user.Username,
+		Email:    user.Email,
+	})
+	if err == nil {
+		return verifyEmail, nil
+	}
+	if err != sql.ErrNoRows {
+		return verifyEmail, fmt.Errorf("failed to get verify email: %w", err)
+	}
+
+	verifyEmail, err = processor.store.CreateVerifyEmail(ctx, db.CreateVerifyEmailParams{
+		Username:   user.Username,
+		Email:      user.Email,
+		SecretCode: util.RandomString(32),
+	})
+	if err != nil {
+		return verifyEmail, fmt.Errorf("failed to create verify email: %w", err)
+	}
+	return verifyEmail, nil
+}
@@ -0,0 +1,37 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	db "simplebank/db/sqlc"
+	"simplebank/nacha"
+)
+
+// ProcessTaskSettleACHPayments 批量文件网络没有清算回执，过了退回期限就当作已清算
+// 每笔单独一个事务，期间被退回的付款会返回 ErrInvalidTransferTransition，直接跳过
+func (processor *QueueTaskProcessor) ProcessTaskSettleACHPayments(ctx context.Context, payload *PayloadSettleACHPayments) error {
+	transferIDs, err := processor.store.ListSettleableExternalPayments(ctx, db.ListSettleableExternalPaymentsParams{
+		Network:         nacha.NetworkName,
+		SubmittedBefore: payload.SubmittedBefore,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list settleable ACH payments: %w", err)
+	}
+
+	settled := 0
+	for _, transferID := range transferIDs {
+		_, err := processor.store.SettleExternalTransferTx(ctx, transferID)
+		if err != nil {
+			if errors.Is(err, db.ErrInvalidTransferTransition) {
+				continue
+			}
+			return fmt.Errorf("failed to settle transfer [%d]: %w", transferID, err)
+		}
+		settled++
+	}
+
+	slog.Info("settled ACH payments", slog.Int("count", settled))
+	return nil
+}
@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	mockdb "simplebank/db/mock"
+	db "simplebank/db/sqlc"
+	"simplebank/nacha"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProcessTaskSettleACHPayments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	processor := &QueueTaskProcessor{store: store}
+
+	payload := &PayloadSettleACHPayments{SubmittedBefore: time.Date(2026, 2, 27, 5, 0, 0, 0, time.UTC)}
+
+	store.EXPECT().
+		ListSettleableExternalPayments(gomock.Any(), db.ListSettleableExternalPaymentsParams{
+			Network:         nacha.NetworkName,
+			SubmittedBefore: payload.SubmittedBefore,
+		}).
+		Return([]int64{1, 2, 3}, nil)
+
+	// 2 在列出之后被退回，跳过继续清算后面的
+	store.EXPECT().SettleExternalTransferTx(gomock.Any(), int64(1)).Return(db.ExternalTransferTxResult{}, nil)
+	store.EXPECT().
+		SettleExternalTransferTx(gomock.Any(), int64(2)).
+		Return(db.ExternalTransferTxResult{}, fmt.Errorf("transfer [2]: %w", db.ErrInvalidTransferTransition))
+	store.EXPECT().SettleExternalTransferTx(gomock.Any(), int64(3)).Return(db.ExternalTransferTxResult{}, nil)
+
+	require.NoError(t, processor.ProcessTaskSettleACHPayments(context.Background(), payload))
+}
+
+func TestProcessTaskSettleACHPaymentsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	processor := &QueueTaskProcessor{store: store}
+
+	store.EXPECT().
+		ListSettleableExternalPayments(gomock.Any(), gomock.Any()).
+		Return([]int64{1, 2}, nil)
+	store.EXPECT().
+		SettleExternalTransferTx(gomock.Any(), int64(1)).
+		Return(db.ExternalTransferTxResult{}, fmt.Errorf("connection reset"))
+
+	// 其他错误让任务重试，已经清算的下次不会再列出来
+	err := processor.ProcessTaskSettleACHPayments(context.Background(), &PayloadSettleACHPayments{})
+	require.Error(t, err)
+}
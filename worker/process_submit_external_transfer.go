@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	db "simplebank/db/sqlc"
+	"simplebank/settlement"
+	"simplebank/util"
+)
+
+// ProcessTaskSubmitExternalTransfer 提交给清算网络后把转账推进到 pending
+// 提交成功但记录失败时任务会重试，网络需要按转账 ID 去重
+func (processor *QueueTaskProcessor) ProcessTaskSubmitExternalTransfer(ctx context.Context, payload *PayloadSubmitExternalTransfer) error {
+	transfer, err := processor.store.GetTransfer(ctx, payload.TransferID)
+	if err != nil {
+		return fmt.Errorf("failed to get transfer: %w", err)
+	}
+	if transfer.Status != util.TransferStatusInitiated {
+		slog.Info("external transfer already submitted",
+			slog.Int64("transfer_id", transfer.ID),
+			slog.String("status", transfer.Status),
+		)
+		return nil
+	}
+
+	payment, err := processor.store.GetExternalPayment(ctx, transfer.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get external payment: %w", err)
+	}
+
+	debtor, err := processor.store.GetAccount(ctx, transfer.FromAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get debtor account: %w", err)
+	}
+
+	user, err := processor.store.GetUser(ctx, debtor.Owner)
+	if err != nil {
+		return fmt.Errorf("failed to get debtor: %w", err)
+	}
+
+	reference, err := processor.network.Submit(ctx, settlement.Payment{
+		TransferID:            transfer.ID,
+		Amount:                transfer.Amount,
+		Currency:              debtor.Currency,
+		DebtorAccountNumber:   debtor.AccountNumber,
+		DebtorName:            user.FullName,
+		CreditorAccountNumber: payment.CreditorAccountNumber,
+		CreditorName:          payment.CreditorName,
+		Memo:                  transfer.Memo,
+	})
+	if errors.Is(err, settlement.ErrQueuedForBatch) {
+		slog.Info("external transfer queued for batch file",
+			slog.Int64("transfer_id", transfer.ID),
+			slog.String("network", processor.network.Name()),
+		)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to submit to %s: %w", processor.network.Name(), err)
+	}
+
+	_, err = processor.store.SubmitExternalTransferTx(ctx, db.SubmitExternalTransferTxParams{
+		TransferID:       transfer.ID,
+		NetworkReference: reference,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark external transfer submitted: %w", err)
+	}
+
+	slog.Info("submitted external transfer",
+		slog.Int64("transfer_id", transfer.ID),
+		slog.String("network", processor.network.Name()),
+		slog.String("reference", reference),
+	)
+	return nil
+}
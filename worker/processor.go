@@ -2,9 +2,13 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	db "simplebank/db/sqlc"
 	"simplebank/mail"
+	"simplebank/settlement"
+	"simplebank/webhook"
+	"time"
 
 	"github.com/hibiken/asynq"
 )
@@ -12,49 +16,133 @@ import (
 type TaskProcessor interface {
 	Start() error
 	Shutdown()
-	ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error
-}
-
-type RedisTaskProcessor struct {
-	server *asynq.Server
-	store  db.Store
-	mailer mail.EmailSender
-}
-
-func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, mailer mail.EmailSender) TaskProcessor {
-	server := asynq.NewServer(redisOpt,
-		asynq.Config{
-			Queues: map[string]int{
-				"critical": 10,
-				"default":  5,
-			},
-			ErrorHandler: asynq.ErrorHandlerFunc(func(ctx context.Context, task *asynq.Task, err error) {
-				slog.ErrorContext(
-					ctx,
-					"process task failed",
-					"type", task.Type(),
-					"payload", task.Payload(),
-				)
-			}),
-			Logger: NewLogger(),
-		})
-
-	return &RedisTaskProcessor{
-		server: server,
-		store:  store,
-		mailer: mailer,
+}
+
+// webhook 接收方的超时时间
+const webhookTimeout = 10 * time.Second
+
+// taskQueues processor 处理的队列和权重
+var taskQueues = map[string]int{
+	QueueCritical: 10,
+	QueueDefault:  5,
+}
+
+// QueueTaskProcessor 从 TaskBackend 取任务处理，和具体用哪种队列无关
+type QueueTaskProcessor struct {
+	server      TaskServer
+	store       db.Store
+	mailer      mail.EmailSender
+	network     settlement.SettlementNetwork
+	distributor TaskDistributor
+	webhooks    *webhook.Client
+	handlers    map[string]TaskHandler
+}
+
+func NewTaskProcessor(
+	backend TaskBackend,
+	store db.Store,
+	mailer mail.EmailSender,
+	network settlement.SettlementNetwork,
+	distributor TaskDistributor,
+) TaskProcessor {
+	processor := &QueueTaskProcessor{
+		store:       store,
+		mailer:      mailer,
+		network:     network,
+		distributor: distributor,
+		webhooks:    webhook.NewClient(webhookTimeout),
+		handlers:    make(map[string]TaskHandler),
+	}
+	for _, handler := range processor.taskHandlers() {
+		processor.handlers[handler.Type] = handler
 	}
+
+	processor.server = backend.Server(ServerConfig{
+		Queues:       taskQueues,
+		ErrorHandler: asynq.ErrorHandlerFunc(processor.handleError),
+		// 任务可以自己定义重试间隔，比如 webhook 投递按指数退避
+		RetryDelayFunc: func(n int, err error, task *asynq.Task) time.Duration {
+			if handler, ok := processor.handlers[task.Type()]; ok && handler.retryDelay != nil {
+				return handler.retryDelay(n)
+			}
+			return asynq.DefaultRetryDelayFunc(n, err, task)
+		},
+	})
+
+	return processor
 }
 
-func (processor *RedisTaskProcessor) Start() error {
+// handleError 每次处理失败都记到 task_failures，重试用完的任务留给运维在失败任务管理里处理
+func (processor *QueueTaskProcessor) handleError(ctx context.Context, task *asynq.Task, err error) {
+	taskID, _ := getTaskID(ctx)
+	queue, _ := getQueueName(ctx)
+	retried, maxRetry, _ := getRetryCount(ctx)
+	archived := errors.Is(err, asynq.SkipRetry) || retried >= maxRetry
+
+	slog.ErrorContext(
+		ctx,
+		"process task failed",
+		"task_id", taskID,
+		"type", task.Type(),
+		"payload", task.Payload(),
+		"retried", retried,
+		"max_retry", maxRetry,
+		"archived", archived,
+		"error", err,
+	)
+
+	// 任务超时时 ctx 已经取消，失败记录还要写
+	_, dbErr := processor.store.CreateTaskFailure(context.WithoutCancel(ctx), db.CreateTaskFailureParams{
+		TaskID:   taskID,
+		TaskType: task.Type(),
+		Queue:    queue,
+		Error:    err.Error(),
+		Retried:  int32(retried),
+		MaxRetry: int32(maxRetry),
+		Archived: archived,
+	})
+	if dbErr != nil {
+		slog.ErrorContext(ctx, "failed to record task failure",
+			"task_id", taskID,
+			"error", dbErr,
+		)
+	}
+}
+
+// taskHandlers 所有任务的处理函数，新任务在这里注册
+func (processor *QueueTaskProcessor) taskHandlers() []TaskHandler {
+	return []TaskHandler{
+		TaskSendVerifyEmail.Handle(processor.ProcessTaskSendVerifyEmail),
+		TaskAccrueInterest.Handle(processor.ProcessTaskAccrueInterest),
+		TaskPostInterest.Handle(processor.ProcessTaskPostInterest),
+		TaskChargeOverdraftInterest.Handle(processor.ProcessTaskChargeOverdraftInterest),
+		TaskSendOverdraftNotice.Handle(processor.ProcessTaskSendOverdraftNotice),
+		TaskSendPaymentRequestNotice.Handle(processor.ProcessTaskSendPaymentRequestNotice),
+		TaskExpirePaymentRequests.Handle(processor.ProcessTaskExpirePaymentRequests),
+		TaskExpirePendingTransfers.Handle(processor.ProcessTaskExpirePendingTransfers),
+		TaskSubmitExternalTransfer.Handle(processor.ProcessTaskSubmitExternalTransfer),
+		TaskApplySettlementOutcome.Handle(processor.ProcessTaskApplySettlementOutcome),
+		TaskSettleACHPayments.Handle(processor.ProcessTaskSettleACHPayments),
+		TaskPublishWebhookEvent.Handle(processor.ProcessTaskPublishWebhookEvent),
+		TaskDeliverWebhook.Handle(processor.ProcessTaskDeliverWebhook),
+		TaskPurgeExpiredSessions.Handle(processor.ProcessTaskPurgeExpiredSessions),
+		TaskPurgeExpiredVerifyEmails.Handle(processor.ProcessTaskPurgeExpiredVerifyEmails),
+		TaskPurgeTaskHistory.Handle(processor.ProcessTaskPurgeTaskHistory),
+		TaskReconcileAccounts.Handle(processor.ProcessTaskReconcileAccounts),
+	}
+}
+
+func (processor *QueueTaskProcessor) Start() error {
 	mux := asynq.NewServeMux()
+	mux.Use(processor.skipCompletedTasks)
 
-	// 注册：当看到 TaskSendVerifyEmail 任务时，执行对应的 Process 函数
-	mux.HandleFunc(TaskSendVerifyEmail, processor.ProcessTaskSendVerifyEmail)
+	for taskType, handler := range processor.handlers {
+		mux.Handle(taskType, handler)
+	}
 
 	return processor.server.Run(mux)
 }
 
-func (processor *RedisTaskProcessor) Shutdown() {
+func (processor *QueueTaskProcessor) Shutdown() {
 	processor.server.Shutdown()
 }
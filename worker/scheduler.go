@@ -0,0 +1,173 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	db "simplebank/db/sqlc"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	schedulerLeaseName = "periodic_scheduler"
+	// leader 退出后最多过这么久别的实例接手
+	schedulerLeaseTTL = 30 * time.Second
+	schedulerTick     = 5 * time.Second
+	// 刚当选时补上这段时间里错过的触发，已经投递过的由 periodic_job_runs 挡住
+	schedulerCatchUp = time.Hour
+)
+
+type scheduledJob struct {
+	PeriodicJob
+	schedule cron.Schedule
+	next     time.Time
+}
+
+// Scheduler 按 cron 表达式把周期任务写进 outbox，和任务队列用哪种后端无关
+// 每个实例都可以跑，只有拿到 scheduler_leases 租约的那个投递
+type Scheduler struct {
+	store  db.Store
+	jobs   []*scheduledJob
+	holder string
+	leader bool
+}
+
+func NewScheduler(store db.Store, jobs []PeriodicJob) (*Scheduler, error) {
+	scheduler := &Scheduler{
+		store:  store,
+		holder: schedulerHolder(),
+	}
+
+	for _, job := range jobs {
+		schedule, err := cron.ParseStandard(job.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron spec %q for periodic job %s: %w", job.Spec, job.Name, err)
+		}
+		scheduler.jobs = append(scheduler.jobs, &scheduledJob{
+			PeriodicJob: job,
+			schedule:    schedule,
+		})
+	}
+
+	return scheduler, nil
+}
+
+// schedulerHolder 租约持有者，同一台机器上的多个进程也要区分开
+func schedulerHolder() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s/%s", hostname, uuid.NewString())
+}
+
+// Start 阻塞到 ctx 结束，退出时交出租约让别的实例马上接手
+func (scheduler *Scheduler) Start(ctx context.Context) error {
+	ticker := time.NewTicker(schedulerTick)
+	defer ticker.Stop()
+
+	for {
+		if err := scheduler.tick(ctx, time.Now().UTC()); err != nil {
+			slog.ErrorContext(ctx, "failed to run periodic scheduler", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			scheduler.release()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick 续租约，是 leader 时投递到期的周期任务
+func (scheduler *Scheduler) tick(ctx context.Context, now time.Time) error {
+	_, err := scheduler.store.AcquireSchedulerLease(ctx, db.AcquireSchedulerLeaseParams{
+		Name:       schedulerLeaseName,
+		Holder:     scheduler.holder,
+		TtlSeconds: int32(schedulerLeaseTTL / time.Second),
+	})
+	if err != nil {
+		// 续不上租约时不能确定自己还是 leader
+		if scheduler.leader {
+			slog.InfoContext(ctx, "periodic scheduler lost leadership", slog.String("holder", scheduler.holder))
+		}
+		scheduler.leader = false
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return fmt.Errorf("failed to acquire scheduler lease: %w", err)
+	}
+
+	if !scheduler.leader {
+		scheduler.leader = true
+		slog.InfoContext(ctx, "periodic scheduler became leader", slog.String("holder", scheduler.holder))
+		for _, job := range scheduler.jobs {
+			job.next = job.schedule.Next(now.Add(-schedulerCatchUp))
+		}
+	}
+
+	for _, job := range scheduler.jobs {
+		// 错过的多次触发只补最近的一次
+		var due time.Time
+		for !job.next.After(now) {
+			due = job.next
+			job.next = job.schedule.Next(job.next)
+		}
+		if due.IsZero() {
+			continue
+		}
+
+		if err := scheduler.fire(ctx, job, due); err != nil {
+			// 下一轮再试这一次
+			job.next = due
+			slog.ErrorContext(ctx, "failed to enqueue periodic job",
+				slog.String("job", job.Name),
+				slog.Time("scheduled_at", due),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+	return nil
+}
+
+func (scheduler *Scheduler) fire(ctx context.Context, job *scheduledJob, scheduledAt time.Time) error {
+	result, err := scheduler.store.SchedulePeriodicJobTx(ctx, db.SchedulePeriodicJobTxParams{
+		Job:         job.Name,
+		ScheduledAt: scheduledAt,
+		Enqueue: func(q db.Querier) error {
+			return job.Enqueue(ctx, NewOutboxTaskDistributor(q), scheduledAt)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.Scheduled {
+		slog.InfoContext(ctx, "enqueued periodic job",
+			slog.String("job", job.Name),
+			slog.Time("scheduled_at", scheduledAt),
+		)
+	}
+	return nil
+}
+
+func (scheduler *Scheduler) release() {
+	if !scheduler.leader {
+		return
+	}
+	scheduler.leader = false
+
+	err := scheduler.store.ReleaseSchedulerLease(context.Background(), db.ReleaseSchedulerLeaseParams{
+		Name:   schedulerLeaseName,
+		Holder: scheduler.holder,
+	})
+	if err != nil {
+		slog.Error("failed to release scheduler lease", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,224 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	mockdb "simplebank/db/mock"
+	db "simplebank/db/sqlc"
+	"simplebank/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// recordingJob 记下每次投递的触发时间
+func recordingJob(name, spec string, fired *[]time.Time) PeriodicJob {
+	return PeriodicJob{
+		Name: name,
+		Spec: spec,
+		Enqueue: func(ctx context.Context, distributor TaskDistributor, scheduledAt time.Time) error {
+			*fired = append(*fired, scheduledAt)
+			return nil
+		},
+	}
+}
+
+// runScheduledJobs 模拟 SchedulePeriodicJobTx，同一次触发只投递一次
+func runScheduledJobs(store *mockdb.MockStore) {
+	runs := make(map[string]bool)
+	store.EXPECT().
+		SchedulePeriodicJobTx(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, arg db.SchedulePeriodicJobTxParams) (db.SchedulePeriodicJobTxResult, error) {
+			key := arg.Job + arg.ScheduledAt.String()
+			if runs[key] {
+				return db.SchedulePeriodicJobTxResult{}, nil
+			}
+			if err := arg.Enqueue(store); err != nil {
+				return db.SchedulePeriodicJobTxResult{}, err
+			}
+			runs[key] = true
+			return db.SchedulePeriodicJobTxResult{Scheduled: true}, nil
+		})
+}
+
+func TestSchedulerTick(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	var fired []time.Time
+	scheduler, err := NewScheduler(store, []PeriodicJob{recordingJob("hourly", "0 * * * *", &fired)})
+	require.NoError(t, err)
+
+	store.EXPECT().
+		AcquireSchedulerLease(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, arg db.AcquireSchedulerLeaseParams) (db.SchedulerLease, error) {
+			require.Equal(t, schedulerLeaseName, arg.Name)
+			require.Equal(t, scheduler.holder, arg.Holder)
+			return db.SchedulerLease{Name: arg.Name, Holder: arg.Holder}, nil
+		})
+	runScheduledJobs(store)
+
+	// 刚当选时补上一小时内错过的那次
+	now := time.Date(2026, 3, 1, 10, 20, 0, 0, time.UTC)
+	require.NoError(t, scheduler.tick(context.Background(), now))
+	require.True(t, scheduler.leader)
+	require.Equal(t, []time.Time{time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)}, fired)
+
+	// 没到下一次不投递
+	require.NoError(t, scheduler.tick(context.Background(), now.Add(30*time.Minute)))
+	require.Len(t, fired, 1)
+
+	// 错过的多次只补最近一次
+	require.NoError(t, scheduler.tick(context.Background(), now.Add(3*time.Hour)))
+	require.Equal(t, time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC), fired[len(fired)-1])
+	require.Len(t, fired, 2)
+}
+
+func TestSchedulerNotLeader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	var fired []time.Time
+	scheduler, err := NewScheduler(store, []PeriodicJob{recordingJob("minutely", "* * * * *", &fired)})
+	require.NoError(t, err)
+
+	store.EXPECT().
+		AcquireSchedulerLease(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.SchedulerLease{}, sql.ErrNoRows)
+	store.EXPECT().
+		SchedulePeriodicJobTx(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	require.NoError(t, scheduler.tick(context.Background(), time.Now()))
+	require.False(t, scheduler.leader)
+	require.Empty(t, fired)
+
+	// 续不上租约就不再当 leader
+	scheduler.leader = true
+	store.EXPECT().
+		AcquireSchedulerLease(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.SchedulerLease{}, sql.ErrConnDone)
+	store.EXPECT().
+		ReleaseSchedulerLease(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	require.Error(t, scheduler.tick(context.Background(), time.Now()))
+	require.False(t, scheduler.leader)
+	scheduler.release()
+}
+
+func TestSchedulerRetryFailedJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	var fired []time.Time
+	failing := true
+	job := recordingJob("hourly", "0 * * * *", &fired)
+	enqueue := job.Enqueue
+	job.Enqueue = func(ctx context.Context, distributor TaskDistributor, scheduledAt time.Time) error {
+		if failing {
+			return errors.New("outbox unavailable")
+		}
+		return enqueue(ctx, distributor, scheduledAt)
+	}
+
+	scheduler, err := NewScheduler(store, []PeriodicJob{job})
+	require.NoError(t, err)
+
+	store.EXPECT().
+		AcquireSchedulerLease(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(db.SchedulerLease{}, nil)
+	runScheduledJobs(store)
+
+	// 投递失败的那次下一轮再试
+	now := time.Date(2026, 3, 1, 10, 0, 30, 0, time.UTC)
+	require.NoError(t, scheduler.tick(context.Background(), now))
+	require.Empty(t, fired)
+
+	failing = false
+	require.NoError(t, scheduler.tick(context.Background(), now.Add(schedulerTick)))
+	require.Equal(t, []time.Time{time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)}, fired)
+}
+
+func TestPeriodicJobs(t *testing.T) {
+	config := util.Config{TaskHistoryRetention: 24 * time.Hour}
+
+	jobs, err := PeriodicJobs(config)
+	require.NoError(t, err)
+
+	specs := make(map[string]string)
+	for _, job := range jobs {
+		specs[job.Name] = job.Spec
+	}
+	require.Equal(t, "0 * * * *", specs["purge_expired_sessions"])
+	require.Equal(t, "10 0 * * *", specs["accrue_interest"])
+
+	// 默认的 cron 表达式都要能解析
+	_, err = NewScheduler(nil, jobs)
+	require.NoError(t, err)
+
+	config.SchedulerSpecs = "purge_expired_sessions=*/15 * * * *; reconcile_accounts=off"
+	jobs, err = PeriodicJobs(config)
+	require.NoError(t, err)
+
+	specs = make(map[string]string)
+	for _, job := range jobs {
+		specs[job.Name] = job.Spec
+	}
+	require.Equal(t, "*/15 * * * *", specs["purge_expired_sessions"])
+	require.NotContains(t, specs, "reconcile_accounts")
+
+	config.SchedulerSpecs = "no_such_job=0 * * * *"
+	_, err = PeriodicJobs(config)
+	require.Error(t, err)
+
+	config.SchedulerSpecs = "purge_expired_sessions"
+	_, err = PeriodicJobs(config)
+	require.Error(t, err)
+
+	config.SchedulerSpecs = "purge_expired_sessions=every hour"
+	jobs, err = PeriodicJobs(config)
+	require.NoError(t, err)
+	_, err = NewScheduler(nil, jobs)
+	require.Error(t, err)
+}
+
+func TestPeriodicJobPayloads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	distributor := NewOutboxTaskDistributor(store)
+
+	jobs, err := PeriodicJobs(util.Config{TaskHistoryRetention: 24 * time.Hour, ACHReturnWindow: 48 * time.Hour})
+	require.NoError(t, err)
+	byName := make(map[string]PeriodicJob)
+	for _, job := range jobs {
+		byName[job.Name] = job
+	}
+
+	scheduledAt := time.Date(2026, 3, 1, 0, 10, 0, 0, time.UTC)
+	payloads := map[string]string{
+		"accrue_interest":     `{"date":"2026-02-28"}`,
+		"post_interest":       `{"period":"2026-02"}`,
+		"purge_task_history":  `{"before":"2026-02-28T00:10:00Z"}`,
+		"settle_ach_payments": `{"submitted_before":"2026-02-27T00:10:00Z"}`,
+	}
+	for name, payload := range payloads {
+		store.EXPECT().
+			CreateOutboxMessage(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.CreateOutboxMessageParams) (db.Outbox, error) {
+				require.JSONEq(t, payload, string(arg.Payload))
+				return db.Outbox{ID: 1}, nil
+			})
+
+		err := byName[name].Enqueue(context.Background(), distributor, scheduledAt)
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+	"simplebank/settlement"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// SimulatedNetwork 本地模拟的清算网络，受理后延迟投递清算结果任务，整个流程不需要外部系统
+type SimulatedNetwork struct {
+	distributor TaskDistributor
+	delay       time.Duration
+}
+
+func NewSimulatedNetwork(distributor TaskDistributor, delay time.Duration) settlement.SettlementNetwork {
+	return &SimulatedNetwork{
+		distributor: distributor,
+		delay:       delay,
+	}
+}
+
+func (network *SimulatedNetwork) Name() string {
+	return settlement.SimulatorNetworkName
+}
+
+// Submit 结果由收款账号决定，被退回的付款先清算成功，再隔一个延迟退回
+func (network *SimulatedNetwork) Submit(ctx context.Context, payment settlement.Payment) (string, error) {
+	outcome := settlement.SimulatedOutcome(payment.CreditorAccountNumber)
+
+	first := outcome
+	if outcome == settlement.OutcomeReturned {
+		first = settlement.OutcomeSettled
+	}
+
+	err := network.schedule(ctx, payment.TransferID, first, network.delay)
+	if err != nil {
+		return "", err
+	}
+
+	if outcome == settlement.OutcomeReturned {
+		err = network.schedule(ctx, payment.TransferID, outcome, 2*network.delay)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return settlement.SimulatedReference(payment.TransferID), nil
+}
+
+func (network *SimulatedNetwork) schedule(ctx context.Context, transferID int64, outcome string, delay time.Duration) error {
+	payload := &PayloadApplySettlementOutcome{
+		TransferID: transferID,
+		Outcome:    outcome,
+	}
+	if outcome != settlement.OutcomeSettled {
+		payload.Reason = "simulated " + outcome
+	}
+
+	return TaskApplySettlementOutcome.Distribute(ctx, network.distributor, payload, asynq.ProcessIn(delay))
+}
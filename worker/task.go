@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// processor 监听的队列
+const (
+	QueueCritical = "critical"
+	QueueDefault  = "default"
+)
+
+// Task 定义一种任务，P 是载荷的类型
+// 载荷的序列化、默认的入队选项、处理函数的注册和日志都在这里，
+// 加新任务只需要声明一个 Task 并在 taskHandlers 里注册，不用改 TaskDistributor 和 TaskProcessor
+type Task[P any] struct {
+	Type       string
+	options    []asynq.Option
+	retryDelay func(n int) time.Duration
+}
+
+// NewTask opts 是这种任务默认的入队选项，投递时传的选项优先
+func NewTask[P any](taskType string, opts ...asynq.Option) *Task[P] {
+	return &Task[P]{
+		Type:    taskType,
+		options: opts,
+	}
+}
+
+// WithRetryDelay 替换 asynq 默认的重试间隔，n 是已经重试的次数
+func (task *Task[P]) WithRetryDelay(retryDelay func(n int) time.Duration) *Task[P] {
+	task.retryDelay = retryDelay
+	return task
+}
+
+// Distribute 序列化载荷后交给 distributor
+func (task *Task[P]) Distribute(ctx context.Context, distributor TaskDistributor, payload *P, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s payload: %w", task.Type, err)
+	}
+
+	opts = append(slices.Clone(task.options), opts...)
+	return distributor.DistributeTask(ctx, task.Type, jsonPayload, opts...)
+}
+
+// Handle 把处理函数包成 TaskHandler，载荷解析失败的任务不重试
+func (task *Task[P]) Handle(handle func(ctx context.Context, payload *P) error) TaskHandler {
+	return TaskHandler{
+		Type:       task.Type,
+		retryDelay: task.retryDelay,
+		handle: func(ctx context.Context, t *asynq.Task) error {
+			taskID, _ := getTaskID(ctx)
+			logger := slog.With(
+				slog.String("type", t.Type()),
+				slog.String("task_id", taskID),
+			)
+
+			var payload P
+			if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+				logger.ErrorContext(ctx, "failed to unmarshal task payload", slog.String("error", err.Error()))
+				return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+			}
+
+			startTime := time.Now()
+			err := handle(ctx, &payload)
+			if err != nil {
+				// 失败由 asynq 的 ErrorHandler 记日志
+				return err
+			}
+
+			logger.InfoContext(ctx, "processed a task", slog.Duration("duration", time.Since(startTime)))
+			return nil
+		},
+	}
+}
+
+// TaskHandler 注册到 processor 的一种任务的处理函数
+type TaskHandler struct {
+	Type       string
+	retryDelay func(n int) time.Duration
+	handle     asynq.HandlerFunc
+}
+
+func (handler TaskHandler) ProcessTask(ctx context.Context, task *asynq.Task) error {
+	return handler.handle(ctx, task)
+}
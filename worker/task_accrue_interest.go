@@ -0,0 +1,8 @@
+package worker
+
+// PayloadAccrueInterest Date 格式 2006-01-02 (UTC)，按这一天的日终余额计息
+type PayloadAccrueInterest struct {
+	Date string `json:"date"`
+}
+
+var TaskAccrueInterest = NewTask[PayloadAccrueInterest]("task:accrue_interest")
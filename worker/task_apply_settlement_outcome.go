@@ -0,0 +1,16 @@
+package worker
+
+import "github.com/hibiken/asynq"
+
+// PayloadApplySettlementOutcome 清算网络回报的结果，模拟网络延迟投递，真实网络由回调接口投递
+type PayloadApplySettlementOutcome struct {
+	TransferID int64  `json:"transfer_id"`
+	Outcome    string `json:"outcome"`
+	Reason     string `json:"reason"`
+}
+
+var TaskApplySettlementOutcome = NewTask[PayloadApplySettlementOutcome](
+	"task:apply_settlement_outcome",
+	asynq.MaxRetry(10),
+	asynq.Queue(QueueCritical),
+)
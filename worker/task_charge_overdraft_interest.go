@@ -0,0 +1,8 @@
+package worker
+
+// PayloadChargeOverdraftInterest Date 格式 2006-01-02 (UTC)，按这一天的日终透支余额扣息
+type PayloadChargeOverdraftInterest struct {
+	Date string `json:"date"`
+}
+
+var TaskChargeOverdraftInterest = NewTask[PayloadChargeOverdraftInterest]("task:charge_overdraft_interest")
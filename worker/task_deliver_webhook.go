@@ -0,0 +1,20 @@
+package worker
+
+import (
+	"simplebank/webhook"
+
+	"github.com/hibiken/asynq"
+)
+
+type PayloadDeliverWebhook struct {
+	DeliveryID int64 `json:"delivery_id"`
+}
+
+var TaskDeliverWebhook = NewTask[PayloadDeliverWebhook](
+	"task:deliver_webhook",
+	asynq.MaxRetry(WebhookMaxRetry),
+	asynq.Queue(QueueDefault),
+).WithRetryDelay(webhook.RetryDelay)
+
+// WebhookMaxRetry 按 webhook.RetryDelay 退避，重试用完大约两天
+const WebhookMaxRetry = 15
@@ -0,0 +1,6 @@
+package worker
+
+// PayloadExpirePaymentRequests 没有参数，把所有已过期的 pending 请求置为 expired
+type PayloadExpirePaymentRequests struct{}
+
+var TaskExpirePaymentRequests = NewTask[PayloadExpirePaymentRequests]("task:expire_payment_requests")
@@ -0,0 +1,6 @@
+package worker
+
+// PayloadExpirePendingTransfers 没有参数，让所有到期的待审批转账过期并释放占用的资金
+type PayloadExpirePendingTransfers struct{}
+
+var TaskExpirePendingTransfers = NewTask[PayloadExpirePendingTransfers]("task:expire_pending_transfers")
@@ -0,0 +1,8 @@
+package worker
+
+// PayloadPostInterest Period 格式 2006-01，把这个月及之前未入账的利息入账
+type PayloadPostInterest struct {
+	Period string `json:"period"`
+}
+
+var TaskPostInterest = NewTask[PayloadPostInterest]("task:post_interest")
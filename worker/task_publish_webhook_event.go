@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"simplebank/webhook"
+
+	"github.com/hibiken/asynq"
+)
+
+// PayloadPublishWebhookEvent 一个用户的事件，处理时按订阅拆成多条投递
+type PayloadPublishWebhookEvent struct {
+	Username string        `json:"username"`
+	Event    webhook.Event `json:"event"`
+}
+
+var TaskPublishWebhookEvent = NewTask[PayloadPublishWebhookEvent](
+	"task:publish_webhook_event",
+	asynq.MaxRetry(10),
+	asynq.Queue(QueueDefault),
+)
@@ -0,0 +1,6 @@
+package worker
+
+// PayloadPurgeExpiredSessions 没有参数，删掉过期的登录会话
+type PayloadPurgeExpiredSessions struct{}
+
+var TaskPurgeExpiredSessions = NewTask[PayloadPurgeExpiredSessions]("task:purge_expired_sessions")
@@ -0,0 +1,6 @@
+package worker
+
+// PayloadPurgeExpiredVerifyEmails 没有参数，删掉过期的邮箱验证
+type PayloadPurgeExpiredVerifyEmails struct{}
+
+var TaskPurgeExpiredVerifyEmails = NewTask[PayloadPurgeExpiredVerifyEmails]("task:purge_expired_verify_emails")
@@ -0,0 +1,10 @@
+package worker
+
+import "time"
+
+// PayloadPurgeTaskHistory 删掉 Before 之前已经投递的 outbox 消息、处理完的任务和它们的记录
+type PayloadPurgeTaskHistory struct {
+	Before time.Time `json:"before"`
+}
+
+var TaskPurgeTaskHistory = NewTask[PayloadPurgeTaskHistory]("task:purge_task_history")
@@ -0,0 +1,6 @@
+package worker
+
+// PayloadReconcileAccounts 没有参数，核对所有账户的余额和分录
+type PayloadReconcileAccounts struct{}
+
+var TaskReconcileAccounts = NewTask[PayloadReconcileAccounts]("task:reconcile_accounts")
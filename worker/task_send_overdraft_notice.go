@@ -0,0 +1,20 @@
+package worker
+
+import "github.com/hibiken/asynq"
+
+// 透支通知事件
+const (
+	OverdraftEventOverdrawn    = "overdrawn"     // 余额由正转负
+	OverdraftEventLimitReached = "limit_reached" // 用满了透支额度
+)
+
+type PayloadSendOverdraftNotice struct {
+	AccountID int64  `json:"account_id"`
+	Event     string `json:"event"`
+}
+
+var TaskSendOverdraftNotice = NewTask[PayloadSendOverdraftNotice](
+	"task:send_overdraft_notice",
+	asynq.MaxRetry(10),
+	asynq.Queue(QueueCritical),
+)
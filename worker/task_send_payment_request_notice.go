@@ -0,0 +1,23 @@
+package worker
+
+import "github.com/hibiken/asynq"
+
+// 付款请求通知事件
+const (
+	PaymentRequestEventCreated  = "created"
+	PaymentRequestEventPaid     = "paid"
+	PaymentRequestEventDeclined = "declined"
+	PaymentRequestEventExpired  = "expired"
+)
+
+// PayloadSendPaymentRequestNotice created 通知付款人，其他事件通知发起人
+type PayloadSendPaymentRequestNotice struct {
+	PaymentRequestID int64  `json:"payment_request_id"`
+	Event            string `json:"event"`
+}
+
+var TaskSendPaymentRequestNotice = NewTask[PayloadSendPaymentRequestNotice](
+	"task:send_payment_request_notice",
+	asynq.MaxRetry(10),
+	asynq.Queue(QueueCritical),
+)
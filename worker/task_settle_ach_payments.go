@@ -0,0 +1,10 @@
+package worker
+
+import "time"
+
+// PayloadSettleACHPayments 把 SubmittedBefore 之前导出、一直没被退回的批量付款记为已清算
+type PayloadSettleACHPayments struct {
+	SubmittedBefore time.Time `json:"submitted_before"`
+}
+
+var TaskSettleACHPayments = NewTask[PayloadSettleACHPayments]("task:settle_ach_payments")
@@ -0,0 +1,14 @@
+package worker
+
+import "github.com/hibiken/asynq"
+
+// PayloadSubmitExternalTransfer 把 initiated 的他行转账提交给清算网络
+type PayloadSubmitExternalTransfer struct {
+	TransferID int64 `json:"transfer_id"`
+}
+
+var TaskSubmitExternalTransfer = NewTask[PayloadSubmitExternalTransfer](
+	"task:submit_external_transfer",
+	asynq.MaxRetry(10),
+	asynq.Queue(QueueCritical),
+)
@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"simplebank/webhook"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedTask struct {
+	taskType string
+	payload  []byte
+	opts     []asynq.Option
+}
+
+type fakeDistributor struct {
+	tasks []recordedTask
+}
+
+func (distributor *fakeDistributor) DistributeTask(ctx context.Context, taskType string, payload []byte, opts ...asynq.Option) error {
+	distributor.tasks = append(distributor.tasks, recordedTask{taskType: taskType, payload: payload, opts: opts})
+	return nil
+}
+
+type testPayload struct {
+	Name string `json:"name"`
+}
+
+func TestTaskDistribute(t *testing.T) {
+	task := NewTask[testPayload]("task:test", asynq.MaxRetry(3), asynq.Queue(QueueCritical))
+	distributor := &fakeDistributor{}
+
+	err := task.Distribute(context.Background(), distributor, &testPayload{Name: "alice"}, asynq.MaxRetry(5))
+	require.NoError(t, err)
+	require.Len(t, distributor.tasks, 1)
+
+	recorded := distributor.tasks[0]
+	require.Equal(t, "task:test", recorded.taskType)
+	require.JSONEq(t, `{"name":"alice"}`, string(recorded.payload))
+
+	// 调用时传的选项排在默认选项后面，按顺序生效时覆盖默认值
+	values := make(map[asynq.OptionType]any)
+	for _, opt := range recorded.opts {
+		values[opt.Type()] = opt.Value()
+	}
+	require.Equal(t, 5, values[asynq.MaxRetryOpt])
+	require.Equal(t, QueueCritical, values[asynq.QueueOpt])
+
+	// 默认选项不会被调用方的选项改掉
+	require.Len(t, task.options, 2)
+}
+
+func TestTaskHandle(t *testing.T) {
+	task := NewTask[testPayload]("task:test")
+
+	var received *testPayload
+	handler := task.Handle(func(ctx context.Context, payload *testPayload) error {
+		received = payload
+		if payload.Name == "bob" {
+			return errors.New("temporary failure")
+		}
+		return nil
+	})
+	require.Equal(t, "task:test", handler.Type)
+
+	err := handler.ProcessTask(context.Background(), asynq.NewTask("task:test", []byte(`{"name":"alice"}`)))
+	require.NoError(t, err)
+	require.Equal(t, "alice", received.Name)
+
+	err = handler.ProcessTask(context.Background(), asynq.NewTask("task:test", []byte(`{"name":"bob"}`)))
+	require.Error(t, err)
+	require.NotErrorIs(t, err, asynq.SkipRetry)
+
+	// 载荷解析不了，重试也没用
+	err = handler.ProcessTask(context.Background(), asynq.NewTask("task:test", []byte(`not json`)))
+	require.ErrorIs(t, err, asynq.SkipRetry)
+}
+
+func TestTaskHandlers(t *testing.T) {
+	processor := &QueueTaskProcessor{}
+
+	types := make(map[string]TaskHandler)
+	for _, handler := range processor.taskHandlers() {
+		require.NotContains(t, types, handler.Type)
+		types[handler.Type] = handler
+	}
+
+	handler, ok := types[TaskDeliverWebhook.Type]
+	require.True(t, ok)
+	require.NotNil(t, handler.retryDelay)
+	require.Equal(t, webhook.RetryDelay(3), handler.retryDelay(3))
+
+	// 没有自定义的任务用 asynq 默认的重试间隔
+	require.Nil(t, types[TaskSendVerifyEmail.Type].retryDelay)
+}